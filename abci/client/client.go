@@ -31,6 +31,15 @@ type Client interface {
 	Echo(context.Context, string) (*types.ResponseEcho, error)
 }
 
+// BatchChecker is implemented by clients that check a batch of transactions
+// more efficiently than one at a time. The socket client sends the whole batch
+// before waiting for any response, so that it takes a single round trip.
+type BatchChecker interface {
+	// CheckTxs checks a batch of transactions, in order, and returns their
+	// responses in the same order.
+	CheckTxs(context.Context, []*types.RequestCheckTx) ([]*types.ResponseCheckTx, error)
+}
+
 //----------------------------------------
 
 // NewClient returns a new ABCI client of the specified transport type.
@@ -46,6 +55,29 @@ func NewClient(logger log.Logger, addr, transport string, mustConnect bool) (Cli
 	}
 }
 
+// CheckTxs checks a batch of transactions with client, in order, and returns
+// their responses in the same order. It uses the batch support of client if it
+// implements BatchChecker, and checks one transaction at a time otherwise.
+func CheckTxs(ctx context.Context, client Client, reqs []*types.RequestCheckTx) ([]*types.ResponseCheckTx, error) {
+	if bc, ok := client.(BatchChecker); ok {
+		return bc.CheckTxs(ctx, reqs)
+	}
+	return checkTxsSequentially(ctx, client, reqs)
+}
+
+// checkTxsSequentially checks a batch of transactions one at a time, for
+// clients that have no better way to do it.
+func checkTxsSequentially(ctx context.Context, app types.Application, reqs []*types.RequestCheckTx) ([]*types.ResponseCheckTx, error) {
+	res := make([]*types.ResponseCheckTx, len(reqs))
+	for i, req := range reqs {
+		var err error
+		if res[i], err = app.CheckTx(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
 type requestAndResponse struct {
 	*types.Request
 	*types.Response
@@ -27,7 +27,7 @@ type socketClient struct {
 	mustConnect bool
 	conn        net.Conn
 
-	reqQueue chan *requestAndResponse
+	reqQueue chan []*requestAndResponse // batches of requests to send
 
 	mtx     sync.Mutex
 	err     error
@@ -42,7 +42,7 @@ var _ Client = (*socketClient)(nil)
 func NewSocketClient(logger log.Logger, addr string, mustConnect bool) Client {
 	cli := &socketClient{
 		logger:      logger,
-		reqQueue:    make(chan *requestAndResponse),
+		reqQueue:    make(chan []*requestAndResponse),
 		mustConnect: mustConnect,
 		addr:        addr,
 		reqSent:     list.New(),
@@ -113,17 +113,27 @@ func (cli *socketClient) sendRequestsRoutine(ctx context.Context, conn io.Writer
 		select {
 		case <-ctx.Done():
 			return
-		case reqres := <-cli.reqQueue:
-			// N.B. We must enqueue before sending out the request, otherwise the
-			// server may reply before we do it, and the receiver will fail for an
-			// unsolicited reply.
-			cli.trackRequest(reqres)
-
-			if err := types.WriteMessage(reqres.Request, bw); err != nil {
-				cli.stopForError(fmt.Errorf("write to buffer: %w", err))
+		case batch := <-cli.reqQueue:
+			if err := cli.writeRequests(bw, batch); err != nil {
+				cli.stopForError(err)
 				return
 			}
 
+			// Write any other requests that are already queued before flushing,
+			// so that concurrent requests share a write to the connection.
+		QUEUED:
+			for {
+				select {
+				case batch := <-cli.reqQueue:
+					if err := cli.writeRequests(bw, batch); err != nil {
+						cli.stopForError(err)
+						return
+					}
+				default:
+					break QUEUED
+				}
+			}
+
 			if err := bw.Flush(); err != nil {
 				cli.stopForError(fmt.Errorf("flush buffer: %w", err))
 				return
@@ -132,6 +142,21 @@ func (cli *socketClient) sendRequestsRoutine(ctx context.Context, conn io.Writer
 	}
 }
 
+// writeRequests writes a batch of requests to the buffer, in order.
+func (cli *socketClient) writeRequests(bw *bufio.Writer, batch []*requestAndResponse) error {
+	for _, reqres := range batch {
+		// N.B. We must enqueue before sending out the request, otherwise the
+		// server may reply before we do it, and the receiver will fail for an
+		// unsolicited reply.
+		cli.trackRequest(reqres)
+
+		if err := types.WriteMessage(reqres.Request, bw); err != nil {
+			return fmt.Errorf("write to buffer: %w", err)
+		}
+	}
+	return nil
+}
+
 func (cli *socketClient) recvResponseRoutine(ctx context.Context, conn io.Reader) {
 	r := bufio.NewReader(conn)
 	for {
@@ -196,28 +221,44 @@ func (cli *socketClient) didRecvResponse(res *types.Response) error {
 //----------------------------------------
 
 func (cli *socketClient) doRequest(ctx context.Context, req *types.Request) (*types.Response, error) {
+	res, err := cli.doRequests(ctx, []*types.Request{req})
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// doRequests sends a batch of requests in order, without waiting for any
+// response in between, and returns the responses in the same order.
+func (cli *socketClient) doRequests(ctx context.Context, reqs []*types.Request) ([]*types.Response, error) {
 	if !cli.IsRunning() {
 		return nil, errors.New("client has stopped")
 	}
 
-	reqres := makeReqRes(req)
+	batch := make([]*requestAndResponse, len(reqs))
+	for i, req := range reqs {
+		batch[i] = makeReqRes(req)
+	}
 
 	select {
-	case cli.reqQueue <- reqres:
+	case cli.reqQueue <- batch:
 	case <-ctx.Done():
 		return nil, fmt.Errorf("can't queue req: %w", ctx.Err())
 	}
 
-	select {
-	case <-reqres.signal:
-		if err := cli.Error(); err != nil {
-			return nil, err
+	res := make([]*types.Response, len(batch))
+	for i, reqres := range batch {
+		select {
+		case <-reqres.signal:
+			if err := cli.Error(); err != nil {
+				return nil, err
+			}
+			res[i] = reqres.Response
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
-
-		return reqres.Response, nil
-	case <-ctx.Done():
-		return nil, ctx.Err()
 	}
+	return res, nil
 }
 
 // drainQueue marks as complete and discards all remaining pending requests
@@ -267,6 +308,22 @@ func (cli *socketClient) CheckTx(ctx context.Context, req *types.RequestCheckTx)
 	return res.GetCheckTx(), nil
 }
 
+func (cli *socketClient) CheckTxs(ctx context.Context, reqs []*types.RequestCheckTx) ([]*types.ResponseCheckTx, error) {
+	batch := make([]*types.Request, len(reqs))
+	for i, req := range reqs {
+		batch[i] = types.ToRequestCheckTx(req)
+	}
+	res, err := cli.doRequests(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	checkTxs := make([]*types.ResponseCheckTx, len(res))
+	for i, r := range res {
+		checkTxs[i] = r.GetCheckTx()
+	}
+	return checkTxs, nil
+}
+
 func (cli *socketClient) Query(ctx context.Context, req *types.RequestQuery) (*types.ResponseQuery, error) {
 	res, err := cli.doRequest(ctx, types.ToRequestQuery(req))
 	if err != nil {
@@ -89,6 +89,20 @@ func testBulk(ctx context.Context, t *testing.T, logger log.Logger, app types.Ap
 		require.Equal(t, tx.Code, code.CodeTypeOK, "Tx failed")
 	}
 
+	// Check a batch of transactions, which are sent in a single round trip
+	reqs := make([]*types.RequestCheckTx, 1000)
+	for i := range reqs {
+		reqs[i] = &types.RequestCheckTx{Tx: []byte(fmt.Sprintf("key%d=value%d", i, i))}
+	}
+	checkTxs, err := abciclient.CheckTxs(ctx, client, reqs)
+	require.NoError(t, err)
+	require.Len(t, checkTxs, len(reqs))
+	for i, req := range reqs {
+		expected, err := app.CheckTx(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, expected, checkTxs[i])
+	}
+
 	// Send final flush message
 	err = client.Flush(ctx)
 	require.NoError(t, err)
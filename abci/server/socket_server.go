@@ -308,6 +308,11 @@ func (s *SocketServer) handleResponses(
 				closer(fmt.Errorf("error writing message: %w", err))
 				return
 			}
+			// Only flush once the responses that are ready have been written,
+			// so that the responses to pipelined requests share a write.
+			if len(responses) > 0 {
+				continue
+			}
 			if err := bw.Flush(); err != nil {
 				closer(fmt.Errorf("error writing message: %w", err))
 				return
@@ -2203,6 +2203,13 @@ type ResponseCheckTx struct {
 	Codespace string `protobuf:"bytes,8,opt,name=codespace,proto3" json:"codespace,omitempty"`
 	Sender    string `protobuf:"bytes,9,opt,name=sender,proto3" json:"sender,omitempty"`
 	Priority  int64  `protobuf:"varint,10,opt,name=priority,proto3" json:"priority,omitempty"`
+	// no_gossip keeps the transaction in the local mempool, to be included in
+	// blocks proposed by this node, without relaying it to peers.
+	NoGossip bool `protobuf:"varint,12,opt,name=no_gossip,json=noGossip,proto3" json:"no_gossip,omitempty"`
+	// proposal_only implies no_gossip, and additionally hides the transaction
+	// from RPC clients listing unconfirmed transactions: it is only used in
+	// block proposals.
+	ProposalOnly bool `protobuf:"varint,13,opt,name=proposal_only,json=proposalOnly,proto3" json:"proposal_only,omitempty"`
 }
 
 func (m *ResponseCheckTx) Reset()         { *m = ResponseCheckTx{} }
@@ -2280,6 +2287,20 @@ func (m *ResponseCheckTx) GetPriority() int64 {
 	return 0
 }
 
+func (m *ResponseCheckTx) GetNoGossip() bool {
+	if m != nil {
+		return m.NoGossip
+	}
+	return false
+}
+
+func (m *ResponseCheckTx) GetProposalOnly() bool {
+	if m != nil {
+		return m.ProposalOnly
+	}
+	return false
+}
+
 type ResponseDeliverTx struct {
 	Code      uint32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
 	Data      []byte  `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
@@ -3828,211 +3849,213 @@ func init() {
 func init() { proto.RegisterFile("tendermint/abci/types.proto", fileDescriptor_252557cfdd89a31a) }
 
 var fileDescriptor_252557cfdd89a31a = []byte{
-	// 3257 bytes of a gzipped FileDescriptorProto
+	// 3292 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x5a, 0xcd, 0x73, 0x23, 0xd5,
-	0x11, 0xd7, 0xe8, 0x5b, 0x2d, 0x4b, 0x1a, 0x3f, 0x9b, 0x45, 0x2b, 0x76, 0x6d, 0x33, 0x14, 0xb0,
-	0x2c, 0x60, 0x13, 0x6f, 0x16, 0x96, 0x2c, 0x84, 0x92, 0x65, 0x6d, 0x64, 0xaf, 0xd7, 0x36, 0x63,
-	0xd9, 0x14, 0xf9, 0x60, 0x18, 0x4b, 0xcf, 0xd6, 0xb0, 0x92, 0x66, 0x98, 0x19, 0x19, 0x99, 0x63,
-	0x12, 0xaa, 0x52, 0x1c, 0x52, 0xdc, 0xc2, 0x21, 0xdc, 0x92, 0xaa, 0xfc, 0x09, 0xc9, 0x25, 0xa7,
-	0x1c, 0x38, 0xe4, 0xc0, 0x29, 0x95, 0x13, 0x49, 0xc1, 0x8d, 0x7f, 0x20, 0xb7, 0x54, 0xea, 0x7d,
-	0xcc, 0x97, 0x34, 0xa3, 0x0f, 0xa0, 0xa8, 0x4a, 0x15, 0xb7, 0x79, 0x3d, 0xdd, 0xfd, 0xbe, 0xfa,
-	0x75, 0xf7, 0xaf, 0xdf, 0x83, 0xc7, 0x6c, 0xdc, 0x6f, 0x63, 0xb3, 0xa7, 0xf5, 0xed, 0x0d, 0xf5,
-	0xb4, 0xa5, 0x6d, 0xd8, 0x97, 0x06, 0xb6, 0xd6, 0x0d, 0x53, 0xb7, 0x75, 0x54, 0xf2, 0x7e, 0xae,
-	0x93, 0x9f, 0x95, 0xeb, 0x3e, 0xee, 0x96, 0x79, 0x69, 0xd8, 0xfa, 0x86, 0x61, 0xea, 0xfa, 0x19,
-	0xe3, 0xaf, 0x5c, 0x1b, 0xff, 0xfd, 0x10, 0x5f, 0x72, 0x6d, 0x01, 0x61, 0xda, 0xcb, 0x86, 0xa1,
-	0x9a, 0x6a, 0xcf, 0xf9, 0xbd, 0x7a, 0xae, 0xeb, 0xe7, 0x5d, 0xbc, 0x41, 0x5b, 0xa7, 0x83, 0xb3,
-	0x0d, 0x5b, 0xeb, 0x61, 0xcb, 0x56, 0x7b, 0x06, 0x67, 0x58, 0x3e, 0xd7, 0xcf, 0x75, 0xfa, 0xb9,
-	0x41, 0xbe, 0x18, 0x55, 0xfa, 0x4b, 0x0e, 0x32, 0x32, 0x7e, 0x77, 0x80, 0x2d, 0x1b, 0x6d, 0x42,
-	0x12, 0xb7, 0x3a, 0x7a, 0x59, 0x58, 0x13, 0x6e, 0xe4, 0x37, 0xaf, 0xad, 0x8f, 0x0c, 0x7f, 0x9d,
-	0xf3, 0xd5, 0x5b, 0x1d, 0xbd, 0x11, 0x93, 0x29, 0x2f, 0xba, 0x0d, 0xa9, 0xb3, 0xee, 0xc0, 0xea,
-	0x94, 0xe3, 0x54, 0xe8, 0x7a, 0x94, 0xd0, 0x3d, 0xc2, 0xd4, 0x88, 0xc9, 0x8c, 0x9b, 0x74, 0xa5,
-	0xf5, 0xcf, 0xf4, 0x72, 0x62, 0x72, 0x57, 0x3b, 0xfd, 0x33, 0xda, 0x15, 0xe1, 0x45, 0x5b, 0x00,
-	0x5a, 0x5f, 0xb3, 0x95, 0x56, 0x47, 0xd5, 0xfa, 0xe5, 0x24, 0x95, 0x7c, 0x3c, 0x5a, 0x52, 0xb3,
-	0x6b, 0x84, 0xb1, 0x11, 0x93, 0x73, 0x9a, 0xd3, 0x20, 0xc3, 0x7d, 0x77, 0x80, 0xcd, 0xcb, 0x72,
-	0x6a, 0xf2, 0x70, 0x5f, 0x27, 0x4c, 0x64, 0xb8, 0x94, 0x1b, 0xbd, 0x02, 0xd9, 0x56, 0x07, 0xb7,
-	0x1e, 0x2a, 0xf6, 0xb0, 0x9c, 0xa1, 0x92, 0xab, 0x51, 0x92, 0x35, 0xc2, 0xd7, 0x1c, 0x36, 0x62,
-	0x72, 0xa6, 0xc5, 0x3e, 0xd1, 0x1d, 0x48, 0xb7, 0xf4, 0x5e, 0x4f, 0xb3, 0xcb, 0x40, 0x65, 0x57,
-	0x22, 0x65, 0x29, 0x57, 0x23, 0x26, 0x73, 0x7e, 0xb4, 0x0f, 0xc5, 0xae, 0x66, 0xd9, 0x8a, 0xd5,
-	0x57, 0x0d, 0xab, 0xa3, 0xdb, 0x56, 0x39, 0x4f, 0x35, 0x3c, 0x19, 0xa5, 0x61, 0x4f, 0xb3, 0xec,
-	0x23, 0x87, 0xb9, 0x11, 0x93, 0x0b, 0x5d, 0x3f, 0x81, 0xe8, 0xd3, 0xcf, 0xce, 0xb0, 0xe9, 0x2a,
-	0x2c, 0x2f, 0x4c, 0xd6, 0x77, 0x40, 0xb8, 0x1d, 0x79, 0xa2, 0x4f, 0xf7, 0x13, 0xd0, 0xcf, 0x60,
-	0xa9, 0xab, 0xab, 0x6d, 0x57, 0x9d, 0xd2, 0xea, 0x0c, 0xfa, 0x0f, 0xcb, 0x05, 0xaa, 0xf4, 0x99,
-	0xc8, 0x41, 0xea, 0x6a, 0xdb, 0x51, 0x51, 0x23, 0x02, 0x8d, 0x98, 0xbc, 0xd8, 0x1d, 0x25, 0xa2,
-	0xb7, 0x60, 0x59, 0x35, 0x8c, 0xee, 0xe5, 0xa8, 0xf6, 0x22, 0xd5, 0x7e, 0x33, 0x4a, 0x7b, 0x95,
-	0xc8, 0x8c, 0xaa, 0x47, 0xea, 0x18, 0x15, 0x35, 0x41, 0x34, 0x4c, 0x6c, 0xa8, 0x26, 0x56, 0x0c,
-	0x53, 0x37, 0x74, 0x4b, 0xed, 0x96, 0x4b, 0x54, 0xf7, 0xd3, 0x51, 0xba, 0x0f, 0x19, 0xff, 0x21,
-	0x67, 0x6f, 0xc4, 0xe4, 0x92, 0x11, 0x24, 0x31, 0xad, 0x7a, 0x0b, 0x5b, 0x96, 0xa7, 0x55, 0x9c,
-	0xa6, 0x95, 0xf2, 0x07, 0xb5, 0x06, 0x48, 0xa8, 0x0e, 0x79, 0x3c, 0x24, 0xe2, 0xca, 0x85, 0x6e,
-	0xe3, 0xf2, 0x22, 0x55, 0x28, 0x45, 0x9e, 0x50, 0xca, 0x7a, 0xa2, 0xdb, 0xb8, 0x11, 0x93, 0x01,
-	0xbb, 0x2d, 0xa4, 0xc2, 0x23, 0x17, 0xd8, 0xd4, 0xce, 0x2e, 0xa9, 0x1a, 0x85, 0xfe, 0xb1, 0x34,
-	0xbd, 0x5f, 0x46, 0x54, 0xe1, 0xb3, 0x51, 0x0a, 0x4f, 0xa8, 0x10, 0x51, 0x51, 0x77, 0x44, 0x1a,
-	0x31, 0x79, 0xe9, 0x62, 0x9c, 0x4c, 0x4c, 0xec, 0x4c, 0xeb, 0xab, 0x5d, 0xed, 0x7d, 0xac, 0x9c,
-	0x76, 0xf5, 0xd6, 0xc3, 0xf2, 0xd2, 0x64, 0x13, 0xbb, 0xc7, 0xb9, 0xb7, 0x08, 0x33, 0x31, 0xb1,
-	0x33, 0x3f, 0x61, 0x2b, 0x03, 0xa9, 0x0b, 0xb5, 0x3b, 0xc0, 0xbb, 0xc9, 0x6c, 0x5a, 0xcc, 0xec,
-	0x26, 0xb3, 0x59, 0x31, 0xb7, 0x9b, 0xcc, 0xe6, 0x44, 0x90, 0x9e, 0x86, 0xbc, 0xcf, 0x25, 0xa1,
-	0x32, 0x64, 0x7a, 0xd8, 0xb2, 0xd4, 0x73, 0x4c, 0x3d, 0x58, 0x4e, 0x76, 0x9a, 0x52, 0x11, 0x16,
-	0xfc, 0x6e, 0x48, 0xfa, 0x48, 0x70, 0x25, 0x89, 0x87, 0x21, 0x92, 0x17, 0xd8, 0xa4, 0x0b, 0xc1,
-	0x25, 0x79, 0x13, 0x3d, 0x01, 0x05, 0x3a, 0x09, 0xc5, 0xf9, 0x4f, 0xdc, 0x5c, 0x52, 0x5e, 0xa0,
-	0xc4, 0x13, 0xce, 0xb4, 0x0a, 0x79, 0x63, 0xd3, 0x70, 0x59, 0x12, 0x94, 0x05, 0x8c, 0x4d, 0xc3,
-	0x61, 0x78, 0x1c, 0x16, 0xc8, 0x8c, 0x5d, 0x8e, 0x24, 0xed, 0x24, 0x4f, 0x68, 0x9c, 0x45, 0xfa,
-	0x7b, 0x1c, 0xc4, 0x51, 0xd7, 0x85, 0xee, 0x40, 0x92, 0x78, 0x71, 0xee, 0x90, 0x2b, 0xeb, 0xcc,
-	0xc5, 0xaf, 0x3b, 0x2e, 0x7e, 0xbd, 0xe9, 0xb8, 0xf8, 0xad, 0xec, 0xa7, 0x9f, 0xaf, 0xc6, 0x3e,
-	0xfa, 0xd7, 0xaa, 0x20, 0x53, 0x09, 0x74, 0x95, 0x38, 0x2c, 0x55, 0xeb, 0x2b, 0x5a, 0x9b, 0x0e,
-	0x39, 0x47, 0xbc, 0x91, 0xaa, 0xf5, 0x77, 0xda, 0x68, 0x0f, 0xc4, 0x96, 0xde, 0xb7, 0x70, 0xdf,
-	0x1a, 0x58, 0x0a, 0x0b, 0x21, 0xdc, 0x0d, 0x07, 0x9c, 0x29, 0x0b, 0x64, 0x35, 0x87, 0xf3, 0x90,
-	0x32, 0xca, 0xa5, 0x56, 0x90, 0x80, 0xee, 0x01, 0x5c, 0xa8, 0x5d, 0xad, 0xad, 0xda, 0xba, 0x69,
-	0x95, 0x93, 0x6b, 0x89, 0x1b, 0xf9, 0xcd, 0xb5, 0xb1, 0xad, 0x3e, 0x71, 0x58, 0x8e, 0x8d, 0xb6,
-	0x6a, 0xe3, 0xad, 0x24, 0x19, 0xae, 0xec, 0x93, 0x44, 0x4f, 0x41, 0x49, 0x35, 0x0c, 0xc5, 0xb2,
-	0x55, 0x1b, 0x2b, 0xa7, 0x97, 0x36, 0xb6, 0xa8, 0x8b, 0x5e, 0x90, 0x0b, 0xaa, 0x61, 0x1c, 0x11,
-	0xea, 0x16, 0x21, 0xa2, 0x27, 0xa1, 0x48, 0xbc, 0xb9, 0xa6, 0x76, 0x95, 0x0e, 0xd6, 0xce, 0x3b,
-	0x76, 0x39, 0xbd, 0x26, 0xdc, 0x48, 0xc8, 0x05, 0x4e, 0x6d, 0x50, 0xa2, 0xd4, 0x76, 0x77, 0x9c,
-	0x7a, 0x72, 0x84, 0x20, 0xd9, 0x56, 0x6d, 0x95, 0xae, 0xe4, 0x82, 0x4c, 0xbf, 0x09, 0xcd, 0x50,
-	0xed, 0x0e, 0x5f, 0x1f, 0xfa, 0x8d, 0xae, 0x40, 0x9a, 0xab, 0x4d, 0x50, 0xb5, 0xbc, 0x85, 0x96,
-	0x21, 0x65, 0x98, 0xfa, 0x05, 0xa6, 0x5b, 0x97, 0x95, 0x59, 0x43, 0x92, 0xa1, 0x18, 0xf4, 0xfa,
-	0xa8, 0x08, 0x71, 0x7b, 0xc8, 0x7b, 0x89, 0xdb, 0x43, 0xf4, 0x02, 0x24, 0xc9, 0x42, 0xd2, 0x3e,
-	0x8a, 0x21, 0x71, 0x8e, 0xcb, 0x35, 0x2f, 0x0d, 0x2c, 0x53, 0x4e, 0xa9, 0x04, 0x85, 0x40, 0x34,
-	0x90, 0xae, 0xc0, 0x72, 0x98, 0x73, 0x97, 0x3a, 0x2e, 0x3d, 0xe0, 0xa4, 0xd1, 0x6d, 0xc8, 0xba,
-	0xde, 0x9d, 0x19, 0xce, 0xd5, 0xb1, 0x6e, 0x1d, 0x66, 0xd9, 0x65, 0x25, 0x16, 0x43, 0x36, 0xa0,
-	0xa3, 0xf2, 0x58, 0xbe, 0x20, 0x67, 0x54, 0xc3, 0x68, 0xa8, 0x56, 0x47, 0x7a, 0x1b, 0xca, 0x51,
-	0x9e, 0xdb, 0xb7, 0x60, 0x02, 0x35, 0x7b, 0x67, 0xc1, 0xae, 0x40, 0xfa, 0x4c, 0x37, 0x7b, 0xaa,
-	0x4d, 0x95, 0x15, 0x64, 0xde, 0x22, 0x0b, 0xc9, 0xbc, 0x78, 0x82, 0x92, 0x59, 0x43, 0x52, 0xe0,
-	0x6a, 0xa4, 0xf7, 0x26, 0x22, 0x5a, 0xbf, 0x8d, 0xd9, 0xb2, 0x16, 0x64, 0xd6, 0xf0, 0x14, 0xb1,
-	0xc1, 0xb2, 0x06, 0xe9, 0xd6, 0xa2, 0x73, 0xa5, 0xfa, 0x73, 0x32, 0x6f, 0x49, 0x1f, 0x27, 0xe0,
-	0x4a, 0xb8, 0x0f, 0x47, 0x6b, 0xb0, 0xd0, 0x53, 0x87, 0x8a, 0x3d, 0xe4, 0x66, 0x27, 0xd0, 0x8d,
-	0x87, 0x9e, 0x3a, 0x6c, 0x0e, 0x99, 0xcd, 0x89, 0x90, 0xb0, 0x87, 0x56, 0x39, 0xbe, 0x96, 0xb8,
-	0xb1, 0x20, 0x93, 0x4f, 0x74, 0x0c, 0x8b, 0x5d, 0xbd, 0xa5, 0x76, 0x95, 0xae, 0x6a, 0xd9, 0x0a,
-	0x0f, 0xee, 0xec, 0x10, 0x3d, 0x31, 0xb6, 0xd8, 0xcc, 0x1b, 0xe3, 0x36, 0xdb, 0x4f, 0xe2, 0x70,
-	0xb8, 0xfd, 0x97, 0xa8, 0x8e, 0x3d, 0xd5, 0xd9, 0x6a, 0xb4, 0x0d, 0xf9, 0x9e, 0x66, 0x9d, 0xe2,
-	0x8e, 0x7a, 0xa1, 0xe9, 0x26, 0x3f, 0x4d, 0xe3, 0x46, 0xf3, 0xc0, 0xe3, 0xe1, 0x9a, 0xfc, 0x62,
-	0xbe, 0x2d, 0x49, 0x05, 0x6c, 0xd8, 0xf1, 0x26, 0xe9, 0xb9, 0xbd, 0xc9, 0x0b, 0xb0, 0xdc, 0xc7,
-	0x43, 0x5b, 0xf1, 0xce, 0x2b, 0xb3, 0x93, 0x0c, 0x5d, 0x7a, 0x44, 0xfe, 0xb9, 0x27, 0xdc, 0x22,
-	0x26, 0x83, 0x9e, 0xa1, 0x51, 0xd0, 0xd0, 0x2d, 0x6c, 0x2a, 0x6a, 0xbb, 0x6d, 0x62, 0xcb, 0x2a,
-	0x67, 0x29, 0x77, 0xc9, 0xa1, 0x57, 0x19, 0x59, 0xfa, 0x8d, 0x7f, 0x6b, 0x82, 0x51, 0x8f, 0x2f,
-	0xbc, 0xe0, 0x2d, 0xfc, 0x11, 0x2c, 0x73, 0xf9, 0x76, 0x60, 0xed, 0x59, 0xf6, 0xf9, 0xd8, 0xf8,
-	0xf9, 0x1a, 0x5d, 0x73, 0xe4, 0x88, 0x47, 0x2f, 0x7b, 0xe2, 0xeb, 0x2d, 0x3b, 0x82, 0x24, 0x5d,
-	0x94, 0x24, 0x73, 0x31, 0xe4, 0xfb, 0xff, 0x6d, 0x2b, 0x5e, 0x83, 0xc5, 0xb1, 0x0c, 0xc2, 0x9d,
-	0x97, 0x10, 0x3a, 0xaf, 0xb8, 0x7f, 0x5e, 0xd2, 0xef, 0x05, 0xa8, 0x44, 0xa7, 0x0c, 0xa1, 0xaa,
-	0x9e, 0x85, 0x45, 0x77, 0x2e, 0xee, 0xf8, 0xd8, 0x99, 0x16, 0xdd, 0x1f, 0x7c, 0x80, 0x91, 0xee,
-	0xf9, 0x49, 0x28, 0x8e, 0x24, 0x34, 0x6c, 0x17, 0x0a, 0x17, 0xfe, 0xfe, 0xa5, 0x5f, 0x27, 0x5c,
-	0x9f, 0x19, 0xc8, 0x3a, 0x42, 0x0c, 0xed, 0x75, 0x58, 0x6a, 0xe3, 0x96, 0xd6, 0xfe, 0xba, 0x76,
-	0xb6, 0xc8, 0xa5, 0xbf, 0x37, 0xb3, 0x71, 0x33, 0xfb, 0x2d, 0x40, 0x56, 0xc6, 0x96, 0x41, 0x52,
-	0x09, 0xb4, 0x05, 0x39, 0x3c, 0x6c, 0x61, 0xc3, 0x76, 0xb2, 0xaf, 0xf0, 0xbc, 0x96, 0x71, 0xd7,
-	0x1d, 0x4e, 0x82, 0xea, 0x5c, 0x31, 0x74, 0x8b, 0x03, 0xd7, 0x68, 0x0c, 0xca, 0xc5, 0xfd, 0xc8,
-	0xf5, 0x45, 0x07, 0xb9, 0x26, 0x22, 0x41, 0x19, 0x93, 0x1a, 0x81, 0xae, 0xb7, 0x38, 0x74, 0x4d,
-	0x4e, 0xe9, 0x2c, 0x80, 0x5d, 0x6b, 0x01, 0xec, 0x9a, 0x9a, 0x32, 0xcd, 0x08, 0xf0, 0xfa, 0xa2,
-	0x03, 0x5e, 0xd3, 0x53, 0x46, 0x3c, 0x82, 0x5e, 0x5f, 0xf5, 0xa1, 0xd7, 0x2c, 0x15, 0x5d, 0x8b,
-	0x14, 0x0d, 0x81, 0xaf, 0x2f, 0xbb, 0xf0, 0x35, 0x1f, 0x09, 0x7d, 0xb9, 0xf0, 0x28, 0x7e, 0x3d,
-	0x18, 0xc3, 0xaf, 0x0c, 0x6f, 0x3e, 0x15, 0xa9, 0x62, 0x0a, 0x80, 0x3d, 0x18, 0x03, 0xb0, 0x85,
-	0x29, 0x0a, 0xa7, 0x20, 0xd8, 0x9f, 0x87, 0x23, 0xd8, 0x68, 0x8c, 0xc9, 0x87, 0x39, 0x1b, 0x84,
-	0x55, 0x22, 0x20, 0x6c, 0x29, 0x12, 0x6e, 0x31, 0xf5, 0x33, 0x63, 0xd8, 0xe3, 0x10, 0x0c, 0xcb,
-	0xd0, 0xe6, 0x8d, 0x48, 0xe5, 0x33, 0x80, 0xd8, 0xe3, 0x10, 0x10, 0xbb, 0x38, 0x55, 0xed, 0x54,
-	0x14, 0x7b, 0x2f, 0x88, 0x62, 0x51, 0x44, 0xc2, 0xe4, 0x9d, 0xf6, 0x08, 0x18, 0x7b, 0x1a, 0x05,
-	0x63, 0x19, 0xd4, 0x7c, 0x2e, 0x52, 0xe3, 0x1c, 0x38, 0xf6, 0x60, 0x0c, 0xc7, 0x2e, 0x4f, 0xb1,
-	0xb4, 0xd9, 0x81, 0x6c, 0x46, 0xcc, 0x32, 0x08, 0xbb, 0x9b, 0xcc, 0x82, 0x98, 0x97, 0x9e, 0x21,
-	0x71, 0x77, 0xc4, 0xc3, 0x91, 0x04, 0x17, 0x9b, 0xa6, 0x6e, 0x72, 0x48, 0xca, 0x1a, 0xd2, 0x0d,
-	0x02, 0x6c, 0x3c, 0x6f, 0x36, 0x01, 0xf4, 0x52, 0x20, 0xe1, 0xf3, 0x60, 0xd2, 0x9f, 0x05, 0x4f,
-	0x96, 0xc2, 0x5e, 0x3f, 0x28, 0xca, 0x71, 0x50, 0xe4, 0x83, 0xc2, 0xf1, 0x20, 0x14, 0x5e, 0x85,
-	0x3c, 0x01, 0x08, 0x23, 0x28, 0x57, 0x35, 0x5c, 0x94, 0x7b, 0x13, 0x16, 0x69, 0xa8, 0x64, 0x80,
-	0x99, 0x07, 0xa4, 0x24, 0x0d, 0x48, 0x25, 0xf2, 0x83, 0xad, 0x0b, 0x8b, 0x4c, 0xcf, 0xc3, 0x92,
-	0x8f, 0xd7, 0x05, 0x1e, 0x0c, 0xf2, 0x89, 0x2e, 0x77, 0x95, 0x23, 0x90, 0xbf, 0x09, 0xde, 0x0a,
-	0x79, 0xf0, 0x38, 0x0c, 0xc9, 0x0a, 0xdf, 0x12, 0x92, 0x8d, 0x7f, 0x6d, 0x24, 0xeb, 0x07, 0x52,
-	0x89, 0x20, 0x90, 0xfa, 0x8f, 0xe0, 0xed, 0x89, 0x8b, 0x4b, 0x5b, 0x7a, 0x1b, 0x73, 0x68, 0x43,
-	0xbf, 0x49, 0x32, 0xd2, 0xd5, 0xcf, 0x39, 0x80, 0x21, 0x9f, 0x84, 0xcb, 0x0d, 0x39, 0x39, 0x1e,
-	0x51, 0x5c, 0x54, 0xc4, 0x42, 0x3e, 0x47, 0x45, 0x22, 0x24, 0x1e, 0x62, 0x16, 0x20, 0x16, 0x64,
-	0xf2, 0x49, 0xf8, 0xa8, 0xd9, 0xf1, 0xd0, 0xcd, 0x1a, 0xe8, 0x0e, 0xe4, 0x68, 0xe5, 0x59, 0xd1,
-	0x0d, 0x8b, 0xc7, 0x84, 0x40, 0x52, 0xc3, 0xca, 0xcf, 0xeb, 0x87, 0x84, 0xe7, 0xc0, 0xb0, 0xe4,
-	0xac, 0xc1, 0xbf, 0x7c, 0xb9, 0x46, 0x2e, 0x90, 0x6b, 0x5c, 0x83, 0x1c, 0x19, 0xbd, 0x65, 0xa8,
-	0x2d, 0x4c, 0xeb, 0x9c, 0x39, 0xd9, 0x23, 0x48, 0x9f, 0x0a, 0x50, 0x1a, 0x09, 0x31, 0xa1, 0x73,
-	0x77, 0x4c, 0x32, 0xee, 0xc3, 0xe9, 0xd7, 0x01, 0xce, 0x55, 0x4b, 0x79, 0x4f, 0xed, 0xdb, 0xb8,
-	0xcd, 0xa7, 0x9b, 0x3b, 0x57, 0xad, 0x37, 0x28, 0x21, 0xd8, 0x71, 0x76, 0xa4, 0x63, 0x1f, 0x20,
-	0xcc, 0xf9, 0x01, 0x21, 0xaa, 0x40, 0xd6, 0x30, 0x35, 0xdd, 0xd4, 0xec, 0x4b, 0x3a, 0xda, 0x84,
-	0xec, 0xb6, 0x77, 0x93, 0xd9, 0x84, 0x98, 0xdc, 0x4d, 0x66, 0x93, 0x62, 0xca, 0xad, 0x3a, 0xb1,
-	0x23, 0x9b, 0x17, 0x17, 0xa4, 0x0f, 0xe2, 0x9e, 0x2d, 0x6e, 0xe3, 0xae, 0x76, 0x81, 0xcd, 0x39,
-	0x26, 0x33, 0xdb, 0xe6, 0xae, 0x84, 0x4c, 0xd9, 0x47, 0x21, 0xa3, 0x27, 0xad, 0x81, 0x85, 0xdb,
-	0xbc, 0xfe, 0xe1, 0xb6, 0x51, 0x03, 0xd2, 0xf8, 0x02, 0xf7, 0x6d, 0xab, 0x9c, 0xa1, 0x36, 0x7c,
-	0x65, 0x1c, 0x90, 0x92, 0xdf, 0x5b, 0x65, 0x62, 0xb9, 0x5f, 0x7d, 0xbe, 0x2a, 0x32, 0xee, 0xe7,
-	0xf4, 0x9e, 0x66, 0xe3, 0x9e, 0x61, 0x5f, 0xca, 0x5c, 0x7e, 0xf2, 0xca, 0x4a, 0x55, 0x28, 0x06,
-	0xe3, 0x3e, 0x7a, 0x02, 0x0a, 0x26, 0xb6, 0x55, 0xad, 0xaf, 0x04, 0x92, 0xf4, 0x05, 0x46, 0x64,
-	0x27, 0x7f, 0x37, 0x99, 0x15, 0xc4, 0xf8, 0x6e, 0x32, 0x1b, 0x17, 0x13, 0xd2, 0x21, 0x3c, 0x12,
-	0x1a, 0xf7, 0xd1, 0x4b, 0x90, 0xf3, 0x52, 0x06, 0x81, 0x4e, 0x63, 0x42, 0x11, 0xc3, 0xe3, 0x95,
-	0xfe, 0x2a, 0x78, 0x2a, 0x83, 0x65, 0x91, 0x3a, 0xa4, 0x4d, 0x6c, 0x0d, 0xba, 0xac, 0x50, 0x51,
-	0xdc, 0x7c, 0x7e, 0xb6, 0x8c, 0x81, 0x50, 0x07, 0x5d, 0x5b, 0xe6, 0xc2, 0xd2, 0x5b, 0x90, 0x66,
-	0x14, 0x94, 0x87, 0xcc, 0xf1, 0xfe, 0xfd, 0xfd, 0x83, 0x37, 0xf6, 0xc5, 0x18, 0x02, 0x48, 0x57,
-	0x6b, 0xb5, 0xfa, 0x61, 0x53, 0x14, 0x50, 0x0e, 0x52, 0xd5, 0xad, 0x03, 0xb9, 0x29, 0xc6, 0x09,
-	0x59, 0xae, 0xef, 0xd6, 0x6b, 0x4d, 0x31, 0x81, 0x16, 0xa1, 0xc0, 0xbe, 0x95, 0x7b, 0x07, 0xf2,
-	0x83, 0x6a, 0x53, 0x4c, 0xfa, 0x48, 0x47, 0xf5, 0xfd, 0xed, 0xba, 0x2c, 0xa6, 0xa4, 0x1f, 0xc0,
-	0xd5, 0xc8, 0x1c, 0xc3, 0xab, 0x79, 0x08, 0xbe, 0x9a, 0x87, 0xf4, 0x71, 0x9c, 0x80, 0xae, 0xa8,
-	0xc4, 0x01, 0xed, 0x8e, 0x4c, 0x7c, 0x73, 0x8e, 0xac, 0x63, 0x64, 0xf6, 0x04, 0x67, 0x99, 0xf8,
-	0x0c, 0xdb, 0xad, 0x0e, 0x4b, 0x64, 0x98, 0x9f, 0x2c, 0xc8, 0x05, 0x4e, 0xa5, 0x42, 0x16, 0x63,
-	0x7b, 0x07, 0xb7, 0x6c, 0x85, 0x9d, 0x36, 0x8b, 0x82, 0x9d, 0x1c, 0x61, 0x23, 0xd4, 0x23, 0x46,
-	0x94, 0xde, 0x9e, 0x6b, 0x2d, 0x73, 0x90, 0x92, 0xeb, 0x4d, 0xf9, 0x4d, 0x31, 0x81, 0x10, 0x14,
-	0xe9, 0xa7, 0x72, 0xb4, 0x5f, 0x3d, 0x3c, 0x6a, 0x1c, 0x90, 0xb5, 0x5c, 0x82, 0x92, 0xb3, 0x96,
-	0x0e, 0x31, 0x25, 0xfd, 0x23, 0x0e, 0x8f, 0x46, 0xa4, 0x3d, 0xe8, 0x0e, 0x80, 0x3d, 0x54, 0x4c,
-	0xdc, 0xd2, 0xcd, 0x76, 0xb4, 0x91, 0x35, 0x87, 0x32, 0xe5, 0x90, 0x73, 0x36, 0xff, 0xb2, 0x26,
-	0x94, 0xca, 0xd0, 0x2b, 0x5c, 0x29, 0x99, 0x95, 0xc5, 0x21, 0xde, 0xf5, 0x90, 0x8a, 0x10, 0x6e,
-	0x11, 0xc5, 0x74, 0x6d, 0xa9, 0x62, 0xca, 0x8f, 0x1e, 0xf8, 0xb1, 0xf0, 0x80, 0x06, 0x98, 0x99,
-	0x6b, 0xaa, 0x3e, 0xb4, 0xcc, 0x08, 0x16, 0x7a, 0x13, 0x1e, 0x1d, 0x89, 0x8f, 0xae, 0xd2, 0xd4,
-	0xac, 0x61, 0xf2, 0x91, 0x60, 0x98, 0xe4, 0xaa, 0xa5, 0x3f, 0x24, 0xfc, 0x0b, 0x1b, 0xcc, 0xf2,
-	0x0e, 0x20, 0x6d, 0xd9, 0xaa, 0x3d, 0xb0, 0xb8, 0xc1, 0xbd, 0x34, 0x6b, 0xca, 0xb8, 0xee, 0x7c,
-	0x1c, 0x51, 0x71, 0x99, 0xab, 0xf9, 0x7e, 0xbd, 0x2d, 0xe9, 0x36, 0x14, 0x83, 0x8b, 0x13, 0x7d,
-	0x64, 0x3c, 0x9f, 0x13, 0x97, 0xee, 0x02, 0x1a, 0x4f, 0xa6, 0x43, 0xaa, 0x25, 0x42, 0x58, 0xb5,
-	0xe4, 0x8f, 0x02, 0x3c, 0x36, 0x21, 0x71, 0x46, 0xaf, 0x8f, 0xec, 0xf3, 0xcb, 0xf3, 0xa4, 0xdd,
-	0xeb, 0x8c, 0x16, 0xdc, 0x69, 0xe9, 0x16, 0x2c, 0xf8, 0xe9, 0xb3, 0x4d, 0xf2, 0xab, 0xb8, 0xe7,
-	0xf3, 0x83, 0x65, 0x1d, 0x2f, 0x14, 0x0a, 0xdf, 0x30, 0x14, 0x06, 0xed, 0x2c, 0x3e, 0xa7, 0x9d,
-	0x1d, 0x85, 0xd9, 0x59, 0x62, 0xae, 0x0c, 0x73, 0x2e, 0x6b, 0x4b, 0x7e, 0x33, 0x6b, 0x0b, 0x1c,
-	0xb8, 0x54, 0x30, 0x85, 0x7d, 0x13, 0xc0, 0xab, 0x75, 0x91, 0x80, 0x64, 0xea, 0x83, 0x7e, 0x9b,
-	0x5a, 0x40, 0x4a, 0x66, 0x0d, 0x74, 0x1b, 0x52, 0xc4, 0x92, 0x9c, 0x75, 0x1a, 0x77, 0xaa, 0xc4,
-	0x12, 0x7c, 0xb5, 0x32, 0xc6, 0x2d, 0x69, 0x80, 0xc6, 0x4b, 0xe5, 0x11, 0x5d, 0xbc, 0x1a, 0xec,
-	0xe2, 0xf1, 0xc8, 0xa2, 0x7b, 0x78, 0x57, 0xef, 0x43, 0x8a, 0xee, 0x3c, 0x49, 0xbe, 0xe8, 0xfd,
-	0x0c, 0x87, 0x40, 0xe4, 0x1b, 0xfd, 0x02, 0x40, 0xb5, 0x6d, 0x53, 0x3b, 0x1d, 0x78, 0x1d, 0xac,
-	0x86, 0x5b, 0x4e, 0xd5, 0xe1, 0xdb, 0xba, 0xc6, 0x4d, 0x68, 0xd9, 0x13, 0xf5, 0x99, 0x91, 0x4f,
-	0xa1, 0xb4, 0x0f, 0xc5, 0xa0, 0xac, 0x93, 0xb4, 0xb3, 0x31, 0x04, 0x93, 0x76, 0x86, 0xc1, 0x78,
-	0xd2, 0xee, 0xa6, 0xfc, 0x09, 0x76, 0x09, 0x45, 0x1b, 0xd2, 0x7f, 0x05, 0x58, 0xf0, 0x1b, 0xde,
-	0xb7, 0x9c, 0x8a, 0x4e, 0xc9, 0xbe, 0xaf, 0x8e, 0x65, 0xa2, 0x99, 0x73, 0xd5, 0x3a, 0xfe, 0x2e,
-	0x13, 0xd1, 0x0f, 0x04, 0xc8, 0xba, 0x93, 0x0f, 0xde, 0x47, 0x05, 0x2e, 0xf0, 0xd8, 0xda, 0xc5,
-	0xfd, 0x97, 0x48, 0xec, 0xba, 0x2e, 0xe1, 0x5e, 0xd7, 0xdd, 0x75, 0x73, 0xa5, 0xa8, 0xea, 0x9e,
-	0x7f, 0xa5, 0xb9, 0x4d, 0x39, 0xa9, 0xe1, 0xef, 0xf8, 0x38, 0x48, 0x92, 0x80, 0x7e, 0x04, 0x69,
-	0xb5, 0xe5, 0xd6, 0x34, 0x8b, 0x21, 0xc5, 0x3e, 0x87, 0x75, 0xbd, 0x39, 0xac, 0x52, 0x4e, 0x99,
-	0x4b, 0xf0, 0x51, 0xc5, 0x9d, 0x51, 0x49, 0xaf, 0x11, 0xbd, 0x8c, 0x27, 0xe8, 0x11, 0x8b, 0x00,
-	0xc7, 0xfb, 0x0f, 0x0e, 0xb6, 0x77, 0xee, 0xed, 0xd4, 0xb7, 0x79, 0xb6, 0xb4, 0xbd, 0x5d, 0xdf,
-	0x16, 0xe3, 0x84, 0x4f, 0xae, 0x3f, 0x38, 0x38, 0xa9, 0x6f, 0x8b, 0x09, 0xe9, 0x2e, 0xe4, 0x5c,
-	0xaf, 0x42, 0x10, 0xbe, 0x53, 0x9f, 0x15, 0xf8, 0xd9, 0xe6, 0xd5, 0xf5, 0x65, 0x48, 0x19, 0xfa,
-	0x7b, 0xfc, 0xee, 0x2c, 0x21, 0xb3, 0x86, 0xd4, 0x86, 0xd2, 0x88, 0x4b, 0x42, 0x77, 0x21, 0x63,
-	0x0c, 0x4e, 0x15, 0xc7, 0x68, 0x47, 0xaa, 0xd8, 0x0e, 0x76, 0x1c, 0x9c, 0x76, 0xb5, 0xd6, 0x7d,
-	0x7c, 0xe9, 0x2c, 0x93, 0x31, 0x38, 0xbd, 0xcf, 0x6c, 0x9b, 0xf5, 0x12, 0xf7, 0xf7, 0x72, 0x01,
-	0x59, 0xe7, 0xa8, 0xa2, 0x1f, 0x43, 0xce, 0xf5, 0x76, 0xee, 0xdd, 0x77, 0xa4, 0x9b, 0xe4, 0xea,
-	0x3d, 0x11, 0x74, 0x13, 0x16, 0x2d, 0xed, 0xbc, 0xef, 0x94, 0xee, 0x59, 0xf5, 0x26, 0x4e, 0xcf,
-	0x4c, 0x89, 0xfd, 0xd8, 0x73, 0x0a, 0x0c, 0x24, 0xc8, 0x89, 0xa3, 0xbe, 0xe2, 0xbb, 0x1c, 0x40,
-	0x48, 0x30, 0x4e, 0x84, 0x05, 0xe3, 0x5f, 0xc5, 0x21, 0xef, 0xbb, 0x19, 0x40, 0x3f, 0xf4, 0x39,
-	0xae, 0x62, 0x48, 0x14, 0xf1, 0xf1, 0x7a, 0x97, 0xcb, 0xc1, 0x89, 0xc5, 0xe7, 0x9f, 0x58, 0xd4,
-	0xfd, 0x8b, 0x73, 0xd1, 0x90, 0x9c, 0xfb, 0xa2, 0xe1, 0x39, 0x40, 0xb6, 0x6e, 0xab, 0x5d, 0xe5,
-	0x42, 0xb7, 0xb5, 0xfe, 0xb9, 0xc2, 0x4c, 0x83, 0xb9, 0x19, 0x91, 0xfe, 0x39, 0xa1, 0x3f, 0x0e,
-	0xa9, 0x95, 0xfc, 0x52, 0x80, 0xac, 0x8b, 0xe8, 0xe6, 0xbd, 0x7a, 0xbe, 0x02, 0x69, 0x0e, 0x5a,
-	0xd8, 0xdd, 0x33, 0x6f, 0x85, 0xde, 0xa8, 0x54, 0x20, 0xdb, 0xc3, 0xb6, 0x4a, 0x7d, 0x26, 0x8b,
-	0x80, 0x6e, 0xfb, 0xe6, 0xcb, 0x90, 0xf7, 0x5d, 0xdb, 0x13, 0x37, 0xba, 0x5f, 0x7f, 0x43, 0x8c,
-	0x55, 0x32, 0x1f, 0x7e, 0xb2, 0x96, 0xd8, 0xc7, 0xef, 0x91, 0x13, 0x26, 0xd7, 0x6b, 0x8d, 0x7a,
-	0xed, 0xbe, 0x28, 0x54, 0xf2, 0x1f, 0x7e, 0xb2, 0x96, 0x91, 0x31, 0x2d, 0xa6, 0xdf, 0xbc, 0x0f,
-	0xa5, 0x91, 0x8d, 0x09, 0x1e, 0x68, 0x04, 0xc5, 0xed, 0xe3, 0xc3, 0xbd, 0x9d, 0x5a, 0xb5, 0x59,
-	0x57, 0x4e, 0x0e, 0x9a, 0x75, 0x51, 0x40, 0x8f, 0xc2, 0xd2, 0xde, 0xce, 0x4f, 0x1a, 0x4d, 0xa5,
-	0xb6, 0xb7, 0x53, 0xdf, 0x6f, 0x2a, 0xd5, 0x66, 0xb3, 0x5a, 0xbb, 0x2f, 0xc6, 0x37, 0xff, 0x94,
-	0x87, 0x52, 0x75, 0xab, 0xb6, 0x43, 0x60, 0x9b, 0xd6, 0x52, 0xa9, 0x7b, 0xa8, 0x41, 0x92, 0x96,
-	0x05, 0x27, 0x3e, 0xde, 0xab, 0x4c, 0xbe, 0x21, 0x41, 0xf7, 0x20, 0x45, 0x2b, 0x86, 0x68, 0xf2,
-	0x6b, 0xbe, 0xca, 0x94, 0x2b, 0x13, 0x32, 0x18, 0x7a, 0x9c, 0x26, 0x3e, 0xef, 0xab, 0x4c, 0xbe,
-	0x41, 0x41, 0x7b, 0x90, 0x71, 0x0a, 0x46, 0xd3, 0xde, 0xdc, 0x55, 0xa6, 0x5e, 0x6b, 0x90, 0xa9,
-	0xb1, 0xc2, 0xdb, 0xe4, 0x97, 0x7f, 0x95, 0x29, 0x77, 0x2b, 0x68, 0x07, 0xd2, 0xbc, 0xe8, 0x31,
-	0xe5, 0x31, 0x5f, 0x65, 0xda, 0x6d, 0x09, 0x92, 0x21, 0xe7, 0x95, 0x34, 0xa7, 0xbf, 0x67, 0xac,
-	0xcc, 0x70, 0x6d, 0x84, 0xde, 0x82, 0x42, 0xb0, 0xa0, 0x32, 0xdb, 0x83, 0xc1, 0xca, 0x8c, 0xf7,
-	0x32, 0x44, 0x7f, 0xb0, 0xba, 0x32, 0xdb, 0x03, 0xc2, 0xca, 0x8c, 0xd7, 0x34, 0xe8, 0x1d, 0x58,
-	0x1c, 0xaf, 0x7e, 0xcc, 0xfe, 0x9e, 0xb0, 0x32, 0xc7, 0xc5, 0x0d, 0xea, 0x01, 0x0a, 0xa9, 0x9a,
-	0xcc, 0xf1, 0xbc, 0xb0, 0x32, 0xcf, 0x3d, 0x0e, 0x6a, 0x43, 0x69, 0xb4, 0x12, 0x31, 0xeb, 0x73,
-	0xc3, 0xca, 0xcc, 0x77, 0x3a, 0xac, 0x97, 0x20, 0x2c, 0x9f, 0xf5, 0xf9, 0x61, 0x65, 0xe6, 0x2b,
-	0x1e, 0x74, 0x0c, 0xe0, 0x83, 0x95, 0x33, 0x3c, 0x47, 0xac, 0xcc, 0x72, 0xd9, 0x83, 0x0c, 0x58,
-	0x0a, 0xc3, 0x9b, 0xf3, 0xbc, 0x4e, 0xac, 0xcc, 0x75, 0x07, 0x44, 0xec, 0x39, 0x88, 0x1c, 0x67,
-	0x7b, 0xad, 0x58, 0x99, 0xf1, 0x32, 0x68, 0xab, 0xfe, 0xe9, 0x17, 0x2b, 0xc2, 0x67, 0x5f, 0xac,
-	0x08, 0xff, 0xfe, 0x62, 0x45, 0xf8, 0xe8, 0xcb, 0x95, 0xd8, 0x67, 0x5f, 0xae, 0xc4, 0xfe, 0xf9,
-	0xe5, 0x4a, 0xec, 0xa7, 0xcf, 0x9e, 0x6b, 0x76, 0x67, 0x70, 0xba, 0xde, 0xd2, 0x7b, 0x1b, 0xfe,
-	0x07, 0xde, 0x61, 0xcf, 0xca, 0x4f, 0xd3, 0x34, 0xa0, 0xde, 0xfa, 0x5f, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0xdc, 0x85, 0x19, 0x4e, 0x76, 0x2e, 0x00, 0x00,
+	0xb5, 0x57, 0xeb, 0xb3, 0x75, 0xf4, 0xd5, 0xbe, 0xf6, 0x0c, 0x1a, 0xcd, 0x8c, 0x6d, 0x9a, 0x02,
+	0x86, 0x01, 0x6c, 0x9e, 0xe7, 0x01, 0xc3, 0x1b, 0x78, 0x94, 0x2d, 0x6b, 0x90, 0x3d, 0x1e, 0xdb,
+	0xb4, 0x65, 0x53, 0xbc, 0x97, 0xd0, 0xb4, 0xa5, 0x6b, 0xab, 0x19, 0x49, 0xdd, 0x74, 0xb7, 0x8c,
+	0xcc, 0x32, 0x09, 0x55, 0x29, 0x16, 0x29, 0x76, 0x61, 0x11, 0x76, 0x49, 0x55, 0xfe, 0x84, 0x64,
+	0x93, 0x55, 0x16, 0x2c, 0xb2, 0x60, 0x95, 0xca, 0x8a, 0xa4, 0x60, 0xc7, 0x3f, 0x90, 0x5d, 0x2a,
+	0x75, 0x3f, 0xba, 0xd5, 0x2d, 0x75, 0xeb, 0x03, 0x28, 0xaa, 0x52, 0xc5, 0xee, 0xde, 0xd3, 0xe7,
+	0x9c, 0xfb, 0xd1, 0xe7, 0x9e, 0x7b, 0x7e, 0xe7, 0x5c, 0xb8, 0xee, 0xe0, 0x5e, 0x0b, 0x5b, 0x5d,
+	0xbd, 0xe7, 0xac, 0x6b, 0xa7, 0x4d, 0x7d, 0xdd, 0xb9, 0x34, 0xb1, 0xbd, 0x66, 0x5a, 0x86, 0x63,
+	0xa0, 0xd2, 0xf0, 0xe3, 0x1a, 0xf9, 0x58, 0xb9, 0xe9, 0xe3, 0x6e, 0x5a, 0x97, 0xa6, 0x63, 0xac,
+	0x9b, 0x96, 0x61, 0x9c, 0x31, 0xfe, 0xca, 0x8d, 0xf1, 0xcf, 0x8f, 0xf0, 0x25, 0xd7, 0x16, 0x10,
+	0xa6, 0xa3, 0xac, 0x9b, 0x9a, 0xa5, 0x75, 0xdd, 0xcf, 0x2b, 0xe7, 0x86, 0x71, 0xde, 0xc1, 0xeb,
+	0xb4, 0x77, 0xda, 0x3f, 0x5b, 0x77, 0xf4, 0x2e, 0xb6, 0x1d, 0xad, 0x6b, 0x72, 0x86, 0xa5, 0x73,
+	0xe3, 0xdc, 0xa0, 0xcd, 0x75, 0xd2, 0x62, 0x54, 0xf9, 0x8f, 0x59, 0xc8, 0x28, 0xf8, 0xfd, 0x3e,
+	0xb6, 0x1d, 0xb4, 0x01, 0x49, 0xdc, 0x6c, 0x1b, 0x65, 0x61, 0x55, 0xb8, 0x95, 0xdb, 0xb8, 0xb1,
+	0x36, 0x32, 0xfd, 0x35, 0xce, 0x57, 0x6b, 0xb6, 0x8d, 0x7a, 0x4c, 0xa1, 0xbc, 0xe8, 0x45, 0x48,
+	0x9d, 0x75, 0xfa, 0x76, 0xbb, 0x1c, 0xa7, 0x42, 0x37, 0xa3, 0x84, 0xee, 0x13, 0xa6, 0x7a, 0x4c,
+	0x61, 0xdc, 0x64, 0x28, 0xbd, 0x77, 0x66, 0x94, 0x13, 0x93, 0x87, 0xda, 0xe9, 0x9d, 0xd1, 0xa1,
+	0x08, 0x2f, 0xda, 0x02, 0xd0, 0x7b, 0xba, 0xa3, 0x36, 0xdb, 0x9a, 0xde, 0x2b, 0x27, 0xa9, 0xe4,
+	0xe3, 0xd1, 0x92, 0xba, 0x53, 0x25, 0x8c, 0xf5, 0x98, 0x92, 0xd5, 0xdd, 0x0e, 0x99, 0xee, 0xfb,
+	0x7d, 0x6c, 0x5d, 0x96, 0x53, 0x93, 0xa7, 0xfb, 0x26, 0x61, 0x22, 0xd3, 0xa5, 0xdc, 0xe8, 0x55,
+	0x10, 0x9b, 0x6d, 0xdc, 0x7c, 0xa4, 0x3a, 0x83, 0x72, 0x86, 0x4a, 0xae, 0x44, 0x49, 0x56, 0x09,
+	0x5f, 0x63, 0x50, 0x8f, 0x29, 0x99, 0x26, 0x6b, 0xa2, 0xbb, 0x90, 0x6e, 0x1a, 0xdd, 0xae, 0xee,
+	0x94, 0x81, 0xca, 0x2e, 0x47, 0xca, 0x52, 0xae, 0x7a, 0x4c, 0xe1, 0xfc, 0x68, 0x1f, 0x8a, 0x1d,
+	0xdd, 0x76, 0x54, 0xbb, 0xa7, 0x99, 0x76, 0xdb, 0x70, 0xec, 0x72, 0x8e, 0x6a, 0x78, 0x32, 0x4a,
+	0xc3, 0x9e, 0x6e, 0x3b, 0x47, 0x2e, 0x73, 0x3d, 0xa6, 0x14, 0x3a, 0x7e, 0x02, 0xd1, 0x67, 0x9c,
+	0x9d, 0x61, 0xcb, 0x53, 0x58, 0xce, 0x4f, 0xd6, 0x77, 0x40, 0xb8, 0x5d, 0x79, 0xa2, 0xcf, 0xf0,
+	0x13, 0xd0, 0xff, 0xc3, 0x62, 0xc7, 0xd0, 0x5a, 0x9e, 0x3a, 0xb5, 0xd9, 0xee, 0xf7, 0x1e, 0x95,
+	0x0b, 0x54, 0xe9, 0x33, 0x91, 0x93, 0x34, 0xb4, 0x96, 0xab, 0xa2, 0x4a, 0x04, 0xea, 0x31, 0x65,
+	0xa1, 0x33, 0x4a, 0x44, 0xef, 0xc0, 0x92, 0x66, 0x9a, 0x9d, 0xcb, 0x51, 0xed, 0x45, 0xaa, 0xfd,
+	0x76, 0x94, 0xf6, 0x4d, 0x22, 0x33, 0xaa, 0x1e, 0x69, 0x63, 0x54, 0xd4, 0x00, 0xc9, 0xb4, 0xb0,
+	0xa9, 0x59, 0x58, 0x35, 0x2d, 0xc3, 0x34, 0x6c, 0xad, 0x53, 0x2e, 0x51, 0xdd, 0x4f, 0x47, 0xe9,
+	0x3e, 0x64, 0xfc, 0x87, 0x9c, 0xbd, 0x1e, 0x53, 0x4a, 0x66, 0x90, 0xc4, 0xb4, 0x1a, 0x4d, 0x6c,
+	0xdb, 0x43, 0xad, 0xd2, 0x34, 0xad, 0x94, 0x3f, 0xa8, 0x35, 0x40, 0x42, 0x35, 0xc8, 0xe1, 0x01,
+	0x11, 0x57, 0x2f, 0x0c, 0x07, 0x97, 0x17, 0xa8, 0x42, 0x39, 0xf2, 0x84, 0x52, 0xd6, 0x13, 0xc3,
+	0xc1, 0xf5, 0x98, 0x02, 0xd8, 0xeb, 0x21, 0x0d, 0xae, 0x5c, 0x60, 0x4b, 0x3f, 0xbb, 0xa4, 0x6a,
+	0x54, 0xfa, 0xc5, 0xd6, 0x8d, 0x5e, 0x19, 0x51, 0x85, 0xcf, 0x46, 0x29, 0x3c, 0xa1, 0x42, 0x44,
+	0x45, 0xcd, 0x15, 0xa9, 0xc7, 0x94, 0xc5, 0x8b, 0x71, 0x32, 0x31, 0xb1, 0x33, 0xbd, 0xa7, 0x75,
+	0xf4, 0x0f, 0xb1, 0x7a, 0xda, 0x31, 0x9a, 0x8f, 0xca, 0x8b, 0x93, 0x4d, 0xec, 0x3e, 0xe7, 0xde,
+	0x22, 0xcc, 0xc4, 0xc4, 0xce, 0xfc, 0x84, 0xad, 0x0c, 0xa4, 0x2e, 0xb4, 0x4e, 0x1f, 0xef, 0x26,
+	0xc5, 0xb4, 0x94, 0xd9, 0x4d, 0x8a, 0xa2, 0x94, 0xdd, 0x4d, 0x8a, 0x59, 0x09, 0xe4, 0xa7, 0x21,
+	0xe7, 0x73, 0x49, 0xa8, 0x0c, 0x99, 0x2e, 0xb6, 0x6d, 0xed, 0x1c, 0x53, 0x0f, 0x96, 0x55, 0xdc,
+	0xae, 0x5c, 0x84, 0xbc, 0xdf, 0x0d, 0xc9, 0x9f, 0x08, 0x90, 0xf3, 0x79, 0x18, 0x22, 0x79, 0x81,
+	0x2d, 0xba, 0x11, 0x5c, 0x92, 0x77, 0xd1, 0x13, 0x50, 0xa0, 0x8b, 0x50, 0xdd, 0xef, 0xc4, 0xcd,
+	0x25, 0x95, 0x3c, 0x25, 0x9e, 0x70, 0xa6, 0x15, 0xc8, 0x99, 0x1b, 0xa6, 0xc7, 0x92, 0xa0, 0x2c,
+	0x60, 0x6e, 0x98, 0x2e, 0xc3, 0xe3, 0x90, 0x27, 0x2b, 0xf6, 0x38, 0x92, 0x74, 0x90, 0x1c, 0xa1,
+	0x71, 0x16, 0xf9, 0x2f, 0x71, 0x90, 0x46, 0x5d, 0x17, 0xba, 0x0b, 0x49, 0xe2, 0xc5, 0xb9, 0x43,
+	0xae, 0xac, 0x31, 0x17, 0xbf, 0xe6, 0xba, 0xf8, 0xb5, 0x86, 0xeb, 0xe2, 0xb7, 0xc4, 0xcf, 0xbf,
+	0x5c, 0x89, 0x7d, 0xf2, 0xf7, 0x15, 0x41, 0xa1, 0x12, 0xe8, 0x1a, 0x71, 0x58, 0x9a, 0xde, 0x53,
+	0xf5, 0x16, 0x9d, 0x72, 0x96, 0x78, 0x23, 0x4d, 0xef, 0xed, 0xb4, 0xd0, 0x1e, 0x48, 0x4d, 0xa3,
+	0x67, 0xe3, 0x9e, 0xdd, 0xb7, 0x55, 0x76, 0x85, 0x94, 0x13, 0xe3, 0xce, 0x94, 0x5d, 0x64, 0x55,
+	0x97, 0xf3, 0x90, 0x32, 0x2a, 0xa5, 0x66, 0x90, 0x80, 0xee, 0x03, 0x5c, 0x68, 0x1d, 0xbd, 0xa5,
+	0x39, 0x86, 0x65, 0x97, 0x93, 0xab, 0x89, 0x5b, 0xb9, 0x8d, 0xd5, 0xb1, 0x5f, 0x7d, 0xe2, 0xb2,
+	0x1c, 0x9b, 0x2d, 0xcd, 0xc1, 0x5b, 0x49, 0x32, 0x5d, 0xc5, 0x27, 0x89, 0x9e, 0x82, 0x92, 0x66,
+	0x9a, 0xaa, 0xed, 0x68, 0x0e, 0x56, 0x4f, 0x2f, 0x1d, 0x6c, 0x53, 0x17, 0x9d, 0x57, 0x0a, 0x9a,
+	0x69, 0x1e, 0x11, 0xea, 0x16, 0x21, 0xa2, 0x27, 0xa1, 0x48, 0xbc, 0xb9, 0xae, 0x75, 0xd4, 0x36,
+	0xd6, 0xcf, 0xdb, 0x4e, 0x39, 0xbd, 0x2a, 0xdc, 0x4a, 0x28, 0x05, 0x4e, 0xad, 0x53, 0xa2, 0xdc,
+	0x82, 0xbc, 0xdf, 0x93, 0x23, 0x04, 0xc9, 0x96, 0xe6, 0x68, 0x74, 0x27, 0xf3, 0x0a, 0x6d, 0x13,
+	0x9a, 0xa9, 0x39, 0x6d, 0xbe, 0x3f, 0xb4, 0x8d, 0xae, 0x42, 0x9a, 0xab, 0x4d, 0x50, 0xb5, 0xbc,
+	0x87, 0x96, 0x20, 0x65, 0x5a, 0xc6, 0x05, 0xa6, 0xbf, 0x4e, 0x54, 0x58, 0x47, 0x56, 0xa0, 0x18,
+	0xf4, 0xfa, 0xa8, 0x08, 0x71, 0x67, 0xc0, 0x47, 0x89, 0x3b, 0x03, 0xf4, 0x02, 0x24, 0xc9, 0x46,
+	0xd2, 0x31, 0x8a, 0x21, 0xf7, 0x1c, 0x97, 0x6b, 0x5c, 0x9a, 0x58, 0xa1, 0x9c, 0x72, 0x09, 0x0a,
+	0x81, 0xdb, 0x40, 0xbe, 0x0a, 0x4b, 0x61, 0xce, 0x5d, 0x6e, 0xc3, 0x52, 0x98, 0x93, 0x46, 0x2f,
+	0x82, 0xe8, 0x79, 0x77, 0x66, 0x38, 0xd7, 0xc6, 0x86, 0x75, 0x99, 0x15, 0x8f, 0x95, 0x58, 0x0c,
+	0xf9, 0x01, 0x6d, 0x8d, 0xdf, 0xe5, 0x79, 0x25, 0xa3, 0x99, 0x66, 0x5d, 0xb3, 0xdb, 0xf2, 0xbb,
+	0x50, 0x8e, 0xf2, 0xdc, 0xbe, 0x0d, 0x13, 0xa8, 0xd9, 0xf3, 0x1e, 0xa1, 0x9f, 0x19, 0x56, 0x57,
+	0x73, 0xa8, 0xb2, 0x82, 0xc2, 0x7b, 0x64, 0x23, 0x99, 0x17, 0x4f, 0x50, 0x32, 0xeb, 0xc8, 0x2a,
+	0x5c, 0x8b, 0xf4, 0xde, 0x44, 0x44, 0xef, 0xb5, 0x30, 0xdb, 0xd6, 0x82, 0xc2, 0x3a, 0x43, 0x45,
+	0x6c, 0xb2, 0xac, 0x43, 0x86, 0xb5, 0xe9, 0x5a, 0xa9, 0xfe, 0xac, 0xc2, 0x7b, 0xf2, 0xa7, 0x09,
+	0xb8, 0x1a, 0xee, 0xc3, 0xd1, 0x2a, 0xe4, 0xbb, 0xda, 0x40, 0x75, 0x06, 0xdc, 0xec, 0x04, 0xfa,
+	0xe3, 0xa1, 0xab, 0x0d, 0x1a, 0x03, 0x66, 0x73, 0x12, 0x24, 0x9c, 0x81, 0x5d, 0x8e, 0xaf, 0x26,
+	0x6e, 0xe5, 0x15, 0xd2, 0x44, 0xc7, 0xb0, 0xd0, 0x31, 0x9a, 0x5a, 0x47, 0xed, 0x68, 0xb6, 0xa3,
+	0xf2, 0xcb, 0x9d, 0x1d, 0xa2, 0x27, 0xc6, 0x36, 0x9b, 0x79, 0x63, 0xdc, 0x62, 0xff, 0x93, 0x38,
+	0x1c, 0x6e, 0xff, 0x25, 0xaa, 0x63, 0x4f, 0x73, 0x7f, 0x35, 0xda, 0x86, 0x5c, 0x57, 0xb7, 0x4f,
+	0x71, 0x5b, 0xbb, 0xd0, 0x0d, 0x8b, 0x9f, 0xa6, 0x71, 0xa3, 0x79, 0x38, 0xe4, 0xe1, 0x9a, 0xfc,
+	0x62, 0xbe, 0x5f, 0x92, 0x0a, 0xd8, 0xb0, 0xeb, 0x4d, 0xd2, 0x73, 0x7b, 0x93, 0x17, 0x60, 0xa9,
+	0x87, 0x07, 0x8e, 0x3a, 0x3c, 0xaf, 0xcc, 0x4e, 0x32, 0x74, 0xeb, 0x11, 0xf9, 0xe6, 0x9d, 0x70,
+	0x9b, 0x98, 0x0c, 0x7a, 0x86, 0xde, 0x82, 0xa6, 0x61, 0x63, 0x4b, 0xd5, 0x5a, 0x2d, 0x0b, 0xdb,
+	0x76, 0x59, 0xa4, 0xdc, 0x25, 0x97, 0xbe, 0xc9, 0xc8, 0xf2, 0x2f, 0xfd, 0xbf, 0x26, 0x78, 0xeb,
+	0xf1, 0x8d, 0x17, 0x86, 0x1b, 0x7f, 0x04, 0x4b, 0x5c, 0xbe, 0x15, 0xd8, 0x7b, 0x16, 0x7d, 0x5e,
+	0x1f, 0x3f, 0x5f, 0xa3, 0x7b, 0x8e, 0x5c, 0xf1, 0xe8, 0x6d, 0x4f, 0x7c, 0xbb, 0x6d, 0x47, 0x90,
+	0xa4, 0x9b, 0x92, 0x64, 0x2e, 0x86, 0xb4, 0xff, 0xd3, 0x7e, 0xc5, 0xeb, 0xb0, 0x30, 0x16, 0x41,
+	0x78, 0xeb, 0x12, 0x42, 0xd7, 0x15, 0xf7, 0xaf, 0x4b, 0xfe, 0x8d, 0x00, 0x95, 0xe8, 0x90, 0x21,
+	0x54, 0xd5, 0xb3, 0xb0, 0xe0, 0xad, 0xc5, 0x9b, 0x1f, 0x3b, 0xd3, 0x92, 0xf7, 0x81, 0x4f, 0x30,
+	0xd2, 0x3d, 0x3f, 0x09, 0xc5, 0x91, 0x80, 0x86, 0xfd, 0x85, 0xc2, 0x85, 0x7f, 0x7c, 0xf9, 0x17,
+	0x09, 0x58, 0x0a, 0x8b, 0x3a, 0x42, 0x0c, 0xed, 0x4d, 0x58, 0x6c, 0xe1, 0xa6, 0xde, 0xfa, 0xb6,
+	0x76, 0xb6, 0xc0, 0xa5, 0x7f, 0x34, 0xb3, 0x71, 0x33, 0xfb, 0x15, 0x80, 0xa8, 0x60, 0xdb, 0x34,
+	0x7a, 0x36, 0x46, 0x5b, 0x90, 0xc5, 0x83, 0x26, 0x36, 0x1d, 0x37, 0xfa, 0x0a, 0x8f, 0x6b, 0x19,
+	0x77, 0xcd, 0xe5, 0x24, 0xa8, 0xce, 0x13, 0x43, 0x77, 0x38, 0x70, 0x8d, 0xc6, 0xa0, 0x5c, 0xdc,
+	0x8f, 0x5c, 0x5f, 0x72, 0x91, 0x6b, 0x22, 0x12, 0x94, 0x31, 0xa9, 0x11, 0xe8, 0x7a, 0x87, 0x43,
+	0xd7, 0xe4, 0x94, 0xc1, 0x02, 0xd8, 0xb5, 0x1a, 0xc0, 0xae, 0xa9, 0x29, 0xcb, 0x8c, 0x00, 0xaf,
+	0x2f, 0xb9, 0xe0, 0x35, 0x3d, 0x65, 0xc6, 0x23, 0xe8, 0xf5, 0x35, 0x1f, 0x7a, 0x15, 0x57, 0x85,
+	0xd0, 0x08, 0xcd, 0x15, 0x0d, 0x81, 0xaf, 0xaf, 0x78, 0xf0, 0x35, 0x17, 0x09, 0x7d, 0xb9, 0xf0,
+	0x28, 0x7e, 0x3d, 0x18, 0xc3, 0xaf, 0x0c, 0x6f, 0x3e, 0x15, 0xa9, 0x62, 0x0a, 0x80, 0x3d, 0x18,
+	0x03, 0xb0, 0x85, 0x29, 0x0a, 0xa7, 0x20, 0xd8, 0x9f, 0x84, 0x23, 0xd8, 0x68, 0x8c, 0xc9, 0xa7,
+	0x39, 0x1b, 0x84, 0x55, 0x23, 0x20, 0x6c, 0x29, 0x12, 0x6e, 0x31, 0xf5, 0x33, 0x63, 0xd8, 0xe3,
+	0x10, 0x0c, 0xcb, 0xd0, 0xe6, 0xad, 0x48, 0xe5, 0x33, 0x80, 0xd8, 0xe3, 0x10, 0x10, 0xbb, 0x30,
+	0x55, 0xed, 0x54, 0x14, 0x7b, 0x3f, 0x88, 0x62, 0x51, 0x44, 0xc0, 0x34, 0x3c, 0xed, 0x11, 0x30,
+	0xf6, 0x34, 0x0a, 0xc6, 0x32, 0xa8, 0xf9, 0x5c, 0xa4, 0xc6, 0x39, 0x70, 0xec, 0xc1, 0x18, 0x8e,
+	0x5d, 0x9a, 0x62, 0x69, 0xb3, 0x03, 0xd9, 0x8c, 0x24, 0x32, 0x08, 0xbb, 0x9b, 0x14, 0x41, 0xca,
+	0xc9, 0xcf, 0xc0, 0x82, 0xab, 0xc4, 0xf3, 0x70, 0x24, 0xc0, 0xc5, 0x96, 0x65, 0x58, 0x1c, 0x92,
+	0xb2, 0x8e, 0x7c, 0x0b, 0xf2, 0x1e, 0xeb, 0x64, 0xd0, 0x4b, 0x81, 0x84, 0xcf, 0x83, 0xc9, 0x7f,
+	0x10, 0x20, 0xef, 0x77, 0x4e, 0x01, 0x50, 0x94, 0xe5, 0xa0, 0xc8, 0x07, 0x85, 0xe3, 0x41, 0x28,
+	0xbc, 0x02, 0x39, 0x02, 0x10, 0x46, 0x50, 0xae, 0x66, 0x7a, 0x28, 0xf7, 0x36, 0x2c, 0xd0, 0xab,
+	0x92, 0x01, 0x66, 0x7e, 0x21, 0x25, 0xe9, 0x85, 0x54, 0x22, 0x1f, 0xd8, 0xbe, 0x50, 0x32, 0x7a,
+	0x1e, 0x16, 0x7d, 0xbc, 0x1e, 0xf0, 0x60, 0x90, 0x4f, 0xf2, 0xb8, 0x37, 0x39, 0x02, 0xf9, 0xb3,
+	0x00, 0x0b, 0x63, 0xce, 0x31, 0x14, 0xc9, 0x0a, 0xdf, 0x13, 0x92, 0x8d, 0x7f, 0x6b, 0x24, 0xeb,
+	0x07, 0x52, 0x89, 0x20, 0x90, 0xfa, 0xa7, 0x00, 0x85, 0x80, 0x8f, 0x26, 0xbf, 0xa0, 0x69, 0xb4,
+	0x30, 0x87, 0x36, 0xb4, 0x4d, 0x82, 0x91, 0x8e, 0x71, 0xce, 0x01, 0x0c, 0x69, 0x12, 0x2e, 0xef,
+	0xca, 0xc9, 0xf2, 0x1b, 0xc5, 0x43, 0x45, 0xec, 0xca, 0x67, 0x1d, 0x22, 0xfb, 0x08, 0xb3, 0x0b,
+	0x22, 0xaf, 0x90, 0x26, 0x5a, 0xe2, 0x66, 0xc7, 0xaf, 0x6e, 0xd6, 0x41, 0x77, 0x21, 0x4b, 0x33,
+	0xcf, 0xaa, 0x61, 0xda, 0x65, 0x71, 0x3c, 0xa8, 0x61, 0xe9, 0xe7, 0xb5, 0x43, 0xc2, 0x73, 0x60,
+	0xda, 0x8a, 0x68, 0xf2, 0x96, 0x2f, 0xd6, 0xc8, 0x06, 0x62, 0x8d, 0x1b, 0x90, 0x25, 0xb3, 0xb7,
+	0x4d, 0xad, 0x89, 0x69, 0x9e, 0x33, 0xab, 0x0c, 0x09, 0xf2, 0xc7, 0x71, 0x28, 0x8d, 0x5c, 0x31,
+	0xa1, 0x6b, 0x77, 0x4d, 0x32, 0xee, 0xc3, 0xe9, 0x37, 0x01, 0xce, 0x35, 0x5b, 0xfd, 0x40, 0xeb,
+	0x39, 0xb8, 0xc5, 0x97, 0x9b, 0x3d, 0xd7, 0xec, 0xb7, 0x28, 0x21, 0x38, 0xb0, 0x38, 0x32, 0xb0,
+	0x0f, 0x10, 0x66, 0xfd, 0x80, 0x10, 0x55, 0x40, 0x34, 0x2d, 0xdd, 0xb0, 0x74, 0xe7, 0x92, 0xce,
+	0x36, 0xa1, 0x78, 0x7d, 0x74, 0x1d, 0xb2, 0x3d, 0x43, 0x3d, 0x37, 0x6c, 0x5b, 0x37, 0xe9, 0x85,
+	0x25, 0x2a, 0x62, 0xcf, 0x78, 0x83, 0xf6, 0x49, 0x46, 0xc8, 0x75, 0x89, 0xaa, 0xd1, 0xeb, 0x5c,
+	0xd2, 0x0b, 0x48, 0x54, 0xf2, 0x2e, 0xf1, 0xa0, 0xd7, 0xb9, 0xdc, 0x4d, 0x8a, 0x09, 0x29, 0xb9,
+	0x9b, 0x14, 0x93, 0x52, 0xca, 0xcb, 0x5b, 0xb1, 0x43, 0x9f, 0x93, 0xf2, 0xf2, 0x47, 0xf1, 0xa1,
+	0x35, 0x6f, 0xe3, 0x8e, 0x7e, 0x81, 0xad, 0x39, 0xb6, 0x63, 0x36, 0xf3, 0x58, 0x0e, 0xd9, 0x34,
+	0x1f, 0x85, 0xac, 0x9f, 0xf4, 0xfa, 0x36, 0x6e, 0xf1, 0x0c, 0x8a, 0xd7, 0x47, 0x75, 0x48, 0xe3,
+	0x0b, 0xdc, 0x73, 0xec, 0x72, 0x86, 0x9e, 0x82, 0xab, 0xe3, 0x90, 0x96, 0x7c, 0xde, 0x2a, 0x13,
+	0xdb, 0xff, 0xe6, 0xcb, 0x15, 0x89, 0x71, 0x3f, 0x67, 0x74, 0x75, 0x07, 0x77, 0x4d, 0xe7, 0x52,
+	0xe1, 0xf2, 0x93, 0xff, 0x8d, 0xbc, 0x09, 0x45, 0x77, 0x1b, 0x78, 0x88, 0xfc, 0x04, 0x14, 0x2c,
+	0xec, 0x90, 0xbc, 0x55, 0x20, 0xcc, 0xcf, 0x33, 0x22, 0xf3, 0x1d, 0xbb, 0x49, 0x51, 0x90, 0xe2,
+	0xbb, 0x49, 0x31, 0x2e, 0x25, 0xe4, 0x43, 0xb8, 0x12, 0x1a, 0x39, 0xa0, 0x97, 0x21, 0x3b, 0x0c,
+	0x3a, 0x84, 0xd5, 0xc4, 0xe4, 0x34, 0xc8, 0x90, 0x57, 0xfe, 0x93, 0x00, 0x57, 0x42, 0x63, 0x07,
+	0x54, 0x83, 0xb4, 0x85, 0xed, 0x7e, 0x87, 0xa5, 0x3a, 0x8a, 0x1b, 0xcf, 0xcf, 0x16, 0x73, 0x10,
+	0x6a, 0xbf, 0xe3, 0x28, 0x5c, 0x58, 0x7e, 0x07, 0xd2, 0x8c, 0x82, 0x72, 0x90, 0x39, 0xde, 0x7f,
+	0xb0, 0x7f, 0xf0, 0xd6, 0xbe, 0x14, 0x43, 0x00, 0xe9, 0xcd, 0x6a, 0xb5, 0x76, 0xd8, 0x90, 0x04,
+	0x94, 0x85, 0xd4, 0xe6, 0xd6, 0x81, 0xd2, 0x90, 0xe2, 0x84, 0xac, 0xd4, 0x76, 0x6b, 0xd5, 0x86,
+	0x94, 0x40, 0x0b, 0x50, 0x60, 0x6d, 0xf5, 0xfe, 0x81, 0xf2, 0x70, 0xb3, 0x21, 0x25, 0x7d, 0xa4,
+	0xa3, 0xda, 0xfe, 0x76, 0x4d, 0x91, 0x52, 0xf2, 0x7f, 0xc1, 0x35, 0x77, 0x1e, 0xe3, 0xe9, 0x1a,
+	0x2f, 0x6b, 0x22, 0xf8, 0xb2, 0x26, 0xf2, 0xa7, 0x71, 0xa8, 0xb8, 0x32, 0x21, 0x09, 0x98, 0xdd,
+	0x91, 0x85, 0x6f, 0xcc, 0x11, 0xb7, 0x8c, 0xac, 0x9e, 0x20, 0x35, 0x0b, 0x9f, 0x61, 0xa7, 0xd9,
+	0x66, 0xa1, 0x10, 0xf3, 0xb4, 0x05, 0xa5, 0xc0, 0xa9, 0x54, 0xc8, 0x66, 0x6c, 0xef, 0xe1, 0xa6,
+	0xa3, 0xb2, 0xf3, 0x6a, 0x53, 0xb8, 0x94, 0x55, 0x0a, 0x8c, 0x7a, 0xc4, 0x88, 0xf2, 0xbb, 0x73,
+	0xed, 0x65, 0x16, 0x52, 0x4a, 0xad, 0xa1, 0xbc, 0x2d, 0x25, 0x10, 0x82, 0x22, 0x6d, 0xaa, 0x47,
+	0xfb, 0x9b, 0x87, 0x47, 0xf5, 0x03, 0xb2, 0x97, 0x8b, 0x50, 0x72, 0xf7, 0xd2, 0x25, 0xa6, 0xe4,
+	0xbf, 0xc6, 0xe1, 0xb1, 0x88, 0xc0, 0x09, 0xdd, 0x05, 0x70, 0x06, 0xaa, 0x85, 0x9b, 0x86, 0xd5,
+	0x8a, 0x36, 0xb2, 0xc6, 0x40, 0xa1, 0x1c, 0x4a, 0xd6, 0xe1, 0x2d, 0x7b, 0x42, 0xb2, 0x0d, 0xbd,
+	0xca, 0x95, 0x92, 0x55, 0xd9, 0x1c, 0x24, 0xde, 0x0c, 0xc9, 0x29, 0xe1, 0x26, 0x51, 0x4c, 0xf7,
+	0x36, 0xeb, 0xf0, 0x96, 0x8d, 0x1e, 0xfa, 0xd1, 0x74, 0x9f, 0x5e, 0x51, 0x33, 0x67, 0x65, 0x7d,
+	0x78, 0x9b, 0x11, 0x6c, 0xf4, 0x36, 0x3c, 0x36, 0x72, 0xc3, 0x7a, 0x4a, 0x53, 0xb3, 0x5e, 0xb4,
+	0x57, 0x82, 0x17, 0x2d, 0x57, 0x2d, 0xff, 0x36, 0xe1, 0xdf, 0xd8, 0x60, 0x9c, 0x78, 0x00, 0x69,
+	0xdb, 0xd1, 0x9c, 0xbe, 0xcd, 0x0d, 0xee, 0xe5, 0x59, 0x83, 0xce, 0x35, 0xb7, 0x71, 0x44, 0xc5,
+	0x15, 0xae, 0xe6, 0xc7, 0xfd, 0xb6, 0xe5, 0x17, 0xa1, 0x18, 0xdc, 0x9c, 0xe8, 0x23, 0x33, 0xf4,
+	0x39, 0x71, 0xf9, 0x1e, 0xa0, 0xf1, 0x70, 0x3c, 0x24, 0xdf, 0x22, 0x84, 0xe5, 0x5b, 0x7e, 0x27,
+	0xc0, 0xf5, 0x09, 0xa1, 0x37, 0x7a, 0x73, 0xe4, 0x3f, 0xbf, 0x32, 0x4f, 0xe0, 0xbe, 0xc6, 0x68,
+	0xc1, 0x3f, 0x2d, 0xdf, 0x81, 0xbc, 0x9f, 0x3e, 0xdb, 0x22, 0xbf, 0x89, 0xc3, 0x95, 0xd0, 0x28,
+	0xde, 0x77, 0x15, 0x0a, 0xdf, 0xf1, 0x2a, 0x0c, 0xda, 0x59, 0x7c, 0x4e, 0x3b, 0x3b, 0x0a, 0xb3,
+	0xb3, 0xc4, 0x5c, 0x31, 0xea, 0x5c, 0xd6, 0x96, 0xfc, 0x6e, 0xd6, 0x16, 0x38, 0x70, 0xa9, 0x60,
+	0x10, 0xfc, 0x36, 0xc0, 0x30, 0x5b, 0x46, 0x2e, 0x24, 0xcb, 0xe8, 0xf7, 0x5a, 0xd4, 0x02, 0x52,
+	0x0a, 0xeb, 0x90, 0x32, 0x3d, 0xb1, 0x24, 0x77, 0x9f, 0xc6, 0x9d, 0x2a, 0xb1, 0x04, 0x5f, 0xb6,
+	0x8d, 0x71, 0xcb, 0x3a, 0xa0, 0xf1, 0x64, 0x7b, 0xc4, 0x10, 0xaf, 0x05, 0x87, 0x78, 0x3c, 0x32,
+	0x6d, 0x1f, 0x3e, 0xd4, 0x87, 0x90, 0xa2, 0x7f, 0x9e, 0x04, 0x5f, 0xb4, 0xc2, 0xc3, 0x41, 0x14,
+	0x69, 0xa3, 0x9f, 0x02, 0x68, 0x8e, 0x63, 0xe9, 0xa7, 0xfd, 0xe1, 0x00, 0x2b, 0xe1, 0x96, 0xb3,
+	0xe9, 0xf2, 0x6d, 0xdd, 0xe0, 0x26, 0xb4, 0x34, 0x14, 0xf5, 0x99, 0x91, 0x4f, 0xa1, 0xbc, 0x0f,
+	0xc5, 0xa0, 0xac, 0x1b, 0xf6, 0xb3, 0x39, 0x04, 0xc3, 0x7e, 0x86, 0xe2, 0x58, 0x67, 0x08, 0x1a,
+	0x12, 0xac, 0x8c, 0x45, 0x3b, 0xf2, 0xbf, 0x04, 0xc8, 0xfb, 0x0d, 0xef, 0x7b, 0x0e, 0x45, 0xa7,
+	0xc4, 0xef, 0xd7, 0xc6, 0x22, 0xd1, 0xcc, 0xb9, 0x66, 0x1f, 0xff, 0x90, 0x81, 0xe8, 0x47, 0x02,
+	0x88, 0xde, 0xe2, 0x83, 0x15, 0xad, 0x40, 0x09, 0x90, 0xed, 0x5d, 0xdc, 0x5f, 0x86, 0x62, 0x05,
+	0xbf, 0x84, 0x57, 0xf0, 0xbb, 0xe7, 0xc5, 0x4a, 0x51, 0xf9, 0x41, 0xff, 0x4e, 0x73, 0x9b, 0x72,
+	0x43, 0xc3, 0x5f, 0xf3, 0x79, 0x90, 0x20, 0x01, 0xfd, 0x0f, 0xa4, 0xb5, 0xa6, 0x97, 0x15, 0x2d,
+	0x86, 0xa4, 0x0b, 0x5d, 0xd6, 0xb5, 0xc6, 0x60, 0x93, 0x72, 0x2a, 0x5c, 0x82, 0xcf, 0x2a, 0xee,
+	0xce, 0x4a, 0x7e, 0x1d, 0x44, 0x97, 0x27, 0xe8, 0x11, 0x8b, 0x00, 0xc7, 0xfb, 0x0f, 0x0f, 0xb6,
+	0x77, 0xee, 0xef, 0xd4, 0xb6, 0x79, 0xb4, 0xb4, 0xbd, 0x5d, 0xdb, 0x96, 0xe2, 0x84, 0x4f, 0xa9,
+	0x3d, 0x3c, 0x38, 0xa9, 0x6d, 0x4b, 0x09, 0xf9, 0x1e, 0x64, 0x3d, 0xaf, 0x42, 0x72, 0x04, 0x6e,
+	0x86, 0x57, 0xe0, 0x67, 0x9b, 0x75, 0x69, 0x99, 0xd4, 0xf8, 0x80, 0x57, 0xdf, 0x12, 0x0a, 0xeb,
+	0xc8, 0x2d, 0x28, 0x8d, 0xb8, 0x24, 0x74, 0x0f, 0x32, 0x66, 0xff, 0x54, 0x75, 0x8d, 0x76, 0x24,
+	0x0f, 0xee, 0xa2, 0xcf, 0xfe, 0x69, 0x47, 0x6f, 0x3e, 0xc0, 0x97, 0xee, 0x36, 0x99, 0xfd, 0xd3,
+	0x07, 0xcc, 0xb6, 0xd9, 0x28, 0x71, 0xff, 0x28, 0x17, 0x20, 0xba, 0x47, 0x15, 0xfd, 0x2f, 0x64,
+	0x3d, 0x6f, 0xe7, 0x55, 0xcf, 0x23, 0xdd, 0x24, 0x57, 0x3f, 0x14, 0x21, 0xa9, 0x0c, 0x5b, 0x3f,
+	0xef, 0xb9, 0xc9, 0x7f, 0x96, 0xff, 0x89, 0xd3, 0x33, 0x53, 0x62, 0x1f, 0xf6, 0xdc, 0x14, 0x05,
+	0xb9, 0xe4, 0xa4, 0x51, 0x5f, 0xf1, 0x43, 0x4e, 0x20, 0xe4, 0x32, 0x4e, 0x84, 0x5d, 0xc6, 0x3f,
+	0x8f, 0x43, 0xce, 0x57, 0x5b, 0x40, 0xff, 0xed, 0x73, 0x5c, 0xc5, 0x90, 0x5b, 0xc4, 0xc7, 0x3b,
+	0x2c, 0x4f, 0x07, 0x17, 0x16, 0x9f, 0x7f, 0x61, 0x51, 0x15, 0x1c, 0xb7, 0x54, 0x91, 0x9c, 0xbb,
+	0x54, 0xf1, 0x1c, 0x20, 0xc7, 0x70, 0xb4, 0x0e, 0xc9, 0x05, 0xea, 0xbd, 0x73, 0x95, 0x99, 0x06,
+	0x73, 0x33, 0x12, 0xfd, 0x72, 0x42, 0x3f, 0x1c, 0x52, 0x2b, 0xf9, 0x99, 0x00, 0xa2, 0x87, 0xe8,
+	0xe6, 0x2d, 0x5e, 0x5f, 0x85, 0x34, 0x07, 0x2d, 0xac, 0x7a, 0xcd, 0x7b, 0xa1, 0x35, 0x99, 0x0a,
+	0x88, 0x5d, 0xec, 0x68, 0xd4, 0x67, 0xb2, 0x1b, 0xd0, 0xeb, 0xdf, 0x7e, 0x05, 0x72, 0xbe, 0xc2,
+	0x3f, 0x71, 0xa3, 0xfb, 0xb5, 0xb7, 0xa4, 0x58, 0x25, 0xf3, 0xf1, 0x67, 0xab, 0x89, 0x7d, 0xfc,
+	0x01, 0x39, 0x61, 0x4a, 0xad, 0x5a, 0xaf, 0x55, 0x1f, 0x48, 0x42, 0x25, 0xf7, 0xf1, 0x67, 0xab,
+	0x19, 0x05, 0xd3, 0x74, 0xfc, 0xed, 0x07, 0x50, 0x1a, 0xf9, 0x31, 0xc1, 0x03, 0x8d, 0xa0, 0xb8,
+	0x7d, 0x7c, 0xb8, 0xb7, 0x53, 0xdd, 0x6c, 0xd4, 0xd4, 0x93, 0x83, 0x46, 0x4d, 0x12, 0xd0, 0x63,
+	0xb0, 0xb8, 0xb7, 0xf3, 0x46, 0xbd, 0xa1, 0x56, 0xf7, 0x76, 0x6a, 0xfb, 0x0d, 0x75, 0xb3, 0xd1,
+	0xd8, 0xac, 0x3e, 0x90, 0xe2, 0x1b, 0xbf, 0xcf, 0x41, 0x69, 0x73, 0xab, 0xba, 0x43, 0x60, 0x9b,
+	0xde, 0xd4, 0xa8, 0x7b, 0xa8, 0x42, 0x92, 0x26, 0x16, 0x27, 0x3e, 0xff, 0xab, 0x4c, 0xae, 0xb1,
+	0xa0, 0xfb, 0x90, 0xa2, 0x39, 0x47, 0x34, 0xf9, 0x3d, 0x60, 0x65, 0x4a, 0xd1, 0x85, 0x4c, 0x86,
+	0x1e, 0xa7, 0x89, 0x0f, 0x04, 0x2b, 0x93, 0x6b, 0x30, 0x68, 0x0f, 0x32, 0x6e, 0xca, 0x69, 0xda,
+	0xab, 0xbd, 0xca, 0xd4, 0xc2, 0x08, 0x59, 0x1a, 0x4b, 0xdd, 0x4d, 0x7e, 0x3b, 0x58, 0x99, 0x52,
+	0x9d, 0x41, 0x3b, 0x90, 0xe6, 0x49, 0x8f, 0x29, 0xcf, 0x01, 0x2b, 0xd3, 0xea, 0x2d, 0x48, 0x81,
+	0xec, 0x30, 0x29, 0x3a, 0xfd, 0x45, 0x64, 0x65, 0x86, 0xc2, 0x13, 0x7a, 0x07, 0x0a, 0xc1, 0x84,
+	0xca, 0x6c, 0x4f, 0x0e, 0x2b, 0x33, 0x56, 0x76, 0x88, 0xfe, 0x60, 0x76, 0x65, 0xb6, 0x27, 0x88,
+	0x95, 0x19, 0x0b, 0x3d, 0xe8, 0x3d, 0x58, 0x18, 0xcf, 0x7e, 0xcc, 0xfe, 0x22, 0xb1, 0x32, 0x47,
+	0xe9, 0x07, 0x75, 0x01, 0x85, 0x64, 0x4d, 0xe6, 0x78, 0xa0, 0x58, 0x99, 0xa7, 0x12, 0x84, 0x5a,
+	0x50, 0x1a, 0xcd, 0x44, 0xcc, 0xfa, 0x60, 0xb1, 0x32, 0x73, 0x55, 0x88, 0x8d, 0x12, 0x84, 0xe5,
+	0xb3, 0x3e, 0x60, 0xac, 0xcc, 0x5c, 0x24, 0x42, 0xc7, 0x00, 0x3e, 0x58, 0x39, 0xc3, 0x83, 0xc6,
+	0xca, 0x2c, 0xe5, 0x22, 0x64, 0xc2, 0x62, 0x18, 0xde, 0x9c, 0xe7, 0x7d, 0x63, 0x65, 0xae, 0x2a,
+	0x12, 0xb1, 0xe7, 0x20, 0x72, 0x9c, 0xed, 0xbd, 0x63, 0x65, 0xc6, 0x72, 0xd2, 0x56, 0xed, 0xf3,
+	0xaf, 0x96, 0x85, 0x2f, 0xbe, 0x5a, 0x16, 0xfe, 0xf1, 0xd5, 0xb2, 0xf0, 0xc9, 0xd7, 0xcb, 0xb1,
+	0x2f, 0xbe, 0x5e, 0x8e, 0xfd, 0xed, 0xeb, 0xe5, 0xd8, 0xff, 0x3d, 0x7b, 0xae, 0x3b, 0xed, 0xfe,
+	0xe9, 0x5a, 0xd3, 0xe8, 0xae, 0xfb, 0x9f, 0x88, 0x87, 0x3d, 0x4c, 0x3f, 0x4d, 0xd3, 0x0b, 0xf5,
+	0xce, 0xbf, 0x07, 0x00, 0xfe, 0x62, 0xd6, 0x73, 0xb8, 0x2e, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -6511,6 +6534,26 @@ func (m *ResponseCheckTx) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.ProposalOnly {
+		i--
+		if m.ProposalOnly {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x68
+	}
+	if m.NoGossip {
+		i--
+		if m.NoGossip {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x60
+	}
 	if m.Priority != 0 {
 		i = encodeVarintTypes(dAtA, i, uint64(m.Priority))
 		i--
@@ -8656,6 +8699,12 @@ func (m *ResponseCheckTx) Size() (n int) {
 	if m.Priority != 0 {
 		n += 1 + sovTypes(uint64(m.Priority))
 	}
+	if m.NoGossip {
+		n += 2
+	}
+	if m.ProposalOnly {
+		n += 2
+	}
 	return n
 }
 
@@ -13881,6 +13930,46 @@ func (m *ResponseCheckTx) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NoGossip", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.NoGossip = bool(v != 0)
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProposalOnly", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ProposalOnly = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
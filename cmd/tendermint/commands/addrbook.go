@@ -0,0 +1,263 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/internal/p2p"
+)
+
+// addrBookEntry is an address book entry, as exported and imported.
+type addrBookEntry struct {
+	Address       string    `json:"address"`
+	Score         int64     `json:"score"`
+	LastConnected time.Time `json:"last_connected"`
+}
+
+var addrBookCSVHeader = []string{"address", "score", "last_connected"}
+
+// MakeAddrBookCommand constructs a command to export and import the address
+// book, i.e. the peer addresses stored by the node.
+func MakeAddrBookCommand(conf *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "addrbook",
+		Short: "Export or import the peer address book",
+		Long: `
+The addrbook commands export the peer addresses stored by the node, and import
+them into another node, e.g. to seed a fresh node with a curated peer list or
+to share vetted peers between nodes. The node must be stopped while they run.
+`,
+	}
+	cmd.AddCommand(
+		makeAddrBookExportCommand(conf),
+		makeAddrBookImportCommand(conf),
+	)
+	return cmd
+}
+
+func makeAddrBookExportCommand(conf *config.Config) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "export [file]",
+		Short: "Export the address book as JSON or CSV",
+		Long: `
+Export writes the stored peer addresses to the given file, or to stdout if no
+file is given, best peers first.
+`,
+		Example: `
+	tendermint addrbook export peers.json
+	tendermint addrbook export --format csv > peers.csv
+	`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			peerManager, closeDB, err := loadAddrBook(conf)
+			if err != nil {
+				return err
+			}
+			defer closeDB()
+
+			var w io.Writer = cmd.OutOrStdout()
+			if len(args) == 1 {
+				f, err := os.Create(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			return exportAddrBook(w, peerManager, format)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "json", "output format: json or csv")
+	return cmd
+}
+
+func makeAddrBookImportCommand(conf *config.Config) *cobra.Command {
+	var (
+		format  string
+		replace bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import addresses into the address book from JSON or CSV",
+		Long: `
+Import adds the peer addresses in the given file, as written by export, to the
+address book. By default, the addresses are merged into the existing address
+book, keeping what the node has learned about the peers it already knows.
+With --replace, addresses that aren't in the file are removed.
+`,
+		Example: `
+	tendermint addrbook import peers.json
+	tendermint addrbook import --format csv --replace peers.csv
+	`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			peerManager, closeDB, err := loadAddrBook(conf)
+			if err != nil {
+				return err
+			}
+			defer closeDB()
+
+			added, removed, err := importAddrBook(f, peerManager, format, replace)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "added %d addresses, removed %d addresses\n", added, removed)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "json", "input format: json or csv")
+	cmd.Flags().BoolVar(&replace, "replace", false, "remove addresses that aren't imported")
+	return cmd
+}
+
+// loadAddrBook opens the node's peer store. The returned function closes it.
+func loadAddrBook(conf *config.Config) (*p2p.PeerManager, func(), error) {
+	nodeID, err := conf.LoadNodeKeyID()
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := dbm.NewDB("peerstore", dbm.BackendType(conf.DBBackend), conf.DBDir())
+	if err != nil {
+		return nil, nil, err
+	}
+	peerManager, err := p2p.NewPeerManager(nodeID, db, p2p.PeerManagerOptions{})
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return peerManager, func() { _ = db.Close() }, nil
+}
+
+// exportAddrBook writes the addresses known to the peer manager to w.
+func exportAddrBook(w io.Writer, peerManager *p2p.PeerManager, format string) error {
+	known := peerManager.KnownAddresses()
+	entries := make([]addrBookEntry, 0, len(known))
+	for _, address := range known {
+		entries = append(entries, addrBookEntry{
+			Address:       address.Address.String(),
+			Score:         int64(address.Score),
+			LastConnected: address.LastConnected,
+		})
+	}
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(addrBookCSVHeader); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			lastConnected := ""
+			if !entry.LastConnected.IsZero() {
+				lastConnected = entry.LastConnected.Format(time.RFC3339)
+			}
+			record := []string{entry.Address, strconv.FormatInt(entry.Score, 10), lastConnected}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unsupported format %q, must be json or csv", format)
+	}
+}
+
+// importAddrBook adds the addresses read from r to the peer manager, removing
+// any other addresses if replace is set. It returns the number of addresses
+// added and removed.
+func importAddrBook(
+	r io.Reader,
+	peerManager *p2p.PeerManager,
+	format string,
+	replace bool,
+) (added, removed int, err error) {
+	var entries []addrBookEntry
+	switch format {
+	case "json":
+		if err := json.NewDecoder(r).Decode(&entries); err != nil {
+			return 0, 0, fmt.Errorf("invalid JSON address book: %w", err)
+		}
+	case "csv":
+		records, err := csv.NewReader(r).ReadAll()
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid CSV address book: %w", err)
+		}
+		for i, record := range records {
+			if i == 0 && len(record) > 0 && record[0] == addrBookCSVHeader[0] {
+				continue
+			}
+			if len(record) == 0 || record[0] == "" {
+				return 0, 0, fmt.Errorf("missing address on CSV line %d", i+1)
+			}
+			entries = append(entries, addrBookEntry{Address: record[0]})
+		}
+	default:
+		return 0, 0, fmt.Errorf("unsupported format %q, must be json or csv", format)
+	}
+
+	// Parse all addresses before touching the address book, so that an
+	// invalid file doesn't leave it half imported.
+	addresses := make([]p2p.NodeAddress, 0, len(entries))
+	imported := make(map[p2p.NodeAddress]bool, len(entries))
+	for _, entry := range entries {
+		address, err := p2p.ParseNodeAddress(entry.Address)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid address %q: %w", entry.Address, err)
+		}
+		if address.NodeID == peerManager.SelfID() {
+			return 0, 0, errors.New("can't import the node's own address")
+		}
+		addresses = append(addresses, address)
+		imported[address] = true
+	}
+
+	if replace {
+		for _, known := range peerManager.KnownAddresses() {
+			if imported[known.Address] {
+				continue
+			}
+			ok, err := peerManager.RemoveAddress(known.Address)
+			if err != nil {
+				return added, removed, err
+			}
+			if ok {
+				removed++
+			}
+		}
+	}
+
+	for _, address := range addresses {
+		ok, err := peerManager.Add(address)
+		if err != nil {
+			return added, removed, fmt.Errorf("failed to add address %v: %w", address, err)
+		}
+		if ok {
+			added++
+		}
+	}
+	return added, removed, nil
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestAddrBookExportImport(t *testing.T) {
+	newPeerManager := func(t *testing.T, self string) *p2p.PeerManager {
+		peerManager, err := p2p.NewPeerManager(types.NodeID(strings.Repeat(self, 40)), dbm.NewMemDB(), p2p.PeerManagerOptions{})
+		require.NoError(t, err)
+		return peerManager
+	}
+	address := func(id string) p2p.NodeAddress {
+		return p2p.NodeAddress{
+			Protocol: p2p.MConnProtocol,
+			NodeID:   types.NodeID(strings.Repeat(id, 40)),
+			Hostname: "127.0.0.1",
+			Port:     26656,
+		}
+	}
+	addresses := func(peerManager *p2p.PeerManager) []p2p.NodeAddress {
+		known := []p2p.NodeAddress{}
+		for _, address := range peerManager.KnownAddresses() {
+			known = append(known, address.Address)
+		}
+		return known
+	}
+
+	for _, format := range []string{"json", "csv"} {
+		format := format
+		t.Run(format, func(t *testing.T) {
+			source := newPeerManager(t, "0")
+			for _, id := range []string{"a", "b"} {
+				added, err := source.Add(address(id))
+				require.NoError(t, err)
+				require.True(t, added)
+			}
+			buf := &bytes.Buffer{}
+			require.NoError(t, exportAddrBook(buf, source, format))
+			exported := buf.Bytes()
+
+			// Merging keeps existing addresses.
+			target := newPeerManager(t, "1")
+			_, err := target.Add(address("c"))
+			require.NoError(t, err)
+			added, removed, err := importAddrBook(bytes.NewReader(exported), target, format, false)
+			require.NoError(t, err)
+			require.Equal(t, 2, added)
+			require.Zero(t, removed)
+			require.ElementsMatch(t, []p2p.NodeAddress{address("a"), address("b"), address("c")}, addresses(target))
+
+			// Importing again adds nothing, and replacing removes the rest.
+			added, removed, err = importAddrBook(bytes.NewReader(exported), target, format, true)
+			require.NoError(t, err)
+			require.Zero(t, added)
+			require.Equal(t, 1, removed)
+			require.ElementsMatch(t, []p2p.NodeAddress{address("a"), address("b")}, addresses(target))
+
+			// The node's own address is rejected.
+			self := newPeerManager(t, "a")
+			_, _, err = importAddrBook(bytes.NewReader(exported), self, format, false)
+			require.Error(t, err)
+			require.Empty(t, addresses(self))
+		})
+	}
+
+	_, _, err := importAddrBook(strings.NewReader(`[{"address": "foo"}]`), newPeerManager(t, "0"), "json", false)
+	require.Error(t, err)
+	require.Error(t, exportAddrBook(&bytes.Buffer{}, newPeerManager(t, "0"), "xml"))
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/internal/consensus"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// MakeExportBlocksCommand constructs a command to export blocks to a block
+// archive.
+func MakeExportBlocksCommand(conf *config.Config) *cobra.Command {
+	var fromHeight, toHeight int64
+
+	cmd := &cobra.Command{
+		Use:   "export-blocks <dir>",
+		Short: "Export blocks and their commits to a block archive",
+		Long: `
+Export-blocks writes the blocks of the block store, with the commits for them,
+to a block archive directory, one file per block. The archive can be copied to
+e.g. object storage and imported with import-blocks to restore a node. By
+default, all the blocks of the block store are exported. The node must be
+stopped.
+`,
+		Example: `
+	tendermint export-blocks /backups/blocks
+	tendermint export-blocks /backups/blocks --from-height 1000 --to-height 2000
+	`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			blockStore, _, err := loadStateAndBlockStore(conf)
+			if err != nil {
+				return err
+			}
+			if fromHeight == 0 {
+				fromHeight = blockStore.Base()
+			}
+			if toHeight == 0 {
+				toHeight = blockStore.Height()
+			}
+			if err := blockStore.ExportBlocks(args[0], fromHeight, toHeight); err != nil {
+				return fmt.Errorf("failed to export blocks: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "exported blocks %d-%d to %s\n", fromHeight, toHeight, args[0])
+			return nil
+		},
+	}
+	cmd.Flags().Int64Var(&fromHeight, "from-height", 0, "first height to export; 0 for the base of the block store")
+	cmd.Flags().Int64Var(&toHeight, "to-height", 0, "last height to export; 0 for the height of the block store")
+	return cmd
+}
+
+// MakeImportBlocksCommand constructs a command to import blocks from a block
+// archive.
+func MakeImportBlocksCommand(conf *config.Config, logger log.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import-blocks <dir>",
+		Short: "Import blocks from a block archive and replay them through the app",
+		Long: `
+Import-blocks restores a node from a block archive written by export-blocks,
+without fetching the blocks from the network. The blocks following the last
+block of the node are verified against their commits and the validator set of
+their height, saved, and replayed through the application, until a block is
+missing from the archive. The application configured by proxy-app must be
+reachable. The node must be stopped.
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			height, err := consensus.ImportBlocks(cmd.Context(), logger, conf.BaseConfig, args[0])
+			if err != nil {
+				return fmt.Errorf("failed to import blocks: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "imported blocks up to height %d\n", height)
+			return nil
+		},
+	}
+}
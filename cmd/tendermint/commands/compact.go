@@ -14,15 +14,18 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 )
 
+// MakeCompactDBCommand constructs a command to compact the state and block
+// stores.
 func MakeCompactDBCommand(cfg *config.Config, logger log.Logger) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "experimental-compact-goleveldb",
-		Short: "force compacts the tendermint storage engine (only GoLevelDB supported)",
+		Use:     "compact-db",
+		Aliases: []string{"experimental-compact-goleveldb"},
+		Short:   "force compacts the tendermint storage engine (only GoLevelDB supported)",
 		Long: `
-This is a temporary utility command that performs a force compaction on the state 
-and blockstores to reduce disk space for a pruning node. This should only be run 
-once the node has stopped. This command will likely be omitted in the future after
-the planned refactor to the storage engine.
+compact-db performs a force compaction on the state and blockstores to reclaim
+the disk space of the data pruned by a pruning node. This should only be run
+once the node has stopped. To compact the stores while the node runs, set
+compaction-interval in the config instead.
 
 Currently, only GoLevelDB is supported.
 	`,
@@ -31,7 +34,7 @@ Currently, only GoLevelDB is supported.
 				return errors.New("compaction is currently only supported with goleveldb")
 			}
 
-			compactGoLevelDBs(cfg.RootDir, logger)
+			compactGoLevelDBs(cfg.DBDir(), logger)
 			return nil
 		},
 	}
@@ -39,7 +42,7 @@ Currently, only GoLevelDB is supported.
 	return cmd
 }
 
-func compactGoLevelDBs(rootDir string, logger log.Logger) {
+func compactGoLevelDBs(dbDir string, logger log.Logger) {
 	dbNames := []string{"state", "blockstore"}
 	o := &opt.Options{
 		DisableSeeksCompaction: true,
@@ -51,7 +54,7 @@ func compactGoLevelDBs(rootDir string, logger log.Logger) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			dbPath := filepath.Join(rootDir, "data", dbName+".db")
+			dbPath := filepath.Join(dbDir, dbName+".db")
 			store, err := leveldb.OpenFile(dbPath, o)
 			if err != nil {
 				logger.Error("failed to initialize tendermint db", "path", dbPath, "err", err)
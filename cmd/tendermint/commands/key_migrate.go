@@ -3,13 +3,17 @@ package commands
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 
 	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/libs/log"
+	tmos "github.com/tendermint/tendermint/libs/os"
+	"github.com/tendermint/tendermint/scripts/abmigrate"
 	"github.com/tendermint/tendermint/scripts/keymigrate"
 	"github.com/tendermint/tendermint/scripts/scmigrate"
+	"github.com/tendermint/tendermint/types"
 )
 
 func MakeKeyMigrateCommand(conf *config.Config, logger log.Logger) *cobra.Command {
@@ -69,7 +73,45 @@ func RunDatabaseMigration(ctx context.Context, logger log.Logger, conf *config.C
 		}
 	}
 
+	if err := migrateAddrBook(ctx, logger, conf); err != nil {
+		return fmt.Errorf("running address book migration: %w", err)
+	}
+
 	logger.Info("completed database migration successfully")
 
 	return nil
 }
+
+// migrateAddrBook imports the addresses of the JSON address book used by 0.34,
+// if there is one, into the peer store.
+func migrateAddrBook(ctx context.Context, logger log.Logger, conf *config.Config) error {
+	addrBookFile := filepath.Join(conf.RootDir, "config", "addrbook.json")
+	if !tmos.FileExists(addrBookFile) {
+		return nil
+	}
+
+	nodeKey, err := types.LoadNodeKey(conf.NodeKeyFile())
+	if err != nil {
+		return fmt.Errorf("loading node key: %w", err)
+	}
+
+	db, err := config.DefaultDBProvider(&config.DBContext{
+		ID:     "peerstore",
+		Config: conf,
+	})
+	if err != nil {
+		return fmt.Errorf("constructing database handle: %w", err)
+	}
+	defer db.Close()
+
+	added, err := abmigrate.Migrate(ctx, nodeKey.ID, addrBookFile, db)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("migrated address book into the peer store",
+		"file", addrBookFile,
+		"added", added,
+	)
+	return nil
+}
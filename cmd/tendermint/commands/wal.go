@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/internal/consensus"
+)
+
+// MakeWALCommand constructs a command family to inspect and repair the
+// consensus WAL.
+func MakeWALCommand(conf *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wal",
+		Short: "Inspect and repair the consensus WAL",
+		Long: `
+The wal commands inspect the consensus write-ahead log, show which of its
+records consensus would replay on startup, and repair a WAL whose tail was
+corrupted, e.g. by a crash. The node must be stopped while they run.
+`,
+	}
+	cmd.AddCommand(
+		makeWALInspectCommand(conf),
+		makeWALTruncateCommand(conf),
+		makeWALReplayDryRunCommand(conf),
+	)
+	return cmd
+}
+
+func makeWALInspectCommand(conf *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "inspect",
+		Short: "Summarize the records of the WAL",
+		Long: `
+Inspect decodes every record of the WAL, including rotated files, and prints
+the number of records, the range of heights and times they cover, and the
+first corrupted record, if any.
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			summary, err := consensus.InspectWAL(cmd.Context(), conf.Consensus.WalFile())
+			if err != nil {
+				return err
+			}
+			printWALSummary(cmd.OutOrStdout(), conf.Consensus.WalFile(), summary)
+			return nil
+		},
+	}
+}
+
+func makeWALTruncateCommand(conf *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "truncate",
+		Short: "Truncate the WAL after its last good record",
+		Long: `
+Truncate removes the corrupted or partially written tail of the WAL head file,
+after its last record that can be decoded. The original file is first copied
+to <wal>.CORRUPTED. Nothing is done if the WAL isn't corrupted.
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			removed, err := consensus.TruncateWALFile(conf.Consensus.WalFile())
+			if err != nil {
+				return err
+			}
+			if removed == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "the WAL is not corrupted, nothing to do")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "removed %d bytes, backup written to %s.CORRUPTED\n",
+				removed, conf.Consensus.WalFile())
+			return nil
+		},
+	}
+}
+
+func makeWALReplayDryRunCommand(conf *config.Config) *cobra.Command {
+	var height int64
+
+	cmd := &cobra.Command{
+		Use:   "replay-dry-run",
+		Short: "Print the WAL records consensus would replay",
+		Long: `
+Replay-dry-run prints, as JSON lines, the WAL records consensus replays when
+starting at the height following --height, without applying them. By default,
+the records following the last completed height are printed.
+`,
+		Example: `
+	tendermint wal replay-dry-run
+	tendermint wal replay-dry-run --height 1200
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			msgs, found, err := consensus.WALReplayMessages(cmd.Context(), conf.Consensus.WalFile(), height)
+			if err != nil {
+				return err
+			}
+			if !found && height < 0 {
+				return errors.New("no completed height found in the WAL")
+			}
+			if !found {
+				return fmt.Errorf("no end of height %d found in the WAL", height)
+			}
+
+			w := cmd.OutOrStdout()
+			for _, msg := range msgs {
+				bz, err := json.Marshal(msg)
+				if err != nil {
+					return fmt.Errorf("failed to marshal WAL record: %w", err)
+				}
+				fmt.Fprintln(w, string(bz))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Int64Var(&height, "height", -1, "last completed height; -1 for the last one in the WAL")
+	return cmd
+}
+
+// printWALSummary writes a human-readable WAL summary to w.
+func printWALSummary(w io.Writer, walFile string, summary *consensus.WALSummary) {
+	fmt.Fprintf(w, "file:            %s\n", walFile)
+	fmt.Fprintf(w, "records:         %d\n", summary.Records)
+	if summary.Records > 0 {
+		fmt.Fprintf(w, "first record:    %s\n", summary.FirstTime.Format(time.RFC3339Nano))
+		fmt.Fprintf(w, "last record:     %s\n", summary.LastTime.Format(time.RFC3339Nano))
+	}
+	if summary.LastEndHeight >= 0 {
+		fmt.Fprintf(w, "end heights:     %d-%d\n", summary.FirstEndHeight, summary.LastEndHeight)
+	}
+	if summary.Err != nil {
+		fmt.Fprintf(w, "corrupted after: %d records: %v\n", summary.Records, summary.Err)
+	} else {
+		fmt.Fprintln(w, "corrupted:       no")
+	}
+}
@@ -45,6 +45,10 @@ func main() {
 		debug.GetDebugCommand(logger),
 		commands.NewCompletionCmd(rcmd, true),
 		commands.MakeCompactDBCommand(conf, logger),
+		commands.MakeAddrBookCommand(conf),
+		commands.MakeWALCommand(conf),
+		commands.MakeExportBlocksCommand(conf),
+		commands.MakeImportBlocksCommand(conf, logger),
 	)
 
 	// NOTE:
@@ -56,6 +56,19 @@ func TestBaseConfigValidateBasic(t *testing.T) {
 	// tamper with log format
 	cfg.LogFormat = "invalid"
 	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestBaseConfig()
+	cfg.MinRetainBlocks = -1
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestBaseConfig()
+	cfg.CompactionInterval = -1
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestBaseConfig()
+	cfg.CompactionInterval = 1000
+	cfg.DBBackend = "memdb"
+	assert.Error(t, cfg.ValidateBasic())
 }
 
 func TestRPCConfigValidateBasic(t *testing.T) {
@@ -86,7 +99,11 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 		"Size",
 		"MaxTxsBytes",
 		"CacheSize",
+		"RejectedCacheSize",
 		"MaxTxBytes",
+		"RecheckTimeout",
+		"RebroadcastInterval",
+		"MaxRebroadcasts",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -94,11 +111,43 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 		assert.Error(t, cfg.ValidateBasic())
 		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
 	}
+
+	cfg.FullnessThresholds = []int{80, 95}
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.FullnessThresholds = []int{95, 80}
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.FullnessThresholds = []int{0}
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.FullnessThresholds = []int{101}
+	assert.Error(t, cfg.ValidateBasic())
 }
 
 func TestStateSyncConfigValidateBasic(t *testing.T) {
 	cfg := TestStateSyncConfig()
 	require.NoError(t, cfg.ValidateBasic())
+
+	cfg.ServeRecentSnapshots = 11
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestStateSyncConfig()
+	cfg.ChunkSendRate = -1
+	assert.Error(t, cfg.ValidateBasic())
+}
+
+func TestBlockSyncConfigValidateBasic(t *testing.T) {
+	cfg := TestBlockSyncConfig()
+	require.NoError(t, cfg.ValidateBasic())
+
+	cfg.SwitchHeightDelta = 0
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestBlockSyncConfig()
+	cfg.SwitchMinPeers = 0
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestBlockSyncConfig()
+	cfg.SwitchStallTimeout = 0
+	assert.Error(t, cfg.ValidateBasic())
 }
 
 func TestConsensusConfig_ValidateBasic(t *testing.T) {
@@ -120,7 +169,17 @@ func TestConsensusConfig_ValidateBasic(t *testing.T) {
 		"PeerGossipSleepDuration negative":           {func(c *ConsensusConfig) { c.PeerGossipSleepDuration = -1 }, true},
 		"PeerQueryMaj23SleepDuration":                {func(c *ConsensusConfig) { c.PeerQueryMaj23SleepDuration = time.Second }, false},
 		"PeerQueryMaj23SleepDuration negative":       {func(c *ConsensusConfig) { c.PeerQueryMaj23SleepDuration = -1 }, true},
+		"PeerGossipBatchWindow":                      {func(c *ConsensusConfig) { c.PeerGossipBatchWindow = 5 * time.Millisecond }, false},
+		"PeerGossipBatchWindow negative":             {func(c *ConsensusConfig) { c.PeerGossipBatchWindow = -1 }, true},
 		"DoubleSignCheckHeight negative":             {func(c *ConsensusConfig) { c.DoubleSignCheckHeight = -1 }, true},
+		"TargetBlockInterval":                        {func(c *ConsensusConfig) { c.TargetBlockInterval = time.Second }, false},
+		"TargetBlockInterval negative":               {func(c *ConsensusConfig) { c.TargetBlockInterval = -1 }, true},
+		"TimeoutJitter":                              {func(c *ConsensusConfig) { c.TimeoutJitter = 100 * time.Millisecond }, false},
+		"TimeoutJitter negative":                     {func(c *ConsensusConfig) { c.TimeoutJitter = -1 }, true},
+		"WalSegmentSize negative":                    {func(c *ConsensusConfig) { c.WalSegmentSize = -1 }, true},
+		"WalRetentionSize negative":                  {func(c *ConsensusConfig) { c.WalRetentionSize = -1 }, true},
+		"WalRetentionSize unlimited":                 {func(c *ConsensusConfig) { c.WalRetentionSize = 0 }, false},
+		"WalRetentionSize too small":                 {func(c *ConsensusConfig) { c.WalRetentionSize = c.WalSegmentSize - 1 }, true},
 	}
 	for desc, tc := range testcases {
 		tc := tc // appease linter
@@ -156,6 +215,14 @@ func TestP2PConfigValidateBasic(t *testing.T) {
 		"MaxPacketMsgPayloadSize",
 		"SendRate",
 		"RecvRate",
+		"MaxMissedPongs",
+		"ResolveInterval",
+		"RemoteBanBackoff",
+		"PexAddressBudget",
+		"PexColdStartPeers",
+		"PexRequestRateTarget",
+		"PexMinRequestInterval",
+		"PexStopTimeout",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -163,4 +230,84 @@ func TestP2PConfigValidateBasic(t *testing.T) {
 		assert.Error(t, cfg.ValidateBasic())
 		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
 	}
+
+	cfg.MaxIncomingConnections = cfg.MaxConnections + 1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.MaxIncomingConnections = 0
+
+	cfg.PexCPUTarget = -0.1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.PexCPUTarget = 1.1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.PexCPUTarget = 0.8
+
+	cfg.PingInterval = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.PingInterval = time.Second
+	cfg.PongTimeout = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.PongTimeout = time.Second
+
+	cfg.SubnetPrefixIPv4 = 33
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.SubnetPrefixIPv4 = 24
+	cfg.SubnetPrefixIPv6 = 129
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.SubnetPrefixIPv6 = 48
+
+	cfg.AllowedCIDRs = "10.0.0.0/8, 2001:db8::/32"
+	cfg.DeniedCIDRs = "10.1.0.0/16"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.DeniedCIDRs = "10.1.0.0"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.AllowedCIDRs, cfg.DeniedCIDRs = "", ""
+
+	cfg.Proxy = "socks5://127.0.0.1:1080"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.Proxy = "http://127.0.0.1:8080"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.Proxy = ""
+
+	cfg.WebSocketListenAddress = "0.0.0.0:443"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.WebSocketListenAddress = "0.0.0.0"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.WebSocketListenAddress = ""
+	cfg.WebSocketTLSCertFile = "cert.pem"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.WebSocketTLSKeyFile = "key.pem"
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.Handshake = "noise"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.Handshake = "tls"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.Handshake = ""
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.Handshake = "secret-connection"
+
+	cfg.Compression = "snappy"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.Compression = "zstd"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.Compression = ""
+	assert.Error(t, cfg.ValidateBasic())
+}
+
+func TestPrivValidatorConfigValidateBasic(t *testing.T) {
+	cfg := DefaultPrivValidatorConfig()
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.SignGateURL = "https://127.0.0.1:8443/gate"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.SignGateURL = "tcp://127.0.0.1:8443"
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg.SignGateURL = "https://127.0.0.1:8443/gate"
+	cfg.SignGateTimeout = 0
+	assert.Error(t, cfg.ValidateBasic())
+
+	// the timeout is unused without a sign gate
+	cfg.SignGateURL = ""
+	assert.NoError(t, cfg.ValidateBasic())
 }
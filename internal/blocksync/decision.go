@@ -0,0 +1,81 @@
+package blocksync
+
+import (
+	"time"
+
+	"github.com/tendermint/tendermint/config"
+)
+
+// switchDecision is the outcome of the periodic check of whether block sync
+// should hand over to consensus.
+type switchDecision int
+
+const (
+	// keepSyncing means that we aren't caught up yet.
+	keepSyncing switchDecision = iota
+	// waitForExtensions means that we can't switch to consensus before we
+	// have the vote extensions of the last block.
+	waitForExtensions
+	// switchCaughtUp means that we caught up with our peers.
+	switchCaughtUp
+	// switchNoProgress means that we made no progress for the stall timeout.
+	switchNoProgress
+	// switchForced means that the switch was requested by the operator.
+	switchForced
+)
+
+// syncStatus holds what the decision to switch to consensus is based on.
+type syncStatus struct {
+	// height is the next height to sync.
+	height int64
+	// maxPeerHeight is the highest height reported by our peers.
+	maxPeerHeight int64
+	// numPeers is the number of peers we sync from.
+	numPeers int
+	// sinceLastAdvance is the time since we last synced a block.
+	sinceLastAdvance time.Duration
+	// extensionsRequired is set if vote extensions are enabled at the height
+	// of the last block.
+	extensionsRequired bool
+	// haveExtendedCommit is set if we have the extended commit of the last
+	// block, i.e. we synced a block or had its extended commit on startup.
+	haveExtendedCommit bool
+	// forced is set if the operator requested the switch.
+	forced bool
+}
+
+// caughtUp returns true if we have synced up to the highest height of our
+// peers, as defined by cfg.
+func (s syncStatus) caughtUp(cfg *config.BlockSyncConfig) bool {
+	// NOTE: syncing block H requires block H+1 to verify the LastCommit, so
+	// the delta is at least 1.
+	return s.numPeers >= cfg.SwitchMinPeers && s.height >= s.maxPeerHeight-cfg.SwitchHeightDelta
+}
+
+// decideSwitchToConsensus decides whether block sync should hand over to
+// consensus, according to the policy of cfg. It's a pure function of its
+// arguments, so that the decision is deterministic and can be tested without
+// running the reactor.
+func decideSwitchToConsensus(s syncStatus, cfg *config.BlockSyncConfig) switchDecision {
+	switch {
+	// If vote extensions are enabled we cannot switch to consensus without the
+	// vote extension data for the last block. If we've synced at least one
+	// block, we're guaranteed to have extensions, since block sync requires
+	// the blocks it fetches to have extensions if they were enabled at their
+	// height. Otherwise, we must already have had them on startup.
+	case s.extensionsRequired && !s.haveExtendedCommit:
+		return waitForExtensions
+
+	case s.forced:
+		return switchForced
+
+	case s.caughtUp(cfg):
+		return switchCaughtUp
+
+	case s.sinceLastAdvance > cfg.SwitchStallTimeout:
+		return switchNoProgress
+
+	default:
+		return keepSyncing
+	}
+}
@@ -0,0 +1,83 @@
+package blocksync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tendermint/tendermint/config"
+)
+
+func TestDecideSwitchToConsensus(t *testing.T) {
+	cfg := config.TestBlockSyncConfig()
+
+	testCases := map[string]struct {
+		status   syncStatus
+		modify   func(*config.BlockSyncConfig)
+		expected switchDecision
+	}{
+		"not caught up": {
+			status:   syncStatus{height: 5, maxPeerHeight: 10, numPeers: 1, sinceLastAdvance: time.Second},
+			expected: keepSyncing,
+		},
+		"caught up": {
+			status:   syncStatus{height: 9, maxPeerHeight: 10, numPeers: 1},
+			expected: switchCaughtUp,
+		},
+		"caught up without peers": {
+			status:   syncStatus{height: 9, maxPeerHeight: 10},
+			expected: keepSyncing,
+		},
+		"caught up within height delta": {
+			status:   syncStatus{height: 5, maxPeerHeight: 10, numPeers: 1},
+			modify:   func(c *config.BlockSyncConfig) { c.SwitchHeightDelta = 5 },
+			expected: switchCaughtUp,
+		},
+		"caught up with too few peers": {
+			status:   syncStatus{height: 9, maxPeerHeight: 10, numPeers: 2},
+			modify:   func(c *config.BlockSyncConfig) { c.SwitchMinPeers = 3 },
+			expected: keepSyncing,
+		},
+		"no progress": {
+			status:   syncStatus{height: 5, maxPeerHeight: 10, sinceLastAdvance: cfg.SwitchStallTimeout + time.Second},
+			expected: switchNoProgress,
+		},
+		"no progress within stall timeout": {
+			status:   syncStatus{height: 5, maxPeerHeight: 10, sinceLastAdvance: 2 * time.Minute},
+			modify:   func(c *config.BlockSyncConfig) { c.SwitchStallTimeout = 5 * time.Minute },
+			expected: keepSyncing,
+		},
+		"forced": {
+			status:   syncStatus{height: 5, maxPeerHeight: 10, forced: true},
+			expected: switchForced,
+		},
+		"caught up without extensions": {
+			status:   syncStatus{height: 9, maxPeerHeight: 10, numPeers: 1, extensionsRequired: true},
+			expected: waitForExtensions,
+		},
+		"forced without extensions": {
+			status:   syncStatus{forced: true, extensionsRequired: true},
+			expected: waitForExtensions,
+		},
+		"no progress without extensions": {
+			status:   syncStatus{sinceLastAdvance: cfg.SwitchStallTimeout + time.Second, extensionsRequired: true},
+			expected: waitForExtensions,
+		},
+		"caught up with extensions": {
+			status:   syncStatus{height: 9, maxPeerHeight: 10, numPeers: 1, extensionsRequired: true, haveExtendedCommit: true},
+			expected: switchCaughtUp,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			cfg := config.TestBlockSyncConfig()
+			if tc.modify != nil {
+				tc.modify(cfg)
+			}
+			assert.Equal(t, tc.expected, decideSwitchToConsensus(tc.status, cfg))
+		})
+	}
+}
@@ -318,6 +318,28 @@ func (pool *BlockPool) MaxPeerHeight() int64 {
 	return pool.maxPeerHeight
 }
 
+// NumPeers returns the number of peers of the pool.
+func (pool *BlockPool) NumPeers() int {
+	pool.mtx.RLock()
+	defer pool.mtx.RUnlock()
+	return len(pool.peers)
+}
+
+// NumServingPeers returns the number of peers which can serve the next block
+// to sync, i.e. whose height is at least the pool height.
+func (pool *BlockPool) NumServingPeers() int {
+	pool.mtx.RLock()
+	defer pool.mtx.RUnlock()
+
+	n := 0
+	for _, peer := range pool.peers {
+		if peer.height >= pool.height {
+			n++
+		}
+	}
+	return n
+}
+
 // LastAdvance returns the time when the last block was processed (or start
 // time if no blocks were processed).
 func (pool *BlockPool) LastAdvance() time.Time {
@@ -397,12 +419,15 @@ func (pool *BlockPool) updateMaxPeerHeight() {
 	pool.maxPeerHeight = max
 }
 
-// Pick an available peer with the given height available.
-// If no peers are available, returns nil.
+// pickIncrAvailablePeer picks the peer with the fewest pending requests among
+// those which have the block at height, so that requests are spread evenly
+// across peers, and increments its number of pending requests. If no peers are
+// available, it returns nil.
 func (pool *BlockPool) pickIncrAvailablePeer(height int64) *bpPeer {
 	pool.mtx.Lock()
 	defer pool.mtx.Unlock()
 
+	var picked *bpPeer
 	for _, peer := range pool.peers {
 		if peer.didTimeout {
 			pool.removePeer(peer.id)
@@ -414,10 +439,14 @@ func (pool *BlockPool) pickIncrAvailablePeer(height int64) *bpPeer {
 		if height < peer.base || height > peer.height {
 			continue
 		}
-		peer.incrPending()
-		return peer
+		if picked == nil || peer.numPending < picked.numPending {
+			picked = peer
+		}
 	}
-	return nil
+	if picked != nil {
+		picked.incrPending()
+	}
+	return picked
 }
 
 func (pool *BlockPool) makeNextRequester(ctx context.Context) {
@@ -235,3 +235,41 @@ func TestBlockPoolRemovePeer(t *testing.T) {
 
 	assert.EqualValues(t, 0, pool.MaxPeerHeight())
 }
+
+func TestBlockPoolPickLeastPendingPeer(t *testing.T) {
+	requestsCh := make(chan BlockRequest)
+	errorsCh := make(chan peerError, 100)
+	pool := NewBlockPool(log.NewNopLogger(), 1, requestsCh, errorsCh)
+
+	for i := 0; i < 3; i++ {
+		pool.SetPeerRange(types.NodeID(fmt.Sprintf("%d", i+1)), 1, 100)
+	}
+
+	// requests are spread evenly across the peers
+	for i := 0; i < 9; i++ {
+		require.NotNil(t, pool.pickIncrAvailablePeer(int64(i+1)))
+	}
+	for _, peer := range pool.peers {
+		assert.EqualValues(t, 3, peer.numPending)
+		peer.timeout.Stop()
+	}
+
+	// no peer has the block
+	assert.Nil(t, pool.pickIncrAvailablePeer(101))
+}
+
+func TestBlockPoolNumServingPeers(t *testing.T) {
+	requestsCh := make(chan BlockRequest)
+	errorsCh := make(chan peerError, 100)
+	pool := NewBlockPool(log.NewNopLogger(), 10, requestsCh, errorsCh)
+
+	assert.Equal(t, 0, pool.NumServingPeers())
+
+	pool.SetPeerRange("a", 1, 9)
+	pool.SetPeerRange("b", 1, 10)
+	pool.SetPeerRange("c", 1, 20)
+	assert.Equal(t, 2, pool.NumServingPeers())
+
+	pool.RemovePeer("c")
+	assert.Equal(t, 1, pool.NumServingPeers())
+}
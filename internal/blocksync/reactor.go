@@ -8,6 +8,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/internal/consensus"
 	"github.com/tendermint/tendermint/internal/eventbus"
 	"github.com/tendermint/tendermint/internal/p2p"
@@ -28,14 +29,11 @@ const (
 
 	trySyncIntervalMS = 10
 
-	// ask for best height every 10s
+	// ask for best height, and publish the sync progress, every 10s
 	statusUpdateIntervalSeconds = 10
 
 	// check if we should switch to consensus reactor
 	switchToConsensusIntervalSeconds = 1
-
-	// switch to consensus after this duration of inactivity
-	syncTimeout = 60 * time.Second
 )
 
 func GetChannelDescriptor() *p2p.ChannelDescriptor {
@@ -47,6 +45,7 @@ func GetChannelDescriptor() *p2p.ChannelDescriptor {
 		RecvBufferCapacity:  1024,
 		RecvMessageCapacity: MaxMsgSize,
 		Name:                "blockSync",
+		Compress:            true,
 	}
 }
 
@@ -69,6 +68,7 @@ func (e peerError) Error() string {
 type Reactor struct {
 	service.BaseService
 	logger log.Logger
+	cfg    *config.BlockSyncConfig
 
 	// immutable
 	initialState sm.State
@@ -80,6 +80,7 @@ type Reactor struct {
 	pool        *BlockPool
 	consReactor consensusReactor
 	blockSync   *atomicBool
+	forceSwitch *atomicBool
 
 	chCreator  p2p.ChannelCreator
 	peerEvents p2p.PeerEventSubscriber
@@ -96,6 +97,7 @@ type Reactor struct {
 // NewReactor returns new reactor instance.
 func NewReactor(
 	logger log.Logger,
+	cfg *config.BlockSyncConfig,
 	stateStore sm.Store,
 	blockExec *sm.BlockExecutor,
 	store *store.BlockStore,
@@ -108,11 +110,13 @@ func NewReactor(
 ) *Reactor {
 	r := &Reactor{
 		logger:      logger,
+		cfg:         cfg,
 		stateStore:  stateStore,
 		blockExec:   blockExec,
 		store:       store,
 		consReactor: consReactor,
 		blockSync:   newAtomicBool(blockSync),
+		forceSwitch: newAtomicBool(false),
 		chCreator:   channelCreator,
 		peerEvents:  peerEvents,
 		metrics:     metrics,
@@ -430,6 +434,7 @@ func (r *Reactor) requestRoutine(ctx context.Context, blockSyncCh p2p.Channel) {
 			}); err != nil {
 				return
 			}
+			r.publishSyncProgress()
 		}
 	}
 }
@@ -474,48 +479,44 @@ func (r *Reactor) poolRoutine(ctx context.Context, stateSynced bool, blockSyncCh
 				"total", lenRequesters,
 				"height", height)
 
-			switch {
-
-			// The case statement below is a bit confusing, so here is a breakdown
-			// of its logic and purpose:
-			//
-			// If VoteExtensions are enabled we cannot switch to consensus without
-			// the vote extension data for the previous height, i.e. state.LastBlockHeight.
-			//
-			// If extensions were required during state.LastBlockHeight and we have
-			// sync'd at least one block, then we are guaranteed to have extensions.
-			// BlockSync requires that the blocks it fetches have extensions if
-			// extensions were enabled during the height.
-			//
-			// If extensions were required during state.LastBlockHeight and we have
-			// not sync'd any blocks, then we can only transition to Consensus
-			// if we already had extensions for the initial height.
-			// If any of these conditions is not met, we continue the loop, looking
-			// for extensions.
-			case state.ConsensusParams.ABCI.VoteExtensionsEnabled(state.LastBlockHeight) &&
-				(blocksSynced == 0 && !initialCommitHasExtensions):
+			decision := decideSwitchToConsensus(syncStatus{
+				height:             height,
+				maxPeerHeight:      r.pool.MaxPeerHeight(),
+				numPeers:           r.pool.NumPeers(),
+				sinceLastAdvance:   time.Since(lastAdvance),
+				extensionsRequired: state.ConsensusParams.ABCI.VoteExtensionsEnabled(state.LastBlockHeight),
+				haveExtendedCommit: blocksSynced > 0 || initialCommitHasExtensions,
+				forced:             r.forceSwitch.IsSet(),
+			}, r.cfg)
+
+			switch decision {
+			case waitForExtensions:
 				r.logger.Info(
 					"no extended commit yet",
 					"height", height,
 					"last_block_height", state.LastBlockHeight,
 					"initial_height", state.InitialHeight,
 					"max_peer_height", r.pool.MaxPeerHeight(),
-					"timeout_in", syncTimeout-time.Since(lastAdvance),
+					"timeout_in", r.cfg.SwitchStallTimeout-time.Since(lastAdvance),
 				)
 				continue
 
-			case r.pool.IsCaughtUp():
+			case switchCaughtUp:
 				r.logger.Info("switching to consensus reactor", "height", height)
 
-			case time.Since(lastAdvance) > syncTimeout:
+			case switchNoProgress:
 				r.logger.Error("no progress since last advance", "last_advance", lastAdvance)
 
+			case switchForced:
+				r.logger.Info("switching to consensus reactor on request", "height", height,
+					"max_peer_height", r.pool.MaxPeerHeight())
+
 			default:
 				r.logger.Info(
 					"not caught up yet",
 					"height", height,
 					"max_peer_height", r.pool.MaxPeerHeight(),
-					"timeout_in", syncTimeout-time.Since(lastAdvance),
+					"timeout_in", r.cfg.SwitchStallTimeout-time.Since(lastAdvance),
 				)
 				continue
 			}
@@ -660,10 +661,38 @@ func (r *Reactor) poolRoutine(ctx context.Context, stateSynced bool, blockSyncCh
 	}
 }
 
+// ForceSwitchToConsensus requests block sync to hand over to consensus, even
+// if it isn't caught up. The switch happens within a second, once the vote
+// extensions of the last block are available, if required.
+func (r *Reactor) ForceSwitchToConsensus() error {
+	if !r.blockSync.IsSet() {
+		return errors.New("the node is not block syncing")
+	}
+	r.forceSwitch.Set()
+	return nil
+}
+
 func (r *Reactor) GetMaxPeerBlockHeight() int64 {
 	return r.pool.MaxPeerHeight()
 }
 
+// GetSyncRate returns the current block sync rate, in blocks per second.
+func (r *Reactor) GetSyncRate() float64 {
+	if !r.blockSync.IsSet() {
+		return 0
+	}
+	return r.pool.getLastSyncRate()
+}
+
+// GetNumSyncPeers returns the number of peers which can serve the next block
+// to sync.
+func (r *Reactor) GetNumSyncPeers() int {
+	if !r.blockSync.IsSet() {
+		return 0
+	}
+	return r.pool.NumServingPeers()
+}
+
 func (r *Reactor) GetTotalSyncedTime() time.Duration {
 	if !r.blockSync.IsSet() || r.syncStartTime.IsZero() {
 		return time.Duration(0)
@@ -695,6 +724,25 @@ func (r *Reactor) PublishStatus(event types.EventDataBlockSyncStatus) error {
 	return r.eventBus.PublishEventBlockSyncStatus(event)
 }
 
+// publishSyncProgress publishes a SyncProgress event while block syncing.
+func (r *Reactor) publishSyncProgress() {
+	if !r.blockSync.IsSet() || r.eventBus == nil {
+		return
+	}
+
+	err := r.eventBus.PublishEventSyncProgress(types.EventDataSyncProgress{
+		Height:          r.store.Height(),
+		TargetHeight:    r.pool.MaxPeerHeight(),
+		BlocksPerSecond: r.GetSyncRate(),
+		RemainingTime:   r.GetRemainingSyncTime(),
+		NumPeers:        r.GetNumSyncPeers(),
+		LastAdvance:     r.pool.LastAdvance(),
+	})
+	if err != nil {
+		r.logger.Error("failed to publish sync progress", "err", err)
+	}
+}
+
 // atomicBool is an atomic Boolean, safe for concurrent use by multiple
 // goroutines.
 type atomicBool int32
@@ -145,6 +145,7 @@ func makeReactor(
 
 	return NewReactor(
 		logger,
+		config.TestBlockSyncConfig(),
 		stateStore,
 		blockExec,
 		blockStore,
@@ -464,6 +465,7 @@ func TestReactorReceivesNoExtendedCommit(t *testing.T) {
 	)
 	NewReactor(
 		log.NewNopLogger(),
+		config.TestBlockSyncConfig(),
 		stateStore,
 		blockExec,
 		blockStore,
@@ -477,3 +479,13 @@ func TestReactorReceivesNoExtendedCommit(t *testing.T) {
 
 }
 */
+
+func TestReactor_ForceSwitchToConsensus(t *testing.T) {
+	r := &Reactor{blockSync: newAtomicBool(false), forceSwitch: newAtomicBool(false)}
+	require.Error(t, r.ForceSwitchToConsensus())
+	require.False(t, r.forceSwitch.IsSet())
+
+	r.blockSync.Set()
+	require.NoError(t, r.ForceSwitchToConsensus())
+	require.True(t, r.forceSwitch.IsSet())
+}
@@ -0,0 +1,118 @@
+package consensus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/libs/log"
+	tmcons "github.com/tendermint/tendermint/proto/tendermint/consensus"
+	"github.com/tendermint/tendermint/types"
+)
+
+// maxBatchSize is the size above which a batch is sent before its window
+// ends. It leaves room under maxMsgSize for the last message added.
+const maxBatchSize = maxMsgSize / 2
+
+// gossipBatcher coalesces the votes and block parts gossiped to a peer during a
+// batching window into a single MessageBatch, sent on the BatchChannel. This
+// saves the per-message overhead of the connection when many votes and block
+// parts are gossiped, e.g. with large validator sets.
+type gossipBatcher struct {
+	logger  log.Logger
+	peerID  types.NodeID
+	batchCh p2p.Channel
+	window  time.Duration
+
+	mtx    sync.Mutex
+	msgs   []tmcons.Message
+	unsent []func()
+	size   int
+	timer  *time.Timer
+}
+
+func newGossipBatcher(logger log.Logger, peerID types.NodeID, batchCh p2p.Channel, window time.Duration) *gossipBatcher {
+	return &gossipBatcher{
+		logger:  logger,
+		peerID:  peerID,
+		batchCh: batchCh,
+		window:  window,
+	}
+}
+
+// Add adds msg to the current batch, which is sent once the window started by
+// its first message ends, or right away once it's larger than maxBatchSize.
+// The caller marks msg as sent to the peer before adding it: unsent, if not
+// nil, is called to undo that if the batch fails to be sent.
+func (b *gossipBatcher) Add(ctx context.Context, msg proto.Message, unsent func()) error {
+	var pm tmcons.Message
+	if err := pm.Wrap(msg); err != nil {
+		return err
+	}
+
+	b.mtx.Lock()
+	b.msgs = append(b.msgs, pm)
+	b.unsent = append(b.unsent, unsent)
+	b.size += pm.Size()
+	if b.size >= maxBatchSize {
+		msgs, unsents := b.take()
+		b.mtx.Unlock()
+		return b.send(ctx, msgs, unsents)
+	}
+	if len(b.msgs) == 1 {
+		b.timer = time.AfterFunc(b.window, func() {
+			if err := b.Flush(ctx); err != nil {
+				b.logger.Debug("failed to send gossip batch", "peer", b.peerID, "err", err)
+			}
+		})
+	}
+	b.mtx.Unlock()
+
+	return nil
+}
+
+// Flush sends the current batch, if any.
+func (b *gossipBatcher) Flush(ctx context.Context) error {
+	b.mtx.Lock()
+	msgs, unsents := b.take()
+	b.mtx.Unlock()
+
+	return b.send(ctx, msgs, unsents)
+}
+
+// take returns the messages of the current batch and their unsent callbacks,
+// and starts a new batch. The caller must hold b.mtx.
+func (b *gossipBatcher) take() ([]tmcons.Message, []func()) {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	msgs, unsents := b.msgs, b.unsent
+	b.msgs = nil
+	b.unsent = nil
+	b.size = 0
+	return msgs, unsents
+}
+
+// send sends msgs as a single batch. If that fails, the unsent callbacks of
+// all the messages are called, so that they can be gossiped again.
+func (b *gossipBatcher) send(ctx context.Context, msgs []tmcons.Message, unsents []func()) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	err := b.batchCh.Send(ctx, p2p.Envelope{
+		To:      b.peerID,
+		Message: &tmcons.MessageBatch{Messages: msgs},
+	})
+	if err != nil {
+		for _, unsent := range unsents {
+			if unsent != nil {
+				unsent()
+			}
+		}
+	}
+	return err
+}
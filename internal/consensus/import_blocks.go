@@ -0,0 +1,145 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/internal/eventbus"
+	"github.com/tendermint/tendermint/internal/proxy"
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/internal/store"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+// ImportBlocks imports the blocks of the block archive in dir (see
+// store.ExportBlocks) that follow the last block of the node, and replays them
+// through the application. The node must be stopped. It returns the height of
+// the last block of the node.
+func ImportBlocks(ctx context.Context, logger log.Logger, cfg config.BaseConfig, dir string) (int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	dbType := dbm.BackendType(cfg.DBBackend)
+	blockStoreDB, err := dbm.NewDB("blockstore", dbType, cfg.DBDir())
+	if err != nil {
+		return 0, err
+	}
+	defer blockStoreDB.Close()
+	blockStore := store.NewBlockStore(blockStoreDB)
+
+	stateDB, err := dbm.NewDB("state", dbType, cfg.DBDir())
+	if err != nil {
+		return 0, err
+	}
+	defer stateDB.Close()
+	stateStore := sm.NewStore(stateDB)
+
+	gdoc, err := sm.MakeGenesisDocFromFile(cfg.GenesisFile())
+	if err != nil {
+		return 0, err
+	}
+	state, err := stateStore.Load()
+	if err != nil {
+		return 0, err
+	}
+	if state.IsEmpty() {
+		state, err = sm.MakeGenesisState(gdoc)
+		if err != nil {
+			return 0, err
+		}
+		if err := stateStore.Save(state); err != nil {
+			return 0, err
+		}
+	}
+
+	client, _, err := proxy.ClientFactory(logger, cfg.ProxyApp, cfg.ABCI, cfg.DBDir())
+	if err != nil {
+		return 0, err
+	}
+	proxyApp := proxy.New(client, logger, proxy.NopMetrics())
+	if err := proxyApp.Start(ctx); err != nil {
+		return 0, fmt.Errorf("starting proxy app conns: %w", err)
+	}
+
+	eventBus := eventbus.NewDefault(logger)
+	if err := eventBus.Start(ctx); err != nil {
+		return 0, fmt.Errorf("failed to start event bus: %w", err)
+	}
+
+	// sync the application with the stores before importing blocks
+	handshaker := NewHandshaker(logger, stateStore, state, blockStore, eventBus, gdoc)
+	if err := handshaker.Handshake(ctx, proxyApp); err != nil {
+		return 0, err
+	}
+	state, err = stateStore.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	blockExec := sm.NewBlockExecutor(stateStore, logger, proxyApp, emptyMempool{}, sm.EmptyEvidencePool{},
+		blockStore, eventBus, sm.NopMetrics())
+
+	state, err = importBlocks(ctx, logger, blockExec, blockStore, state, dir)
+	return state.LastBlockHeight, err
+}
+
+// importBlocks imports the blocks of the block archive in dir following the
+// last block of state, until a block is missing from the archive. Every block
+// is verified against its commit and the validator set of its height, as in
+// block sync, then saved and applied. It returns the state after the last
+// imported block.
+func importBlocks(
+	ctx context.Context,
+	logger log.Logger,
+	blockExec *sm.BlockExecutor,
+	blockStore sm.BlockStore,
+	state sm.State,
+	dir string,
+) (sm.State, error) {
+	for height := state.LastBlockHeight + 1; ; height++ {
+		if ctx.Err() != nil {
+			return state, ctx.Err()
+		}
+
+		block, extCommit, err := store.ReadArchivedBlock(dir, height)
+		if errors.Is(err, store.ErrArchivedBlockNotFound) {
+			return state, nil
+		} else if err != nil {
+			return state, err
+		}
+
+		parts, err := block.MakePartSet(types.BlockPartSizeBytes)
+		if err != nil {
+			return state, fmt.Errorf("failed to make part set of block %d: %w", height, err)
+		}
+		blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}
+
+		commit := extCommit.ToCommit()
+		if err := state.Validators.VerifyCommitLight(state.ChainID, blockID, height, commit); err != nil {
+			return state, fmt.Errorf("invalid commit for block %d: %w", height, err)
+		}
+		if err := blockExec.ValidateBlock(ctx, state, block); err != nil {
+			return state, fmt.Errorf("invalid block %d: %w", height, err)
+		}
+
+		if state.ConsensusParams.ABCI.VoteExtensionsEnabled(height) {
+			if err := extCommit.EnsureExtensions(); err != nil {
+				return state, fmt.Errorf("invalid extended commit for block %d: %w", height, err)
+			}
+			blockStore.SaveBlockWithExtendedCommit(block, parts, extCommit)
+		} else {
+			blockStore.SaveBlock(block, parts, commit)
+		}
+
+		state, err = blockExec.ApplyBlock(ctx, state, blockID, block)
+		if err != nil {
+			return state, fmt.Errorf("failed to apply block %d: %w", height, err)
+		}
+		logger.Info("imported block", "height", height, "hash", block.Hash())
+	}
+}
@@ -0,0 +1,91 @@
+package consensus
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	abciclient "github.com/tendermint/tendermint/abci/client"
+	"github.com/tendermint/tendermint/abci/example/kvstore"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/internal/eventbus"
+	"github.com/tendermint/tendermint/internal/proxy"
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/internal/store"
+	"github.com/tendermint/tendermint/libs/log"
+	bcproto "github.com/tendermint/tendermint/proto/tendermint/blocksync"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestImportBlocks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sim := setupSimulator(ctx, t)
+	logger := log.NewNopLogger()
+
+	// export the simulated chain, which changes the validator set, to an archive
+	source := store.NewBlockStore(dbm.NewMemDB())
+	for i, block := range sim.Chain {
+		parts, err := block.MakePartSet(types.BlockPartSizeBytes)
+		require.NoError(t, err)
+		source.SaveBlockWithExtendedCommit(block, parts, sim.ExtCommits[i])
+	}
+	dir := t.TempDir()
+	require.NoError(t, source.ExportBlocks(dir, 1, numBlocks))
+
+	newImporter := func(t *testing.T) (*sm.BlockExecutor, *store.BlockStore, sm.State) {
+		t.Helper()
+
+		proxyApp := proxy.New(abciclient.NewLocalClient(logger, kvstore.NewApplication()), logger, proxy.NopMetrics())
+		require.NoError(t, proxyApp.Start(ctx))
+		eventBus := eventbus.NewDefault(logger)
+		require.NoError(t, eventBus.Start(ctx))
+
+		state := sim.GenesisState.Copy()
+		state.Version.Consensus.App = kvstore.ProtocolVersion // simulate handshake
+		_, err := proxyApp.InitChain(ctx, &abci.RequestInitChain{
+			Validators: types.TM2PB.ValidatorUpdates(state.Validators),
+		})
+		require.NoError(t, err)
+		stateStore := sm.NewStore(dbm.NewMemDB())
+		require.NoError(t, stateStore.Save(state))
+
+		blockStore := store.NewBlockStore(dbm.NewMemDB())
+		blockExec := sm.NewBlockExecutor(stateStore, logger, proxyApp, emptyMempool{}, sm.EmptyEvidencePool{},
+			blockStore, eventBus, sm.NopMetrics())
+		return blockExec, blockStore, state
+	}
+
+	t.Run("valid archive", func(t *testing.T) {
+		blockExec, blockStore, state := newImporter(t)
+		state, err := importBlocks(ctx, logger, blockExec, blockStore, state, dir)
+		require.NoError(t, err)
+		require.EqualValues(t, numBlocks, state.LastBlockHeight)
+		require.EqualValues(t, numBlocks, blockStore.Height())
+		require.Equal(t, sim.Chain[numBlocks-1].Hash(), blockStore.LoadBlock(numBlocks).Hash())
+	})
+
+	t.Run("invalid commit", func(t *testing.T) {
+		// the commit of block 3 is for another block
+		block, extCommit, err := store.ReadArchivedBlock(dir, 3)
+		require.NoError(t, err)
+		extCommit.BlockID = sim.ExtCommits[3].BlockID
+		pb, err := block.ToProto()
+		require.NoError(t, err)
+		bz, err := proto.Marshal(&bcproto.BlockResponse{Block: pb, ExtCommit: extCommit.ToProto()})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, store.ArchiveFileName(3)), bz, 0644))
+
+		blockExec, blockStore, state := newImporter(t)
+		state, err = importBlocks(ctx, logger, blockExec, blockStore, state, dir)
+		require.Error(t, err)
+		require.EqualValues(t, 2, state.LastBlockHeight)
+		require.EqualValues(t, 2, blockStore.Height())
+	})
+}
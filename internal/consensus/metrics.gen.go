@@ -208,6 +208,28 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "late_votes",
 			Help:      "Number of votes received by the node since process start that correspond to earlier heights and rounds than this node is currently in.",
 		}, append(labels, "vote_type")).With(labelsAndValues...),
+		MissedPrecommits: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "missed_precommits",
+			Help:      "Number of committed blocks without a precommit for the block from the validator.",
+		}, append(labels, "validator_address")).With(labelsAndValues...),
+		RoundsPerHeight: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "rounds_per_height",
+			Help:      "Histogram of the number of rounds it took to commit a height.",
+
+			Buckets: []float64{1, 2, 3, 5, 10},
+		}, labels).With(labelsAndValues...),
+		ProposalReceiveLatency: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "proposal_receive_latency",
+			Help:      "Histogram of the time in seconds between entering the propose step and receiving the proposal.",
+
+			Buckets: stdprometheus.ExponentialBucketsRange(0.01, 10, 8),
+		}, labels).With(labelsAndValues...),
 	}
 }
 
@@ -244,5 +266,8 @@ func NopMetrics() *Metrics {
 		ProposalCreateCount:         discard.NewCounter(),
 		RoundVotingPowerPercent:     discard.NewGauge(),
 		LateVotes:                   discard.NewCounter(),
+		MissedPrecommits:            discard.NewCounter(),
+		RoundsPerHeight:             discard.NewHistogram(),
+		ProposalReceiveLatency:      discard.NewHistogram(),
 	}
 }
@@ -135,6 +135,24 @@ type Metrics struct {
 	// in.
 	//metrics:Number of votes received by the node since process start that correspond to earlier heights and rounds than this node is currently in.
 	LateVotes metrics.Counter `metrics_labels:"vote_type"`
+
+	// MissedPrecommits is the number of committed blocks whose commit does not
+	// include a precommit for the block from a validator, labeled by the
+	// address of that validator. It covers all validators, unlike
+	// ValidatorMissedBlocks which only covers this node's validator.
+	//metrics:Number of committed blocks without a precommit for the block from the validator.
+	MissedPrecommits metrics.Counter `metrics_labels:"validator_address"`
+
+	// RoundsPerHeight is the number of rounds it took to commit each height.
+	//metrics:Histogram of the number of rounds it took to commit a height.
+	RoundsPerHeight metrics.Histogram `metrics_bucketsizes:"1, 2, 3, 5, 10"`
+
+	// ProposalReceiveLatency is the time between entering the propose step of
+	// a round and receiving the proposal of that round. Proposals received
+	// before entering the propose step are not observed.
+	//metrics:Histogram of the time in seconds between entering the propose step and receiving the proposal.
+	ProposalReceiveLatency metrics.Histogram `metrics_buckettype:"exprange" metrics_bucketsizes:"0.01, 10, 8"`
+	proposeStart           time.Time
 }
 
 // RecordConsMetrics uses for recording the block related metrics during fast-sync.
@@ -194,6 +212,17 @@ func (m *Metrics) MarkLateVote(vt tmproto.SignedMsgType) {
 	m.LateVotes.With("vote_type", n).Add(1)
 }
 
+func (m *Metrics) MarkProposeStarted() {
+	m.proposeStart = time.Now()
+}
+
+func (m *Metrics) MarkProposalReceived(recvTime time.Time) {
+	if m.proposeStart.IsZero() {
+		return
+	}
+	m.ProposalReceiveLatency.Observe(recvTime.Sub(m.proposeStart).Seconds())
+}
+
 func (m *Metrics) MarkStep(s cstypes.RoundStepType) {
 	if !m.stepStart.IsZero() {
 		stepTime := time.Since(m.stepStart).Seconds()
@@ -28,10 +28,12 @@ func init() {
 	jsontypes.MustRegister(&ProposalMessage{})
 	jsontypes.MustRegister(&ProposalPOLMessage{})
 	jsontypes.MustRegister(&BlockPartMessage{})
+	jsontypes.MustRegister(&BlockParityPartMessage{})
 	jsontypes.MustRegister(&VoteMessage{})
 	jsontypes.MustRegister(&HasVoteMessage{})
 	jsontypes.MustRegister(&VoteSetMaj23Message{})
 	jsontypes.MustRegister(&VoteSetBitsMessage{})
+	jsontypes.MustRegister(&VoteRequestMessage{})
 }
 
 // NewRoundStepMessage is sent for every step taken in the ConsensusState.
@@ -213,6 +215,35 @@ func (m *BlockPartMessage) String() string {
 	return fmt.Sprintf("[BlockPart H:%v R:%v P:%v]", m.Height, m.Round, m.Part)
 }
 
+// BlockParityPartMessage is sent when gossipping a Reed-Solomon parity part
+// of the proposed block to peers that support it.
+type BlockParityPartMessage struct {
+	Height int64 `json:",string"`
+	Round  int32
+	Part   *types.ParityPart
+}
+
+func (*BlockParityPartMessage) TypeTag() string { return "tendermint/BlockParityPart" }
+
+// ValidateBasic performs basic validation.
+func (m *BlockParityPartMessage) ValidateBasic() error {
+	if m.Height < 0 {
+		return errors.New("negative Height")
+	}
+	if m.Round < 0 {
+		return errors.New("negative Round")
+	}
+	if err := m.Part.ValidateBasic(); err != nil {
+		return fmt.Errorf("wrong Part: %w", err)
+	}
+	return nil
+}
+
+// String returns a string representation.
+func (m *BlockParityPartMessage) String() string {
+	return fmt.Sprintf("[BlockParityPart H:%v R:%v P:%v]", m.Height, m.Round, m.Part)
+}
+
 // VoteMessage is sent when voting for a proposal (or lack thereof).
 type VoteMessage struct {
 	Vote *types.Vote
@@ -332,6 +363,41 @@ func (m *VoteSetBitsMessage) String() string {
 	return fmt.Sprintf("[VSB %v/%02d/%v %v %v]", m.Height, m.Round, m.Type, m.BlockID, m.Votes)
 }
 
+// VoteRequestMessage is sent to request the votes of a vote set that we are
+// missing, e.g. after a brief disconnect, instead of waiting for peers to
+// gossip them.
+type VoteRequestMessage struct {
+	Height int64 `json:",string"`
+	Round  int32
+	Type   tmproto.SignedMsgType
+	Votes  *bits.BitArray
+}
+
+func (*VoteRequestMessage) TypeTag() string { return "tendermint/VoteRequest" }
+
+// ValidateBasic performs basic validation.
+func (m *VoteRequestMessage) ValidateBasic() error {
+	if m.Height < 0 {
+		return errors.New("negative Height")
+	}
+	if m.Round < 0 {
+		return errors.New("negative Round")
+	}
+	if !types.IsVoteTypeValid(m.Type) {
+		return errors.New("invalid Type")
+	}
+	if m.Votes.Size() > types.MaxVotesCount {
+		return fmt.Errorf("votes bit array is too big: %d, max: %d", m.Votes.Size(), types.MaxVotesCount)
+	}
+
+	return nil
+}
+
+// String returns a string representation.
+func (m *VoteRequestMessage) String() string {
+	return fmt.Sprintf("[VoteRequest %v/%02d/%v %v]", m.Height, m.Round, m.Type, m.Votes)
+}
+
 // MsgToProto takes a consensus message type and returns the proto defined
 // consensus message.
 //
@@ -403,6 +469,18 @@ func MsgToProto(msg Message) (*tmcons.Message, error) {
 				},
 			},
 		}
+	case *BlockParityPartMessage:
+		pb = tmcons.Message{
+			Sum: &tmcons.Message_BlockParityPart{
+				BlockParityPart: &tmcons.BlockParityPart{
+					Height:       msg.Height,
+					Round:        msg.Round,
+					Index:        msg.Part.Index,
+					LastPartSize: msg.Part.LastPartSize,
+					Bytes:        msg.Part.Bytes,
+				},
+			},
+		}
 	case *VoteMessage:
 		vote := msg.Vote.ToProto()
 		pb = tmcons.Message{
@@ -455,6 +533,22 @@ func MsgToProto(msg Message) (*tmcons.Message, error) {
 		pb = tmcons.Message{
 			Sum: vsb,
 		}
+	case *VoteRequestMessage:
+		vr := &tmcons.Message_VoteRequest{
+			VoteRequest: &tmcons.VoteRequest{
+				Height: msg.Height,
+				Round:  msg.Round,
+				Type:   msg.Type,
+			},
+		}
+
+		if bits := msg.Votes.ToProto(); bits != nil {
+			vr.VoteRequest.Votes = *bits
+		}
+
+		pb = tmcons.Message{
+			Sum: vr,
+		}
 
 	default:
 		return nil, fmt.Errorf("consensus: message not recognized: %T", msg)
@@ -533,6 +627,16 @@ func MsgFromProto(msg *tmcons.Message) (Message, error) {
 			Round:  msg.BlockPart.Round,
 			Part:   parts,
 		}
+	case *tmcons.Message_BlockParityPart:
+		pb = &BlockParityPartMessage{
+			Height: msg.BlockParityPart.Height,
+			Round:  msg.BlockParityPart.Round,
+			Part: &types.ParityPart{
+				Index:        msg.BlockParityPart.Index,
+				LastPartSize: msg.BlockParityPart.LastPartSize,
+				Bytes:        msg.BlockParityPart.Bytes,
+			},
+		}
 	case *tmcons.Message_Vote:
 		// Vote validation will be handled in the vote message ValidateBasic
 		// call below.
@@ -580,6 +684,18 @@ func MsgFromProto(msg *tmcons.Message) (Message, error) {
 			BlockID: *bi,
 			Votes:   bits,
 		}
+	case *tmcons.Message_VoteRequest:
+		bits := new(bits.BitArray)
+		if err := bits.FromProto(&msg.VoteRequest.Votes); err != nil {
+			return nil, fmt.Errorf("votes to proto error: %w", err)
+		}
+
+		pb = &VoteRequestMessage{
+			Height: msg.VoteRequest.Height,
+			Round:  msg.VoteRequest.Round,
+			Type:   msg.VoteRequest.Type,
+			Votes:  bits,
+		}
 	default:
 		return nil, fmt.Errorf("consensus: message not recognized: %T", msg)
 	}
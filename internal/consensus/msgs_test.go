@@ -125,6 +125,21 @@ func TestMsgToProto(t *testing.T) {
 				},
 			},
 		}, false},
+		{"successful BlockParityPartMessage", &BlockParityPartMessage{
+			Height: 100,
+			Round:  1,
+			Part:   &types.ParityPart{Index: 1, LastPartSize: 2, Bytes: []byte{1, 2, 3}},
+		}, &tmcons.Message{
+			Sum: &tmcons.Message_BlockParityPart{
+				BlockParityPart: &tmcons.BlockParityPart{
+					Height:       100,
+					Round:        1,
+					Index:        1,
+					LastPartSize: 2,
+					Bytes:        []byte{1, 2, 3},
+				},
+			},
+		}, false},
 		{"successful ProposalPOLMessage", &ProposalPOLMessage{
 			Height:           1,
 			ProposalPOLRound: 1,
@@ -187,6 +202,21 @@ func TestMsgToProto(t *testing.T) {
 				},
 			},
 		}, false},
+		{"successful VoteRequest", &VoteRequestMessage{
+			Height: 1,
+			Round:  1,
+			Type:   1,
+			Votes:  bits,
+		}, &tmcons.Message{
+			Sum: &tmcons.Message_VoteRequest{
+				VoteRequest: &tmcons.VoteRequest{
+					Height: 1,
+					Round:  1,
+					Type:   1,
+					Votes:  *pbBits,
+				},
+			},
+		}, false},
 		{"failure", nil, &tmcons.Message{}, true},
 	}
 	for _, tt := range testsCases {
@@ -416,6 +446,9 @@ func TestConsMsgsVectors(t *testing.T) {
 		{"VoteSetBits", &tmcons.Message{Sum: &tmcons.Message_VoteSetBits{
 			VoteSetBits: &tmcons.VoteSetBits{Height: 1, Round: 1, Type: tmproto.PrevoteType, BlockID: pbBi, Votes: *pbBits}}},
 			"4a5708011001180122480a206164645f6d6f72655f6578636c616d6174696f6e5f6d61726b735f636f64652d1224080112206164645f6d6f72655f6578636c616d6174696f6e5f6d61726b735f636f64652d2a050801120100"},
+		{"VoteRequest", &tmcons.Message{Sum: &tmcons.Message_VoteRequest{
+			VoteRequest: &tmcons.VoteRequest{Height: 1, Round: 1, Type: tmproto.PrevoteType, Votes: *pbBits}}},
+			"5a0d08011001180122050801120100"},
 	}
 
 	for _, tc := range testCases {
@@ -515,6 +548,40 @@ func TestVoteSetBitsMessageValidateBasic(t *testing.T) {
 	}
 }
 
+func TestVoteRequestMessageValidateBasic(t *testing.T) {
+	testCases := []struct {
+		malleateFn func(*VoteRequestMessage)
+		expErr     string
+	}{
+		{func(msg *VoteRequestMessage) {}, ""},
+		{func(msg *VoteRequestMessage) { msg.Height = -1 }, "negative Height"},
+		{func(msg *VoteRequestMessage) { msg.Round = -1 }, "negative Round"},
+		{func(msg *VoteRequestMessage) { msg.Type = 0x03 }, "invalid Type"},
+		{func(msg *VoteRequestMessage) { msg.Votes = bits.NewBitArray(types.MaxVotesCount + 1) },
+			"votes bit array is too big: 10001, max: 10000"},
+	}
+
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("#%d", i), func(t *testing.T) {
+			msg := &VoteRequestMessage{
+				Height: 1,
+				Round:  0,
+				Type:   0x01,
+				Votes:  bits.NewBitArray(1),
+			}
+
+			tc.malleateFn(msg)
+			err := msg.ValidateBasic()
+			if tc.expErr != "" && assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.expErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestNewRoundStepMessageValidateBasic(t *testing.T) {
 	testCases := []struct { // nolint: maligned
 		expectErr              bool
@@ -45,8 +45,21 @@ type PeerState struct {
 	mtx     sync.RWMutex
 	cancel  context.CancelFunc
 	running bool
+	parity  bool                   // set if the peer accepts block parity parts
+	batcher *gossipBatcher         // set if gossip to the peer is batched
 	PRS     cstypes.PeerRoundState `json:"round_state"`
 	Stats   *peerStateStats        `json:"stats"`
+
+	// vote requests served to the peer at voteRequestsHeight, see
+	// TryServeVoteRequest()
+	voteRequests       map[voteRequestKey]time.Time
+	voteRequestsHeight int64
+
+	// time at which the peer was first sent enough block parts and parity
+	// parts to reconstruct the proposal block of parityHeight/parityRound
+	paritySufficientAt time.Time
+	parityHeight       int64
+	parityRound        int32
 }
 
 // NewPeerState returns a new PeerState for the given node ID.
@@ -81,6 +94,66 @@ func (ps *PeerState) IsRunning() bool {
 	return ps.running
 }
 
+// SetParityPartsSupported sets whether the peer accepts block parity parts.
+func (ps *PeerState) SetParityPartsSupported(v bool) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	ps.parity = v
+}
+
+// ParityPartsSupported returns true if the peer accepts block parity parts.
+func (ps *PeerState) ParityPartsSupported() bool {
+	ps.mtx.RLock()
+	defer ps.mtx.RUnlock()
+
+	return ps.parity
+}
+
+// SetGossipBatcher sets the batcher of the votes and block parts gossiped to
+// the peer, nil if they aren't batched.
+func (ps *PeerState) SetGossipBatcher(b *gossipBatcher) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	ps.batcher = b
+}
+
+// voteRequestKey identifies the votes requested by a VoteRequest message.
+type voteRequestKey struct {
+	round    int32
+	voteType tmproto.SignedMsgType
+}
+
+// TryServeVoteRequest reports whether a request from the peer for the votes
+// of the given height, round and type may be served, and records it as served
+// if so. The same votes are served at most once per voteRequestTimeout, so
+// that repeated requests can't make us send the same votes over and over.
+func (ps *PeerState) TryServeVoteRequest(height int64, round int32, voteType tmproto.SignedMsgType, now time.Time) bool {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	if ps.voteRequests == nil || ps.voteRequestsHeight != height {
+		ps.voteRequests = make(map[voteRequestKey]time.Time)
+		ps.voteRequestsHeight = height
+	}
+	key := voteRequestKey{round: round, voteType: voteType}
+	if served, ok := ps.voteRequests[key]; ok && now.Sub(served) < voteRequestTimeout {
+		return false
+	}
+	ps.voteRequests[key] = now
+	return true
+}
+
+// GossipBatcher returns the batcher of the votes and block parts gossiped to
+// the peer, nil if they aren't batched.
+func (ps *PeerState) GossipBatcher() *gossipBatcher {
+	ps.mtx.RLock()
+	defer ps.mtx.RUnlock()
+
+	return ps.batcher
+}
+
 // GetRoundState returns a shallow copy of the PeerRoundState. There's no point
 // in mutating it since it won't change PeerState.
 func (ps *PeerState) GetRoundState() *cstypes.PeerRoundState {
@@ -149,6 +222,16 @@ func (ps *PeerState) InitProposalBlockParts(partSetHeader types.PartSetHeader) {
 
 // SetHasProposalBlockPart sets the given block part index as known for the peer.
 func (ps *PeerState) SetHasProposalBlockPart(height int64, round int32, index int) {
+	ps.setHasProposalBlockPart(height, round, index, true)
+}
+
+// ClearHasProposalBlockPart sets the given block part index as unknown for the
+// peer, e.g. because sending it to the peer failed.
+func (ps *PeerState) ClearHasProposalBlockPart(height int64, round int32, index int) {
+	ps.setHasProposalBlockPart(height, round, index, false)
+}
+
+func (ps *PeerState) setHasProposalBlockPart(height int64, round int32, index int, has bool) {
 	ps.mtx.Lock()
 	defer ps.mtx.Unlock()
 
@@ -156,7 +239,52 @@ func (ps *PeerState) SetHasProposalBlockPart(height int64, round int32, index in
 		return
 	}
 
-	ps.PRS.ProposalBlockParts.SetIndex(index, true)
+	ps.PRS.ProposalBlockParts.SetIndex(index, has)
+}
+
+// SetHasProposalBlockParityPart sets the given block parity part index as known
+// for the peer.
+func (ps *PeerState) SetHasProposalBlockParityPart(height int64, round int32, index int) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	if ps.PRS.Height != height || ps.PRS.Round != round || ps.PRS.ProposalBlockParts == nil {
+		return
+	}
+
+	if ps.PRS.ProposalBlockParityParts == nil {
+		numParity := types.NumParityParts(ps.PRS.ProposalBlockPartSetHeader.Total)
+		ps.PRS.ProposalBlockParityParts = bits.NewBitArray(int(numParity))
+	}
+	ps.PRS.ProposalBlockParityParts.SetIndex(index, true)
+}
+
+// ClearHasProposalBlockParityPart sets the given block parity part index as
+// unknown for the peer, e.g. because sending it to the peer failed.
+func (ps *PeerState) ClearHasProposalBlockParityPart(height int64, round int32, index int) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	if ps.PRS.Height != height || ps.PRS.Round != round {
+		return
+	}
+
+	ps.PRS.ProposalBlockParityParts.SetIndex(index, false)
+}
+
+// ParitySufficientSince returns the time at which the peer was first found to
+// have enough block parts and parity parts to reconstruct the proposal block of
+// the given height and round, recording now if it wasn't found before.
+func (ps *PeerState) ParitySufficientSince(height int64, round int32, now time.Time) time.Time {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	if ps.paritySufficientAt.IsZero() || ps.parityHeight != height || ps.parityRound != round {
+		ps.paritySufficientAt = now
+		ps.parityHeight = height
+		ps.parityRound = round
+	}
+	return ps.paritySufficientAt
 }
 
 // PickVoteToSend picks a vote to send to the peer. It will return true if a
@@ -369,6 +497,18 @@ func (ps *PeerState) SetHasVote(vote *types.Vote) error {
 	return ps.setHasVote(vote.Height, vote.Round, vote.Type, vote.ValidatorIndex)
 }
 
+// ClearHasVote sets the given vote as unknown by the peer, e.g. because
+// sending it to the peer failed.
+func (ps *PeerState) ClearHasVote(vote *types.Vote) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	// NOTE: some may be nil BitArrays -> no side effects
+	if psVotes := ps.getVoteBitArray(vote.Height, vote.Round, vote.Type); psVotes != nil {
+		psVotes.SetIndex(int(vote.ValidatorIndex), false)
+	}
+}
+
 // setHasVote will return an error when the index exceeds the bitArray length
 func (ps *PeerState) setHasVote(height int64, round int32, voteType tmproto.SignedMsgType, index int32) error {
 	logger := ps.logger.With(
@@ -416,6 +556,7 @@ func (ps *PeerState) ApplyNewRoundStepMessage(msg *NewRoundStepMessage) {
 		ps.PRS.Proposal = false
 		ps.PRS.ProposalBlockPartSetHeader = types.PartSetHeader{}
 		ps.PRS.ProposalBlockParts = nil
+		ps.PRS.ProposalBlockParityParts = nil
 		ps.PRS.ProposalPOLRound = -1
 		ps.PRS.ProposalPOL = nil
 
@@ -463,6 +604,7 @@ func (ps *PeerState) ApplyNewValidBlockMessage(msg *NewValidBlockMessage) {
 
 	ps.PRS.ProposalBlockPartSetHeader = msg.BlockPartSetHeader
 	ps.PRS.ProposalBlockParts = msg.BlockParts
+	ps.PRS.ProposalBlockParityParts = nil
 }
 
 // ApplyProposalPOLMessage updates the peer state for the new proposal POL.
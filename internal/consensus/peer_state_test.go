@@ -2,6 +2,7 @@ package consensus
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -99,3 +100,36 @@ func TestApplyHasVoteMessage(t *testing.T) {
 
 	// skip test cases like v & v3 in TestSetHasVote due to the same path
 }
+
+func TestSetHasProposalBlockParityPart(t *testing.T) {
+	ps := peerStateSetup(1, 1, 1)
+
+	// ignored until the peer has the part set header
+	ps.SetHasProposalBlockParityPart(1, 1, 0)
+	require.Nil(t, ps.PRS.ProposalBlockParityParts)
+
+	ps.InitProposalBlockParts(types.PartSetHeader{Total: 10, Hash: make([]byte, 32)})
+	ps.SetHasProposalBlockParityPart(1, 1, 2)
+	require.Equal(t, 5, ps.PRS.ProposalBlockParityParts.Size())
+	require.True(t, ps.PRS.ProposalBlockParityParts.GetIndex(2))
+
+	// ignored for other rounds
+	ps.SetHasProposalBlockParityPart(1, 2, 3)
+	require.False(t, ps.PRS.ProposalBlockParityParts.GetIndex(3))
+
+	// reset for a new round
+	ps.ApplyNewRoundStepMessage(&NewRoundStepMessage{Height: 1, Round: 2})
+	require.Nil(t, ps.PRS.ProposalBlockParityParts)
+}
+
+func TestParitySufficientSince(t *testing.T) {
+	ps := peerStateSetup(1, 1, 1)
+	now := time.Now()
+
+	require.Equal(t, now, ps.ParitySufficientSince(1, 1, now))
+	require.Equal(t, now, ps.ParitySufficientSince(1, 1, now.Add(time.Second)))
+
+	// reset for another round
+	later := now.Add(2 * time.Second)
+	require.Equal(t, later, ps.ParitySufficientSince(1, 2, later))
+}
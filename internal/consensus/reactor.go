@@ -8,6 +8,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
+
 	cstypes "github.com/tendermint/tendermint/internal/consensus/types"
 	"github.com/tendermint/tendermint/internal/eventbus"
 	tmstrings "github.com/tendermint/tendermint/internal/libs/strings"
@@ -52,6 +54,20 @@ func getChannelDescriptors() map[p2p.ChannelID]*p2p.ChannelDescriptor {
 			RecvBufferCapacity:  512,
 			RecvMessageCapacity: maxMsgSize,
 			Name:                "data",
+			Compress:            true,
+		},
+		DataParityChannel: {
+			// Peers that open this channel accept Reed-Solomon parity parts of
+			// the proposal block, so that they can reconstruct it from any
+			// PartSetHeader.Total parts and parity parts.
+			ID:                  DataParityChannel,
+			MessageType:         new(tmcons.Message),
+			Priority:            12,
+			SendQueueCapacity:   64,
+			RecvBufferCapacity:  512,
+			RecvMessageCapacity: maxMsgSize,
+			Name:                "dataParity",
+			Compress:            true,
 		},
 		VoteChannel: {
 			ID:                  VoteChannel,
@@ -71,6 +87,29 @@ func getChannelDescriptors() map[p2p.ChannelID]*p2p.ChannelDescriptor {
 			RecvMessageCapacity: maxMsgSize,
 			Name:                "voteSet",
 		},
+		VoteRequestChannel: {
+			// Peers that open this channel answer VoteRequest messages with
+			// the requested votes they have.
+			ID:                  VoteRequestChannel,
+			MessageType:         new(tmcons.Message),
+			Priority:            5,
+			SendQueueCapacity:   8,
+			RecvBufferCapacity:  128,
+			RecvMessageCapacity: maxMsgSize,
+			Name:                "voteRequest",
+		},
+		BatchChannel: {
+			// Peers that open this channel accept batches of the votes and
+			// block parts otherwise sent on the VoteChannel, DataChannel and
+			// DataParityChannel.
+			ID:                  BatchChannel,
+			MessageType:         new(tmcons.Message),
+			Priority:            10,
+			SendQueueCapacity:   64,
+			RecvBufferCapacity:  512,
+			RecvMessageCapacity: maxMsgSize,
+			Name:                "batch",
+		},
 	}
 }
 
@@ -79,12 +118,27 @@ const (
 	DataChannel        = p2p.ChannelID(0x21)
 	VoteChannel        = p2p.ChannelID(0x22)
 	VoteSetBitsChannel = p2p.ChannelID(0x23)
+	DataParityChannel  = p2p.ChannelID(0x24)
+	VoteRequestChannel = p2p.ChannelID(0x25)
+	BatchChannel       = p2p.ChannelID(0x26)
 
 	maxMsgSize = 1048576 // 1MB; NOTE: keep in sync with types.PartSet sizes.
 
 	blocksToContributeToBecomeGoodPeer = 10000
 	votesToContributeToBecomeGoodPeer  = 10000
 
+	// voteRequestTimeout is the minimum time between two responses to requests
+	// from a peer for the same votes, and maxVoteRequestVotes is the maximum
+	// number of votes sent in response to one request.
+	voteRequestTimeout  = 2 * time.Second
+	maxVoteRequestVotes = 128
+
+	// parityFallbackTimeout is the time after which the missing block parts
+	// are sent to a peer that was sent enough parts and parity parts to
+	// reconstruct the proposal block, but still doesn't have it. This happens
+	// if some of the parity parts it received were invalid.
+	parityFallbackTimeout = 2 * time.Second
+
 	listenerIDConsensus = "consensus-reactor"
 )
 
@@ -166,10 +220,13 @@ func NewReactor(
 }
 
 type channelBundle struct {
-	state  p2p.Channel
-	data   p2p.Channel
-	vote   p2p.Channel
-	votSet p2p.Channel
+	state      p2p.Channel
+	data       p2p.Channel
+	dataParity p2p.Channel
+	vote       p2p.Channel
+	votSet     p2p.Channel
+	voteReq    p2p.Channel
+	batch      p2p.Channel
 }
 
 // OnStart starts separate go routines for each p2p Channel and listens for
@@ -195,6 +252,11 @@ func (r *Reactor) OnStart(ctx context.Context) error {
 		return err
 	}
 
+	chBundle.dataParity, err = r.chCreator(ctx, chans[DataParityChannel])
+	if err != nil {
+		return err
+	}
+
 	chBundle.vote, err = r.chCreator(ctx, chans[VoteChannel])
 	if err != nil {
 		return err
@@ -205,13 +267,23 @@ func (r *Reactor) OnStart(ctx context.Context) error {
 		return err
 	}
 
+	chBundle.voteReq, err = r.chCreator(ctx, chans[VoteRequestChannel])
+	if err != nil {
+		return err
+	}
+
+	chBundle.batch, err = r.chCreator(ctx, chans[BatchChannel])
+	if err != nil {
+		return err
+	}
+
 	// start routine that computes peer statistics for evaluating peer quality
 	//
 	// TODO: Evaluate if we need this to be synchronized via WaitGroup as to not
 	// leak the goroutine when stopping the reactor.
 	go r.peerStatsRoutine(ctx, peerUpdates)
 
-	r.subscribeToBroadcastEvents(ctx, chBundle.state)
+	r.subscribeToBroadcastEvents(ctx, chBundle.state, chBundle.dataParity, chBundle.voteReq)
 
 	if !r.WaitSync() {
 		if err := r.state.Start(ctx); err != nil {
@@ -225,8 +297,11 @@ func (r *Reactor) OnStart(ctx context.Context) error {
 
 	go r.processStateCh(ctx, chBundle)
 	go r.processDataCh(ctx, chBundle)
+	go r.processDataParityCh(ctx, chBundle)
 	go r.processVoteCh(ctx, chBundle)
 	go r.processVoteSetBitsCh(ctx, chBundle)
+	go r.processVoteRequestCh(ctx, chBundle)
+	go r.processBatchCh(ctx, chBundle)
 	go r.processPeerUpdates(ctx, peerUpdates, chBundle)
 
 	return nil
@@ -344,10 +419,52 @@ func (r *Reactor) broadcastHasVoteMessage(ctx context.Context, vote *types.Vote,
 	})
 }
 
+// broadcastVoteRequestMessage requests the votes of the current round we are
+// missing from the peers which support it, when we are waiting for more votes
+// after having received +2/3 of any votes.
+func (r *Reactor) broadcastVoteRequestMessage(ctx context.Context, rs *cstypes.RoundState, voteReqCh p2p.Channel) error {
+	if rs.Votes == nil {
+		return nil
+	}
+
+	var (
+		voteType tmproto.SignedMsgType
+		votes    *types.VoteSet
+	)
+	switch rs.Step {
+	case cstypes.RoundStepPrevoteWait:
+		voteType, votes = tmproto.PrevoteType, rs.Votes.Prevotes(rs.Round)
+	case cstypes.RoundStepPrecommitWait:
+		voteType, votes = tmproto.PrecommitType, rs.Votes.Precommits(rs.Round)
+	default:
+		return nil
+	}
+
+	ourVotes := votes.BitArray()
+	if ourVotes == nil || ourVotes.IsFull() {
+		return nil
+	}
+
+	msg := &tmcons.VoteRequest{
+		Height: rs.Height,
+		Round:  rs.Round,
+		Type:   voteType,
+	}
+	if missing := ourVotes.Not().ToProto(); missing != nil {
+		msg.Votes = *missing
+	}
+
+	return voteReqCh.Send(ctx, p2p.Envelope{
+		Broadcast: true,
+		Message:   msg,
+	})
+}
+
 // subscribeToBroadcastEvents subscribes for new round steps and votes using the
 // internal pubsub defined in the consensus state to broadcast them to peers
-// upon receiving.
-func (r *Reactor) subscribeToBroadcastEvents(ctx context.Context, stateCh p2p.Channel) {
+// upon receiving. It also subscribes for invalid block parity parts to report
+// the peers that sent them.
+func (r *Reactor) subscribeToBroadcastEvents(ctx context.Context, stateCh, dataParityCh, voteReqCh p2p.Channel) {
 	onStopCh := r.state.getOnStopCh()
 
 	err := r.state.evsw.AddListenerForEvent(
@@ -357,6 +474,9 @@ func (r *Reactor) subscribeToBroadcastEvents(ctx context.Context, stateCh p2p.Ch
 			if err := r.broadcastNewRoundStepMessage(ctx, data.(*cstypes.RoundState), stateCh); err != nil {
 				return err
 			}
+			if err := r.broadcastVoteRequestMessage(ctx, data.(*cstypes.RoundState), voteReqCh); err != nil {
+				return err
+			}
 			select {
 			case onStopCh <- data.(*cstypes.RoundState):
 				return nil
@@ -392,6 +512,21 @@ func (r *Reactor) subscribeToBroadcastEvents(ctx context.Context, stateCh p2p.Ch
 	if err != nil {
 		r.logger.Error("failed to add listener for events", "err", err)
 	}
+
+	err = r.state.evsw.AddListenerForEvent(
+		listenerIDConsensus,
+		eventInvalidParityPartValue,
+		func(data tmevents.EventData) error {
+			ev := data.(invalidParityPart)
+			return dataParityCh.SendError(ctx, p2p.PeerError{
+				NodeID: ev.PeerID,
+				Err:    ev.Err,
+			})
+		},
+	)
+	if err != nil {
+		r.logger.Error("failed to add listener for events", "err", err)
+	}
 }
 
 func makeRoundStepMessage(rs *cstypes.RoundState) *tmcons.NewRoundStep {
@@ -483,14 +618,11 @@ func (r *Reactor) gossipDataForCatchup(ctx context.Context, rs *cstypes.RoundSta
 		}
 
 		logger.Debug("sending block part for catchup", "round", prs.Round, "index", index)
-		_ = dataCh.Send(ctx, p2p.Envelope{
-			To: ps.peerID,
-			Message: &tmcons.BlockPart{
-				Height: prs.Height, // not our height, so it does not matter.
-				Round:  prs.Round,  // not our height, so it does not matter
-				Part:   *partProto,
-			},
-		})
+		_ = r.sendGossip(ctx, ps, dataCh, &tmcons.BlockPart{
+			Height: prs.Height, // not our height, so it does not matter.
+			Round:  prs.Round,  // not our height, so it does not matter
+			Part:   *partProto,
+		}, nil, nil)
 
 		return
 	}
@@ -498,7 +630,7 @@ func (r *Reactor) gossipDataForCatchup(ctx context.Context, rs *cstypes.RoundSta
 	time.Sleep(r.state.config.PeerGossipSleepDuration)
 }
 
-func (r *Reactor) gossipDataRoutine(ctx context.Context, ps *PeerState, dataCh p2p.Channel) {
+func (r *Reactor) gossipDataRoutine(ctx context.Context, ps *PeerState, dataCh, dataParityCh p2p.Channel) {
 	logger := r.logger.With("peer", ps.peerID)
 
 	timer := time.NewTimer(0)
@@ -522,7 +654,17 @@ OUTER_LOOP:
 		prs := ps.GetRoundState()
 
 		// Send proposal Block parts?
-		if rs.ProposalBlockParts.HasHeader(prs.ProposalBlockPartSetHeader) {
+		if rs.ProposalBlockParts.HasHeader(prs.ProposalBlockPartSetHeader) &&
+			ps.ParityPartsSupported() && rs.ProposalBlockParts.IsComplete() &&
+			types.NumParityParts(rs.ProposalBlockParts.Total()) > 0 {
+			sent, err := r.gossipPartOrParityPart(ctx, rs, prs, ps, dataCh, dataParityCh)
+			if err != nil {
+				return
+			}
+			if sent {
+				continue OUTER_LOOP
+			}
+		} else if rs.ProposalBlockParts.HasHeader(prs.ProposalBlockPartSetHeader) {
 			if index, ok := rs.ProposalBlockParts.BitArray().Sub(prs.ProposalBlockParts.Copy()).PickRandom(); ok {
 				part := rs.ProposalBlockParts.GetPart(index)
 				partProto, err := part.ToProto()
@@ -532,18 +674,19 @@ OUTER_LOOP:
 				}
 
 				logger.Debug("sending block part", "height", prs.Height, "round", prs.Round)
-				if err := dataCh.Send(ctx, p2p.Envelope{
-					To: ps.peerID,
-					Message: &tmcons.BlockPart{
-						Height: rs.Height, // this tells peer that this part applies to us
-						Round:  rs.Round,  // this tells peer that this part applies to us
-						Part:   *partProto,
-					},
+				if err := r.sendGossip(ctx, ps, dataCh, &tmcons.BlockPart{
+					Height: rs.Height, // this tells peer that this part applies to us
+					Round:  rs.Round,  // this tells peer that this part applies to us
+					Part:   *partProto,
+				}, func() error {
+					ps.SetHasProposalBlockPart(prs.Height, prs.Round, index)
+					return nil
+				}, func() {
+					ps.ClearHasProposalBlockPart(prs.Height, prs.Round, index)
 				}); err != nil {
 					return
 				}
 
-				ps.SetHasProposalBlockPart(prs.Height, prs.Round, index)
 				continue OUTER_LOOP
 			}
 		}
@@ -631,6 +774,140 @@ OUTER_LOOP:
 	}
 }
 
+// gossipPartOrParityPart sends a random block part or parity part of our
+// complete proposal block to a peer that supports parity parts. The peer can
+// reconstruct the block from any PartSetHeader.Total of them, so nothing is
+// sent once it has that many, until parityFallbackTimeout has passed: the
+// reconstruction may have failed, so the missing block parts are sent then.
+// It returns true if a part was sent.
+func (r *Reactor) gossipPartOrParityPart(
+	ctx context.Context,
+	rs *cstypes.RoundState,
+	prs *cstypes.PeerRoundState,
+	ps *PeerState,
+	dataCh, dataParityCh p2p.Channel,
+) (bool, error) {
+	parts := rs.ProposalBlockParts
+	total := int(parts.Total())
+	numParity := int(types.NumParityParts(parts.Total()))
+
+	// wanted has a bit set for each part, then each parity part, that the
+	// peer doesn't have yet.
+	wanted := bits.NewBitArray(total + numParity)
+	numHave := 0
+	for i := 0; i < total; i++ {
+		if prs.ProposalBlockParts.GetIndex(i) {
+			numHave++
+		} else {
+			wanted.SetIndex(i, true)
+		}
+	}
+	for i := 0; i < numParity; i++ {
+		if prs.ProposalBlockParityParts.GetIndex(i) {
+			numHave++
+		} else {
+			wanted.SetIndex(total+i, true)
+		}
+	}
+	if numHave >= total {
+		since := ps.ParitySufficientSince(prs.Height, prs.Round, time.Now())
+		if time.Since(since) < parityFallbackTimeout {
+			return false, nil
+		}
+		for i := 0; i < numParity; i++ {
+			wanted.SetIndex(total+i, false)
+		}
+	}
+
+	index, ok := wanted.PickRandom()
+	if !ok {
+		return false, nil
+	}
+
+	if index < total {
+		partProto, err := parts.GetPart(index).ToProto()
+		if err != nil {
+			r.logger.Error("failed to convert block part to proto", "err", err)
+			return false, err
+		}
+
+		r.logger.Debug("sending block part", "peer", ps.peerID, "height", prs.Height, "round", prs.Round)
+		if err := r.sendGossip(ctx, ps, dataCh, &tmcons.BlockPart{
+			Height: rs.Height, // this tells peer that this part applies to us
+			Round:  rs.Round,  // this tells peer that this part applies to us
+			Part:   *partProto,
+		}, func() error {
+			ps.SetHasProposalBlockPart(prs.Height, prs.Round, index)
+			return nil
+		}, func() {
+			ps.ClearHasProposalBlockPart(prs.Height, prs.Round, index)
+		}); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	parity, err := parts.ParityParts()
+	if err != nil {
+		r.logger.Error("failed to compute block parity parts", "err", err)
+		return false, err
+	}
+	pp := parity[index-total]
+
+	r.logger.Debug("sending block parity part", "peer", ps.peerID, "height", prs.Height, "round", prs.Round)
+	if err := r.sendGossip(ctx, ps, dataParityCh, &tmcons.BlockParityPart{
+		Height:       rs.Height,
+		Round:        rs.Round,
+		Index:        pp.Index,
+		LastPartSize: pp.LastPartSize,
+		Bytes:        pp.Bytes,
+	}, func() error {
+		ps.SetHasProposalBlockParityPart(prs.Height, prs.Round, int(pp.Index))
+		return nil
+	}, func() {
+		ps.ClearHasProposalBlockParityPart(prs.Height, prs.Round, int(pp.Index))
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// sendGossip sends a vote or block part to the peer of ps on ch, or adds it to
+// the peer's current batch if gossip to the peer is batched. Once it's sent,
+// setHas, if not nil, marks it as known by the peer. A batched message is
+// marked before it's added to the batch, so that it isn't picked again while
+// the batch is pending, and clearHas unmarks it if the batch fails to be sent.
+func (r *Reactor) sendGossip(
+	ctx context.Context,
+	ps *PeerState,
+	ch p2p.Channel,
+	msg proto.Message,
+	setHas func() error,
+	clearHas func(),
+) error {
+	if b := ps.GossipBatcher(); b != nil {
+		if setHas != nil {
+			if err := setHas(); err != nil {
+				return err
+			}
+		}
+		return b.Add(ctx, msg, clearHas)
+	}
+
+	if err := ch.Send(ctx, p2p.Envelope{
+		To:      ps.peerID,
+		Message: msg,
+	}); err != nil {
+		return err
+	}
+	if setHas != nil {
+		return setHas()
+	}
+	return nil
+}
+
 // pickSendVote picks a vote and sends it to the peer. It will return true if
 // there is a vote to send and false otherwise.
 func (r *Reactor) pickSendVote(ctx context.Context, ps *PeerState, votes types.VoteSetReader, voteCh p2p.Channel) (bool, error) {
@@ -640,19 +917,16 @@ func (r *Reactor) pickSendVote(ctx context.Context, ps *PeerState, votes types.V
 	}
 
 	r.logger.Debug("sending vote message", "ps", ps, "vote", vote)
-	if err := voteCh.Send(ctx, p2p.Envelope{
-		To: ps.peerID,
-		Message: &tmcons.Vote{
-			Vote: vote.ToProto(),
-		},
+	if err := r.sendGossip(ctx, ps, voteCh, &tmcons.Vote{
+		Vote: vote.ToProto(),
+	}, func() error {
+		return ps.SetHasVote(vote)
+	}, func() {
+		ps.ClearHasVote(vote)
 	}); err != nil {
 		return false, err
 	}
 
-	if err := ps.SetHasVote(vote); err != nil {
-		return false, err
-	}
-
 	return true, nil
 }
 
@@ -961,6 +1235,16 @@ func (r *Reactor) processPeerUpdate(ctx context.Context, peerUpdate p2p.PeerUpda
 			ps = NewPeerState(r.logger, peerUpdate.NodeID)
 			r.peers[peerUpdate.NodeID] = ps
 		}
+		ps.SetParityPartsSupported(peerUpdate.Channels.Contains(DataParityChannel))
+		if window := r.state.config.PeerGossipBatchWindow; window > 0 && peerUpdate.Channels.Contains(BatchChannel) {
+			// Keep the batcher of a peer that's already up, so that its
+			// pending batch isn't dropped.
+			if ps.GossipBatcher() == nil {
+				ps.SetGossipBatcher(newGossipBatcher(r.logger, ps.peerID, chans.batch, window))
+			}
+		} else {
+			ps.SetGossipBatcher(nil)
+		}
 
 		if !ps.IsRunning() {
 			// Set the peer state's closer to signal to all spawned goroutines to exit
@@ -982,7 +1266,7 @@ func (r *Reactor) processPeerUpdate(ctx context.Context, peerUpdate p2p.PeerUpda
 					return
 				}
 				// start goroutines for this peer
-				go r.gossipDataRoutine(ctx, ps, chans.data)
+				go r.gossipDataRoutine(ctx, ps, chans.data, chans.dataParity)
 				go r.gossipVotesRoutine(ctx, ps, chans.vote)
 				go r.queryMaj23Routine(ctx, ps, chans.state)
 
@@ -1141,6 +1425,17 @@ func (r *Reactor) handleDataMessage(ctx context.Context, envelope *p2p.Envelope,
 		case <-ctx.Done():
 			return ctx.Err()
 		}
+	case *tmcons.BlockParityPart:
+		bppMsg := msgI.(*BlockParityPartMessage)
+
+		ps.SetHasProposalBlockParityPart(bppMsg.Height, bppMsg.Round, int(bppMsg.Part.Index))
+		r.Metrics.BlockParts.With("peer_id", string(envelope.From)).Add(1)
+		select {
+		case r.state.peerMsgQueue <- msgInfo{bppMsg, envelope.From, tmtime.Now()}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 
 	default:
 		return fmt.Errorf("received unknown message on DataChannel: %T", msg)
@@ -1244,6 +1539,119 @@ func (r *Reactor) handleVoteSetBitsMessage(ctx context.Context, envelope *p2p.En
 	return nil
 }
 
+// handleVoteRequestMessage handles envelopes sent from peers on the
+// VoteRequestChannel, by sending the requested votes we have to the peer on the
+// VoteChannel. If we fail to find the peer state for the envelope sender, we
+// perform a no-op and return. This can happen when we process the envelope
+// after the peer is removed.
+func (r *Reactor) handleVoteRequestMessage(ctx context.Context, envelope *p2p.Envelope, msgI Message, voteCh p2p.Channel) error {
+	logger := r.logger.With("peer", envelope.From, "ch_id", "VoteRequestChannel")
+
+	ps, ok := r.GetPeerState(envelope.From)
+	if !ok || ps == nil {
+		r.logger.Debug("failed to find peer state")
+		return nil
+	}
+
+	if r.WaitSync() {
+		logger.Info("ignoring message received during sync", "msg", msgI)
+		return nil
+	}
+
+	switch msg := envelope.Message.(type) {
+	case *tmcons.VoteRequest:
+		r.state.mtx.RLock()
+		height, votes, lastCommit := r.state.Height, r.state.Votes, r.state.LastCommit
+		r.state.mtx.RUnlock()
+
+		var voteSet *types.VoteSet
+		switch {
+		case msg.Height == height && msg.Type == tmproto.PrevoteType:
+			voteSet = votes.Prevotes(msg.Round)
+		case msg.Height == height && msg.Type == tmproto.PrecommitType:
+			voteSet = votes.Precommits(msg.Round)
+		case msg.Height == height-1 && msg.Type == tmproto.PrecommitType && lastCommit.GetRound() == msg.Round:
+			voteSet = lastCommit
+		}
+		if voteSet == nil {
+			return nil
+		}
+		if !ps.TryServeVoteRequest(msg.Height, msg.Round, msg.Type, time.Now()) {
+			logger.Debug("ignoring repeated vote request", "height", msg.Height, "round", msg.Round, "type", msg.Type)
+			return nil
+		}
+
+		requested := msgI.(*VoteRequestMessage).Votes
+		sent := 0
+		for i := 0; i < requested.Size() && sent < maxVoteRequestVotes; i++ {
+			if !requested.GetIndex(i) {
+				continue
+			}
+			vote := voteSet.GetByIndex(int32(i))
+			if vote == nil {
+				continue
+			}
+			sent++
+
+			if err := voteCh.Send(ctx, p2p.Envelope{
+				To: ps.peerID,
+				Message: &tmcons.Vote{
+					Vote: vote.ToProto(),
+				},
+			}); err != nil {
+				return err
+			}
+			if err := ps.SetHasVote(vote); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("received unknown message on VoteRequestChannel: %T", msg)
+	}
+
+	return nil
+}
+
+// handleBatchMessage handles envelopes sent from peers on the BatchChannel. Each
+// message of a batch is handled as if it was received on the channel it's sent
+// on when gossip isn't batched.
+func (r *Reactor) handleBatchMessage(ctx context.Context, envelope *p2p.Envelope, chans channelBundle) error {
+	batch, ok := envelope.Message.(*tmcons.MessageBatch)
+	if !ok {
+		return fmt.Errorf("received unknown message on BatchChannel: %T", envelope.Message)
+	}
+
+	for i := range batch.Messages {
+		msg, err := batch.Messages[i].Unwrap()
+		if err != nil {
+			return err
+		}
+
+		var chID p2p.ChannelID
+		switch msg.(type) {
+		case *tmcons.Vote:
+			chID = VoteChannel
+		case *tmcons.BlockPart:
+			chID = DataChannel
+		case *tmcons.BlockParityPart:
+			chID = DataParityChannel
+		default:
+			return fmt.Errorf("received unknown message in batch: %T", msg)
+		}
+
+		if err := r.handleMessage(ctx, &p2p.Envelope{
+			From:      envelope.From,
+			Message:   msg,
+			ChannelID: chID,
+		}, chans); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // handleMessage handles an Envelope sent from a peer on a specific p2p Channel.
 // It will handle errors and any possible panics gracefully. A caller can handle
 // any error returned by sending a PeerError on the respective channel.
@@ -1266,6 +1674,10 @@ func (r *Reactor) handleMessage(ctx context.Context, envelope *p2p.Envelope, cha
 		}
 	}()
 
+	if envelope.ChannelID == BatchChannel {
+		return r.handleBatchMessage(ctx, envelope, chans)
+	}
+
 	// We wrap the envelope's message in a Proto wire type so we can convert back
 	// the domain type that individual channel message handlers can work with. We
 	// do this here once to avoid having to do it for each individual message type.
@@ -1287,12 +1699,14 @@ func (r *Reactor) handleMessage(ctx context.Context, envelope *p2p.Envelope, cha
 	switch envelope.ChannelID {
 	case StateChannel:
 		err = r.handleStateMessage(ctx, envelope, msgI, chans.votSet)
-	case DataChannel:
+	case DataChannel, DataParityChannel:
 		err = r.handleDataMessage(ctx, envelope, msgI)
 	case VoteChannel:
 		err = r.handleVoteMessage(ctx, envelope, msgI)
 	case VoteSetBitsChannel:
 		err = r.handleVoteSetBitsMessage(ctx, envelope, msgI)
+	case VoteRequestChannel:
+		err = r.handleVoteRequestMessage(ctx, envelope, msgI, chans.vote)
 	default:
 		err = fmt.Errorf("unknown channel ID (%d) for envelope (%v)", envelope.ChannelID, envelope)
 	}
@@ -1342,6 +1756,27 @@ func (r *Reactor) processDataCh(ctx context.Context, chans channelBundle) {
 	}
 }
 
+// processDataParityCh initiates a blocking process where we listen for and
+// handle envelopes on the DataParityChannel. Any error encountered during
+// message execution will result in a PeerError being sent on the
+// DataParityChannel. When the reactor is stopped, we will catch the signal and
+// close the p2p Channel gracefully.
+func (r *Reactor) processDataParityCh(ctx context.Context, chans channelBundle) {
+	iter := chans.dataParity.Receive(ctx)
+	for iter.Next(ctx) {
+		envelope := iter.Envelope()
+		if err := r.handleMessage(ctx, envelope, chans); err != nil {
+			r.logger.Error("failed to process message", "ch_id", envelope.ChannelID, "envelope", envelope, "err", err)
+			if serr := chans.dataParity.SendError(ctx, p2p.PeerError{
+				NodeID: envelope.From,
+				Err:    err,
+			}); serr != nil {
+				return
+			}
+		}
+	}
+}
+
 // processVoteCh initiates a blocking process where we listen for and handle
 // envelopes on the VoteChannel. Any error encountered during message
 // execution will result in a PeerError being sent on the VoteChannel. When
@@ -1389,6 +1824,58 @@ func (r *Reactor) processVoteSetBitsCh(ctx context.Context, chans channelBundle)
 	}
 }
 
+// processVoteRequestCh initiates a blocking process where we listen for and
+// handle envelopes on the VoteRequestChannel. Any error encountered during
+// message execution will result in a PeerError being sent on the
+// VoteRequestChannel. When the reactor is stopped, we will catch the signal
+// and close the p2p Channel gracefully.
+func (r *Reactor) processVoteRequestCh(ctx context.Context, chans channelBundle) {
+	iter := chans.voteReq.Receive(ctx)
+	for iter.Next(ctx) {
+		envelope := iter.Envelope()
+
+		if err := r.handleMessage(ctx, envelope, chans); err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return
+			}
+
+			r.logger.Error("failed to process message", "ch_id", envelope.ChannelID, "envelope", envelope, "err", err)
+			if serr := chans.voteReq.SendError(ctx, p2p.PeerError{
+				NodeID: envelope.From,
+				Err:    err,
+			}); serr != nil {
+				return
+			}
+		}
+	}
+}
+
+// processBatchCh initiates a blocking process where we listen for and handle
+// envelopes on the BatchChannel. Any error encountered during message
+// execution will result in a PeerError being sent on the BatchChannel. When
+// the reactor is stopped, we will catch the signal and close the p2p Channel
+// gracefully.
+func (r *Reactor) processBatchCh(ctx context.Context, chans channelBundle) {
+	iter := chans.batch.Receive(ctx)
+	for iter.Next(ctx) {
+		envelope := iter.Envelope()
+
+		if err := r.handleMessage(ctx, envelope, chans); err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return
+			}
+
+			r.logger.Error("failed to process message", "ch_id", envelope.ChannelID, "envelope", envelope, "err", err)
+			if serr := chans.batch.SendError(ctx, p2p.PeerError{
+				NodeID: envelope.From,
+				Err:    err,
+			}); serr != nil {
+				return
+			}
+		}
+	}
+}
+
 // processPeerUpdates initiates a blocking process where we listen for and handle
 // PeerUpdate messages. When the reactor is stopped, we will catch the signal and
 // close the p2p PeerUpdatesCh gracefully.
@@ -1427,7 +1914,7 @@ func (r *Reactor) peerStatsRoutine(ctx context.Context, peerUpdates *p2p.PeerUpd
 					})
 				}
 
-			case *BlockPartMessage:
+			case *BlockPartMessage, *BlockParityPartMessage:
 				if numParts := ps.RecordBlockPart(); numParts%blocksToContributeToBecomeGoodPeer == 0 {
 					peerUpdates.SendUpdate(ctx, p2p.PeerUpdate{
 						NodeID: msg.PeerID,
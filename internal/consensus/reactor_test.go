@@ -30,6 +30,7 @@ import (
 	statemocks "github.com/tendermint/tendermint/internal/state/mocks"
 	"github.com/tendermint/tendermint/internal/store"
 	"github.com/tendermint/tendermint/internal/test/factory"
+	"github.com/tendermint/tendermint/libs/bits"
 	"github.com/tendermint/tendermint/libs/log"
 	tmcons "github.com/tendermint/tendermint/proto/tendermint/consensus"
 	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
@@ -48,8 +49,11 @@ type reactorTestSuite struct {
 	blocksyncSubs       map[types.NodeID]eventbus.Subscription
 	stateChannels       map[types.NodeID]p2p.Channel
 	dataChannels        map[types.NodeID]p2p.Channel
+	dataParityChannels  map[types.NodeID]p2p.Channel
 	voteChannels        map[types.NodeID]p2p.Channel
 	voteSetBitsChannels map[types.NodeID]p2p.Channel
+	voteRequestChannels map[types.NodeID]p2p.Channel
+	batchChannels       map[types.NodeID]p2p.Channel
 }
 
 func chDesc(chID p2p.ChannelID, size int) *p2p.ChannelDescriptor {
@@ -79,8 +83,11 @@ func setup(
 
 	rts.stateChannels = rts.network.MakeChannelsNoCleanup(ctx, t, chDesc(StateChannel, size))
 	rts.dataChannels = rts.network.MakeChannelsNoCleanup(ctx, t, chDesc(DataChannel, size))
+	rts.dataParityChannels = rts.network.MakeChannelsNoCleanup(ctx, t, chDesc(DataParityChannel, size))
 	rts.voteChannels = rts.network.MakeChannelsNoCleanup(ctx, t, chDesc(VoteChannel, size))
 	rts.voteSetBitsChannels = rts.network.MakeChannelsNoCleanup(ctx, t, chDesc(VoteSetBitsChannel, size))
+	rts.voteRequestChannels = rts.network.MakeChannelsNoCleanup(ctx, t, chDesc(VoteRequestChannel, size))
+	rts.batchChannels = rts.network.MakeChannelsNoCleanup(ctx, t, chDesc(BatchChannel, size))
 
 	ctx, cancel := context.WithCancel(ctx)
 	t.Cleanup(cancel)
@@ -92,10 +99,16 @@ func setup(
 				return rts.stateChannels[nodeID], nil
 			case DataChannel:
 				return rts.dataChannels[nodeID], nil
+			case DataParityChannel:
+				return rts.dataParityChannels[nodeID], nil
 			case VoteChannel:
 				return rts.voteChannels[nodeID], nil
 			case VoteSetBitsChannel:
 				return rts.voteSetBitsChannels[nodeID], nil
+			case VoteRequestChannel:
+				return rts.voteRequestChannels[nodeID], nil
+			case BatchChannel:
+				return rts.batchChannels[nodeID], nil
 			default:
 				return nil, fmt.Errorf("invalid channel; %v", desc.ID)
 			}
@@ -440,6 +453,50 @@ func TestReactorBasic(t *testing.T) {
 	}
 }
 
+func TestReactorGossipBatching(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	cfg := configSetup(t)
+
+	n := 4
+	states, cleanup := makeConsensusState(ctx, t,
+		cfg, n, "consensus_reactor_test",
+		newMockTickerFunc(true))
+	t.Cleanup(cleanup)
+
+	for _, state := range states {
+		state.config.PeerGossipBatchWindow = 5 * time.Millisecond
+	}
+
+	rts := setup(ctx, t, n, states, 100) // buffer must be large enough to not deadlock
+
+	for _, reactor := range rts.reactors {
+		state := reactor.state.GetState()
+		reactor.SwitchToConsensus(ctx, state, false)
+	}
+
+	// votes and block parts are only gossiped in batches, so committing blocks
+	// shows that batches are sent and handled
+	for i := 0; i < 2; i++ {
+		for _, sub := range rts.subs {
+			_, err := sub.Next(ctx)
+			require.NoError(t, err)
+		}
+	}
+
+	for nodeID, reactor := range rts.reactors {
+		for peerID := range rts.reactors {
+			if peerID == nodeID {
+				continue
+			}
+			ps, ok := reactor.GetPeerState(peerID)
+			require.True(t, ok)
+			require.NotNil(t, ps.GossipBatcher())
+		}
+	}
+}
+
 func TestReactorWithEvidence(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
@@ -778,6 +835,91 @@ func TestReactorRecordsVotesAndBlockParts(t *testing.T) {
 	require.Greater(t, ps.VotesSent(), 0, "number of votes sent should've increased")
 }
 
+func TestReactorVoteRequestNotServedTwice(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := configSetup(t)
+	cs, vss := makeState(ctx, t, makeStateArgs{config: cfg})
+
+	vote := signVote(ctx, t, vss[1], tmproto.PrevoteType, cs.state.ChainID, types.BlockID{})
+	added, err := cs.Votes.AddVote(vote, "")
+	require.NoError(t, err)
+	require.True(t, added)
+
+	reactor := NewReactor(log.NewNopLogger(), cs, nil, nil, cs.eventBus, false, NopMetrics())
+	peerID := types.NodeID("aa")
+	reactor.peers[peerID] = NewPeerState(log.NewNopLogger(), peerID)
+
+	outCh := make(chan p2p.Envelope, len(vss))
+	voteCh := p2p.NewChannel(VoteChannel, "vote", nil, outCh, nil)
+
+	requested := bits.NewBitArray(len(vss))
+	requested.SetIndex(int(vote.ValidatorIndex), true)
+	msg := &VoteRequestMessage{Height: vote.Height, Round: vote.Round, Type: vote.Type, Votes: requested}
+	envelope := &p2p.Envelope{
+		From: peerID,
+		Message: &tmcons.VoteRequest{
+			Height: msg.Height,
+			Round:  msg.Round,
+			Type:   msg.Type,
+			Votes:  *requested.ToProto(),
+		},
+	}
+
+	require.NoError(t, reactor.handleVoteRequestMessage(ctx, envelope, msg, voteCh))
+	require.Len(t, outCh, 1)
+	<-outCh
+
+	// the same request is ignored within voteRequestTimeout
+	require.NoError(t, reactor.handleVoteRequestMessage(ctx, envelope, msg, voteCh))
+	require.Len(t, outCh, 0)
+}
+
+func TestReactorGossipBatchFailureClearsHasVote(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := configSetup(t)
+	cs, vss := makeState(ctx, t, makeStateArgs{config: cfg})
+
+	vote := signVote(ctx, t, vss[1], tmproto.PrevoteType, cs.state.ChainID, types.BlockID{})
+	added, err := cs.Votes.AddVote(vote, "")
+	require.NoError(t, err)
+	require.True(t, added)
+
+	reactor := NewReactor(log.NewNopLogger(), cs, nil, nil, cs.eventBus, false, NopMetrics())
+	peerID := types.NodeID("aa")
+	ps := NewPeerState(log.NewNopLogger(), peerID)
+	ps.PRS.Height = vote.Height
+	ps.PRS.Round = vote.Round
+	ps.EnsureVoteBitArrays(vote.Height, len(vss))
+
+	// nothing reads the batch channel, so sending a batch blocks until its
+	// context is canceled
+	batchCh := p2p.NewChannel(BatchChannel, "batch", nil, make(chan p2p.Envelope), nil)
+	batcher := newGossipBatcher(log.NewNopLogger(), peerID, batchCh, time.Hour)
+	ps.SetGossipBatcher(batcher)
+
+	sent, err := reactor.pickSendVote(ctx, ps, cs.Votes.Prevotes(vote.Round), nil)
+	require.NoError(t, err)
+	require.True(t, sent)
+
+	// the vote is marked as known while the batch is pending, so it isn't
+	// picked again
+	_, ok := ps.PickVoteToSend(cs.Votes.Prevotes(vote.Round))
+	require.False(t, ok)
+
+	flushCtx, flushCancel := context.WithCancel(ctx)
+	flushCancel()
+	require.Error(t, batcher.Flush(flushCtx))
+
+	// the batch failed, so the vote is picked again
+	picked, ok := ps.PickVoteToSend(cs.Votes.Prevotes(vote.Round))
+	require.True(t, ok)
+	require.Equal(t, vote.ValidatorIndex, picked.ValidatorIndex)
+}
+
 func TestReactorVotingPowerChange(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test in short mode")
@@ -76,6 +76,8 @@ func (cs *State) readReplayMessage(ctx context.Context, msg *TimedWALMessage, ne
 				p.BlockID.PartSetHeader, "pol", p.POLRound, "peer", peerID)
 		case *BlockPartMessage:
 			cs.logger.Info("Replay: BlockPart", "height", msg.Height, "round", msg.Round, "peer", peerID)
+		case *BlockParityPartMessage:
+			cs.logger.Info("Replay: BlockParityPart", "height", msg.Height, "round", msg.Round, "peer", peerID)
 		case *VoteMessage:
 			v := msg.Vote
 			cs.logger.Info("Replay: Vote", "height", v.Height, "round", v.Round, "type", v.Type,
@@ -27,6 +27,10 @@ func (emptyMempool) CheckTx(context.Context, types.Tx, func(*abci.ResponseCheckT
 func (emptyMempool) RemoveTxByKey(txKey types.TxKey) error   { return nil }
 func (emptyMempool) ReapMaxBytesMaxGas(_, _ int64) types.Txs { return types.Txs{} }
 func (emptyMempool) ReapMaxTxs(n int) types.Txs              { return types.Txs{} }
+func (emptyMempool) ReapTxsBySender(string) types.Txs        { return types.Txs{} }
+func (emptyMempool) RejectedTx(types.TxKey) (mempool.RejectedTx, bool) {
+	return mempool.RejectedTx{}, false
+}
 func (emptyMempool) Update(
 	_ context.Context,
 	_ int64,
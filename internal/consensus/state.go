@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	mrand "math/rand"
 	"os"
 	"runtime/debug"
 	"sort"
@@ -47,6 +48,16 @@ var (
 
 var msgQueueSize = 1000
 
+// eventInvalidParityPartValue is fired on the internal event switch with an
+// invalidParityPart when the block parity parts received from a peer failed to
+// reconstruct the proposal block.
+const eventInvalidParityPartValue = "InvalidParityPart"
+
+type invalidParityPart struct {
+	PeerID types.NodeID
+	Err    error
+}
+
 // msgs from the reactor which may update the state
 type msgInfo struct {
 	Msg         Message
@@ -176,6 +187,12 @@ type State struct {
 	// state only emits EventNewRoundStep, EventValidBlock, and EventVote
 	evsw tmevents.EventSwitch
 
+	// selects the proposer of each round
+	proposerSelector types.ProposerSelector
+
+	// inspects the blocks we propose, may be nil
+	proposalBlockHook types.ProposalBlockHook
+
 	// for reporting metrics
 	metrics *Metrics
 
@@ -220,6 +237,7 @@ func NewState(
 		wal:              nilWAL{},
 		evpool:           evpool,
 		evsw:             tmevents.NewEventSwitch(),
+		proposerSelector: types.PriorityProposerSelector{},
 		metrics:          NopMetrics(),
 		onStopCh:         make(chan *cstypes.RoundState),
 	}
@@ -280,6 +298,17 @@ func StateMetrics(metrics *Metrics) StateOption {
 	return func(cs *State) { cs.metrics = metrics }
 }
 
+// StateProposerSelector sets the selector of the proposer of each round. All
+// validators of a network must use the same one.
+func StateProposerSelector(selector types.ProposerSelector) StateOption {
+	return func(cs *State) { cs.proposerSelector = selector }
+}
+
+// StateProposalBlockHook sets a hook which may veto the blocks we propose.
+func StateProposalBlockHook(hook types.ProposalBlockHook) StateOption {
+	return func(cs *State) { cs.proposalBlockHook = hook }
+}
+
 // String returns a string.
 func (cs *State) String() string {
 	// better not to access shared variables
@@ -536,7 +565,11 @@ func (cs *State) OnStop() {
 // OpenWAL opens a file to log all consensus messages and timeouts for
 // deterministic accountability.
 func (cs *State) OpenWAL(ctx context.Context, walFile string) (WAL, error) {
-	wal, err := NewWAL(ctx, cs.logger.With("wal", walFile), walFile)
+	wal, err := NewWAL(ctx, cs.logger.With("wal", walFile), walFile,
+		autofile.GroupCompressRotated(cs.config.WalCompress),
+		autofile.GroupHeadSizeLimit(cs.config.WalSegmentSize),
+		autofile.GroupTotalSizeLimit(cs.config.WalRetentionSize),
+	)
 	if err != nil {
 		cs.logger.Error("failed to open WAL", "file", walFile, "err", err)
 		return nil, err
@@ -828,12 +861,12 @@ func (cs *State) updateToState(state sm.State) {
 		// to be gathered for the first block.
 		// And alternative solution that relies on clocks:
 		// cs.StartTime = state.LastBlockTime.Add(timeoutCommit)
-		cs.StartTime = cs.commitTime(tmtime.Now())
+		cs.StartTime = cs.commitTime(state, tmtime.Now())
 	} else {
-		cs.StartTime = cs.commitTime(cs.CommitTime)
+		cs.StartTime = cs.commitTime(state, cs.CommitTime)
 	}
 
-	cs.Validators = validators
+	cs.Validators = cs.selectProposer(validators, height, 0)
 	cs.Proposal = nil
 	cs.ProposalReceiveTime = time.Time{}
 	cs.ProposalBlock = nil
@@ -1044,6 +1077,28 @@ func (cs *State) handleMsg(ctx context.Context, mi msgInfo) {
 			err = nil
 		}
 
+	case *BlockParityPartMessage:
+		// as for BlockPartMessage, yield the lock before handling a proposal
+		// completed by reconstruction
+		added, err = cs.addProposalBlockParityPart(msg, peerID)
+		cs.mtx.Unlock()
+
+		cs.mtx.Lock()
+		if added && cs.ProposalBlockParts.IsComplete() {
+			cs.handleCompleteProposal(ctx, msg.Height)
+		}
+		if added {
+			select {
+			case cs.statsMsgQueue <- mi:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if errors.Is(err, types.ErrPartSetInvalidParity) && peerID != "" {
+			cs.evsw.FireEvent(eventInvalidParityPartValue, invalidParityPart{PeerID: peerID, Err: err})
+		}
+
 	case *VoteMessage:
 		// attempt to add the vote and dupeout the validator if its a duplicate signature
 		// if the vote gives us a 2/3-any or 2/3-one, we transition
@@ -1206,6 +1261,7 @@ func (cs *State) enterNewRound(ctx context.Context, height int64, round int32) {
 			panic(err)
 		}
 		validators.IncrementProposerPriority(r)
+		validators = cs.selectProposer(validators, height, round)
 	}
 
 	// Setup new round
@@ -1300,6 +1356,7 @@ func (cs *State) enterPropose(ctx context.Context, height int64, round int32) {
 		// Done enterPropose:
 		cs.updateRoundStep(round, cstypes.RoundStepPropose)
 		cs.newStep()
+		cs.metrics.MarkProposeStarted()
 
 		// If we have the whole proposal + POL, then goto Prevote now.
 		// else, we'll enterPrevote when the rest of the proposal is received (in AddProposalBlockPart),
@@ -1310,7 +1367,7 @@ func (cs *State) enterPropose(ctx context.Context, height int64, round int32) {
 	}()
 
 	// If we don't get the proposal and all block parts quick enough, enterPrevote
-	cs.scheduleTimeout(cs.proposeTimeout(round), height, round, cstypes.RoundStepPropose)
+	cs.scheduleTimeout(cs.withJitter(cs.proposeTimeout(round)), height, round, cstypes.RoundStepPropose)
 
 	// Nothing more to do if we're not a validator
 	if cs.privValidator == nil {
@@ -1346,6 +1403,31 @@ func (cs *State) enterPropose(ctx context.Context, height int64, round int32) {
 	}
 }
 
+// selectProposer returns vals with its proposer set to the one chosen by the
+// proposer selector for the given height and round. vals is copied if the
+// proposer changes, since it may be shared with the state.
+func (cs *State) selectProposer(vals *types.ValidatorSet, height int64, round int32) *types.ValidatorSet {
+	if vals.IsNilOrEmpty() {
+		return vals
+	}
+
+	proposer := cs.proposerSelector.SelectProposer(vals, height, round)
+	if proposer == nil {
+		panic(fmt.Sprintf("no proposer selected for height %d round %d", height, round))
+	}
+	if bytes.Equal(proposer.Address, vals.GetProposer().Address) {
+		return vals
+	}
+
+	vals = vals.Copy()
+	_, val := vals.GetByAddress(proposer.Address)
+	if val == nil {
+		panic(fmt.Sprintf("selected proposer %X is not a validator", proposer.Address))
+	}
+	vals.Proposer = val
+	return vals
+}
+
 func (cs *State) isProposer(address []byte) bool {
 	return bytes.Equal(cs.Validators.GetProposer().Address, address)
 }
@@ -1465,6 +1547,24 @@ func (cs *State) createProposalBlock(ctx context.Context) (*types.Block, error)
 	if err != nil {
 		panic(err)
 	}
+
+	if cs.proposalBlockHook != nil && !cs.proposalBlockHook(ctx, ret) {
+		cs.logger.Info("proposal block vetoed; proposing a block without mempool txs",
+			"height", cs.Height, "round", cs.Round, "num_txs", len(ret.Txs))
+		ret, err = cs.blockExec.CreateProposalBlockWithoutMempool(ctx, cs.Height, cs.state, lastExtCommit, proposerAddr)
+		if err != nil {
+			panic(err)
+		}
+
+		// The application may have added txs of its own to the fallback, so
+		// it goes through the hook too. If it's vetoed again, we propose an
+		// empty block.
+		if !cs.proposalBlockHook(ctx, ret) {
+			cs.logger.Info("fallback proposal block vetoed; proposing an empty block",
+				"height", cs.Height, "round", cs.Round, "num_txs", len(ret.Txs))
+			ret = cs.state.MakeBlock(cs.Height, nil, ret.LastCommit, ret.Evidence, proposerAddr)
+		}
+	}
 	return ret, nil
 }
 
@@ -1671,7 +1771,7 @@ func (cs *State) enterPrevoteWait(height int64, round int32) {
 	}()
 
 	// Wait for some more prevotes; enterPrecommit
-	cs.scheduleTimeout(cs.voteTimeout(round), height, round, cstypes.RoundStepPrevoteWait)
+	cs.scheduleTimeout(cs.withJitter(cs.voteTimeout(round)), height, round, cstypes.RoundStepPrevoteWait)
 }
 
 // Enter: `timeoutPrevote` after any +2/3 prevotes.
@@ -1830,7 +1930,7 @@ func (cs *State) enterPrecommitWait(height int64, round int32) {
 	}()
 
 	// wait for some more precommits; enterNewRound
-	cs.scheduleTimeout(cs.voteTimeout(round), height, round, cstypes.RoundStepPrecommitWait)
+	cs.scheduleTimeout(cs.withJitter(cs.voteTimeout(round)), height, round, cstypes.RoundStepPrecommitWait)
 }
 
 // Enter: +2/3 precommits for block
@@ -2083,6 +2183,9 @@ func (cs *State) RecordMetrics(height int64, block *types.Block) {
 				missingValidators++
 				missingValidatorsPower += val.VotingPower
 			}
+			if commitSig.BlockIDFlag != types.BlockIDFlagCommit {
+				cs.metrics.MissedPrecommits.With("validator_address", val.Address.String()).Add(1)
+			}
 
 			if bytes.Equal(val.Address, address) {
 				label := []string{
@@ -2131,6 +2234,7 @@ func (cs *State) RecordMetrics(height int64, block *types.Block) {
 	cs.metrics.TotalTxs.Add(float64(len(block.Data.Txs)))
 	cs.metrics.BlockSizeBytes.Observe(float64(block.Size()))
 	cs.metrics.CommittedHeight.Set(float64(block.Height))
+	cs.metrics.RoundsPerHeight.Observe(float64(cs.CommitRound + 1))
 }
 
 //-----------------------------------------------------------------------------
@@ -2165,6 +2269,9 @@ func (cs *State) defaultSetProposal(proposal *types.Proposal, recvTime time.Time
 	cs.Proposal = proposal
 	cs.ProposalReceiveTime = recvTime
 	cs.calculateProposalTimestampDifferenceMetric()
+	if cs.Step == cstypes.RoundStepPropose {
+		cs.metrics.MarkProposalReceived(recvTime)
+	}
 	// We don't update cs.ProposalBlockParts if it is already set.
 	// This happens if we're already in cstypes.RoundStepCommit or if there is a valid block in the current round.
 	// TODO: We can check if Proposal is for a different block as this is a sign of misbehavior!
@@ -2217,8 +2324,53 @@ func (cs *State) addProposalBlockPart(
 
 	cs.metrics.BlockGossipPartsReceived.With("matches_current", "true").Add(1)
 
+	return added, cs.checkProposalBlockParts(added)
+}
+
+// addProposalBlockParityPart adds a parity part to the proposal block parts,
+// reconstructing the missing parts once enough of them have been received.
+func (cs *State) addProposalBlockParityPart(
+	msg *BlockParityPartMessage,
+	peerID types.NodeID,
+) (added bool, err error) {
+	height, round, part := msg.Height, msg.Round, msg.Part
+
+	if cs.Height != height {
+		cs.logger.Debug("received block parity part from wrong height", "height", height, "round", round)
+		cs.metrics.BlockGossipPartsReceived.With("matches_current", "false").Add(1)
+		return false, nil
+	}
+
+	// Parity parts aren't committed to by the proposal, so only accept them
+	// for the proposal block of the current round.
+	if cs.Round != round || cs.Proposal == nil || cs.Proposal.Round != round ||
+		!cs.ProposalBlockParts.HasHeader(cs.Proposal.BlockID.PartSetHeader) {
+		cs.metrics.BlockGossipPartsReceived.With("matches_current", "false").Add(1)
+		cs.logger.Debug("received a block parity part when we are not expecting any",
+			"height", height,
+			"round", round,
+			"index", part.Index,
+			"peer", peerID,
+		)
+		return false, nil
+	}
+
+	added, err = cs.ProposalBlockParts.AddParityPart(part)
+	if err != nil {
+		cs.metrics.BlockGossipPartsReceived.With("matches_current", "false").Add(1)
+		return added, err
+	}
+
+	cs.metrics.BlockGossipPartsReceived.With("matches_current", "true").Add(1)
+
+	return added, cs.checkProposalBlockParts(added)
+}
+
+// checkProposalBlockParts checks the size of the proposal block parts after
+// adding a part, and decodes the proposal block once they're complete.
+func (cs *State) checkProposalBlockParts(added bool) error {
 	if cs.ProposalBlockParts.ByteSize() > cs.state.ConsensusParams.Block.MaxBytes {
-		return added, fmt.Errorf("total size of proposal block parts exceeds maximum block bytes (%d > %d)",
+		return fmt.Errorf("total size of proposal block parts exceeds maximum block bytes (%d > %d)",
 			cs.ProposalBlockParts.ByteSize(), cs.state.ConsensusParams.Block.MaxBytes,
 		)
 	}
@@ -2226,18 +2378,18 @@ func (cs *State) addProposalBlockPart(
 		cs.metrics.MarkBlockGossipComplete()
 		bz, err := io.ReadAll(cs.ProposalBlockParts.GetReader())
 		if err != nil {
-			return added, err
+			return err
 		}
 
 		var pbb = new(tmproto.Block)
 		err = proto.Unmarshal(bz, pbb)
 		if err != nil {
-			return added, err
+			return err
 		}
 
 		block, err := types.BlockFromProto(pbb)
 		if err != nil {
-			return added, err
+			return err
 		}
 
 		cs.ProposalBlock = block
@@ -2250,7 +2402,7 @@ func (cs *State) addProposalBlockPart(
 		}
 	}
 
-	return added, nil
+	return nil
 }
 func (cs *State) handleCompleteProposal(ctx context.Context, height int64) {
 	// Update Valid* if we can.
@@ -2796,12 +2948,34 @@ func (cs *State) voteTimeout(round int32) time.Duration {
 	) * time.Nanosecond
 }
 
-func (cs *State) commitTime(t time.Time) time.Time {
-	c := cs.state.ConsensusParams.Timeout.Commit
+// withJitter adds a random duration of up to TimeoutJitter to the timeout d, so
+// that the validators don't all time out at the same time.
+func (cs *State) withJitter(d time.Duration) time.Duration {
+	if cs.config.TimeoutJitter <= 0 {
+		return d
+	}
+	return d + time.Duration(mrand.Int63n(int64(cs.config.TimeoutJitter)+1)) // nolint:gosec
+}
+
+// commitTime returns when the height following the last block of state starts,
+// given the time t at which that block was committed.
+func (cs *State) commitTime(state sm.State, t time.Time) time.Time {
+	c := state.ConsensusParams.Timeout.Commit
 	if cs.config.UnsafeCommitTimeoutOverride != 0 {
-		c = cs.config.UnsafeProposeTimeoutOverride
+		c = cs.config.UnsafeCommitTimeoutOverride
+	}
+	if cs.config.SkipCommitTimeout {
+		c = 0
+	}
+	start := t.Add(c)
+
+	// Wait longer if the last block is more recent than the target interval.
+	if cs.config.TargetBlockInterval > 0 && state.LastBlockHeight > 0 {
+		if target := state.LastBlockTime.Add(cs.config.TargetBlockInterval); target.After(start) {
+			start = target
+		}
 	}
-	return t.Add(c)
+	return start
 }
 
 func (cs *State) bypassCommitTimeout() bool {
@@ -16,10 +16,12 @@ import (
 	"github.com/tendermint/tendermint/crypto"
 	cstypes "github.com/tendermint/tendermint/internal/consensus/types"
 	"github.com/tendermint/tendermint/internal/eventbus"
+	"github.com/tendermint/tendermint/internal/mempool"
 	tmpubsub "github.com/tendermint/tendermint/internal/pubsub"
 	tmquery "github.com/tendermint/tendermint/internal/pubsub/query"
 	"github.com/tendermint/tendermint/internal/test/factory"
 	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	tmevents "github.com/tendermint/tendermint/libs/events"
 	"github.com/tendermint/tendermint/libs/log"
 	tmrand "github.com/tendermint/tendermint/libs/rand"
 	tmtime "github.com/tendermint/tendermint/libs/time"
@@ -148,6 +150,125 @@ func TestStateProposerSelection2(t *testing.T) {
 
 }
 
+// lastValidatorSelector always selects the last validator of the set.
+type lastValidatorSelector struct{}
+
+func (lastValidatorSelector) SelectProposer(vals *types.ValidatorSet, height int64, round int32) *types.Validator {
+	return vals.Validators[vals.Size()-1]
+}
+
+func TestStateProposerSelector(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config := configSetup(t)
+
+	cs1, _ := makeState(ctx, t, makeStateArgs{config: config})
+	vals := cs1.GetRoundState().Validators
+	defaultProposer := vals.GetProposer()
+
+	// the default selector keeps the proposer of the priority rotation
+	selected := cs1.selectProposer(vals, cs1.Height, 0)
+	require.Equal(t, defaultProposer.Address, selected.GetProposer().Address)
+
+	cs1.proposerSelector = lastValidatorSelector{}
+	last := vals.Validators[vals.Size()-1]
+	require.NotEqual(t, defaultProposer.Address, last.Address)
+
+	selected = cs1.selectProposer(vals, cs1.Height, 0)
+	require.Equal(t, last.Address, selected.GetProposer().Address)
+	// the given set is left untouched
+	require.Equal(t, defaultProposer.Address, vals.GetProposer().Address)
+
+	// the selection applies to later rounds too
+	newRoundCh := subscribe(ctx, t, cs1.eventBus, types.EventQueryNewRound)
+	startTestRound(ctx, cs1, cs1.Height, 2)
+	ensureNewRound(t, newRoundCh, cs1.Height, 2)
+	require.Equal(t, last.Address, cs1.GetRoundState().Validators.GetProposer().Address)
+}
+
+func TestStateProposalBlockHook(t *testing.T) {
+	config := configSetup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cs1, _ := makeState(ctx, t, makeStateArgs{config: config, validators: 1})
+	require.NoError(t, assertMempool(t, cs1.txNotifier).CheckTx(ctx, []byte("key=value"), nil, mempool.TxInfo{}))
+
+	// an accepted block is proposed as is
+	var hookTxs []int
+	veto := false
+	cs1.proposalBlockHook = func(ctx context.Context, block *types.Block) bool {
+		hookTxs = append(hookTxs, len(block.Txs))
+		return !veto
+	}
+	block, err := cs1.createProposalBlock(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, hookTxs)
+	require.Len(t, block.Txs, 1)
+
+	// a vetoed block is replaced by one without txs, which is hooked too
+	veto = true
+	hookTxs = nil
+	block, err = cs1.createProposalBlock(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 0}, hookTxs)
+	require.Empty(t, block.Txs)
+	require.Equal(t, cs1.Height, block.Height)
+}
+
+// injectingApplication adds its own tx to every proposal it prepares.
+type injectingApplication struct {
+	abci.Application
+	tx []byte
+}
+
+func (app *injectingApplication) PrepareProposal(_ context.Context, req *abci.RequestPrepareProposal) (*abci.ResponsePrepareProposal, error) {
+	trs := make([]*abci.TxRecord, 0, len(req.Txs)+1)
+	for _, tx := range req.Txs {
+		trs = append(trs, &abci.TxRecord{Action: abci.TxRecord_UNMODIFIED, Tx: tx})
+	}
+	trs = append(trs, &abci.TxRecord{Action: abci.TxRecord_ADDED, Tx: app.tx})
+	return &abci.ResponsePrepareProposal{TxRecords: trs}, nil
+}
+
+func TestStateProposalBlockHookPreparesFallback(t *testing.T) {
+	config := configSetup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	app := &injectingApplication{Application: kvstore.NewApplication(), tx: []byte("injected=tx")}
+	cs1, _ := makeState(ctx, t, makeStateArgs{config: config, validators: 1, application: app})
+	mempoolTx := types.Tx("key=value")
+	require.NoError(t, assertMempool(t, cs1.txNotifier).CheckTx(ctx, mempoolTx, nil, mempool.TxInfo{}))
+
+	// the fallback block drops the mempool txs, but is still prepared by
+	// the application and passed to the hook
+	var hooked []types.Txs
+	cs1.proposalBlockHook = func(ctx context.Context, block *types.Block) bool {
+		hooked = append(hooked, block.Txs)
+		return block.Txs.Index(mempoolTx) < 0
+	}
+	block, err := cs1.createProposalBlock(ctx)
+	require.NoError(t, err)
+	require.Len(t, hooked, 2)
+	require.Equal(t, types.Txs{types.Tx(app.tx)}, block.Txs)
+	require.Equal(t, 1, assertMempool(t, cs1.txNotifier).Size())
+
+	// if the fallback is vetoed too, the block is empty, even though the
+	// application injected a tx into it
+	hooked = nil
+	cs1.proposalBlockHook = func(ctx context.Context, block *types.Block) bool {
+		hooked = append(hooked, block.Txs)
+		return false
+	}
+	block, err = cs1.createProposalBlock(ctx)
+	require.NoError(t, err)
+	require.Len(t, hooked, 2)
+	require.Equal(t, types.Txs{types.Tx(app.tx)}, hooked[1])
+	require.Empty(t, block.Txs)
+	require.Equal(t, cs1.Height, block.Height)
+}
+
 // a non-validator should timeout into the prevote round
 func TestStateEnterProposeNoPrivValidator(t *testing.T) {
 	config := configSetup(t)
@@ -2913,6 +3034,79 @@ func TestStateOutputsBlockPartsStats(t *testing.T) {
 
 }
 
+func TestStateBlockParityPartCorrupted(t *testing.T) {
+	config := configSetup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cs, vss := makeState(ctx, t, makeStateArgs{config: config, validators: 1})
+	peerID := types.NodeID("aa")
+
+	invalidCh := make(chan invalidParityPart, 1)
+	err := cs.evsw.AddListenerForEvent("test", eventInvalidParityPartValue, func(data tmevents.EventData) error {
+		invalidCh <- data.(invalidParityPart)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// split the block into enough small parts to have parity parts
+	block, err := cs.createProposalBlock(ctx)
+	require.NoError(t, err)
+	parts, err := block.MakePartSet(64)
+	require.NoError(t, err)
+	total := int(parts.Total())
+	parity, err := parts.ParityParts()
+	require.NoError(t, err)
+	require.Greater(t, len(parity), 1)
+
+	blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}
+	proposal := types.NewProposal(cs.Height, cs.Round, -1, blockID, block.Header.Time)
+	p := proposal.ToProto()
+	require.NoError(t, vss[0].SignProposal(ctx, cs.state.ChainID, p))
+	proposal.Signature = p.Signature
+	cs.handleMsg(ctx, msgInfo{&ProposalMessage{Proposal: proposal}, peerID, tmtime.Now()})
+	require.NotNil(t, cs.Proposal)
+
+	// parity parts for another round are ignored
+	added, err := cs.addProposalBlockParityPart(&BlockParityPartMessage{
+		Height: cs.Height,
+		Round:  cs.Round + 1,
+		Part:   parity[0],
+	}, peerID)
+	require.NoError(t, err)
+	require.False(t, added)
+
+	// reconstruct from the first data parts and the parity parts, one of
+	// which is corrupted
+	numData := total - len(parity)
+	for i := 0; i < numData; i++ {
+		cs.handleMsg(ctx, msgInfo{&BlockPartMessage{Height: cs.Height, Round: cs.Round, Part: parts.GetPart(i)}, peerID, tmtime.Now()})
+	}
+	corrupted := *parity[0]
+	corrupted.Bytes = tmrand.Bytes(len(parity[0].Bytes))
+	cs.handleMsg(ctx, msgInfo{&BlockParityPartMessage{Height: cs.Height, Round: cs.Round, Part: &corrupted}, peerID, tmtime.Now()})
+	for _, pp := range parity[1:] {
+		cs.handleMsg(ctx, msgInfo{&BlockParityPartMessage{Height: cs.Height, Round: cs.Round, Part: pp}, peerID, tmtime.Now()})
+	}
+	require.False(t, cs.ProposalBlockParts.IsComplete())
+
+	select {
+	case ev := <-invalidCh:
+		require.Equal(t, peerID, ev.PeerID)
+		require.ErrorIs(t, ev.Err, types.ErrPartSetInvalidParity)
+	default:
+		t.Fatal("expected the peer that sent the invalid parity part to be reported")
+	}
+
+	// the block still completes from the remaining data parts
+	for i := numData; i < total; i++ {
+		cs.handleMsg(ctx, msgInfo{&BlockPartMessage{Height: cs.Height, Round: cs.Round, Part: parts.GetPart(i)}, peerID, tmtime.Now()})
+	}
+	require.True(t, cs.ProposalBlockParts.IsComplete())
+	require.NotNil(t, cs.ProposalBlock)
+	require.Equal(t, block.Hash(), cs.ProposalBlock.Hash())
+}
+
 func TestStateOutputVoteStats(t *testing.T) {
 	config := configSetup(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -2994,6 +3188,49 @@ func TestSignSameVoteTwice(t *testing.T) {
 // TestStateTimestamp_ProposalNotMatch tests that a validator does not prevote a
 // proposed block if the timestamp in the block does not matche the timestamp in the
 // corresponding proposal message.
+func TestStateCommitTime(t *testing.T) {
+	config := configSetup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cs, _ := makeState(ctx, t, makeStateArgs{config: config, validators: 1})
+	state := cs.GetState()
+	state.LastBlockHeight = 1
+	state.ConsensusParams.Timeout.Commit = time.Second
+
+	commit := tmtime.Now()
+	state.LastBlockTime = commit.Add(-500 * time.Millisecond)
+	assert.Equal(t, commit.Add(time.Second), cs.commitTime(state, commit))
+
+	cs.config.SkipCommitTimeout = true
+	assert.Equal(t, commit, cs.commitTime(state, commit))
+
+	// the commit wait is stretched to the target interval
+	cs.config.TargetBlockInterval = 2 * time.Second
+	assert.Equal(t, state.LastBlockTime.Add(2*time.Second), cs.commitTime(state, commit))
+
+	// but never shortened
+	cs.config.SkipCommitTimeout = false
+	cs.config.TargetBlockInterval = 1200 * time.Millisecond
+	assert.Equal(t, commit.Add(time.Second), cs.commitTime(state, commit))
+}
+
+func TestStateTimeoutJitter(t *testing.T) {
+	config := configSetup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cs, _ := makeState(ctx, t, makeStateArgs{config: config, validators: 1})
+	assert.Equal(t, time.Second, cs.withJitter(time.Second))
+
+	cs.config.TimeoutJitter = 100 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		d := cs.withJitter(time.Second)
+		assert.GreaterOrEqual(t, d, time.Second)
+		assert.LessOrEqual(t, d, time.Second+100*time.Millisecond)
+	}
+}
+
 func TestStateTimestamp_ProposalNotMatch(t *testing.T) {
 	config := configSetup(t)
 	ctx, cancel := context.WithCancel(context.Background())
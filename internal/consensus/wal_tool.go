@@ -0,0 +1,189 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	auto "github.com/tendermint/tendermint/internal/libs/autofile"
+	"github.com/tendermint/tendermint/libs/log"
+	tmos "github.com/tendermint/tendermint/libs/os"
+)
+
+// The functions below let operators inspect and repair a WAL offline, i.e.
+// while the node is stopped. They're used by the `tendermint wal` commands.
+
+// WALSummary summarizes the records of a WAL, across all of its files.
+type WALSummary struct {
+	Records int
+	// Heights of the first and last EndHeightMessage, -1 if none.
+	FirstEndHeight int64
+	LastEndHeight  int64
+	// Times of the first and last records.
+	FirstTime time.Time
+	LastTime  time.Time
+	// Err is the error which stopped decoding before the end of the WAL, if
+	// any. It's a DataCorruptionError if a record is corrupted.
+	Err error
+}
+
+// WalkWAL decodes the records of the WAL with the given head file, from the
+// oldest file to the head, and calls fn for each of them. Rotated files,
+// including compressed ones, are read transparently. It stops at the first
+// error returned by fn or by the decoder, and returns it.
+func WalkWAL(ctx context.Context, walFile string, fn func(*TimedWALMessage) error) error {
+	if _, err := os.Stat(walFile); err != nil {
+		return err
+	}
+
+	group, err := auto.OpenGroup(ctx, log.NewNopLogger(), walFile)
+	if err != nil {
+		return err
+	}
+	defer group.Close()
+
+	gr, err := group.NewReader(group.MinIndex())
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	dec := NewWALDecoder(gr)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		msg, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// InspectWAL summarizes the records of the WAL with the given head file. A
+// corrupted record doesn't fail it, but is reported in WALSummary.Err.
+func InspectWAL(ctx context.Context, walFile string) (*WALSummary, error) {
+	summary := &WALSummary{FirstEndHeight: -1, LastEndHeight: -1}
+	err := WalkWAL(ctx, walFile, func(msg *TimedWALMessage) error {
+		if summary.Records == 0 {
+			summary.FirstTime = msg.Time
+		}
+		summary.Records++
+		summary.LastTime = msg.Time
+		if m, ok := msg.Msg.(EndHeightMessage); ok {
+			if summary.FirstEndHeight == -1 {
+				summary.FirstEndHeight = m.Height
+			}
+			summary.LastEndHeight = m.Height
+		}
+		return nil
+	})
+	if IsDataCorruptionError(err) {
+		summary.Err = err
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// WALReplayMessages returns the records of the WAL with the given head file
+// which follow the EndHeightMessage for height, i.e. the records replayed by
+// consensus when starting at height+1. If height is negative, the records
+// following the last EndHeightMessage are returned. found is false if there is
+// no such EndHeightMessage.
+func WALReplayMessages(ctx context.Context, walFile string, height int64) (msgs []*TimedWALMessage, found bool, err error) {
+	err = WalkWAL(ctx, walFile, func(msg *TimedWALMessage) error {
+		if m, ok := msg.Msg.(EndHeightMessage); ok {
+			if height < 0 || m.Height == height {
+				msgs, found = nil, true
+				return nil
+			}
+			if found {
+				// the next height starts here
+				return io.EOF
+			}
+		}
+		if found {
+			msgs = append(msgs, msg)
+		}
+		return nil
+	})
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return msgs, found, nil
+}
+
+// TruncateWALFile truncates the WAL head file after its last record that can
+// be decoded, dropping a corrupted or partially written tail, e.g. after a
+// crash. The original file is first copied to <walFile>.CORRUPTED. It returns
+// the number of bytes removed, 0 if the file wasn't corrupted.
+func TruncateWALFile(walFile string) (int64, error) {
+	f, err := os.Open(walFile)
+	if err != nil {
+		return 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return 0, err
+	}
+
+	cr := &countingReader{r: f}
+	dec := NewWALDecoder(cr)
+	var validSize int64
+	for {
+		_, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if !IsDataCorruptionError(err) {
+				f.Close()
+				return 0, err
+			}
+			break
+		}
+		validSize = cr.n
+	}
+	f.Close()
+
+	if validSize == info.Size() {
+		return 0, nil
+	}
+
+	corruptedFile := fmt.Sprintf("%s.CORRUPTED", walFile)
+	if err := tmos.CopyFile(walFile, corruptedFile); err != nil {
+		return 0, err
+	}
+	if err := os.Truncate(walFile, validSize); err != nil {
+		return 0, err
+	}
+	return info.Size() - validSize, nil
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
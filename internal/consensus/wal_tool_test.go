@@ -0,0 +1,98 @@
+package consensus
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/internal/consensus/types"
+)
+
+func TestWALTools(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	walFile := filepath.Join(t.TempDir(), "wal")
+	f, err := os.Create(walFile)
+	require.NoError(t, err)
+	enc := NewWALEncoder(f)
+
+	now := time.Now().UTC()
+	msgs := []WALMessage{
+		EndHeightMessage{Height: 0},
+		timeoutInfo{Duration: time.Second, Height: 1, Round: 0, Step: types.RoundStepPropose},
+		EndHeightMessage{Height: 1},
+		timeoutInfo{Duration: time.Second, Height: 2, Round: 0, Step: types.RoundStepPropose},
+		timeoutInfo{Duration: time.Second, Height: 2, Round: 1, Step: types.RoundStepPropose},
+	}
+	for i, msg := range msgs {
+		require.NoError(t, enc.Encode(&TimedWALMessage{Time: now.Add(time.Duration(i) * time.Second), Msg: msg}))
+	}
+	info, err := f.Stat()
+	require.NoError(t, err)
+	goodSize := info.Size()
+
+	summary, err := InspectWAL(ctx, walFile)
+	require.NoError(t, err)
+	assert.Equal(t, 5, summary.Records)
+	assert.EqualValues(t, 0, summary.FirstEndHeight)
+	assert.EqualValues(t, 1, summary.LastEndHeight)
+	assert.True(t, summary.FirstTime.Equal(now))
+	assert.True(t, summary.LastTime.Equal(now.Add(4*time.Second)))
+	assert.NoError(t, summary.Err)
+
+	// the records after the last end height
+	replayed, found, err := WALReplayMessages(ctx, walFile, -1)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Len(t, replayed, 2)
+	assert.Equal(t, msgs[3], replayed[0].Msg)
+
+	// the records of a given height
+	replayed, found, err = WALReplayMessages(ctx, walFile, 0)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Len(t, replayed, 1)
+	assert.Equal(t, msgs[1], replayed[0].Msg)
+
+	_, found, err = WALReplayMessages(ctx, walFile, 5)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	// nothing to truncate
+	removed, err := TruncateWALFile(walFile)
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+
+	// a partially written record is truncated
+	_, err = f.Write([]byte{0x01, 0x02, 0x03, 0x04, 0x00, 0x00, 0x10})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	summary, err = InspectWAL(ctx, walFile)
+	require.NoError(t, err)
+	assert.Equal(t, 5, summary.Records)
+	assert.True(t, IsDataCorruptionError(summary.Err))
+
+	removed, err = TruncateWALFile(walFile)
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, removed)
+
+	info, err = os.Stat(walFile)
+	require.NoError(t, err)
+	assert.Equal(t, goodSize, info.Size())
+	_, err = os.Stat(walFile + ".CORRUPTED")
+	assert.NoError(t, err)
+
+	summary, err = InspectWAL(ctx, walFile)
+	require.NoError(t, err)
+	assert.NoError(t, summary.Err)
+
+	_, err = InspectWAL(ctx, filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
@@ -191,6 +191,34 @@ func (b *EventBus) PublishEventValidatorSetUpdates(data types.EventDataValidator
 	return b.Publish(types.EventValidatorSetUpdatesValue, data)
 }
 
+func (b *EventBus) PublishEventConsensusParamUpdates(data types.EventDataConsensusParamUpdates) error {
+	return b.Publish(types.EventConsensusParamUpdatesValue, data)
+}
+
 func (b *EventBus) PublishEventEvidenceValidated(evidence types.EventDataEvidenceValidated) error {
 	return b.Publish(types.EventEvidenceValidatedValue, evidence)
 }
+
+// PublishEventEvictedTx publishes an evicted tx event, which can be queried by
+// tx hash (TxHashKey).
+func (b *EventBus) PublishEventEvictedTx(data types.EventDataEvictedTx) error {
+	tokens := strings.Split(types.EventTypeKey, ".")
+	events := append(data.ABCIEvents(), abci.Event{
+		Type: tokens[0],
+		Attributes: []abci.EventAttribute{
+			{
+				Key:   tokens[1],
+				Value: types.EventEvictedTxValue,
+			},
+		},
+	})
+	return b.pubsub.PublishWithEvents(data, events)
+}
+
+func (b *EventBus) PublishEventMempoolFullness(data types.EventDataMempoolFullness) error {
+	return b.Publish(types.EventMempoolFullnessValue, data)
+}
+
+func (b *EventBus) PublishEventSyncProgress(data types.EventDataSyncProgress) error {
+	return b.Publish(types.EventSyncProgressValue, data)
+}
@@ -422,6 +422,7 @@ func TestEventBusPublish(t *testing.T) {
 	require.NoError(t, eventBus.PublishEventRelock(types.EventDataRoundState{}))
 	require.NoError(t, eventBus.PublishEventLock(types.EventDataRoundState{}))
 	require.NoError(t, eventBus.PublishEventValidatorSetUpdates(types.EventDataValidatorSetUpdates{}))
+	require.NoError(t, eventBus.PublishEventConsensusParamUpdates(types.EventDataConsensusParamUpdates{}))
 	require.NoError(t, eventBus.PublishEventBlockSyncStatus(types.EventDataBlockSyncStatus{}))
 	require.NoError(t, eventBus.PublishEventStateSyncStatus(types.EventDataStateSyncStatus{}))
 
@@ -14,6 +14,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/golang/snappy"
+
 	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/libs/service"
 )
@@ -23,6 +25,10 @@ const (
 	defaultHeadSizeLimit      = 10 * 1024 * 1024       // 10MB
 	defaultTotalSizeLimit     = 1 * 1024 * 1024 * 1024 // 1GB
 	maxFilesToRemove          = 4                      // needs to be greater than 1
+
+	// compressedFileSuffix is appended to the name of a rotated file once it
+	// has been compressed (snappy framing format).
+	compressedFileSuffix = ".sz"
 )
 
 /*
@@ -50,6 +56,15 @@ As more files are written, the index numbers grow...
 	- ...
 	- <HeadPath>       // New head path
 
+If compression is enabled, rotated files are compressed in the background and
+renamed to <HeadPath>.NNN.sz. GroupReader reads compressed and uncompressed
+files transparently.
+
+	Dir/
+	- <HeadPath>.000.sz // First rolled file, compressed
+	- <HeadPath>.001    // Second rolled file, not compressed yet
+	- <HeadPath>        // New head path
+
 The Group can also be used to binary-search for some line,
 assuming that marker lines are written occasionally.
 */
@@ -66,6 +81,7 @@ type Group struct {
 	headSizeLimit      int64
 	totalSizeLimit     int64
 	groupCheckDuration time.Duration
+	compress           bool
 	minIndex           int // Includes head
 	maxIndex           int // Includes head, where Head will move to
 
@@ -131,6 +147,14 @@ func GroupTotalSizeLimit(limit int64) func(*Group) {
 	}
 }
 
+// GroupCompressRotated enables compression of rotated files. The head is never
+// compressed.
+func GroupCompressRotated(compress bool) func(*Group) {
+	return func(g *Group) {
+		g.compress = compress
+	}
+}
+
 // OnStart implements service.Service by starting the goroutine that checks file
 // and group limits.
 func (g *Group) OnStart(ctx context.Context) error {
@@ -234,6 +258,7 @@ func (g *Group) processTicks(ctx context.Context) {
 			return
 		case <-g.ticker.C:
 			g.checkHeadSizeLimit(ctx)
+			g.compressRotatedFiles(ctx)
 			g.checkTotalSizeLimit(ctx)
 		}
 	}
@@ -286,6 +311,10 @@ func (g *Group) checkTotalSizeLimit(ctx context.Context) {
 
 		pathToRemove := filePathForIndex(g.Head.Path, index, gInfo.MaxIndex)
 		fInfo, err := os.Stat(pathToRemove)
+		if os.IsNotExist(err) {
+			pathToRemove += compressedFileSuffix
+			fInfo, err = os.Stat(pathToRemove)
+		}
 		if err != nil {
 			g.logger.Error("Failed to fetch info for file", "file", pathToRemove)
 			continue
@@ -303,6 +332,81 @@ func (g *Group) checkTotalSizeLimit(ctx context.Context) {
 	}
 }
 
+// compressRotatedFiles compresses every rotated file which has not been
+// compressed yet. It's a no-op unless compression is enabled.
+// NOTE: this function is called manually in tests.
+func (g *Group) compressRotatedFiles(ctx context.Context) {
+	g.mtx.Lock()
+	compress := g.compress
+	gInfo := g.readGroupInfo()
+	g.mtx.Unlock()
+
+	if !compress {
+		return
+	}
+
+	for index := gInfo.MinIndex; index < gInfo.MaxIndex; index++ {
+		if ctx.Err() != nil {
+			return
+		}
+
+		path := filePathForIndex(g.Head.Path, index, gInfo.MaxIndex)
+		if _, err := os.Stat(path); err != nil {
+			// Either already compressed or removed.
+			continue
+		}
+
+		if err := g.compressFile(path); err != nil {
+			g.logger.Error("Failed to compress file", "file", path, "err", err)
+			return
+		}
+	}
+}
+
+// compressFile writes a compressed copy of the rotated file at path and then
+// replaces the original with it. Rotated files are never written to, so the
+// (potentially slow) compression is done without holding g.mtx.
+func (g *Group) compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmpPath := path + compressedFileSuffix + ".tmp"
+	dst, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, autoFilePerms)
+	if err != nil {
+		return err
+	}
+
+	zw := snappy.NewBufferedWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	// Lock on Group so that readers see either the original or the
+	// compressed file.
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	if err := os.Rename(tmpPath, path+compressedFileSuffix); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
 // rotateFile causes group to close the current head and assign it
 // some index. Panics if it encounters an error.
 func (g *Group) rotateFile(ctx context.Context) {
@@ -397,7 +501,7 @@ func (g *Group) readGroupInfo() GroupInfo {
 		} else if strings.HasPrefix(fileInfo.Name(), headBase) {
 			fileSize := fileInfo.Size()
 			totalSize += fileSize
-			indexedFilePattern := regexp.MustCompile(`^.+\.([0-9]{3,})$`)
+			indexedFilePattern := regexp.MustCompile(`^.+\.([0-9]{3,})(\.sz)?$`)
 			submatch := indexedFilePattern.FindSubmatch([]byte(fileInfo.Name()))
 			if len(submatch) != 0 {
 				// Matches
@@ -524,11 +628,26 @@ func (gr *GroupReader) openFile(index int) error {
 	}
 
 	curFilePath := filePathForIndex(gr.Head.Path, index, gr.Group.maxIndex)
+	compressed := false
+	if index < gr.Group.maxIndex {
+		// A rotated file might have been compressed.
+		if _, err := os.Stat(curFilePath); os.IsNotExist(err) {
+			if _, err := os.Stat(curFilePath + compressedFileSuffix); err == nil {
+				curFilePath += compressedFileSuffix
+				compressed = true
+			}
+		}
+	}
 	curFile, err := os.OpenFile(curFilePath, os.O_RDONLY|os.O_CREATE, autoFilePerms)
 	if err != nil {
 		return err
 	}
-	curReader := bufio.NewReader(curFile)
+	var curReader *bufio.Reader
+	if compressed {
+		curReader = bufio.NewReader(snappy.NewReader(curFile))
+	} else {
+		curReader = bufio.NewReader(curFile)
+	}
 
 	// Update gr.cur*
 	if gr.curFile != nil {
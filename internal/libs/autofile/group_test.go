@@ -319,3 +319,57 @@ func TestMaxIndex(t *testing.T) {
 	// Cleanup
 	destroyTestGroup(t, g)
 }
+
+func TestCompressRotatedFiles(t *testing.T) {
+	logger := log.NewNopLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := createTestGroupWithHeadSizeLimit(ctx, t, logger, 0)
+	GroupCompressRotated(true)(g)
+
+	// Create and rotate files
+	lines := []string{"Line 1", "Line 2", "Line 3"}
+	for _, line := range lines {
+		require.NoError(t, g.WriteLine(line))
+		require.NoError(t, g.FlushAndSync())
+		g.rotateFile(ctx)
+	}
+	require.NoError(t, g.WriteLine("Line 4"))
+	require.NoError(t, g.FlushAndSync())
+
+	g.compressRotatedFiles(ctx)
+
+	// Rotated files are replaced by compressed ones, the head is untouched.
+	for _, path := range []string{g.Head.Path + ".000", g.Head.Path + ".001", g.Head.Path + ".002"} {
+		_, err := os.Stat(path)
+		assert.True(t, os.IsNotExist(err), "expected %s to be removed", path)
+		_, err = os.Stat(path + compressedFileSuffix)
+		assert.NoError(t, err)
+	}
+	body, err := os.ReadFile(g.Head.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "Line 4\n", string(body))
+
+	gInfo := g.ReadGroupInfo()
+	assert.Equal(t, 0, gInfo.MinIndex)
+	assert.Equal(t, 3, gInfo.MaxIndex)
+
+	// Reading across compressed files and the head.
+	gr, err := g.NewReader(0)
+	require.NoError(t, err)
+	read, err := io.ReadAll(gr)
+	require.NoError(t, err)
+	assert.Equal(t, "Line 1\nLine 2\nLine 3\nLine 4\n", string(read))
+	require.NoError(t, gr.Close())
+
+	// Compressed files are removed once the total size limit is exceeded.
+	GroupTotalSizeLimit(gInfo.TotalSize - 1)(g)
+	g.checkTotalSizeLimit(ctx)
+	_, err = os.Stat(g.Head.Path + ".000" + compressedFileSuffix)
+	assert.True(t, os.IsNotExist(err))
+	assert.Equal(t, 1, g.ReadGroupInfo().MinIndex)
+
+	// Cleanup
+	destroyTestGroup(t, g)
+}
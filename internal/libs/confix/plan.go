@@ -234,4 +234,43 @@ var plan = transform.Plan{
 		T:       transform.Remove(parser.Key{"p2p", "seeds"}),
 		ErrorOK: true,
 	},
+	{
+		// The [blocksync] section removed above is back, with new settings.
+		Desc: "Add [blocksync] switch to consensus settings",
+		T: transform.Func(func(_ context.Context, doc *tomledit.Document) error {
+			var tab *tomledit.Section
+			if found := transform.FindTable(doc, "blocksync"); found != nil {
+				tab = found.Section
+			} else {
+				tab = &tomledit.Section{Heading: &parser.Heading{Name: parser.Key{"blocksync"}}}
+				doc.Sections = append(doc.Sections, tab)
+			}
+			transform.InsertMapping(tab, &parser.KeyValue{
+				Block: parser.Comments{
+					"Block sync switches to consensus once the next height to sync is within",
+					"switch-height-delta of the highest height reported by its peers. It must",
+					"be at least 1, since syncing a block requires the next one.",
+				},
+				Name:  parser.Key{"switch-height-delta"},
+				Value: parser.MustValue("1"),
+			}, false)
+			transform.InsertMapping(tab, &parser.KeyValue{
+				Block: parser.Comments{
+					"The minimum number of peers block sync must have to consider itself caught",
+					"up. Nodes with few peers may switch too early, when their only peer lags.",
+				},
+				Name:  parser.Key{"switch-min-peers"},
+				Value: parser.MustValue("1"),
+			}, false)
+			transform.InsertMapping(tab, &parser.KeyValue{
+				Block: parser.Comments{
+					"Block sync switches to consensus if no block was synced for this long, even",
+					"if it isn't caught up.",
+				},
+				Name:  parser.Key{"switch-stall-timeout"},
+				Value: parser.MustValue(`"60s"`),
+			}, false)
+			return nil
+		}),
+	},
 }
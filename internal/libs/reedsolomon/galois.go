@@ -0,0 +1,144 @@
+package reedsolomon
+
+import "errors"
+
+// generatorPolynomial is the irreducible polynomial x^8+x^4+x^3+x^2+1 used
+// to construct GF(2^8).
+const generatorPolynomial = 0x11d
+
+var (
+	expTable [2 * 255]byte
+	logTable [256]byte
+	mulTable [256][256]byte
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		expTable[i] = byte(x)
+		expTable[i+255] = byte(x)
+		logTable[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= generatorPolynomial
+		}
+	}
+	for a := 0; a < 256; a++ {
+		for b := 0; b < 256; b++ {
+			mulTable[a][b] = gfMul(byte(a), byte(b))
+		}
+	}
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+int(logTable[b])]
+}
+
+// gfInv returns the multiplicative inverse of a, which must not be zero.
+func gfInv(a byte) byte {
+	return expTable[255-int(logTable[a])]
+}
+
+func gfExp(a byte, n int) byte {
+	if n == 0 {
+		return 1
+	}
+	if a == 0 {
+		return 0
+	}
+	return expTable[(int(logTable[a])*n)%255]
+}
+
+//-----------------------------------------------------------------------------
+
+var errSingularMatrix = errors.New("matrix is singular")
+
+// matrix is a row-major matrix over GF(2^8).
+type matrix [][]byte
+
+func newMatrix(rows, cols int) matrix {
+	m := make(matrix, rows)
+	for r := range m {
+		m[r] = make([]byte, cols)
+	}
+	return m
+}
+
+// vandermonde returns the rows x cols matrix with m[r][c] = r^c.
+func vandermonde(rows, cols int) matrix {
+	m := newMatrix(rows, cols)
+	for r := range m {
+		for c := range m[r] {
+			m[r][c] = gfExp(byte(r), c)
+		}
+	}
+	return m
+}
+
+func (m matrix) multiply(o matrix) matrix {
+	out := newMatrix(len(m), len(o[0]))
+	for r := range out {
+		for c := range out[r] {
+			var v byte
+			for i := range o {
+				v ^= gfMul(m[r][i], o[i][c])
+			}
+			out[r][c] = v
+		}
+	}
+	return out
+}
+
+// invert returns the inverse of the square matrix m using Gauss-Jordan
+// elimination. m is not modified.
+func (m matrix) invert() (matrix, error) {
+	n := len(m)
+	work := newMatrix(n, 2*n)
+	for r := range m {
+		copy(work[r], m[r])
+		work[r][n+r] = 1
+	}
+
+	for c := 0; c < n; c++ {
+		// Find a pivot.
+		pivot := -1
+		for r := c; r < n; r++ {
+			if work[r][c] != 0 {
+				pivot = r
+				break
+			}
+		}
+		if pivot == -1 {
+			return nil, errSingularMatrix
+		}
+		work[c], work[pivot] = work[pivot], work[c]
+
+		// Scale the pivot row to 1.
+		if v := work[c][c]; v != 1 {
+			inv := gfInv(v)
+			for i := range work[c] {
+				work[c][i] = gfMul(work[c][i], inv)
+			}
+		}
+
+		// Eliminate the column from all other rows.
+		for r := 0; r < n; r++ {
+			if r == c || work[r][c] == 0 {
+				continue
+			}
+			f := work[r][c]
+			for i := range work[r] {
+				work[r][i] ^= gfMul(f, work[c][i])
+			}
+		}
+	}
+
+	out := make(matrix, n)
+	for r := range work {
+		out[r] = work[r][n:]
+	}
+	return out, nil
+}
@@ -0,0 +1,176 @@
+// Package reedsolomon implements systematic Reed-Solomon erasure coding over
+// GF(2^8).
+//
+// An Encoder splits the shards of a message into data shards and parity
+// shards. The original data can be recovered from any dataShards of the
+// dataShards+parityShards shards. At most 256 shards are supported.
+package reedsolomon
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxShards is the maximum total number of shards supported by an Encoder.
+const MaxShards = 256
+
+var (
+	// ErrTooFewShards is returned by Reconstruct if fewer than dataShards
+	// shards are present.
+	ErrTooFewShards = errors.New("too few shards given to reconstruct")
+	// ErrShardSize is returned if the shards are empty or of different sizes.
+	ErrShardSize = errors.New("shards must be non-empty and of equal size")
+)
+
+// Encoder encodes and reconstructs shards for a fixed number of data and
+// parity shards.
+type Encoder struct {
+	dataShards   int
+	parityShards int
+	// matrix is the (dataShards+parityShards) x dataShards encoding matrix.
+	// Its first dataShards rows form the identity matrix.
+	matrix matrix
+}
+
+// New returns an Encoder for the given number of data and parity shards.
+func New(dataShards, parityShards int) (*Encoder, error) {
+	if dataShards <= 0 {
+		return nil, fmt.Errorf("data shards must be positive, got %d", dataShards)
+	}
+	if parityShards < 0 {
+		return nil, fmt.Errorf("parity shards can't be negative, got %d", parityShards)
+	}
+	if dataShards+parityShards > MaxShards {
+		return nil, fmt.Errorf("too many shards: %d, max: %d", dataShards+parityShards, MaxShards)
+	}
+
+	// Any dataShards rows of a Vandermonde matrix are linearly independent.
+	// Multiplying by the inverse of its top square keeps that property and
+	// makes the code systematic.
+	vm := vandermonde(dataShards+parityShards, dataShards)
+	top, err := vm[:dataShards].invert()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Encoder{
+		dataShards:   dataShards,
+		parityShards: parityShards,
+		matrix:       vm.multiply(top),
+	}, nil
+}
+
+// DataShards returns the number of data shards.
+func (e *Encoder) DataShards() int { return e.dataShards }
+
+// ParityShards returns the number of parity shards.
+func (e *Encoder) ParityShards() int { return e.parityShards }
+
+// Encode computes the parity shards from the data shards. shards must have
+// dataShards+parityShards entries; the data shards must be of equal size.
+// Parity shards are allocated if they are nil or of the wrong size.
+func (e *Encoder) Encode(shards [][]byte) error {
+	if len(shards) != e.dataShards+e.parityShards {
+		return fmt.Errorf("expected %d shards, got %d", e.dataShards+e.parityShards, len(shards))
+	}
+	size, err := shardSize(shards[:e.dataShards], false)
+	if err != nil {
+		return err
+	}
+
+	for i := e.dataShards; i < len(shards); i++ {
+		if len(shards[i]) != size {
+			shards[i] = make([]byte, size)
+		}
+		codeShard(e.matrix[i], shards[:e.dataShards], shards[i])
+	}
+	return nil
+}
+
+// Reconstruct recovers the missing shards, which must be given as nil
+// entries. At least dataShards shards must be present.
+func (e *Encoder) Reconstruct(shards [][]byte) error {
+	if len(shards) != e.dataShards+e.parityShards {
+		return fmt.Errorf("expected %d shards, got %d", e.dataShards+e.parityShards, len(shards))
+	}
+	size, err := shardSize(shards, true)
+	if err != nil {
+		return err
+	}
+
+	// Pick the first dataShards present shards and the matching rows of the
+	// encoding matrix.
+	var (
+		present = make([][]byte, 0, e.dataShards)
+		rows    = make(matrix, 0, e.dataShards)
+		missing = false
+	)
+	for i, shard := range shards {
+		if shard == nil {
+			missing = true
+			continue
+		}
+		if len(present) < e.dataShards {
+			present = append(present, shard)
+			rows = append(rows, e.matrix[i])
+		}
+	}
+	if len(present) < e.dataShards {
+		return ErrTooFewShards
+	}
+	if !missing {
+		return nil
+	}
+
+	decode, err := rows.invert()
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < e.dataShards; i++ {
+		if shards[i] == nil {
+			shards[i] = make([]byte, size)
+			codeShard(decode[i], present, shards[i])
+		}
+	}
+	for i := e.dataShards; i < len(shards); i++ {
+		if shards[i] == nil {
+			shards[i] = make([]byte, size)
+			codeShard(e.matrix[i], shards[:e.dataShards], shards[i])
+		}
+	}
+	return nil
+}
+
+// shardSize returns the common size of the given shards. If allowNil is true,
+// nil shards are skipped.
+func shardSize(shards [][]byte, allowNil bool) (int, error) {
+	size := 0
+	for _, shard := range shards {
+		if shard == nil && allowNil {
+			continue
+		}
+		if len(shard) == 0 || (size != 0 && len(shard) != size) {
+			return 0, ErrShardSize
+		}
+		size = len(shard)
+	}
+	if size == 0 {
+		return 0, ErrShardSize
+	}
+	return size, nil
+}
+
+// codeShard sets out to the linear combination of inputs with the given
+// coefficients.
+func codeShard(coefficients []byte, inputs [][]byte, out []byte) {
+	for i := range out {
+		out[i] = 0
+	}
+	for j, in := range inputs {
+		mt := &mulTable[coefficients[j]]
+		for i, b := range in {
+			out[i] ^= mt[b]
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package reedsolomon
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		data, parity int
+		expectErr    bool
+	}{
+		{1, 0, false},
+		{10, 5, false},
+		{200, 56, false},
+		{0, 1, true},
+		{-1, 1, true},
+		{1, -1, true},
+		{200, 57, true},
+	}
+	for _, tc := range testCases {
+		_, err := New(tc.data, tc.parity)
+		if tc.expectErr {
+			assert.Error(t, err, "data: %d, parity: %d", tc.data, tc.parity)
+		} else {
+			assert.NoError(t, err, "data: %d, parity: %d", tc.data, tc.parity)
+		}
+	}
+}
+
+func TestEncodeReconstruct(t *testing.T) {
+	testCases := []struct {
+		data, parity, size int
+	}{
+		{1, 1, 1},
+		{4, 2, 100},
+		{10, 5, 1000},
+		{171, 85, 64},
+	}
+	for _, tc := range testCases {
+		enc, err := New(tc.data, tc.parity)
+		require.NoError(t, err)
+
+		shards := make([][]byte, tc.data+tc.parity)
+		for i := 0; i < tc.data; i++ {
+			shards[i] = make([]byte, tc.size)
+			rand.Read(shards[i])
+		}
+		require.NoError(t, enc.Encode(shards))
+
+		orig := make([][]byte, len(shards))
+		for i := range shards {
+			orig[i] = append([]byte(nil), shards[i]...)
+		}
+
+		// Drop as many shards as there are parity shards.
+		for _, i := range rand.Perm(len(shards))[:tc.parity] {
+			shards[i] = nil
+		}
+		require.NoError(t, enc.Reconstruct(shards))
+		assert.Equal(t, orig, shards)
+	}
+}
+
+func TestReconstructErrors(t *testing.T) {
+	enc, err := New(3, 2)
+	require.NoError(t, err)
+
+	shards := [][]byte{{1, 2}, {3, 4}, {5, 6}, nil, nil}
+	require.NoError(t, enc.Encode(shards))
+
+	shards[0], shards[1], shards[2] = nil, nil, nil
+	assert.Equal(t, ErrTooFewShards, enc.Reconstruct(shards))
+
+	shards = [][]byte{{1, 2}, {3}, {5, 6}, nil, nil}
+	assert.Equal(t, ErrShardSize, enc.Encode(shards))
+	assert.Equal(t, ErrShardSize, enc.Reconstruct(shards))
+
+	assert.Error(t, enc.Encode(shards[:4]))
+}
+
+func TestInvert(t *testing.T) {
+	m := vandermonde(5, 5)
+	inv, err := m.invert()
+	require.NoError(t, err)
+
+	identity := m.multiply(inv)
+	for r := range identity {
+		for c := range identity[r] {
+			if r == c {
+				assert.EqualValues(t, 1, identity[r][c])
+			} else {
+				assert.EqualValues(t, 0, identity[r][c])
+			}
+		}
+	}
+
+	_, err = matrix{{1, 2}, {1, 2}}.invert()
+	assert.Equal(t, errSingularMatrix, err)
+}
@@ -62,22 +62,29 @@ func (c *LRUTxCache) Reset() {
 }
 
 func (c *LRUTxCache) Push(tx types.Tx) bool {
+	added, _, _ := c.pushKey(tx.Key())
+	return added
+}
+
+// pushKey adds the given key to the cache, or marks it as the most recently
+// used if it's already present, and returns true if it was newly added. If
+// the cache was full, it also returns the least recently used key, which was
+// evicted to make room.
+func (c *LRUTxCache) pushKey(key types.TxKey) (added bool, evicted types.TxKey, ok bool) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 
-	key := tx.Key()
-
-	moved, ok := c.cacheMap[key]
-	if ok {
+	moved, exists := c.cacheMap[key]
+	if exists {
 		c.list.MoveToBack(moved)
-		return false
+		return false, evicted, false
 	}
 
 	if c.list.Len() >= c.size {
 		front := c.list.Front()
 		if front != nil {
-			frontKey := front.Value.(types.TxKey)
-			delete(c.cacheMap, frontKey)
+			evicted, ok = front.Value.(types.TxKey), true
+			delete(c.cacheMap, evicted)
 			c.list.Remove(front)
 		}
 	}
@@ -85,14 +92,18 @@ func (c *LRUTxCache) Push(tx types.Tx) bool {
 	e := c.list.PushBack(key)
 	c.cacheMap[key] = e
 
-	return true
+	return true, evicted, ok
 }
 
 func (c *LRUTxCache) Remove(tx types.Tx) {
+	c.removeKey(tx.Key())
+}
+
+// removeKey removes the given key from the cache.
+func (c *LRUTxCache) removeKey(key types.TxKey) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 
-	key := tx.Key()
 	e := c.cacheMap[key]
 	delete(c.cacheMap, key)
 
@@ -2,7 +2,9 @@ package mempool
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"sync"
@@ -13,9 +15,12 @@ import (
 	abciclient "github.com/tendermint/tendermint/abci/client"
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/internal/eventbus"
 	"github.com/tendermint/tendermint/internal/libs/clist"
 	tmstrings "github.com/tendermint/tendermint/internal/libs/strings"
 	"github.com/tendermint/tendermint/libs/log"
+	tmmath "github.com/tendermint/tendermint/libs/math"
+	tmos "github.com/tendermint/tendermint/libs/os"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -39,7 +44,9 @@ type TxMempool struct {
 	config       *config.MempoolConfig
 	proxyAppConn abciclient.Client
 	metrics      *Metrics
-	cache        TxCache // seen transactions
+	cache        TxCache            // seen transactions
+	rejected     *rejectedTxCache   // recently rejected transactions, if enabled
+	eventBus     *eventbus.EventBus // publishes evictions, if set
 
 	// Atomically-updated fields
 	txsBytes int64 // atomic: the total size of all transactions in the mempool, in bytes
@@ -50,11 +57,14 @@ type TxMempool struct {
 	txsAvailable         chan struct{} // one value sent per height when mempool is not empty
 	preCheck             PreCheckFunc
 	postCheck            PostCheckFunc
-	height               int64 // the latest height passed to Update
+	height               int64              // the latest height passed to Update
+	recheckCancel        context.CancelFunc // cancels the running recheck, if any
+	fullness             int                // the number of fullness thresholds reached
 
 	txs        *clist.CList // valid transactions (passed CheckTx)
 	txByKey    map[types.TxKey]*clist.CElement
-	txBySender map[string]*clist.CElement // for sender != ""
+	txBySender map[string][]*clist.CElement // for sender != "", in order of arrival
+	wal        *txWAL                       // records added and removed txs, if set
 }
 
 // NewTxMempool constructs a new, empty priority mempool at the specified
@@ -75,11 +85,14 @@ func NewTxMempool(
 		txs:          clist.New(),
 		mtx:          new(sync.RWMutex),
 		txByKey:      make(map[types.TxKey]*clist.CElement),
-		txBySender:   make(map[string]*clist.CElement),
+		txBySender:   make(map[string][]*clist.CElement),
 	}
 	if cfg.CacheSize > 0 {
 		txmp.cache = NewLRUTxCache(cfg.CacheSize)
 	}
+	if cfg.RejectedCacheSize > 0 {
+		txmp.rejected = newRejectedTxCache(cfg.RejectedCacheSize)
+	}
 
 	for _, opt := range options {
 		opt(txmp)
@@ -107,6 +120,13 @@ func WithMetrics(metrics *Metrics) TxMempoolOption {
 	return func(txmp *TxMempool) { txmp.metrics = metrics }
 }
 
+// WithEventBus sets the event bus on which the mempool publishes an
+// EventDataEvictedTx for each transaction it evicts, and an
+// EventDataMempoolFullness when its fullness crosses a configured threshold.
+func WithEventBus(eventBus *eventbus.EventBus) TxMempoolOption {
+	return func(txmp *TxMempool) { txmp.eventBus = eventBus }
+}
+
 // Lock obtains a write-lock on the mempool. A caller must be sure to explicitly
 // release the lock when finished.
 func (txmp *TxMempool) Lock() { txmp.mtx.Lock() }
@@ -148,6 +168,96 @@ func (txmp *TxMempool) EnableTxsAvailable() {
 	txmp.txsAvailable = make(chan struct{}, 1)
 }
 
+// InitWAL opens the mempool write-ahead log in the configured WAL directory,
+// and restores the transactions recorded in it by passing each of them to the
+// application's ABCI CheckTx method again, in the order they were originally
+// added. Transactions the application now rejects are dropped. From then on,
+// transactions added to and removed from the mempool are recorded in the WAL.
+//
+// InitWAL must be called before the mempool receives any transactions, and
+// after the application has been synchronized with the latest block.
+func (txmp *TxMempool) InitWAL(ctx context.Context) error {
+	dir := txmp.config.WalDir()
+	if err := tmos.EnsureDir(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create mempool WAL directory: %w", err)
+	}
+	path := filepath.Join(dir, walFile)
+
+	txs, err := readTxWAL(path)
+	if err != nil {
+		return fmt.Errorf("failed to read mempool WAL: %w", err)
+	}
+
+	// Replay the transactions before the WAL is set, so that they aren't
+	// recorded twice. The WAL file is left untouched until they have all been
+	// checked, so a crash during the replay loses nothing.
+	for _, tx := range txs {
+		if err := txmp.CheckTx(ctx, tx, nil, TxInfo{}); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			txmp.logger.Info("dropped transaction from mempool WAL",
+				"tx", tmstrings.LazySprintf("%X", tx.Hash()),
+				"err", err,
+			)
+		}
+	}
+
+	txmp.mtx.Lock()
+	defer txmp.mtx.Unlock()
+
+	live := txmp.walTxs()
+	if err := writeTxWAL(path, live); err != nil {
+		return fmt.Errorf("failed to write mempool WAL: %w", err)
+	}
+	txmp.wal, err = openTxWAL(path, len(live))
+	if err != nil {
+		return fmt.Errorf("failed to open mempool WAL: %w", err)
+	}
+	txmp.logger.Info("restored transactions from mempool WAL",
+		"restored", len(live),
+		"dropped", len(txs)-len(live),
+	)
+	return nil
+}
+
+// CloseWAL closes the mempool write-ahead log, if it is open. Transactions are
+// no longer recorded afterwards.
+func (txmp *TxMempool) CloseWAL() error {
+	txmp.mtx.Lock()
+	defer txmp.mtx.Unlock()
+
+	if txmp.wal == nil {
+		return nil
+	}
+	err := txmp.wal.close()
+	txmp.wal = nil
+	return err
+}
+
+// compactWAL rewrites the write-ahead log with only the transactions currently
+// in the mempool, once it holds enough records of removed transactions.
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) compactWAL() {
+	if txmp.wal == nil || txmp.wal.records-2*txmp.Size() < walCompactRecords {
+		return
+	}
+	if err := txmp.wal.rewrite(txmp.walTxs()); err != nil {
+		txmp.logger.Error("failed to compact mempool WAL; disabling it", "err", err)
+		txmp.wal = nil
+	}
+}
+
+// walTxs returns the transactions in the mempool in order of arrival.
+// The caller must hold txmp.mtx.
+func (txmp *TxMempool) walTxs() []types.Tx {
+	txs := make([]types.Tx, 0, txmp.Size())
+	for cur := txmp.txs.Front(); cur != nil; cur = cur.Next() {
+		txs = append(txs, cur.Value.(*WrappedTx).tx)
+	}
+	return txs
+}
+
 // TxsAvailable returns a channel which fires once for every height, and only
 // when transactions are available in the mempool. It is thread-safe.
 func (txmp *TxMempool) TxsAvailable() <-chan struct{} { return txmp.txsAvailable }
@@ -244,20 +354,18 @@ func (txmp *TxMempool) CheckTx(
 func (txmp *TxMempool) RemoveTxByKey(txKey types.TxKey) error {
 	txmp.mtx.Lock()
 	defer txmp.mtx.Unlock()
-	return txmp.removeTxByKey(txKey)
+	if err := txmp.removeTxByKey(txKey); err != nil {
+		return err
+	}
+	txmp.updateFullness()
+	return nil
 }
 
 // removeTxByKey removes the specified transaction key from the mempool.
 // The caller must hold txmp.mtx exclusively.
 func (txmp *TxMempool) removeTxByKey(key types.TxKey) error {
 	if elt, ok := txmp.txByKey[key]; ok {
-		w := elt.Value.(*WrappedTx)
-		delete(txmp.txByKey, key)
-		delete(txmp.txBySender, w.sender)
-		txmp.txs.Remove(elt)
-		elt.DetachPrev()
-		elt.DetachNext()
-		atomic.AddInt64(&txmp.txsBytes, -w.Size())
+		txmp.removeTxByElement(elt)
 		return nil
 	}
 	return fmt.Errorf("transaction %x not found", key)
@@ -268,11 +376,72 @@ func (txmp *TxMempool) removeTxByKey(key types.TxKey) error {
 func (txmp *TxMempool) removeTxByElement(elt *clist.CElement) {
 	w := elt.Value.(*WrappedTx)
 	delete(txmp.txByKey, w.tx.Key())
-	delete(txmp.txBySender, w.sender)
+	txmp.removeFromSenderLane(w.sender, elt)
 	txmp.txs.Remove(elt)
 	elt.DetachPrev()
 	elt.DetachNext()
 	atomic.AddInt64(&txmp.txsBytes, -w.Size())
+
+	if txmp.wal != nil {
+		if err := txmp.wal.remove(w.tx.Key()); err != nil {
+			txmp.logger.Error("failed to write mempool WAL", "err", err)
+		}
+	}
+}
+
+// removeFromSenderLane removes the specified transaction element from the
+// transactions of the given sender. The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) removeFromSenderLane(sender string, elt *clist.CElement) {
+	lane := txmp.txBySender[sender]
+	for i, cur := range lane {
+		if cur == elt {
+			lane = append(lane[:i], lane[i+1:]...)
+			break
+		}
+	}
+	if len(lane) == 0 {
+		delete(txmp.txBySender, sender)
+	} else {
+		txmp.txBySender[sender] = lane
+	}
+}
+
+// rebroadcastTxs returns the transactions due to be rebroadcast at the given
+// time: those that have been in the mempool for another rebroadcast interval
+// since they were last broadcast, and haven't been rebroadcast the maximum
+// number of times yet. The returned transactions are counted as rebroadcast.
+func (txmp *TxMempool) rebroadcastTxs(now time.Time) []types.Tx {
+	txmp.mtx.RLock()
+	defer txmp.mtx.RUnlock()
+
+	interval := txmp.config.RebroadcastInterval
+	var txs []types.Tx
+	for cur := txmp.txs.Front(); cur != nil; cur = cur.Next() {
+		w := cur.Value.(*WrappedTx)
+		if w.NoGossip() {
+			continue
+		}
+		w.mtx.Lock()
+		due := w.rebroadcasts < txmp.config.MaxRebroadcasts &&
+			now.Sub(w.timestamp) >= time.Duration(w.rebroadcasts+1)*interval
+		if due {
+			w.rebroadcasts++
+			txs = append(txs, w.tx)
+		}
+		w.mtx.Unlock()
+	}
+	return txs
+}
+
+// removePeer forgets that the peer with the specified ID has any of the
+// transactions in the mempool, so that the ID can be reused for another peer.
+func (txmp *TxMempool) removePeer(id uint16) {
+	txmp.mtx.RLock()
+	defer txmp.mtx.RUnlock()
+
+	for cur := txmp.txs.Front(); cur != nil; cur = cur.Next() {
+		cur.Value.(*WrappedTx).RemovePeer(id)
+	}
 }
 
 // Flush purges the contents of the mempool and the cache, leaving both empty.
@@ -290,11 +459,14 @@ func (txmp *TxMempool) Flush() {
 		cur = next
 	}
 	txmp.cache.Reset()
+	txmp.updateFullness()
 }
 
 // allEntriesSorted returns a slice of all the transactions currently in the
 // mempool, sorted in nonincreasing order by priority with ties broken by
-// increasing order of arrival time.
+// increasing order of arrival time. The transactions of each sender are kept
+// in order of arrival relative to each other, in the positions that sender's
+// transactions take in priority order.
 func (txmp *TxMempool) allEntriesSorted() []*WrappedTx {
 	txmp.mtx.RLock()
 	defer txmp.mtx.RUnlock()
@@ -309,12 +481,23 @@ func (txmp *TxMempool) allEntriesSorted() []*WrappedTx {
 		}
 		return all[i].priority > all[j].priority // N.B. higher priorities first
 	})
+
+	// Reorder the transactions of senders with more than one, so that an
+	// application using sequence numbers doesn't see them out of order.
+	next := make(map[string]int)
+	for i, w := range all {
+		if lane := txmp.txBySender[w.sender]; w.sender != "" && len(lane) > 1 {
+			all[i] = lane[next[w.sender]].Value.(*WrappedTx)
+			next[w.sender]++
+		}
+	}
 	return all
 }
 
 // ReapMaxBytesMaxGas returns a slice of valid transactions that fit within the
 // size and gas constraints. The results are ordered by nonincreasing priority,
-// with ties broken by increasing order of arrival.  Reaping transactions does
+// with ties broken by increasing order of arrival, except that the transactions
+// of each sender stay in order of arrival.  Reaping transactions does
 // not remove them from the mempool.
 //
 // If maxBytes < 0, no limit is set on the total size in bytes.
@@ -349,7 +532,9 @@ func (txmp *TxMempool) TxsFront() *clist.CElement { return txmp.txs.Front() }
 
 // ReapMaxTxs returns up to max transactions from the mempool. The results are
 // ordered by nonincreasing priority with ties broken by increasing order of
+// arrival, except that the transactions of each sender stay in order of
 // arrival. Reaping transactions does not remove them from the mempool.
+// Transactions the application marked proposal-only are not returned.
 //
 // If max < 0, all transactions in the mempool are reaped.
 //
@@ -362,11 +547,45 @@ func (txmp *TxMempool) ReapMaxTxs(max int) types.Txs {
 		if max >= 0 && len(keep) >= max {
 			break
 		}
+		if w.ProposalOnly() {
+			continue
+		}
 		keep = append(keep, w.tx)
 	}
 	return keep
 }
 
+// RejectedTx reports why the transaction with the given key was rejected by
+// the mempool, if it was rejected recently. Transactions accepted since are
+// not reported.
+func (txmp *TxMempool) RejectedTx(key types.TxKey) (RejectedTx, bool) {
+	if txmp.rejected == nil {
+		return RejectedTx{}, false
+	}
+	return txmp.rejected.get(key)
+}
+
+// ReapTxsBySender returns all the transactions in the mempool from the given
+// sender, as reported by the application in CheckTx, in order of arrival,
+// which is also the order in which they are reaped. Reaping transactions does
+// not remove them from the mempool. Transactions the application marked
+// proposal-only are not returned.
+//
+// An empty sender matches no transactions.
+func (txmp *TxMempool) ReapTxsBySender(sender string) types.Txs {
+	txmp.mtx.RLock()
+	defer txmp.mtx.RUnlock()
+
+	lane := txmp.txBySender[sender]
+	keep := make([]types.Tx, 0, len(lane))
+	for _, elt := range lane {
+		if w := elt.Value.(*WrappedTx); !w.ProposalOnly() {
+			keep = append(keep, w.tx)
+		}
+	}
+	return keep
+}
+
 // Update removes all the given transactions from the mempool and the cache,
 // and updates the current block height. The blockTxs and deliverTxResponses
 // must have the same length with each response corresponding to the tx at the
@@ -418,6 +637,8 @@ func (txmp *TxMempool) Update(
 	}
 
 	txmp.purgeExpiredTxs(blockHeight)
+	txmp.compactWAL()
+	txmp.updateFullness()
 
 	// If there any uncommitted transactions left in the mempool, we either
 	// initiate re-CheckTx per remaining transaction or notify that remaining
@@ -467,6 +688,11 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 		)
 
 		txmp.metrics.FailedTxs.Add(1)
+		if err != nil {
+			txmp.recordRejected(wtx.tx, checkTxRes, RejectedPostCheck+": "+err.Error())
+		} else {
+			txmp.recordRejected(wtx.tx, checkTxRes, RejectedInvalid)
+		}
 
 		// Remove the invalid transaction from the cache, unless the operator has
 		// instructed us to keep invalid transactions.
@@ -486,19 +712,20 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 	priority := checkTxRes.Priority
 	sender := checkTxRes.Sender
 
-	// Disallow multiple concurrent transactions from the same sender assigned
-	// by the ABCI application. As a special case, an empty sender is not
-	// restricted.
-	if sender != "" {
-		elt, ok := txmp.txBySender[sender]
+	// Unless sender lanes are enabled, disallow multiple concurrent
+	// transactions from the same sender assigned by the ABCI application. As a
+	// special case, an empty sender is not restricted.
+	if sender != "" && !txmp.config.SenderLanes {
+		lane, ok := txmp.txBySender[sender]
 		if ok {
-			w := elt.Value.(*WrappedTx)
+			w := lane[0].Value.(*WrappedTx)
 			txmp.logger.Debug(
 				"rejected valid incoming transaction; tx already exists for sender",
 				"tx", fmt.Sprintf("%X", w.tx.Hash()),
 				"sender", sender,
 			)
 			txmp.metrics.RejectedTxs.Add(1)
+			txmp.recordRejected(wtx.tx, checkTxRes, RejectedSender)
 			// TODO(creachadair): Report an error for a duplicate sender.
 			// This is an API change, unfortunately, but should be made safe if it isn't.
 			// fmt.Errorf("transaction rejected: tx already exists for sender %q (%X)", sender, w.tx.Hash())
@@ -534,6 +761,7 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 				"err", err.Error(),
 			)
 			txmp.metrics.RejectedTxs.Add(1)
+			txmp.recordRejected(wtx.tx, checkTxRes, RejectedFull)
 			// TODO(creachadair): Report an error for a full mempool.
 			// This is an API change, unfortunately, but should be made safe if it isn't.
 			// fmt.Errorf("transaction rejected: mempool is full (%X)", wtx.tx.Hash())
@@ -566,9 +794,7 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 				"old_tx", tmstrings.LazySprintf("%X", w.tx.Hash()),
 				"old_priority", w.priority,
 			)
-			txmp.removeTxByElement(vic)
-			txmp.cache.Remove(w.tx)
-			txmp.metrics.EvictedTxs.Add(1)
+			txmp.evictTxByElement(vic, types.EvictedTxFull)
 
 			// We may not need to evict all the eligible transactions.  Bail out
 			// early if we have made enough room.
@@ -582,10 +808,12 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 	wtx.SetGasWanted(checkTxRes.GasWanted)
 	wtx.SetPriority(priority)
 	wtx.SetSender(sender)
+	wtx.SetPropagation(checkTxRes.NoGossip, checkTxRes.ProposalOnly)
 	txmp.insertTx(wtx)
 
 	txmp.metrics.TxSizeBytes.Observe(float64(wtx.Size()))
 	txmp.metrics.Size.Set(float64(txmp.Size()))
+	txmp.updateFullness()
 	txmp.logger.Debug(
 		"inserted new valid transaction",
 		"priority", wtx.Priority(),
@@ -598,13 +826,39 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 }
 
 func (txmp *TxMempool) insertTx(wtx *WrappedTx) {
+	if txmp.rejected != nil {
+		txmp.rejected.remove(wtx.tx.Key())
+	}
+
 	elt := txmp.txs.PushBack(wtx)
 	txmp.txByKey[wtx.tx.Key()] = elt
 	if s := wtx.Sender(); s != "" {
-		txmp.txBySender[s] = elt
+		txmp.txBySender[s] = append(txmp.txBySender[s], elt)
 	}
 
 	atomic.AddInt64(&txmp.txsBytes, wtx.Size())
+
+	if txmp.wal != nil {
+		if err := txmp.wal.add(wtx.tx); err != nil {
+			txmp.logger.Error("failed to write mempool WAL", "err", err)
+		}
+	}
+}
+
+// recordRejected records that tx was rejected for the given reason, with the
+// application's CheckTx response, if rejected transactions are kept.
+func (txmp *TxMempool) recordRejected(tx types.Tx, res *abci.ResponseCheckTx, reason string) {
+	if txmp.rejected == nil {
+		return
+	}
+	txmp.rejected.add(tx.Key(), RejectedTx{
+		Code:      res.Code,
+		Codespace: res.Codespace,
+		Data:      res.Data,
+		Reason:    reason,
+		Height:    txmp.height,
+		Time:      time.Now().UTC(),
+	})
 }
 
 // handleRecheckResult handles the responses from ABCI CheckTx calls issued
@@ -635,6 +889,7 @@ func (txmp *TxMempool) handleRecheckResult(tx types.Tx, checkTxRes *abci.Respons
 
 	if checkTxRes.Code == abci.CodeTypeOK && err == nil {
 		wtx.SetPriority(checkTxRes.Priority)
+		wtx.SetPropagation(checkTxRes.NoGossip, checkTxRes.ProposalOnly)
 		return // N.B. Size of mempool did not change
 	}
 
@@ -647,12 +902,23 @@ func (txmp *TxMempool) handleRecheckResult(tx types.Tx, checkTxRes *abci.Respons
 	)
 	txmp.removeTxByElement(elt)
 	txmp.metrics.FailedTxs.Add(1)
+	if err != nil {
+		txmp.recordRejected(wtx.tx, checkTxRes, RejectedPostCheck+": "+err.Error())
+	} else {
+		txmp.recordRejected(wtx.tx, checkTxRes, RejectedRecheck)
+	}
 	if !txmp.config.KeepInvalidTxsInCache {
 		txmp.cache.Remove(wtx.tx)
 	}
+	txmp.publishEvictedTx(wtx.tx, types.EvictedTxRecheck)
 	txmp.metrics.Size.Set(float64(txmp.Size()))
+	txmp.updateFullness()
 }
 
+// recheckBatchSize is the number of transactions rechecked with the
+// application in a single batch.
+const recheckBatchSize = 64
+
 // recheckTransactions initiates re-CheckTx ABCI calls for all the transactions
 // currently in the mempool. It reports the number of recheck calls that were
 // successfully initiated.
@@ -675,33 +941,81 @@ func (txmp *TxMempool) recheckTransactions(ctx context.Context) {
 		wtxs = append(wtxs, e.Value.(*WrappedTx))
 	}
 
+	// A recheck still running for an earlier block is superseded by this one,
+	// which covers the same transactions against a newer state.
+	if txmp.recheckCancel != nil {
+		txmp.recheckCancel()
+	}
+	var rctx context.Context
+	var cancel context.CancelFunc
+	if txmp.config.RecheckTimeout > 0 {
+		rctx, cancel = context.WithTimeout(ctx, txmp.config.RecheckTimeout)
+	} else {
+		rctx, cancel = context.WithCancel(ctx)
+	}
+	txmp.recheckCancel = cancel
+
+	pending := int64(len(wtxs))
+	txmp.metrics.RecheckPending.Set(float64(pending))
+
 	// Issue CheckTx calls for each remaining transaction, and when all the
 	// rechecks are complete signal watchers that transactions may be available.
+	// The rechecks run concurrently in batches, up to a limit, without holding
+	// the lock, so that consensus isn't held up by a large mempool. Each batch
+	// takes a single round trip to a remote application. Transactions not
+	// rechecked by the deadline stay in the mempool until the next recheck.
 	go func() {
+		defer cancel()
+		startTime := time.Now()
 		g, start := taskgroup.New(nil).Limit(2 * runtime.NumCPU())
 
-		for _, wtx := range wtxs {
-			wtx := wtx
+		for i := 0; i < len(wtxs); i += recheckBatchSize {
+			batch := wtxs[i:tmmath.MinInt(i+recheckBatchSize, len(wtxs))]
 			start(func() error {
-				rsp, err := txmp.proxyAppConn.CheckTx(ctx, &abci.RequestCheckTx{
-					Tx:   wtx.tx,
-					Type: abci.CheckTxType_Recheck,
-				})
+				if rctx.Err() != nil {
+					return nil
+				}
+				reqs := make([]*abci.RequestCheckTx, len(batch))
+				for j, wtx := range batch {
+					reqs[j] = &abci.RequestCheckTx{Tx: wtx.tx, Type: abci.CheckTxType_Recheck}
+				}
+				rsps, err := abciclient.CheckTxs(rctx, txmp.proxyAppConn, reqs)
 				if err != nil {
-					txmp.logger.Error("failed to execute CheckTx during recheck",
-						"err", err, "hash", fmt.Sprintf("%x", wtx.tx.Hash()))
+					if rctx.Err() == nil {
+						txmp.logger.Error("failed to execute CheckTx during recheck",
+							"err", err, "num_txs", len(batch))
+					}
 				} else {
-					txmp.handleRecheckResult(wtx.tx, rsp)
+					for j, wtx := range batch {
+						txmp.handleRecheckResult(wtx.tx, rsps[j])
+					}
+				}
+				if rctx.Err() == nil {
+					txmp.metrics.RecheckPending.Set(float64(atomic.AddInt64(&pending, -int64(len(batch)))))
 				}
 				return nil
 			})
 		}
-		if err := txmp.proxyAppConn.Flush(ctx); err != nil {
+		if err := txmp.proxyAppConn.Flush(rctx); err != nil && rctx.Err() == nil {
 			txmp.logger.Error("failed to flush transactions during recheck", "err", err)
 		}
 
-		// When recheck is complete, trigger a notification for more transactions.
 		_ = g.Wait()
+		txmp.metrics.RecheckDurationSeconds.Observe(time.Since(startTime).Seconds())
+
+		switch {
+		case errors.Is(rctx.Err(), context.DeadlineExceeded):
+			txmp.logger.Info("recheck did not complete before the deadline",
+				"timeout", txmp.config.RecheckTimeout,
+				"not_rechecked", atomic.LoadInt64(&pending),
+			)
+			txmp.metrics.RecheckPending.Set(0)
+		case rctx.Err() != nil:
+			// Superseded by a newer recheck, which notifies in turn.
+			return
+		}
+
+		// When recheck is complete, trigger a notification for more transactions.
 		txmp.mtx.Lock()
 		defer txmp.mtx.Unlock()
 		txmp.notifyTxsAvailable()
@@ -746,18 +1060,97 @@ func (txmp *TxMempool) purgeExpiredTxs(blockHeight int64) {
 
 		w := cur.Value.(*WrappedTx)
 		if txmp.config.TTLNumBlocks > 0 && (blockHeight-w.height) > txmp.config.TTLNumBlocks {
-			txmp.removeTxByElement(cur)
-			txmp.cache.Remove(w.tx)
-			txmp.metrics.EvictedTxs.Add(1)
+			txmp.evictTxByElement(cur, types.EvictedTxExpired)
 		} else if txmp.config.TTLDuration > 0 && now.Sub(w.timestamp) > txmp.config.TTLDuration {
-			txmp.removeTxByElement(cur)
-			txmp.cache.Remove(w.tx)
-			txmp.metrics.EvictedTxs.Add(1)
+			txmp.evictTxByElement(cur, types.EvictedTxExpired)
 		}
 		cur = next
 	}
 }
 
+// evictTxByElement removes a valid transaction from the mempool and the cache,
+// so that it may be resubmitted, and publishes its eviction.
+//
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) evictTxByElement(elt *clist.CElement, reason string) {
+	w := elt.Value.(*WrappedTx)
+	txmp.removeTxByElement(elt)
+	txmp.cache.Remove(w.tx)
+	txmp.metrics.EvictedTxs.Add(1)
+	txmp.publishEvictedTx(w.tx, reason)
+}
+
+// publishEvictedTx publishes the eviction of tx for the given reason, if the
+// mempool has an event bus.
+func (txmp *TxMempool) publishEvictedTx(tx types.Tx, reason string) {
+	if txmp.eventBus == nil {
+		return
+	}
+	if err := txmp.eventBus.PublishEventEvictedTx(types.EventDataEvictedTx{
+		Tx:     tx,
+		Reason: reason,
+	}); err != nil {
+		txmp.logger.Error("failed to publish evicted tx event", "err", err)
+	}
+}
+
+// updateFullness publishes a MempoolFullness event if the fullness of the
+// mempool crossed one or more of the configured thresholds since it was last
+// updated. It is called once an operation is complete, rather than for each
+// transaction added or removed, so that a transaction evicting another one
+// from a full mempool doesn't report the mempool draining and filling up.
+//
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) updateFullness() {
+	thresholds := txmp.config.FullnessThresholds
+	if len(thresholds) == 0 {
+		return
+	}
+
+	numTxs, txsBytes := txmp.Size(), txmp.SizeBytes()
+	var percent int64
+	if txmp.config.Size > 0 {
+		percent = int64(100 * numTxs / txmp.config.Size)
+	}
+	if txmp.config.MaxTxsBytes > 0 {
+		percent = tmmath.MaxInt64(percent, 100*txsBytes/txmp.config.MaxTxsBytes)
+	}
+
+	fullness := 0
+	for fullness < len(thresholds) && percent >= int64(thresholds[fullness]) {
+		fullness++
+	}
+	if fullness == txmp.fullness {
+		return
+	}
+	rising := fullness > txmp.fullness
+	txmp.fullness = fullness
+
+	threshold := 0
+	if fullness > 0 {
+		threshold = thresholds[fullness-1]
+	}
+	txmp.logger.Debug("mempool fullness crossed a threshold",
+		"threshold", threshold,
+		"rising", rising,
+		"num_txs", numTxs,
+		"txs_bytes", txsBytes,
+	)
+	if txmp.eventBus == nil {
+		return
+	}
+	if err := txmp.eventBus.PublishEventMempoolFullness(types.EventDataMempoolFullness{
+		Threshold:   threshold,
+		Rising:      rising,
+		NumTxs:      numTxs,
+		MaxTxs:      txmp.config.Size,
+		TxsBytes:    txsBytes,
+		MaxTxsBytes: txmp.config.MaxTxsBytes,
+	}); err != nil {
+		txmp.logger.Error("failed to publish mempool fullness event", "err", err)
+	}
+}
+
 func (txmp *TxMempool) notifyTxsAvailable() {
 	if txmp.Size() == 0 {
 		return // nothing to do
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -21,6 +22,8 @@ import (
 	"github.com/tendermint/tendermint/abci/example/kvstore"
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/internal/eventbus"
+	tmpubsub "github.com/tendermint/tendermint/internal/pubsub"
 	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/types"
 )
@@ -543,6 +546,84 @@ func TestTxMempool_CheckTxSameSender(t *testing.T) {
 	require.Equal(t, 1, txmp.Size())
 }
 
+func TestTxMempool_RejectedTx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	txmp := setup(t, client, 100)
+
+	// The application rejects transactions that aren't sender=key=priority.
+	bad := types.Tx("malformed")
+	require.NoError(t, txmp.CheckTx(ctx, bad, nil, TxInfo{}))
+	rejected, ok := txmp.RejectedTx(bad.Key())
+	require.True(t, ok)
+	require.EqualValues(t, 101, rejected.Code)
+	require.Equal(t, RejectedInvalid, rejected.Reason)
+
+	// A second transaction from the same sender is rejected by the mempool.
+	mustCheckTx(ctx, t, txmp, "sender-0=a=1")
+	dup := types.Tx("sender-0=b=1")
+	require.NoError(t, txmp.CheckTx(ctx, dup, nil, TxInfo{}))
+	rejected, ok = txmp.RejectedTx(dup.Key())
+	require.True(t, ok)
+	require.EqualValues(t, code.CodeTypeOK, rejected.Code)
+	require.Equal(t, RejectedSender, rejected.Reason)
+
+	// Accepted transactions aren't reported.
+	_, ok = txmp.RejectedTx(types.Tx("sender-0=a=1").Key())
+	require.False(t, ok)
+
+	// The cache only keeps the most recent rejections.
+	txmp.rejected = newRejectedTxCache(2)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, txmp.CheckTx(ctx, types.Tx(fmt.Sprintf("malformed-%d", i)), nil, TxInfo{}))
+	}
+	_, ok = txmp.RejectedTx(types.Tx("malformed-0").Key())
+	require.False(t, ok)
+	_, ok = txmp.RejectedTx(types.Tx("malformed-2").Key())
+	require.True(t, ok)
+}
+
+func TestTxMempool_SenderLanes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	txmp := setup(t, client, 100)
+	txmp.config.SenderLanes = true
+
+	txs := []types.Tx{
+		[]byte("sender-a=1=10"),
+		[]byte("sender-b=1=50"),
+		[]byte("sender-a=2=100"),
+		[]byte("sender-a=3=20"),
+	}
+	for _, tx := range txs {
+		require.NoError(t, txmp.CheckTx(ctx, tx, nil, TxInfo{}))
+	}
+	require.Equal(t, len(txs), txmp.Size())
+
+	// By priority alone, the order would be a2, b1, a3, a1.
+	require.Equal(t, types.Txs{txs[0], txs[1], txs[2], txs[3]}, txmp.ReapMaxTxs(-1))
+	require.Equal(t, types.Txs{txs[0], txs[1]}, txmp.ReapMaxBytesMaxGas(-1, 2))
+	require.Equal(t, types.Txs{txs[0], txs[2], txs[3]}, txmp.ReapTxsBySender("sender-a"))
+	require.Empty(t, txmp.ReapTxsBySender("sender-c"))
+
+	require.NoError(t, txmp.RemoveTxByKey(txs[2].Key()))
+	require.Equal(t, types.Txs{txs[1], txs[0], txs[3]}, txmp.ReapMaxTxs(-1))
+}
+
 func TestTxMempool_ConcurrentTxs(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test in short mode")
@@ -675,6 +756,278 @@ func TestTxMempool_ExpiredTxs_NumBlocks(t *testing.T) {
 	require.GreaterOrEqual(t, txmp.Size(), 45)
 }
 
+func TestTxMempool_ExpiredTxs_Event(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	eventBus := eventbus.NewDefault(log.NewNopLogger())
+	require.NoError(t, eventBus.Start(ctx))
+	sub, err := eventBus.SubscribeWithArgs(ctx, tmpubsub.SubscribeArgs{
+		ClientID: "test",
+		Query:    types.EventQueryEvictedTx,
+	})
+	require.NoError(t, err)
+
+	txmp := setup(t, client, 500, WithEventBus(eventBus))
+	txmp.height = 100
+	txmp.config.TTLNumBlocks = 1
+
+	tTxs := checkTxs(ctx, t, txmp, 2, 0)
+	require.Equal(t, 2, txmp.Size())
+
+	txmp.Lock()
+	require.NoError(t, txmp.Update(ctx, txmp.height+2, nil, nil, nil, nil, true))
+	txmp.Unlock()
+	require.Zero(t, txmp.Size())
+
+	evicted := make([]types.Tx, 0, len(tTxs))
+	for range tTxs {
+		msg, err := sub.Next(ctx)
+		require.NoError(t, err)
+		data, ok := msg.Data().(types.EventDataEvictedTx)
+		require.True(t, ok)
+		require.Equal(t, types.EvictedTxExpired, data.Reason)
+		evicted = append(evicted, data.Tx)
+	}
+	require.ElementsMatch(t, []types.Tx{tTxs[0].tx, tTxs[1].tx}, evicted)
+}
+
+func TestTxMempool_FullnessEvent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	eventBus := eventbus.NewDefault(log.NewNopLogger())
+	require.NoError(t, eventBus.Start(ctx))
+	sub, err := eventBus.SubscribeWithArgs(ctx, tmpubsub.SubscribeArgs{
+		ClientID: "test",
+		Query:    types.EventQueryMempoolFullness,
+	})
+	require.NoError(t, err)
+
+	txmp := setup(t, client, 500, WithEventBus(eventBus))
+	txmp.config.Size = 4
+	txmp.config.FullnessThresholds = []int{50, 100}
+
+	next := func() types.EventDataMempoolFullness {
+		t.Helper()
+		msg, err := sub.Next(ctx)
+		require.NoError(t, err)
+		data, ok := msg.Data().(types.EventDataMempoolFullness)
+		require.True(t, ok)
+		return data
+	}
+
+	mustCheckTx(ctx, t, txmp, "sender-0=a=1")
+	mustCheckTx(ctx, t, txmp, "sender-1=b=2")
+	data := next()
+	require.Equal(t, 50, data.Threshold)
+	require.True(t, data.Rising)
+	require.Equal(t, 2, data.NumTxs)
+	require.Equal(t, 4, data.MaxTxs)
+
+	mustCheckTx(ctx, t, txmp, "sender-2=c=3")
+	mustCheckTx(ctx, t, txmp, "sender-3=d=4")
+	require.Equal(t, 100, next().Threshold)
+
+	// Evicting a transaction to make room for another keeps the mempool full.
+	mustCheckTx(ctx, t, txmp, "sender-4=e=5")
+	require.Equal(t, 4, txmp.Size())
+
+	require.NoError(t, txmp.RemoveTxByKey(types.Tx("sender-4=e=5").Key()))
+	data = next()
+	require.Equal(t, 50, data.Threshold)
+	require.False(t, data.Rising)
+
+	txmp.Flush()
+	data = next()
+	require.Zero(t, data.Threshold)
+	require.False(t, data.Rising)
+	require.Zero(t, data.NumTxs)
+}
+
+func TestTxMempool_RebroadcastTxs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	txmp := setup(t, client, 100)
+	txmp.config.RebroadcastInterval = time.Minute
+	txmp.config.MaxRebroadcasts = 2
+
+	tTxs := checkTxs(ctx, t, txmp, 3, 0)
+	now := time.Now()
+
+	require.Empty(t, txmp.rebroadcastTxs(now))
+	require.ElementsMatch(t, convertTex(tTxs), txmp.rebroadcastTxs(now.Add(time.Minute)))
+	require.Empty(t, txmp.rebroadcastTxs(now.Add(time.Minute)))
+	require.ElementsMatch(t, convertTex(tTxs), txmp.rebroadcastTxs(now.Add(2*time.Minute)))
+
+	// The transactions have used up their rebroadcasts.
+	require.Empty(t, txmp.rebroadcastTxs(now.Add(time.Hour)))
+}
+
+// propagationApp is an application that asks not to gossip transactions from
+// the sender "private", and marks those from "proposer" proposal-only.
+type propagationApp struct {
+	*application
+}
+
+func (app propagationApp) CheckTx(ctx context.Context, req *abci.RequestCheckTx) (*abci.ResponseCheckTx, error) {
+	res, err := app.application.CheckTx(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	res.NoGossip = res.Sender == "private"
+	res.ProposalOnly = res.Sender == "proposer"
+	return res, nil
+}
+
+func TestTxMempool_Propagation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	app := propagationApp{&application{Application: kvstore.NewApplication()}}
+	client := abciclient.NewLocalClient(log.NewNopLogger(), app)
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	txmp := setup(t, client, 100)
+	txmp.config.RebroadcastInterval = time.Minute
+
+	public, private, proposer := types.Tx("public=a=1"), types.Tx("private=b=2"), types.Tx("proposer=c=3")
+	for _, tx := range []types.Tx{public, private, proposer} {
+		mustCheckTx(ctx, t, txmp, string(tx))
+	}
+
+	// Only public transactions are gossiped.
+	require.Equal(t, []types.Tx{public}, txmp.rebroadcastTxs(time.Now().Add(time.Minute)))
+	for e := txmp.TxsFront(); e != nil; e = e.Next() {
+		w := e.Value.(*WrappedTx)
+		require.Equal(t, w.tx.Key() != public.Key(), w.NoGossip())
+	}
+
+	// All transactions are proposed, but proposal-only ones aren't listed.
+	require.Equal(t, types.Txs{proposer, private, public}, txmp.ReapMaxBytesMaxGas(-1, -1))
+	require.Equal(t, types.Txs{private, public}, txmp.ReapMaxTxs(-1))
+	require.Empty(t, txmp.ReapTxsBySender("proposer"))
+}
+
+// stallingRecheckApp is an application whose rechecks never complete.
+type stallingRecheckApp struct {
+	*application
+}
+
+func (app stallingRecheckApp) CheckTx(ctx context.Context, req *abci.RequestCheckTx) (*abci.ResponseCheckTx, error) {
+	if req.Type == abci.CheckTxType_Recheck {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return app.application.CheckTx(ctx, req)
+}
+
+func TestTxMempool_RecheckTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	app := stallingRecheckApp{&application{Application: kvstore.NewApplication()}}
+	client := abciclient.NewLocalClient(log.NewNopLogger(), app)
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	txmp := setup(t, client, 100)
+	txmp.config.RecheckTimeout = 50 * time.Millisecond
+	txmp.EnableTxsAvailable()
+
+	_ = checkTxs(ctx, t, txmp, 10, 0)
+	<-txmp.TxsAvailable()
+
+	// Update returns without waiting for the recheck, which times out and
+	// leaves the transactions in the mempool.
+	txmp.Lock()
+	require.NoError(t, txmp.Update(ctx, 1, nil, nil, nil, nil, true))
+	txmp.Unlock()
+
+	select {
+	case <-txmp.TxsAvailable():
+	case <-time.After(5 * time.Second):
+		t.Fatal("recheck did not time out")
+	}
+	require.Equal(t, 10, txmp.Size())
+}
+
+func TestTxMempool_WAL(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := abciclient.NewLocalClient(log.NewNopLogger(), &application{Application: kvstore.NewApplication()})
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Wait)
+
+	walDir := t.TempDir()
+	newMempool := func() *TxMempool {
+		txmp := setup(t, client, 500)
+		txmp.config.WalPath = walDir
+		require.NoError(t, txmp.InitWAL(ctx))
+		return txmp
+	}
+
+	txmp := newMempool()
+	require.Zero(t, txmp.Size())
+
+	tTxs := checkTxs(ctx, t, txmp, 3, 0)
+	require.Equal(t, 3, txmp.Size())
+
+	// Commit the first transaction, which removes it from the WAL as well.
+	txmp.Lock()
+	require.NoError(t, txmp.Update(ctx, 1, types.Txs{tTxs[0].tx},
+		[]*abci.ExecTxResult{{Code: abci.CodeTypeOK}}, nil, nil, false))
+	txmp.Unlock()
+	require.NoError(t, txmp.CloseWAL())
+
+	// A record truncated by a crash is ignored.
+	f, err := os.OpenFile(filepath.Join(walDir, walFile), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.Write(encodeWALRecord(nil, walRecordAdd, []byte("truncated=tx"))[:walHeaderSize+4])
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// A new mempool restores the remaining transactions in order.
+	txmp = newMempool()
+	require.Equal(t, 2, txmp.Size())
+	require.Equal(t, []types.Tx{tTxs[1].tx, tTxs[2].tx}, txmp.walTxs())
+
+	// Transactions added after a restart are recorded as well, and the
+	// compacted WAL no longer holds the truncated record.
+	tTxs = append(tTxs, checkTxs(ctx, t, txmp, 1, 0)...)
+	require.NoError(t, txmp.CloseWAL())
+	txs, err := readTxWAL(filepath.Join(walDir, walFile))
+	require.NoError(t, err)
+	require.Equal(t, []types.Tx{tTxs[1].tx, tTxs[2].tx, tTxs[3].tx}, txs)
+}
+
 func TestTxMempool_CheckTxPostCheckError(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
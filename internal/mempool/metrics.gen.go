@@ -52,16 +52,39 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "recheck_times",
 			Help:      "Number of times transactions are rechecked in the mempool.",
 		}, labels).With(labelsAndValues...),
+		RecheckPending: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "recheck_pending",
+			Help:      "Number of transactions still to be rechecked after the latest block.",
+		}, labels).With(labelsAndValues...),
+		RecheckDurationSeconds: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "recheck_duration_seconds",
+			Help:      "Time in seconds taken to recheck the mempool after a block.",
+
+			Buckets: stdprometheus.ExponentialBucketsRange(0.01, 100, 10),
+		}, labels).With(labelsAndValues...),
+		RebroadcastTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "rebroadcast_txs",
+			Help:      "Number of transactions rebroadcast to peers.",
+		}, labels).With(labelsAndValues...),
 	}
 }
 
 func NopMetrics() *Metrics {
 	return &Metrics{
-		Size:         discard.NewGauge(),
-		TxSizeBytes:  discard.NewHistogram(),
-		FailedTxs:    discard.NewCounter(),
-		RejectedTxs:  discard.NewCounter(),
-		EvictedTxs:   discard.NewCounter(),
-		RecheckTimes: discard.NewCounter(),
+		Size:                   discard.NewGauge(),
+		TxSizeBytes:            discard.NewHistogram(),
+		FailedTxs:              discard.NewCounter(),
+		RejectedTxs:            discard.NewCounter(),
+		EvictedTxs:             discard.NewCounter(),
+		RecheckTimes:           discard.NewCounter(),
+		RecheckPending:         discard.NewGauge(),
+		RecheckDurationSeconds: discard.NewHistogram(),
+		RebroadcastTxs:         discard.NewCounter(),
 	}
 }
@@ -40,4 +40,13 @@ type Metrics struct {
 
 	// Number of times transactions are rechecked in the mempool.
 	RecheckTimes metrics.Counter
+
+	// Number of transactions still to be rechecked after the latest block.
+	RecheckPending metrics.Gauge
+
+	// Time in seconds taken to recheck the mempool after a block.
+	RecheckDurationSeconds metrics.Histogram `metrics_buckettype:"exprange" metrics_bucketsizes:"0.01, 100, 10"`
+
+	// Number of transactions rebroadcast to peers.
+	RebroadcastTxs metrics.Counter
 }
@@ -94,6 +94,43 @@ func (_m *Mempool) ReapMaxTxs(max int) types.Txs {
 	return r0
 }
 
+// ReapTxsBySender provides a mock function with given fields: sender
+func (_m *Mempool) ReapTxsBySender(sender string) types.Txs {
+	ret := _m.Called(sender)
+
+	var r0 types.Txs
+	if rf, ok := ret.Get(0).(func(string) types.Txs); ok {
+		r0 = rf(sender)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(types.Txs)
+		}
+	}
+
+	return r0
+}
+
+// RejectedTx provides a mock function with given fields: key
+func (_m *Mempool) RejectedTx(key types.TxKey) (mempool.RejectedTx, bool) {
+	ret := _m.Called(key)
+
+	var r0 mempool.RejectedTx
+	if rf, ok := ret.Get(0).(func(types.TxKey) mempool.RejectedTx); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Get(0).(mempool.RejectedTx)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(types.TxKey) bool); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
 // RemoveTxByKey provides a mock function with given fields: txKey
 func (_m *Mempool) RemoveTxByKey(txKey types.TxKey) error {
 	ret := _m.Called(txKey)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"runtime/debug"
 	"sync"
+	"time"
 
 	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/internal/libs/clist"
@@ -105,6 +106,9 @@ func (r *Reactor) OnStart(ctx context.Context) error {
 
 	go r.processMempoolCh(ctx, ch)
 	go r.processPeerUpdates(ctx, r.peerEvents(ctx), ch)
+	if r.cfg.Broadcast && r.cfg.RebroadcastInterval > 0 {
+		go r.rebroadcastRoutine(ctx, ch)
+	}
 
 	return nil
 }
@@ -249,8 +253,6 @@ func (r *Reactor) processPeerUpdate(ctx context.Context, peerUpdate p2p.PeerUpda
 		}
 
 	case p2p.PeerStatusDown:
-		r.ids.Reclaim(peerUpdate.NodeID)
-
 		// Check if we've started a tx broadcasting goroutine for this peer.
 		// If we have, we signal to terminate the goroutine via the channel's closure.
 		// This will internally decrement the peer waitgroup and remove the peer
@@ -259,6 +261,13 @@ func (r *Reactor) processPeerUpdate(ctx context.Context, peerUpdate p2p.PeerUpda
 		if ok {
 			closer()
 		}
+
+		// The peer's ID may be reserved for another peer after it's reclaimed,
+		// which doesn't have the transactions this peer had.
+		if id := r.ids.GetForPeer(peerUpdate.NodeID); id != UnknownPeerID {
+			r.mempool.removePeer(id)
+		}
+		r.ids.Reclaim(peerUpdate.NodeID)
 	}
 }
 
@@ -319,7 +328,11 @@ func (r *Reactor) broadcastTxRoutine(ctx context.Context, peerID types.NodeID, m
 
 		// NOTE: Transaction batching was disabled due to:
 		// https://github.com/tendermint/tendermint/issues/5796
-		if !memTx.HasPeer(peerMempoolID) {
+		//
+		// Skip transactions the peer already has, because it sent them to us or
+		// we sent them to it before, e.g. before restarting from the front of
+		// the list, and transactions the application asked not to relay.
+		if !memTx.HasPeer(peerMempoolID) && !memTx.NoGossip() {
 			// Send the mempool tx to the corresponding peer. Note, the peer may be
 			// behind and thus would not be able to process the mempool tx correctly.
 			if err := mempoolCh.Send(ctx, p2p.Envelope{
@@ -330,6 +343,7 @@ func (r *Reactor) broadcastTxRoutine(ctx context.Context, peerID types.NodeID, m
 			}); err != nil {
 				return
 			}
+			memTx.SetPeer(peerMempoolID)
 
 			r.logger.Debug("gossiped tx to peer",
 				"tx", tmstrings.LazySprintf("%X", memTx.tx.Hash()),
@@ -345,3 +359,32 @@ func (r *Reactor) broadcastTxRoutine(ctx context.Context, peerID types.NodeID, m
 		}
 	}
 }
+
+// rebroadcastRoutine periodically broadcasts the transactions due to be
+// rebroadcast to all connected peers, including those that already have them,
+// since they may have dropped them since.
+func (r *Reactor) rebroadcastRoutine(ctx context.Context, mempoolCh p2p.Channel) {
+	ticker := time.NewTicker(r.cfg.RebroadcastInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			txs := r.mempool.rebroadcastTxs(now)
+			for _, tx := range txs {
+				if err := mempoolCh.Send(ctx, p2p.Envelope{
+					Broadcast: true,
+					Message:   &protomem.Txs{Txs: [][]byte{tx}},
+				}); err != nil {
+					return
+				}
+			}
+			if len(txs) > 0 {
+				r.mempool.metrics.RebroadcastTxs.Add(float64(len(txs)))
+				r.logger.Debug("rebroadcast transactions to peers", "num_txs", len(txs))
+			}
+		}
+	}
+}
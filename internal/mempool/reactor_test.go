@@ -423,3 +423,44 @@ func TestBroadcastTxForPeerStopsWhenPeerStops(t *testing.T) {
 	require.Equal(t, 4, rts.mempools[primary].Size())
 	require.Equal(t, 0, rts.mempools[secondary].Size())
 }
+
+func TestReactorBroadcastRecordsPeer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.NewNopLogger()
+	rts := setupReactors(ctx, t, logger, 2, 0)
+
+	primary := rts.nodes[0]
+	secondary := rts.nodes[1]
+
+	rts.start(ctx, t)
+
+	txs := checkTxs(ctx, t, rts.mempools[primary], 4, UnknownPeerID)
+	rts.waitForTxns(t, convertTex(txs), secondary)
+
+	// The primary remembers that it sent the transactions to the secondary,
+	// and the secondary that it received them from the primary.
+	hasPeer := func(node, peer types.NodeID) bool {
+		id := rts.reactors[node].ids.GetForPeer(peer)
+		for _, tx := range rts.mempools[node].allEntriesSorted() {
+			if !tx.HasPeer(id) {
+				return false
+			}
+		}
+		return true
+	}
+	require.Eventually(t, func() bool { return hasPeer(primary, secondary) },
+		time.Minute, 50*time.Millisecond)
+	require.True(t, hasPeer(secondary, primary))
+
+	// It forgets once the secondary disconnects.
+	id := rts.reactors[primary].ids.GetForPeer(secondary)
+	rts.reactors[primary].processPeerUpdate(ctx, p2p.PeerUpdate{
+		Status: p2p.PeerStatusDown,
+		NodeID: secondary,
+	}, nil)
+	for _, tx := range rts.mempools[primary].allEntriesSorted() {
+		require.False(t, tx.HasPeer(id))
+	}
+}
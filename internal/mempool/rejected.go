@@ -0,0 +1,74 @@
+package mempool
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// RejectedTx describes why the mempool rejected a transaction, or dropped it
+// after a failed recheck.
+type RejectedTx struct {
+	Code      uint32    // the application's CheckTx code, if it rejected the transaction
+	Codespace string    // the application's CheckTx codespace
+	Data      []byte    // the application's CheckTx data
+	Reason    string    // why the mempool rejected the transaction
+	Height    int64     // the latest block height when the transaction was rejected
+	Time      time.Time // when the transaction was rejected
+}
+
+// Reasons for rejecting a transaction.
+const (
+	RejectedInvalid   = "rejected by the application in CheckTx"
+	RejectedRecheck   = "rejected by the application when rechecked after a block"
+	RejectedPostCheck = "rejected by the post-check filter"
+	RejectedFull      = "mempool is full"
+	RejectedSender    = "sender already has a transaction in the mempool"
+)
+
+// rejectedTxCache is a thread-safe LRU cache of recently rejected transactions
+// and the reasons they were rejected. The LRU order of the keys is kept by an
+// LRUTxCache.
+type rejectedTxCache struct {
+	mtx  sync.Mutex
+	keys *LRUTxCache
+	txs  map[types.TxKey]RejectedTx
+}
+
+func newRejectedTxCache(size int) *rejectedTxCache {
+	return &rejectedTxCache{
+		keys: NewLRUTxCache(size),
+		txs:  make(map[types.TxKey]RejectedTx, size),
+	}
+}
+
+// add records that the transaction with the given key was rejected, replacing
+// any earlier record for it.
+func (c *rejectedTxCache) add(key types.TxKey, rejected RejectedTx) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if _, evicted, ok := c.keys.pushKey(key); ok {
+		delete(c.txs, evicted)
+	}
+	c.txs[key] = rejected
+}
+
+// get returns the record of the transaction with the given key, if any.
+func (c *rejectedTxCache) get(key types.TxKey) (RejectedTx, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	rejected, ok := c.txs[key]
+	return rejected, ok
+}
+
+// remove forgets the transaction with the given key, e.g. once it's accepted.
+func (c *rejectedTxCache) remove(key types.TxKey) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.keys.removeKey(key)
+	delete(c.txs, key)
+}
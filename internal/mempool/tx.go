@@ -27,17 +27,22 @@ type WrappedTx struct {
 	height    int64       // height when this transaction was initially checked (for expiry)
 	timestamp time.Time   // time when transaction was entered (for TTL)
 
-	mtx       sync.Mutex
-	gasWanted int64           // app: gas required to execute this transaction
-	priority  int64           // app: priority value for this transaction
-	sender    string          // app: assigned sender label
-	peers     map[uint16]bool // peer IDs who have sent us this transaction
+	mtx          sync.Mutex
+	gasWanted    int64           // app: gas required to execute this transaction
+	priority     int64           // app: priority value for this transaction
+	sender       string          // app: assigned sender label
+	noGossip     bool            // app: don't relay this transaction to peers
+	proposalOnly bool            // app: only use this transaction in block proposals
+	peers        map[uint16]bool // peer IDs who have sent us this transaction, or been sent it
+
+	rebroadcasts int // number of times this transaction was rebroadcast
 }
 
 // Size reports the size of the raw transaction in bytes.
 func (w *WrappedTx) Size() int64 { return int64(len(w.tx)) }
 
-// SetPeer adds the specified peer ID as a sender of w.
+// SetPeer records that the peer with the specified ID has w, either because
+// it sent w to us or because we sent w to it.
 func (w *WrappedTx) SetPeer(id uint16) {
 	w.mtx.Lock()
 	defer w.mtx.Unlock()
@@ -48,7 +53,7 @@ func (w *WrappedTx) SetPeer(id uint16) {
 	}
 }
 
-// HasPeer reports whether the specified peer ID is a sender of w.
+// HasPeer reports whether the peer with the specified ID has w.
 func (w *WrappedTx) HasPeer(id uint16) bool {
 	w.mtx.Lock()
 	defer w.mtx.Unlock()
@@ -56,6 +61,13 @@ func (w *WrappedTx) HasPeer(id uint16) bool {
 	return ok
 }
 
+// RemovePeer forgets that the peer with the specified ID has w.
+func (w *WrappedTx) RemovePeer(id uint16) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	delete(w.peers, id)
+}
+
 // SetGasWanted sets the application-assigned gas requirement of w.
 func (w *WrappedTx) SetGasWanted(gas int64) {
 	w.mtx.Lock()
@@ -97,3 +109,28 @@ func (w *WrappedTx) Priority() int64 {
 	defer w.mtx.Unlock()
 	return w.priority
 }
+
+// SetPropagation sets whether the application asked to keep w from peers, and
+// to only use it in block proposals.
+func (w *WrappedTx) SetPropagation(noGossip, proposalOnly bool) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.noGossip = noGossip
+	w.proposalOnly = proposalOnly
+}
+
+// NoGossip reports whether w must not be relayed to peers, either because the
+// application asked so or because w is proposal-only.
+func (w *WrappedTx) NoGossip() bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.noGossip || w.proposalOnly
+}
+
+// ProposalOnly reports whether the application asked to only use w in block
+// proposals, hiding it from RPC clients listing unconfirmed transactions.
+func (w *WrappedTx) ProposalOnly() bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.proposalOnly
+}
@@ -48,9 +48,19 @@ type Mempool interface {
 
 	// ReapMaxTxs reaps up to max transactions from the mempool. If max is
 	// negative, there is no cap on the size of all returned transactions
-	// (~ all available transactions).
+	// (~ all available transactions). Transactions the application marked
+	// proposal-only are left out; they are only reaped by ReapMaxBytesMaxGas.
 	ReapMaxTxs(max int) types.Txs
 
+	// ReapTxsBySender reaps all transactions from the given sender, as
+	// reported by the application in CheckTx, in the order they would be
+	// reaped by ReapMaxTxs, leaving out proposal-only transactions.
+	ReapTxsBySender(sender string) types.Txs
+
+	// RejectedTx reports why the transaction with the given key was rejected,
+	// if it was rejected recently.
+	RejectedTx(key types.TxKey) (RejectedTx, bool)
+
 	// Lock locks the mempool. The consensus must be able to hold lock to safely
 	// update.
 	Lock()
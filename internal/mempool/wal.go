@@ -0,0 +1,207 @@
+package mempool
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// walFile is the name of the write-ahead log file in the mempool WAL directory.
+const walFile = "mempool.wal"
+
+// walCompactRecords is the number of records the WAL may hold in excess of
+// twice the number of transactions in the mempool before it is compacted.
+const walCompactRecords = 10000
+
+// WAL record types.
+const (
+	walRecordAdd    byte = 1 // payload is the transaction
+	walRecordRemove byte = 2 // payload is the transaction key
+)
+
+// walHeaderSize is the size of a WAL record header: the record type, the
+// CRC32C checksum and the length of the payload.
+const walHeaderSize = 1 + 4 + 4
+
+var walCRC32c = crc32.MakeTable(crc32.Castagnoli)
+
+// txWAL is an append-only write-ahead log of the transactions added to and
+// removed from the mempool. Each record is a type byte, followed by the big
+// endian CRC32C checksum and length of the payload, and the payload itself.
+//
+// Records are written to the file without syncing it, so they survive a crash
+// of the node process but not necessarily of the operating system.
+//
+// A txWAL is not safe for concurrent use; the mempool only writes to it while
+// holding its lock exclusively.
+type txWAL struct {
+	path    string
+	file    *os.File
+	records int // the number of records in the file
+}
+
+// openTxWAL opens the WAL file at path for appending, containing the given
+// number of records.
+func openTxWAL(path string, records int) (*txWAL, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &txWAL{path: path, file: file, records: records}, nil
+}
+
+// add records the addition of tx to the mempool.
+func (w *txWAL) add(tx types.Tx) error {
+	return w.write(walRecordAdd, tx)
+}
+
+// remove records the removal of the transaction with the given key.
+func (w *txWAL) remove(key types.TxKey) error {
+	return w.write(walRecordRemove, key[:])
+}
+
+func (w *txWAL) write(kind byte, payload []byte) error {
+	w.records++
+	_, err := w.file.Write(encodeWALRecord(nil, kind, payload))
+	return err
+}
+
+// rewrite replaces the contents of the WAL with an add record for each of txs.
+func (w *txWAL) rewrite(txs []types.Tx) error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	if err := writeTxWAL(w.path, txs); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(w.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	w.file = file
+	w.records = len(txs)
+	return nil
+}
+
+// close syncs and closes the WAL file.
+func (w *txWAL) close() error {
+	if err := w.file.Sync(); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// encodeWALRecord appends a WAL record of the given type and payload to buf.
+func encodeWALRecord(buf []byte, kind byte, payload []byte) []byte {
+	var header [walHeaderSize]byte
+	header[0] = kind
+	binary.BigEndian.PutUint32(header[1:5], crc32.Checksum(payload, walCRC32c))
+	binary.BigEndian.PutUint32(header[5:9], uint32(len(payload)))
+	buf = append(buf, header[:]...)
+	return append(buf, payload...)
+}
+
+// writeTxWAL atomically replaces the WAL file at path with one containing an
+// add record for each of txs, and syncs it to disk.
+func writeTxWAL(path string, txs []types.Tx) error {
+	var buf []byte
+	for _, tx := range txs {
+		buf = encodeWALRecord(buf, walRecordAdd, tx)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), walFile+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // nolint: errcheck // no-op once renamed
+	if _, err := tmp.Write(buf); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// readTxWAL reads the WAL file at path, and returns the transactions that were
+// added and not removed again, in the order they were added. A missing file
+// holds no transactions.
+//
+// A truncated or corrupted record at the end of the file, as left behind by a
+// crash in the middle of a write, ends the log and is ignored. A corrupted
+// record followed by further records is an error.
+func readTxWAL(path string) ([]types.Tx, error) {
+	bz, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var (
+		txs   []types.Tx
+		index = map[types.TxKey]int{} // index of live transactions in txs
+	)
+	for offset := 0; offset < len(bz); {
+		if len(bz)-offset < walHeaderSize {
+			break
+		}
+		kind := bz[offset]
+		crc := binary.BigEndian.Uint32(bz[offset+1 : offset+5])
+		length := int(binary.BigEndian.Uint32(bz[offset+5 : offset+9]))
+		end := offset + walHeaderSize + length
+		if end > len(bz) {
+			break
+		}
+		payload := bz[offset+walHeaderSize : end]
+		if crc32.Checksum(payload, walCRC32c) != crc {
+			if end == len(bz) {
+				break
+			}
+			return nil, fmt.Errorf("corrupted mempool WAL record at offset %d: checksum mismatch", offset)
+		}
+
+		switch kind {
+		case walRecordAdd:
+			// The mempool holds at most one copy of a transaction.
+			key := types.Tx(payload).Key()
+			if i, ok := index[key]; ok {
+				txs[i] = nil
+			}
+			index[key] = len(txs)
+			txs = append(txs, types.Tx(payload))
+		case walRecordRemove:
+			var key types.TxKey
+			if len(payload) != len(key) {
+				return nil, fmt.Errorf("invalid mempool WAL record at offset %d: key has %d bytes", offset, len(payload))
+			}
+			copy(key[:], payload)
+			if i, ok := index[key]; ok {
+				txs[i] = nil
+				delete(index, key)
+			}
+		default:
+			return nil, fmt.Errorf("invalid mempool WAL record at offset %d: unknown type %d", offset, kind)
+		}
+		offset = end
+	}
+
+	live := make([]types.Tx, 0, len(index))
+	for _, tx := range txs {
+		if tx != nil {
+			live = append(live, tx)
+		}
+	}
+	return live, nil
+}
@@ -24,6 +24,16 @@ var (
 	reSchemeIsHost = regexp.MustCompile(`^[^/:]+:\d+(/|$)`)
 )
 
+// dnsResolver looks up DNS records, see net.Resolver.
+type dnsResolver interface {
+	LookupIP(ctx context.Context, network, host string) ([]net.IP, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// resolver looks up DNS records for node addresses. It is a variable so that
+// tests can replace it.
+var resolver dnsResolver = net.DefaultResolver
+
 // NodeAddress is a node address URL. It differs from a transport Endpoint in
 // that it contains the node's ID, and that the address hostname may be resolved
 // into multiple IP addresses (and thus multiple endpoints).
@@ -115,7 +125,30 @@ func (a NodeAddress) Resolve(ctx context.Context) ([]*Endpoint, error) {
 		}}, nil
 	}
 
-	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", a.Hostname)
+	// Onion addresses can't be resolved, and are dialed by hostname through a
+	// proxy instead.
+	if types.IsOnionHost(a.Hostname) {
+		return []*Endpoint{{
+			Protocol: a.Protocol,
+			Hostname: a.Hostname,
+			Port:     a.Port,
+			Path:     a.Path,
+		}}, nil
+	}
+
+	// Hostnames of the form _service._proto.name are resolved via DNS SRV
+	// records, which may point to several hosts and ports. This allows seed
+	// operators to move nodes around without changing node configuration.
+	if isSRVName(a.Hostname) {
+		return a.resolveSRV(ctx)
+	}
+
+	return a.resolveHost(ctx, a.Hostname, a.Port)
+}
+
+// resolveHost resolves a hostname into endpoints with the given port.
+func (a NodeAddress) resolveHost(ctx context.Context, host string, port uint16) ([]*Endpoint, error) {
+	ips, err := resolver.LookupIP(ctx, "ip", host)
 	if err != nil {
 		return nil, err
 	}
@@ -124,13 +157,44 @@ func (a NodeAddress) Resolve(ctx context.Context) ([]*Endpoint, error) {
 		endpoints[i] = &Endpoint{
 			Protocol: a.Protocol,
 			IP:       ip,
-			Port:     a.Port,
+			Port:     port,
 			Path:     a.Path,
 		}
 	}
 	return endpoints, nil
 }
 
+// resolveSRV resolves the address' SRV records into endpoints, in the order of
+// the records' priority and weight. Targets that fail to resolve are skipped,
+// unless none of them resolve.
+func (a NodeAddress) resolveSRV(ctx context.Context) ([]*Endpoint, error) {
+	_, records, err := resolver.LookupSRV(ctx, "", "", a.Hostname)
+	if err != nil {
+		return nil, err
+	}
+	endpoints := []*Endpoint{}
+	for _, record := range records {
+		targetEndpoints, targetErr := a.resolveHost(ctx, strings.TrimSuffix(record.Target, "."), record.Port)
+		if targetErr != nil {
+			err = targetErr
+			continue
+		}
+		endpoints = append(endpoints, targetEndpoints...)
+	}
+	if len(endpoints) == 0 && err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// isSRVName returns true if the hostname is an SRV record name, i.e. of the
+// form _service._proto.name.
+func isSRVName(host string) bool {
+	parts := strings.SplitN(host, ".", 3)
+	return len(parts) == 3 && len(parts[0]) > 1 && len(parts[1]) > 1 &&
+		parts[0][0] == '_' && parts[1][0] == '_'
+}
+
 // String formats the address as a URL string.
 func (a NodeAddress) String() string {
 	u := url.URL{Scheme: string(a.Protocol)}
@@ -172,5 +236,11 @@ func (a NodeAddress) Validate() error {
 	if a.Port > 0 && a.Hostname == "" {
 		return errors.New("cannot specify port without hostname")
 	}
+	if strings.HasSuffix(a.Hostname, ".onion") && !types.IsOnionHost(a.Hostname) {
+		return fmt.Errorf("invalid onion address %q", a.Hostname)
+	}
+	if a.Port > 0 && isSRVName(a.Hostname) {
+		return errors.New("cannot specify port for SRV record name")
+	}
 	return nil
 }
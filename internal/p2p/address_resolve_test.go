@@ -0,0 +1,80 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResolver resolves names from static records.
+type fakeResolver struct {
+	ips map[string][]net.IP
+	srv map[string][]*net.SRV
+}
+
+func (r fakeResolver) LookupIP(_ context.Context, _, host string) ([]net.IP, error) {
+	if ips, ok := r.ips[host]; ok {
+		return ips, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func (r fakeResolver) LookupSRV(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+	if records, ok := r.srv[name]; ok {
+		return "", records, nil
+	}
+	return "", nil, errors.New("no such host")
+}
+
+func TestNodeAddress_ResolveSRV(t *testing.T) {
+	ctx := context.Background()
+
+	defer func(r dnsResolver) { resolver = r }(resolver)
+	resolver = fakeResolver{
+		ips: map[string][]net.IP{
+			"a.example.com": {net.IPv4(10, 0, 0, 1), net.IPv4(10, 0, 0, 2)},
+			"b.example.com": {net.IPv4(10, 0, 0, 3)},
+		},
+		srv: map[string][]*net.SRV{
+			"_p2p._tcp.example.com": {
+				{Target: "a.example.com.", Port: 26656},
+				{Target: "missing.example.com.", Port: 26656},
+				{Target: "b.example.com.", Port: 26666},
+			},
+			"_p2p._tcp.missing.com": {
+				{Target: "missing.example.com.", Port: 26656},
+			},
+		},
+	}
+
+	id := "00112233445566778899aabbccddeeff00112233"
+
+	address, err := ParseNodeAddress(id + "@_p2p._tcp.example.com")
+	require.NoError(t, err)
+	endpoints, err := address.Resolve(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 3)
+	require.Equal(t, "mconn://10.0.0.1:26656", endpoints[0].String())
+	require.Equal(t, "mconn://10.0.0.2:26656", endpoints[1].String())
+	require.Equal(t, "mconn://10.0.0.3:26666", endpoints[2].String())
+
+	// Regular hostnames still resolve via A/AAAA records.
+	address, err = ParseNodeAddress(id + "@b.example.com:26656")
+	require.NoError(t, err)
+	endpoints, err = address.Resolve(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+
+	// It errors if none of the targets resolve.
+	address, err = ParseNodeAddress(id + "@_p2p._tcp.missing.com")
+	require.NoError(t, err)
+	_, err = address.Resolve(ctx)
+	require.Error(t, err)
+
+	// SRV records specify the port.
+	_, err = ParseNodeAddress(id + "@_p2p._tcp.example.com:26656")
+	require.Error(t, err)
+}
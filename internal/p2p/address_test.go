@@ -175,8 +175,15 @@ func TestParseNodeAddress(t *testing.T) {
 			true,
 		},
 
+		{
+			user + "@VWW6YBAL4BD7SZMGNCYRUUCPGFKQAHZDDI37KTCEO3AH7NGMCOPNPYYD.ONION:26656",
+			p2p.NodeAddress{Protocol: "mconn", NodeID: id, Hostname: "vww6ybal4bd7szmgncyruucpgfkqahzddi37ktceo3ah7ngmcopnpyyd.onion", Port: 26656},
+			true,
+		},
+
 		// Invalid addresses.
 		{"", p2p.NodeAddress{}, false},
+		{user + "@invalid.onion:26656", p2p.NodeAddress{}, false},
 		{"127.0.0.1", p2p.NodeAddress{}, false},
 		{"hostname", p2p.NodeAddress{}, false},
 		{"scheme:", p2p.NodeAddress{}, false},
@@ -256,6 +263,13 @@ func TestNodeAddress_Resolve(t *testing.T) {
 			false,
 		},
 
+		// Onion addresses are not resolved.
+		{
+			p2p.NodeAddress{Protocol: "tcp", Hostname: "vww6ybal4bd7szmgncyruucpgfkqahzddi37ktceo3ah7ngmcopnpyyd.onion", Port: 80},
+			&p2p.Endpoint{Protocol: "tcp", Hostname: "vww6ybal4bd7szmgncyruucpgfkqahzddi37ktceo3ah7ngmcopnpyyd.onion", Port: 80},
+			true,
+		},
+
 		// Valid non-networked addresses.
 		{
 			p2p.NodeAddress{Protocol: "memory", NodeID: id},
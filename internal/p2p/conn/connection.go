@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
 
 	"github.com/tendermint/tendermint/internal/libs/flowrate"
 	"github.com/tendermint/tendermint/internal/libs/protoio"
@@ -23,8 +24,13 @@ import (
 	tmmath "github.com/tendermint/tendermint/libs/math"
 	"github.com/tendermint/tendermint/libs/service"
 	tmp2p "github.com/tendermint/tendermint/proto/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
 )
 
+// CompressionSnappy is the name of the snappy compression algorithm, as
+// negotiated in the handshake.
+const CompressionSnappy = "snappy"
+
 const (
 	// mirrors MaxPacketMsgPayloadSize from config/config.go
 	defaultMaxPacketMsgPayloadSize = 1400
@@ -47,11 +53,39 @@ const (
 	defaultSendTimeout         = 10 * time.Second
 	defaultPingInterval        = 60 * time.Second
 	defaultPongTimeout         = 90 * time.Second
+	defaultMaxMissedPongs      = 3
+
+	// how long Disconnect waits for the disconnect packet to be sent before
+	// closing the connection anyway
+	disconnectTimeout = time.Second
+
+	// each ping RTT sample moves the smoothed RTT 1/rttSmoothing of the way,
+	// as for TCP's smoothed RTT
+	rttSmoothing = 8
+
+	// how long to wait before retrying to send when all channels with pending
+	// messages have exceeded their send rate
+	channelThrottleInterval = 10 * time.Millisecond
 )
 
 type receiveCbFunc func(ctx context.Context, chID ChannelID, msgBytes []byte)
 type errorCbFunc func(context.Context, interface{})
 
+// ErrDisconnected is passed to the error callback when the peer closes the
+// connection with a PacketDisconnect, giving the reason it disconnected.
+type ErrDisconnected struct {
+	Reason tmp2p.DisconnectReason
+}
+
+func (e ErrDisconnected) Error() string {
+	return fmt.Sprintf("peer disconnected: %v", e.Reason)
+}
+
+// ErrPongTimeout is passed to the error callback when the peer is considered
+// dead, because it didn't send anything within the pong timeout or didn't
+// answer too many consecutive pings.
+var ErrPongTimeout = errors.New("pong timeout")
+
 /*
 Each peer has one `MConnection` (multiplex connection) instance.
 
@@ -83,6 +117,7 @@ type MConnection struct {
 	recvMonitor   *flowrate.Monitor
 	send          chan struct{}
 	pong          chan struct{}
+	disconnect    chan tmp2p.DisconnectReason
 	channels      []*channel
 	channelsIdx   map[ChannelID]*channel
 	onReceive     receiveCbFunc
@@ -90,6 +125,16 @@ type MConnection struct {
 	errored       uint32
 	config        MConnConfig
 
+	// pingSentAt is when the last unanswered ping was sent, and rtt the
+	// smoothed ping round-trip time, both in nanoseconds and accessed
+	// atomically. 0 means unset.
+	pingSentAt int64
+	rtt        int64
+
+	// missedPongs is the number of consecutive pings that weren't answered
+	// before the next ping was due. Accessed atomically.
+	missedPongs int32
+
 	// Closing quitSendRoutine will cause the sendRoutine to eventually quit.
 	// doneSendRoutine is closed when the sendRoutine actually quits.
 	quitSendRoutine chan struct{}
@@ -105,8 +150,9 @@ type MConnection struct {
 
 	cancel context.CancelFunc
 
-	flushTimer *timer.ThrottleTimer // flush writes as necessary but throttled.
-	pingTimer  *time.Ticker         // send pings periodically
+	flushTimer    *timer.ThrottleTimer // flush writes as necessary but throttled.
+	throttleTimer *timer.ThrottleTimer // retry sending on rate-limited channels.
+	pingTimer     *time.Ticker         // send pings periodically
 
 	// close conn if pong is not received in pongTimeout
 	lastMsgRecv struct {
@@ -138,6 +184,16 @@ type MConnConfig struct {
 	// Maximum wait time for pongs
 	PongTimeout time.Duration `mapstructure:"pong_timeout"`
 
+	// Maximum number of consecutive pings the peer may leave unanswered
+	// until the next ping is due, before the connection is considered dead.
+	// 0 disables this check.
+	MaxMissedPongs int `mapstructure:"max_missed_pongs"`
+
+	// Compression algorithm used for messages on channels that have Compress
+	// set, e.g. CompressionSnappy. It must have been negotiated with the peer
+	// during the handshake. Empty disables compression.
+	Compression string `mapstructure:"compression"`
+
 	// Process/Transport Start time
 	StartTime time.Time `mapstructure:",omitempty"`
 }
@@ -151,6 +207,7 @@ func DefaultMConnConfig() MConnConfig {
 		FlushThrottle:           defaultFlushThrottle,
 		PingInterval:            defaultPingInterval,
 		PongTimeout:             defaultPongTimeout,
+		MaxMissedPongs:          defaultMaxMissedPongs,
 		StartTime:               time.Now(),
 	}
 }
@@ -173,6 +230,7 @@ func NewMConnection(
 		recvMonitor:   flowrate.New(config.StartTime, 0, 0),
 		send:          make(chan struct{}, 1),
 		pong:          make(chan struct{}, 1),
+		disconnect:    make(chan tmp2p.DisconnectReason, 1),
 		onReceive:     onReceive,
 		onError:       onError,
 		config:        config,
@@ -203,6 +261,7 @@ func NewMConnection(
 // OnStart implements BaseService
 func (c *MConnection) OnStart(ctx context.Context) error {
 	c.flushTimer = timer.NewThrottleTimer("flush", c.config.FlushThrottle)
+	c.throttleTimer = timer.NewThrottleTimer("throttle", channelThrottleInterval)
 	c.pingTimer = time.NewTicker(c.config.PingInterval)
 	c.chStatsTimer = time.NewTicker(updateStats)
 	c.quitSendRoutine = make(chan struct{})
@@ -249,6 +308,7 @@ func (c *MConnection) stopServices() (alreadyStopped bool) {
 	}
 
 	c.flushTimer.Stop()
+	c.throttleTimer.Stop()
 	c.pingTimer.Stop()
 	c.chStatsTimer.Stop()
 
@@ -330,6 +390,52 @@ func (c *MConnection) Send(chID ChannelID, msgBytes []byte) bool {
 	return success
 }
 
+// Disconnect tells the peer why the connection is being closed, and stops the
+// connection. This is best effort: the reason is not sent if the connection
+// is not running, or if it can't be sent within disconnectTimeout.
+func (c *MConnection) Disconnect(reason tmp2p.DisconnectReason) {
+	if c.IsRunning() {
+		select {
+		case c.disconnect <- reason:
+			timer := time.NewTimer(disconnectTimeout)
+			defer timer.Stop()
+			select {
+			case <-c.doneSendRoutine:
+			case <-timer.C:
+			}
+		default:
+		}
+	}
+	c.Stop()
+}
+
+// recordPong updates the smoothed round-trip time with the time since the
+// last ping was sent.
+func (c *MConnection) recordPong(now time.Time) {
+	sentAt := atomic.SwapInt64(&c.pingSentAt, 0)
+	if sentAt == 0 {
+		return // unsolicited pong
+	}
+	atomic.StoreInt32(&c.missedPongs, 0)
+	sample := now.UnixNano() - sentAt
+	if sample <= 0 {
+		sample = 1
+	}
+	rtt := atomic.LoadInt64(&c.rtt)
+	if rtt == 0 {
+		rtt = sample
+	} else {
+		rtt += (sample - rtt) / rttSmoothing
+	}
+	atomic.StoreInt64(&c.rtt, rtt)
+}
+
+// RTT returns the smoothed round-trip time of pings sent on the connection, or
+// 0 if no ping has been answered yet.
+func (c *MConnection) RTT() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.rtt))
+}
+
 // sendRoutine polls for packets to send from channels.
 func (c *MConnection) sendRoutine(ctx context.Context) {
 	defer c._recover(ctx)
@@ -347,11 +453,26 @@ FOR_LOOP:
 			// NOTE: flushTimer.Set() must be called every time
 			// something is written to .bufConnWriter.
 			c.flush()
+		case <-c.throttleTimer.Ch:
+			// Rate-limited channels may be able to send again.
+			select {
+			case c.send <- struct{}{}:
+			default:
+			}
 		case <-c.chStatsTimer.C:
 			for _, channel := range c.channels {
 				channel.updateStats()
 			}
 		case <-c.pingTimer.C:
+			// record the send time first, since the pong may arrive
+			// before the flush returns
+			if atomic.SwapInt64(&c.pingSentAt, time.Now().UnixNano()) != 0 {
+				missed := atomic.AddInt32(&c.missedPongs, 1)
+				if max := c.config.MaxMissedPongs; max > 0 && int(missed) >= max {
+					err = fmt.Errorf("%w: %d consecutive pings unanswered", ErrPongTimeout, missed)
+					break SELECTION
+				}
+			}
 			_n, err = protoWriter.WriteMsg(mustWrapPacket(&tmp2p.PacketPing{}))
 			if err != nil {
 				c.logger.Error("Failed to send PacketPing", "err", err)
@@ -367,6 +488,16 @@ FOR_LOOP:
 			}
 			c.sendMonitor.Update(_n)
 			c.flush()
+		case reason := <-c.disconnect:
+			_n, err = protoWriter.WriteMsg(mustWrapPacket(&tmp2p.PacketDisconnect{Reason: reason}))
+			if err != nil {
+				c.logger.Error("Failed to send PacketDisconnect", "err", err)
+				break SELECTION
+			}
+			c.sendMonitor.Update(_n)
+			c.flush()
+			// Disconnect stops the connection once we're done.
+			break FOR_LOOP
 		case <-ctx.Done():
 			break FOR_LOOP
 		case <-c.quitSendRoutine:
@@ -392,7 +523,7 @@ FOR_LOOP:
 		}
 
 		if time.Since(c.getLastMessageAt()) > c.config.PongTimeout {
-			err = errors.New("pong timeout")
+			err = ErrPongTimeout
 		}
 
 		if err != nil {
@@ -432,11 +563,17 @@ func (c *MConnection) sendPacketMsg(ctx context.Context) bool {
 	// The chosen channel will be the one whose recentlySent/priority is the least.
 	var leastRatio float32 = math.MaxFloat32
 	var leastChannel *channel
+	var throttled bool
 	for _, channel := range c.channels {
 		// If nothing to send, skip this channel
 		if !channel.isSendPending() {
 			continue
 		}
+		// If the channel has exceeded its send rate, skip it for now
+		if !channel.canSend(c._maxPacketMsgSize) {
+			throttled = true
+			continue
+		}
 		// Get ratio, and keep track of lowest ratio.
 		ratio := float32(channel.recentlySent) / float32(channel.desc.Priority)
 		if ratio < leastRatio {
@@ -445,8 +582,12 @@ func (c *MConnection) sendPacketMsg(ctx context.Context) bool {
 		}
 	}
 
-	// Nothing to send?
+	// Nothing to send? If channels were skipped due to their send rate, try
+	// again shortly.
 	if leastChannel == nil {
+		if throttled {
+			c.throttleTimer.Set()
+		}
 		return true
 	}
 	// c.logger.Info("Found a msgPacket to send")
@@ -539,9 +680,15 @@ FOR_LOOP:
 				// never block
 			}
 		case *tmp2p.Packet_PacketPong:
-			// do nothing, we updated the "last message
-			// received" timestamp above, so we can ignore
-			// this message
+			// we updated the "last message received"
+			// timestamp above, so we only need to record
+			// the round-trip time
+			c.recordPong(time.Now())
+		case *tmp2p.Packet_PacketDisconnect:
+			err := ErrDisconnected{Reason: pkt.PacketDisconnect.Reason}
+			c.logger.Debug("Connection closed by peer @ recvRoutine", "conn", c, "reason", err.Reason)
+			c.stopForError(ctx, err)
+			break FOR_LOOP
 		case *tmp2p.Packet_PacketMsg:
 			channelID := ChannelID(pkt.PacketMsg.ChannelID)
 			channel, ok := c.channelsIdx[channelID]
@@ -552,6 +699,12 @@ FOR_LOOP:
 				break FOR_LOOP
 			}
 
+			// Block until the channel's receive rate allows us to continue.
+			channel.recvMonitor.Update(_n)
+			if channel.desc.RecvRate > 0 {
+				channel.recvMonitor.Limit(c._maxPacketMsgSize, channel.desc.RecvRate, true)
+			}
+
 			msgBytes, err := channel.recvPacketMsg(*pkt.PacketMsg)
 			if err != nil {
 				if c.IsRunning() {
@@ -622,6 +775,33 @@ type ChannelDescriptor struct {
 	// Human readable name of the channel, used in logging and
 	// diagnostics.
 	Name string
+
+	// SendRate and RecvRate limit the rate in bytes/second at which data is
+	// sent and received on the channel, in addition to the connection-wide
+	// limits, e.g. so that bulk data can't starve other channels. A channel
+	// that exceeds its receive rate pauses reading from the connection. 0
+	// means no channel limit.
+	SendRate int64
+	RecvRate int64
+
+	// RecvMessageRate limits the number of messages per second each peer may
+	// send on the channel, with bursts of up to RecvMessageBurst messages.
+	// Messages above the limit are dropped by the router, and peers that keep
+	// exceeding it are disconnected and banned. 0 means no limit.
+	RecvMessageRate  float64
+	RecvMessageBurst int
+
+	// Compress enables compression of the channel's messages, if the
+	// connection has negotiated a compression algorithm. It should be set for
+	// channels with bulky messages, and left unset for latency-sensitive
+	// control channels.
+	Compress bool
+
+	// OnInvalidMessage is called by the router when a peer sends a message
+	// on the channel that can't be decoded or unwrapped. Such messages are
+	// always dropped; the hook lets the channel's reactor decide whether the
+	// peer should be punished for it. nil means the message is just logged.
+	OnInvalidMessage func(peerID types.NodeID, err error)
 }
 
 func (chDesc ChannelDescriptor) FillDefaults() (filled ChannelDescriptor) {
@@ -652,6 +832,9 @@ type channel struct {
 	sendQueueSize int32 // atomic.
 	recving       []byte
 	sending       []byte
+	sendMonitor   *flowrate.Monitor
+	recvMonitor   *flowrate.Monitor
+	compress      bool
 
 	maxPacketMsgPayloadSize int
 
@@ -668,6 +851,9 @@ func newChannel(conn *MConnection, desc ChannelDescriptor) *channel {
 		desc:                    desc,
 		sendQueue:               make(chan []byte, desc.SendQueueCapacity),
 		recving:                 make([]byte, 0, desc.RecvBufferCapacity),
+		sendMonitor:             flowrate.New(conn.config.StartTime, 0, 0),
+		recvMonitor:             flowrate.New(conn.config.StartTime, 0, 0),
+		compress:                desc.Compress && conn.config.Compression == CompressionSnappy,
 		maxPacketMsgPayloadSize: conn.config.MaxPacketMsgPayloadSize,
 		logger:                  conn.logger,
 	}
@@ -677,6 +863,9 @@ func newChannel(conn *MConnection, desc ChannelDescriptor) *channel {
 // Goroutine-safe
 // Times out (and returns false) after defaultSendTimeout
 func (ch *channel) sendBytes(bytes []byte) bool {
+	if ch.compress {
+		bytes = snappy.Encode(nil, bytes)
+	}
 	select {
 	case ch.sendQueue <- bytes:
 		atomic.AddInt32(&ch.sendQueueSize, 1)
@@ -701,6 +890,13 @@ func (ch *channel) isSendPending() bool {
 	return true
 }
 
+// Returns true if the channel may send another packet of the given size
+// without exceeding its send rate.
+// Goroutine-safe
+func (ch *channel) canSend(packetSize int) bool {
+	return ch.desc.SendRate <= 0 || ch.sendMonitor.Limit(packetSize, ch.desc.SendRate, false) > 0
+}
+
 // Creates a new PacketMsg to send.
 // Not goroutine-safe
 func (ch *channel) nextPacketMsg() tmp2p.PacketMsg {
@@ -724,6 +920,7 @@ func (ch *channel) writePacketMsgTo(w io.Writer) (n int, err error) {
 	packet := ch.nextPacketMsg()
 	n, err = protoio.NewDelimitedWriter(w).WriteMsg(mustWrapPacket(&packet))
 	atomic.AddInt64(&ch.recentlySent, int64(n))
+	ch.sendMonitor.Update(n)
 	return
 }
 
@@ -740,11 +937,32 @@ func (ch *channel) recvPacketMsg(packet tmp2p.PacketMsg) ([]byte, error) {
 	if packet.EOF {
 		msgBytes := ch.recving
 		ch.recving = make([]byte, 0, ch.desc.RecvBufferCapacity)
+		if ch.compress {
+			return ch.decompress(msgBytes)
+		}
 		return msgBytes, nil
 	}
 	return nil, nil
 }
 
+// decompress decodes a compressed message, checking the decoded size against
+// the channel's receive capacity before allocating it.
+// Not goroutine-safe
+func (ch *channel) decompress(msgBytes []byte) ([]byte, error) {
+	size, err := snappy.DecodedLen(msgBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compressed message: %w", err)
+	}
+	if size > ch.desc.RecvMessageCapacity {
+		return nil, fmt.Errorf("received message exceeds available capacity: %v < %v", ch.desc.RecvMessageCapacity, size)
+	}
+	decoded, err := snappy.Decode(nil, msgBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compressed message: %w", err)
+	}
+	return decoded, nil
+}
+
 // Call this periodically to update stats for throttling purposes.
 // Not goroutine-safe
 func (ch *channel) updateStats() {
@@ -781,6 +999,12 @@ func mustWrapPacket(pb proto.Message) *tmp2p.Packet {
 				PacketMsg: pb,
 			},
 		}
+	case *tmp2p.PacketDisconnect:
+		msg = tmp2p.Packet{
+			Sum: &tmp2p.Packet_PacketDisconnect{
+				PacketDisconnect: pb,
+			},
+		}
 	default:
 		panic(fmt.Errorf("unknown packet type %T", pb))
 	}
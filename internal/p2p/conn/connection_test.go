@@ -161,6 +161,134 @@ func TestMConnectionReceive(t *testing.T) {
 	}
 }
 
+func TestMConnectionChannelRateLimit(t *testing.T) {
+	const (
+		rate    = 20000 // bytes/s
+		msgSize = 1000
+		numMsgs = 10
+
+		// sending numMsgs at the rate takes around 500ms, while unlimited
+		// sends are only delayed by the 100ms flush throttle.
+		minDelay = 300 * time.Millisecond
+	)
+
+	testcases := map[string]struct {
+		sendRate int64
+		recvRate int64
+	}{
+		"send rate": {sendRate: rate},
+		"recv rate": {recvRate: rate},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			server, client := net.Pipe()
+			t.Cleanup(closeAll(t, client, server))
+
+			receivedCh := make(chan ChannelID, numMsgs+1)
+			onReceive := func(ctx context.Context, chID ChannelID, msgBytes []byte) {
+				receivedCh <- chID
+			}
+			onError := func(ctx context.Context, r interface{}) {}
+			logger := log.NewNopLogger()
+
+			// Channel 0x01 is rate limited, 0x02 isn't.
+			chDescs := func(sendRate, recvRate int64) []*ChannelDescriptor {
+				return []*ChannelDescriptor{
+					{ID: 0x01, Priority: 1, SendQueueCapacity: numMsgs, SendRate: sendRate, RecvRate: recvRate},
+					{ID: 0x02, Priority: 1, SendQueueCapacity: 1},
+				}
+			}
+			sender := NewMConnection(logger, client, chDescs(tc.sendRate, 0), onReceive, onError, DefaultMConnConfig())
+			require.NoError(t, sender.Start(ctx))
+			t.Cleanup(waitAll(sender))
+			receiver := NewMConnection(logger, server, chDescs(0, tc.recvRate), onReceive, onError, DefaultMConnConfig())
+			require.NoError(t, receiver.Start(ctx))
+			t.Cleanup(waitAll(receiver))
+
+			start := time.Now()
+			for i := 0; i < numMsgs; i++ {
+				require.True(t, sender.Send(0x01, make([]byte, msgSize)))
+			}
+			for i := 0; i < numMsgs; i++ {
+				select {
+				case chID := <-receivedCh:
+					require.Equal(t, ChannelID(0x01), chID)
+				case <-time.After(5 * time.Second):
+					t.Fatal("rate limited channel stalled")
+				}
+			}
+			require.GreaterOrEqual(t, time.Since(start), minDelay)
+
+			// The unlimited channel isn't affected.
+			start = time.Now()
+			require.True(t, sender.Send(0x02, make([]byte, msgSize)))
+			require.Equal(t, ChannelID(0x02), <-receivedCh)
+			require.Less(t, time.Since(start), minDelay)
+		})
+	}
+}
+
+func TestMConnectionCompression(t *testing.T) {
+	const msgSize = 10000
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, client := net.Pipe()
+	t.Cleanup(closeAll(t, client, server))
+
+	receivedCh := make(chan []byte, 1)
+	errorsCh := make(chan interface{}, 1)
+	onReceive := func(ctx context.Context, chID ChannelID, msgBytes []byte) {
+		receivedCh <- msgBytes
+	}
+	onError := func(ctx context.Context, r interface{}) {
+		errorsCh <- r
+	}
+	logger := log.NewNopLogger()
+
+	// Channels 0x01 and 0x02 are compressed, 0x03 isn't. The receiver can't
+	// take messages of msgSize on 0x02 once decompressed.
+	chDescs := func(recvCap int) []*ChannelDescriptor {
+		return []*ChannelDescriptor{
+			{ID: 0x01, Priority: 1, SendQueueCapacity: 1, Compress: true},
+			{ID: 0x02, Priority: 1, SendQueueCapacity: 1, Compress: true, RecvMessageCapacity: recvCap},
+			{ID: 0x03, Priority: 1, SendQueueCapacity: 1},
+		}
+	}
+	cfg := DefaultMConnConfig()
+	cfg.Compression = CompressionSnappy
+	sender := NewMConnection(logger, client, chDescs(0), onReceive, onError, cfg)
+	require.NoError(t, sender.Start(ctx))
+	t.Cleanup(waitAll(sender))
+	receiver := NewMConnection(logger, server, chDescs(msgSize/2), onReceive, onError, cfg)
+	require.NoError(t, receiver.Start(ctx))
+	t.Cleanup(waitAll(receiver))
+
+	msg := make([]byte, msgSize)
+	require.True(t, sender.Send(0x01, msg))
+	require.Equal(t, msg, <-receivedCh)
+	require.Less(t, sender.channelsIdx[0x01].sendMonitor.Status().Bytes, int64(msgSize/10))
+
+	require.True(t, sender.Send(0x03, msg))
+	require.Equal(t, msg, <-receivedCh)
+	require.Greater(t, sender.channelsIdx[0x03].sendMonitor.Status().Bytes, int64(msgSize))
+
+	// A message that exceeds the capacity once decompressed is rejected.
+	require.True(t, sender.Send(0x02, msg))
+	select {
+	case <-errorsCh:
+	case <-receivedCh:
+		t.Fatal("expected oversized message to be rejected")
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected error")
+	}
+}
+
 func TestMConnectionWillEventuallyTimeout(t *testing.T) {
 	server, client := net.Pipe()
 	t.Cleanup(closeAll(t, client, server))
@@ -202,6 +330,58 @@ func TestMConnectionWillEventuallyTimeout(t *testing.T) {
 	}
 }
 
+func TestMConnectionMissedPongs(t *testing.T) {
+	server, client := net.Pipe()
+	t.Cleanup(closeAll(t, client, server))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errorsCh := make(chan interface{}, 1)
+	onError := func(ctx context.Context, r interface{}) {
+		errorsCh <- r
+	}
+	cfg := DefaultMConnConfig()
+	cfg.PingInterval = 50 * time.Millisecond
+	cfg.PongTimeout = 10 * time.Second
+	cfg.MaxMissedPongs = 2
+	chDescs := []*ChannelDescriptor{{ID: 0x01, Priority: 1, SendQueueCapacity: 1}}
+	mconn := NewMConnection(log.NewNopLogger(), client, chDescs,
+		func(ctx context.Context, chID ChannelID, msgBytes []byte) {}, onError, cfg)
+	require.NoError(t, mconn.Start(ctx))
+	t.Cleanup(waitAll(mconn))
+
+	// The peer keeps sending messages, but never answers our pings.
+	go func() {
+		_, _ = io.Copy(io.Discard, server)
+	}()
+	go func() {
+		protoWriter := protoio.NewDelimitedWriter(server)
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				packet := tmp2p.PacketMsg{ChannelID: 0x01, EOF: true, Data: []byte("x")}
+				if _, err := protoWriter.WriteMsg(mustWrapPacket(&packet)); err != nil {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	select {
+	case r := <-errorsCh:
+		err, ok := r.(error)
+		require.True(t, ok)
+		require.ErrorIs(t, err, ErrPongTimeout)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the connection to fail after missed pongs")
+	}
+}
+
 func TestMConnectionMultiplePongsInTheBeginning(t *testing.T) {
 	server, client := net.Pipe()
 	t.Cleanup(closeAll(t, client, server))
@@ -360,6 +540,9 @@ func TestMConnectionPingPongs(t *testing.T) {
 	_, err = protoWriter.WriteMsg(mustWrapPacket(&tmp2p.PacketPong{}))
 	require.NoError(t, err)
 
+	// the pong answers the ping, so the RTT is measured
+	require.Eventually(t, func() bool { return mconn.RTT() > 0 }, time.Second, 10*time.Millisecond)
+
 	time.Sleep(mconn.config.PingInterval)
 
 	// read ping
@@ -381,6 +564,27 @@ func TestMConnectionPingPongs(t *testing.T) {
 	}
 }
 
+func TestMConnectionRTT(t *testing.T) {
+	mconn := createTestMConnection(log.NewNopLogger(), nil)
+	require.Zero(t, mconn.RTT())
+
+	now := time.Now()
+
+	// unsolicited pongs are ignored
+	mconn.recordPong(now)
+	require.Zero(t, mconn.RTT())
+
+	// the first sample sets the RTT
+	mconn.pingSentAt = now.Add(-80 * time.Millisecond).UnixNano()
+	mconn.recordPong(now)
+	require.Equal(t, 80*time.Millisecond, mconn.RTT())
+
+	// later samples are smoothed
+	mconn.pingSentAt = now.Add(-160 * time.Millisecond).UnixNano()
+	mconn.recordPong(now)
+	require.Equal(t, 90*time.Millisecond, mconn.RTT())
+}
+
 func TestMConnectionStopsAndReturnsError(t *testing.T) {
 	server, client := net.Pipe()
 	t.Cleanup(closeAll(t, client, server))
@@ -422,6 +626,43 @@ func TestMConnectionStopsAndReturnsError(t *testing.T) {
 	}
 }
 
+func TestMConnectionDisconnect(t *testing.T) {
+	server, client := net.Pipe()
+	t.Cleanup(closeAll(t, client, server))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errorsCh := make(chan interface{}, 1)
+	onReceive := func(ctx context.Context, chID ChannelID, msgBytes []byte) {}
+	onError := func(ctx context.Context, r interface{}) {
+		select {
+		case errorsCh <- r:
+		case <-ctx.Done():
+		}
+	}
+
+	mconnClient := createMConnectionWithCallbacks(log.NewNopLogger(), client, onReceive, onError)
+	require.NoError(t, mconnClient.Start(ctx))
+	t.Cleanup(waitAll(mconnClient))
+
+	mconnServer := createTestMConnection(log.NewNopLogger(), server)
+	require.NoError(t, mconnServer.Start(ctx))
+	t.Cleanup(waitAll(mconnServer))
+
+	// The server disconnects, telling the client why.
+	mconnServer.Disconnect(tmp2p.DisconnectPeerLimit)
+	assert.False(t, mconnServer.IsRunning())
+
+	select {
+	case err := <-errorsCh:
+		assert.Equal(t, ErrDisconnected{Reason: tmp2p.DisconnectPeerLimit}, err)
+		assert.False(t, mconnClient.IsRunning())
+	case <-time.After(time.Second):
+		t.Fatal("Did not receive disconnect error in 1s")
+	}
+}
+
 func newClientAndServerConnsForReadErrors(
 	ctx context.Context,
 	t *testing.T,
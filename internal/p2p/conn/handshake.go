@@ -0,0 +1,280 @@
+package conn
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/tendermint/tendermint/crypto"
+)
+
+// HandshakeProtocol identifies a protocol used to authenticate and encrypt
+// peer connections. Higher values are newer, and preferred during
+// negotiation.
+type HandshakeProtocol uint8
+
+const (
+	// HandshakeSecretConnection is the legacy station-to-station handshake
+	// of SecretConnection, which is deprecated in favor of Noise. It is never
+	// negotiated, since legacy peers start their handshake right away without
+	// a hello. Instead, supporting it falls back to it with such peers.
+	HandshakeSecretConnection HandshakeProtocol = 1
+
+	// HandshakeNoise is the Noise XX handshake of NoiseConnection.
+	HandshakeNoise HandshakeProtocol = 2
+)
+
+// String implements fmt.Stringer.
+func (p HandshakeProtocol) String() string {
+	switch p {
+	case HandshakeSecretConnection:
+		return "secret-connection"
+	case HandshakeNoise:
+		return "noise"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(p))
+	}
+}
+
+const (
+	// handshakeNonceSize is the size of the random nonce in the handshake
+	// hello, which decides which side initiates the handshake.
+	handshakeNonceSize = 32
+
+	// handshakeMaxProtocols is the maximum number of protocols in a hello.
+	handshakeMaxProtocols = 16
+
+	labelHandshakeNegotiation = "TENDERMINT_HANDSHAKE_NEGOTIATION"
+)
+
+var (
+	// handshakeMagic starts a handshake hello, to tell it apart from the
+	// legacy handshake, which starts with a length-prefixed ephemeral key.
+	handshakeMagic = []byte("TMHS")
+
+	// ErrHandshakeNotNegotiated is returned when the peer doesn't negotiate
+	// the handshake, i.e. it only supports the legacy handshake.
+	ErrHandshakeNotNegotiated = errors.New("peer does not support handshake negotiation")
+)
+
+// AuthenticatedConn is a connection that has been encrypted and
+// authenticated by a handshake.
+type AuthenticatedConn interface {
+	net.Conn
+
+	// RemotePubKey returns the authenticated remote node key.
+	RemotePubKey() crypto.PubKey
+}
+
+var (
+	_ AuthenticatedConn = (*SecretConnection)(nil)
+	_ AuthenticatedConn = (*NoiseConnection)(nil)
+)
+
+// handshakeHello is sent by both sides to negotiate the handshake protocol.
+// On the wire, it is the magic bytes, the nonce, the number of protocols as
+// a single byte, and a byte per protocol.
+type handshakeHello struct {
+	nonce     [handshakeNonceSize]byte
+	protocols []HandshakeProtocol
+}
+
+func (h handshakeHello) bytes() []byte {
+	bz := make([]byte, 0, len(handshakeMagic)+handshakeNonceSize+1+len(h.protocols))
+	bz = append(bz, handshakeMagic...)
+	bz = append(bz, h.nonce[:]...)
+	bz = append(bz, byte(len(h.protocols)))
+	for _, p := range h.protocols {
+		bz = append(bz, byte(p))
+	}
+	return bz
+}
+
+// readHandshakeMagic reads the magic bytes that start a hello. If the peer
+// sent something else, it returns the bytes read and
+// ErrHandshakeNotNegotiated. A legacy peer's first message may be shorter
+// than a hello, so the magic bytes are checked first.
+func readHandshakeMagic(r io.Reader) ([]byte, error) {
+	magic := make([]byte, len(handshakeMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(magic, handshakeMagic) {
+		return magic, ErrHandshakeNotNegotiated
+	}
+	return magic, nil
+}
+
+// readHandshakeHello reads the rest of a hello, after the magic bytes.
+func readHandshakeHello(r io.Reader) (handshakeHello, error) {
+	header := make([]byte, handshakeNonceSize+1)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return handshakeHello{}, err
+	}
+	var hello handshakeHello
+	copy(hello.nonce[:], header)
+	count := int(header[handshakeNonceSize])
+	if count == 0 || count > handshakeMaxProtocols {
+		return handshakeHello{}, fmt.Errorf("invalid number of handshake protocols %d", count)
+	}
+	protocols := make([]byte, count)
+	if _, err := io.ReadFull(r, protocols); err != nil {
+		return handshakeHello{}, err
+	}
+	for _, p := range protocols {
+		hello.protocols = append(hello.protocols, HandshakeProtocol(p))
+	}
+	return hello, nil
+}
+
+// MakeNegotiatedConnection negotiates a handshake protocol with the peer, and
+// performs the handshake. Both sides send the protocols they support, and the
+// newest protocol supported by both is used. It returns the authenticated
+// connection and the protocol that was used. The caller should call
+// conn.Close() on errors.
+//
+// The outbound side, which dialed the connection, sends its hello first, and
+// the inbound side waits for it before answering. This way, the inbound side
+// can tell legacy peers apart, which start the SecretConnection handshake
+// right away, and falls back to it if HandshakeSecretConnection is among the
+// protocols. A legacy peer doesn't answer the outbound side's hello, so it
+// returns ErrHandshakeNotNegotiated, and the caller may redial the peer with
+// MakeSecretConnection.
+//
+// The side that sent the lowest random nonce initiates the handshake. To
+// protect against downgrade attacks, the hellos of both sides are
+// authenticated as the Noise prologue.
+func MakeNegotiatedConnection(
+	conn io.ReadWriteCloser,
+	locPrivKey crypto.PrivKey,
+	protocols []HandshakeProtocol,
+	outbound bool,
+) (AuthenticatedConn, HandshakeProtocol, error) {
+	locHello := handshakeHello{}
+	legacy := false
+	for _, p := range protocols {
+		if p == HandshakeSecretConnection {
+			legacy = true
+		} else {
+			locHello.protocols = append(locHello.protocols, p)
+		}
+	}
+	if len(locHello.protocols) == 0 || len(locHello.protocols) > handshakeMaxProtocols {
+		return nil, 0, fmt.Errorf("invalid number of handshake protocols %d", len(locHello.protocols))
+	}
+	if _, err := crand.Read(locHello.nonce[:]); err != nil {
+		return nil, 0, err
+	}
+
+	var remHello handshakeHello
+	if outbound {
+		if _, err := conn.Write(locHello.bytes()); err != nil {
+			return nil, 0, err
+		}
+		if _, err := readHandshakeMagic(conn); err != nil {
+			return nil, 0, err
+		}
+		hello, err := readHandshakeHello(conn)
+		if err != nil {
+			return nil, 0, err
+		}
+		remHello = hello
+	} else {
+		prefix, err := readHandshakeMagic(conn)
+		if errors.Is(err, ErrHandshakeNotNegotiated) && legacy {
+			sc, err := MakeSecretConnection(newReplayConn(conn, prefix), locPrivKey)
+			if err != nil {
+				return nil, 0, err
+			}
+			return sc, HandshakeSecretConnection, nil
+		} else if err != nil {
+			return nil, 0, err
+		}
+		hello, err := readHandshakeHello(conn)
+		if err != nil {
+			return nil, 0, err
+		}
+		remHello = hello
+		if _, err := conn.Write(locHello.bytes()); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	protocol, err := selectHandshakeProtocol(locHello.protocols, remHello.protocols)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Both sides must agree on who's the initiator, and the order of the
+	// hellos in the transcript.
+	var initiator bool
+	switch bytes.Compare(locHello.nonce[:], remHello.nonce[:]) {
+	case -1:
+		initiator = true
+	case 1:
+		initiator = false
+	default:
+		return nil, 0, errors.New("peer sent our own handshake nonce")
+	}
+	transcript := []byte(labelHandshakeNegotiation)
+	if initiator {
+		transcript = append(transcript, locHello.bytes()...)
+		transcript = append(transcript, remHello.bytes()...)
+	} else {
+		transcript = append(transcript, remHello.bytes()...)
+		transcript = append(transcript, locHello.bytes()...)
+	}
+
+	switch protocol {
+	case HandshakeNoise:
+		nc, err := MakeNoiseConnection(conn, locPrivKey, initiator, transcript)
+		if err != nil {
+			return nil, 0, err
+		}
+		return nc, protocol, nil
+
+	default:
+		return nil, 0, fmt.Errorf("unsupported handshake protocol %v", protocol)
+	}
+}
+
+// selectHandshakeProtocol returns the newest protocol supported by both sides.
+func selectHandshakeProtocol(local, remote []HandshakeProtocol) (HandshakeProtocol, error) {
+	var selected HandshakeProtocol
+	for _, l := range local {
+		for _, r := range remote {
+			if l == r && l > selected {
+				selected = l
+			}
+		}
+	}
+	if selected == 0 {
+		return 0, fmt.Errorf("no common handshake protocol (local %v, remote %v)", local, remote)
+	}
+	return selected, nil
+}
+
+// replayConn replays bytes already read from a connection, before reading
+// from the connection again.
+type replayConn struct {
+	net.Conn
+	r io.Reader
+}
+
+// newReplayConn returns a connection that reads prefix, and then from conn.
+// If conn is a net.Conn, so is the returned connection.
+func newReplayConn(conn io.ReadWriteCloser, prefix []byte) io.ReadWriteCloser {
+	r := io.MultiReader(bytes.NewReader(prefix), conn)
+	if nc, ok := conn.(net.Conn); ok {
+		return &replayConn{Conn: nc, r: r}
+	}
+	return struct {
+		io.Reader
+		io.WriteCloser
+	}{r, conn}
+}
+
+func (c *replayConn) Read(b []byte) (int, error) { return c.r.Read(b) }
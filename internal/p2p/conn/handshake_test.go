@@ -0,0 +1,184 @@
+package conn
+
+import (
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/internal/libs/async"
+)
+
+// negotiateConnPair negotiates connections between foo and bar, which are
+// connected through a proxy that may tamper with the hello messages. foo
+// dials bar.
+func negotiateConnPair(
+	t *testing.T,
+	fooProtocols, barProtocols []HandshakeProtocol,
+	tamper func(hello []byte),
+) ([2]AuthenticatedConn, [2]HandshakeProtocol, error) {
+	t.Helper()
+
+	fooConn, fooProxy := net.Pipe()
+	barConn, barProxy := net.Pipe()
+	t.Cleanup(func() {
+		for _, c := range []net.Conn{fooConn, fooProxy, barConn, barProxy} {
+			c.Close()
+		}
+	})
+	proxy := func(dst, src net.Conn) {
+		defer dst.Close()
+		// Each hello is sent in a single write, so it's the first read.
+		buf := make([]byte, 1024)
+		n, err := src.Read(buf)
+		if err != nil {
+			return
+		}
+		if tamper != nil {
+			tamper(buf[:n])
+		}
+		if _, err := dst.Write(buf[:n]); err != nil {
+			return
+		}
+		_, _ = io.Copy(dst, src)
+	}
+	go proxy(barProxy, fooProxy)
+	go proxy(fooProxy, barProxy)
+
+	fooPrvKey := ed25519.GenPrivKey()
+	barPrvKey := ed25519.GenPrivKey()
+	type result struct {
+		conn     AuthenticatedConn
+		protocol HandshakeProtocol
+	}
+	trs, _ := async.Parallel(
+		func(_ int) (val interface{}, abort bool, err error) {
+			c, p, err := MakeNegotiatedConnection(fooConn, fooPrvKey, fooProtocols, true)
+			if err != nil {
+				fooConn.Close()
+				return nil, true, err
+			}
+			assert.True(t, c.RemotePubKey().Equals(barPrvKey.PubKey()))
+			return result{c, p}, false, nil
+		},
+		func(_ int) (val interface{}, abort bool, err error) {
+			c, p, err := MakeNegotiatedConnection(barConn, barPrvKey, barProtocols, false)
+			if err != nil {
+				barConn.Close()
+				return nil, true, err
+			}
+			assert.True(t, c.RemotePubKey().Equals(fooPrvKey.PubKey()))
+			return result{c, p}, false, nil
+		},
+	)
+	if err := trs.FirstError(); err != nil {
+		return [2]AuthenticatedConn{}, [2]HandshakeProtocol{}, err
+	}
+	foo, _ := trs.LatestResult(0)
+	bar, _ := trs.LatestResult(1)
+	fooRes, barRes := foo.Value.(result), bar.Value.(result)
+	return [2]AuthenticatedConn{fooRes.conn, barRes.conn},
+		[2]HandshakeProtocol{fooRes.protocol, barRes.protocol}, nil
+}
+
+func TestMakeNegotiatedConnection(t *testing.T) {
+	noise := []HandshakeProtocol{HandshakeNoise}
+	testcases := map[string]struct {
+		foo, bar []HandshakeProtocol
+		expect   HandshakeProtocol
+	}{
+		"both support noise":        {noise, noise, HandshakeNoise},
+		"no common protocol":        {noise, []HandshakeProtocol{9}, 0},
+		"unknown protocols ignored": {[]HandshakeProtocol{9, HandshakeNoise}, noise, HandshakeNoise},
+		"legacy is not negotiated": {
+			[]HandshakeProtocol{HandshakeSecretConnection, HandshakeNoise},
+			[]HandshakeProtocol{HandshakeNoise, HandshakeSecretConnection},
+			HandshakeNoise,
+		},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			conns, protocols, err := negotiateConnPair(t, tc.foo, tc.bar, nil)
+			if tc.expect == 0 {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, [2]HandshakeProtocol{tc.expect, tc.expect}, protocols)
+
+			go func() { _, _ = conns[0].Write([]byte("hello")) }()
+			buf := make([]byte, 5)
+			_, err = io.ReadFull(conns[1], buf)
+			require.NoError(t, err)
+			require.Equal(t, "hello", string(buf))
+		})
+	}
+}
+
+func TestMakeNegotiatedConnection_Tampered(t *testing.T) {
+	noise := []HandshakeProtocol{HandshakeNoise}
+
+	// Any change to the hellos fails the Noise handshake. We change the
+	// last byte of the nonce, so the initiator doesn't change.
+	_, _, err := negotiateConnPair(t, noise, noise, func(hello []byte) {
+		hello[len(handshakeMagic)+handshakeNonceSize-1] ^= 0x01
+	})
+	require.Error(t, err)
+}
+
+func TestMakeNegotiatedConnection_LegacyPeer(t *testing.T) {
+	legacy := []HandshakeProtocol{HandshakeNoise, HandshakeSecretConnection}
+	testcases := map[string]struct {
+		protocols []HandshakeProtocol
+		outbound  bool
+		expectErr error
+	}{
+		"dialing a legacy peer":              {legacy, true, ErrHandshakeNotNegotiated},
+		"accepting a legacy peer":            {legacy, false, nil},
+		"accepting a legacy peer without it": {[]HandshakeProtocol{HandshakeNoise}, false, ErrHandshakeNotNegotiated},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			fooConn, barConn := net.Pipe()
+			t.Cleanup(func() {
+				fooConn.Close()
+				barConn.Close()
+			})
+
+			// A peer without negotiation support starts the legacy
+			// handshake right away, which isn't a valid hello.
+			fooPrvKey, barPrvKey := ed25519.GenPrivKey(), ed25519.GenPrivKey()
+			barCh := make(chan *SecretConnection, 1)
+			go func() {
+				sc, err := MakeSecretConnection(barConn, barPrvKey)
+				if err != nil {
+					barConn.Close()
+				}
+				barCh <- sc
+			}()
+
+			foo, protocol, err := MakeNegotiatedConnection(fooConn, fooPrvKey, tc.protocols, tc.outbound)
+			if tc.expectErr != nil {
+				require.ErrorIs(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, HandshakeSecretConnection, protocol)
+			require.True(t, foo.RemotePubKey().Equals(barPrvKey.PubKey()))
+
+			bar := <-barCh
+			require.NotNil(t, bar)
+			require.True(t, bar.RemotePubKey().Equals(fooPrvKey.PubKey()))
+			go func() { _, _ = bar.Write([]byte("hello")) }()
+			buf := make([]byte, 5)
+			_, err = io.ReadFull(foo, buf)
+			require.NoError(t, err)
+			require.Equal(t, "hello", string(buf))
+		})
+	}
+}
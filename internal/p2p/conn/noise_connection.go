@@ -0,0 +1,309 @@
+package conn
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/flynn/noise"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/encoding"
+	tmp2p "github.com/tendermint/tendermint/proto/tendermint/p2p"
+)
+
+const (
+	// noiseLenSize is the size of the big-endian length prefix of each Noise
+	// message on the wire.
+	noiseLenSize = 2
+
+	// noiseDataMaxSize is the maximum amount of plaintext in a transport
+	// message. It is kept well below the Noise limit of 65535 bytes so that
+	// frames fit in a single WebSocket message.
+	noiseDataMaxSize = 16 * 1024
+
+	// noiseMaxPayloadSize is the maximum size of a handshake payload.
+	noiseMaxPayloadSize = 1024
+)
+
+var (
+	// noiseCipherSuite is Noise_XX_25519_ChaChaPoly_SHA256.
+	noiseCipherSuite = noise.NewCipherSuite(noise.DH25519, noise.CipherChaChaPoly, noise.HashSHA256)
+
+	// noiseStaticKeyPrefix is prepended to the Noise static key before it is
+	// signed with the node key, binding the node key to the handshake.
+	noiseStaticKeyPrefix = []byte("TENDERMINT_NOISE_STATIC_KEY")
+)
+
+// NoiseConnection implements net.Conn, encrypting and authenticating the
+// connection with a Noise_XX_25519_ChaChaPoly_SHA256 handshake. See
+// https://noiseprotocol.org/noise.html for details on the protocol.
+//
+// Each side generates a fresh static Noise key for every connection, and sends
+// its node key along with a signature of the static key in the encrypted
+// handshake payload. Like with SecretConnection, consumers are responsible for
+// authenticating the remote peer's pubkey against known information, like a
+// nodeID.
+type NoiseConnection struct {
+	remPubKey crypto.PubKey
+	conn      io.ReadWriteCloser
+
+	// Reads and writes are independent, so like SecretConnection we use a
+	// separate mutex for each.
+	recvMtx    sync.Mutex
+	recvCipher *noise.CipherState
+	recvBuffer []byte
+
+	sendMtx    sync.Mutex
+	sendCipher *noise.CipherState
+}
+
+// MakeNoiseConnection performs a Noise XX handshake and returns a new
+// authenticated NoiseConnection. Exactly one side must be the initiator, and
+// both sides must use the same prologue. The caller should call conn.Close()
+// on errors.
+func MakeNoiseConnection(
+	conn io.ReadWriteCloser,
+	locPrivKey crypto.PrivKey,
+	initiator bool,
+	prologue []byte,
+) (*NoiseConnection, error) {
+	staticKey, err := noiseCipherSuite.GenerateKeypair(crand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	hs, err := newNoiseHandshakeState(initiator, staticKey, prologue, crand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := makeNoisePayload(locPrivKey, staticKey.Public)
+	if err != nil {
+		return nil, err
+	}
+
+	// XX is three messages: -> e, <- e ee s es, -> s se. Each side sends its
+	// payload along with its static key, where it is encrypted, i.e. in the
+	// second and third message.
+	var (
+		remPayload     []byte
+		sendCS, recvCS *noise.CipherState
+		cs1, cs2       *noise.CipherState
+	)
+	for i := 0; i < 3; i++ {
+		if (i%2 == 0) == initiator {
+			var outPayload []byte
+			if i > 0 {
+				outPayload = payload
+			}
+			var msg []byte
+			msg, cs1, cs2, err = hs.WriteMessage(nil, outPayload)
+			if err != nil {
+				return nil, err
+			}
+			if err := writeNoiseMessage(conn, msg); err != nil {
+				return nil, err
+			}
+		} else {
+			msg, err := readNoiseMessage(conn)
+			if err != nil {
+				return nil, err
+			}
+			var p []byte
+			p, cs1, cs2, err = hs.ReadMessage(nil, msg)
+			if err != nil {
+				return nil, fmt.Errorf("noise handshake failed: %w", err)
+			}
+			if len(p) > 0 {
+				remPayload = p
+			}
+		}
+	}
+	if cs1 == nil || cs2 == nil {
+		return nil, errors.New("noise handshake did not complete")
+	}
+	// cs1 encrypts messages from the initiator, cs2 from the responder.
+	if initiator {
+		sendCS, recvCS = cs1, cs2
+	} else {
+		sendCS, recvCS = cs2, cs1
+	}
+
+	remPubKey, err := verifyNoisePayload(remPayload, hs.PeerStatic())
+	if err != nil {
+		return nil, err
+	}
+
+	nc := newNoiseConnection(conn, sendCS, recvCS)
+	nc.remPubKey = remPubKey
+	return nc, nil
+}
+
+// newNoiseHandshakeState sets up the Noise XX handshake. The random source
+// generates the ephemeral key, and is only overridden by tests.
+func newNoiseHandshakeState(
+	initiator bool,
+	staticKey noise.DHKey,
+	prologue []byte,
+	random io.Reader,
+) (*noise.HandshakeState, error) {
+	return noise.NewHandshakeState(noise.Config{
+		CipherSuite:   noiseCipherSuite,
+		Random:        random,
+		Pattern:       noise.HandshakeXX,
+		Initiator:     initiator,
+		Prologue:      prologue,
+		StaticKeypair: staticKey,
+	})
+}
+
+func newNoiseConnection(conn io.ReadWriteCloser, sendCS, recvCS *noise.CipherState) *NoiseConnection {
+	return &NoiseConnection{
+		conn:       conn,
+		sendCipher: sendCS,
+		recvCipher: recvCS,
+	}
+}
+
+// makeNoisePayload builds our handshake payload: the node key and its
+// signature of the Noise static key.
+func makeNoisePayload(locPrivKey crypto.PrivKey, staticPubKey []byte) ([]byte, error) {
+	signature, err := locPrivKey.Sign(append(append([]byte{}, noiseStaticKeyPrefix...), staticPubKey...))
+	if err != nil {
+		return nil, err
+	}
+	pbpk, err := encoding.PubKeyToProto(locPrivKey.PubKey())
+	if err != nil {
+		return nil, err
+	}
+	msg := tmp2p.AuthSigMessage{PubKey: pbpk, Sig: signature}
+	return msg.Marshal()
+}
+
+// verifyNoisePayload verifies the remote handshake payload against the remote
+// Noise static key, and returns the remote node key.
+func verifyNoisePayload(payload []byte, staticPubKey []byte) (crypto.PubKey, error) {
+	if len(payload) == 0 {
+		return nil, errors.New("noise handshake payload missing")
+	}
+	if len(payload) > noiseMaxPayloadSize {
+		return nil, fmt.Errorf("noise handshake payload too large (%d bytes)", len(payload))
+	}
+	var msg tmp2p.AuthSigMessage
+	if err := msg.Unmarshal(payload); err != nil {
+		return nil, fmt.Errorf("invalid noise handshake payload: %w", err)
+	}
+	remPubKey, err := encoding.PubKeyFromProto(msg.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := remPubKey.(ed25519.PubKey); !ok {
+		return nil, fmt.Errorf("expected ed25519 pubkey, got %T", remPubKey)
+	}
+	signed := append(append([]byte{}, noiseStaticKeyPrefix...), staticPubKey...)
+	if !remPubKey.VerifySignature(signed, msg.Sig) {
+		return nil, errors.New("noise static key verification failed")
+	}
+	return remPubKey, nil
+}
+
+// writeNoiseMessage writes a length-prefixed Noise message.
+func writeNoiseMessage(w io.Writer, msg []byte) error {
+	if len(msg) > noise.MaxMsgLen {
+		return fmt.Errorf("noise message too large (%d bytes)", len(msg))
+	}
+	frame := make([]byte, noiseLenSize+len(msg))
+	binary.BigEndian.PutUint16(frame, uint16(len(msg)))
+	copy(frame[noiseLenSize:], msg)
+	_, err := w.Write(frame)
+	return err
+}
+
+// readNoiseMessage reads a length-prefixed Noise message.
+func readNoiseMessage(r io.Reader) ([]byte, error) {
+	var lenBuf [noiseLenSize]byte
+	if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+		return nil, err
+	}
+	msg := make([]byte, binary.BigEndian.Uint16(lenBuf[:]))
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// RemotePubKey returns the authenticated remote pubkey.
+func (nc *NoiseConnection) RemotePubKey() crypto.PubKey {
+	return nc.remPubKey
+}
+
+// Write encrypts data in messages of at most noiseDataMaxSize plaintext bytes.
+// CONTRACT: data smaller than noiseDataMaxSize is written atomically.
+func (nc *NoiseConnection) Write(data []byte) (n int, err error) {
+	nc.sendMtx.Lock()
+	defer nc.sendMtx.Unlock()
+
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > noiseDataMaxSize {
+			chunk = data[:noiseDataMaxSize]
+		}
+		msg, err := nc.sendCipher.Encrypt(nil, nil, chunk)
+		if err != nil {
+			return n, err
+		}
+		if err := writeNoiseMessage(nc.conn, msg); err != nil {
+			return n, err
+		}
+		data = data[len(chunk):]
+		n += len(chunk)
+	}
+	return n, nil
+}
+
+// Read decrypts a message, buffering any data that doesn't fit in data.
+// CONTRACT: data smaller than noiseDataMaxSize is read atomically.
+func (nc *NoiseConnection) Read(data []byte) (n int, err error) {
+	nc.recvMtx.Lock()
+	defer nc.recvMtx.Unlock()
+
+	if len(nc.recvBuffer) > 0 {
+		n = copy(data, nc.recvBuffer)
+		nc.recvBuffer = nc.recvBuffer[n:]
+		return n, nil
+	}
+
+	msg, err := readNoiseMessage(nc.conn)
+	if err != nil {
+		return 0, err
+	}
+	chunk, err := nc.recvCipher.Decrypt(msg[:0], nil, msg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decrypt NoiseConnection: %w", err)
+	}
+	if len(chunk) > noiseDataMaxSize {
+		return 0, errors.New("chunk is larger than noiseDataMaxSize")
+	}
+	n = copy(data, chunk)
+	if n < len(chunk) {
+		nc.recvBuffer = chunk[n:]
+	}
+	return n, nil
+}
+
+// Implements net.Conn
+func (nc *NoiseConnection) Close() error                  { return nc.conn.Close() }
+func (nc *NoiseConnection) LocalAddr() net.Addr           { return nc.conn.(net.Conn).LocalAddr() }
+func (nc *NoiseConnection) RemoteAddr() net.Addr          { return nc.conn.(net.Conn).RemoteAddr() }
+func (nc *NoiseConnection) SetDeadline(t time.Time) error { return nc.conn.(net.Conn).SetDeadline(t) }
+func (nc *NoiseConnection) SetReadDeadline(t time.Time) error {
+	return nc.conn.(net.Conn).SetReadDeadline(t)
+}
+func (nc *NoiseConnection) SetWriteDeadline(t time.Time) error {
+	return nc.conn.(net.Conn).SetWriteDeadline(t)
+}
@@ -0,0 +1,177 @@
+package conn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/flynn/noise"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/internal/libs/async"
+	tmrand "github.com/tendermint/tendermint/libs/rand"
+)
+
+// makeNoiseConnPair makes a pair of NoiseConnections over a net.Pipe.
+func makeNoiseConnPair(t *testing.T, fooPrologue, barPrologue []byte) (*NoiseConnection, *NoiseConnection, error) {
+	t.Helper()
+
+	fooConn, barConn := net.Pipe()
+	t.Cleanup(func() {
+		fooConn.Close()
+		barConn.Close()
+	})
+	fooPrvKey := ed25519.GenPrivKey()
+	barPrvKey := ed25519.GenPrivKey()
+
+	trs, _ := async.Parallel(
+		func(_ int) (val interface{}, abort bool, err error) {
+			nc, err := MakeNoiseConnection(fooConn, fooPrvKey, true, fooPrologue)
+			if err != nil {
+				fooConn.Close() // unblock the other side
+				return nil, true, err
+			}
+			assert.True(t, nc.RemotePubKey().Equals(barPrvKey.PubKey()))
+			return nc, false, nil
+		},
+		func(_ int) (val interface{}, abort bool, err error) {
+			nc, err := MakeNoiseConnection(barConn, barPrvKey, false, barPrologue)
+			if err != nil {
+				barConn.Close() // unblock the other side
+				return nil, true, err
+			}
+			assert.True(t, nc.RemotePubKey().Equals(fooPrvKey.PubKey()))
+			return nc, false, nil
+		},
+	)
+	if err := trs.FirstError(); err != nil {
+		return nil, nil, err
+	}
+	foo, _ := trs.LatestResult(0)
+	bar, _ := trs.LatestResult(1)
+	return foo.Value.(*NoiseConnection), bar.Value.(*NoiseConnection), nil
+}
+
+func TestNoiseConnection(t *testing.T) {
+	foo, bar, err := makeNoiseConnPair(t, []byte("prologue"), []byte("prologue"))
+	require.NoError(t, err)
+
+	// Write more than a single message in both directions.
+	for _, pair := range [][2]*NoiseConnection{{foo, bar}, {bar, foo}} {
+		data := tmrand.Bytes(3*noiseDataMaxSize + 7)
+		errCh := make(chan error, 1)
+		go func(w *NoiseConnection) {
+			_, err := w.Write(data)
+			errCh <- err
+		}(pair[0])
+
+		read := make([]byte, len(data))
+		_, err := io.ReadFull(pair[1], read)
+		require.NoError(t, err)
+		require.NoError(t, <-errCh)
+		require.Equal(t, data, read)
+	}
+}
+
+func TestNoiseConnection_PrologueMismatch(t *testing.T) {
+	_, _, err := makeNoiseConnPair(t, []byte("foo"), []byte("bar"))
+	require.Error(t, err)
+}
+
+// bufferConn is an io.ReadWriteCloser backed by a buffer.
+type bufferConn struct {
+	bytes.Buffer
+}
+
+func (*bufferConn) Close() error { return nil }
+
+func unhex(t *testing.T, s string) []byte {
+	t.Helper()
+	bz, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return bz
+}
+
+// TestNoiseConnection_Vectors checks the handshake and transport messages
+// against the Noise_XX_25519_ChaChaPoly_SHA256 test vector of the cacophony
+// test suite, which is also used by other Noise implementations.
+func TestNoiseConnection_Vectors(t *testing.T) {
+	var (
+		initStatic    = unhex(t, "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+		respStatic    = unhex(t, "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20")
+		initEphemeral = unhex(t, "202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f")
+		respEphemeral = unhex(t, "4142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f60")
+
+		handshakeMessages = []string{
+			"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254",
+			"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466" +
+				"3414af878d3e46a2f58911a816d6e8346d4ea17a6f2a0bb4ef4ed56c133cff45" +
+				"60a34e36ea82109f26cf2e5a5caf992b608d55c747f615e5a3425a7a19eefb8f",
+			"87f864c11ba449f46a0a4f4e2eacbb7b0457784f4fca1937f572c93603e9c4d9" +
+				"7e5ea11b16f3968710b23a3be3202dc1b5e1ce3c963347491e74f5c0768a9b42",
+		}
+		initPayload    = unhex(t, "79656c6c6f777375626d6172696e65")
+		initCiphertext = unhex(t, "a52ef02ba60e12696d1d6b9ef4245c88fca757b6134ad6e76b56e310a6adf6")
+		respPayload    = unhex(t, "7375626d6172696e6579656c6c6f77")
+		respCiphertext = unhex(t, "2445aa438ebd649281c636cc7269ca82f1d9023d72520943aeabf909cdf521")
+	)
+
+	initKey, err := noiseCipherSuite.GenerateKeypair(bytes.NewReader(initStatic))
+	require.NoError(t, err)
+	respKey, err := noiseCipherSuite.GenerateKeypair(bytes.NewReader(respStatic))
+	require.NoError(t, err)
+	initHS, err := newNoiseHandshakeState(true, initKey, nil, bytes.NewReader(initEphemeral))
+	require.NoError(t, err)
+	respHS, err := newNoiseHandshakeState(false, respKey, nil, bytes.NewReader(respEphemeral))
+	require.NoError(t, err)
+
+	writers := []int{0, 1, 0}
+	hss := [2]*noise.HandshakeState{initHS, respHS}
+	var initConn, respConn *NoiseConnection
+	initBuf, respBuf := &bufferConn{}, &bufferConn{}
+	for i, expected := range handshakeMessages {
+		w, r := hss[writers[i]], hss[1-writers[i]]
+		msg, cs1, cs2, err := w.WriteMessage(nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, expected, hex.EncodeToString(msg))
+		_, rcs1, rcs2, err := r.ReadMessage(nil, msg)
+		require.NoError(t, err)
+		if i == len(handshakeMessages)-1 {
+			initConn = newNoiseConnection(initBuf, cs1, cs2)
+			respConn = newNoiseConnection(respBuf, rcs2, rcs1)
+		}
+	}
+	require.NotNil(t, initConn)
+	require.NotNil(t, respConn)
+
+	// Transport messages are framed with a big-endian length prefix.
+	for _, tc := range []struct {
+		w, r       *NoiseConnection
+		wBuf, rBuf *bufferConn
+		payload    []byte
+		ciphertext []byte
+	}{
+		{initConn, respConn, initBuf, respBuf, initPayload, initCiphertext},
+		{respConn, initConn, respBuf, initBuf, respPayload, respCiphertext},
+	} {
+		_, err := tc.w.Write(tc.payload)
+		require.NoError(t, err)
+
+		frame := make([]byte, noiseLenSize)
+		binary.BigEndian.PutUint16(frame, uint16(len(tc.ciphertext)))
+		frame = append(frame, tc.ciphertext...)
+		require.Equal(t, frame, tc.wBuf.Bytes())
+
+		_, err = tc.rBuf.Write(tc.wBuf.Next(len(frame)))
+		require.NoError(t, err)
+		read := make([]byte, len(tc.payload))
+		_, err = io.ReadFull(tc.r, read)
+		require.NoError(t, err)
+		require.Equal(t, tc.payload, read)
+	}
+}
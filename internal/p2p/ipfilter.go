@@ -0,0 +1,56 @@
+package p2p
+
+import (
+	"fmt"
+	"net"
+)
+
+// IPFilter filters peer IP addresses by CIDR range. Addresses in a denied
+// range are rejected, as are addresses outside the allowed ranges if any are
+// given. A nil IPFilter allows all addresses.
+type IPFilter struct {
+	allowed []*net.IPNet
+	denied  []*net.IPNet
+}
+
+// NewIPFilter creates a new IPFilter from allowed and denied CIDR ranges, e.g.
+// "10.0.0.0/8" or "2001:db8::/32".
+func NewIPFilter(allowed, denied []string) (*IPFilter, error) {
+	filter := &IPFilter{}
+	for _, cidr := range allowed {
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed CIDR range %q: %w", cidr, err)
+		}
+		filter.allowed = append(filter.allowed, ipnet)
+	}
+	for _, cidr := range denied {
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid denied CIDR range %q: %w", cidr, err)
+		}
+		filter.denied = append(filter.denied, ipnet)
+	}
+	return filter, nil
+}
+
+// Check returns an error if the IP address is rejected by the filter.
+func (f *IPFilter) Check(ip net.IP) error {
+	if f == nil {
+		return nil
+	}
+	for _, ipnet := range f.denied {
+		if ipnet.Contains(ip) {
+			return fmt.Errorf("IP %v is in denied range %v", ip, ipnet)
+		}
+	}
+	if len(f.allowed) == 0 {
+		return nil
+	}
+	for _, ipnet := range f.allowed {
+		if ipnet.Contains(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("IP %v is not in an allowed range", ip)
+}
@@ -0,0 +1,35 @@
+package p2p_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+)
+
+func TestIPFilter(t *testing.T) {
+	_, err := p2p.NewIPFilter([]string{"10.0.0.0"}, nil)
+	require.Error(t, err)
+	_, err = p2p.NewIPFilter(nil, []string{"foo"})
+	require.Error(t, err)
+
+	// A nil filter allows everything.
+	var filter *p2p.IPFilter
+	require.NoError(t, filter.Check(net.IPv4(1, 2, 3, 4)))
+
+	filter, err = p2p.NewIPFilter(nil, []string{"10.0.0.0/8", "2001:db8::/32"})
+	require.NoError(t, err)
+	require.Error(t, filter.Check(net.IPv4(10, 1, 2, 3)))
+	require.Error(t, filter.Check(net.ParseIP("2001:db8::1")))
+	require.NoError(t, filter.Check(net.IPv4(192, 168, 1, 1)))
+	require.NoError(t, filter.Check(net.ParseIP("2001:db9::1")))
+
+	// Denied ranges take precedence over allowed ones.
+	filter, err = p2p.NewIPFilter([]string{"10.0.0.0/8"}, []string{"10.1.0.0/16"})
+	require.NoError(t, err)
+	require.NoError(t, filter.Check(net.IPv4(10, 2, 0, 1)))
+	require.Error(t, filter.Check(net.IPv4(10, 1, 0, 1)))
+	require.Error(t, filter.Check(net.IPv4(192, 168, 1, 1)))
+}
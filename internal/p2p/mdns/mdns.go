@@ -0,0 +1,316 @@
+// Package mdns discovers peers on the local network using multicast DNS
+// service discovery (RFC 6762 and RFC 6763), which is useful for local
+// testnets and air-gapped clusters without seeds.
+//
+// Nodes are advertised as instances of the _tendermint._tcp service, named
+// after their node ID. Their network (chain ID) and p2p address are given in
+// TXT records, and only nodes on the same network are added as peers.
+package mdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// the DNS-SD service type that nodes are advertised under
+	serviceType = "_tendermint._tcp.local."
+
+	// how often we advertise ourselves and query for other nodes
+	queryInterval = 30 * time.Second
+
+	// the TTL of the records we advertise, in seconds
+	recordTTL = 120
+
+	// the maximum size of an mDNS message we read
+	maxMessageSize = 9000
+)
+
+// Group is the IPv4 mDNS multicast group.
+var Group = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// PeerAdder adds discovered peer addresses, e.g. the peer manager.
+type PeerAdder interface {
+	Add(p2p.NodeAddress) (bool, error)
+}
+
+// Service advertises the local node on the local network, and adds other
+// nodes it discovers on the same network to a PeerAdder.
+type Service struct {
+	logger   log.Logger
+	nodeID   types.NodeID
+	network  string
+	endpoint p2p.Endpoint
+	peers    PeerAdder
+
+	// conn and group are only set by tests, which don't use multicast.
+	conn  net.PacketConn
+	group net.Addr
+}
+
+// NewService creates a new mDNS service advertising the given p2p endpoint.
+// If the endpoint's IP is unspecified (e.g. 0.0.0.0), other nodes dial the
+// address they received our advertisement from instead.
+func NewService(
+	logger log.Logger,
+	nodeID types.NodeID,
+	network string,
+	endpoint p2p.Endpoint,
+	peers PeerAdder,
+) *Service {
+	return &Service{
+		logger:   logger,
+		nodeID:   nodeID,
+		network:  network,
+		endpoint: endpoint,
+		peers:    peers,
+		group:    Group,
+	}
+}
+
+// Start joins the mDNS multicast group, and advertises the node and queries
+// for other nodes in the background until the context is done. It errors if
+// the multicast group can't be joined.
+func (s *Service) Start(ctx context.Context) error {
+	conn := s.conn
+	if conn == nil {
+		var err error
+		conn, err = net.ListenMulticastUDP("udp4", nil, Group)
+		if err != nil {
+			return fmt.Errorf("failed to join mDNS multicast group: %w", err)
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	go s.advertise(ctx, conn)
+	go s.receive(ctx, conn)
+
+	s.logger.Info("started mDNS peer discovery", "service", serviceType, "network", s.network)
+	return nil
+}
+
+// advertise periodically announces the node and queries for other nodes.
+func (s *Service) advertise(ctx context.Context, conn net.PacketConn) {
+	ticker := time.NewTicker(queryInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, build := range []func() ([]byte, error){s.buildResponse, buildQuery} {
+			bz, err := build()
+			if err != nil {
+				s.logger.Error("failed to build mDNS message", "err", err)
+				return
+			}
+			if _, err := conn.WriteTo(bz, s.group); err != nil && ctx.Err() == nil {
+				s.logger.Debug("failed to send mDNS message", "err", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// receive handles incoming mDNS messages until the connection is closed.
+func (s *Service) receive(ctx context.Context, conn net.PacketConn) {
+	buf := make([]byte, maxMessageSize)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+				s.logger.Error("failed to read mDNS message", "err", err)
+			}
+			return
+		}
+		s.handleMessage(ctx, conn, buf[:n], from)
+	}
+}
+
+// handleMessage answers queries for our service, and adds the nodes
+// advertised in responses.
+func (s *Service) handleMessage(ctx context.Context, conn net.PacketConn, bz []byte, from net.Addr) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(bz)
+	if err != nil {
+		return
+	}
+
+	if !header.Response {
+		questions, err := parser.AllQuestions()
+		if err != nil {
+			return
+		}
+		for _, q := range questions {
+			if !isServiceName(q.Name) || (q.Type != dnsmessage.TypePTR && q.Type != dnsmessage.TypeALL) {
+				continue
+			}
+			resp, err := s.buildResponse()
+			if err != nil {
+				s.logger.Error("failed to build mDNS response", "err", err)
+				return
+			}
+			if _, err := conn.WriteTo(resp, s.group); err != nil && ctx.Err() == nil {
+				s.logger.Debug("failed to send mDNS response", "err", err)
+			}
+			return
+		}
+		return
+	}
+
+	if err := parser.SkipAllQuestions(); err != nil {
+		return
+	}
+	records, err := parser.AllAnswers()
+	if err != nil {
+		return
+	}
+	if err := parser.SkipAllAuthorities(); err == nil {
+		additionals, _ := parser.AllAdditionals()
+		records = append(records, additionals...)
+	}
+	for _, record := range records {
+		if txt, ok := record.Body.(*dnsmessage.TXTResource); ok {
+			s.discovered(txt.TXT, from)
+		}
+	}
+}
+
+// discovered adds a node advertised by a TXT record, if it's on our network.
+func (s *Service) discovered(txt []string, from net.Addr) {
+	fields := make(map[string]string, len(txt))
+	for _, entry := range txt {
+		if kv := strings.SplitN(entry, "=", 2); len(kv) == 2 {
+			fields[kv[0]] = kv[1]
+		}
+	}
+	nodeID := types.NodeID(fields["id"])
+	if nodeID == "" || nodeID == s.nodeID || fields["network"] != s.network {
+		return
+	}
+
+	host, portStr, err := net.SplitHostPort(fields["addr"])
+	if err != nil {
+		return
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsUnspecified() {
+		udpAddr, ok := from.(*net.UDPAddr)
+		if !ok {
+			return
+		}
+		ip = udpAddr.IP
+	}
+
+	address := p2p.NodeAddress{
+		Protocol: p2p.MConnProtocol,
+		NodeID:   nodeID,
+		Hostname: ip.String(),
+		Port:     uint16(port),
+	}
+	if err := address.Validate(); err != nil {
+		s.logger.Debug("ignoring invalid mDNS address", "address", address, "err", err)
+		return
+	}
+	added, err := s.peers.Add(address)
+	if err != nil {
+		s.logger.Debug("failed to add mDNS address", "address", address, "err", err)
+		return
+	}
+	if added {
+		s.logger.Info("discovered peer on local network", "address", address)
+	}
+}
+
+// buildResponse builds an mDNS response advertising the node.
+func (s *Service) buildResponse() ([]byte, error) {
+	service, err := dnsmessage.NewName(serviceType)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := dnsmessage.NewName(string(s.nodeID) + "." + serviceType)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := s.endpoint.IP
+	if ip == nil {
+		ip = net.IPv4zero
+	}
+	txt := []string{
+		"id=" + string(s.nodeID),
+		"network=" + s.network,
+		"addr=" + net.JoinHostPort(ip.String(), strconv.Itoa(int(s.endpoint.Port))),
+	}
+
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{Response: true, Authoritative: true})
+	builder.EnableCompression()
+	if err := builder.StartAnswers(); err != nil {
+		return nil, err
+	}
+	err = builder.PTRResource(dnsmessage.ResourceHeader{
+		Name:  service,
+		Class: dnsmessage.ClassINET,
+		TTL:   recordTTL,
+	}, dnsmessage.PTRResource{PTR: instance})
+	if err != nil {
+		return nil, err
+	}
+	err = builder.TXTResource(dnsmessage.ResourceHeader{
+		Name:  instance,
+		Class: dnsmessage.ClassINET,
+		TTL:   recordTTL,
+	}, dnsmessage.TXTResource{TXT: txt})
+	if err != nil {
+		return nil, err
+	}
+	return builder.Finish()
+}
+
+// buildQuery builds an mDNS query for nodes advertising our service.
+func buildQuery() ([]byte, error) {
+	service, err := dnsmessage.NewName(serviceType)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{})
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	err = builder.Question(dnsmessage.Question{
+		Name:  service,
+		Type:  dnsmessage.TypePTR,
+		Class: dnsmessage.ClassINET,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return builder.Finish()
+}
+
+// isServiceName returns true if the name is our service type. DNS names are
+// case-insensitive.
+func isServiceName(name dnsmessage.Name) bool {
+	return strings.EqualFold(name.String(), serviceType)
+}
@@ -0,0 +1,108 @@
+package mdns
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+type peerSet struct {
+	mtx   sync.Mutex
+	peers map[types.NodeID]p2p.NodeAddress
+}
+
+func (p *peerSet) Add(address p2p.NodeAddress) (bool, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if _, ok := p.peers[address.NodeID]; ok {
+		return false, nil
+	}
+	p.peers[address.NodeID] = address
+	return true, nil
+}
+
+func (p *peerSet) Get(id types.NodeID) (p2p.NodeAddress, bool) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	address, ok := p.peers[id]
+	return address, ok
+}
+
+// testService creates a service on a localhost socket. Instead of a multicast
+// group, the services send their messages to each other's sockets.
+func testService(t *testing.T, id, network string, endpoint p2p.Endpoint) (*Service, *peerSet) {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	peers := &peerSet{peers: map[types.NodeID]p2p.NodeAddress{}}
+	service := NewService(log.NewNopLogger(), types.NodeID(strings.Repeat(id, 40)), network, endpoint, peers)
+	service.conn = conn
+	return service, peers
+}
+
+func TestService(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a, aPeers := testService(t, "a", "test-chain", p2p.Endpoint{IP: net.IPv4zero, Port: 26656})
+	b, bPeers := testService(t, "b", "test-chain", p2p.Endpoint{IP: net.IPv4(10, 0, 0, 2), Port: 26657})
+	c, cPeers := testService(t, "c", "other-chain", p2p.Endpoint{IP: net.IPv4(10, 0, 0, 3), Port: 26656})
+	a.group = b.conn.LocalAddr()
+	b.group = a.conn.LocalAddr()
+	c.group = a.conn.LocalAddr()
+
+	require.NoError(t, a.Start(ctx))
+	require.NoError(t, b.Start(ctx))
+	require.NoError(t, c.Start(ctx))
+
+	// a advertises an unspecified IP, so b uses the address it came from.
+	require.Eventually(t, func() bool {
+		_, ok := bPeers.Get(a.nodeID)
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+	address, _ := bPeers.Get(a.nodeID)
+	require.Equal(t, p2p.NodeAddress{
+		Protocol: p2p.MConnProtocol,
+		NodeID:   a.nodeID,
+		Hostname: "127.0.0.1",
+		Port:     26656,
+	}, address)
+
+	require.Eventually(t, func() bool {
+		_, ok := aPeers.Get(b.nodeID)
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+	address, _ = aPeers.Get(b.nodeID)
+	require.Equal(t, p2p.NodeAddress{
+		Protocol: p2p.MConnProtocol,
+		NodeID:   b.nodeID,
+		Hostname: "10.0.0.2",
+		Port:     26657,
+	}, address)
+
+	// c is on a different network, so a ignores it.
+	_, ok := aPeers.Get(c.nodeID)
+	require.False(t, ok)
+	require.Empty(t, cPeers.peers)
+}
+
+func TestService_IgnoresSelf(t *testing.T) {
+	a, aPeers := testService(t, "a", "test-chain", p2p.Endpoint{IP: net.IPv4(10, 0, 0, 1), Port: 26656})
+
+	resp, err := a.buildResponse()
+	require.NoError(t, err)
+	a.handleMessage(context.Background(), a.conn, resp, a.conn.LocalAddr())
+	require.Empty(t, aPeers.peers)
+}
@@ -80,6 +80,18 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "peers_evicted",
 			Help:      "Number of peers evicted by this node.",
 		}, labels).With(labelsAndValues...),
+		PeersRemoteBanned: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peers_remote_banned",
+			Help:      "Number of peers that disconnected us shortly after connecting, and are assumed to have banned this node.",
+		}, labels).With(labelsAndValues...),
+		PeersUnresponsive: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peers_unresponsive",
+			Help:      "Number of peers disconnected because they stopped answering pings.",
+		}, labels).With(labelsAndValues...),
 		RouterPeerQueueRecv: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
 			Namespace: namespace,
 			Subsystem: MetricsSubsystem,
@@ -126,6 +138,8 @@ func NopMetrics() *Metrics {
 		PeersConnectedIncoming: discard.NewGauge(),
 		PeersConnectedOutgoing: discard.NewGauge(),
 		PeersEvicted:           discard.NewCounter(),
+		PeersRemoteBanned:      discard.NewCounter(),
+		PeersUnresponsive:      discard.NewCounter(),
 		RouterPeerQueueRecv:    discard.NewHistogram(),
 		RouterPeerQueueSend:    discard.NewHistogram(),
 		RouterChannelQueueSend: discard.NewHistogram(),
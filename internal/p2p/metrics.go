@@ -54,6 +54,13 @@ type Metrics struct {
 	// Number of peers evicted by this node.
 	PeersEvicted metrics.Counter
 
+	// Number of peers that disconnected us shortly after connecting, and
+	// are assumed to have banned this node.
+	PeersRemoteBanned metrics.Counter
+
+	// Number of peers disconnected because they stopped answering pings.
+	PeersUnresponsive metrics.Counter
+
 	// RouterPeerQueueRecv defines the time taken to read off of a peer's queue
 	// before sending on the connection.
 	//metrics:The time taken to read off of a peer's queue before sending on the connection.
@@ -135,6 +135,11 @@ func (_m *Transport) Protocols() []p2p.Protocol {
 	return r0
 }
 
+// RemoveChannelDescriptor provides a mock function with given fields: _a0
+func (_m *Transport) RemoveChannelDescriptor(_a0 conn.ChannelID) {
+	_m.Called(_a0)
+}
+
 // String provides a mock function with given fields:
 func (_m *Transport) String() string {
 	ret := _m.Called()
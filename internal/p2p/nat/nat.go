@@ -0,0 +1,116 @@
+// Package nat maps ports on NAT gateways, such as home routers, using UPnP or
+// NAT-PMP, so that nodes behind them can accept inbound peer connections.
+package nat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	// how long port mappings are requested for
+	mapLifetime = 20 * time.Minute
+
+	// how often port mappings are renewed, well before they expire
+	mapRefreshInterval = 15 * time.Minute
+
+	// how long to wait for a response from a gateway
+	requestTimeout = 3 * time.Second
+)
+
+// Gateway is a NAT gateway that can map ports.
+type Gateway interface {
+	// ExternalIP returns the external (public) IP address of the gateway.
+	ExternalIP(ctx context.Context) (net.IP, error)
+
+	// AddPortMapping maps the external port on the gateway to the internal
+	// port on this host for the given protocol ("tcp" or "udp").
+	AddPortMapping(ctx context.Context, protocol string, extPort, intPort uint16, desc string, lifetime time.Duration) error
+
+	// DeletePortMapping removes a port mapping added by AddPortMapping.
+	DeletePortMapping(ctx context.Context, protocol string, extPort, intPort uint16) error
+
+	// String describes the gateway, for logging.
+	String() string
+}
+
+// Discover looks for a UPnP or NAT-PMP gateway on the local network, and
+// returns the first one that responds. It errors if none are found before the
+// context is done.
+func Discover(ctx context.Context) (Gateway, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		gateway Gateway
+		err     error
+	}
+	results := make(chan result, 2)
+	go func() {
+		gateway, err := DiscoverUPnP(ctx)
+		results <- result{gateway, err}
+	}()
+	go func() {
+		gateway, err := DiscoverPMP(ctx)
+		results <- result{gateway, err}
+	}()
+
+	errs := []string{}
+	for i := 0; i < cap(results); i++ {
+		res := <-results
+		if res.err == nil {
+			return res.gateway, nil
+		}
+		errs = append(errs, res.err.Error())
+	}
+	return nil, fmt.Errorf("no NAT gateway found: %s", strings.Join(errs, "; "))
+}
+
+// Map maps the external port on the gateway to the internal port, and keeps
+// renewing the mapping in the background until the context is done, at which
+// point the mapping is removed. It errors if the initial mapping fails.
+func Map(
+	ctx context.Context,
+	logger log.Logger,
+	gateway Gateway,
+	protocol string,
+	extPort, intPort uint16,
+	desc string,
+) error {
+	if err := gateway.AddPortMapping(ctx, protocol, extPort, intPort, desc, mapLifetime); err != nil {
+		return fmt.Errorf("failed to map port %d on %v: %w", extPort, gateway, err)
+	}
+	logger.Info("mapped port on NAT gateway", "gateway", gateway, "protocol", protocol,
+		"external_port", extPort, "internal_port", intPort)
+
+	go func() {
+		ticker := time.NewTicker(mapRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				// The context is done, so we need a fresh one to clean up.
+				dctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+				defer cancel()
+				if err := gateway.DeletePortMapping(dctx, protocol, extPort, intPort); err != nil {
+					logger.Error("failed to remove port mapping", "gateway", gateway, "err", err)
+				}
+				return
+
+			case <-ticker.C:
+				if err := gateway.AddPortMapping(ctx, protocol, extPort, intPort, desc, mapLifetime); err != nil &&
+					!errors.Is(err, context.Canceled) {
+					logger.Error("failed to renew port mapping", "gateway", gateway, "err", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
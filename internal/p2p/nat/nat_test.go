@@ -0,0 +1,245 @@
+package nat
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// fakePMPServer runs a NAT-PMP gateway on localhost, mapping ports as requested
+// unless remapTo is set. It returns the gateway and the received requests.
+func fakePMPServer(t *testing.T, remapTo uint16) (*pmpGateway, <-chan []byte) {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	requests := make(chan []byte, 10)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			req := append([]byte{}, buf[:n]...)
+			requests <- req
+
+			resp := make([]byte, 16)
+			resp[1] = req[1] | pmpOpResponseFlag
+			switch req[1] {
+			case pmpOpExternalIP:
+				copy(resp[8:12], net.IPv4(203, 0, 113, 7).To4())
+				resp = resp[:12]
+			default:
+				copy(resp[8:10], req[4:6])
+				copy(resp[10:12], req[6:8])
+				if remapTo != 0 && binary.BigEndian.Uint16(req[6:8]) != 0 {
+					binary.BigEndian.PutUint16(resp[10:12], remapTo)
+				}
+				copy(resp[12:16], req[8:12])
+			}
+			_, _ = conn.WriteToUDP(resp, addr)
+		}
+	}()
+
+	return &pmpGateway{addr: conn.LocalAddr().(*net.UDPAddr)}, requests
+}
+
+func TestPMPGateway(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	gateway, requests := fakePMPServer(t, 0)
+
+	ip, err := gateway.ExternalIP(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "203.0.113.7", ip.String())
+	require.Equal(t, []byte{pmpVersion, pmpOpExternalIP}, <-requests)
+
+	require.NoError(t, gateway.AddPortMapping(ctx, "tcp", 26656, 26657, "test", time.Minute))
+	req := <-requests
+	require.Equal(t, byte(pmpOpMapTCP), req[1])
+	require.EqualValues(t, 26657, binary.BigEndian.Uint16(req[4:6]))
+	require.EqualValues(t, 26656, binary.BigEndian.Uint16(req[6:8]))
+	require.EqualValues(t, 60, binary.BigEndian.Uint32(req[8:12]))
+
+	require.NoError(t, gateway.DeletePortMapping(ctx, "udp", 26656, 26657))
+	req = <-requests
+	require.Equal(t, byte(pmpOpMapUDP), req[1])
+	require.EqualValues(t, 0, binary.BigEndian.Uint16(req[6:8]))
+	require.EqualValues(t, 0, binary.BigEndian.Uint32(req[8:12]))
+
+	require.Error(t, gateway.AddPortMapping(ctx, "sctp", 26656, 26656, "test", time.Minute))
+}
+
+func TestPMPGateway_Remapped(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	gateway, requests := fakePMPServer(t, 40000)
+
+	err := gateway.AddPortMapping(ctx, "tcp", 26656, 26656, "test", time.Minute)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "mapped port 40000")
+
+	// The unusable mapping is removed again.
+	<-requests
+	req := <-requests
+	require.EqualValues(t, 0, binary.BigEndian.Uint32(req[8:12]))
+}
+
+const testDeviceDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+    <deviceList>
+      <device>
+        <deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+        <deviceList>
+          <device>
+            <deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+            <serviceList>
+              <service>
+                <serviceType>urn:schemas-upnp-org:service:WANIPConnection:1</serviceType>
+                <controlURL>/ctl/IPConn</controlURL>
+              </service>
+            </serviceList>
+          </device>
+        </deviceList>
+      </device>
+    </deviceList>
+  </device>
+</root>`
+
+func TestUPnPGateway(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		mtx     sync.Mutex
+		actions []string
+		bodies  []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/desc.xml":
+			fmt.Fprint(w, testDeviceDescription)
+		case "/ctl/IPConn":
+			body, _ := io.ReadAll(r.Body)
+			action := r.Header.Get("SOAPAction")
+			mtx.Lock()
+			actions = append(actions, action)
+			bodies = append(bodies, string(body))
+			mtx.Unlock()
+			if strings.HasSuffix(action, `#GetExternalIPAddress"`) {
+				fmt.Fprint(w, `<?xml version="1.0"?>`+
+					`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`+
+					`<u:GetExternalIPAddressResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">`+
+					`<NewExternalIPAddress>203.0.113.7</NewExternalIPAddress>`+
+					`</u:GetExternalIPAddressResponse></s:Body></s:Envelope>`)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	gateway, err := newUPnPGateway(ctx, server.URL+"/desc.xml")
+	require.NoError(t, err)
+	require.Equal(t, server.URL+"/ctl/IPConn", gateway.controlURL)
+	require.Equal(t, "127.0.0.1", gateway.localIP.String())
+
+	ip, err := gateway.ExternalIP(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "203.0.113.7", ip.String())
+
+	require.NoError(t, gateway.AddPortMapping(ctx, "tcp", 26656, 26657, "a<b", time.Minute))
+	require.NoError(t, gateway.DeletePortMapping(ctx, "tcp", 26656, 26657))
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.Equal(t, []string{
+		`"urn:schemas-upnp-org:service:WANIPConnection:1#GetExternalIPAddress"`,
+		`"urn:schemas-upnp-org:service:WANIPConnection:1#AddPortMapping"`,
+		`"urn:schemas-upnp-org:service:WANIPConnection:1#DeletePortMapping"`,
+	}, actions)
+	require.Contains(t, bodies[1], "<NewExternalPort>26656</NewExternalPort>")
+	require.Contains(t, bodies[1], "<NewInternalPort>26657</NewInternalPort>")
+	require.Contains(t, bodies[1], "<NewInternalClient>127.0.0.1</NewInternalClient>")
+	require.Contains(t, bodies[1], "<NewProtocol>TCP</NewProtocol>")
+	require.Contains(t, bodies[1], "<NewPortMappingDescription>a&lt;b</NewPortMappingDescription>")
+	require.Contains(t, bodies[1], "<NewLeaseDuration>60</NewLeaseDuration>")
+}
+
+func TestUPnPGateway_NoService(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<root><device><deviceType>foo</deviceType></device></root>`)
+	}))
+	defer server.Close()
+
+	_, err := newUPnPGateway(ctx, server.URL)
+	require.Error(t, err)
+}
+
+// mockGateway records port mapping calls.
+type mockGateway struct {
+	mtx     sync.Mutex
+	added   int
+	deleted int
+}
+
+func (g *mockGateway) ExternalIP(context.Context) (net.IP, error) {
+	return net.IPv4(203, 0, 113, 7), nil
+}
+
+func (g *mockGateway) AddPortMapping(context.Context, string, uint16, uint16, string, time.Duration) error {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	g.added++
+	return nil
+}
+
+func (g *mockGateway) DeletePortMapping(context.Context, string, uint16, uint16) error {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	g.deleted++
+	return nil
+}
+
+func (g *mockGateway) String() string { return "mock" }
+
+func TestMap(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gateway := &mockGateway{}
+	require.NoError(t, Map(ctx, log.NewNopLogger(), gateway, "tcp", 26656, 26656, "test"))
+	gateway.mtx.Lock()
+	require.Equal(t, 1, gateway.added)
+	gateway.mtx.Unlock()
+
+	// Cancelling the context removes the mapping.
+	cancel()
+	require.Eventually(t, func() bool {
+		gateway.mtx.Lock()
+		defer gateway.mtx.Unlock()
+		return gateway.deleted == 1
+	}, time.Second, 10*time.Millisecond)
+}
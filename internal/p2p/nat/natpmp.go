@@ -0,0 +1,202 @@
+package nat
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// the port NAT-PMP gateways listen on, see RFC 6886
+	pmpPort = 5351
+
+	pmpVersion         = 0
+	pmpOpExternalIP    = 0
+	pmpOpMapUDP        = 1
+	pmpOpMapTCP        = 2
+	pmpOpResponseFlag  = 128
+	pmpRetries         = 4
+	pmpInitialInterval = 250 * time.Millisecond
+)
+
+// pmpGateway is a NAT-PMP gateway.
+type pmpGateway struct {
+	addr *net.UDPAddr
+}
+
+var _ Gateway = (*pmpGateway)(nil)
+
+// DiscoverPMP looks for a NAT-PMP gateway. Since NAT-PMP gateways are the
+// default router, which we can't portably look up, we probe the first address
+// of each private network this host is on.
+func DiscoverPMP(ctx context.Context) (Gateway, error) {
+	candidates := potentialGateways()
+	if len(candidates) == 0 {
+		return nil, errors.New("no NAT-PMP gateway candidates")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	found := make(chan *pmpGateway, len(candidates))
+	for _, ip := range candidates {
+		gateway := &pmpGateway{addr: &net.UDPAddr{IP: ip, Port: pmpPort}}
+		go func() {
+			if _, err := gateway.ExternalIP(ctx); err != nil {
+				gateway = nil
+			}
+			found <- gateway
+		}()
+	}
+	for range candidates {
+		if gateway := <-found; gateway != nil {
+			return gateway, nil
+		}
+	}
+	return nil, errors.New("no NAT-PMP gateway responded")
+}
+
+// potentialGateways returns the first address of each private IPv4 network
+// that this host has an address on.
+func potentialGateways() []net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+	ips := []net.IP{}
+	for _, addr := range addrs {
+		ipnet, ok := addr.(*net.IPNet)
+		if !ok || !ipnet.IP.IsPrivate() {
+			continue
+		}
+		ip := ipnet.IP.Mask(ipnet.Mask).To4()
+		if ip == nil {
+			continue
+		}
+		ip[3] |= 1
+		ips = append(ips, ip)
+	}
+	return ips
+}
+
+// ExternalIP implements Gateway.
+func (g *pmpGateway) ExternalIP(ctx context.Context) (net.IP, error) {
+	resp, err := g.call(ctx, []byte{pmpVersion, pmpOpExternalIP}, 12)
+	if err != nil {
+		return nil, err
+	}
+	return net.IPv4(resp[8], resp[9], resp[10], resp[11]), nil
+}
+
+// AddPortMapping implements Gateway.
+func (g *pmpGateway) AddPortMapping(
+	ctx context.Context,
+	protocol string,
+	extPort, intPort uint16,
+	desc string,
+	lifetime time.Duration,
+) error {
+	resp, err := g.mapPort(ctx, protocol, extPort, intPort, lifetime)
+	if err != nil {
+		return err
+	}
+	// Gateways may map a different external port than the one requested, which
+	// we can't advertise, so we treat it as a failure.
+	if mapped := binary.BigEndian.Uint16(resp[10:12]); mapped != extPort {
+		_ = g.DeletePortMapping(ctx, protocol, mapped, intPort)
+		return fmt.Errorf("gateway mapped port %d instead of %d", mapped, extPort)
+	}
+	return nil
+}
+
+// DeletePortMapping implements Gateway.
+func (g *pmpGateway) DeletePortMapping(ctx context.Context, protocol string, extPort, intPort uint16) error {
+	_, err := g.mapPort(ctx, protocol, 0, intPort, 0)
+	return err
+}
+
+// String implements Gateway.
+func (g *pmpGateway) String() string {
+	return fmt.Sprintf("NAT-PMP(%v)", g.addr.IP)
+}
+
+func (g *pmpGateway) mapPort(
+	ctx context.Context,
+	protocol string,
+	extPort, intPort uint16,
+	lifetime time.Duration,
+) ([]byte, error) {
+	var op byte
+	switch strings.ToLower(protocol) {
+	case "udp":
+		op = pmpOpMapUDP
+	case "tcp":
+		op = pmpOpMapTCP
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q", protocol)
+	}
+
+	req := make([]byte, 12)
+	req[0] = pmpVersion
+	req[1] = op
+	binary.BigEndian.PutUint16(req[4:6], intPort)
+	binary.BigEndian.PutUint16(req[6:8], extPort)
+	binary.BigEndian.PutUint32(req[8:12], uint32(lifetime/time.Second))
+	return g.call(ctx, req, 16)
+}
+
+// call sends a request to the gateway and waits for the response, retrying
+// with exponential backoff as the RFC specifies. It errors if the response is
+// malformed or has a non-zero result code.
+func (g *pmpGateway) call(ctx context.Context, req []byte, respSize int) ([]byte, error) {
+	conn, err := net.DialUDP("udp", nil, g.addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	resp := make([]byte, 16)
+	interval := pmpInitialInterval
+	for i := 0; i < pmpRetries; i++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		deadline := time.Now().Add(interval)
+		if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+			deadline = d
+		}
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+		if _, err := conn.Write(req); err != nil {
+			return nil, err
+		}
+
+		n, err := conn.Read(resp)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			interval *= 2
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case n < respSize:
+			return nil, fmt.Errorf("short NAT-PMP response (%d < %d bytes)", n, respSize)
+		case resp[0] != pmpVersion:
+			return nil, fmt.Errorf("unsupported NAT-PMP version %d", resp[0])
+		case resp[1] != req[1]|pmpOpResponseFlag:
+			return nil, fmt.Errorf("unexpected NAT-PMP opcode %d", resp[1])
+		}
+		if result := binary.BigEndian.Uint16(resp[2:4]); result != 0 {
+			return nil, fmt.Errorf("NAT-PMP request failed with result code %d", result)
+		}
+		return resp[:n], nil
+	}
+	return nil, fmt.Errorf("no response from NAT-PMP gateway %v", g.addr)
+}
@@ -0,0 +1,295 @@
+package nat
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// the multicast address SSDP discovery requests are sent to
+	ssdpAddr = "239.255.255.250:1900"
+
+	// the device type of UPnP internet gateways
+	igdDeviceType = "urn:schemas-upnp-org:device:InternetGatewayDevice:1"
+
+	// the maximum size of a device description we'll read
+	maxDescriptionSize = 1 << 20
+)
+
+// upnpServiceTypes are the UPnP service types that can map ports, in order of
+// preference.
+var upnpServiceTypes = []string{
+	"urn:schemas-upnp-org:service:WANIPConnection:1",
+	"urn:schemas-upnp-org:service:WANPPPConnection:1",
+}
+
+// upnpGateway is a UPnP internet gateway device.
+type upnpGateway struct {
+	controlURL  string
+	serviceType string
+	localIP     net.IP // our address on the gateway's network
+	client      *http.Client
+}
+
+var _ Gateway = (*upnpGateway)(nil)
+
+// DiscoverUPnP looks for a UPnP internet gateway via SSDP.
+func DiscoverUPnP(ctx context.Context) (Gateway, error) {
+	location, err := ssdpSearch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newUPnPGateway(ctx, location)
+}
+
+// ssdpSearch multicasts an SSDP search for internet gateways, and returns the
+// device description URL of the first one to respond.
+func ssdpSearch(ctx context.Context) (string, error) {
+	raddr, err := net.ResolveUDPAddr("udp4", ssdpAddr)
+	if err != nil {
+		return "", err
+	}
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(requestTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return "", err
+	}
+
+	req := strings.Join([]string{
+		"M-SEARCH * HTTP/1.1",
+		"HOST: " + ssdpAddr,
+		"ST: " + igdDeviceType,
+		`MAN: "ssdp:discover"`,
+		"MX: 2",
+		"", "",
+	}, "\r\n")
+	if _, err := conn.WriteTo([]byte(req), raddr); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 1536)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return "", fmt.Errorf("no UPnP gateway responded: %w", err)
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf[:n])), nil)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		if !strings.Contains(resp.Header.Get("St"), "InternetGatewayDevice") {
+			continue
+		}
+		if location := resp.Header.Get("Location"); location != "" {
+			return location, nil
+		}
+	}
+}
+
+// upnpDevice is a UPnP device description, as returned from the location URL.
+type upnpDevice struct {
+	DeviceType string        `xml:"deviceType"`
+	Services   []upnpService `xml:"serviceList>service"`
+	Devices    []upnpDevice  `xml:"deviceList>device"`
+}
+
+type upnpService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+type upnpRoot struct {
+	URLBase string     `xml:"URLBase"`
+	Device  upnpDevice `xml:"device"`
+}
+
+// findService searches the device tree for a service of the given type.
+func (d upnpDevice) findService(serviceType string) (upnpService, bool) {
+	for _, service := range d.Services {
+		if service.ServiceType == serviceType {
+			return service, true
+		}
+	}
+	for _, device := range d.Devices {
+		if service, ok := device.findService(serviceType); ok {
+			return service, true
+		}
+	}
+	return upnpService{}, false
+}
+
+// newUPnPGateway fetches the device description from the location URL, and
+// returns a gateway for its port mapping service.
+func newUPnPGateway(ctx context.Context, location string) (*upnpGateway, error) {
+	client := &http.Client{Timeout: requestTimeout}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch UPnP device description: %v", resp.Status)
+	}
+
+	var root upnpRoot
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, maxDescriptionSize)).Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid UPnP device description: %w", err)
+	}
+
+	base, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	if root.URLBase != "" {
+		if base, err = url.Parse(root.URLBase); err != nil {
+			return nil, fmt.Errorf("invalid UPnP URLBase %q: %w", root.URLBase, err)
+		}
+	}
+
+	for _, serviceType := range upnpServiceTypes {
+		service, ok := root.Device.findService(serviceType)
+		if !ok {
+			continue
+		}
+		controlURL, err := base.Parse(service.ControlURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid UPnP control URL %q: %w", service.ControlURL, err)
+		}
+		localIP, err := localIPFor(controlURL.Host)
+		if err != nil {
+			return nil, err
+		}
+		return &upnpGateway{
+			controlURL:  controlURL.String(),
+			serviceType: serviceType,
+			localIP:     localIP,
+			client:      client,
+		}, nil
+	}
+	return nil, errors.New("UPnP device has no port mapping service")
+}
+
+// localIPFor returns the local IP address used to reach the given host.
+func localIPFor(hostport string) (net.IP, error) {
+	conn, err := net.Dial("udp4", hostport)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// ExternalIP implements Gateway.
+func (g *upnpGateway) ExternalIP(ctx context.Context) (net.IP, error) {
+	var resp struct {
+		IP string `xml:"Body>GetExternalIPAddressResponse>NewExternalIPAddress"`
+	}
+	if err := g.soap(ctx, "GetExternalIPAddress", nil, &resp); err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(strings.TrimSpace(resp.IP))
+	if ip == nil {
+		return nil, fmt.Errorf("invalid external IP address %q", resp.IP)
+	}
+	return ip, nil
+}
+
+// AddPortMapping implements Gateway.
+func (g *upnpGateway) AddPortMapping(
+	ctx context.Context,
+	protocol string,
+	extPort, intPort uint16,
+	desc string,
+	lifetime time.Duration,
+) error {
+	return g.soap(ctx, "AddPortMapping", [][2]string{
+		{"NewRemoteHost", ""},
+		{"NewExternalPort", strconv.Itoa(int(extPort))},
+		{"NewProtocol", strings.ToUpper(protocol)},
+		{"NewInternalPort", strconv.Itoa(int(intPort))},
+		{"NewInternalClient", g.localIP.String()},
+		{"NewEnabled", "1"},
+		{"NewPortMappingDescription", desc},
+		{"NewLeaseDuration", strconv.Itoa(int(lifetime / time.Second))},
+	}, nil)
+}
+
+// DeletePortMapping implements Gateway.
+func (g *upnpGateway) DeletePortMapping(ctx context.Context, protocol string, extPort, intPort uint16) error {
+	return g.soap(ctx, "DeletePortMapping", [][2]string{
+		{"NewRemoteHost", ""},
+		{"NewExternalPort", strconv.Itoa(int(extPort))},
+		{"NewProtocol", strings.ToUpper(protocol)},
+	}, nil)
+}
+
+// String implements Gateway.
+func (g *upnpGateway) String() string {
+	return fmt.Sprintf("UPnP(%v)", g.controlURL)
+}
+
+// soap calls an action on the gateway's port mapping service, with the given
+// ordered arguments, decoding the response envelope into resp if given.
+func (g *upnpGateway) soap(ctx context.Context, action string, args [][2]string, resp interface{}) error {
+	var body bytes.Buffer
+	body.WriteString(`<?xml version="1.0"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" ` +
+		`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(&body, `<u:%s xmlns:u="%s">`, action, g.serviceType)
+	for _, arg := range args {
+		fmt.Fprintf(&body, "<%s>", arg[0])
+		if err := xml.EscapeText(&body, []byte(arg[1])); err != nil {
+			return err
+		}
+		fmt.Fprintf(&body, "</%s>", arg[0])
+	}
+	fmt.Fprintf(&body, `</u:%s></s:Body></s:Envelope>`, action)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.controlURL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, g.serviceType, action))
+
+	res, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("UPnP %s failed: %v", action, res.Status)
+	}
+	if resp == nil {
+		return nil
+	}
+	if err := xml.NewDecoder(io.LimitReader(res.Body, maxDescriptionSize)).Decode(resp); err != nil {
+		return fmt.Errorf("invalid UPnP %s response: %w", action, err)
+	}
+	return nil
+}
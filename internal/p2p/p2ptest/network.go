@@ -169,6 +169,12 @@ func (n *Network) MakeChannelsNoCleanup(
 	return channels
 }
 
+// MemoryNetwork returns the underlying memory network, which can be used to
+// simulate latency, packet loss and partitions between nodes.
+func (n *Network) MemoryNetwork() *p2p.MemoryNetwork {
+	return n.memoryNetwork
+}
+
 // RandomNode returns a random node.
 func (n *Network) RandomNode() *Node {
 	nodes := make([]*Node, 0, len(n.Nodes))
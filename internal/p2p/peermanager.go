@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"net"
 	"sort"
 	"sync"
 	"time"
@@ -22,6 +23,19 @@ import (
 const (
 	// retryNever is returned by retryDelay() when retries are disabled.
 	retryNever time.Duration = math.MaxInt64
+
+	// uptimeScoreInterval is the connection time for which a peer's score is
+	// increased by 1 when it disconnects.
+	uptimeScoreInterval = time.Hour
+
+	// rttWindow is the number of most recent connections a peer's RTT is
+	// averaged over.
+	rttWindow = 10
+)
+
+var (
+	errMaxConnected = errors.New("already connected to maximum number of peers")
+	errMaxIncoming  = errors.New("already connected to maximum number of incoming peers")
 )
 
 // PeerStatus is a peer status.
@@ -38,6 +52,29 @@ const (
 	PeerStatusBad  PeerStatus = "bad"  // peer observed as bad
 )
 
+// DialSkipReason is the reason why TryDialNext skipped a peer address.
+type DialSkipReason string
+
+const (
+	DialSkipConnected     DialSkipReason = "connected"      // peer already connected
+	DialSkipDialing       DialSkipReason = "dialing"        // peer already being dialed
+	DialSkipCooldown      DialSkipReason = "cooldown"       // peer disconnected recently
+	DialSkipBanned        DialSkipReason = "banned"         // peer appears to have banned us
+	DialSkipBackoff       DialSkipReason = "backoff"        // address failed to dial recently
+	DialSkipOutgoingFull  DialSkipReason = "outgoing-full"  // outgoing connection limit reached
+	DialSkipConnectedFull DialSkipReason = "connected-full" // connection limit reached
+	DialSkipBanList       DialSkipReason = "ban-list"       // peer is on our ban list
+	DialSkipSubnetFull    DialSkipReason = "subnet-full"    // too many outgoing peers in the address' subnet
+	DialSkipSelf          DialSkipReason = "self"           // address of the local node
+	DialSkipFiltered      DialSkipReason = "filtered"       // peer or address rejected by the allowlist or IP filter
+)
+
+// DialSkip records a peer address that TryDialNext skipped, and why.
+type DialSkip struct {
+	Address NodeAddress
+	Reason  DialSkipReason
+}
+
 type peerConnectionDirection int
 
 const (
@@ -58,6 +95,14 @@ type PeerUpdate struct {
 	NodeID   types.NodeID
 	Status   PeerStatus
 	Channels ChannelIDSet
+
+	// ProtocolVersion is the protocol versions negotiated with the peer, set
+	// for PeerStatusUp.
+	ProtocolVersion types.ProtocolVersion
+
+	// Unresponsive is set for PeerStatusDown if the peer was disconnected
+	// because it stopped answering pings, i.e. it appears to be dead.
+	Unresponsive bool
 }
 
 // PeerUpdates is a peer update subscription with notifications about peer
@@ -113,8 +158,36 @@ type PeerManagerOptions struct {
 	// 0, then all connections can be outgoing. Once this limit is
 	// reached, the node will not dial peers, allowing the
 	// remaining peer connections to be used by incoming connections.
+	// Persistent peers don't count towards this limit, and are dialed
+	// regardless.
 	MaxOutgoingConnections uint16
 
+	// MaxIncomingConnections specifies how many incoming connections a node
+	// will accept. It must be lower than MaxConnected. If it is 0, then all
+	// connections can be incoming. Persistent and unconditional peers don't
+	// count towards this limit.
+	MaxIncomingConnections uint16
+
+	// EvictIncoming makes the peer manager accept incoming connections once
+	// MaxIncomingConnections is reached, as long as a connected incoming peer
+	// has a lower score than the new peer. The lowest-scored such peer is
+	// evicted to make room. Persistent and unconditional peers are never
+	// evicted.
+	EvictIncoming bool
+
+	// MaxPeersPerSubnet is the maximum number of outgoing connections to
+	// peer addresses in the same subnet, to make it harder for an attacker
+	// controlling a single network to eclipse us. Subnets are /24 for IPv4
+	// and /48 for IPv6 unless SubnetPrefixIPv4 or SubnetPrefixIPv6 are given.
+	// Only addresses with IP hostnames are counted, and persistent and
+	// unconditional peers are exempt. 0 means no limit.
+	MaxPeersPerSubnet uint16
+
+	// SubnetPrefixIPv4 and SubnetPrefixIPv6 are the prefix lengths of the
+	// subnets used for MaxPeersPerSubnet.
+	SubnetPrefixIPv4 uint8
+	SubnetPrefixIPv6 uint8
+
 	// MaxConnectedUpgrade is the maximum number of additional connections to
 	// use for probing any better-scored peers to upgrade to when all connection
 	// slots are full. 0 disables peer upgrading.
@@ -129,7 +202,8 @@ type PeerManagerOptions struct {
 	MaxConnectedUpgrade uint16
 
 	// MinRetryTime is the minimum time to wait between retries. Retry times
-	// double for each retry, up to MaxRetryTime. 0 disables retries.
+	// increase by MinRetryTime for each retry, or double for persistent peers,
+	// up to MaxRetryTime. 0 disables retries.
 	MinRetryTime time.Duration
 
 	// MaxRetryTime is the maximum time to wait between retries. 0 means
@@ -148,6 +222,22 @@ type PeerManagerOptions struct {
 	// disconnect from a peer before we'll consider dialing a new peer
 	DisconnectCooldownPeriod time.Duration
 
+	// RemoteBanWindow is the connection age below which a disconnect
+	// initiated by the remote peer is assumed to mean that the peer has
+	// banned or rate-limited us. 0 disables remote ban detection.
+	RemoteBanWindow time.Duration
+
+	// RemoteBanBackoff is the amount of time to wait before redialing a
+	// peer that appears to have banned us, to avoid immediately getting
+	// banned again. 0 disables the extended backoff.
+	RemoteBanBackoff time.Duration
+
+	// NodeKeyRotationTimeout is how long to wait for a peer that announced a
+	// node key rotation to connect with its next node ID. After this, the
+	// rotation is abandoned and the next node ID is forgotten, unless it was
+	// known before. 0 means rotations never expire.
+	NodeKeyRotationTimeout time.Duration
+
 	// PeerScores sets fixed scores for specific peers. It is mainly used
 	// for testing. A score of 0 is ignored.
 	PeerScores map[types.NodeID]PeerScore
@@ -156,6 +246,20 @@ type PeerManagerOptions struct {
 	// consider private and never gossip.
 	PrivatePeers map[types.NodeID]struct{}
 
+	// UnconditionalPeers are peers that we connect to, and accept connections
+	// from, even when MaxConnected is reached. They don't count towards
+	// MaxConnected, and are never evicted to make room for other peers.
+	UnconditionalPeers map[types.NodeID]struct{}
+
+	// AllowedPeers, if not empty, restricts the node to a permissioned set
+	// of peers: only these peers are stored, dialed, accepted and advertised.
+	// PersistentPeers and UnconditionalPeers must be allowed.
+	AllowedPeers map[types.NodeID]struct{}
+
+	// IPFilter rejects peer addresses with IPs in denied CIDR ranges, or
+	// outside allowed ranges. Such addresses are not added to the peer store.
+	IPFilter *IPFilter
+
 	// SelfAddress is the address that will be advertised to peers for them to dial back to us.
 	// If Hostname and Port are unset, Advertise() will include no self-announcement
 	SelfAddress NodeAddress
@@ -182,6 +286,30 @@ func (o *PeerManagerOptions) Validate() error {
 		}
 	}
 
+	for id := range o.UnconditionalPeers {
+		if err := id.Validate(); err != nil {
+			return fmt.Errorf("invalid unconditional peer ID %q: %w", id, err)
+		}
+	}
+
+	for id := range o.AllowedPeers {
+		if err := id.Validate(); err != nil {
+			return fmt.Errorf("invalid allowed peer ID %q: %w", id, err)
+		}
+	}
+	if len(o.AllowedPeers) > 0 {
+		for _, id := range o.PersistentPeers {
+			if _, ok := o.AllowedPeers[id]; !ok {
+				return fmt.Errorf("persistent peer %v is not an allowed peer", id)
+			}
+		}
+		for id := range o.UnconditionalPeers {
+			if _, ok := o.AllowedPeers[id]; !ok {
+				return fmt.Errorf("unconditional peer %v is not an allowed peer", id)
+			}
+		}
+	}
+
 	if o.MaxConnected > 0 && len(o.PersistentPeers) > int(o.MaxConnected) {
 		return fmt.Errorf("number of persistent peers %v can't exceed MaxConnected %v",
 			len(o.PersistentPeers), o.MaxConnected)
@@ -218,6 +346,21 @@ func (o *PeerManagerOptions) Validate() error {
 		return errors.New("cannot set MaxOutgoingConnections to a value larger than MaxConnected")
 	}
 
+	if o.SubnetPrefixIPv4 > 32 {
+		return fmt.Errorf("invalid SubnetPrefixIPv4 %v", o.SubnetPrefixIPv4)
+	}
+	if o.SubnetPrefixIPv6 > 128 {
+		return fmt.Errorf("invalid SubnetPrefixIPv6 %v", o.SubnetPrefixIPv6)
+	}
+
+	if o.MaxIncomingConnections > 0 && o.MaxConnected < o.MaxIncomingConnections {
+		return errors.New("cannot set MaxIncomingConnections to a value larger than MaxConnected")
+	}
+
+	if o.RemoteBanBackoff > 0 && o.RemoteBanWindow == 0 {
+		return errors.New("can't set RemoteBanBackoff without RemoteBanWindow")
+	}
+
 	return nil
 }
 
@@ -300,6 +443,17 @@ type PeerManager struct {
 	ready         map[types.NodeID]bool                    // ready peers (Ready → Disconnected)
 	evict         map[types.NodeID]bool                    // peers scheduled for eviction (Connected → EvictNext)
 	evicting      map[types.NodeID]bool                    // peers being evicted (EvictNext → Disconnected)
+	dialSkips     []DialSkip                               // addresses skipped by the last TryDialNext
+	dialSubnets   map[types.NodeID]string                  // subnets of dialed addresses (DialNext → Disconnected/DialFail)
+	rotations     map[types.NodeID]nodeKeyRotation         // announced node key rotations, by next ID (RotateNodeKey → Dialed/Accepted/expiry)
+	retired       map[types.NodeID]bool                    // previous node IDs of peers that completed a node key rotation
+	versions      map[types.NodeID]types.ProtocolVersion   // negotiated protocol versions (SetProtocolVersion → Disconnected)
+	nodeInfos     map[types.NodeID]types.NodeInfo          // node info sent in the handshake (SetNodeInfo → Disconnected)
+	unresponsive  map[types.NodeID]bool                    // peers that stopped answering pings (Unresponsive → Disconnected)
+
+	// evictReasons are the reasons peers are evicted, given to them when
+	// disconnecting (Errored/Ban/upgrade/EvictNext → Disconnected).
+	evictReasons map[types.NodeID]p2pproto.DisconnectReason
 }
 
 // NewPeerManager creates a new peer manager.
@@ -330,9 +484,16 @@ func NewPeerManager(selfID types.NodeID, peerDB dbm.DB, options PeerManagerOptio
 		dialing:       map[types.NodeID]bool{},
 		upgrading:     map[types.NodeID]types.NodeID{},
 		connected:     map[types.NodeID]peerConnectionDirection{},
+		dialSubnets:   map[types.NodeID]string{},
+		rotations:     map[types.NodeID]nodeKeyRotation{},
+		retired:       map[types.NodeID]bool{},
+		versions:      map[types.NodeID]types.ProtocolVersion{},
+		nodeInfos:     map[types.NodeID]types.NodeInfo{},
+		unresponsive:  map[types.NodeID]bool{},
 		ready:         map[types.NodeID]bool{},
 		evict:         map[types.NodeID]bool{},
 		evicting:      map[types.NodeID]bool{},
+		evictReasons:  map[types.NodeID]p2pproto.DisconnectReason{},
 		subscriptions: map[*PeerUpdates]*PeerUpdates{},
 	}
 
@@ -357,6 +518,17 @@ func (m *PeerManager) configurePeers() error {
 		return err
 	}
 
+	// Forget peers stored before they were excluded by AllowedPeers.
+	if len(m.options.AllowedPeers) > 0 {
+		for _, peer := range m.store.Ranked() {
+			if !m.IsAllowed(peer.ID) {
+				if err := m.store.Delete(peer.ID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
 	configure := map[types.NodeID]bool{}
 	for _, id := range m.options.PersistentPeers {
 		configure[id] = true
@@ -379,7 +551,7 @@ func (m *PeerManager) configurePeers() error {
 
 // configurePeer configures a peer with ephemeral runtime configuration.
 func (m *PeerManager) configurePeer(peer peerInfo) peerInfo {
-	peer.Persistent = m.options.isPersistent(peer.ID)
+	peer.Persistent = m.options.isPersistent(peer.ID) && !m.retired[peer.ID]
 	peer.FixedScore = m.options.PeerScores[peer.ID]
 	return peer
 }
@@ -409,6 +581,7 @@ func (m *PeerManager) prunePeers() error {
 			return nil
 		case m.dialing[peerID]:
 		case m.isConnected(peerID):
+		case ranked[i].Persistent:
 		default:
 			if err := m.store.Delete(peerID); err != nil {
 				return err
@@ -424,14 +597,57 @@ func (m *PeerManager) isConnected(peerID types.NodeID) bool {
 	return ok
 }
 
+// IsAllowed returns true if the peer is allowed to connect, i.e. there are no
+// AllowedPeers or the peer is one of them.
+func (m *PeerManager) IsAllowed(peerID types.NodeID) bool {
+	if len(m.options.AllowedPeers) == 0 {
+		return true
+	}
+	_, ok := m.options.AllowedPeers[peerID]
+	return ok
+}
+
+// isPersistent returns true if the peer is persistent, either because it's in
+// PersistentPeers or because it rotated its node key from a persistent peer.
+// The caller must hold the mutex lock.
+func (m *PeerManager) isPersistent(peerID types.NodeID) bool {
+	if m.retired[peerID] {
+		return false
+	}
+	return m.options.isPersistent(peerID) || m.store.IsPersistent(peerID)
+}
+
+func (m *PeerManager) isUnconditional(peerID types.NodeID) bool {
+	_, ok := m.options.UnconditionalPeers[peerID]
+	return ok
+}
+
+// numConnected returns the number of connected peers that count towards
+// MaxConnected, i.e. excluding unconditional peers.
+func (m *PeerManager) numConnected() int {
+	n := len(m.connected)
+	for id := range m.options.UnconditionalPeers {
+		if m.isConnected(id) {
+			n--
+		}
+	}
+	return n
+}
+
 type connectionStats struct {
 	incoming uint16
 	outgoing uint16
 }
 
+// getConnectedInfo counts the connected peers by direction. Persistent and
+// unconditional peers are not counted, since they don't count towards
+// MaxOutgoingConnections or MaxIncomingConnections.
 func (m *PeerManager) getConnectedInfo() connectionStats {
 	out := connectionStats{}
-	for _, direction := range m.connected {
+	for id, direction := range m.connected {
+		if m.isPersistent(id) || m.isUnconditional(id) {
+			continue
+		}
 		switch direction {
 		case peerConnectionIncoming:
 			out.incoming++
@@ -452,6 +668,14 @@ func (m *PeerManager) Add(address NodeAddress) (bool, error) {
 	if address.NodeID == m.selfID {
 		return false, fmt.Errorf("can't add self (%v) to peer store", m.selfID)
 	}
+	if !m.IsAllowed(address.NodeID) {
+		return false, fmt.Errorf("can't add peer %v: not an allowed peer", address.NodeID)
+	}
+	if ip := net.ParseIP(address.Hostname); ip != nil {
+		if err := m.options.IPFilter.Check(ip); err != nil {
+			return false, fmt.Errorf("can't add peer %v: %w", address.NodeID, err)
+		}
+	}
 
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
@@ -483,6 +707,19 @@ func (m *PeerManager) Add(address NodeAddress) (bool, error) {
 	return true, nil
 }
 
+// SelfID returns the node ID of the local node.
+func (m *PeerManager) SelfID() types.NodeID {
+	return m.selfID
+}
+
+// SetSelfAddress sets the address advertised to peers for them to dial back
+// to us, e.g. once it's been mapped on a NAT gateway after startup.
+func (m *PeerManager) SetSelfAddress(addr NodeAddress) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.options.SelfAddress = addr
+}
+
 // PeerRatio returns the ratio of peer addresses stored to the maximum size.
 func (m *PeerManager) PeerRatio() float64 {
 	m.mtx.Lock()
@@ -499,7 +736,7 @@ func (m *PeerManager) HasMaxPeerCapacity() bool {
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
 
-	return len(m.connected) >= int(m.options.MaxConnected)
+	return m.numConnected() >= int(m.options.MaxConnected)
 }
 
 func (m *PeerManager) HasDialedMaxPeers() bool {
@@ -536,58 +773,167 @@ func (m *PeerManager) TryDialNext() NodeAddress {
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
 
+	// Skips are recorded afresh by every call, even one that returns early.
+	m.dialSkips = m.dialSkips[:0]
+
+	// Don't keep dialing next node IDs of rotations that were abandoned. This
+	// only fails if the peer store can't be written to, in which case the
+	// dial below will fail as well.
+	_ = m.expireRotations(time.Now())
+
 	// We allow dialing MaxConnected+MaxConnectedUpgrade peers. Including
 	// MaxConnectedUpgrade allows us to probe additional peers that have a
 	// higher score than any other peers, and if successful evict it.
-	if m.options.MaxConnected > 0 && len(m.connected)+len(m.dialing) >= int(m.options.MaxConnected)+int(m.options.MaxConnectedUpgrade) {
+	//
+	// Unconditional peers are dialed regardless.
+	connectedFull := m.options.MaxConnected > 0 &&
+		m.numConnected()+len(m.dialing) >= int(m.options.MaxConnected)+int(m.options.MaxConnectedUpgrade)
+	if connectedFull && len(m.options.UnconditionalPeers) == 0 {
 		return NodeAddress{}
 	}
 
+	// Once the outgoing connections are used up, we only dial persistent
+	// peers, which don't count towards the limit.
 	cinfo := m.getConnectedInfo()
-	if m.options.MaxOutgoingConnections > 0 && cinfo.outgoing >= m.options.MaxOutgoingConnections {
-		return NodeAddress{}
-	}
+	outgoingFull := m.options.MaxOutgoingConnections > 0 && cinfo.outgoing >= m.options.MaxOutgoingConnections
+
+	subnetPeers := m.countSubnetPeers()
 
 	for _, peer := range m.store.Ranked() {
-		if m.dialing[peer.ID] || m.isConnected(peer.ID) {
-			continue
+		var skip DialSkipReason
+		switch {
+		case peer.ID == m.selfID:
+			skip = DialSkipSelf
+		case !m.IsAllowed(peer.ID):
+			skip = DialSkipFiltered
+		case m.dialing[peer.ID]:
+			skip = DialSkipDialing
+		case m.isConnected(peer.ID):
+			skip = DialSkipConnected
+		case m.isBanned(peer.ID):
+			skip = DialSkipBanList
+		case connectedFull && !m.isUnconditional(peer.ID):
+			skip = DialSkipConnectedFull
+		case outgoingFull && !peer.Persistent:
+			skip = DialSkipOutgoingFull
+		case !peer.LastRemoteBan.IsZero() && time.Since(peer.LastRemoteBan) < m.options.RemoteBanBackoff:
+			skip = DialSkipBanned
+		case !peer.LastDisconnected.IsZero() && time.Since(peer.LastDisconnected) < m.options.DisconnectCooldownPeriod:
+			skip = DialSkipCooldown
 		}
-
-		if !peer.LastDisconnected.IsZero() && time.Since(peer.LastDisconnected) < m.options.DisconnectCooldownPeriod {
+		if skip != "" {
+			for _, addressInfo := range peer.AddressInfo {
+				m.dialSkips = append(m.dialSkips, DialSkip{Address: addressInfo.Address, Reason: skip})
+			}
 			continue
 		}
 
 		for _, addressInfo := range peer.AddressInfo {
+			if ip := net.ParseIP(addressInfo.Address.Hostname); ip != nil && m.options.IPFilter.Check(ip) != nil {
+				m.dialSkips = append(m.dialSkips, DialSkip{Address: addressInfo.Address, Reason: DialSkipFiltered})
+				continue
+			}
 			if time.Since(addressInfo.LastDialFailure) < m.retryDelay(addressInfo.DialFailures, peer.Persistent) {
+				m.dialSkips = append(m.dialSkips, DialSkip{Address: addressInfo.Address, Reason: DialSkipBackoff})
 				continue
 			}
 
 			if id, ok := m.store.Resolve(addressInfo.Address); ok && (m.isConnected(id) || m.dialing[id]) {
+				skip := DialSkipConnected
+				if m.dialing[id] {
+					skip = DialSkipDialing
+				}
+				m.dialSkips = append(m.dialSkips, DialSkip{Address: addressInfo.Address, Reason: skip})
 				continue
 			}
 
+			subnet := ""
+			if m.options.MaxPeersPerSubnet > 0 && !peer.Persistent && !m.isUnconditional(peer.ID) {
+				subnet = m.subnet(addressInfo.Address)
+				if subnet != "" && subnetPeers[subnet] >= int(m.options.MaxPeersPerSubnet) {
+					m.dialSkips = append(m.dialSkips, DialSkip{Address: addressInfo.Address, Reason: DialSkipSubnetFull})
+					continue
+				}
+			}
+
 			// We now have an eligible address to dial. If we're full but have
 			// upgrade capacity (as checked above), we find a lower-scored peer
 			// we can replace and mark it as upgrading so noone else claims it.
 			//
 			// If we don't find one, there is no point in trying additional
 			// peers, since they will all have the same or lower score than this
-			// peer (since they're ordered by score via peerStore.Ranked).
-			if m.options.MaxConnected > 0 && len(m.connected) >= int(m.options.MaxConnected) {
+			// peer (since they're ordered by score via peerStore.Ranked),
+			// although we keep looking for unconditional peers.
+			if m.options.MaxConnected > 0 && m.numConnected() >= int(m.options.MaxConnected) && !m.isUnconditional(peer.ID) {
 				upgradeFromPeer := m.findUpgradeCandidate(peer.ID, peer.Score())
 				if upgradeFromPeer == "" {
-					return NodeAddress{}
+					if len(m.options.UnconditionalPeers) == 0 {
+						return NodeAddress{}
+					}
+					break
 				}
 				m.upgrading[upgradeFromPeer] = peer.ID
 			}
 
 			m.dialing[peer.ID] = true
+			if subnet != "" {
+				m.dialSubnets[peer.ID] = subnet
+			}
 			return addressInfo.Address
 		}
 	}
 	return NodeAddress{}
 }
 
+// countSubnetPeers counts the peers being dialed or connected to via outgoing
+// connections by subnet, for MaxPeersPerSubnet.
+func (m *PeerManager) countSubnetPeers() map[string]int {
+	counts := map[string]int{}
+	for id, subnet := range m.dialSubnets {
+		if !m.dialing[id] && m.connected[id] != peerConnectionOutgoing {
+			delete(m.dialSubnets, id)
+			continue
+		}
+		counts[subnet]++
+	}
+	return counts
+}
+
+// subnet returns the subnet of an address for MaxPeersPerSubnet, or an empty
+// string if the address hostname is not an IP.
+func (m *PeerManager) subnet(address NodeAddress) string {
+	ip := net.ParseIP(address.Hostname)
+	if ip == nil {
+		return ""
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		prefix := int(m.options.SubnetPrefixIPv4)
+		if prefix == 0 {
+			prefix = 24
+		}
+		return fmt.Sprintf("%v/%d", ip4.Mask(net.CIDRMask(prefix, 32)), prefix)
+	}
+	prefix := int(m.options.SubnetPrefixIPv6)
+	if prefix == 0 {
+		prefix = 48
+	}
+	return fmt.Sprintf("%v/%d", ip.Mask(net.CIDRMask(prefix, 128)), prefix)
+}
+
+// LastDialSkips returns the addresses that the last call to TryDialNext (or
+// DialNext) skipped over before returning, along with the reason each one was
+// skipped. It does not include addresses that weren't considered, e.g. because
+// an address to dial was found first or because we're out of connection
+// slots, so an address missing here is not necessarily eligible for dialing.
+func (m *PeerManager) LastDialSkips() []DialSkip {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	skips := make([]DialSkip, len(m.dialSkips))
+	copy(skips, m.dialSkips)
+	return skips
+}
+
 // DialFailed reports a failed dial attempt. This will make the peer available
 // for dialing again when appropriate (possibly after a retry timeout).
 func (m *PeerManager) DialFailed(ctx context.Context, address NodeAddress) error {
@@ -596,11 +942,17 @@ func (m *PeerManager) DialFailed(ctx context.Context, address NodeAddress) error
 	m.metrics.PeersConnectedFailure.Add(1)
 
 	delete(m.dialing, address.NodeID)
+	delete(m.dialSubnets, address.NodeID)
 	for from, to := range m.upgrading {
 		if to == address.NodeID {
 			delete(m.upgrading, from) // Unmark failed upgrade attempt.
 		}
 	}
+	if !m.isConnected(address.NodeID) {
+		if err := m.deleteRetired(address.NodeID); err != nil {
+			return err
+		}
+	}
 
 	peer, ok := m.store.Get(address.NodeID)
 	if !ok { // Peer may have been removed while dialing, ignore.
@@ -641,6 +993,48 @@ func (m *PeerManager) DialFailed(ctx context.Context, address NodeAddress) error
 	return nil
 }
 
+// DialCanceled reports a dial attempt that was abandoned before it completed,
+// e.g. because the connection limits were reached in the meantime. Unlike
+// DialFailed, it doesn't count against the address.
+func (m *PeerManager) DialCanceled(address NodeAddress) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	delete(m.dialing, address.NodeID)
+	delete(m.dialSubnets, address.NodeID)
+	for from, to := range m.upgrading {
+		if to == address.NodeID {
+			delete(m.upgrading, from)
+		}
+	}
+	m.dialWaker.Wake()
+}
+
+// WantsDial returns whether a dial to the peer that is in progress is still
+// wanted, i.e. whether the peer could be connected once the dial succeeds. It
+// returns false once the connection limits have been reached by other peers
+// in the meantime, so that the dial can be canceled.
+func (m *PeerManager) WantsDial(peerID types.NodeID) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if !m.dialing[peerID] {
+		return false
+	}
+	if m.isUnconditional(peerID) {
+		return true
+	}
+	if m.options.MaxConnected > 0 &&
+		m.numConnected() >= int(m.options.MaxConnected)+int(m.options.MaxConnectedUpgrade) {
+		return false
+	}
+	if m.options.MaxOutgoingConnections > 0 && !m.isPersistent(peerID) &&
+		m.getConnectedInfo().outgoing >= m.options.MaxOutgoingConnections {
+		return false
+	}
+	return true
+}
+
 // Dialed marks a peer as successfully dialed. Any further connections will be
 // rejected, and once disconnected the peer may be dialed again.
 func (m *PeerManager) Dialed(address NodeAddress) error {
@@ -663,12 +1057,15 @@ func (m *PeerManager) Dialed(address NodeAddress) error {
 	if address.NodeID == m.selfID {
 		return fmt.Errorf("rejecting connection to self (%v)", address.NodeID)
 	}
+	if !m.IsAllowed(address.NodeID) {
+		return fmt.Errorf("rejecting connection to peer %v, which is not allowed", address.NodeID)
+	}
 	if m.isConnected(address.NodeID) {
 		return fmt.Errorf("peer %v is already connected", address.NodeID)
 	}
-	if m.options.MaxConnected > 0 && len(m.connected) >= int(m.options.MaxConnected) {
-		if upgradeFromPeer == "" || len(m.connected) >= int(m.options.MaxConnected)+int(m.options.MaxConnectedUpgrade) {
-			return fmt.Errorf("already connected to maximum number of peers")
+	if m.options.MaxConnected > 0 && m.numConnected() >= int(m.options.MaxConnected) && !m.isUnconditional(address.NodeID) {
+		if upgradeFromPeer == "" || m.numConnected() >= int(m.options.MaxConnected)+int(m.options.MaxConnectedUpgrade) {
+			return errMaxConnected
 		}
 	}
 
@@ -692,8 +1089,11 @@ func (m *PeerManager) Dialed(address NodeAddress) error {
 	if err := m.store.Set(peer); err != nil {
 		return err
 	}
+	if err := m.completeRotation(peer.ID); err != nil {
+		return err
+	}
 
-	if upgradeFromPeer != "" && m.options.MaxConnected > 0 && len(m.connected) >= int(m.options.MaxConnected) {
+	if upgradeFromPeer != "" && m.options.MaxConnected > 0 && m.numConnected() >= int(m.options.MaxConnected) {
 		// Look for an even lower-scored peer that may have appeared since we
 		// started the upgrade.
 		if p, ok := m.store.Get(upgradeFromPeer); ok {
@@ -702,6 +1102,7 @@ func (m *PeerManager) Dialed(address NodeAddress) error {
 			}
 		}
 		m.evict[upgradeFromPeer] = true
+		m.evictReasons[upgradeFromPeer] = p2pproto.DisconnectPeerLimit
 		m.evictWaker.Wake()
 	}
 
@@ -735,11 +1136,24 @@ func (m *PeerManager) Accepted(peerID types.NodeID) error {
 	if peerID == m.selfID {
 		return fmt.Errorf("rejecting connection from self (%v)", peerID)
 	}
+	if !m.IsAllowed(peerID) {
+		return fmt.Errorf("rejecting connection from peer %v, which is not allowed", peerID)
+	}
 	if m.isConnected(peerID) {
 		return fmt.Errorf("peer %q is already connected", peerID)
 	}
-	if m.options.MaxConnected > 0 && len(m.connected) >= int(m.options.MaxConnected)+int(m.options.MaxConnectedUpgrade) {
-		return fmt.Errorf("already connected to maximum number of peers")
+	if m.isBanned(peerID) {
+		return fmt.Errorf("peer %v is banned", peerID)
+	}
+
+	unconditional := m.isUnconditional(peerID)
+	if m.options.MaxConnected > 0 && m.numConnected() >= int(m.options.MaxConnected)+int(m.options.MaxConnectedUpgrade) && !unconditional {
+		return errMaxConnected
+	}
+	incomingFull := m.options.MaxIncomingConnections > 0 && !unconditional && !m.isPersistent(peerID) &&
+		m.getConnectedInfo().incoming >= m.options.MaxIncomingConnections
+	if incomingFull && !m.options.EvictIncoming {
+		return errMaxIncoming
 	}
 
 	peer, ok := m.store.Get(peerID)
@@ -747,6 +1161,17 @@ func (m *PeerManager) Accepted(peerID types.NodeID) error {
 		peer = m.newPeerInfo(peerID)
 	}
 
+	// If the incoming connection slots are full and we evict incoming peers,
+	// look for a lower-scored incoming peer to replace. Evicting it also
+	// frees up a connection slot, so no upgrade is needed below.
+	var evictPeer types.NodeID
+	if incomingFull {
+		evictPeer = m.findIncomingEvictionCandidate(peer.ID, peer.Score())
+		if evictPeer == "" {
+			return errMaxIncoming
+		}
+	}
+
 	// reset this to avoid penalizing peers for their past transgressions
 	for _, addr := range peer.AddressInfo {
 		addr.DialFailures = 0
@@ -755,11 +1180,10 @@ func (m *PeerManager) Accepted(peerID types.NodeID) error {
 	// If all connections slots are full, but we allow upgrades (and we checked
 	// above that we have upgrade capacity), then we can look for a lower-scored
 	// peer to replace and if found accept the connection anyway and evict it.
-	var upgradeFromPeer types.NodeID
-	if m.options.MaxConnected > 0 && len(m.connected) >= int(m.options.MaxConnected) {
-		upgradeFromPeer = m.findUpgradeCandidate(peer.ID, peer.Score())
-		if upgradeFromPeer == "" {
-			return fmt.Errorf("already connected to maximum number of peers")
+	if evictPeer == "" && m.options.MaxConnected > 0 && m.numConnected() >= int(m.options.MaxConnected) && !unconditional {
+		evictPeer = m.findUpgradeCandidate(peer.ID, peer.Score())
+		if evictPeer == "" {
+			return errMaxConnected
 		}
 	}
 
@@ -771,16 +1195,178 @@ func (m *PeerManager) Accepted(peerID types.NodeID) error {
 	if err := m.store.Set(peer); err != nil {
 		return err
 	}
+	if err := m.completeRotation(peerID); err != nil {
+		return err
+	}
 
 	m.metrics.PeersConnectedIncoming.Add(1)
 	m.connected[peerID] = peerConnectionIncoming
-	if upgradeFromPeer != "" {
-		m.evict[upgradeFromPeer] = true
+	if evictPeer != "" {
+		m.evict[evictPeer] = true
+		m.evictReasons[evictPeer] = p2pproto.DisconnectPeerLimit
 	}
 	m.evictWaker.Wake()
 	return nil
 }
 
+// nodeKeyRotation is a node key rotation announced by a peer.
+type nodeKeyRotation struct {
+	prevID  types.NodeID // the node ID the peer is rotating from
+	added   bool         // whether the rotation added the next ID to the peer store
+	expires time.Time    // when the rotation is abandoned, if ever
+}
+
+// RotateNodeKey records a peer's announcement that it will rotate its node
+// key to nextID, which the router has verified. The peer's addresses are added
+// for nextID, and if the peer is persistent then so is nextID, such that we
+// can reconnect to the peer once it has rotated its key. It returns true if
+// any addresses were added. Rotations to node IDs that aren't allowed, or are
+// banned, are rejected.
+//
+// Once the peer connects with nextID, its previous node ID is retired: it's
+// removed from the peer store and no longer treated as persistent. If it
+// doesn't within NodeKeyRotationTimeout, the rotation is abandoned instead.
+// Neither is stored across restarts, so operators should still update their
+// persistent peers configuration.
+func (m *PeerManager) RotateNodeKey(peerID, nextID types.NodeID) (bool, error) {
+	if err := nextID.Validate(); err != nil {
+		return false, err
+	}
+	if nextID == peerID {
+		return false, fmt.Errorf("peer %v can't rotate to its own node ID", peerID)
+	}
+	if nextID == m.selfID {
+		return false, fmt.Errorf("peer %v can't rotate to our node ID", peerID)
+	}
+	if !m.IsAllowed(nextID) {
+		return false, fmt.Errorf("peer %v can't rotate to %v: not an allowed peer", peerID, nextID)
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.isBanned(nextID) {
+		return false, fmt.Errorf("peer %v can't rotate to %v: peer is banned", peerID, nextID)
+	}
+	if err := m.expireRotations(time.Now()); err != nil {
+		return false, err
+	}
+	rotation, ok := m.rotations[nextID]
+	if ok && rotation.prevID == peerID {
+		rotation.expires = m.rotationExpiry()
+		m.rotations[nextID] = rotation
+		return false, nil
+	}
+	peer, ok := m.store.Get(peerID)
+	if !ok {
+		return false, nil
+	}
+
+	next, known := m.store.Get(nextID)
+	if !known {
+		next = m.newPeerInfo(nextID)
+		m.metrics.PeersStored.Add(1)
+	}
+	if peer.Persistent {
+		next.Persistent = true
+	}
+	added := false
+	for address := range peer.AddressInfo {
+		address.NodeID = nextID
+		if _, ok := next.AddressInfo[address]; !ok {
+			next.AddressInfo[address] = &peerAddressInfo{Address: address}
+			added = true
+		}
+	}
+	if err := m.store.Set(next); err != nil {
+		return false, err
+	}
+	delete(m.retired, nextID)
+	m.rotations[nextID] = nodeKeyRotation{
+		prevID:  peerID,
+		added:   !known,
+		expires: m.rotationExpiry(),
+	}
+
+	if err := m.prunePeers(); err != nil {
+		return added, err
+	}
+	m.dialWaker.Wake()
+	return added, nil
+}
+
+// rotationExpiry returns the expiry time of a node key rotation announced now.
+func (m *PeerManager) rotationExpiry() time.Time {
+	if m.options.NodeKeyRotationTimeout == 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(m.options.NodeKeyRotationTimeout)
+}
+
+// expireRotations abandons node key rotations that have expired, unless the
+// next node ID is being dialed. Next node IDs that were added by the rotation
+// are removed from the peer store, otherwise they're no longer persistent
+// unless configured to be. The caller must hold the mutex lock.
+func (m *PeerManager) expireRotations(now time.Time) error {
+	for nextID, rotation := range m.rotations {
+		if rotation.expires.IsZero() || now.Before(rotation.expires) || m.dialing[nextID] {
+			continue
+		}
+		delete(m.rotations, nextID)
+		next, ok := m.store.Get(nextID)
+		switch {
+		case !ok || m.isConnected(nextID):
+		case rotation.added:
+			if err := m.store.Delete(nextID); err != nil {
+				return err
+			}
+			m.metrics.PeersStored.Add(-1)
+		default:
+			next.Persistent = m.options.isPersistent(nextID)
+			if err := m.store.Set(next); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// completeRotation retires the previous node ID of a peer that has connected
+// with a rotated node key. It's removed from the peer store, or once it
+// disconnects if it's still in use. The caller must hold the mutex lock.
+func (m *PeerManager) completeRotation(peerID types.NodeID) error {
+	rotation, ok := m.rotations[peerID]
+	if !ok {
+		return nil
+	}
+	delete(m.rotations, peerID)
+	m.retired[rotation.prevID] = true
+	if m.isConnected(rotation.prevID) || m.dialing[rotation.prevID] {
+		if prev, ok := m.store.Get(rotation.prevID); ok {
+			prev.Persistent = false
+			return m.store.Set(prev)
+		}
+		return nil
+	}
+	return m.deleteRetired(rotation.prevID)
+}
+
+// deleteRetired removes a retired node ID from the peer store, once it's no
+// longer in use. The caller must hold the mutex lock.
+func (m *PeerManager) deleteRetired(peerID types.NodeID) error {
+	if !m.retired[peerID] {
+		return nil
+	}
+	if _, ok := m.store.Get(peerID); !ok {
+		return nil
+	}
+	if err := m.store.Delete(peerID); err != nil {
+		return err
+	}
+	m.metrics.PeersStored.Add(-1)
+	return nil
+}
+
 // Ready marks a peer as ready, broadcasting status updates to
 // subscribers. The peer must already be marked as connected. This is
 // separate from Dialed() and Accepted() to allow the router to set up
@@ -795,13 +1381,57 @@ func (m *PeerManager) Ready(ctx context.Context, peerID types.NodeID, channels C
 	if m.isConnected(peerID) {
 		m.ready[peerID] = true
 		m.broadcast(ctx, PeerUpdate{
-			NodeID:   peerID,
-			Status:   PeerStatusUp,
-			Channels: channels,
+			NodeID:          peerID,
+			Status:          PeerStatusUp,
+			Channels:        channels,
+			ProtocolVersion: m.versions[peerID],
 		})
 	}
 }
 
+// SetProtocolVersion records the protocol versions negotiated with a
+// connected peer during the handshake, which are passed on to reactors when
+// the peer is ready.
+func (m *PeerManager) SetProtocolVersion(peerID types.NodeID, version types.ProtocolVersion) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.isConnected(peerID) {
+		m.versions[peerID] = version
+	}
+}
+
+// ProtocolVersion returns the protocol versions negotiated with a connected
+// peer, if any.
+func (m *PeerManager) ProtocolVersion(peerID types.NodeID) (types.ProtocolVersion, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	version, ok := m.versions[peerID]
+	return version, ok
+}
+
+// SetNodeInfo records the node info a connected peer sent us during the
+// handshake.
+func (m *PeerManager) SetNodeInfo(nodeInfo types.NodeInfo) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.isConnected(nodeInfo.NodeID) {
+		m.nodeInfos[nodeInfo.NodeID] = nodeInfo.Copy()
+	}
+}
+
+// NodeInfo returns the node info a connected peer sent us during the
+// handshake, if any.
+func (m *PeerManager) NodeInfo(peerID types.NodeID) (types.NodeInfo, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	nodeInfo, ok := m.nodeInfos[peerID]
+	return nodeInfo, ok
+}
+
 // EvictNext returns the next peer to evict (i.e. disconnect). If no evictable
 // peers are found, the call will block until one becomes available.
 func (m *PeerManager) EvictNext(ctx context.Context) (types.NodeID, error) {
@@ -836,7 +1466,7 @@ func (m *PeerManager) TryEvictNext() (types.NodeID, error) {
 
 	// If we're below capacity, we don't need to evict anything.
 	if m.options.MaxConnected == 0 ||
-		len(m.connected)-len(m.evicting) <= int(m.options.MaxConnected) {
+		m.numConnected()-len(m.evicting) <= int(m.options.MaxConnected) {
 		return "", nil
 	}
 
@@ -845,8 +1475,9 @@ func (m *PeerManager) TryEvictNext() (types.NodeID, error) {
 	ranked := m.store.Ranked()
 	for i := len(ranked) - 1; i >= 0; i-- {
 		peer := ranked[i]
-		if m.isConnected(peer.ID) && !m.evicting[peer.ID] {
+		if m.isConnected(peer.ID) && !m.evicting[peer.ID] && !m.isUnconditional(peer.ID) {
 			m.evicting[peer.ID] = true
+			m.evictReasons[peer.ID] = p2pproto.DisconnectPeerLimit
 			return peer.ID, nil
 		}
 	}
@@ -868,16 +1499,27 @@ func (m *PeerManager) Disconnected(ctx context.Context, peerID types.NodeID) {
 	}
 
 	ready := m.ready[peerID]
+	_, connected := m.connected[peerID]
+	unresponsive := m.unresponsive[peerID]
 
 	delete(m.connected, peerID)
 	delete(m.upgrading, peerID)
 	delete(m.evict, peerID)
 	delete(m.evicting, peerID)
+	delete(m.evictReasons, peerID)
 	delete(m.ready, peerID)
+	delete(m.versions, peerID)
+	delete(m.nodeInfos, peerID)
+	delete(m.unresponsive, peerID)
 
 	if peer, ok := m.store.Get(peerID); ok {
 		peer.LastDisconnected = time.Now()
+		// Reward peers for the time they stayed connected.
+		if connected && !peer.LastConnected.IsZero() {
+			peer.MutableScore += int64(peer.LastDisconnected.Sub(peer.LastConnected) / uptimeScoreInterval)
+		}
 		_ = m.store.Set(peer)
+		_ = m.deleteRetired(peerID)
 		// launch a thread to ping the dialWaker when the
 		// disconnected peer can be dialed again.
 		go func() {
@@ -893,14 +1535,94 @@ func (m *PeerManager) Disconnected(ctx context.Context, peerID types.NodeID) {
 
 	if ready {
 		m.broadcast(ctx, PeerUpdate{
-			NodeID: peerID,
-			Status: PeerStatusDown,
+			NodeID:       peerID,
+			Status:       PeerStatusDown,
+			Unresponsive: unresponsive,
 		})
 	}
 
 	m.dialWaker.Wake()
 }
 
+// Unresponsive reports that a connected peer stopped answering pings, so its
+// connection is considered dead. The peer's score is lowered, and the
+// PeerStatusDown update sent once it's disconnected is flagged as
+// unresponsive, so that e.g. the PEX reactor can look for a replacement. It
+// must be called before Disconnected.
+func (m *PeerManager) Unresponsive(peerID types.NodeID) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if !m.isConnected(peerID) || m.unresponsive[peerID] {
+		return
+	}
+	m.unresponsive[peerID] = true
+	m.metrics.PeersUnresponsive.Add(1)
+
+	if peer, ok := m.store.Get(peerID); ok && peer.MutableScore > math.MinInt16 {
+		peer.MutableScore--
+		_ = m.store.Set(peer)
+	}
+}
+
+// RemoteClosed reports that a connected peer closed the connection from its
+// end. If this happens within RemoteBanWindow of the connection being
+// established, and we weren't evicting the peer ourselves, we assume that the
+// peer has banned or rate-limited us and won't dial it again until
+// RemoteBanBackoff has elapsed. It must be called before Disconnected.
+func (m *PeerManager) RemoteClosed(ctx context.Context, peerID types.NodeID) {
+	m.remoteClosed(ctx, peerID, false)
+}
+
+// RemoteDisconnected is like RemoteClosed, but for peers that told us why they
+// closed the connection. Peers that are full or shutting down are not assumed
+// to have banned us, while peers that say they banned us are backed off even
+// outside of RemoteBanWindow. It must be called before Disconnected.
+func (m *PeerManager) RemoteDisconnected(ctx context.Context, peerID types.NodeID, reason p2pproto.DisconnectReason) {
+	switch reason {
+	case p2pproto.DisconnectPeerLimit, p2pproto.DisconnectShuttingDown:
+	case p2pproto.DisconnectBanned:
+		m.remoteClosed(ctx, peerID, true)
+	default:
+		m.remoteClosed(ctx, peerID, false)
+	}
+}
+
+func (m *PeerManager) remoteClosed(ctx context.Context, peerID types.NodeID, banned bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.options.RemoteBanBackoff == 0 || (m.options.RemoteBanWindow == 0 && !banned) {
+		return
+	}
+	if !m.isConnected(peerID) || m.evicting[peerID] {
+		return
+	}
+
+	peer, ok := m.store.Get(peerID)
+	if !ok || (!banned && time.Since(peer.LastConnected) > m.options.RemoteBanWindow) {
+		return
+	}
+
+	peer.LastRemoteBan = time.Now()
+	if err := m.store.Set(peer); err != nil {
+		return
+	}
+	m.metrics.PeersRemoteBanned.Add(1)
+
+	// wake DialNext() once the backoff has elapsed, so that the peer
+	// can be considered for dialing again.
+	go func() {
+		timer := time.NewTimer(m.options.RemoteBanBackoff)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			m.dialWaker.Wake()
+		case <-ctx.Done():
+		}
+	}()
+}
+
 // Errored reports a peer error, causing the peer to be evicted if it's
 // currently connected.
 //
@@ -915,11 +1637,135 @@ func (m *PeerManager) Errored(peerID types.NodeID, err error) {
 
 	if m.isConnected(peerID) {
 		m.evict[peerID] = true
+		m.evictReasons[peerID] = p2pproto.DisconnectProtocolError
 	}
 
 	m.evictWaker.Wake()
 }
 
+// Release evicts a connected peer that we have no further use for, e.g. a
+// seed evicting a peer it has crawled. Unlike Errored, the peer is not at
+// fault: it's told that we're out of room for it (DisconnectPeerLimit), so
+// that it doesn't back off from dialing us again as if we had banned it.
+func (m *PeerManager) Release(peerID types.NodeID) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.isConnected(peerID) {
+		m.evict[peerID] = true
+		m.evictReasons[peerID] = p2pproto.DisconnectPeerLimit
+	}
+
+	m.evictWaker.Wake()
+}
+
+// Redial evicts a connected peer so that it's dialed again once disconnected,
+// e.g. because its hostname now resolves to a different IP address.
+func (m *PeerManager) Redial(peerID types.NodeID) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.isConnected(peerID) {
+		m.evict[peerID] = true
+		m.evictReasons[peerID] = p2pproto.DisconnectUnknown
+	}
+
+	m.evictWaker.Wake()
+}
+
+// DialImmediately clears the dial failures of a peer's addresses and wakes up
+// DialNext, so that the peer is dialed right away if it's not connected, e.g.
+// to dial seeds without waiting for their retry delay when we have no peers.
+func (m *PeerManager) DialImmediately(peerID types.NodeID) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	peer, ok := m.store.Get(peerID)
+	if !ok {
+		return nil
+	}
+	for _, addressInfo := range peer.AddressInfo {
+		addressInfo.DialFailures = 0
+		addressInfo.LastDialFailure = time.Time{}
+	}
+	if err := m.store.Set(peer); err != nil {
+		return err
+	}
+
+	m.dialWaker.Wake()
+	return nil
+}
+
+// EvictReason returns the reason a peer is being evicted, to give to the peer
+// when disconnecting it, or DisconnectUnknown if it isn't being evicted.
+func (m *PeerManager) EvictReason(peerID types.NodeID) p2pproto.DisconnectReason {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.evictReasons[peerID]
+}
+
+// ConnectionClosed records the quality of a peer connection once it closes:
+// its ping round-trip time (0 if unknown), and whether it failed. These are
+// persisted, and used to prefer stable, low-latency peers among equally scored
+// ones.
+func (m *PeerManager) ConnectionClosed(peerID types.NodeID, rtt time.Duration, failed bool) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	peer, ok := m.store.Get(peerID)
+	if !ok {
+		return nil
+	}
+	if rtt > 0 {
+		peer.RTTSamples++
+		if peer.RTTSamples > rttWindow {
+			peer.RTTSamples = rttWindow
+		}
+		// Average the RTT over the peer's last rttWindow connections.
+		peer.RTT += (rtt - peer.RTT) / time.Duration(peer.RTTSamples)
+	}
+	peer.Connections++
+	if failed {
+		peer.ConnectionErrors++
+	}
+	m.store.ranked = nil // the ranking of equally scored peers may change
+	return m.store.Set(peer)
+}
+
+// Ban adds a peer to the ban list for the given duration, disconnecting it if
+// connected. Banned peers are not dialed, accepted or advertised until the ban
+// expires. Bans are persisted, and an existing longer ban is not shortened.
+func (m *PeerManager) Ban(peerID types.NodeID, duration time.Duration) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	until := time.Now().Add(duration)
+	if until.After(m.store.bans[peerID]) {
+		if err := m.store.SetBan(peerID, until); err != nil {
+			return err
+		}
+	}
+
+	if m.isConnected(peerID) {
+		m.evict[peerID] = true
+		m.evictReasons[peerID] = p2pproto.DisconnectBanned
+		m.evictWaker.Wake()
+	}
+	return nil
+}
+
+// IsBanned returns true if the peer is on the ban list.
+func (m *PeerManager) IsBanned(peerID types.NodeID) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.isBanned(peerID)
+}
+
+func (m *PeerManager) isBanned(peerID types.NodeID) bool {
+	until, ok := m.store.bans[peerID]
+	return ok && time.Now().Before(until)
+}
+
 // Inactivate marks a peer as inactive which means we won't attempt to
 // dial this peer again. A peer can be reactivated by successfully
 // dialing and connecting to the node.
@@ -961,7 +1807,7 @@ func (m *PeerManager) Advertise(peerID types.NodeID, limit uint16) []NodeAddress
 
 	// get the total number of possible addresses
 	for _, peer := range ranked {
-		if peer.ID == peerID {
+		if peer.ID == peerID || m.isBanned(peer.ID) {
 			continue
 		}
 		score := int(peer.Score())
@@ -999,7 +1845,7 @@ func (m *PeerManager) Advertise(peerID types.NodeID, limit uint16) []NodeAddress
 		addedLastIteration = false
 
 		for idx, peer := range ranked {
-			if peer.ID == peerID {
+			if peer.ID == peerID || m.isBanned(peer.ID) {
 				continue
 			}
 
@@ -1128,6 +1974,7 @@ func (m *PeerManager) processPeerEvent(ctx context.Context, pu PeerUpdate) {
 		}
 		m.store.peers[pu.NodeID].MutableScore++
 	}
+	m.store.ranked = nil // score changed, so the ranking must be recomputed
 }
 
 // broadcast broadcasts a peer update to all subscriptions. The caller must
@@ -1189,6 +2036,140 @@ func (m *PeerManager) Scores() map[types.NodeID]PeerScore {
 	return scores
 }
 
+// Score returns the current score of a peer, or 0 if the peer is unknown.
+func (m *PeerManager) Score(peerID types.NodeID) PeerScore {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if peer, ok := m.store.Get(peerID); ok {
+		return peer.Score()
+	}
+	return 0
+}
+
+// ConnectionAges returns how long each currently connected peer has been
+// connected for.
+func (m *PeerManager) ConnectionAges() map[types.NodeID]time.Duration {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	ages := make(map[types.NodeID]time.Duration, len(m.connected))
+	for id := range m.connected {
+		if peer, ok := m.store.Get(id); ok {
+			ages[id] = time.Since(peer.LastConnected)
+		}
+	}
+	return ages
+}
+
+// NeverConnectedAddress is a peer address that we have failed to dial, and
+// have never been connected to.
+type NeverConnectedAddress struct {
+	Address         NodeAddress
+	DialFailures    uint32
+	LastDialFailure time.Time
+}
+
+// NeverConnected returns the addresses that have failed to dial at least once,
+// belonging to peers that we have never been connected to, neither via an
+// outbound dial nor an inbound connection. Operators can use this to decide
+// whether to purge them. The order is arbitrary.
+func (m *PeerManager) NeverConnected() []NeverConnectedAddress {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	addresses := []NeverConnectedAddress{}
+	for _, peer := range m.store.Ranked() {
+		if !peer.LastConnected.IsZero() {
+			continue
+		}
+		for _, addressInfo := range peer.AddressInfo {
+			if addressInfo.DialFailures == 0 || !addressInfo.LastDialSuccess.IsZero() {
+				continue
+			}
+			addresses = append(addresses, NeverConnectedAddress{
+				Address:         addressInfo.Address,
+				DialFailures:    addressInfo.DialFailures,
+				LastDialFailure: addressInfo.LastDialFailure,
+			})
+		}
+	}
+	return addresses
+}
+
+// KnownAddress describes an address in the peer store, for inspection by
+// operators.
+type KnownAddress struct {
+	Address         NodeAddress
+	Score           PeerScore
+	Persistent      bool
+	Connected       bool
+	BannedUntil     time.Time
+	LastConnected   time.Time
+	LastDialSuccess time.Time
+	LastDialFailure time.Time
+	DialFailures    uint32 // since last successful dial
+}
+
+// KnownAddresses returns all addresses in the peer store, ordered by peer
+// score (better peers first).
+func (m *PeerManager) KnownAddresses() []KnownAddress {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	addresses := []KnownAddress{}
+	for _, peer := range m.store.Ranked() {
+		for _, addressInfo := range peer.AddressInfo {
+			addresses = append(addresses, KnownAddress{
+				Address:         addressInfo.Address,
+				Score:           peer.Score(),
+				Persistent:      peer.Persistent,
+				Connected:       m.isConnected(peer.ID),
+				BannedUntil:     m.store.bans[peer.ID],
+				LastConnected:   peer.LastConnected,
+				LastDialSuccess: addressInfo.LastDialSuccess,
+				LastDialFailure: addressInfo.LastDialFailure,
+				DialFailures:    addressInfo.DialFailures,
+			})
+		}
+	}
+	return addresses
+}
+
+// RemoveAddress removes an address from the peer store, returning false if it
+// wasn't known. Once a peer has no addresses left, it is removed entirely
+// unless we're currently connected to it. Addresses of persistent peers can't
+// be removed, since they'd be re-added from the configuration on restart.
+func (m *PeerManager) RemoveAddress(address NodeAddress) (bool, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	peer, ok := m.store.Get(address.NodeID)
+	if !ok {
+		return false, nil
+	}
+	if _, ok := peer.AddressInfo[address]; !ok {
+		return false, nil
+	}
+	if peer.Persistent {
+		return false, fmt.Errorf("can't remove address of persistent peer %v", peer.ID)
+	}
+
+	delete(peer.AddressInfo, address)
+	delete(m.store.index, address)
+	if len(peer.AddressInfo) == 0 && !m.dialing[peer.ID] && !m.isConnected(peer.ID) {
+		if err := m.store.Delete(peer.ID); err != nil {
+			return false, err
+		}
+		m.metrics.PeersStored.Add(-1)
+		return true, nil
+	}
+	if err := m.store.Set(peer); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // Status returns the status for a peer, primarily for testing.
 func (m *PeerManager) Status(id types.NodeID) PeerStatus {
 	m.mtx.Lock()
@@ -1221,6 +2202,7 @@ func (m *PeerManager) findUpgradeCandidate(id types.NodeID, score PeerScore) typ
 		case candidate.Score() >= score:
 			return "" // no further peers can be scored lower, due to sorting
 		case !m.isConnected(candidate.ID):
+		case m.isUnconditional(candidate.ID):
 		case m.evict[candidate.ID]:
 		case m.evicting[candidate.ID]:
 		case m.upgrading[candidate.ID] != "":
@@ -1231,6 +2213,31 @@ func (m *PeerManager) findUpgradeCandidate(id types.NodeID, score PeerScore) typ
 	return ""
 }
 
+// findIncomingEvictionCandidate looks for the lowest-scored connected incoming
+// peer that has a lower score than the given score, and can be evicted to make
+// room for an incoming connection from the given peer. If none is found, it
+// returns an empty ID. The caller must hold the mutex lock.
+func (m *PeerManager) findIncomingEvictionCandidate(id types.NodeID, score PeerScore) types.NodeID {
+	ranked := m.store.Ranked()
+	for i := len(ranked) - 1; i >= 0; i-- {
+		candidate := ranked[i]
+		switch {
+		case candidate.ID == id:
+			continue
+		case candidate.Score() >= score:
+			return "" // no further peers can be scored lower, due to sorting
+		case m.connected[candidate.ID] != peerConnectionIncoming:
+		case candidate.Persistent:
+		case m.isUnconditional(candidate.ID):
+		case m.evict[candidate.ID]:
+		case m.evicting[candidate.ID]:
+		default:
+			return candidate.ID
+		}
+	}
+	return ""
+}
+
 // retryDelay calculates a dial retry delay using exponential backoff, based on
 // retry settings in PeerManagerOptions. If retries are disabled (i.e.
 // MinRetryTime is 0), this returns retryNever (i.e. an infinite retry delay).
@@ -1248,6 +2255,17 @@ func (m *PeerManager) retryDelay(failures uint32, persistent bool) time.Duration
 	}
 
 	delay := m.options.MinRetryTime * time.Duration(failures)
+	if persistent {
+		// Persistent peers are retried forever, so back off exponentially
+		// instead to avoid hammering peers that are down for a long time.
+		delay = m.options.MinRetryTime
+		for i := uint32(1); i < failures && delay < math.MaxInt64/2; i++ {
+			if maxDelay > 0 && delay >= maxDelay {
+				break
+			}
+			delay *= 2
+		}
+	}
 	if m.options.RetryTimeJitter > 0 {
 		delay += time.Duration(m.rand.Int63n(int64(m.options.RetryTimeJitter)))
 	}
@@ -1271,6 +2289,7 @@ type peerStore struct {
 	peers  map[types.NodeID]*peerInfo
 	index  map[NodeAddress]types.NodeID
 	ranked []*peerInfo // cache for Ranked(), nil invalidates cache
+	bans   map[types.NodeID]time.Time
 }
 
 // newPeerStore creates a new peer store, loading all persisted peers from the
@@ -1283,6 +2302,9 @@ func newPeerStore(db dbm.DB) (*peerStore, error) {
 	if err := store.loadPeers(); err != nil {
 		return nil, err
 	}
+	if err := store.loadBans(); err != nil {
+		return nil, err
+	}
 	return store, nil
 }
 
@@ -1325,6 +2347,70 @@ func (s *peerStore) loadPeers() error {
 	return nil
 }
 
+// loadBans loads all unexpired bans from the database into memory, removing
+// expired ones.
+func (s *peerStore) loadBans() error {
+	bans := map[types.NodeID]time.Time{}
+	expired := [][]byte{}
+	now := time.Now()
+
+	start, end := keyPeerBanRange()
+	iter, err := s.db.Iterator(start, end)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var (
+			prefix int64
+			id     string
+			nanos  int64
+		)
+		if _, err := orderedcode.Parse(string(iter.Key()), &prefix, &id); err != nil {
+			return fmt.Errorf("invalid peer ban key: %w", err)
+		}
+		if _, err := orderedcode.Parse(string(iter.Value()), &nanos); err != nil {
+			return fmt.Errorf("invalid peer ban data: %w", err)
+		}
+		until := time.Unix(0, nanos)
+		if !now.Before(until) {
+			expired = append(expired, append([]byte{}, iter.Key()...))
+			continue
+		}
+		bans[types.NodeID(id)] = until
+	}
+	if iter.Error() != nil {
+		return iter.Error()
+	}
+	for _, key := range expired {
+		if err := s.db.Delete(key); err != nil {
+			return err
+		}
+	}
+	s.bans = bans
+	return nil
+}
+
+// SetBan bans a peer until the given time.
+func (s *peerStore) SetBan(id types.NodeID, until time.Time) error {
+	bz, err := orderedcode.Append(nil, until.UnixNano())
+	if err != nil {
+		return err
+	}
+	if err := s.db.Set(keyPeerBan(id), bz); err != nil {
+		return err
+	}
+	s.bans[id] = until
+	return nil
+}
+
+// IsPersistent returns true if the peer exists and is persistent, without
+// copying it like Get.
+func (s *peerStore) IsPersistent(id types.NodeID) bool {
+	peer, ok := s.peers[id]
+	return ok && peer.Persistent
+}
+
 // Get fetches a peer. The boolean indicates whether the peer existed or not.
 // The returned peer info is a copy, and can be mutated at will.
 func (s *peerStore) Get(id types.NodeID) (peerInfo, bool) {
@@ -1424,7 +2510,22 @@ func (s *peerStore) Ranked() []*peerInfo {
 		s.ranked = append(s.ranked, peer)
 	}
 	sort.Slice(s.ranked, func(i, j int) bool {
-		return s.ranked[i].Score() > s.ranked[j].Score()
+		a, b := s.ranked[i], s.ranked[j]
+		if scoreA, scoreB := a.Score(), b.Score(); scoreA != scoreB {
+			return scoreA > scoreB
+		}
+		// Among equally scored peers, prefer stable and low-latency ones.
+		if rateA, rateB := a.connectionErrorRate(), b.connectionErrorRate(); rateA != rateB {
+			return rateA < rateB
+		}
+		switch {
+		case a.RTT == b.RTT:
+			return false
+		case a.RTT == 0 || b.RTT == 0:
+			return b.RTT == 0 // unknown RTTs last
+		default:
+			return a.RTT < b.RTT
+		}
 	})
 	return s.ranked
 }
@@ -1441,10 +2542,17 @@ type peerInfo struct {
 	LastConnected    time.Time
 	LastDisconnected time.Time
 
+	// Connection quality statistics, updated when connections close.
+	RTT              time.Duration // rolling average ping round-trip time, 0 if unknown
+	RTTSamples       uint32        // number of connections RTT is averaged over
+	Connections      uint32        // number of closed connections
+	ConnectionErrors uint32        // number of connections that failed
+
 	// These fields are ephemeral, i.e. not persisted to the database.
-	Persistent bool
-	Height     int64
-	FixedScore PeerScore // mainly for tests
+	Persistent    bool
+	Height        int64
+	FixedScore    PeerScore // mainly for tests
+	LastRemoteBan time.Time // when the peer last appeared to ban us
 
 	MutableScore int64 // updated by router
 	Inactive     bool
@@ -1457,6 +2565,15 @@ func peerInfoFromProto(msg *p2pproto.PeerInfo) (*peerInfo, error) {
 		ID:          types.NodeID(msg.ID),
 		AddressInfo: map[NodeAddress]*peerAddressInfo{},
 		Inactive:    msg.Inactive,
+
+		RTT:              msg.RTT,
+		Connections:      msg.Connections,
+		ConnectionErrors: msg.ConnectionErrors,
+	}
+	if p.RTT > 0 {
+		// The number of samples isn't persisted, so averaging resumes with
+		// a full window.
+		p.RTTSamples = rttWindow
 	}
 	if msg.LastConnected != nil {
 		p.LastConnected = *msg.LastConnected
@@ -1481,6 +2598,10 @@ func (p *peerInfo) ToProto() *p2pproto.PeerInfo {
 		ID:            string(p.ID),
 		Inactive:      p.Inactive,
 		LastConnected: &p.LastConnected,
+
+		RTT:              p.RTT,
+		Connections:      p.Connections,
+		ConnectionErrors: p.ConnectionErrors,
 	}
 	for _, addressInfo := range p.AddressInfo {
 		msg.AddressInfo = append(msg.AddressInfo, addressInfo.ToProto())
@@ -1573,6 +2694,15 @@ func (p *peerInfo) Score() PeerScore {
 	return PeerScore(score)
 }
 
+// connectionErrorRate returns the fraction of the peer's closed connections
+// that failed.
+func (p *peerInfo) connectionErrorRate() float64 {
+	if p.Connections == 0 {
+		return 0
+	}
+	return float64(p.ConnectionErrors) / float64(p.Connections)
+}
+
 // Validate validates the peer info.
 func (p *peerInfo) Validate() error {
 	if p.ID == "" {
@@ -1639,6 +2769,7 @@ func (a *peerAddressInfo) Validate() error {
 // Database key prefixes.
 const (
 	prefixPeerInfo int64 = 1
+	prefixPeerBan  int64 = 2
 )
 
 // keyPeerInfo generates a peerInfo database key.
@@ -1662,3 +2793,25 @@ func keyPeerInfoRange() ([]byte, []byte) {
 	}
 	return start, end
 }
+
+// keyPeerBan generates a peer ban database key.
+func keyPeerBan(id types.NodeID) []byte {
+	key, err := orderedcode.Append(nil, prefixPeerBan, string(id))
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// keyPeerBanRange generates start/end keys for the entire peer ban key range.
+func keyPeerBanRange() ([]byte, []byte) {
+	start, err := orderedcode.Append(nil, prefixPeerBan, "")
+	if err != nil {
+		panic(err)
+	}
+	end, err := orderedcode.Append(nil, prefixPeerBan, orderedcode.Infinity)
+	if err != nil {
+		panic(err)
+	}
+	return start, end
+}
@@ -0,0 +1,46 @@
+package p2p
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestPeerManager_RetryDelay(t *testing.T) {
+	selfID := types.NodeID(strings.Repeat("f", 40))
+	peerManager, err := NewPeerManager(selfID, dbm.NewMemDB(), PeerManagerOptions{
+		MinRetryTime:           time.Second,
+		MaxRetryTime:           10 * time.Second,
+		MaxRetryTimePersistent: 20 * time.Second,
+	})
+	require.NoError(t, err)
+
+	testcases := []struct {
+		failures   uint32
+		persistent bool
+		expect     time.Duration
+	}{
+		{0, false, 0},
+		{1, false, time.Second},
+		{3, false, 3 * time.Second},
+		{20, false, 10 * time.Second},
+
+		// persistent peers back off exponentially
+		{0, true, 0},
+		{1, true, time.Second},
+		{2, true, 2 * time.Second},
+		{4, true, 8 * time.Second},
+		{5, true, 16 * time.Second},
+		{6, true, 20 * time.Second},
+		{1000, true, 20 * time.Second},
+	}
+	for _, tc := range testcases {
+		require.Equal(t, tc.expect, peerManager.retryDelay(tc.failures, tc.persistent),
+			"failures=%v persistent=%v", tc.failures, tc.persistent)
+	}
+}
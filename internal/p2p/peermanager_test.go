@@ -14,6 +14,7 @@ import (
 	dbm "github.com/tendermint/tm-db"
 
 	"github.com/tendermint/tendermint/internal/p2p"
+	p2pproto "github.com/tendermint/tendermint/proto/tendermint/p2p"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -56,6 +57,34 @@ func TestPeerManagerOptions_Validate(t *testing.T) {
 			MaxConnectedUpgrade: 2,
 		}, false},
 
+		// UnconditionalPeers
+		"valid UnconditionalPeers NodeID": {p2p.PeerManagerOptions{
+			UnconditionalPeers: map[types.NodeID]struct{}{nodeID: {}},
+		}, true},
+		"invalid UnconditionalPeers NodeID": {p2p.PeerManagerOptions{
+			UnconditionalPeers: map[types.NodeID]struct{}{"foo": {}},
+		}, false},
+
+		// AllowedPeers
+		"valid AllowedPeers NodeID": {p2p.PeerManagerOptions{
+			AllowedPeers: map[types.NodeID]struct{}{nodeID: {}},
+		}, true},
+		"invalid AllowedPeers NodeID": {p2p.PeerManagerOptions{
+			AllowedPeers: map[types.NodeID]struct{}{"foo": {}},
+		}, false},
+		"PersistentPeers in AllowedPeers": {p2p.PeerManagerOptions{
+			PersistentPeers: []types.NodeID{nodeID},
+			AllowedPeers:    map[types.NodeID]struct{}{nodeID: {}},
+		}, true},
+		"PersistentPeers not in AllowedPeers": {p2p.PeerManagerOptions{
+			PersistentPeers: []types.NodeID{nodeID},
+			AllowedPeers:    map[types.NodeID]struct{}{"ffeeddccbbaa99887766554433221100ffeeddcc": {}},
+		}, false},
+		"UnconditionalPeers not in AllowedPeers": {p2p.PeerManagerOptions{
+			UnconditionalPeers: map[types.NodeID]struct{}{nodeID: {}},
+			AllowedPeers:       map[types.NodeID]struct{}{"ffeeddccbbaa99887766554433221100ffeeddcc": {}},
+		}, false},
+
 		// MaxPeers
 		"MaxPeers without MaxConnected": {p2p.PeerManagerOptions{
 			MaxPeers: 3,
@@ -71,6 +100,16 @@ func TestPeerManagerOptions_Validate(t *testing.T) {
 			MaxConnectedUpgrade: 1,
 		}, true},
 
+		// MaxIncomingConnections
+		"MaxIncomingConnections above MaxConnected": {p2p.PeerManagerOptions{
+			MaxConnected:           2,
+			MaxIncomingConnections: 3,
+		}, false},
+		"MaxIncomingConnections at MaxConnected": {p2p.PeerManagerOptions{
+			MaxConnected:           2,
+			MaxIncomingConnections: 2,
+		}, true},
+
 		// MaxRetryTime
 		"MaxRetryTime below MinRetryTime": {p2p.PeerManagerOptions{
 			MinRetryTime: 7 * time.Second,
@@ -96,6 +135,15 @@ func TestPeerManagerOptions_Validate(t *testing.T) {
 		"MaxRetryTimePersistent without MinRetryTime": {p2p.PeerManagerOptions{
 			MaxRetryTimePersistent: 5 * time.Second,
 		}, false},
+
+		// RemoteBanBackoff
+		"RemoteBanBackoff with RemoteBanWindow": {p2p.PeerManagerOptions{
+			RemoteBanWindow:  5 * time.Second,
+			RemoteBanBackoff: time.Minute,
+		}, true},
+		"RemoteBanBackoff without RemoteBanWindow": {p2p.PeerManagerOptions{
+			RemoteBanBackoff: time.Minute,
+		}, false},
 	}
 	for name, tc := range testcases {
 		tc := tc
@@ -476,6 +524,117 @@ func TestPeerManager_DialNext_WakeOnDisconnected(t *testing.T) {
 	require.Equal(t, a, dial)
 }
 
+func TestPeerManager_TryDialNext_ConnectionQuality(t *testing.T) {
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+	c := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("c", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	for _, addr := range []p2p.NodeAddress{a, b, c} {
+		added, err := peerManager.Add(addr)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	// Equally scored peers are dialed in order of latency, with unknown
+	// latencies last.
+	require.NoError(t, peerManager.ConnectionClosed(a.NodeID, 100*time.Millisecond, false))
+	require.NoError(t, peerManager.ConnectionClosed(b.NodeID, 10*time.Millisecond, false))
+	require.Equal(t, b, peerManager.TryDialNext())
+	require.Equal(t, a, peerManager.TryDialNext())
+	require.Equal(t, c, peerManager.TryDialNext())
+
+	// Peers whose connections fail are dialed after stable ones.
+	peerManager2, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	for _, addr := range []p2p.NodeAddress{a, b} {
+		added, err := peerManager2.Add(addr)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	require.NoError(t, peerManager2.ConnectionClosed(a.NodeID, 100*time.Millisecond, false))
+	require.NoError(t, peerManager2.ConnectionClosed(b.NodeID, 10*time.Millisecond, true))
+	require.Equal(t, a, peerManager2.TryDialNext())
+	require.Equal(t, b, peerManager2.TryDialNext())
+}
+
+func TestPeerManager_ConnectionQuality_Persisted(t *testing.T) {
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+	db := dbm.NewMemDB()
+
+	peerManager, err := p2p.NewPeerManager(selfID, db, p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	for _, addr := range []p2p.NodeAddress{a, b} {
+		added, err := peerManager.Add(addr)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	// A's RTT is averaged over its connections, rather than being dominated
+	// by the latest one, and ends up above b's.
+	for i := 0; i < 5; i++ {
+		require.NoError(t, peerManager.ConnectionClosed(a.NodeID, 100*time.Millisecond, false))
+	}
+	require.NoError(t, peerManager.ConnectionClosed(a.NodeID, 10*time.Millisecond, false))
+	require.NoError(t, peerManager.ConnectionClosed(b.NodeID, 50*time.Millisecond, false))
+	require.Equal(t, b, peerManager.TryDialNext())
+
+	// The statistics survive a restart.
+	peerManager, err = p2p.NewPeerManager(selfID, db, p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	require.Equal(t, b, peerManager.TryDialNext())
+	require.Equal(t, a, peerManager.TryDialNext())
+
+	// As do connection failures.
+	require.NoError(t, peerManager.ConnectionClosed(b.NodeID, 0, true))
+	peerManager, err = p2p.NewPeerManager(selfID, db, p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	require.Equal(t, a, peerManager.TryDialNext())
+}
+
+func TestPeerManager_TryDialNext_MaxPeersPerSubnet(t *testing.T) {
+	ctx := context.Background()
+	a := p2p.NodeAddress{Protocol: "tcp", NodeID: types.NodeID(strings.Repeat("a", 40)), Hostname: "10.0.0.1", Port: 26656}
+	b := p2p.NodeAddress{Protocol: "tcp", NodeID: types.NodeID(strings.Repeat("b", 40)), Hostname: "10.0.0.2", Port: 26656}
+	c := p2p.NodeAddress{Protocol: "tcp", NodeID: types.NodeID(strings.Repeat("c", 40)), Hostname: "10.0.0.3", Port: 26656}
+	d := p2p.NodeAddress{Protocol: "tcp", NodeID: types.NodeID(strings.Repeat("d", 40)), Hostname: "10.0.1.1", Port: 26656}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		MaxPeersPerSubnet: 2,
+	})
+	require.NoError(t, err)
+	for _, addr := range []p2p.NodeAddress{a, b, c, d} {
+		added, err := peerManager.Add(addr)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	// Only two of the peers in 10.0.0.0/24 should be dialed, along with d.
+	dialed := map[string]p2p.NodeAddress{}
+	for i := 0; i < 3; i++ {
+		dial := peerManager.TryDialNext()
+		require.NotZero(t, dial)
+		dialed[dial.Hostname] = dial
+	}
+	require.Contains(t, dialed, d.Hostname)
+	require.Zero(t, peerManager.TryDialNext())
+
+	// Once a dial in the subnet fails, the remaining peer can be dialed.
+	var failed p2p.NodeAddress
+	for _, dial := range dialed {
+		if dial.Hostname != d.Hostname {
+			failed = dial
+			break
+		}
+	}
+	require.NoError(t, peerManager.DialFailed(ctx, failed))
+	dial := peerManager.TryDialNext()
+	require.NotZero(t, dial)
+	require.NotContains(t, dialed, dial.Hostname)
+}
+
 func TestPeerManager_TryDialNext_MaxConnected(t *testing.T) {
 	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
 	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
@@ -509,6 +668,36 @@ func TestPeerManager_TryDialNext_MaxConnected(t *testing.T) {
 	require.Zero(t, dial)
 }
 
+func TestPeerManager_TryDialNext_Unconditional(t *testing.T) {
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+	c := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("c", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		UnconditionalPeers: map[types.NodeID]struct{}{c.NodeID: {}},
+		MaxConnected:       1,
+	})
+	require.NoError(t, err)
+
+	// Connect to a, filling up all connection slots.
+	added, err := peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+
+	// b is skipped, but we still dial the unconditional c.
+	added, err = peerManager.Add(b)
+	require.NoError(t, err)
+	require.True(t, added)
+	added, err = peerManager.Add(c)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	require.Equal(t, c, peerManager.TryDialNext())
+	require.NoError(t, peerManager.Dialed(c))
+	require.Zero(t, peerManager.TryDialNext())
+}
+
 func TestPeerManager_TryDialNext_MaxConnectedUpgrade(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -702,6 +891,153 @@ func TestPeerManager_TryDialNext_Multiple(t *testing.T) {
 	require.Zero(t, address)
 }
 
+func TestPeerManager_LastDialSkips(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	connected := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	dialing := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+	cooldown := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("c", 40))}
+	banned := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("d", 40))}
+	backoff := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("e", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		MinRetryTime:             time.Hour,
+		DisconnectCooldownPeriod: time.Hour,
+		RemoteBanWindow:          time.Hour,
+		RemoteBanBackoff:         time.Hour,
+	})
+	require.NoError(t, err)
+	require.Empty(t, peerManager.LastDialSkips())
+
+	// The peer being dialed must be the only one known when dialing it.
+	added, err := peerManager.Add(dialing)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.Equal(t, dialing, peerManager.TryDialNext())
+
+	for _, address := range []p2p.NodeAddress{connected, cooldown, banned, backoff} {
+		added, err := peerManager.Add(address)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	require.NoError(t, peerManager.Accepted(connected.NodeID))
+
+	require.NoError(t, peerManager.Accepted(cooldown.NodeID))
+	peerManager.Disconnected(ctx, cooldown.NodeID)
+
+	require.NoError(t, peerManager.Accepted(banned.NodeID))
+	peerManager.RemoteClosed(ctx, banned.NodeID)
+	peerManager.Disconnected(ctx, banned.NodeID)
+
+	require.NoError(t, peerManager.DialFailed(ctx, backoff))
+
+	require.Zero(t, peerManager.TryDialNext())
+	require.ElementsMatch(t, []p2p.DialSkip{
+		{Address: connected, Reason: p2p.DialSkipConnected},
+		{Address: dialing, Reason: p2p.DialSkipDialing},
+		{Address: cooldown, Reason: p2p.DialSkipCooldown},
+		{Address: banned, Reason: p2p.DialSkipBanned},
+		{Address: backoff, Reason: p2p.DialSkipBackoff},
+	}, peerManager.LastDialSkips())
+
+	// Once the dial completes, the next cycle reports the peer as connected.
+	require.NoError(t, peerManager.Dialed(dialing))
+	require.Zero(t, peerManager.TryDialNext())
+	require.Contains(t, peerManager.LastDialSkips(),
+		p2p.DialSkip{Address: dialing, Reason: p2p.DialSkipConnected})
+}
+
+func TestPeerManager_TryDialNext_PersistentIgnoresOutgoingLimit(t *testing.T) {
+	p1 := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("1", 40))}
+	p2 := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("2", 40))}
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		PersistentPeers:        []types.NodeID{p1.NodeID, p2.NodeID},
+		MaxConnected:           10,
+		MaxOutgoingConnections: 1,
+	})
+	require.NoError(t, err)
+
+	for _, addr := range []p2p.NodeAddress{p1, a, b} {
+		added, err := peerManager.Add(addr)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	// The persistent peer is dialed first, and doesn't use up the outgoing
+	// connection.
+	require.Equal(t, p1, peerManager.TryDialNext())
+	require.NoError(t, peerManager.Dialed(p1))
+
+	dial := peerManager.TryDialNext()
+	require.Contains(t, []p2p.NodeAddress{a, b}, dial)
+	require.NoError(t, peerManager.Dialed(dial))
+	require.True(t, peerManager.HasDialedMaxPeers())
+
+	// Other peers aren't dialed once the outgoing connection is used up, but
+	// persistent peers still are.
+	require.Zero(t, peerManager.TryDialNext())
+	other := a
+	if dial == a {
+		other = b
+	}
+	require.Contains(t, peerManager.LastDialSkips(),
+		p2p.DialSkip{Address: other, Reason: p2p.DialSkipOutgoingFull})
+
+	added, err := peerManager.Add(p2)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.Equal(t, p2, peerManager.TryDialNext())
+}
+
+func TestPeerManager_LastDialSkips_Reset(t *testing.T) {
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		MaxConnected: 2,
+	})
+	require.NoError(t, err)
+	for _, address := range []p2p.NodeAddress{a, b} {
+		added, err := peerManager.Add(address)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	dialed := peerManager.TryDialNext()
+	require.NoError(t, peerManager.Dialed(dialed))
+	require.NotZero(t, peerManager.TryDialNext())
+	require.Equal(t, []p2p.DialSkip{{Address: dialed, Reason: p2p.DialSkipConnected}}, peerManager.LastDialSkips())
+
+	// A cycle that returns early because we're full doesn't keep the skips of
+	// the previous one.
+	require.Zero(t, peerManager.TryDialNext())
+	require.Empty(t, peerManager.LastDialSkips())
+}
+
+func TestPeerManager_LastDialSkips_Filtered(t *testing.T) {
+	a := p2p.NodeAddress{Protocol: "tcp", NodeID: types.NodeID(strings.Repeat("a", 40)), Hostname: "10.0.0.1", Port: 26656}
+	db := dbm.NewMemDB()
+
+	peerManager, err := p2p.NewPeerManager(selfID, db, p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	added, err := peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// An address stored before its IP was denied is not dialed.
+	filter, err := p2p.NewIPFilter(nil, []string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	peerManager, err = p2p.NewPeerManager(selfID, db, p2p.PeerManagerOptions{IPFilter: filter})
+	require.NoError(t, err)
+	require.Zero(t, peerManager.TryDialNext())
+	require.Equal(t, []p2p.DialSkip{{Address: a, Reason: p2p.DialSkipFiltered}}, peerManager.LastDialSkips())
+}
+
 func TestPeerManager_DialFailed(t *testing.T) {
 	// DialFailed is tested through other tests, we'll just check a few basic
 	// things here, e.g. reporting unknown addresses.
@@ -742,6 +1078,314 @@ func TestPeerManager_DialFailed(t *testing.T) {
 	require.Equal(t, []types.NodeID{aID}, peerManager.Peers())
 }
 
+func TestPeerManager_DialCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		MaxConnected:           2,
+		MaxOutgoingConnections: 1,
+		MinRetryTime:           time.Hour,
+	})
+	require.NoError(t, err)
+
+	for _, addr := range []p2p.NodeAddress{a, b} {
+		added, err := peerManager.Add(addr)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	require.False(t, peerManager.WantsDial(a.NodeID))
+
+	// Both peers are dialed, and both dials are wanted until one of them
+	// uses up the outgoing connections.
+	dials := []p2p.NodeAddress{peerManager.TryDialNext(), peerManager.TryDialNext()}
+	require.ElementsMatch(t, []p2p.NodeAddress{a, b}, dials)
+	require.True(t, peerManager.WantsDial(a.NodeID))
+	require.True(t, peerManager.WantsDial(b.NodeID))
+
+	require.NoError(t, peerManager.Dialed(dials[0]))
+	require.False(t, peerManager.WantsDial(dials[1].NodeID))
+
+	// Canceling the dial doesn't count as a failure, so the peer can be
+	// dialed again right away once the outgoing connection is freed up.
+	peerManager.DialCanceled(dials[1])
+	require.Zero(t, peerManager.TryDialNext())
+	peerManager.Disconnected(ctx, dials[0].NodeID)
+	redials := []p2p.NodeAddress{peerManager.TryDialNext(), peerManager.TryDialNext()}
+	require.ElementsMatch(t, []p2p.NodeAddress{a, b}, redials)
+}
+
+func TestPeerManager_NeverConnected(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+	c := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("c", 40))}
+	d := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("d", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+
+	// c is dialed successfully, so it must be added and dialed before the
+	// other peers are added.
+	added, err := peerManager.Add(c)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.Equal(t, c, peerManager.TryDialNext())
+	require.NoError(t, peerManager.Dialed(c))
+	peerManager.Disconnected(ctx, c.NodeID)
+	require.NoError(t, peerManager.DialFailed(ctx, c))
+
+	for _, address := range []p2p.NodeAddress{a, b, d} {
+		added, err := peerManager.Add(address)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	require.Empty(t, peerManager.NeverConnected())
+
+	// a fails to dial twice, and has never been connected.
+	require.NoError(t, peerManager.DialFailed(ctx, a))
+	require.NoError(t, peerManager.DialFailed(ctx, a))
+
+	// b fails to dial, but has connected to us.
+	require.NoError(t, peerManager.DialFailed(ctx, b))
+	require.NoError(t, peerManager.Accepted(b.NodeID))
+
+	// d has never been dialed, so it hasn't failed either.
+	neverConnected := peerManager.NeverConnected()
+	require.Len(t, neverConnected, 1)
+	require.Equal(t, a, neverConnected[0].Address)
+	require.EqualValues(t, 2, neverConnected[0].DialFailures)
+	require.False(t, neverConnected[0].LastDialFailure.IsZero())
+}
+
+func TestPeerManager_KnownAddresses(t *testing.T) {
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		PersistentPeers: []types.NodeID{a.NodeID},
+	})
+	require.NoError(t, err)
+
+	for _, address := range []p2p.NodeAddress{a, b} {
+		added, err := peerManager.Add(address)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	require.NoError(t, peerManager.Accepted(b.NodeID))
+
+	// The persistent peer is ranked first.
+	known := peerManager.KnownAddresses()
+	require.Len(t, known, 2)
+	require.Equal(t, a, known[0].Address)
+	require.True(t, known[0].Persistent)
+	require.False(t, known[0].Connected)
+	require.Equal(t, b, known[1].Address)
+	require.False(t, known[1].Persistent)
+	require.True(t, known[1].Connected)
+}
+
+func TestPeerManager_RemoveAddress(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	aID := types.NodeID(strings.Repeat("a", 40))
+	a1 := p2p.NodeAddress{Protocol: "tcp", NodeID: aID, Hostname: "127.0.0.1", Port: 26656}
+	a2 := p2p.NodeAddress{Protocol: "tcp", NodeID: aID, Hostname: "127.0.0.2", Port: 26656}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+	c := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("c", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		PersistentPeers: []types.NodeID{c.NodeID},
+	})
+	require.NoError(t, err)
+
+	for _, address := range []p2p.NodeAddress{a1, a2, b, c} {
+		added, err := peerManager.Add(address)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	// Removing an unknown address is a noop.
+	removed, err := peerManager.RemoveAddress(p2p.NodeAddress{Protocol: "memory", NodeID: aID})
+	require.NoError(t, err)
+	require.False(t, removed)
+
+	// Removing one of a's addresses keeps the other one.
+	removed, err = peerManager.RemoveAddress(a1)
+	require.NoError(t, err)
+	require.True(t, removed)
+	require.Equal(t, []p2p.NodeAddress{a2}, peerManager.Addresses(aID))
+
+	// Removing its last address removes the peer.
+	removed, err = peerManager.RemoveAddress(a2)
+	require.NoError(t, err)
+	require.True(t, removed)
+	require.NotContains(t, peerManager.Peers(), aID)
+
+	// A connected peer is kept even without addresses.
+	require.NoError(t, peerManager.Accepted(b.NodeID))
+	removed, err = peerManager.RemoveAddress(b)
+	require.NoError(t, err)
+	require.True(t, removed)
+	require.Contains(t, peerManager.Peers(), b.NodeID)
+	require.Empty(t, peerManager.Addresses(b.NodeID))
+	peerManager.Disconnected(ctx, b.NodeID)
+
+	// Persistent peers' addresses can't be removed.
+	_, err = peerManager.RemoveAddress(c)
+	require.Error(t, err)
+	require.Equal(t, []p2p.NodeAddress{c}, peerManager.Addresses(c.NodeID))
+}
+
+func TestPeerManager_RotateNodeKey(t *testing.T) {
+	a := p2p.NodeAddress{Protocol: "tcp", NodeID: types.NodeID(strings.Repeat("a", 40)), Hostname: "127.0.0.1", Port: 26656}
+	aNext := types.NodeID(strings.Repeat("b", 40))
+	c := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("c", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		PersistentPeers: []types.NodeID{a.NodeID},
+	})
+	require.NoError(t, err)
+	added, err := peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// Invalid rotations are rejected.
+	_, err = peerManager.RotateNodeKey(a.NodeID, a.NodeID)
+	require.Error(t, err)
+	_, err = peerManager.RotateNodeKey(a.NodeID, selfID)
+	require.Error(t, err)
+
+	// Unknown peers have no addresses to rotate.
+	added, err = peerManager.RotateNodeKey(c.NodeID, aNext)
+	require.NoError(t, err)
+	require.False(t, added)
+
+	// The next node ID gets the peer's addresses, and is persistent too.
+	added, err = peerManager.RotateNodeKey(a.NodeID, aNext)
+	require.NoError(t, err)
+	require.True(t, added)
+	aNextAddress := a
+	aNextAddress.NodeID = aNext
+	require.Equal(t, []p2p.NodeAddress{aNextAddress}, peerManager.Addresses(aNext))
+	require.Equal(t, p2p.PeerScorePersistent, peerManager.Scores()[aNext])
+
+	// Repeated announcements are noops.
+	added, err = peerManager.RotateNodeKey(a.NodeID, aNext)
+	require.NoError(t, err)
+	require.False(t, added)
+
+	// Once the peer connects with its next node ID, the previous one is
+	// removed.
+	require.NoError(t, peerManager.Dialed(aNextAddress))
+	require.NotContains(t, peerManager.Peers(), a.NodeID)
+	require.Contains(t, peerManager.Peers(), aNext)
+}
+
+func TestPeerManager_RotateNodeKey_RetiresPreviousID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	aNext := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		PersistentPeers: []types.NodeID{a.NodeID},
+	})
+	require.NoError(t, err)
+	added, err := peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+
+	_, err = peerManager.RotateNodeKey(a.NodeID, aNext.NodeID)
+	require.NoError(t, err)
+	require.Equal(t, p2p.PeerScorePersistent, peerManager.Scores()[aNext.NodeID])
+
+	// While the peer is still connected with its previous node ID, that ID
+	// is kept but is no longer persistent.
+	require.NoError(t, peerManager.Dialed(aNext))
+	require.Contains(t, peerManager.Peers(), a.NodeID)
+	require.NotEqual(t, p2p.PeerScorePersistent, peerManager.Scores()[a.NodeID])
+	require.Equal(t, p2p.PeerScorePersistent, peerManager.Scores()[aNext.NodeID])
+
+	// Once it disconnects, it's removed, and it's not persistent if added
+	// again.
+	peerManager.Disconnected(ctx, a.NodeID)
+	require.NotContains(t, peerManager.Peers(), a.NodeID)
+	added, err = peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NotEqual(t, p2p.PeerScorePersistent, peerManager.Scores()[a.NodeID])
+}
+
+func TestPeerManager_RotateNodeKey_Expires(t *testing.T) {
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+	c := types.NodeID(strings.Repeat("c", 40))
+	d := types.NodeID(strings.Repeat("d", 40))
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		PersistentPeers:        []types.NodeID{a.NodeID},
+		NodeKeyRotationTimeout: 100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	for _, address := range []p2p.NodeAddress{a, b} {
+		added, err := peerManager.Add(address)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	// a rotates to a node ID we don't know, and b to one we do.
+	_, err = peerManager.RotateNodeKey(a.NodeID, c)
+	require.NoError(t, err)
+	_, err = peerManager.RotateNodeKey(a.NodeID, b.NodeID)
+	require.NoError(t, err)
+	_, err = peerManager.RotateNodeKey(b.NodeID, d)
+	require.NoError(t, err)
+	require.Equal(t, p2p.PeerScorePersistent, peerManager.Scores()[b.NodeID])
+
+	// If the peers never connect with their next node IDs, the rotations are
+	// abandoned: c and d are forgotten, and b is no longer persistent.
+	time.Sleep(200 * time.Millisecond)
+	peerManager.TryDialNext()
+	require.ElementsMatch(t, []types.NodeID{a.NodeID, b.NodeID}, peerManager.Peers())
+	require.Equal(t, p2p.PeerScorePersistent, peerManager.Scores()[a.NodeID])
+	require.NotEqual(t, p2p.PeerScorePersistent, peerManager.Scores()[b.NodeID])
+}
+
+func TestPeerManager_RotateNodeKey_Rejected(t *testing.T) {
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := types.NodeID(strings.Repeat("b", 40))
+	c := types.NodeID(strings.Repeat("c", 40))
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		AllowedPeers: map[types.NodeID]struct{}{a.NodeID: {}, b: {}},
+	})
+	require.NoError(t, err)
+	added, err := peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// In permissioned mode, peers can't rotate to node IDs that aren't
+	// allowed.
+	_, err = peerManager.RotateNodeKey(a.NodeID, c)
+	require.Error(t, err)
+	require.NotContains(t, peerManager.Peers(), c)
+
+	// Nor can they rotate to banned node IDs.
+	require.NoError(t, peerManager.Ban(b, time.Hour))
+	_, err = peerManager.RotateNodeKey(a.NodeID, b)
+	require.Error(t, err)
+	require.Empty(t, peerManager.Addresses(b))
+}
+
 func TestPeerManager_DialFailed_UnreservePeer(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -1101,26 +1745,191 @@ func TestPeerManager_Accepted_MaxConnected(t *testing.T) {
 	c := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("c", 40))}
 
 	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
-		MaxConnected: 2,
+		MaxConnected: 2,
+	})
+	require.NoError(t, err)
+
+	// Connect to a and b.
+	added, err := peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, peerManager.Dialed(a))
+
+	added, err = peerManager.Add(b)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, peerManager.Accepted(b.NodeID))
+
+	// Accepting c should now fail.
+	added, err = peerManager.Add(c)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.Error(t, peerManager.Accepted(c.NodeID))
+}
+
+func TestPeerManager_Accepted_Unconditional(t *testing.T) {
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+	c := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("c", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		PeerScores:          map[types.NodeID]p2p.PeerScore{b.NodeID: 1},
+		UnconditionalPeers:  map[types.NodeID]struct{}{a.NodeID: {}},
+		MaxConnected:        1,
+		MaxConnectedUpgrade: 1,
+	})
+	require.NoError(t, err)
+
+	// Connect to c, filling up all connection slots.
+	added, err := peerManager.Add(c)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, peerManager.Accepted(c.NodeID))
+	require.True(t, peerManager.HasMaxPeerCapacity())
+
+	// Accepting a should work, since it's unconditional, and it shouldn't
+	// count towards MaxConnected or be marked as an upgrade.
+	added, err = peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+	require.True(t, peerManager.HasMaxPeerCapacity())
+	evict, err := peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Zero(t, evict)
+
+	// Accepting b upgrades c rather than the lower-scored unconditional a.
+	added, err = peerManager.Add(b)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, peerManager.Accepted(b.NodeID))
+	evict, err = peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Equal(t, c.NodeID, evict)
+}
+
+func TestPeerManager_Add_IPFilter(t *testing.T) {
+	id := types.NodeID(strings.Repeat("a", 40))
+	filter, err := p2p.NewIPFilter(nil, []string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		IPFilter: filter,
+	})
+	require.NoError(t, err)
+
+	_, err = peerManager.Add(p2p.NodeAddress{Protocol: "tcp", NodeID: id, Hostname: "10.0.0.1", Port: 26656})
+	require.Error(t, err)
+
+	added, err := peerManager.Add(p2p.NodeAddress{Protocol: "tcp", NodeID: id, Hostname: "192.168.0.1", Port: 26656})
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// Hostnames are filtered when they're resolved and dialed.
+	added, err = peerManager.Add(p2p.NodeAddress{Protocol: "tcp", NodeID: id, Hostname: "example.com", Port: 26656})
+	require.NoError(t, err)
+	require.True(t, added)
+}
+
+func TestPeerManager_AllowedPeers(t *testing.T) {
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+	db := dbm.NewMemDB()
+
+	// Peers stored before the allowlist was configured are forgotten.
+	peerManager, err := p2p.NewPeerManager(selfID, db, p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	for _, address := range []p2p.NodeAddress{a, b} {
+		added, err := peerManager.Add(address)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	peerManager, err = p2p.NewPeerManager(selfID, db, p2p.PeerManagerOptions{
+		AllowedPeers: map[types.NodeID]struct{}{a.NodeID: {}},
+	})
+	require.NoError(t, err)
+	require.True(t, peerManager.IsAllowed(a.NodeID))
+	require.False(t, peerManager.IsAllowed(b.NodeID))
+	require.ElementsMatch(t, []types.NodeID{a.NodeID}, peerManager.Peers())
+
+	// Other peers can't be added, dialed or accepted.
+	_, err = peerManager.Add(b)
+	require.Error(t, err)
+	require.Error(t, peerManager.Dialed(b))
+	require.Error(t, peerManager.Accepted(b.NodeID))
+
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+}
+
+func TestPeerManager_Accepted_MaxIncoming(t *testing.T) {
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+	c := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("c", 40))}
+	d := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("d", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		PersistentPeers:        []types.NodeID{c.NodeID},
+		MaxConnected:           3,
+		MaxIncomingConnections: 1,
+	})
+	require.NoError(t, err)
+
+	// Accepting a fills up the incoming connection slots.
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+	require.Error(t, peerManager.Accepted(b.NodeID))
+
+	// Persistent peers don't count towards the limit.
+	require.NoError(t, peerManager.Accepted(c.NodeID))
+
+	// Outgoing connections can still be made.
+	added, err := peerManager.Add(d)
+	require.NoError(t, err)
+	require.True(t, added)
+	dial := peerManager.TryDialNext()
+	require.Equal(t, d, dial)
+	require.NoError(t, peerManager.Dialed(d))
+
+	// Once a disconnects, b can connect.
+	peerManager.Disconnected(context.Background(), a.NodeID)
+	require.NoError(t, peerManager.Accepted(b.NodeID))
+}
+
+func TestPeerManager_Accepted_EvictIncoming(t *testing.T) {
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+	c := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("c", 40))}
+	d := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("d", 40))}
+	e := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("e", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		PersistentPeers:        []types.NodeID{a.NodeID},
+		MaxConnected:           4,
+		MaxIncomingConnections: 2,
+		EvictIncoming:          true,
+		PeerScores: map[types.NodeID]p2p.PeerScore{
+			b.NodeID: 1,
+			c.NodeID: 2,
+			d.NodeID: 3,
+			e.NodeID: 1,
+		},
 	})
 	require.NoError(t, err)
 
-	// Connect to a and b.
-	added, err := peerManager.Add(a)
-	require.NoError(t, err)
-	require.True(t, added)
-	require.NoError(t, peerManager.Dialed(a))
-
-	added, err = peerManager.Add(b)
-	require.NoError(t, err)
-	require.True(t, added)
+	// The persistent peer a doesn't count towards the limit, and isn't
+	// evicted.
+	require.NoError(t, peerManager.Accepted(a.NodeID))
 	require.NoError(t, peerManager.Accepted(b.NodeID))
+	require.NoError(t, peerManager.Accepted(c.NodeID))
 
-	// Accepting c should now fail.
-	added, err = peerManager.Add(c)
+	// e isn't scored higher than any incoming peer, so it's rejected.
+	require.Error(t, peerManager.Accepted(e.NodeID))
+
+	// d is scored higher than b, which is evicted to make room for it.
+	require.NoError(t, peerManager.Accepted(d.NodeID))
+	evict, err := peerManager.TryEvictNext()
 	require.NoError(t, err)
-	require.True(t, added)
-	require.Error(t, peerManager.Accepted(c.NodeID))
+	require.Equal(t, b.NodeID, evict)
 }
 
 func TestPeerManager_Accepted_MaxConnectedUpgrade(t *testing.T) {
@@ -1556,6 +2365,149 @@ func TestPeerManager_Disconnected(t *testing.T) {
 	require.Zero(t, dial)
 }
 
+func TestPeerManager_RemoteClosed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+
+	options := p2p.PeerManagerOptions{
+		RemoteBanWindow:  time.Minute,
+		RemoteBanBackoff: 500 * time.Millisecond,
+	}
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), options)
+	require.NoError(t, err)
+
+	// Reporting a remote close for an unknown peer does nothing.
+	peerManager.RemoteClosed(ctx, a.NodeID)
+	require.Empty(t, peerManager.Peers())
+
+	added, err := peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// The peer hangs up on us right after we connect, so it is assumed to
+	// have banned us and we shouldn't redial it until the backoff elapses.
+	dial := peerManager.TryDialNext()
+	require.Equal(t, a, dial)
+	require.NoError(t, peerManager.Dialed(a))
+	peerManager.Ready(ctx, a.NodeID, nil)
+
+	start := time.Now()
+	peerManager.RemoteClosed(ctx, a.NodeID)
+	peerManager.Disconnected(ctx, a.NodeID)
+	require.Zero(t, peerManager.TryDialNext())
+
+	dialCtx, dialCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer dialCancel()
+	dial, err = peerManager.DialNext(dialCtx)
+	require.NoError(t, err)
+	require.Equal(t, a, dial)
+	require.GreaterOrEqual(t, time.Since(start), options.RemoteBanBackoff)
+
+	// If we evict the peer ourselves, the disconnect isn't a ban.
+	require.NoError(t, peerManager.Dialed(a))
+	peerManager.Ready(ctx, a.NodeID, nil)
+	peerManager.Errored(a.NodeID, errors.New("foo"))
+	evict, err := peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Equal(t, a.NodeID, evict)
+	peerManager.RemoteClosed(ctx, a.NodeID)
+	peerManager.Disconnected(ctx, a.NodeID)
+	require.Equal(t, a, peerManager.TryDialNext())
+}
+
+func TestPeerManager_RemoteDisconnected(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		RemoteBanWindow:  100 * time.Millisecond,
+		RemoteBanBackoff: time.Hour,
+	})
+	require.NoError(t, err)
+
+	added, err := peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// A peer that says it's full right after we connect hasn't banned us.
+	require.Equal(t, a, peerManager.TryDialNext())
+	require.NoError(t, peerManager.Dialed(a))
+	peerManager.Ready(ctx, a.NodeID, nil)
+	peerManager.RemoteDisconnected(ctx, a.NodeID, p2pproto.DisconnectPeerLimit)
+	peerManager.Disconnected(ctx, a.NodeID)
+	require.Equal(t, a, peerManager.TryDialNext())
+
+	// A peer that says it banned us is backed off, even after a long-lived
+	// connection.
+	require.NoError(t, peerManager.Dialed(a))
+	peerManager.Ready(ctx, a.NodeID, nil)
+	time.Sleep(200 * time.Millisecond)
+	peerManager.RemoteDisconnected(ctx, a.NodeID, p2pproto.DisconnectBanned)
+	peerManager.Disconnected(ctx, a.NodeID)
+	require.Zero(t, peerManager.TryDialNext())
+}
+
+func TestPeerManager_EvictReason(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+
+	added, err := peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.Equal(t, a, peerManager.TryDialNext())
+	require.NoError(t, peerManager.Dialed(a))
+	peerManager.Ready(ctx, a.NodeID, nil)
+	require.Equal(t, p2pproto.DisconnectUnknown, peerManager.EvictReason(a.NodeID))
+
+	// Peers evicted for errors are given a protocol error.
+	peerManager.Errored(a.NodeID, errors.New("foo"))
+	evict, err := peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Equal(t, a.NodeID, evict)
+	require.Equal(t, p2pproto.DisconnectProtocolError, peerManager.EvictReason(a.NodeID))
+
+	// The reason is forgotten once the peer disconnects.
+	peerManager.Disconnected(ctx, a.NodeID)
+	require.Equal(t, p2pproto.DisconnectUnknown, peerManager.EvictReason(a.NodeID))
+}
+
+func TestPeerManager_RemoteClosed_Window(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		RemoteBanWindow:  100 * time.Millisecond,
+		RemoteBanBackoff: time.Hour,
+	})
+	require.NoError(t, err)
+
+	added, err := peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// A peer that disconnects after a long-lived connection is not
+	// considered to have banned us.
+	require.Equal(t, a, peerManager.TryDialNext())
+	require.NoError(t, peerManager.Dialed(a))
+	peerManager.Ready(ctx, a.NodeID, nil)
+	time.Sleep(200 * time.Millisecond)
+
+	peerManager.RemoteClosed(ctx, a.NodeID)
+	peerManager.Disconnected(ctx, a.NodeID)
+	require.Equal(t, a, peerManager.TryDialNext())
+}
+
 func TestPeerManager_Errored(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -1595,6 +2547,216 @@ func TestPeerManager_Errored(t *testing.T) {
 	require.Equal(t, a.NodeID, evict)
 }
 
+func TestPeerManager_Ban(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+	c := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("c", 40))}
+
+	db := dbm.NewMemDB()
+	peerManager, err := p2p.NewPeerManager(selfID, db, p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+
+	for _, addr := range []p2p.NodeAddress{a, b, c} {
+		added, err := peerManager.Add(addr)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	// Banning a connected peer evicts it.
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+	peerManager.Ready(ctx, a.NodeID, nil)
+	require.NoError(t, peerManager.Ban(a.NodeID, time.Hour))
+	require.True(t, peerManager.IsBanned(a.NodeID))
+	evict, err := peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Equal(t, a.NodeID, evict)
+	peerManager.Disconnected(ctx, a.NodeID)
+
+	// Banned peers are neither accepted, dialed nor advertised. An expired ban
+	// has no effect.
+	require.NoError(t, peerManager.Ban(b.NodeID, time.Hour))
+	require.NoError(t, peerManager.Ban(c.NodeID, -time.Second))
+	require.False(t, peerManager.IsBanned(c.NodeID))
+	require.Error(t, peerManager.Accepted(b.NodeID))
+	require.ElementsMatch(t, []p2p.NodeAddress{c}, peerManager.Advertise(selfID, 10))
+	require.Equal(t, c, peerManager.TryDialNext())
+	require.Zero(t, peerManager.TryDialNext())
+	require.Contains(t, peerManager.LastDialSkips(), p2p.DialSkip{Address: b, Reason: p2p.DialSkipBanList})
+
+	// Bans are persisted.
+	peerManager, err = p2p.NewPeerManager(selfID, db, p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	require.True(t, peerManager.IsBanned(a.NodeID))
+	require.True(t, peerManager.IsBanned(b.NodeID))
+	require.False(t, peerManager.IsBanned(c.NodeID))
+}
+
+func TestPeerManager_PeerEventScore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	for _, addr := range []p2p.NodeAddress{a, b} {
+		added, err := peerManager.Add(addr)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	require.Zero(t, peerManager.Score(a.NodeID))
+	require.Zero(t, peerManager.Score(b.NodeID))
+	require.Len(t, peerManager.Peers(), 2) // ranks the peers
+
+	// Reporting peers as good or bad changes their score, and thus the order
+	// in which they are dialed.
+	sub := peerManager.Subscribe(ctx)
+	sub.SendUpdate(ctx, p2p.PeerUpdate{NodeID: a.NodeID, Status: p2p.PeerStatusBad})
+	sub.SendUpdate(ctx, p2p.PeerUpdate{NodeID: b.NodeID, Status: p2p.PeerStatusGood})
+	require.Eventually(t, func() bool {
+		return peerManager.Score(a.NodeID) == -1 && peerManager.Score(b.NodeID) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	require.Equal(t, b, peerManager.TryDialNext())
+	require.Equal(t, a, peerManager.TryDialNext())
+}
+
+func TestPeerManager_ProtocolVersion(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	version := types.ProtocolVersion{P2P: 8, Block: 11, App: 1}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	sub := peerManager.Subscribe(ctx)
+
+	// The version isn't recorded for peers that aren't connected.
+	peerManager.SetProtocolVersion(a.NodeID, version)
+	_, ok := peerManager.ProtocolVersion(a.NodeID)
+	require.False(t, ok)
+
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+	peerManager.SetProtocolVersion(a.NodeID, version)
+	v, ok := peerManager.ProtocolVersion(a.NodeID)
+	require.True(t, ok)
+	require.Equal(t, version, v)
+
+	peerManager.Ready(ctx, a.NodeID, nil)
+	require.Equal(t, p2p.PeerUpdate{
+		NodeID:          a.NodeID,
+		Status:          p2p.PeerStatusUp,
+		ProtocolVersion: version,
+	}, <-sub.Updates())
+
+	peerManager.Disconnected(ctx, a.NodeID)
+	_, ok = peerManager.ProtocolVersion(a.NodeID)
+	require.False(t, ok)
+}
+
+func TestPeerManager_DialImmediately(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		MinRetryTime: time.Hour,
+	})
+	require.NoError(t, err)
+	added, err := peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	require.Equal(t, a, peerManager.TryDialNext())
+	require.NoError(t, peerManager.DialFailed(ctx, a))
+	require.Zero(t, peerManager.TryDialNext())
+
+	// Unknown peers are ignored.
+	require.NoError(t, peerManager.DialImmediately(types.NodeID(strings.Repeat("b", 40))))
+
+	require.NoError(t, peerManager.DialImmediately(a.NodeID))
+	require.Equal(t, a, peerManager.TryDialNext())
+}
+
+func TestPeerManager_Redial(t *testing.T) {
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		PersistentPeers: []types.NodeID{a.NodeID},
+	})
+	require.NoError(t, err)
+	added, err := peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// Redialing a peer that isn't connected does nothing.
+	peerManager.Redial(a.NodeID)
+	evict, err := peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Zero(t, evict)
+
+	// A connected peer is evicted, without penalizing it, and can be dialed
+	// again once disconnected.
+	require.Equal(t, a, peerManager.TryDialNext())
+	require.NoError(t, peerManager.Dialed(a))
+	peerManager.Redial(a.NodeID)
+	evict, err = peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Equal(t, a.NodeID, evict)
+	require.Equal(t, p2pproto.DisconnectUnknown, peerManager.EvictReason(a.NodeID))
+
+	peerManager.Disconnected(context.Background(), a.NodeID)
+	require.False(t, peerManager.IsBanned(a.NodeID))
+	require.Equal(t, a, peerManager.TryDialNext())
+}
+
+func TestPeerManager_Unresponsive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := p2p.NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	sub := peerManager.Subscribe(ctx)
+	added, err := peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// Reporting a peer that isn't connected does nothing.
+	peerManager.Unresponsive(a.NodeID)
+	require.EqualValues(t, 0, peerManager.Score(a.NodeID))
+
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+	peerManager.Ready(ctx, a.NodeID, nil)
+	require.Equal(t, p2p.PeerStatusUp, (<-sub.Updates()).Status)
+
+	// An unresponsive peer loses score, and its disconnection is flagged.
+	peerManager.Unresponsive(a.NodeID)
+	peerManager.Unresponsive(a.NodeID)
+	require.EqualValues(t, -1, peerManager.Score(a.NodeID))
+
+	peerManager.Disconnected(ctx, a.NodeID)
+	require.Equal(t, p2p.PeerUpdate{
+		NodeID:       a.NodeID,
+		Status:       p2p.PeerStatusDown,
+		Unresponsive: true,
+	}, <-sub.Updates())
+
+	// The flag doesn't carry over to the next connection.
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+	peerManager.Ready(ctx, a.NodeID, nil)
+	require.Equal(t, p2p.PeerStatusUp, (<-sub.Updates()).Status)
+	peerManager.Disconnected(ctx, a.NodeID)
+	require.Equal(t, p2p.PeerUpdate{NodeID: a.NodeID, Status: p2p.PeerStatusDown}, <-sub.Updates())
+}
+
 func TestPeerManager_Subscribe(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -1842,3 +3004,19 @@ func TestPeerManager_Advertise_Self(t *testing.T) {
 		self,
 	}, peerManager.Advertise(dID, 100))
 }
+
+func TestPeerManager_Advertise_SetSelfAddress(t *testing.T) {
+	dID := types.NodeID(strings.Repeat("d", 40))
+
+	// Without an external address, nothing is advertised for us.
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	require.Empty(t, peerManager.Advertise(dID, 100))
+
+	// Once it's been mapped, e.g. on a NAT gateway, it's advertised.
+	mapped := p2p.NodeAddress{Protocol: "mconn", NodeID: selfID, Hostname: "203.0.113.1", Port: 26656}
+	peerManager.SetSelfAddress(mapped)
+	require.ElementsMatch(t, []p2p.NodeAddress{
+		mapped,
+	}, peerManager.Advertise(dID, 100))
+}
@@ -0,0 +1,12 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package pex
+
+import "time"
+
+// processCPUTime returns the CPU time used by the process so far. It isn't
+// measured on this platform, so the CPU never triggers throttling.
+func processCPUTime() time.Duration {
+	return 0
+}
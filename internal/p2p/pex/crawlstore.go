@@ -0,0 +1,109 @@
+package pex
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/google/orderedcode"
+	dbm "github.com/tendermint/tm-db"
+
+	protop2p "github.com/tendermint/tendermint/proto/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+// prefixCrawlStatus is the database key prefix of crawl status records.
+const prefixCrawlStatus int64 = 1
+
+// crawlStore persists the crawl status of peers, so that a seed remembers
+// which peers are worth handing out across restarts.
+type crawlStore struct {
+	db dbm.DB
+}
+
+// load returns all persisted crawl status records.
+func (s crawlStore) load() (map[types.NodeID]CrawlStatus, error) {
+	start, end := keyCrawlStatusRange()
+	iter, err := s.db.Iterator(start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	records := map[types.NodeID]CrawlStatus{}
+	for ; iter.Valid(); iter.Next() {
+		msg := new(protop2p.PeerCrawlRecord)
+		if err := proto.Unmarshal(iter.Value(), msg); err != nil {
+			return nil, fmt.Errorf("invalid crawl status Protobuf data: %w", err)
+		}
+		id := types.NodeID(msg.ID)
+		if err := id.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid crawl status peer ID %q: %w", msg.ID, err)
+		}
+		records[id] = crawlStatusFromProto(msg)
+	}
+	return records, iter.Error()
+}
+
+// set persists the crawl status of a peer.
+func (s crawlStore) set(id types.NodeID, status CrawlStatus) error {
+	bz, err := status.toProto(id).Marshal()
+	if err != nil {
+		return err
+	}
+	return s.db.Set(keyCrawlStatus(id), bz)
+}
+
+// delete removes the crawl status of a peer.
+func (s crawlStore) delete(id types.NodeID) error {
+	return s.db.Delete(keyCrawlStatus(id))
+}
+
+// toProto converts the crawl status of a peer to Protobuf.
+func (s CrawlStatus) toProto(id types.NodeID) *protop2p.PeerCrawlRecord {
+	return &protop2p.PeerCrawlRecord{
+		ID:                  string(id),
+		LastCrawled:         s.LastCrawled,
+		LastFailed:          s.LastFailed,
+		Crawls:              uint64(s.Crawls),
+		ConsecutiveFailures: uint32(s.ConsecutiveFailures),
+		Addresses:           uint32(s.Addresses),
+		Moniker:             s.Moniker,
+		Version:             s.Version,
+	}
+}
+
+// crawlStatusFromProto converts a Protobuf crawl status record.
+func crawlStatusFromProto(msg *protop2p.PeerCrawlRecord) CrawlStatus {
+	return CrawlStatus{
+		LastCrawled:         msg.LastCrawled,
+		LastFailed:          msg.LastFailed,
+		Crawls:              int(msg.Crawls),
+		ConsecutiveFailures: int(msg.ConsecutiveFailures),
+		Addresses:           int(msg.Addresses),
+		Moniker:             msg.Moniker,
+		Version:             msg.Version,
+	}
+}
+
+// keyCrawlStatus generates a crawl status database key.
+func keyCrawlStatus(id types.NodeID) []byte {
+	key, err := orderedcode.Append(nil, prefixCrawlStatus, string(id))
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// keyCrawlStatusRange generates start/end keys for the entire crawl status
+// key range.
+func keyCrawlStatusRange() ([]byte, []byte) {
+	start, err := orderedcode.Append(nil, prefixCrawlStatus, "")
+	if err != nil {
+		panic(err)
+	}
+	end, err := orderedcode.Append(nil, prefixCrawlStatus, orderedcode.Infinity)
+	if err != nil {
+		panic(err)
+	}
+	return start, end
+}
@@ -0,0 +1,126 @@
+package pex
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+// newSubscribedTestReactor returns a started reactor that receives peer
+// updates from a peer manager subscription, like it does in a node.
+func newSubscribedTestReactor(ctx context.Context, t *testing.T, opts ...ReactorOption) (*Reactor, *p2p.PeerManager) {
+	t.Helper()
+	peerManager, err := p2p.NewPeerManager(types.NodeID(strings.Repeat("0", 40)), dbm.NewMemDB(),
+		p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+
+	chDesc := ChannelDescriptor()
+	pexCh := p2p.NewChannel(chDesc.ID, chDesc.Name,
+		make(chan p2p.Envelope), make(chan p2p.Envelope, 10), make(chan p2p.PeerError, 10))
+	chCreator := func(context.Context, *p2p.ChannelDescriptor) (p2p.Channel, error) {
+		return pexCh, nil
+	}
+
+	r := NewReactor(log.NewNopLogger(), peerManager, chCreator, peerManager.Subscribe, opts...)
+	require.NoError(t, r.Start(ctx))
+	t.Cleanup(r.Wait)
+	return r, peerManager
+}
+
+// connectPeer connects a peer in the peer manager, which broadcasts it as up.
+func connectPeer(ctx context.Context, peerManager *p2p.PeerManager, peerID types.NodeID) error {
+	if err := peerManager.Accepted(peerID); err != nil {
+		return err
+	}
+	peerManager.Ready(ctx, peerID, nil)
+	return nil
+}
+
+func TestReactorDialsSeedsWhileBroadcasting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	seed := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: types.NodeID(strings.Repeat("5", 40))}
+	r, peerManager := newSubscribedTestReactor(ctx, t, WithSeeds(seed.NodeID))
+	_, err := peerManager.Add(seed)
+	require.NoError(t, err)
+
+	a := types.NodeID(strings.Repeat("a", 40))
+	require.NoError(t, connectPeer(ctx, peerManager, a))
+	require.Eventually(t, func() bool {
+		r.mtx.RLock()
+		defer r.mtx.RUnlock()
+		return len(r.availablePeers)+len(r.requestsSent) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// Stall the reactor while the last peer goes down and two more peers come
+	// up, such that the peer manager is left broadcasting the second one while
+	// holding its lock, and the reactor then dials the seeds.
+	r.mtx.Lock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		peerManager.Disconnected(ctx, a)
+		assert.NoError(t, connectPeer(ctx, peerManager, types.NodeID(strings.Repeat("b", 40))))
+		assert.NoError(t, connectPeer(ctx, peerManager, types.NodeID(strings.Repeat("c", 40))))
+	}()
+	time.Sleep(100 * time.Millisecond)
+	r.mtx.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "peer manager deadlocked with the PEX reactor")
+	}
+	require.Eventually(t, func() bool {
+		r.mtx.RLock()
+		defer r.mtx.RUnlock()
+		return len(r.availablePeers)+len(r.requestsSent) == 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestReactorSeedModeRecordsCrawlWhileBroadcasting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r, peerManager := newSubscribedTestReactor(ctx, t, WithSeedMode())
+
+	// The reactor requests addresses from the peer as soon as it's up.
+	a := types.NodeID(strings.Repeat("a", 40))
+	require.NoError(t, connectPeer(ctx, peerManager, a))
+	require.Eventually(t, func() bool {
+		r.mtx.RLock()
+		defer r.mtx.RUnlock()
+		_, ok := r.requestsSent[a]
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	// The peer goes down before responding, failing its crawl, while two
+	// more peers come up.
+	r.mtx.Lock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		peerManager.Disconnected(ctx, a)
+		assert.NoError(t, connectPeer(ctx, peerManager, types.NodeID(strings.Repeat("b", 40))))
+		assert.NoError(t, connectPeer(ctx, peerManager, types.NodeID(strings.Repeat("c", 40))))
+	}()
+	time.Sleep(100 * time.Millisecond)
+	r.mtx.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "peer manager deadlocked with the PEX reactor")
+	}
+	require.Equal(t, 1, r.CrawlStatus()[a].ConsecutiveFailures)
+}
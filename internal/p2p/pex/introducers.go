@@ -0,0 +1,93 @@
+package pex
+
+import (
+	"sort"
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// the connection age at which a peer introduced via PEX counts as
+	// retained, for IntroducerQuality
+	retainedPeerAge = 10 * time.Minute
+
+	// the maximum number of introduced peers to track
+	maxTrackedIntroductions = 10000
+)
+
+// IntroducerQuality summarizes the quality of the peers that a single peer,
+// typically a seed, introduced to us via PEX.
+type IntroducerQuality struct {
+	Introducer types.NodeID
+	Introduced int // peers first learned about from the introducer
+	Connected  int // introduced peers we have connected to at some point
+	Retained   int // introduced peers that stayed connected for retainedPeerAge
+}
+
+// introduction tracks a peer that was introduced to us via PEX.
+type introduction struct {
+	introducer  types.NodeID
+	connected   bool      // whether we have ever connected to the peer
+	connectedAt time.Time // when the current connection started, if any
+	retained    bool      // whether a past connection lasted retainedPeerAge
+}
+
+// introductions follows peers from the peer that introduced them to whether we
+// end up connecting to them and staying connected. It is not thread-safe.
+type introductions map[types.NodeID]*introduction
+
+// introduce records that a peer was first learned about from introducer.
+func (in introductions) introduce(introducer, peerID types.NodeID) {
+	if _, ok := in[peerID]; ok || len(in) >= maxTrackedIntroductions {
+		return
+	}
+	in[peerID] = &introduction{introducer: introducer}
+}
+
+// up records that a connection to a peer was established.
+func (in introductions) up(peerID types.NodeID, now time.Time) {
+	if i, ok := in[peerID]; ok {
+		i.connected = true
+		i.connectedAt = now
+	}
+}
+
+// down records that the connection to a peer was closed.
+func (in introductions) down(peerID types.NodeID, now time.Time) {
+	if i, ok := in[peerID]; ok && !i.connectedAt.IsZero() {
+		if now.Sub(i.connectedAt) >= retainedPeerAge {
+			i.retained = true
+		}
+		i.connectedAt = time.Time{}
+	}
+}
+
+// quality returns the quality of each introducer's peers, ordered by
+// introducer ID.
+func (in introductions) quality(now time.Time) []IntroducerQuality {
+	byIntroducer := map[types.NodeID]*IntroducerQuality{}
+	for _, i := range in {
+		q, ok := byIntroducer[i.introducer]
+		if !ok {
+			q = &IntroducerQuality{Introducer: i.introducer}
+			byIntroducer[i.introducer] = q
+		}
+		q.Introduced++
+		if i.connected {
+			q.Connected++
+		}
+		if i.retained || (!i.connectedAt.IsZero() && now.Sub(i.connectedAt) >= retainedPeerAge) {
+			q.Retained++
+		}
+	}
+
+	quality := make([]IntroducerQuality, 0, len(byIntroducer))
+	for _, q := range byIntroducer {
+		quality = append(quality, *q)
+	}
+	sort.Slice(quality, func(i, j int) bool {
+		return quality[i].Introducer < quality[j].Introducer
+	})
+	return quality
+}
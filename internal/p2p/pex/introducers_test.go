@@ -0,0 +1,53 @@
+package pex
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestIntroductions(t *testing.T) {
+	in := introductions{}
+	now := time.Now()
+
+	peer := func(seed string, i int) types.NodeID {
+		return types.NodeID(fmt.Sprintf("%s-%d", seed, i))
+	}
+
+	// good introduces peers which we connect to and keep, except for one
+	// which we never connect to.
+	for i := 0; i < 4; i++ {
+		in.introduce("good", peer("good", i))
+	}
+	for i := 0; i < 3; i++ {
+		in.up(peer("good", i), now)
+	}
+	in.down(peer("good", 0), now.Add(retainedPeerAge))
+
+	// bad introduces peers which we only connect to briefly, if at all.
+	for i := 0; i < 4; i++ {
+		in.introduce("bad", peer("bad", i))
+	}
+	in.up(peer("bad", 0), now)
+	in.down(peer("bad", 0), now.Add(time.Second))
+	in.up(peer("bad", 1), now.Add(retainedPeerAge))
+
+	// a peer is attributed to the first introducer only
+	in.introduce("bad", peer("good", 3))
+
+	require.Equal(t, []IntroducerQuality{
+		{Introducer: "bad", Introduced: 4, Connected: 2, Retained: 0},
+		{Introducer: "good", Introduced: 4, Connected: 3, Retained: 3},
+	}, in.quality(now.Add(retainedPeerAge)))
+
+	// peers that are still connected only count as retained once they reach
+	// the retention age
+	require.Equal(t, []IntroducerQuality{
+		{Introducer: "bad", Introduced: 4, Connected: 2, Retained: 0},
+		{Introducer: "good", Introduced: 4, Connected: 3, Retained: 1},
+	}, in.quality(now.Add(time.Minute)))
+}
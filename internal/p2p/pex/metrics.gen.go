@@ -0,0 +1,51 @@
+// Code generated by metricsgen. DO NOT EDIT.
+
+package pex
+
+import (
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		PeersByAge: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peers_by_age",
+			Help:      "Number of connected peers by connection age, bucketed by the upper bound of their age.",
+		}, append(labels, "max_age")).With(labelsAndValues...),
+		Throttled: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "throttled",
+			Help:      "Whether incoming PEX requests are currently being throttled (1) or not (0).",
+		}, labels).With(labelsAndValues...),
+		PeerMisbehavior: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peer_misbehavior",
+			Help:      "Number of peers banned for sending malformed, invalid or unsolicited PEX messages.",
+		}, append(labels, "reason")).With(labelsAndValues...),
+		MessagesReceived: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "messages_received",
+			Help:      "Number of PEX messages received from peers, by message type.",
+		}, append(labels, "message_type")).With(labelsAndValues...),
+	}
+}
+
+func NopMetrics() *Metrics {
+	return &Metrics{
+		PeersByAge:       discard.NewGauge(),
+		Throttled:        discard.NewGauge(),
+		PeerMisbehavior:  discard.NewCounter(),
+		MessagesReceived: discard.NewCounter(),
+	}
+}
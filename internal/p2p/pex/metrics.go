@@ -0,0 +1,31 @@
+package pex
+
+import (
+	"github.com/go-kit/kit/metrics"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "pex"
+)
+
+//go:generate go run ../../../scripts/metricsgen -struct=Metrics
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of connected peers by connection age, bucketed by the
+	// upper bound of their age.
+	PeersByAge metrics.Gauge `metrics_labels:"max_age"`
+
+	// Whether incoming PEX requests are currently being throttled (1) or
+	// not (0).
+	Throttled metrics.Gauge
+
+	// Number of peers banned for sending malformed, invalid or unsolicited
+	// PEX messages.
+	PeerMisbehavior metrics.Counter `metrics_labels:"reason"`
+
+	// Number of PEX messages received from peers, by message type.
+	MessagesReceived metrics.Counter `metrics_labels:"message_type"`
+}
@@ -0,0 +1,50 @@
+package pex
+
+import (
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// peerAgeBuckets are the upper bounds of the buckets used by
+// PeerAgeHistogram. Peers older than the last bound fall into a final,
+// unbounded bucket.
+var peerAgeBuckets = []time.Duration{
+	time.Minute,
+	10 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+	24 * time.Hour,
+}
+
+// PeerAgeBucket counts the connected peers whose connection age is less
+// than MaxAge, but at least the MaxAge of the previous bucket. The last
+// bucket has a MaxAge of 0 and counts all peers older than that.
+type PeerAgeBucket struct {
+	MaxAge time.Duration
+	Count  int
+}
+
+func (b PeerAgeBucket) label() string {
+	if b.MaxAge == 0 {
+		return "+Inf"
+	}
+	return b.MaxAge.String()
+}
+
+// bucketPeerAges sorts peer connection ages into the peerAgeBuckets.
+func bucketPeerAges(ages map[types.NodeID]time.Duration) []PeerAgeBucket {
+	hist := make([]PeerAgeBucket, len(peerAgeBuckets)+1)
+	for i, maxAge := range peerAgeBuckets {
+		hist[i].MaxAge = maxAge
+	}
+
+	for _, age := range ages {
+		i := 0
+		for i < len(peerAgeBuckets) && age >= peerAgeBuckets[i] {
+			i++
+		}
+		hist[i].Count++
+	}
+	return hist
+}
@@ -0,0 +1,42 @@
+package pex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestBucketPeerAges(t *testing.T) {
+	ages := map[types.NodeID]time.Duration{
+		"a": 0,
+		"b": 30 * time.Second,
+		"c": time.Minute, // bucket bounds are exclusive
+		"d": 45 * time.Minute,
+		"e": 59 * time.Minute,
+		"f": 5 * time.Hour,
+		"g": 30 * time.Hour,
+		"h": 1000 * time.Hour,
+	}
+
+	require.Equal(t, []PeerAgeBucket{
+		{MaxAge: time.Minute, Count: 2},
+		{MaxAge: 10 * time.Minute, Count: 1},
+		{MaxAge: time.Hour, Count: 2},
+		{MaxAge: 6 * time.Hour, Count: 1},
+		{MaxAge: 24 * time.Hour, Count: 0},
+		{MaxAge: 0, Count: 2},
+	}, bucketPeerAges(ages))
+}
+
+func TestBucketPeerAges_Empty(t *testing.T) {
+	hist := bucketPeerAges(nil)
+	require.Len(t, hist, len(peerAgeBuckets)+1)
+	for _, bucket := range hist {
+		require.Zero(t, bucket.Count)
+	}
+	require.Equal(t, "1m0s", hist[0].label())
+	require.Equal(t, "+Inf", hist[len(hist)-1].label())
+}
@@ -2,10 +2,14 @@ package pex
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
+	dbm "github.com/tendermint/tm-db"
+
 	"github.com/tendermint/tendermint/internal/p2p"
 	"github.com/tendermint/tendermint/internal/p2p/conn"
 	"github.com/tendermint/tendermint/libs/log"
@@ -49,8 +53,66 @@ const (
 	// The reactor should still look to add new peers in order to flush out low
 	// scoring peers that are still in the peer store
 	fullCapacityInterval = 10 * time.Minute
+
+	// how often the connection age distribution of peers is reported
+	peerAgeReportInterval = 30 * time.Second
+
+	// how long OnStop waits for the reactor's goroutines to exit
+	defaultStopTimeout = 10 * time.Second
+
+	// seeds pick the addresses they hand out from crawlSelectionFactor times
+	// as many candidates, preferring peers that were crawled successfully
+	crawlSelectionFactor = 2
+
+	// the window over which the bytes of PEX responses received from each
+	// peer are counted against the address budget
+	addressBudgetWindow = 10 * time.Minute
+
+	// the number of peers a request for addresses is sent to at once while
+	// the reactor is cold starting
+	coldStartFanout = 3
+
+	// the number of new addresses a peer must contribute to be reported as a
+	// good peer
+	addressesToContributeToBecomeGoodPeer = 100
+
+	// peers whose score is below this are not trusted as a source of
+	// addresses, and their responses are ignored
+	minAddressSourceScore p2p.PeerScore = -10
+
+	// how long the addresses advertised in response to PEX requests are
+	// reused for while requests are being throttled
+	advertiseCacheTTL = 10 * time.Second
+
+	// the number of addresses received while throttled that are queued
+	// before they're added to the peer manager in a single batch
+	addBatchSize = 5 * maxAddresses
+
+	// how long peers that flood us with requests or addresses are banned for
+	floodBanDuration = 24 * time.Hour
+
+	// the number of requests sent too soon within a minute after which a peer
+	// is considered to be flooding us, rather than seeing network jitter
+	maxRequestViolations = 3
+
+	// how long peers that send us malformed, invalid or unsolicited PEX
+	// messages are banned for
+	misbehaviorBanDuration = time.Hour
+
+	// peers are usually just relaying addresses they got from others, so
+	// they are only penalized for invalid addresses once at least
+	// minInvalidAddresses of them, and more than maxInvalidAddressFraction of
+	// the response, are invalid
+	minInvalidAddresses       = 3
+	maxInvalidAddressFraction = 0.5
 )
 
+// requestRateLimit allows each peer one PEX request per
+// minReceiveRequestInterval. Peers are disconnected for each request sent too
+// soon, and banned once they've done so maxRequestViolations times within a
+// minute, even across reconnects.
+var requestRateLimit = p2p.RateLimit{Rate: 1 / minReceiveRequestInterval.Seconds(), Burst: 1}
+
 // TODO: We should decide whether we want channel descriptors to be housed
 // within each reactor (as they are now) or, considering that the reactor doesn't
 // really need to care about the channel descriptors, if they should be housed
@@ -64,6 +126,7 @@ func ChannelDescriptor() *conn.ChannelDescriptor {
 		RecvMessageCapacity: maxMsgSize,
 		RecvBufferCapacity:  128,
 		Name:                "pex",
+		Compress:            true,
 	}
 }
 
@@ -77,7 +140,8 @@ func ChannelDescriptor() *conn.ChannelDescriptor {
 // adding it to the back of the list once a response is received.
 type Reactor struct {
 	service.BaseService
-	logger log.Logger
+	logger  log.Logger
+	metrics *Metrics
 
 	peerManager *p2p.PeerManager
 	chCreator   p2p.ChannelCreator
@@ -93,13 +157,224 @@ type Reactor struct {
 	// peer down status update is sent
 	requestsSent map[types.NodeID]struct{}
 
-	// lastReceivedRequests keeps track of when peers send a request to prevent
-	// peers from sending requests too often (as defined by
-	// minReceiveRequestInterval).
-	lastReceivedRequests map[types.NodeID]time.Time
+	// requestLimiter prevents peers from sending requests too often (as
+	// defined by minReceiveRequestInterval).
+	requestLimiter *p2p.PeerRateLimiter
+
+	// minRequestInterval is the minimum time a peer must wait between PEX
+	// requests, with 0 meaning no minimum beyond requestLimiter's.
+	// lastRequest records when each peer last sent us a request.
+	minRequestInterval time.Duration
+	lastRequest        map[types.NodeID]time.Time
+
+	// addressBudget is the number of bytes of PEX responses each peer may
+	// send us per addressBudgetWindow, with 0 meaning no limit.
+	// addressUsage tracks how much of the budget each peer has used in the
+	// current window.
+	addressBudget int64
+	addressUsage  map[types.NodeID]*addressUsage
 
 	// the total number of unique peers added
 	totalPeers int
+
+	// coldStart is set while the node has fewer than coldStartPeers connected
+	// peers, during which the reactor requests addresses more aggressively.
+	// Once the minimum is reached, it is cleared for good.
+	coldStart      bool
+	coldStartPeers int
+
+	// cycleSkips are the addresses received via PEX since the last request
+	// cycle started that were dropped, see LastCycleSkips.
+	cycleSkips []SkipRecord
+
+	// seeds are dialed right away on start and whenever the node has no
+	// peers left, instead of waiting for their dial retry delay.
+	seeds []types.NodeID
+
+	// replaceCh is signaled when a peer is disconnected for not answering
+	// pings, so that addresses to replace it are requested right away.
+	replaceCh chan struct{}
+
+	// throttle decides whether we're receiving so many PEX requests that we
+	// should answer them from advertised, the addresses returned by the
+	// peer manager at advertisedAt, instead of asking it for each request.
+	throttle     requestThrottle
+	advertised   []p2p.NodeAddress
+	advertisedAt time.Time
+
+	// pendingAdds holds the addresses received while throttled that have yet
+	// to be added to the peer manager, with the peer that first sent each,
+	// since pendingSince. They are added in batches, see flushPendingAdds.
+	pendingAdds  map[p2p.NodeAddress]types.NodeID
+	pendingSince time.Time
+
+	// introductions tracks which peer introduced each peer we learned about
+	// via PEX, and whether we went on to connect to it.
+	introductions introductions
+
+	// channel is the PEX channel, once started. pendingRequests holds the
+	// RequestAddresses calls waiting for a response from each peer.
+	channel         p2p.Channel
+	pendingRequests map[types.NodeID]*pendingRequest
+
+	// seedMode makes the reactor disconnect peers as soon as it has
+	// exchanged addresses with them, and crawlStatus records the outcome of
+	// the exchanges with each peer, persisted to crawlStore if set.
+	seedMode    bool
+	crawlStatus map[types.NodeID]CrawlStatus
+	crawlStore  *crawlStore
+
+	// peerUpdates is used to report good peers, and addressesContributed
+	// counts the new addresses each connected peer has sent us.
+	peerUpdates          *p2p.PeerUpdates
+	addressesContributed map[types.NodeID]int
+
+	// validators decides which addresses received from peers are passed on
+	// to the peer manager.
+	validators *AddressPipeline
+
+	// cancel stops the goroutines spawned by OnStart, and routines tracks
+	// the ones still running so OnStop can wait for them for up to
+	// stopTimeout.
+	cancel      context.CancelFunc
+	stopTimeout time.Duration
+	routines    sync.WaitGroup
+	runningMtx  sync.Mutex
+	running     map[string]int
+}
+
+// SkipRecord records a peer address that was not dialed, and why.
+type SkipRecord struct {
+	Address p2p.NodeAddress
+	Reason  p2p.DialSkipReason
+}
+
+// CrawlStatus is the outcome of crawling a peer for addresses in seed mode.
+type CrawlStatus struct {
+	LastCrawled         time.Time // when the peer last sent us addresses
+	LastFailed          time.Time // when crawling the peer last failed
+	Crawls              int       // number of times the peer sent us addresses
+	ConsecutiveFailures int       // number of failed crawls since the last successful one
+	Addresses           int       // number of valid addresses in the last response
+	Moniker             string    // moniker the peer last reported in its handshake
+	Version             string    // software version the peer last reported
+}
+
+// quality ranks the crawl status of a peer for address selection: lower is
+// better. Peers whose last crawl succeeded come first, followed by peers that
+// haven't been crawled, followed by failing peers, worst last.
+func (s CrawlStatus) quality() int {
+	switch {
+	case s.ConsecutiveFailures > 0:
+		return 1 + s.ConsecutiveFailures
+	case s.Crawls > 0:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// addressUsage is the number of bytes of PEX responses a peer has sent us
+// since the start of its budget window.
+type addressUsage struct {
+	windowStart time.Time
+	bytes       int64
+}
+
+// pendingRequest is a request for addresses from a peer, which may be shared
+// by several RequestAddresses callers. done is closed once the response, or
+// an error, has been received.
+type pendingRequest struct {
+	done      chan struct{}
+	addresses []p2p.NodeAddress
+	err       error
+}
+
+// ReactorOption sets an optional parameter on the Reactor.
+type ReactorOption func(*Reactor)
+
+// WithAddressValidators appends custom validators to the reactor's address
+// validation pipeline. They run after the default validators, in the order
+// given.
+func WithAddressValidators(validators ...AddressValidator) ReactorOption {
+	return func(r *Reactor) { r.validators.Append(validators...) }
+}
+
+// WithMetrics sets the reactor's metrics.
+func WithMetrics(metrics *Metrics) ReactorOption {
+	return func(r *Reactor) { r.metrics = metrics }
+}
+
+// WithStopTimeout sets how long OnStop waits for in-flight operations to
+// finish before giving up on them.
+func WithStopTimeout(timeout time.Duration) ReactorOption {
+	return func(r *Reactor) { r.stopTimeout = timeout }
+}
+
+// WithAddressBudget limits the number of bytes of PEX responses that each
+// peer may send us within a 10 minute window. This catches peers which bloat
+// the address book with many responses that are each within the per-message
+// limits. A budget of 0 disables the limit.
+func WithAddressBudget(bytes int64) ReactorOption {
+	return func(r *Reactor) { r.addressBudget = bytes }
+}
+
+// WithColdStart makes the reactor request addresses aggressively until the
+// node has connected to at least minPeers peers: it polls at the minimum
+// interval and asks up to 3 peers at a time instead of one. A minPeers of 0
+// disables cold start.
+func WithColdStart(minPeers int) ReactorOption {
+	return func(r *Reactor) {
+		r.coldStartPeers = minPeers
+		r.coldStart = minPeers > 0
+	}
+}
+
+// WithSeeds sets the peers to dial right away when the node starts and
+// whenever it has no peers left, e.g. its bootstrap peers. They must have been
+// added to the peer manager.
+func WithSeeds(seeds ...types.NodeID) ReactorOption {
+	return func(r *Reactor) { r.seeds = seeds }
+}
+
+// WithRequestRateTarget sets the rate of PEX requests per second above which
+// the reactor answers requests from a periodically refreshed set of addresses,
+// rather than ranking the peer store for each request, in order to cap the CPU
+// spent on busy nodes such as seeds. A target of 0 disables throttling.
+func WithRequestRateTarget(target int) ReactorOption {
+	return func(r *Reactor) { r.throttle.target = target }
+}
+
+// WithCPUTarget sets the fraction of the available CPU, measured as the CPU
+// time used by the process each second, above which the reactor throttles
+// PEX handling as if the request rate target had been exceeded. While
+// throttled, addresses received from peers are also deduplicated and added to
+// the peer manager in batches. A target of 0 disables the CPU trigger.
+func WithCPUTarget(target float64) ReactorOption {
+	return func(r *Reactor) { r.throttle.cpuTarget = target }
+}
+
+// WithMinRequestInterval disconnects peers that send PEX requests more often
+// than once per interval, and reports them as bad peers, lowering their score.
+// Unlike peers that flood us faster than the hard 100ms limit, they aren't
+// banned. An interval of 0 disables the check.
+func WithMinRequestInterval(interval time.Duration) ReactorOption {
+	return func(r *Reactor) { r.minRequestInterval = interval }
+}
+
+// WithSeedMode runs the reactor as a seed: it crawls the network by asking
+// peers for addresses, and hands out addresses to peers that ask for them,
+// disconnecting each peer once it has done either, so that peer slots are
+// freed up for more peers.
+func WithSeedMode() ReactorOption {
+	return func(r *Reactor) { r.seedMode = true }
+}
+
+// WithCrawlStore persists the crawl status of peers in seed mode to db, and
+// loads it on start, so that a seed keeps handing out the peers it knows to
+// be good after a restart.
+func WithCrawlStore(db dbm.DB) ReactorOption {
+	return func(r *Reactor) { r.crawlStore = &crawlStore{db: db} }
 }
 
 // NewReactor returns a reference to a new reactor.
@@ -108,15 +383,35 @@ func NewReactor(
 	peerManager *p2p.PeerManager,
 	channelCreator p2p.ChannelCreator,
 	peerEvents p2p.PeerEventSubscriber,
+	options ...ReactorOption,
 ) *Reactor {
 	r := &Reactor{
 		logger:               logger,
+		metrics:              NopMetrics(),
 		peerManager:          peerManager,
 		chCreator:            channelCreator,
 		peerEvents:           peerEvents,
 		availablePeers:       make(map[types.NodeID]struct{}),
 		requestsSent:         make(map[types.NodeID]struct{}),
-		lastReceivedRequests: make(map[types.NodeID]time.Time),
+		requestLimiter:       p2p.NewPeerRateLimiter(requestRateLimit, maxRequestViolations),
+		lastRequest:          make(map[types.NodeID]time.Time),
+		addressUsage:         make(map[types.NodeID]*addressUsage),
+		introductions:        make(introductions),
+		pendingRequests:      make(map[types.NodeID]*pendingRequest),
+		crawlStatus:          make(map[types.NodeID]CrawlStatus),
+		addressesContributed: make(map[types.NodeID]int),
+		pendingAdds:          make(map[p2p.NodeAddress]types.NodeID),
+		throttle:             requestThrottle{cpuTime: processCPUTime},
+		replaceCh:            make(chan struct{}, 1),
+		validators:           DefaultAddressPipeline(peerManager.SelfID()),
+		stopTimeout:          defaultStopTimeout,
+		running:              make(map[string]int),
+	}
+	// In permissioned networks, only allowlisted addresses are exchanged.
+	r.validators.Append(ValidateAddressAllowed(peerManager.IsAllowed))
+
+	for _, opt := range options {
+		opt(r)
 	}
 
 	r.BaseService = *service.NewBaseService(logger, "PEX", r)
@@ -128,20 +423,98 @@ func NewReactor(
 // messages on that p2p channel accordingly. The caller must be sure to execute
 // OnStop to ensure the outbound p2p Channels are closed.
 func (r *Reactor) OnStart(ctx context.Context) error {
-	channel, err := r.chCreator(ctx, ChannelDescriptor())
+	if err := r.loadCrawlStatus(); err != nil {
+		return fmt.Errorf("failed to load crawl status: %w", err)
+	}
+
+	ctx, r.cancel = context.WithCancel(ctx)
+
+	// Peers that send PEX messages we can't decode are banned, like those
+	// that send us malformed ones.
+	chDesc := ChannelDescriptor()
+	chDesc.OnInvalidMessage = func(peerID types.NodeID, err error) {
+		r.misbehaved(peerID, "malformed", err)
+	}
+	channel, err := r.chCreator(ctx, chDesc)
 	if err != nil {
+		r.cancel()
 		return err
 	}
+	r.mtx.Lock()
+	r.channel = channel
+	r.mtx.Unlock()
 
 	peerUpdates := r.peerEvents(ctx)
-	go r.processPexCh(ctx, channel)
-	go r.processPeerUpdates(ctx, peerUpdates)
+	r.mtx.Lock()
+	r.peerUpdates = peerUpdates
+	r.mtx.Unlock()
+	r.dialSeeds()
+	r.spawn("pex channel", func() { r.processPexCh(ctx, channel) })
+	r.spawn("peer updates", func() { r.processPeerUpdates(ctx, peerUpdates) })
+	r.spawn("peer age reporting", func() { r.reportPeerAges(ctx) })
 	return nil
 }
 
 // OnStop stops the reactor by signaling to all spawned goroutines to exit and
-// blocking until they all exit.
-func (r *Reactor) OnStop() {}
+// blocking until they all exit, or until the stop timeout expires. In the
+// latter case, the goroutines that are still running are logged and left to
+// exit on their own.
+func (r *Reactor) OnStop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.routines.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(r.stopTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		r.logger.Error("timed out waiting for PEX reactor to stop",
+			"timeout", r.stopTimeout,
+			"running", r.runningRoutines())
+	}
+}
+
+// spawn runs fn in a goroutine that OnStop waits for.
+func (r *Reactor) spawn(name string, fn func()) {
+	r.runningMtx.Lock()
+	r.running[name]++
+	r.runningMtx.Unlock()
+
+	r.routines.Add(1)
+	go func() {
+		defer func() {
+			r.runningMtx.Lock()
+			if r.running[name]--; r.running[name] == 0 {
+				delete(r.running, name)
+			}
+			r.runningMtx.Unlock()
+			r.routines.Done()
+		}()
+		fn()
+	}()
+}
+
+// runningRoutines returns the names of the goroutines that haven't exited yet.
+func (r *Reactor) runningRoutines() []string {
+	r.runningMtx.Lock()
+	defer r.runningMtx.Unlock()
+
+	names := make([]string, 0, len(r.running))
+	for name := range r.running {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
 
 // processPexCh implements a blocking event loop where we listen for p2p
 // Envelope messages from the pexCh.
@@ -171,9 +544,14 @@ func (r *Reactor) processPexCh(ctx context.Context, pexCh p2p.Channel) {
 
 		select {
 		case <-ctx.Done():
+			// Don't lose the addresses queued while throttled.
+			r.flushPendingAdds(ctx, time.Now(), true)
 			return
 
 		case <-timer.C:
+			// Add any addresses queued while throttled that are due.
+			r.flushPendingAdds(ctx, time.Now(), false)
+
 			// Send a request for more peer addresses.
 			if err := r.sendRequestForPeers(ctx, pexCh); err != nil {
 				return
@@ -184,6 +562,12 @@ func (r *Reactor) processPexCh(ctx context.Context, pexCh p2p.Channel) {
 			// Note we do not update the poll timer upon making a request, only
 			// when we receive an update that updates our priors.
 
+		case <-r.replaceCh:
+			// A dead peer was dropped, look for addresses to replace it.
+			if err := r.sendRequestForPeers(ctx, pexCh); err != nil {
+				return
+			}
+
 		case envelope, ok := <-incoming:
 			if !ok {
 				return // channel closed
@@ -221,69 +605,510 @@ func (r *Reactor) processPeerUpdates(ctx context.Context, peerUpdates *p2p.PeerU
 	}
 }
 
+// reportPeerAges periodically updates the peer age metrics.
+func (r *Reactor) reportPeerAges(ctx context.Context) {
+	ticker := time.NewTicker(peerAgeReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.PeerAgeHistogram()
+		}
+	}
+}
+
+// PeerAgeHistogram returns the distribution of connection ages of the
+// currently connected peers, and updates the peer age metrics.
+func (r *Reactor) PeerAgeHistogram() []PeerAgeBucket {
+	hist := bucketPeerAges(r.peerManager.ConnectionAges())
+	for _, bucket := range hist {
+		r.metrics.PeersByAge.With("max_age", bucket.label()).Set(float64(bucket.Count))
+	}
+	return hist
+}
+
 // handlePexMessage handles envelopes sent from peers on the PexChannel.
 // If an update was received, a new polling interval is returned; otherwise the
 // duration is 0.
 func (r *Reactor) handlePexMessage(ctx context.Context, envelope *p2p.Envelope, pexCh p2p.Channel) (time.Duration, error) {
-	logger := r.logger.With("peer", envelope.From)
-
 	switch msg := envelope.Message.(type) {
 	case *protop2p.PexRequest:
+		r.metrics.MessagesReceived.With("message_type", "request").Add(1)
+
 		// Verify that this peer hasn't sent us another request too recently.
 		if err := r.markPeerRequest(envelope.From); err != nil {
+			var rateErr p2p.ErrRateLimited
+			if errors.As(err, &rateErr) && rateErr.Sustained {
+				r.banPeer(envelope.From, err)
+			}
+			return 0, err
+		}
+		if err := r.markPeerRequestInterval(envelope.From, time.Now()); err != nil {
+			r.reportBadPeer(ctx, envelope.From)
 			return 0, err
 		}
 
 		// Fetch peers from the peer manager, convert NodeAddresses into URL
 		// strings, and send them back to the caller.
-		nodeAddresses := r.peerManager.Advertise(envelope.From, maxAddresses)
+		nodeAddresses := r.advertise(envelope.From)
 		pexAddresses := make([]protop2p.PexAddress, len(nodeAddresses))
 		for idx, addr := range nodeAddresses {
 			pexAddresses[idx] = protop2p.PexAddress{
 				URL: addr.String(),
 			}
 		}
-		return 0, pexCh.Send(ctx, p2p.Envelope{
+		if err := pexCh.Send(ctx, p2p.Envelope{
 			To:      envelope.From,
 			Message: &protop2p.PexResponse{Addresses: pexAddresses},
-		})
+		}); err != nil {
+			return 0, err
+		}
+
+		// Seeds have no use for the peer once it has our addresses.
+		if r.seedMode {
+			r.peerManager.Release(envelope.From)
+		}
+		return 0, nil
 
 	case *protop2p.PexResponse:
-		// Verify that this response corresponds to one of our pending requests.
-		if err := r.markPeerResponse(envelope.From); err != nil {
-			return 0, err
+		r.metrics.MessagesReceived.With("message_type", "response").Add(1)
+		accepted, dur, err := r.handlePexResponse(ctx, envelope.From, msg, pexCh)
+		r.completeRequest(envelope.From, accepted, err)
+
+		// Seeds have no use for the peer once they've crawled it.
+		if r.seedMode {
+			var nodeInfo *types.NodeInfo
+			if info, ok := r.peerManager.NodeInfo(envelope.From); ok {
+				nodeInfo = &info
+			}
+			r.mtx.Lock()
+			r.recordCrawlLocked(envelope.From, err == nil, len(accepted), nodeInfo)
+			r.mtx.Unlock()
+			if err == nil {
+				r.peerManager.Release(envelope.From)
+			}
+		}
+		return dur, err
+
+	default:
+		r.metrics.MessagesReceived.With("message_type", "unknown").Add(1)
+		err := fmt.Errorf("received unknown message: %T", msg)
+		r.misbehaved(envelope.From, "malformed", err)
+		return 0, err
+	}
+}
+
+// handlePexResponse handles a response to one of our PEX requests, adding
+// the addresses it contains to the peer manager. It returns the addresses that
+// passed validation, and the new polling interval.
+func (r *Reactor) handlePexResponse(
+	ctx context.Context,
+	from types.NodeID,
+	msg *protop2p.PexResponse,
+	pexCh p2p.Channel,
+) ([]p2p.NodeAddress, time.Duration, error) {
+	logger := r.logger.With("peer", from)
+
+	// Verify that this response corresponds to one of our pending requests.
+	if err := r.markPeerResponse(from); err != nil {
+		r.misbehaved(from, "unsolicited", err)
+		return nil, 0, err
+	}
+
+	// Verify that the response does not exceed the safety limit.
+	if len(msg.Addresses) > maxAddresses {
+		err := fmt.Errorf("peer sent too many addresses (%d > maxiumum %d)",
+			len(msg.Addresses), maxAddresses)
+		r.misbehaved(from, "oversized", err)
+		return nil, 0, err
+	}
+
+	// Verify that the peer hasn't sent us too many addresses recently.
+	if err := r.markPeerAddressBytes(from, msg.Size()); err != nil {
+		r.banPeer(from, err)
+		return nil, 0, err
+	}
+
+	// Don't trust addresses from peers with a poor reputation.
+	if score := r.peerManager.Score(from); score < minAddressSourceScore {
+		logger.Debug("ignoring PEX addresses from low-scored peer", "score", score)
+		return nil, r.calculateNextRequestTime(0), nil
+	}
+
+	var numPenalized int
+	accepted := make([]p2p.NodeAddress, 0, len(msg.Addresses))
+	for _, pexAddress := range msg.Addresses {
+		peerAddress, err := p2p.ParseNodeAddress(pexAddress.URL)
+		if err != nil {
+			logger.Debug("dropped invalid PEX address", "url", pexAddress.URL, "err", err)
+			numPenalized++
+			continue
+		}
+		switch verdict := r.validators.Validate(from, peerAddress); verdict {
+		case AddressAccept:
+		case AddressPenalize:
+			numPenalized++
+			fallthrough
+		default:
+			logger.Debug("dropped PEX address", "address", peerAddress, "verdict", verdict)
+			reason := p2p.DialSkipFiltered
+			if peerAddress.NodeID == r.peerManager.SelfID() {
+				reason = p2p.DialSkipSelf
+			}
+			r.mtx.Lock()
+			r.cycleSkips = append(r.cycleSkips, SkipRecord{Address: peerAddress, Reason: reason})
+			r.mtx.Unlock()
+			continue
 		}
+		accepted = append(accepted, peerAddress)
+	}
 
-		// Verify that the response does not exceed the safety limit.
-		if len(msg.Addresses) > maxAddresses {
-			return 0, fmt.Errorf("peer sent too many addresses (%d > maxiumum %d)",
-				len(msg.Addresses), maxAddresses)
+	if numPenalized >= minInvalidAddresses &&
+		float64(numPenalized) > maxInvalidAddressFraction*float64(len(msg.Addresses)) {
+		err := fmt.Errorf("peer sent %d invalid addresses", numPenalized)
+		r.misbehaved(from, "invalid_addresses", err)
+		if err := pexCh.SendError(ctx, p2p.PeerError{
+			NodeID: from,
+			Err:    err,
+		}); err != nil {
+			return nil, 0, err
 		}
+	}
 
-		var numAdded int
-		for _, pexAddress := range msg.Addresses {
-			peerAddress, err := p2p.ParseNodeAddress(pexAddress.URL)
-			if err != nil {
-				continue
+	// While throttled, queue the addresses to be added in a batch with the
+	// ones from other responses, since peers tend to send us many of the same
+	// addresses.
+	now := time.Now()
+	r.mtx.Lock()
+	queue := r.updateThrottleLocked(now, false) || len(r.pendingAdds) > 0
+	if queue {
+		if len(r.pendingAdds) == 0 {
+			r.pendingSince = now
+		}
+		for _, addr := range accepted {
+			if _, ok := r.pendingAdds[addr]; !ok {
+				r.pendingAdds[addr] = from
 			}
-			added, err := r.peerManager.Add(peerAddress)
-			if err != nil {
-				logger.Error("failed to add PEX address", "address", peerAddress, "err", err)
+		}
+	}
+	r.mtx.Unlock()
+
+	var numAdded int
+	if queue {
+		numAdded = r.flushPendingAdds(ctx, now, false)
+	} else {
+		numAdded = r.addAddresses(ctx, accepted, func(p2p.NodeAddress) types.NodeID { return from })
+	}
+	return accepted, r.calculateNextRequestTime(numAdded), nil
+}
+
+// flushPendingAdds adds the addresses queued while throttled to the peer
+// manager, if there are at least addBatchSize of them, they have been queued
+// for advertiseCacheTTL, throttling has relaxed, or force is set. It returns
+// the number of new addresses added.
+func (r *Reactor) flushPendingAdds(ctx context.Context, now time.Time, force bool) int {
+	r.mtx.Lock()
+	if len(r.pendingAdds) == 0 || !force && len(r.pendingAdds) < addBatchSize &&
+		now.Sub(r.pendingSince) < advertiseCacheTTL && r.updateThrottleLocked(now, false) {
+		r.mtx.Unlock()
+		return 0
+	}
+	pending := r.pendingAdds
+	r.pendingAdds = make(map[p2p.NodeAddress]types.NodeID)
+	r.mtx.Unlock()
+
+	addrs := make([]p2p.NodeAddress, 0, len(pending))
+	for addr := range pending {
+		addrs = append(addrs, addr)
+	}
+	r.logger.Debug("adding queued PEX addresses", "addresses", len(addrs))
+	return r.addAddresses(ctx, addrs, func(addr p2p.NodeAddress) types.NodeID { return pending[addr] })
+}
+
+// addAddresses adds addresses received via PEX to the peer manager, crediting
+// the peer returned by source for each new one, unless it's empty because the
+// peer has since disconnected. It returns the number of new addresses added.
+func (r *Reactor) addAddresses(
+	ctx context.Context,
+	addrs []p2p.NodeAddress,
+	source func(p2p.NodeAddress) types.NodeID,
+) int {
+	contributed := make(map[types.NodeID]int)
+	var numAdded int
+	for _, addr := range addrs {
+		from := source(addr)
+		added, err := r.peerManager.Add(addr)
+		if err != nil {
+			r.logger.Error("failed to add PEX address", "peer", from, "address", addr, "err", err)
+			continue
+		}
+		if added {
+			numAdded++
+			r.logger.Debug("added PEX address", "peer", from, "address", addr)
+			if from == "" {
 				continue
 			}
-			if added {
-				numAdded++
-				logger.Debug("added PEX address", "address", peerAddress)
+			contributed[from]++
+			r.mtx.Lock()
+			r.introductions.introduce(from, addr.NodeID)
+			r.mtx.Unlock()
+		}
+	}
+
+	for from, n := range contributed {
+		r.mtx.Lock()
+		prev := r.addressesContributed[from]
+		r.addressesContributed[from] = prev + n
+		peerUpdates := r.peerUpdates
+		r.mtx.Unlock()
+
+		if prev/addressesToContributeToBecomeGoodPeer !=
+			(prev+n)/addressesToContributeToBecomeGoodPeer && peerUpdates != nil {
+			peerUpdates.SendUpdate(ctx, p2p.PeerUpdate{
+				NodeID: from,
+				Status: p2p.PeerStatusGood,
+			})
+		}
+	}
+	return numAdded
+}
+
+// RequestAddresses asks a connected peer for addresses, and waits for its
+// response. The addresses that pass validation are returned, and also added to
+// the peer manager as usual, which is deferred while PEX handling is
+// throttled. Concurrent requests to the same peer, including
+// the reactor's own periodic requests, share a single PEX request, so that we
+// don't exceed the peer's rate limit.
+func (r *Reactor) RequestAddresses(ctx context.Context, peerID types.NodeID) ([]p2p.NodeAddress, error) {
+	r.mtx.Lock()
+	req, ok := r.pendingRequests[peerID]
+	if !ok {
+		_, sent := r.requestsSent[peerID]
+		_, available := r.availablePeers[peerID]
+		switch {
+		case r.channel == nil:
+			r.mtx.Unlock()
+			return nil, errors.New("PEX reactor not started")
+		case !sent && !available:
+			r.mtx.Unlock()
+			return nil, fmt.Errorf("peer %v is not available for PEX", peerID)
+		}
+
+		// If a request has already been sent, we just wait for the response.
+		if !sent {
+			if err := r.channel.Send(ctx, p2p.Envelope{
+				To:      peerID,
+				Message: &protop2p.PexRequest{},
+			}); err != nil {
+				r.mtx.Unlock()
+				return nil, err
 			}
+			delete(r.availablePeers, peerID)
+			r.requestsSent[peerID] = struct{}{}
 		}
 
-		return r.calculateNextRequestTime(numAdded), nil
+		req = &pendingRequest{done: make(chan struct{})}
+		r.pendingRequests[peerID] = req
+	}
+	r.mtx.Unlock()
 
-	default:
-		return 0, fmt.Errorf("received unknown message: %T", msg)
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-req.done:
+		return req.addresses, req.err
+	}
+}
+
+// completeRequest completes any pending RequestAddresses calls for a peer.
+func (r *Reactor) completeRequest(peerID types.NodeID, addresses []p2p.NodeAddress, err error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.completeRequestLocked(peerID, addresses, err)
+}
+
+// completeRequestLocked is completeRequest for callers holding the mutex.
+func (r *Reactor) completeRequestLocked(peerID types.NodeID, addresses []p2p.NodeAddress, err error) {
+	if req, ok := r.pendingRequests[peerID]; ok {
+		req.addresses = addresses
+		req.err = err
+		close(req.done)
+		delete(r.pendingRequests, peerID)
 	}
 }
 
+// CrawlStatus returns the crawl status of each peer crawled while running in
+// seed mode, including those persisted by previous runs.
+func (r *Reactor) CrawlStatus() map[types.NodeID]CrawlStatus {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	status := make(map[types.NodeID]CrawlStatus, len(r.crawlStatus))
+	for id, s := range r.crawlStatus {
+		status[id] = s
+	}
+	return status
+}
+
+// loadCrawlStatus loads the persisted crawl status of peers, if any, and
+// forgets peers that are no longer in the peer store.
+func (r *Reactor) loadCrawlStatus() error {
+	if r.crawlStore == nil {
+		return nil
+	}
+	records, err := r.crawlStore.load()
+	if err != nil {
+		return err
+	}
+
+	known := map[types.NodeID]bool{}
+	for _, id := range r.peerManager.Peers() {
+		known[id] = true
+	}
+	for id := range records {
+		if !known[id] {
+			if err := r.crawlStore.delete(id); err != nil {
+				return err
+			}
+			delete(records, id)
+		}
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for id, status := range records {
+		r.crawlStatus[id] = status
+	}
+	return nil
+}
+
+// recordCrawlLocked records the outcome of crawling a peer, along with the
+// moniker and version in its node info, if given. The caller must hold the
+// mutex, and look up the node info beforehand since the peer manager must not
+// be called while holding it.
+func (r *Reactor) recordCrawlLocked(peerID types.NodeID, ok bool, addresses int, nodeInfo *types.NodeInfo) {
+	status := r.crawlStatus[peerID]
+	now := time.Now()
+	if ok {
+		status.LastCrawled = now
+		status.Crawls++
+		status.ConsecutiveFailures = 0
+		status.Addresses = addresses
+	} else {
+		status.LastFailed = now
+		status.ConsecutiveFailures++
+	}
+	if nodeInfo != nil {
+		status.Moniker = nodeInfo.Moniker
+		status.Version = nodeInfo.Version
+	}
+	r.crawlStatus[peerID] = status
+
+	if r.crawlStore != nil {
+		if err := r.crawlStore.set(peerID, status); err != nil {
+			r.logger.Error("failed to persist crawl status", "peer", peerID, "err", err)
+		}
+	}
+}
+
+// selectByCrawlQualityLocked orders addresses by the crawl status of their
+// peers, keeping the order of peers of equal quality, and returns the best
+// maxAddresses of them. The caller must hold the mutex.
+func (r *Reactor) selectByCrawlQualityLocked(addresses []p2p.NodeAddress) []p2p.NodeAddress {
+	sort.SliceStable(addresses, func(i, j int) bool {
+		return r.crawlStatus[addresses[i].NodeID].quality() < r.crawlStatus[addresses[j].NodeID].quality()
+	})
+	if len(addresses) > maxAddresses {
+		addresses = addresses[:maxAddresses]
+	}
+	return addresses
+}
+
+// IntroducerQuality returns, for each peer that introduced us to new peers via
+// PEX, how many of those peers we connected to and how many we stayed
+// connected to for at least 10 minutes. This can be used to evaluate seeds.
+func (r *Reactor) IntroducerQuality() []IntroducerQuality {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.introductions.quality(time.Now())
+}
+
+// advertise returns the addresses to send to a peer in response to a PEX
+// request, throttling how often they are computed if we receive too many
+// requests. Seeds select them by crawl status.
+func (r *Reactor) advertise(peerID types.NodeID) []p2p.NodeAddress {
+	limit := uint16(maxAddresses)
+	if r.seedMode {
+		limit *= crawlSelectionFactor
+	}
+
+	// The peer manager must not be called while holding r.mtx, see
+	// dialSeeds.
+	now := time.Now()
+	r.mtx.Lock()
+	throttled := r.updateThrottleLocked(now, true)
+	if !throttled {
+		r.advertised = nil
+	}
+	cached := r.advertised
+	if now.Sub(r.advertisedAt) >= advertiseCacheTTL {
+		cached = nil
+	}
+	r.mtx.Unlock()
+
+	var addresses []p2p.NodeAddress
+	if !throttled {
+		addresses = r.peerManager.Advertise(peerID, limit)
+	} else {
+		if cached == nil {
+			cached = r.peerManager.Advertise("", limit)
+			r.mtx.Lock()
+			r.advertised = cached
+			r.advertisedAt = now
+			r.mtx.Unlock()
+		}
+		addresses = make([]p2p.NodeAddress, 0, len(cached))
+		for _, addr := range cached {
+			if addr.NodeID != peerID {
+				addresses = append(addresses, addr)
+			}
+		}
+	}
+
+	if r.seedMode {
+		r.mtx.RLock()
+		addresses = r.selectByCrawlQualityLocked(addresses)
+		r.mtx.RUnlock()
+	}
+	return addresses
+}
+
+// updateThrottleLocked updates the request throttle, recording a request if
+// request is set, and returns whether PEX handling is throttled. The caller
+// must hold r.mtx.
+func (r *Reactor) updateThrottleLocked(now time.Time, request bool) bool {
+	wasThrottled := r.throttle.throttled
+	var throttled bool
+	if request {
+		throttled = r.throttle.observe(now)
+	} else {
+		throttled = r.throttle.update(now)
+	}
+	if throttled != wasThrottled {
+		r.logger.Info("PEX request throttling changed", "throttled", throttled,
+			"target", r.throttle.target, "cpu_target", r.throttle.cpuTarget)
+		if throttled {
+			r.metrics.Throttled.Set(1)
+		} else {
+			r.metrics.Throttled.Set(0)
+		}
+	}
+	return throttled
+}
+
 // processPeerUpdate processes a PeerUpdate. For added peers, PeerStatusUp, we
 // send a request for addresses.
 func (r *Reactor) processPeerUpdate(peerUpdate p2p.PeerUpdate) {
@@ -295,47 +1120,132 @@ func (r *Reactor) processPeerUpdate(peerUpdate p2p.PeerUpdate) {
 	switch peerUpdate.Status {
 	case p2p.PeerStatusUp:
 		r.availablePeers[peerUpdate.NodeID] = struct{}{}
+		r.introductions.up(peerUpdate.NodeID, time.Now())
+		if r.coldStart && len(r.availablePeers)+len(r.requestsSent) >= r.coldStartPeers {
+			r.logger.Info("PEX cold start complete", "peers", r.coldStartPeers)
+			r.coldStart = false
+		}
 	case p2p.PeerStatusDown:
+		// A seed's crawl fails if the peer disconnects before responding. The
+		// peer manager has already forgotten its node info, and it must not be
+		// called from here anyway, see dialSeeds.
+		if _, pending := r.requestsSent[peerUpdate.NodeID]; pending && r.seedMode {
+			r.recordCrawlLocked(peerUpdate.NodeID, false, 0, nil)
+		}
 		delete(r.availablePeers, peerUpdate.NodeID)
 		delete(r.requestsSent, peerUpdate.NodeID)
-		delete(r.lastReceivedRequests, peerUpdate.NodeID)
+		r.requestLimiter.ReleasePeer(peerUpdate.NodeID)
+		delete(r.lastRequest, peerUpdate.NodeID)
+		delete(r.addressUsage, peerUpdate.NodeID)
+		delete(r.addressesContributed, peerUpdate.NodeID)
+		for addr, from := range r.pendingAdds {
+			if from == peerUpdate.NodeID {
+				r.pendingAdds[addr] = ""
+			}
+		}
+		r.introductions.down(peerUpdate.NodeID, time.Now())
+		r.completeRequestLocked(peerUpdate.NodeID, nil,
+			fmt.Errorf("peer %v disconnected", peerUpdate.NodeID))
+		if peerUpdate.Unresponsive {
+			select {
+			case r.replaceCh <- struct{}{}:
+			default:
+			}
+		}
+		if len(r.availablePeers)+len(r.requestsSent) == 0 && len(r.seeds) > 0 {
+			// This goroutine drains the peer manager's subscription, which
+			// it may be broadcasting to while holding its lock, so the
+			// seeds must be dialed elsewhere.
+			r.spawn("seed dialing", r.dialSeeds)
+		}
 	default:
 	}
 }
 
+// dialSeeds makes the peer manager dial the seeds right away. It must not be
+// called while holding r.mtx, nor from processPeerUpdates.
+func (r *Reactor) dialSeeds() {
+	for _, id := range r.seeds {
+		if err := r.peerManager.DialImmediately(id); err != nil {
+			r.logger.Error("failed to dial seed", "peer", id, "err", err)
+		}
+	}
+}
+
 // sendRequestForPeers chooses a peer from the set of available peers and sends
 // that peer a request for more peer addresses. The chosen peer is moved into
 // the requestsSent bucket so that we will not attempt to contact them again
-// until they've replied or updated.
+// until they've replied or updated. While cold starting, up to coldStartFanout
+// peers are chosen at once.
 func (r *Reactor) sendRequestForPeers(ctx context.Context, pexCh p2p.Channel) error {
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
+
+	// A new cycle starts, even if no request can be sent.
+	r.cycleSkips = nil
+
 	if len(r.availablePeers) == 0 {
 		// no peers are available
 		r.logger.Debug("no available peers to send a PEX request to (retrying)")
 		return nil
 	}
 
-	// Select an arbitrary peer from the available set.
-	var peerID types.NodeID
-	for peerID = range r.availablePeers {
-		break
+	fanout := 1
+	if r.coldStart {
+		fanout = coldStartFanout
 	}
 
-	if err := pexCh.Send(ctx, p2p.Envelope{
-		To:      peerID,
-		Message: &protop2p.PexRequest{},
-	}); err != nil {
-		return err
-	}
+	// Select arbitrary peers from the available set.
+	for peerID := range r.availablePeers {
+		if fanout == 0 {
+			break
+		}
+		fanout--
 
-	// Move the peer from available to pending.
-	delete(r.availablePeers, peerID)
-	r.requestsSent[peerID] = struct{}{}
+		if err := pexCh.Send(ctx, p2p.Envelope{
+			To:      peerID,
+			Message: &protop2p.PexRequest{},
+		}); err != nil {
+			return err
+		}
+
+		// Move the peer from available to pending.
+		delete(r.availablePeers, peerID)
+		r.requestsSent[peerID] = struct{}{}
+	}
 
 	return nil
 }
 
+// LastCycleSkips returns the addresses that were skipped in the current PEX
+// request cycle, and why: those received from peers but dropped, e.g. because
+// they're our own or were filtered out, and those that the peer manager last
+// skipped over when looking for a peer to dial, e.g. because they're banned,
+// backing off or already connected. The records are reset at the start of
+// each cycle.
+func (r *Reactor) LastCycleSkips() []SkipRecord {
+	dialSkips := r.peerManager.LastDialSkips()
+
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	skips := make([]SkipRecord, 0, len(r.cycleSkips)+len(dialSkips))
+	skips = append(skips, r.cycleSkips...)
+	for _, skip := range dialSkips {
+		skips = append(skips, SkipRecord{Address: skip.Address, Reason: skip.Reason})
+	}
+	return skips
+}
+
+// ColdStarting returns true if the reactor is still cold starting, i.e. the
+// node has not yet connected to the minimum number of peers given by
+// WithColdStart.
+func (r *Reactor) ColdStarting() bool {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.coldStart
+}
+
 // calculateNextRequestTime selects how long we should wait before attempting
 // to send out another request for peer addresses.
 //
@@ -350,19 +1260,26 @@ func (r *Reactor) sendRequestForPeers(ctx context.Context, pexCh p2p.Channel) er
 // The minimum interval will be minReceiveRequestInterval to ensure we will not
 // request from any peer more often than we would allow them to do from us.
 func (r *Reactor) calculateNextRequestTime(added int) time.Duration {
+	ratio := r.peerManager.PeerRatio()
+
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
 
 	r.totalPeers += added
 
 	// If the peer store is nearly full, wait the maximum interval.
-	if ratio := r.peerManager.PeerRatio(); ratio >= 0.95 {
+	if ratio >= 0.95 {
 		r.logger.Debug("Peer manager is nearly full",
 			"sleep_period", fullCapacityInterval,
 			"ratio", ratio)
 		return fullCapacityInterval
 	}
 
+	// While cold starting, poll as often as we can.
+	if r.coldStart {
+		return minReceiveRequestInterval
+	}
+
 	// If there are no available peers to query, poll less aggressively.
 	if len(r.availablePeers) == 0 {
 		r.logger.Debug("No available peers to send a PEX request",
@@ -379,18 +1296,45 @@ func (r *Reactor) calculateNextRequestTime(added int) time.Duration {
 }
 
 func (r *Reactor) markPeerRequest(peer types.NodeID) error {
+	if err := r.requestLimiter.Allow(peer); err != nil {
+		return fmt.Errorf("peer %v sent PEX request too soon (minimum interval %v): %w",
+			peer, minReceiveRequestInterval, err)
+	}
+	return nil
+}
+
+// markPeerRequestInterval records a PEX request received from the peer at
+// now, and errors if the peer's previous request was less than
+// minRequestInterval ago.
+func (r *Reactor) markPeerRequestInterval(peer types.NodeID, now time.Time) error {
+	if r.minRequestInterval <= 0 {
+		return nil
+	}
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
-	if lastRequestTime, ok := r.lastReceivedRequests[peer]; ok {
-		if d := time.Since(lastRequestTime); d < minReceiveRequestInterval {
-			return fmt.Errorf("peer %v sent PEX request too soon (%v < minimum %v)",
-				peer, d, minReceiveRequestInterval)
-		}
+	last, ok := r.lastRequest[peer]
+	r.lastRequest[peer] = now
+	if ok && now.Sub(last) < r.minRequestInterval {
+		return fmt.Errorf("peer %v sent PEX request %v after the previous one (minimum interval %v)",
+			peer, now.Sub(last), r.minRequestInterval)
 	}
-	r.lastReceivedRequests[peer] = time.Now()
 	return nil
 }
 
+// reportBadPeer lowers the score of a peer that sent us requests too often.
+// The caller disconnects it by returning an error.
+func (r *Reactor) reportBadPeer(ctx context.Context, peer types.NodeID) {
+	r.mtx.RLock()
+	peerUpdates := r.peerUpdates
+	r.mtx.RUnlock()
+	if peerUpdates != nil {
+		peerUpdates.SendUpdate(ctx, p2p.PeerUpdate{
+			NodeID: peer,
+			Status: p2p.PeerStatusBad,
+		})
+	}
+}
+
 func (r *Reactor) markPeerResponse(peer types.NodeID) error {
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
@@ -405,3 +1349,50 @@ func (r *Reactor) markPeerResponse(peer types.NodeID) error {
 	r.availablePeers[peer] = struct{}{}
 	return nil
 }
+
+// banPeer puts a peer that flooded us on the peer manager's ban list, so that
+// we don't reconnect to it once it's been disconnected.
+func (r *Reactor) banPeer(peer types.NodeID, reason error) {
+	r.logger.Info("banning peer for flooding", "peer", peer, "reason", reason, "duration", floodBanDuration)
+	if err := r.peerManager.Ban(peer, floodBanDuration); err != nil {
+		r.logger.Error("failed to ban peer", "peer", peer, "err", err)
+	}
+}
+
+// misbehaved records that a peer sent us a malformed, invalid or unsolicited
+// PEX message, and bans it so that we don't reconnect to it once it's been
+// disconnected.
+func (r *Reactor) misbehaved(peer types.NodeID, reason string, err error) {
+	r.metrics.PeerMisbehavior.With("reason", reason).Add(1)
+	r.logger.Info("banning misbehaving peer", "peer", peer, "reason", reason, "err", err,
+		"duration", misbehaviorBanDuration)
+	if err := r.peerManager.Ban(peer, misbehaviorBanDuration); err != nil {
+		r.logger.Error("failed to ban peer", "peer", peer, "err", err)
+	}
+}
+
+// markPeerAddressBytes counts the size of a PEX response against the peer's
+// address budget, and errors if the budget for the current window has been
+// exceeded.
+func (r *Reactor) markPeerAddressBytes(peer types.NodeID, size int) error {
+	if r.addressBudget <= 0 {
+		return nil
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	now := time.Now()
+	usage, ok := r.addressUsage[peer]
+	if !ok || now.Sub(usage.windowStart) >= addressBudgetWindow {
+		usage = &addressUsage{windowStart: now}
+		r.addressUsage[peer] = usage
+	}
+	usage.bytes += int64(size)
+
+	if usage.bytes > r.addressBudget {
+		return fmt.Errorf("peer %v sent too many address bytes (%d > budget %d within %v)",
+			peer, usage.bytes, r.addressBudget, addressBudgetWindow)
+	}
+	return nil
+}
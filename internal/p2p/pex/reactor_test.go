@@ -98,6 +98,128 @@ func TestReactorSendsRequestsTooOften(t *testing.T) {
 	require.Empty(t, r.pexOutCh)
 	require.Contains(t, peerErr.Err.Error(), "sent PEX request too soon")
 	require.Equal(t, badNode, peerErr.NodeID)
+
+	// a single early request only gets the peer disconnected, but it's banned
+	// once it keeps sending them, even across reconnects
+	for i := 0; i < 2; i++ {
+		require.False(t, r.manager.IsBanned(badNode))
+		r.peerCh <- p2p.PeerUpdate{NodeID: badNode, Status: p2p.PeerStatusDown}
+		r.pexInCh <- p2p.Envelope{
+			From:    badNode,
+			Message: &p2pproto.PexRequest{},
+		}
+		peerErr = <-r.pexErrCh
+		require.Contains(t, peerErr.Err.Error(), "sent PEX request too soon")
+	}
+	require.True(t, r.manager.IsBanned(badNode))
+}
+
+func TestReactorColdStartPollingIsNotBanned(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := setupSingle(ctx, t)
+	peer := newNodeID(t, "b")
+
+	// A cold starting peer polls us as often as it may, i.e. every
+	// minReceiveRequestInterval after receiving our previous response.
+	const pollInterval = 100 * time.Millisecond
+	request := func() {
+		r.pexInCh <- p2p.Envelope{
+			From:    peer,
+			Message: &p2pproto.PexRequest{},
+		}
+		select {
+		case resp := <-r.pexOutCh:
+			_, ok := resp.Message.(*p2pproto.PexResponse)
+			require.True(t, ok)
+		case peerErr := <-r.pexErrCh:
+			t.Fatalf("cold start poll failed: %v", peerErr.Err)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		request()
+		time.Sleep(pollInterval)
+	}
+
+	// Network jitter can make a poll arrive a bit early, which disconnects
+	// the peer, but doesn't get it banned.
+	request()
+	r.pexInCh <- p2p.Envelope{
+		From:    peer,
+		Message: &p2pproto.PexRequest{},
+	}
+	peerErr := <-r.pexErrCh
+	require.Contains(t, peerErr.Err.Error(), "sent PEX request too soon")
+	r.peerCh <- p2p.PeerUpdate{NodeID: peer, Status: p2p.PeerStatusDown}
+
+	time.Sleep(pollInterval)
+	for i := 0; i < 10; i++ {
+		request()
+		time.Sleep(pollInterval)
+	}
+	require.False(t, r.manager.IsBanned(peer))
+}
+
+func TestReactorEnforcesMinRequestInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pexInCh := make(chan p2p.Envelope, 1)
+	pexOutCh := make(chan p2p.Envelope, 10)
+	pexErrCh := make(chan p2p.PeerError, 1)
+	chDesc := pex.ChannelDescriptor()
+	pexCh := p2p.NewChannel(chDesc.ID, chDesc.Name, pexInCh, pexOutCh, pexErrCh)
+
+	peerManager, err := p2p.NewPeerManager(newNodeID(t, "a"), dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	reactor := pex.NewReactor(
+		log.NewNopLogger(),
+		peerManager,
+		func(context.Context, *p2p.ChannelDescriptor) (p2p.Channel, error) { return pexCh, nil },
+		peerManager.Subscribe,
+		pex.WithMinRequestInterval(time.Minute),
+	)
+	require.NoError(t, reactor.Start(ctx))
+	t.Cleanup(reactor.Wait)
+
+	peer := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	added, err := peerManager.Add(peer)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, peerManager.Accepted(peer.NodeID))
+	peerManager.Ready(ctx, peer.NodeID, nil)
+
+	// The first request is answered. The second one is past the hard rate
+	// limit, but within the minimum interval.
+	pexInCh <- p2p.Envelope{From: peer.NodeID, Message: &p2pproto.PexRequest{}}
+	require.Eventually(t, func() bool {
+		for {
+			select {
+			case envelope := <-pexOutCh:
+				if _, ok := envelope.Message.(*p2pproto.PexResponse); ok {
+					return true
+				}
+			default:
+				return false
+			}
+		}
+	}, 10*time.Second, 10*time.Millisecond)
+
+	time.Sleep(200 * time.Millisecond)
+	pexInCh <- p2p.Envelope{From: peer.NodeID, Message: &p2pproto.PexRequest{}}
+
+	select {
+	case peerErr := <-pexErrCh:
+		require.Equal(t, peer.NodeID, peerErr.NodeID)
+		require.Contains(t, peerErr.Err.Error(), "minimum interval")
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to report the peer within 10 seconds")
+	}
+	require.Eventually(t, func() bool {
+		return peerManager.Score(peer.NodeID) == -1
+	}, 10*time.Second, 10*time.Millisecond)
+	require.False(t, peerManager.IsBanned(peer.NodeID))
 }
 
 func TestReactorSendsResponseWithoutRequest(t *testing.T) {
@@ -191,6 +313,734 @@ func TestReactorErrorsOnReceivingTooManyPeers(t *testing.T) {
 	require.Empty(t, r.pexOutCh)
 	require.Contains(t, peerErr.Err.Error(), "peer sent too many addresses")
 	require.Equal(t, peer.NodeID, peerErr.NodeID)
+	require.True(t, r.manager.IsBanned(peer.NodeID))
+}
+
+func TestReactorBansUnsolicitedResponse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := setupSingle(ctx, t)
+	peer := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	added, err := r.manager.Add(peer)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// the peer never reported as up, so we never sent it a request
+	r.pexInCh <- p2p.Envelope{
+		From: peer.NodeID,
+		Message: &p2pproto.PexResponse{
+			Addresses: []p2pproto.PexAddress{{URL: peer.String()}},
+		},
+	}
+
+	peerErr := <-r.pexErrCh
+	require.Equal(t, peer.NodeID, peerErr.NodeID)
+	require.Contains(t, peerErr.Err.Error(), "none was requested")
+	require.True(t, r.manager.IsBanned(peer.NodeID))
+}
+
+func TestReactorErrorsOnExceedingAddressBudget(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// each response is well within the per-message limits, but the budget
+	// only allows for three of them
+	makeResponse := func() *p2pproto.PexResponse {
+		addresses := make([]p2pproto.PexAddress, 20)
+		for i := range addresses {
+			nodeAddress := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+			addresses[i] = p2pproto.PexAddress{URL: nodeAddress.String()}
+		}
+		return &p2pproto.PexResponse{Addresses: addresses}
+	}
+	budget := int64(3*makeResponse().Size() + 1)
+
+	r := setupSingle(ctx, t, pex.WithAddressBudget(budget))
+	peer := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	added, err := r.manager.Add(peer)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	r.peerCh <- p2p.PeerUpdate{
+		NodeID: peer.NodeID,
+		Status: p2p.PeerStatusUp,
+	}
+
+	for i := 0; i < 4; i++ {
+		select {
+		case req := <-r.pexOutCh:
+			_, ok := req.Message.(*p2pproto.PexRequest)
+			require.True(t, ok, "expected pex request")
+			r.pexInCh <- p2p.Envelope{From: peer.NodeID, Message: makeResponse()}
+
+		case peerErr := <-r.pexErrCh:
+			t.Fatalf("unexpected peer error after %d responses: %v", i, peerErr)
+
+		case <-time.After(10 * time.Second):
+			t.Fatal("pex failed to send a request within 10 seconds")
+		}
+	}
+
+	select {
+	case peerErr := <-r.pexErrCh:
+		require.Contains(t, peerErr.Err.Error(), "sent too many address bytes")
+		require.Equal(t, peer.NodeID, peerErr.NodeID)
+		require.True(t, r.manager.IsBanned(peer.NodeID))
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to report the peer within 10 seconds")
+	}
+}
+
+func TestReactorColdStart(t *testing.T) {
+	testcases := map[string]struct {
+		minPeers     int
+		coldStarting bool
+		requests     int
+	}{
+		"cold start requests from several peers": {5, true, 3},
+		"minimum reached requests from one peer": {3, false, 1},
+		"disabled requests from one peer":        {0, false, 1},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			r := setupSingle(ctx, t, pex.WithColdStart(tc.minPeers))
+			require.Equal(t, tc.minPeers > 0, r.reactor.ColdStarting())
+
+			for i := 0; i < 3; i++ {
+				r.peerCh <- p2p.PeerUpdate{NodeID: randomNodeID(), Status: p2p.PeerStatusUp}
+			}
+			require.Eventually(t, func() bool {
+				return len(r.peerCh) == 0
+			}, time.Second, 10*time.Millisecond)
+
+			// The requests of a single round are sent back to back, and the
+			// next round is at least minReceiveRequestInterval later.
+			requests := 0
+			timeout := time.After(10 * time.Second)
+		loop:
+			for {
+				select {
+				case req := <-r.pexOutCh:
+					_, ok := req.Message.(*p2pproto.PexRequest)
+					require.True(t, ok, "expected pex request")
+					requests++
+					timeout = time.After(50 * time.Millisecond)
+				case <-timeout:
+					break loop
+				}
+			}
+			require.Equal(t, tc.requests, requests)
+			require.Equal(t, tc.coldStarting, r.reactor.ColdStarting())
+		})
+	}
+}
+
+func TestReactorDialsSeedsWithoutPeers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	seed := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	r := setupSingle(ctx, t, pex.WithSeeds(seed.NodeID))
+	added, err := r.manager.Add(seed)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// a failed dial puts the seed in backoff
+	require.Equal(t, seed, r.manager.TryDialNext())
+	require.NoError(t, r.manager.DialFailed(ctx, seed))
+	require.Zero(t, r.manager.TryDialNext())
+
+	// once the last peer disconnects, the seed is dialed right away
+	peer := randomNodeID()
+	r.peerCh <- p2p.PeerUpdate{NodeID: peer, Status: p2p.PeerStatusUp}
+	r.peerCh <- p2p.PeerUpdate{NodeID: peer, Status: p2p.PeerStatusDown}
+	require.Eventually(t, func() bool {
+		return r.manager.TryDialNext() == seed
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestReactorIntroducerQuality(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := setupSingle(ctx, t)
+	seed := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	r.peerCh <- p2p.PeerUpdate{NodeID: seed.NodeID, Status: p2p.PeerStatusUp}
+
+	introduced := []p2p.NodeAddress{
+		{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()},
+		{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()},
+	}
+	select {
+	case req := <-r.pexOutCh:
+		_, ok := req.Message.(*p2pproto.PexRequest)
+		require.True(t, ok, "expected pex request")
+		r.pexInCh <- p2p.Envelope{
+			From: seed.NodeID,
+			Message: &p2pproto.PexResponse{Addresses: []p2pproto.PexAddress{
+				{URL: introduced[0].String()},
+				{URL: introduced[1].String()},
+			}},
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to send a request within 10 seconds")
+	}
+
+	require.Eventually(t, func() bool {
+		quality := r.reactor.IntroducerQuality()
+		return len(quality) == 1 && quality[0].Introduced == 2
+	}, 10*time.Second, 10*time.Millisecond)
+
+	r.peerCh <- p2p.PeerUpdate{NodeID: introduced[0].NodeID, Status: p2p.PeerStatusUp}
+	require.Eventually(t, func() bool {
+		quality := r.reactor.IntroducerQuality()
+		return len(quality) == 1 && quality[0].Connected == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Equal(t, []pex.IntroducerQuality{
+		{Introducer: seed.NodeID, Introduced: 2, Connected: 1, Retained: 0},
+	}, r.reactor.IntroducerQuality())
+}
+
+func TestReactorRequestAddressesCoalesced(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := setupSingle(ctx, t)
+	peer := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+
+	_, err := r.reactor.RequestAddresses(ctx, peer.NodeID)
+	require.Error(t, err, "peer is not connected")
+
+	r.peerCh <- p2p.PeerUpdate{NodeID: peer.NodeID, Status: p2p.PeerStatusUp}
+	require.Eventually(t, func() bool { return len(r.peerCh) == 0 }, time.Second, 10*time.Millisecond)
+
+	const callers = 5
+	type result struct {
+		addresses []p2p.NodeAddress
+		err       error
+	}
+	results := make(chan result, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			addresses, err := r.reactor.RequestAddresses(ctx, peer.NodeID)
+			results <- result{addresses, err}
+		}()
+	}
+
+	// Only a single request is sent, whether by one of the callers or by the
+	// reactor's own polling.
+	select {
+	case req := <-r.pexOutCh:
+		_, ok := req.Message.(*p2pproto.PexRequest)
+		require.True(t, ok, "expected pex request")
+		require.Equal(t, peer.NodeID, req.To)
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to send a request within 10 seconds")
+	}
+	time.Sleep(100 * time.Millisecond) // let all callers join the request
+	require.Empty(t, r.pexOutCh)
+
+	introduced := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	r.pexInCh <- p2p.Envelope{
+		From: peer.NodeID,
+		Message: &p2pproto.PexResponse{Addresses: []p2pproto.PexAddress{
+			{URL: introduced.String()},
+		}},
+	}
+
+	for i := 0; i < callers; i++ {
+		select {
+		case res := <-results:
+			require.NoError(t, res.err)
+			require.Equal(t, []p2p.NodeAddress{introduced}, res.addresses)
+		case <-time.After(10 * time.Second):
+			t.Fatal("caller did not receive the response within 10 seconds")
+		}
+	}
+}
+
+func TestReactorRequestAddressesPeerDown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := setupSingle(ctx, t)
+	peer := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	r.peerCh <- p2p.PeerUpdate{NodeID: peer.NodeID, Status: p2p.PeerStatusUp}
+	require.Eventually(t, func() bool { return len(r.peerCh) == 0 }, time.Second, 10*time.Millisecond)
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := r.reactor.RequestAddresses(ctx, peer.NodeID)
+		errCh <- err
+	}()
+
+	<-r.pexOutCh
+	time.Sleep(100 * time.Millisecond) // let the caller join the request
+	r.peerCh <- p2p.PeerUpdate{NodeID: peer.NodeID, Status: p2p.PeerStatusDown}
+
+	select {
+	case err := <-errCh:
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "disconnected")
+	case <-time.After(10 * time.Second):
+		t.Fatal("caller was not released within 10 seconds")
+	}
+}
+
+func TestReactorRequestsReplacementForUnresponsivePeer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := setupSingle(ctx, t)
+	a, b := randomNodeID(), randomNodeID()
+	added, err := r.manager.Add(p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: a})
+	require.NoError(t, err)
+	require.True(t, added)
+	r.peerCh <- p2p.PeerUpdate{NodeID: a, Status: p2p.PeerStatusUp}
+
+	respond := func(addresses []p2pproto.PexAddress) {
+		select {
+		case req := <-r.pexOutCh:
+			_, ok := req.Message.(*p2pproto.PexRequest)
+			require.True(t, ok, "expected pex request")
+			require.Equal(t, a, req.To)
+			r.pexInCh <- p2p.Envelope{From: a, Message: &p2pproto.PexResponse{Addresses: addresses}}
+		case <-time.After(10 * time.Second):
+			t.Fatal("pex failed to send a request within 10 seconds")
+		}
+	}
+
+	// A response full of new addresses followed by one with hardly any makes
+	// the reactor back off for a long time.
+	addresses := make([]p2pproto.PexAddress, 20)
+	for i := range addresses {
+		nodeAddress := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+		addresses[i] = p2pproto.PexAddress{URL: nodeAddress.String()}
+	}
+	respond(addresses[1:])
+	respond(addresses[:1])
+	require.Eventually(t, func() bool {
+		return len(r.manager.Peers()) == len(addresses)+1
+	}, time.Second, 10*time.Millisecond)
+
+	r.peerCh <- p2p.PeerUpdate{NodeID: b, Status: p2p.PeerStatusUp}
+	r.peerCh <- p2p.PeerUpdate{NodeID: a, Status: p2p.PeerStatusDown}
+	select {
+	case req := <-r.pexOutCh:
+		t.Fatalf("unexpected request to %v", req.To)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	// Losing an unresponsive peer triggers a request for replacements.
+	r.peerCh <- p2p.PeerUpdate{NodeID: a, Status: p2p.PeerStatusUp}
+	r.peerCh <- p2p.PeerUpdate{NodeID: a, Status: p2p.PeerStatusDown, Unresponsive: true}
+	select {
+	case req := <-r.pexOutCh:
+		_, ok := req.Message.(*p2pproto.PexRequest)
+		require.True(t, ok, "expected pex request")
+		require.Equal(t, b, req.To)
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to request replacements within 10 seconds")
+	}
+}
+
+func TestReactorSeedMode(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := setupSingle(ctx, t, pex.WithSeedMode())
+	connect := func() p2p.NodeAddress {
+		peer := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+		added, err := r.manager.Add(peer)
+		require.NoError(t, err)
+		require.True(t, added)
+		require.NoError(t, r.manager.Accepted(peer.NodeID))
+		return peer
+	}
+
+	// a peer that asks us for addresses is disconnected once it has them
+	asker := connect()
+	r.pexInCh <- p2p.Envelope{From: asker.NodeID, Message: &p2pproto.PexRequest{}}
+	select {
+	case resp := <-r.pexOutCh:
+		_, ok := resp.Message.(*p2pproto.PexResponse)
+		require.True(t, ok, "expected pex response")
+		require.Equal(t, asker.NodeID, resp.To)
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to send a response within 10 seconds")
+	}
+	require.Eventually(t, func() bool {
+		evict, err := r.manager.TryEvictNext()
+		require.NoError(t, err)
+		return evict == asker.NodeID
+	}, 10*time.Second, 10*time.Millisecond)
+	r.manager.Disconnected(ctx, asker.NodeID)
+
+	// a peer that we crawl is disconnected once it has sent us addresses
+	crawled := connect()
+	r.peerCh <- p2p.PeerUpdate{NodeID: crawled.NodeID, Status: p2p.PeerStatusUp}
+	select {
+	case req := <-r.pexOutCh:
+		_, ok := req.Message.(*p2pproto.PexRequest)
+		require.True(t, ok, "expected pex request")
+		require.Equal(t, crawled.NodeID, req.To)
+		introduced := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+		r.pexInCh <- p2p.Envelope{
+			From: crawled.NodeID,
+			Message: &p2pproto.PexResponse{Addresses: []p2pproto.PexAddress{
+				{URL: introduced.String()},
+			}},
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to send a request within 10 seconds")
+	}
+	require.Eventually(t, func() bool {
+		evict, err := r.manager.TryEvictNext()
+		require.NoError(t, err)
+		return evict == crawled.NodeID
+	}, 10*time.Second, 10*time.Millisecond)
+	reason := r.manager.EvictReason(crawled.NodeID)
+	require.Equal(t, p2pproto.DisconnectPeerLimit, reason)
+
+	// the crawled peer doesn't back off from dialing the seed again
+	seed := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: r.manager.SelfID()}
+	crawledManager, err := p2p.NewPeerManager(crawled.NodeID, dbm.NewMemDB(), p2p.PeerManagerOptions{
+		RemoteBanWindow:  time.Hour,
+		RemoteBanBackoff: time.Hour,
+	})
+	require.NoError(t, err)
+	added, err := crawledManager.Add(seed)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.Equal(t, seed, crawledManager.TryDialNext())
+	require.NoError(t, crawledManager.Dialed(seed))
+	crawledManager.RemoteDisconnected(ctx, seed.NodeID, reason)
+	crawledManager.Disconnected(ctx, seed.NodeID)
+	require.Equal(t, seed, crawledManager.TryDialNext())
+
+	status := r.reactor.CrawlStatus()
+	require.Len(t, status, 1)
+	require.Equal(t, 1, status[crawled.NodeID].Crawls)
+	require.Equal(t, 1, status[crawled.NodeID].Addresses)
+	require.False(t, status[crawled.NodeID].LastCrawled.IsZero())
+}
+
+func TestReactorSeedModeCrawlStore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := dbm.NewMemDB()
+	r := setupSingle(ctx, t, pex.WithSeedMode(), pex.WithCrawlStore(db))
+	connect := func() p2p.NodeAddress {
+		peer := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+		added, err := r.manager.Add(peer)
+		require.NoError(t, err)
+		require.True(t, added)
+		require.NoError(t, r.manager.Accepted(peer.NodeID))
+		r.peerCh <- p2p.PeerUpdate{NodeID: peer.NodeID, Status: p2p.PeerStatusUp}
+		select {
+		case req := <-r.pexOutCh:
+			require.Equal(t, peer.NodeID, req.To)
+		case <-time.After(10 * time.Second):
+			t.Fatal("pex failed to send a request within 10 seconds")
+		}
+		return peer
+	}
+
+	// a peer that disconnects before responding fails to be crawled
+	failing := connect()
+	r.peerCh <- p2p.PeerUpdate{NodeID: failing.NodeID, Status: p2p.PeerStatusDown}
+	r.manager.Disconnected(ctx, failing.NodeID)
+	require.Eventually(t, func() bool {
+		return r.reactor.CrawlStatus()[failing.NodeID].ConsecutiveFailures == 1
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// a peer that responds is crawled, along with its reported moniker
+	good := connect()
+	r.manager.SetNodeInfo(types.NodeInfo{NodeID: good.NodeID, Moniker: "good", Version: "1.2.3"})
+	introduced := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	r.pexInCh <- p2p.Envelope{
+		From:    good.NodeID,
+		Message: &p2pproto.PexResponse{Addresses: []p2pproto.PexAddress{{URL: introduced.String()}}},
+	}
+	require.Eventually(t, func() bool {
+		return r.reactor.CrawlStatus()[good.NodeID].Crawls == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	status := r.reactor.CrawlStatus()
+	require.Equal(t, "good", status[good.NodeID].Moniker)
+	require.Equal(t, "1.2.3", status[good.NodeID].Version)
+	require.Zero(t, status[good.NodeID].ConsecutiveFailures)
+	require.False(t, status[failing.NodeID].LastFailed.IsZero())
+
+	// peers are handed out best first: crawled, not crawled, failing
+	asker := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	_, err := r.manager.Add(asker)
+	require.NoError(t, err)
+	require.NoError(t, r.manager.Accepted(asker.NodeID))
+	r.pexInCh <- p2p.Envelope{From: asker.NodeID, Message: &p2pproto.PexRequest{}}
+	select {
+	case resp := <-r.pexOutCh:
+		msg, ok := resp.Message.(*p2pproto.PexResponse)
+		require.True(t, ok, "expected pex response")
+		var order []string
+		for _, addr := range msg.Addresses {
+			switch addr.URL {
+			case good.String(), introduced.String(), failing.String():
+				order = append(order, addr.URL)
+			}
+		}
+		require.Equal(t, []string{good.String(), introduced.String(), failing.String()}, order)
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to send a response within 10 seconds")
+	}
+
+	// the crawl status is loaded from the store on restart
+	chDesc := pex.ChannelDescriptor()
+	pexCh := p2p.NewChannel(chDesc.ID, chDesc.Name, make(chan p2p.Envelope), make(chan p2p.Envelope), make(chan p2p.PeerError))
+	restarted := pex.NewReactor(log.NewNopLogger(), r.manager,
+		func(context.Context, *p2p.ChannelDescriptor) (p2p.Channel, error) { return pexCh, nil },
+		func(context.Context) *p2p.PeerUpdates { return p2p.NewPeerUpdates(make(chan p2p.PeerUpdate), 1) },
+		pex.WithSeedMode(), pex.WithCrawlStore(db))
+	require.NoError(t, restarted.Start(ctx))
+	t.Cleanup(restarted.Wait)
+
+	loaded := restarted.CrawlStatus()
+	require.Len(t, loaded, 2)
+	require.Equal(t, 1, loaded[good.NodeID].Crawls)
+	require.Equal(t, 1, loaded[good.NodeID].Addresses)
+	require.Equal(t, "good", loaded[good.NodeID].Moniker)
+	require.True(t, status[good.NodeID].LastCrawled.Equal(loaded[good.NodeID].LastCrawled))
+	require.True(t, loaded[good.NodeID].LastFailed.IsZero())
+	require.Equal(t, 1, loaded[failing.NodeID].ConsecutiveFailures)
+}
+
+func TestReactorReportsGoodAddressSources(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pexInCh := make(chan p2p.Envelope, 1)
+	pexOutCh := make(chan p2p.Envelope, 1)
+	chDesc := pex.ChannelDescriptor()
+	pexCh := p2p.NewChannel(chDesc.ID, chDesc.Name, pexInCh, pexOutCh, make(chan p2p.PeerError, 1))
+
+	peerManager, err := p2p.NewPeerManager(newNodeID(t, "a"), dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	reactor := pex.NewReactor(
+		log.NewNopLogger(),
+		peerManager,
+		func(context.Context, *p2p.ChannelDescriptor) (p2p.Channel, error) { return pexCh, nil },
+		peerManager.Subscribe,
+	)
+	require.NoError(t, reactor.Start(ctx))
+	t.Cleanup(reactor.Wait)
+
+	peer := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	added, err := peerManager.Add(peer)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, peerManager.Accepted(peer.NodeID))
+	peerManager.Ready(ctx, peer.NodeID, nil)
+
+	addresses := make([]p2pproto.PexAddress, 100)
+	for i := range addresses {
+		addresses[i].URL = p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}.String()
+	}
+	select {
+	case req := <-pexOutCh:
+		_, ok := req.Message.(*p2pproto.PexRequest)
+		require.True(t, ok, "expected pex request")
+		pexInCh <- p2p.Envelope{From: peer.NodeID, Message: &p2pproto.PexResponse{Addresses: addresses}}
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to send a request within 10 seconds")
+	}
+
+	require.Eventually(t, func() bool {
+		return peerManager.Score(peer.NodeID) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestReactorIgnoresLowScoredAddressSources(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := setupSingle(ctx, t)
+	peer := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	added, err := r.manager.Add(peer)
+	require.NoError(t, err)
+	require.True(t, added)
+	for i := 0; i < 11; i++ {
+		require.NoError(t, r.manager.DialFailed(ctx, peer))
+	}
+	require.Less(t, int(r.manager.Score(peer.NodeID)), -10)
+
+	r.peerCh <- p2p.PeerUpdate{NodeID: peer.NodeID, Status: p2p.PeerStatusUp}
+	introduced := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	select {
+	case req := <-r.pexOutCh:
+		_, ok := req.Message.(*p2pproto.PexRequest)
+		require.True(t, ok, "expected pex request")
+		r.pexInCh <- p2p.Envelope{
+			From: peer.NodeID,
+			Message: &p2pproto.PexResponse{Addresses: []p2pproto.PexAddress{
+				{URL: introduced.String()},
+			}},
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to send a request within 10 seconds")
+	}
+
+	// the response is handled once the peer is available for requests again
+	select {
+	case <-r.pexOutCh:
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to send a request within 10 seconds")
+	}
+	require.NotContains(t, r.manager.Peers(), introduced.NodeID)
+	require.Empty(t, r.pexErrCh)
+}
+
+func TestReactorLastCycleSkips(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := setupSingle(ctx, t)
+	banned := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	added, err := r.manager.Add(banned)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, r.manager.Ban(banned.NodeID, time.Hour))
+	require.Zero(t, r.manager.TryDialNext())
+
+	self := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: r.manager.SelfID()}
+	noPort := p2p.NodeAddress{Protocol: p2p.TCPProtocol, NodeID: randomNodeID(), Hostname: "1.2.3.4"}
+
+	peer := randomNodeID()
+	r.peerCh <- p2p.PeerUpdate{NodeID: peer, Status: p2p.PeerStatusUp}
+	select {
+	case req := <-r.pexOutCh:
+		_, ok := req.Message.(*p2pproto.PexRequest)
+		require.True(t, ok, "expected pex request")
+		r.pexInCh <- p2p.Envelope{
+			From: peer,
+			Message: &p2pproto.PexResponse{Addresses: []p2pproto.PexAddress{
+				{URL: self.String()},
+				{URL: noPort.String()},
+			}},
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to send a request within 10 seconds")
+	}
+
+	require.Eventually(t, func() bool {
+		return len(r.reactor.LastCycleSkips()) == 3
+	}, 5*time.Second, 5*time.Millisecond)
+	require.ElementsMatch(t, []pex.SkipRecord{
+		{Address: self, Reason: p2p.DialSkipSelf},
+		{Address: noPort, Reason: p2p.DialSkipFiltered},
+		{Address: banned, Reason: p2p.DialSkipBanList},
+	}, r.reactor.LastCycleSkips())
+
+	// the records of dropped addresses are reset by the next cycle
+	select {
+	case <-r.pexOutCh:
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to send a request within 10 seconds")
+	}
+	require.Equal(t, []pex.SkipRecord{
+		{Address: banned, Reason: p2p.DialSkipBanList},
+	}, r.reactor.LastCycleSkips())
+}
+
+func TestReactorPeerAgeHistogram(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := setupSingle(ctx, t)
+	hist := r.reactor.PeerAgeHistogram()
+	for _, bucket := range hist {
+		require.Zero(t, bucket.Count)
+	}
+
+	for i := 0; i < 3; i++ {
+		peer := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+		added, err := r.manager.Add(peer)
+		require.NoError(t, err)
+		require.True(t, added)
+		require.NoError(t, r.manager.Accepted(peer.NodeID))
+	}
+
+	// freshly connected peers all fall into the youngest bucket
+	hist = r.reactor.PeerAgeHistogram()
+	require.Equal(t, time.Minute, hist[0].MaxAge)
+	require.Equal(t, 3, hist[0].Count)
+	for _, bucket := range hist[1:] {
+		require.Zero(t, bucket.Count)
+	}
+}
+
+// stuckChannel is a p2p.Channel whose Send blocks, ignoring the context,
+// until unblock is closed.
+type stuckChannel struct {
+	p2p.Channel
+	sending chan struct{}
+	unblock chan struct{}
+}
+
+func (ch *stuckChannel) Send(context.Context, p2p.Envelope) error {
+	select {
+	case ch.sending <- struct{}{}:
+	default:
+	}
+	<-ch.unblock
+	return nil
+}
+
+func TestReactorStopTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	chDesc := pex.ChannelDescriptor()
+	pexCh := &stuckChannel{
+		Channel: p2p.NewChannel(chDesc.ID, chDesc.Name,
+			make(chan p2p.Envelope), make(chan p2p.Envelope), make(chan p2p.PeerError)),
+		sending: make(chan struct{}, 1),
+		unblock: make(chan struct{}),
+	}
+	defer close(pexCh.unblock)
+
+	peerCh := make(chan p2p.PeerUpdate, 1)
+	peerUpdates := p2p.NewPeerUpdates(peerCh, 1)
+	peerManager, err := p2p.NewPeerManager(newNodeID(t, "a"), dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+
+	const stopTimeout = 100 * time.Millisecond
+	reactor := pex.NewReactor(
+		log.NewNopLogger(),
+		peerManager,
+		func(context.Context, *p2p.ChannelDescriptor) (p2p.Channel, error) { return pexCh, nil },
+		func(context.Context) *p2p.PeerUpdates { return peerUpdates },
+		pex.WithStopTimeout(stopTimeout),
+	)
+	require.NoError(t, reactor.Start(ctx))
+
+	// make a peer available, so the reactor sends it a request which never
+	// completes
+	peerCh <- p2p.PeerUpdate{NodeID: randomNodeID(), Status: p2p.PeerStatusUp}
+	select {
+	case <-pexCh.sending:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "reactor did not send a PEX request")
+	}
+
+	start := time.Now()
+	reactor.Stop()
+	require.GreaterOrEqual(t, time.Since(start), stopTimeout)
+	require.Less(t, time.Since(start), stopTimeout+time.Second)
 }
 
 func TestReactorSmallPeerStoreInALargeNetwork(t *testing.T) {
@@ -280,7 +1130,7 @@ type singleTestReactor struct {
 	manager  *p2p.PeerManager
 }
 
-func setupSingle(ctx context.Context, t *testing.T) *singleTestReactor {
+func setupSingle(ctx context.Context, t *testing.T, opts ...pex.ReactorOption) *singleTestReactor {
 	t.Helper()
 	nodeID := newNodeID(t, "a")
 	chBuf := 2
@@ -306,7 +1156,7 @@ func setupSingle(ctx context.Context, t *testing.T) *singleTestReactor {
 		return pexCh, nil
 	}
 
-	reactor := pex.NewReactor(log.NewNopLogger(), peerManager, chCreator, func(_ context.Context) *p2p.PeerUpdates { return peerUpdates })
+	reactor := pex.NewReactor(log.NewNopLogger(), peerManager, chCreator, func(_ context.Context) *p2p.PeerUpdates { return peerUpdates }, opts...)
 
 	require.NoError(t, reactor.Start(ctx))
 	t.Cleanup(reactor.Wait)
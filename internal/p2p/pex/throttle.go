@@ -0,0 +1,80 @@
+package pex
+
+import (
+	"runtime"
+	"time"
+)
+
+// requestThrottle tracks the rate of PEX requests received from all peers and
+// the CPU used by the process, and decides whether the reactor should coarsen
+// its handling of PEX messages to cap the CPU spent on PEX. Throttling engages
+// as soon as the request rate exceeds its target, or once a window's CPU usage
+// does, and relaxes once a full window's request rate and CPU usage drop to
+// half their targets.
+type requestThrottle struct {
+	target    int     // requests per second, 0 disables the request rate trigger
+	cpuTarget float64 // fraction of the available CPU, 0 disables the CPU trigger
+
+	// cpuTime returns the CPU time used by the process so far.
+	cpuTime func() time.Duration
+
+	windowStart time.Time
+	windowCPU   time.Duration
+	count       int
+	throttled   bool
+}
+
+// observe records a request received at the given time, and returns whether
+// requests are being throttled.
+func (t *requestThrottle) observe(now time.Time) bool {
+	t.update(now)
+	if t.target <= 0 {
+		return t.throttled
+	}
+
+	t.count++
+	if t.count > t.target {
+		t.throttled = true
+	}
+	return t.throttled
+}
+
+// update starts a new window once the current one has lasted a second, and
+// returns whether requests are being throttled.
+func (t *requestThrottle) update(now time.Time) bool {
+	if t.target <= 0 && t.cpuTarget <= 0 {
+		return false
+	}
+
+	elapsed := now.Sub(t.windowStart)
+	if elapsed < time.Second {
+		return t.throttled
+	}
+
+	rateLow := true
+	if t.target > 0 {
+		rateLow = float64(t.count)/elapsed.Seconds() <= float64(t.target)/2
+	}
+
+	cpuLow := true
+	var cpuTime time.Duration
+	if t.cpuTarget > 0 && t.cpuTime != nil {
+		cpuTime = t.cpuTime()
+		// The first window has no CPU baseline to compare with.
+		if !t.windowStart.IsZero() {
+			usage := float64(cpuTime-t.windowCPU) / float64(elapsed) / float64(runtime.NumCPU())
+			if usage > t.cpuTarget {
+				t.throttled = true
+			}
+			cpuLow = usage <= t.cpuTarget/2
+		}
+	}
+
+	if t.throttled && rateLow && cpuLow {
+		t.throttled = false
+	}
+	t.windowStart = now
+	t.windowCPU = cpuTime
+	t.count = 0
+	return t.throttled
+}
@@ -0,0 +1,187 @@
+package pex
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/libs/log"
+	p2pproto "github.com/tendermint/tendermint/proto/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestRequestThrottle(t *testing.T) {
+	throttle := requestThrottle{target: 10}
+	now := time.Now()
+
+	// low load isn't throttled
+	for i := 0; i < 10; i++ {
+		require.False(t, throttle.observe(now))
+	}
+
+	// exceeding the target engages throttling immediately
+	require.True(t, throttle.observe(now))
+
+	// a window with a rate above half the target keeps it engaged
+	now = now.Add(time.Second)
+	for i := 0; i < 8; i++ {
+		require.True(t, throttle.observe(now))
+	}
+
+	// once a window's rate drops to half the target, it relaxes
+	now = now.Add(2 * time.Second)
+	require.False(t, throttle.observe(now))
+	for i := 0; i < 9; i++ {
+		require.False(t, throttle.observe(now))
+	}
+}
+
+func TestRequestThrottle_Disabled(t *testing.T) {
+	throttle := requestThrottle{}
+	now := time.Now()
+	for i := 0; i < 1000; i++ {
+		require.False(t, throttle.observe(now))
+	}
+}
+
+func TestRequestThrottle_CPU(t *testing.T) {
+	var cpu time.Duration
+	throttle := requestThrottle{
+		cpuTarget: 0.5,
+		cpuTime:   func() time.Duration { return cpu },
+	}
+	now := time.Now()
+	require.False(t, throttle.update(now))
+
+	// a window using more than the target engages throttling, even without
+	// any requests
+	cpu += time.Duration(runtime.NumCPU()) * 600 * time.Millisecond
+	now = now.Add(time.Second)
+	require.True(t, throttle.update(now))
+
+	// a window using more than half the target keeps it engaged
+	cpu += time.Duration(runtime.NumCPU()) * 300 * time.Millisecond
+	now = now.Add(time.Second)
+	require.True(t, throttle.update(now))
+
+	// once a window's usage drops to half the target, it relaxes
+	cpu += time.Duration(runtime.NumCPU()) * 200 * time.Millisecond
+	now = now.Add(time.Second)
+	require.False(t, throttle.update(now))
+}
+
+func TestReactorBatchesAddsWhileThrottled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := newAdvertiseTestReactor(t, 1, 0)
+	now := time.Now()
+	r.throttle.windowStart = now
+	r.throttle.throttled = true
+
+	addr := func(i int) p2p.NodeAddress {
+		return p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: types.NodeID(fmt.Sprintf("%040x", i))}
+	}
+	respond := func(from types.NodeID, addrs ...p2p.NodeAddress) {
+		r.requestsSent[from] = struct{}{}
+		resp := &p2pproto.PexResponse{}
+		for _, a := range addrs {
+			resp.Addresses = append(resp.Addresses, p2pproto.PexAddress{URL: a.String()})
+		}
+		accepted, _, err := r.handlePexResponse(ctx, from, resp, nil)
+		require.NoError(t, err)
+		require.Equal(t, addrs, accepted)
+	}
+	peerA := types.NodeID(strings.Repeat("a", 40))
+	peerB := types.NodeID(strings.Repeat("b", 40))
+
+	// while throttled, addresses are queued and deduplicated, crediting the
+	// peer that sent each first
+	respond(peerA, addr(1), addr(2))
+	respond(peerB, addr(2), addr(3))
+	require.Len(t, r.pendingAdds, 3)
+	require.Equal(t, peerA, r.pendingAdds[addr(2)])
+	require.Empty(t, r.peerManager.Peers())
+
+	// they're added once they've been queued long enough
+	require.Zero(t, r.flushPendingAdds(ctx, now, false))
+	require.Equal(t, 3, r.flushPendingAdds(ctx, r.pendingSince.Add(advertiseCacheTTL), false))
+	require.Empty(t, r.pendingAdds)
+	require.Len(t, r.peerManager.Peers(), 3)
+	require.Equal(t, 2, r.addressesContributed[peerA])
+	require.Equal(t, 1, r.addressesContributed[peerB])
+
+	// or as soon as throttling relaxes
+	respond(peerA, addr(4))
+	require.Len(t, r.pendingAdds, 1)
+	r.throttle.throttled = false
+	require.Equal(t, 1, r.flushPendingAdds(ctx, now, false))
+	require.Empty(t, r.pendingAdds)
+
+	// without throttling, addresses are added right away
+	respond(peerB, addr(5))
+	require.Empty(t, r.pendingAdds)
+	require.Len(t, r.peerManager.Peers(), 5)
+}
+
+func TestReactorAdvertiseThrottled(t *testing.T) {
+	r := newAdvertiseTestReactor(t, 1, 10)
+	requester := types.NodeID(strings.Repeat("f", 40))
+
+	r.advertise(requester)
+	require.False(t, r.throttle.throttled)
+	before := r.advertise(requester)
+	require.True(t, r.throttle.throttled)
+	require.Len(t, before, 10)
+
+	// while throttled, new addresses are not advertised until the cached
+	// addresses expire
+	peer := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: types.NodeID(strings.Repeat("e", 40))}
+	added, err := r.peerManager.Add(peer)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.Equal(t, before, r.advertise(requester))
+
+	r.advertisedAt = time.Time{}
+	require.Contains(t, r.advertise(requester), peer)
+
+	// peers are never sent their own address
+	require.NotContains(t, r.advertise(peer.NodeID), peer)
+}
+
+// newAdvertiseTestReactor returns a reactor with the given request rate
+// target, whose peer manager knows about the given number of peers.
+func newAdvertiseTestReactor(t testing.TB, target, peers int) *Reactor {
+	peerManager, err := p2p.NewPeerManager(types.NodeID(strings.Repeat("0", 40)), dbm.NewMemDB(),
+		p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	for i := 0; i < peers; i++ {
+		added, err := peerManager.Add(p2p.NodeAddress{
+			Protocol: p2p.MemoryProtocol,
+			NodeID:   types.NodeID(fmt.Sprintf("%040x", i+1)),
+		})
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	return NewReactor(log.NewNopLogger(), peerManager, nil, nil, WithRequestRateTarget(target))
+}
+
+func BenchmarkReactorAdvertise(b *testing.B) {
+	for _, target := range []int{0, 1} {
+		b.Run(fmt.Sprintf("target=%d", target), func(b *testing.B) {
+			r := newAdvertiseTestReactor(b, target, 1000)
+			requester := types.NodeID(strings.Repeat("f", 40))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				r.advertise(requester)
+			}
+		})
+	}
+}
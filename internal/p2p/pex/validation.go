@@ -0,0 +1,139 @@
+package pex
+
+import (
+	"net"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+// AddressVerdict is the outcome of running an address received via PEX
+// through an AddressValidator.
+type AddressVerdict int
+
+const (
+	// AddressAccept passes the address on to the next validator, and
+	// eventually to the peer manager.
+	AddressAccept AddressVerdict = iota
+	// AddressReject drops the address.
+	AddressReject
+	// AddressPenalize drops the address and reports the peer that sent it.
+	AddressPenalize
+)
+
+func (v AddressVerdict) String() string {
+	switch v {
+	case AddressAccept:
+		return "accept"
+	case AddressReject:
+		return "reject"
+	case AddressPenalize:
+		return "penalize"
+	default:
+		return "unknown"
+	}
+}
+
+// AddressValidator inspects an address sent to us by a peer and decides
+// whether it should be added to the peer manager.
+type AddressValidator func(from types.NodeID, addr p2p.NodeAddress) AddressVerdict
+
+// AddressPipeline is an ordered list of address validators. An address is
+// accepted only if every validator accepts it, and the first validator which
+// doesn't accept it determines the verdict.
+type AddressPipeline struct {
+	validators []AddressValidator
+}
+
+// NewAddressPipeline returns a pipeline which runs the given validators in
+// order.
+func NewAddressPipeline(validators ...AddressValidator) *AddressPipeline {
+	return &AddressPipeline{validators: validators}
+}
+
+// Append adds validators to the end of the pipeline.
+func (p *AddressPipeline) Append(validators ...AddressValidator) {
+	p.validators = append(p.validators, validators...)
+}
+
+// Validate runs an address through the pipeline.
+func (p *AddressPipeline) Validate(from types.NodeID, addr p2p.NodeAddress) AddressVerdict {
+	for _, validate := range p.validators {
+		if verdict := validate(from, addr); verdict != AddressAccept {
+			return verdict
+		}
+	}
+	return AddressAccept
+}
+
+// DefaultAddressPipeline returns the validators that the reactor applies
+// to every address, for a node with the given ID.
+func DefaultAddressPipeline(selfID types.NodeID) *AddressPipeline {
+	return NewAddressPipeline(
+		ValidateAddressSize(maxAddressSize),
+		ValidateAddressPort(),
+		ValidateAddressNotSelf(selfID),
+	)
+}
+
+// ValidateAddressSize penalizes peers that send addresses longer than
+// maxSize bytes when encoded.
+func ValidateAddressSize(maxSize int) AddressValidator {
+	return func(_ types.NodeID, addr p2p.NodeAddress) AddressVerdict {
+		if len(addr.String()) > maxSize {
+			return AddressPenalize
+		}
+		return AddressAccept
+	}
+}
+
+// ValidateAddressPort rejects TCP addresses without a port, since they
+// can't be dialed.
+func ValidateAddressPort() AddressValidator {
+	return func(_ types.NodeID, addr p2p.NodeAddress) AddressVerdict {
+		switch addr.Protocol {
+		case p2p.TCPProtocol, p2p.MConnProtocol:
+			if addr.Port == 0 {
+				return AddressReject
+			}
+		}
+		return AddressAccept
+	}
+}
+
+// ValidateAddressNotSelf rejects addresses of the local node.
+func ValidateAddressNotSelf(selfID types.NodeID) AddressValidator {
+	return func(_ types.NodeID, addr p2p.NodeAddress) AddressVerdict {
+		if addr.NodeID == selfID {
+			return AddressReject
+		}
+		return AddressAccept
+	}
+}
+
+// ValidateAddressAllowed rejects addresses of peers that allowed doesn't
+// accept, e.g. peers outside the allowlist of a permissioned network.
+func ValidateAddressAllowed(allowed func(types.NodeID) bool) AddressValidator {
+	return func(_ types.NodeID, addr p2p.NodeAddress) AddressVerdict {
+		if !allowed(addr.NodeID) {
+			return AddressReject
+		}
+		return AddressAccept
+	}
+}
+
+// ValidateAddressNotPrivate rejects addresses with loopback, link-local or
+// private IPs, which are generally not reachable by other nodes. Hostnames
+// are not resolved, and are always accepted.
+func ValidateAddressNotPrivate() AddressValidator {
+	return func(_ types.NodeID, addr p2p.NodeAddress) AddressVerdict {
+		ip := net.ParseIP(addr.Hostname)
+		if ip == nil {
+			return AddressAccept
+		}
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+			return AddressReject
+		}
+		return AddressAccept
+	}
+}
@@ -0,0 +1,246 @@
+package pex_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/internal/p2p/pex"
+	p2pproto "github.com/tendermint/tendermint/proto/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestAddressPipeline(t *testing.T) {
+	from := randomNodeID()
+	addr := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+
+	var calls []string
+	record := func(name string, verdict pex.AddressVerdict) pex.AddressValidator {
+		return func(types.NodeID, p2p.NodeAddress) pex.AddressVerdict {
+			calls = append(calls, name)
+			return verdict
+		}
+	}
+
+	testcases := map[string]struct {
+		validators []pex.AddressValidator
+		verdict    pex.AddressVerdict
+		calls      []string
+	}{
+		"empty pipeline accepts": {nil, pex.AddressAccept, nil},
+		"all accept": {
+			[]pex.AddressValidator{record("a", pex.AddressAccept), record("b", pex.AddressAccept)},
+			pex.AddressAccept,
+			[]string{"a", "b"},
+		},
+		"reject stops pipeline": {
+			[]pex.AddressValidator{
+				record("a", pex.AddressAccept),
+				record("b", pex.AddressReject),
+				record("c", pex.AddressPenalize),
+			},
+			pex.AddressReject,
+			[]string{"a", "b"},
+		},
+		"penalize stops pipeline": {
+			[]pex.AddressValidator{
+				record("a", pex.AddressPenalize),
+				record("b", pex.AddressReject),
+			},
+			pex.AddressPenalize,
+			[]string{"a"},
+		},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			calls = nil
+			pipeline := pex.NewAddressPipeline(tc.validators...)
+			require.Equal(t, tc.verdict, pipeline.Validate(from, addr))
+			require.Equal(t, tc.calls, calls)
+		})
+	}
+}
+
+func TestAddressPipeline_Append(t *testing.T) {
+	from := randomNodeID()
+	addr := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+
+	pipeline := pex.NewAddressPipeline(func(types.NodeID, p2p.NodeAddress) pex.AddressVerdict {
+		return pex.AddressAccept
+	})
+	require.Equal(t, pex.AddressAccept, pipeline.Validate(from, addr))
+
+	// custom validators run after the existing ones
+	pipeline.Append(func(f types.NodeID, a p2p.NodeAddress) pex.AddressVerdict {
+		require.Equal(t, from, f)
+		require.Equal(t, addr, a)
+		return pex.AddressPenalize
+	})
+	require.Equal(t, pex.AddressPenalize, pipeline.Validate(from, addr))
+}
+
+func TestDefaultAddressPipeline(t *testing.T) {
+	self := newNodeID(t, "a")
+	from := randomNodeID()
+	id := randomNodeID()
+
+	testcases := map[string]struct {
+		addr    p2p.NodeAddress
+		verdict pex.AddressVerdict
+	}{
+		"memory address": {
+			p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: id}, pex.AddressAccept},
+		"memory address with placeholder IP": {
+			p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: id, Hostname: "0.0.0.0"}, pex.AddressAccept},
+		"tcp address": {
+			p2p.NodeAddress{Protocol: p2p.TCPProtocol, NodeID: id, Hostname: "1.2.3.4", Port: 26656}, pex.AddressAccept},
+		"port zero": {
+			p2p.NodeAddress{Protocol: p2p.TCPProtocol, NodeID: id, Hostname: "1.2.3.4"}, pex.AddressReject},
+		"self": {
+			p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: self}, pex.AddressReject},
+		"oversized": {
+			p2p.NodeAddress{Protocol: p2p.TCPProtocol, NodeID: id, Hostname: strings.Repeat("a", 300), Port: 26656},
+			pex.AddressPenalize},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.verdict, pex.DefaultAddressPipeline(self).Validate(from, tc.addr))
+		})
+	}
+}
+
+func TestValidateAddressNotPrivate(t *testing.T) {
+	validate := pex.ValidateAddressNotPrivate()
+	from := randomNodeID()
+	id := randomNodeID()
+
+	for host, verdict := range map[string]pex.AddressVerdict{
+		"8.8.8.8":      pex.AddressAccept,
+		"example.com":  pex.AddressAccept,
+		"127.0.0.1":    pex.AddressReject,
+		"10.0.0.1":     pex.AddressReject,
+		"192.168.1.10": pex.AddressReject,
+		"fe80::1":      pex.AddressReject,
+		"0.0.0.0":      pex.AddressReject,
+	} {
+		addr := p2p.NodeAddress{Protocol: p2p.TCPProtocol, NodeID: id, Hostname: host, Port: 26656}
+		require.Equal(t, verdict, validate(from, addr), host)
+	}
+}
+
+func TestValidateAddressAllowed(t *testing.T) {
+	from := randomNodeID()
+	allowed := randomNodeID()
+	validate := pex.ValidateAddressAllowed(func(id types.NodeID) bool { return id == allowed })
+
+	addr := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: allowed}
+	require.Equal(t, pex.AddressAccept, validate(from, addr))
+	addr.NodeID = randomNodeID()
+	require.Equal(t, pex.AddressReject, validate(from, addr))
+}
+
+func TestReactorAddressValidation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	blocked := randomNodeID()
+	r := setupSingle(ctx, t, pex.WithAddressValidators(
+		func(_ types.NodeID, addr p2p.NodeAddress) pex.AddressVerdict {
+			if addr.NodeID == blocked {
+				return pex.AddressReject
+			}
+			return pex.AddressAccept
+		},
+	))
+	peer := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	added, err := r.manager.Add(peer)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	good := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	oversized := fmt.Sprintf("tcp://%s@%s.com:26656", randomNodeID(), strings.Repeat("a", 300))
+	addresses := []p2pproto.PexAddress{
+		{URL: good.String()},
+		{URL: p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: blocked}.String()},
+		{URL: oversized},
+	}
+
+	r.peerCh <- p2p.PeerUpdate{
+		NodeID: peer.NodeID,
+		Status: p2p.PeerStatusUp,
+	}
+
+	select {
+	case req := <-r.pexOutCh:
+		_, ok := req.Message.(*p2pproto.PexRequest)
+		require.True(t, ok)
+		r.pexInCh <- p2p.Envelope{
+			From:    peer.NodeID,
+			Message: &p2pproto.PexResponse{Addresses: addresses},
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to send a request within 10 seconds")
+	}
+
+	// only the good address makes it into the peer manager
+	require.Eventually(t, func() bool {
+		return len(r.manager.Peers()) == 2
+	}, 10*time.Second, 10*time.Millisecond)
+	peers := r.manager.Peers()
+	require.Contains(t, peers, good.NodeID)
+	require.NotContains(t, peers, blocked)
+
+	// a single oversized address isn't enough to get the sender penalized,
+	// since it's usually just relaying it
+	require.Empty(t, r.pexErrCh)
+	require.False(t, r.manager.IsBanned(peer.NodeID))
+}
+
+func TestReactorPenalizesMostlyInvalidAddresses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := setupSingle(ctx, t)
+	peer := p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}
+	added, err := r.manager.Add(peer)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	addresses := []p2pproto.PexAddress{
+		{URL: p2p.NodeAddress{Protocol: p2p.MemoryProtocol, NodeID: randomNodeID()}.String()},
+	}
+	for i := 0; i < 3; i++ {
+		addresses = append(addresses, p2pproto.PexAddress{
+			URL: fmt.Sprintf("tcp://%s@%s.com:26656", randomNodeID(), strings.Repeat("a", 300)),
+		})
+	}
+
+	r.peerCh <- p2p.PeerUpdate{
+		NodeID: peer.NodeID,
+		Status: p2p.PeerStatusUp,
+	}
+
+	select {
+	case req := <-r.pexOutCh:
+		_, ok := req.Message.(*p2pproto.PexRequest)
+		require.True(t, ok)
+		r.pexInCh <- p2p.Envelope{
+			From:    peer.NodeID,
+			Message: &p2pproto.PexResponse{Addresses: addresses},
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("pex failed to send a request within 10 seconds")
+	}
+
+	peerErr := <-r.pexErrCh
+	require.Equal(t, peer.NodeID, peerErr.NodeID)
+	require.Contains(t, peerErr.Err.Error(), "peer sent 3 invalid addresses")
+	require.True(t, r.manager.IsBanned(peer.NodeID))
+}
@@ -0,0 +1,151 @@
+package p2p
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// rateLimitViolationWindow is the window over which rate limit violations are
+// counted towards a peer's sustained violation limit.
+const rateLimitViolationWindow = time.Minute
+
+// RateLimit is a token bucket rate limit for messages.
+type RateLimit struct {
+	// Rate is the number of messages per second a peer may send on average.
+	// 0 disables the limit.
+	Rate float64
+
+	// Burst is the number of messages a peer may send at once, above the
+	// average rate. Defaults to 1.
+	Burst int
+}
+
+// ErrRateLimited is returned by PeerRateLimiter when a peer exceeds its rate
+// limit. Sustained is set once the peer has exceeded the limit too many
+// times, at which point it should be disconnected.
+type ErrRateLimited struct {
+	PeerID     types.NodeID
+	Violations int
+	Sustained  bool
+}
+
+func (e ErrRateLimited) Error() string {
+	if e.Sustained {
+		return fmt.Sprintf("peer %v exceeded rate limit %d times within %v", e.PeerID, e.Violations,
+			rateLimitViolationWindow)
+	}
+	return fmt.Sprintf("peer %v exceeded rate limit", e.PeerID)
+}
+
+// PeerRateLimiter rate limits messages per peer using a token bucket for each
+// peer. Reactors can use it for their own messages, and the router attaches
+// one to each channel with a receive rate limit.
+type PeerRateLimiter struct {
+	limit         RateLimit
+	maxViolations int
+
+	mtx     sync.Mutex
+	buckets map[types.NodeID]*rateBucket
+}
+
+type rateBucket struct {
+	tokens      float64
+	last        time.Time
+	violations  int
+	windowStart time.Time
+	released    bool
+}
+
+// NewPeerRateLimiter creates a new rate limiter. Once a peer has exceeded the
+// limit maxViolations times within a minute, the violations are reported as
+// sustained. maxViolations of 0 means violations are never sustained.
+func NewPeerRateLimiter(limit RateLimit, maxViolations int) *PeerRateLimiter {
+	if limit.Burst <= 0 {
+		limit.Burst = 1
+	}
+	return &PeerRateLimiter{
+		limit:         limit,
+		maxViolations: maxViolations,
+		buckets:       make(map[types.NodeID]*rateBucket),
+	}
+}
+
+// Allow takes a token from the peer's bucket, and returns an ErrRateLimited
+// error if there are none left.
+func (l *PeerRateLimiter) Allow(peerID types.NodeID) error {
+	return l.allowAt(peerID, time.Now())
+}
+
+func (l *PeerRateLimiter) allowAt(peerID types.NodeID, now time.Time) error {
+	if l.limit.Rate <= 0 {
+		return nil
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	bucket, ok := l.buckets[peerID]
+	if !ok {
+		bucket = &rateBucket{tokens: float64(l.limit.Burst), last: now}
+		l.buckets[peerID] = bucket
+	}
+	bucket.released = false
+
+	if elapsed := now.Sub(bucket.last); elapsed > 0 {
+		bucket.tokens += elapsed.Seconds() * l.limit.Rate
+		if bucket.tokens > float64(l.limit.Burst) {
+			bucket.tokens = float64(l.limit.Burst)
+		}
+		bucket.last = now
+	}
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return nil
+	}
+
+	if now.Sub(bucket.windowStart) > rateLimitViolationWindow {
+		bucket.windowStart = now
+		bucket.violations = 0
+	}
+	bucket.violations++
+
+	return ErrRateLimited{
+		PeerID:     peerID,
+		Violations: bucket.violations,
+		Sustained:  l.maxViolations > 0 && bucket.violations >= l.maxViolations,
+	}
+}
+
+// RemovePeer forgets a peer's state, e.g. once it has disconnected.
+func (l *PeerRateLimiter) RemovePeer(peerID types.NodeID) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	delete(l.buckets, peerID)
+}
+
+// ReleasePeer forgets a peer's state once it has disconnected, unless it
+// exceeded the limit within the last minute. Its violations are then kept
+// until the window ends, such that those of peers that keep reconnecting add
+// up to a sustained violation.
+func (l *PeerRateLimiter) ReleasePeer(peerID types.NodeID) {
+	l.releasePeerAt(peerID, time.Now())
+}
+
+func (l *PeerRateLimiter) releasePeerAt(peerID types.NodeID, now time.Time) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if bucket, ok := l.buckets[peerID]; ok {
+		bucket.released = true
+	}
+	for id, bucket := range l.buckets {
+		if bucket.released && (bucket.violations == 0 ||
+			now.Sub(bucket.windowStart) > rateLimitViolationWindow) {
+			delete(l.buckets, id)
+		}
+	}
+}
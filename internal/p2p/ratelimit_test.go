@@ -0,0 +1,99 @@
+package p2p
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestPeerRateLimiter(t *testing.T) {
+	a := types.NodeID("aa")
+	b := types.NodeID("bb")
+	now := time.Now()
+
+	limiter := NewPeerRateLimiter(RateLimit{Rate: 10, Burst: 2}, 0)
+
+	// The burst is allowed, after which peers must wait for tokens to refill.
+	require.NoError(t, limiter.allowAt(a, now))
+	require.NoError(t, limiter.allowAt(a, now))
+	require.Error(t, limiter.allowAt(a, now))
+	require.Error(t, limiter.allowAt(a, now.Add(50*time.Millisecond)))
+	require.NoError(t, limiter.allowAt(a, now.Add(100*time.Millisecond)))
+
+	// Peers have separate buckets.
+	require.NoError(t, limiter.allowAt(b, now))
+
+	// Tokens refill up to the burst.
+	later := now.Add(time.Hour)
+	require.NoError(t, limiter.allowAt(a, later))
+	require.NoError(t, limiter.allowAt(a, later))
+	require.Error(t, limiter.allowAt(a, later))
+
+	// Removed peers start with a full bucket.
+	limiter.RemovePeer(a)
+	require.NoError(t, limiter.allowAt(a, later))
+	require.NoError(t, limiter.allowAt(a, later))
+}
+
+func TestPeerRateLimiter_Sustained(t *testing.T) {
+	a := types.NodeID("aa")
+	now := time.Now()
+
+	limiter := NewPeerRateLimiter(RateLimit{Rate: 1}, 3)
+	require.NoError(t, limiter.allowAt(a, now))
+
+	var rateErr ErrRateLimited
+	for i := 1; i <= 3; i++ {
+		err := limiter.allowAt(a, now)
+		require.True(t, errors.As(err, &rateErr))
+		require.Equal(t, i, rateErr.Violations)
+		require.Equal(t, i == 3, rateErr.Sustained)
+	}
+
+	// Violations are forgotten once the window has passed.
+	later := now.Add(2 * rateLimitViolationWindow)
+	require.NoError(t, limiter.allowAt(a, later))
+	err := limiter.allowAt(a, later)
+	require.True(t, errors.As(err, &rateErr))
+	require.Equal(t, 1, rateErr.Violations)
+	require.False(t, rateErr.Sustained)
+}
+
+func TestPeerRateLimiter_ReleasePeer(t *testing.T) {
+	a := types.NodeID("aa")
+	b := types.NodeID("bb")
+	now := time.Now()
+
+	limiter := NewPeerRateLimiter(RateLimit{Rate: 1}, 2)
+
+	// Released peers without violations start afresh.
+	require.NoError(t, limiter.allowAt(b, now))
+	limiter.releasePeerAt(b, now)
+	require.NoError(t, limiter.allowAt(b, now))
+
+	// Violations of released peers add up once they reconnect.
+	require.NoError(t, limiter.allowAt(a, now))
+	require.Error(t, limiter.allowAt(a, now))
+	limiter.releasePeerAt(a, now)
+
+	var rateErr ErrRateLimited
+	err := limiter.allowAt(a, now)
+	require.True(t, errors.As(err, &rateErr))
+	require.True(t, rateErr.Sustained)
+
+	// They're forgotten once the window has passed.
+	limiter.releasePeerAt(a, now)
+	limiter.releasePeerAt(b, now.Add(2*rateLimitViolationWindow))
+	require.Empty(t, limiter.buckets)
+}
+
+func TestPeerRateLimiter_NoLimit(t *testing.T) {
+	limiter := NewPeerRateLimiter(RateLimit{}, 1)
+	for i := 0; i < 100; i++ {
+		require.NoError(t, limiter.allowAt("aa", time.Now()))
+	}
+}
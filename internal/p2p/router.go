@@ -8,14 +8,17 @@ import (
 	"net"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
 
 	"github.com/tendermint/tendermint/crypto"
 	tmstrings "github.com/tendermint/tendermint/internal/libs/strings"
+	"github.com/tendermint/tendermint/internal/p2p/conn"
 	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/libs/service"
+	p2pproto "github.com/tendermint/tendermint/proto/tendermint/p2p"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -30,6 +33,12 @@ type RouterOptions struct {
 	// DialTimeout is the timeout for dialing a peer. 0 means no timeout.
 	DialTimeout time.Duration
 
+	// ResolveInterval is how often the hostnames of dialed peers are
+	// resolved again while connected. If a peer's hostname no longer resolves
+	// to the IP address it's connected to, it is disconnected and dialed
+	// again at its new address. 0 disables this.
+	ResolveInterval time.Duration
+
 	// HandshakeTimeout is the timeout for handshaking with a peer. 0 means
 	// no timeout.
 	HandshakeTimeout time.Duration
@@ -48,24 +57,38 @@ type RouterOptions struct {
 	IncomingConnectionWindow time.Duration
 
 	// FilterPeerByIP is used by the router to inject filtering
-	// behavior for new incoming connections. The router passes
-	// the remote IP of the incoming connection the port number as
+	// behavior for new incoming and outgoing connections. The router
+	// passes the remote IP of the connection and the port number as
 	// arguments. Functions should return an error to reject the
 	// peer.
 	FilterPeerByIP func(context.Context, net.IP, uint16) error
 
 	// FilterPeerByID is used by the router to inject filtering
-	// behavior for new incoming connections. The router passes
+	// behavior for new incoming and outgoing connections. The router passes
 	// the NodeID of the node before completing the connection,
 	// but this occurs after the handshake is complete. Filter by
 	// IP address to filter before the handshake. Functions should
 	// return an error to reject the peer.
 	FilterPeerByID func(context.Context, types.NodeID) error
 
+	// IPFilter rejects incoming connections from, and outgoing connections
+	// to, IPs in denied CIDR ranges or outside allowed ranges. It is applied
+	// before the handshake.
+	IPFilter *IPFilter
+
 	// NumConcrruentDials controls how many parallel go routines
 	// are used to dial peers. This defaults to the value of
 	// runtime.NumCPU.
 	NumConcurrentDials func() int
+
+	// MaxRateLimitViolations is the number of times within a minute a peer
+	// may exceed a channel's message rate limit before it is disconnected
+	// and banned. Defaults to 10.
+	MaxRateLimitViolations int
+
+	// RateLimitBanDuration is how long peers that keep exceeding channel
+	// message rate limits are banned for. Defaults to 1 hour.
+	RateLimitBanDuration time.Duration
 }
 
 const (
@@ -97,6 +120,14 @@ func (o *RouterOptions) Validate() error {
 		o.MaxIncomingConnectionAttempts = 100
 	}
 
+	if o.MaxRateLimitViolations == 0 {
+		o.MaxRateLimitViolations = 10
+	}
+
+	if o.RateLimitBanDuration == 0 {
+		o.RateLimitBanDuration = time.Hour
+	}
+
 	return nil
 }
 
@@ -153,6 +184,7 @@ type Router struct {
 	transport   Transport
 	endpoint    *Endpoint
 	connTracker connectionTracker
+	stopping    uint32 // set once the router is stopping, accessed atomically
 
 	peerMtx    sync.RWMutex
 	peerQueues map[types.NodeID]queue // outbound messages per peer for all channels
@@ -167,6 +199,24 @@ type Router struct {
 	channelMtx      sync.RWMutex
 	channelQueues   map[ChannelID]queue // inbound messages from all peers to a single channel
 	channelMessages map[ChannelID]proto.Message
+	channelLimiters map[ChannelID]*PeerRateLimiter // per-peer message rate limits
+	channelInvalid  map[ChannelID]func(types.NodeID, error)
+
+	// dialCancels cancels each dial in progress, so that dials which are no
+	// longer needed once the connection limits are reached can be abandoned.
+	dialMtx     sync.Mutex
+	dialCancels map[types.NodeID]context.CancelFunc
+
+	// hostnamePeers are the connected peers that were dialed by hostname,
+	// along with the IP address they're connected to.
+	hostnameMtx   sync.Mutex
+	hostnamePeers map[types.NodeID]hostnamePeer
+}
+
+// hostnamePeer is a peer that was dialed by hostname, and is connected at ip.
+type hostnamePeer struct {
+	address NodeAddress
+	ip      net.IP
 }
 
 // NewRouter creates a new Router. The given Transports must already be
@@ -204,6 +254,10 @@ func NewRouter(
 		options:         options,
 		channelQueues:   map[ChannelID]queue{},
 		channelMessages: map[ChannelID]proto.Message{},
+		channelLimiters: map[ChannelID]*PeerRateLimiter{},
+		channelInvalid:  map[ChannelID]func(types.NodeID, error){},
+		dialCancels:     map[types.NodeID]context.CancelFunc{},
+		hostnamePeers:   map[types.NodeID]hostnamePeer{},
 		peerQueues:      map[types.NodeID]queue{},
 		peerChannels:    make(map[types.NodeID]ChannelIDSet),
 	}
@@ -248,6 +302,12 @@ type ChannelCreator func(context.Context, *ChannelDescriptor) (Channel, error)
 // implement Wrapper to automatically (un)wrap multiple message types in a
 // wrapper message. The caller may provide a size to make the channel buffered,
 // which internally makes the inbound, outbound, and error channel buffered.
+//
+// Channels may be opened and closed (by canceling ctx) while the router is
+// running, e.g. for reactors that are only needed for a while. Peers exchange
+// their channel sets during the handshake and these aren't renegotiated, so
+// peers that are already connected will only use a channel opened later once
+// they reconnect, and messages they send on a closed channel are dropped.
 func (r *Router) OpenChannel(ctx context.Context, chDesc *ChannelDescriptor) (Channel, error) {
 	r.channelMtx.Lock()
 	defer r.channelMtx.Unlock()
@@ -272,6 +332,15 @@ func (r *Router) OpenChannel(ctx context.Context, chDesc *ChannelDescriptor) (Ch
 
 	r.channelQueues[id] = queue
 	r.channelMessages[id] = messageType
+	if chDesc.RecvMessageRate > 0 {
+		r.channelLimiters[id] = NewPeerRateLimiter(RateLimit{
+			Rate:  chDesc.RecvMessageRate,
+			Burst: chDesc.RecvMessageBurst,
+		}, r.options.MaxRateLimitViolations)
+	}
+	if chDesc.OnInvalidMessage != nil {
+		r.channelInvalid[id] = chDesc.OnInvalidMessage
+	}
 
 	// add the channel to the nodeInfo if it's not already there.
 	r.nodeInfoProducer().AddChannel(uint16(chDesc.ID))
@@ -283,6 +352,9 @@ func (r *Router) OpenChannel(ctx context.Context, chDesc *ChannelDescriptor) (Ch
 			r.channelMtx.Lock()
 			delete(r.channelQueues, id)
 			delete(r.channelMessages, id)
+			delete(r.channelLimiters, id)
+			delete(r.channelInvalid, id)
+			r.removeChannelDescriptor(id)
 			r.channelMtx.Unlock()
 			queue.close()
 		}()
@@ -293,6 +365,21 @@ func (r *Router) OpenChannel(ctx context.Context, chDesc *ChannelDescriptor) (Ch
 	return channel, nil
 }
 
+// removeChannelDescriptor removes a closed channel from the channel
+// descriptors, the node info and the transport, such that it is no longer
+// advertised to or set up with new peers and can be opened again later. The caller must hold channelMtx.
+func (r *Router) removeChannelDescriptor(id ChannelID) {
+	chDescs := make([]*ChannelDescriptor, 0, len(r.chDescs))
+	for _, chDesc := range r.chDescs {
+		if chDesc.ID != id {
+			chDescs = append(chDescs, chDesc)
+		}
+	}
+	r.chDescs = chDescs
+	r.nodeInfoProducer().RemoveChannel(uint16(id))
+	r.transport.RemoveChannelDescriptor(id)
+}
+
 // routeChannel receives outbound channel messages and routes them to the
 // appropriate peer. It also receives peer errors and reports them to the peer
 // manager. It returns when either the outbound channel or error channel is
@@ -482,6 +569,10 @@ func (r *Router) openConnection(ctx context.Context, conn Connection) {
 	re := conn.RemoteEndpoint()
 	incomingIP := re.IP
 
+	if err := r.options.IPFilter.Check(incomingIP); err != nil {
+		r.logger.Debug("peer filtered by IP range", "ip", incomingIP.String(), "err", err)
+		return
+	}
 	if err := r.filterPeersIP(ctx, incomingIP, re.Port); err != nil {
 		r.logger.Debug("peer filtered by IP", "ip", incomingIP.String(), "err", err)
 		return
@@ -518,8 +609,19 @@ func (r *Router) openConnection(ctx context.Context, conn Connection) {
 	if err := r.runWithPeerMutex(func() error { return r.peerManager.Accepted(peerInfo.NodeID) }); err != nil {
 		r.logger.Error("failed to accept connection",
 			"op", "incoming/accepted", "peer", peerInfo.NodeID, "err", err)
+		reason := p2pproto.DisconnectUnknown
+		switch {
+		case errors.Is(err, errMaxConnected), errors.Is(err, errMaxIncoming):
+			reason = p2pproto.DisconnectPeerLimit
+		case r.peerManager.IsBanned(peerInfo.NodeID):
+			reason = p2pproto.DisconnectBanned
+		}
+		_ = disconnect(conn, reason)
 		return
 	}
+	r.setPeerInfo(peerInfo)
+	r.rotateNodeKey(peerInfo)
+	r.cancelUnwantedDials()
 
 	r.routePeer(ctx, peerInfo.NodeID, conn, toChannelIDs(peerInfo.Channels))
 }
@@ -574,8 +676,25 @@ LOOP:
 }
 
 func (r *Router) connectPeer(ctx context.Context, address NodeAddress) {
-	conn, err := r.dialPeer(ctx, address)
+	// The dial and handshake use their own context, which is canceled if the
+	// connection limits are reached by other peers in the meantime.
+	dialCtx, cancel := context.WithCancel(ctx)
+	r.dialMtx.Lock()
+	r.dialCancels[address.NodeID] = cancel
+	r.dialMtx.Unlock()
+	defer func() {
+		r.dialMtx.Lock()
+		delete(r.dialCancels, address.NodeID)
+		r.dialMtx.Unlock()
+		cancel()
+	}()
+
+	conn, err := r.dialPeer(dialCtx, address)
 	switch {
+	case err != nil && ctx.Err() == nil && dialCtx.Err() != nil:
+		r.logger.Debug("canceled dial to peer", "peer", address)
+		r.peerManager.DialCanceled(address)
+		return
 	case errors.Is(err, context.Canceled):
 		return
 	case err != nil:
@@ -586,8 +705,13 @@ func (r *Router) connectPeer(ctx context.Context, address NodeAddress) {
 		return
 	}
 
-	peerInfo, err := r.handshakePeer(ctx, conn, address.NodeID)
+	peerInfo, err := r.handshakePeer(dialCtx, conn, address.NodeID)
 	switch {
+	case err != nil && ctx.Err() == nil && dialCtx.Err() != nil:
+		r.logger.Debug("canceled handshake with peer", "peer", address)
+		r.peerManager.DialCanceled(address)
+		conn.Close()
+		return
 	case errors.Is(err, context.Canceled):
 		conn.Close()
 		return
@@ -599,6 +723,14 @@ func (r *Router) connectPeer(ctx context.Context, address NodeAddress) {
 		conn.Close()
 		return
 	}
+	if err := r.filterPeersID(ctx, peerInfo.NodeID); err != nil {
+		r.logger.Debug("peer filtered by node ID", "node", peerInfo.NodeID, "err", err)
+		if err = r.peerManager.DialFailed(ctx, address); err != nil {
+			r.logger.Error("failed to report dial failure", "peer", address, "err", err)
+		}
+		conn.Close()
+		return
+	}
 
 	if err := r.runWithPeerMutex(func() error { return r.peerManager.Dialed(address) }); err != nil {
 		r.logger.Error("failed to dial peer", "op", "outgoing/dialing", "peer", address.NodeID, "err", err)
@@ -606,11 +738,103 @@ func (r *Router) connectPeer(ctx context.Context, address NodeAddress) {
 		conn.Close()
 		return
 	}
+	r.setPeerInfo(peerInfo)
+	r.rotateNodeKey(peerInfo)
+	r.cancelUnwantedDials()
+	if address.Hostname != "" && net.ParseIP(address.Hostname) == nil {
+		if endpoint := conn.RemoteEndpoint(); endpoint.IP != nil {
+			r.hostnameMtx.Lock()
+			r.hostnamePeers[address.NodeID] = hostnamePeer{address: address, ip: endpoint.IP}
+			r.hostnameMtx.Unlock()
+		}
+	}
 
 	// routePeer (also) calls connection close
 	go r.routePeer(ctx, address.NodeID, conn, toChannelIDs(peerInfo.Channels))
 }
 
+// setPeerInfo records the node info of a peer that has been connected, and
+// the protocol versions negotiated with it.
+func (r *Router) setPeerInfo(peerInfo types.NodeInfo) {
+	r.peerManager.SetNodeInfo(peerInfo)
+	r.channelMtx.RLock()
+	version, err := r.nodeInfoProducer().NegotiateProtocolVersion(peerInfo)
+	r.channelMtx.RUnlock()
+	if err != nil {
+		// This was already checked by handshakePeer.
+		r.logger.Error("failed to negotiate protocol version", "peer", peerInfo.NodeID, "err", err)
+		return
+	}
+	r.peerManager.SetProtocolVersion(peerInfo.NodeID, version)
+}
+
+// resolvePeers periodically resolves the hostnames of connected peers that
+// were dialed by hostname, and redials peers whose hostname no longer resolves
+// to the IP address they're connected to.
+func (r *Router) resolvePeers(ctx context.Context) {
+	ticker := time.NewTicker(r.options.ResolveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		r.hostnameMtx.Lock()
+		peers := make(map[types.NodeID]hostnamePeer, len(r.hostnamePeers))
+		for peerID, peer := range r.hostnamePeers {
+			peers[peerID] = peer
+		}
+		r.hostnameMtx.Unlock()
+
+		for peerID, peer := range peers {
+			if r.hostnameMoved(ctx, peer) {
+				r.logger.Info("peer hostname resolves to a new address, redialing",
+					"peer", peerID, "hostname", peer.address.Hostname, "ip", peer.ip)
+				r.peerManager.Redial(peerID)
+			}
+		}
+	}
+}
+
+// hostnameMoved returns true if the peer's hostname no longer resolves to the
+// IP address it's connected to. Resolution errors are ignored, since they're
+// likely to be transient.
+func (r *Router) hostnameMoved(ctx context.Context, peer hostnamePeer) bool {
+	if r.options.ResolveTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.options.ResolveTimeout)
+		defer cancel()
+	}
+	endpoints, err := peer.address.Resolve(ctx)
+	if err != nil || len(endpoints) == 0 {
+		r.logger.Debug("failed to resolve peer hostname", "peer", peer.address.NodeID,
+			"hostname", peer.address.Hostname, "err", err)
+		return false
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.IP.Equal(peer.ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// cancelUnwantedDials cancels the dials in progress to peers that can no
+// longer be connected, since the connection limits have been reached.
+func (r *Router) cancelUnwantedDials() {
+	r.dialMtx.Lock()
+	defer r.dialMtx.Unlock()
+
+	for peerID, cancel := range r.dialCancels {
+		if !r.peerManager.WantsDial(peerID) {
+			cancel()
+		}
+	}
+}
+
 func (r *Router) getOrMakeQueue(peerID types.NodeID, channels ChannelIDSet) queue {
 	r.peerMtx.Lock()
 	defer r.peerMtx.Unlock()
@@ -644,6 +868,17 @@ func (r *Router) dialPeer(ctx context.Context, address NodeAddress) (Connection,
 	}
 
 	for _, endpoint := range endpoints {
+		if endpoint.IP != nil {
+			if err := r.options.IPFilter.Check(endpoint.IP); err != nil {
+				r.logger.Debug("not dialing filtered endpoint", "peer", address.NodeID, "endpoint", endpoint, "err", err)
+				continue
+			}
+			if err := r.filterPeersIP(ctx, endpoint.IP, endpoint.Port); err != nil {
+				r.logger.Debug("peer filtered by IP", "peer", address.NodeID, "endpoint", endpoint, "err", err)
+				continue
+			}
+		}
+
 		dialCtx := ctx
 		if r.options.DialTimeout > 0 {
 			var cancel context.CancelFunc
@@ -678,8 +913,13 @@ func (r *Router) handshakePeer(
 	expectID types.NodeID,
 ) (types.NodeInfo, error) {
 
-	nodeInfo := r.nodeInfoProducer()
-	peerInfo, peerKey, err := conn.Handshake(ctx, r.options.HandshakeTimeout, *nodeInfo, r.privKey)
+	// Channels may be opened or closed concurrently, which changes the node
+	// info, so we copy it while holding channelMtx.
+	r.channelMtx.RLock()
+	nodeInfo := *r.nodeInfoProducer()
+	r.channelMtx.RUnlock()
+
+	peerInfo, peerKey, err := conn.Handshake(ctx, r.options.HandshakeTimeout, nodeInfo, r.privKey)
 	if err != nil {
 		return peerInfo, err
 	}
@@ -695,6 +935,11 @@ func (r *Router) handshakePeer(
 		return peerInfo, fmt.Errorf("expected to connect with peer %q, got %q",
 			expectID, peerInfo.NodeID)
 	}
+	if peerInfo.NextNodeKey != nil {
+		if err := peerInfo.NextNodeKey.Verify(peerKey); err != nil {
+			return peerInfo, fmt.Errorf("invalid node key rotation: %w", err)
+		}
+	}
 
 	if err := nodeInfo.CompatibleWith(peerInfo); err != nil {
 		if err := r.peerManager.Inactivate(peerInfo.NodeID); err != nil {
@@ -710,6 +955,24 @@ func (r *Router) handshakePeer(
 	return peerInfo, nil
 }
 
+// rotateNodeKey records the node key rotation announced by a peer, if any.
+// handshakePeer has verified the announcement, but it's only recorded once the
+// peer manager has accepted the connection, so that banned or filtered peers
+// can't add addresses for other node IDs.
+func (r *Router) rotateNodeKey(peerInfo types.NodeInfo) {
+	if peerInfo.NextNodeKey == nil {
+		return
+	}
+	nextID := peerInfo.NextNodeKey.NextNodeID
+	added, err := r.peerManager.RotateNodeKey(peerInfo.NodeID, nextID)
+	if err != nil {
+		r.logger.Error("failed to record peer node key rotation",
+			"peer", peerInfo.NodeID, "next", nextID, "err", err)
+	} else if added {
+		r.logger.Info("peer announced node key rotation", "peer", peerInfo.NodeID, "next", nextID)
+	}
+}
+
 func (r *Router) runWithPeerMutex(fn func() error) error {
 	r.peerMtx.Lock()
 	defer r.peerMtx.Unlock()
@@ -730,8 +993,18 @@ func (r *Router) routePeer(ctx context.Context, peerID types.NodeID, conn Connec
 		delete(r.peerChannels, peerID)
 		r.peerMtx.Unlock()
 
+		r.channelMtx.RLock()
+		for _, limiter := range r.channelLimiters {
+			limiter.RemovePeer(peerID)
+		}
+		r.channelMtx.RUnlock()
+
 		sendQueue.close()
 
+		r.hostnameMtx.Lock()
+		delete(r.hostnamePeers, peerID)
+		r.hostnameMtx.Unlock()
+
 		r.peerManager.Disconnected(ctx, peerID)
 		r.metrics.PeersConnected.Add(-1)
 	}()
@@ -741,8 +1014,23 @@ func (r *Router) routePeer(ctx context.Context, peerID types.NodeID, conn Connec
 	errCh := make(chan error, 2)
 
 	go func() {
+		err := r.receivePeer(ctx, peerID, conn)
+		if peerUnresponsive(err) {
+			r.peerManager.Unresponsive(peerID)
+		} else if reason, ok := remoteDisconnectReason(err); ok {
+			r.peerManager.RemoteDisconnected(ctx, peerID, reason)
+		} else if errors.Is(err, io.EOF) && ctx.Err() == nil {
+			// If the send queue is still open we didn't close the
+			// connection ourselves, so the remote peer hung up on us.
+			select {
+			case <-sendQueue.closed():
+			default:
+				r.peerManager.RemoteClosed(ctx, peerID)
+			}
+		}
+
 		select {
-		case errCh <- r.receivePeer(ctx, peerID, conn):
+		case errCh <- err:
 		case <-ctx.Done():
 		}
 	}()
@@ -760,8 +1048,17 @@ func (r *Router) routePeer(ctx context.Context, peerID types.NodeID, conn Connec
 	case <-ctx.Done():
 	}
 
-	_ = conn.Close()
+	// The connection failed if it errored before we closed it ourselves,
+	// unless the peer closed it gracefully.
+	failed := err != nil && !errors.Is(err, io.EOF) && ctx.Err() == nil
+	if reason, ok := remoteDisconnectReason(err); ok {
+		failed = reason == p2pproto.DisconnectBanned || reason == p2pproto.DisconnectProtocolError
+	}
+
+	// Close the send queue before the connection, so that the receive
+	// routine can tell our own disconnects apart from the remote's.
 	sendQueue.close()
+	_ = disconnect(conn, r.disconnectReason(ctx, peerID, err))
 
 	select {
 	case <-ctx.Done():
@@ -784,6 +1081,66 @@ func (r *Router) routePeer(ctx context.Context, peerID types.NodeID, conn Connec
 	default:
 		r.logger.Error("peer failure", "peer", peerID, "endpoint", conn, "err", err)
 	}
+
+	var rtt time.Duration
+	if c, ok := conn.(rttConnection); ok {
+		rtt = c.RTT()
+	}
+	if err := r.peerManager.ConnectionClosed(peerID, rtt, failed); err != nil {
+		r.logger.Error("failed to record peer connection quality", "peer", peerID, "err", err)
+	}
+}
+
+// rttConnection is implemented by connections that measure their round-trip
+// time, e.g. via pings.
+type rttConnection interface {
+	RTT() time.Duration
+}
+
+// disconnectConnection is implemented by connections that can tell the peer
+// why they're being closed.
+type disconnectConnection interface {
+	Disconnect(reason p2pproto.DisconnectReason) error
+}
+
+// disconnect closes a connection, telling the peer why if the connection
+// supports it.
+func disconnect(c Connection, reason p2pproto.DisconnectReason) error {
+	if dc, ok := c.(disconnectConnection); ok {
+		return dc.Disconnect(reason)
+	}
+	return c.Close()
+}
+
+// remoteDisconnectReason returns the reason the peer gave for closing the
+// connection, if err is due to the peer disconnecting gracefully.
+func remoteDisconnectReason(err error) (p2pproto.DisconnectReason, bool) {
+	var disconnected conn.ErrDisconnected
+	if errors.As(err, &disconnected) {
+		return disconnected.Reason, true
+	}
+	return p2pproto.DisconnectUnknown, false
+}
+
+// peerUnresponsive returns true if a connection error means that the peer
+// stopped answering pings, i.e. the connection appears to be dead.
+func peerUnresponsive(err error) bool {
+	return errors.Is(err, conn.ErrPongTimeout)
+}
+
+// disconnectReason returns the reason to give a peer when closing its
+// connection, where err is the error that caused it, if any.
+func (r *Router) disconnectReason(ctx context.Context, peerID types.NodeID, err error) p2pproto.DisconnectReason {
+	switch {
+	case ctx.Err() != nil || atomic.LoadUint32(&r.stopping) == 1:
+		return p2pproto.DisconnectShuttingDown
+	case r.peerManager.IsBanned(peerID):
+		return p2pproto.DisconnectBanned
+	case err != nil && !errors.Is(err, io.EOF):
+		return p2pproto.DisconnectProtocolError
+	default:
+		return r.peerManager.EvictReason(peerID)
+	}
 }
 
 // receivePeer receives inbound messages from a peer, deserializes them and
@@ -798,6 +1155,8 @@ func (r *Router) receivePeer(ctx context.Context, peerID types.NodeID, conn Conn
 		r.channelMtx.RLock()
 		queue, ok := r.channelQueues[chID]
 		messageType := r.channelMessages[chID]
+		limiter := r.channelLimiters[chID]
+		onInvalid := r.channelInvalid[chID]
 		r.channelMtx.RUnlock()
 
 		if !ok {
@@ -805,9 +1164,28 @@ func (r *Router) receivePeer(ctx context.Context, peerID types.NodeID, conn Conn
 			continue
 		}
 
+		if limiter != nil {
+			if err := limiter.Allow(peerID); err != nil {
+				var rateErr ErrRateLimited
+				if errors.As(err, &rateErr) && rateErr.Sustained {
+					r.logger.Info("banning peer for exceeding rate limit", "peer", peerID, "channel", chID,
+						"err", err, "duration", r.options.RateLimitBanDuration)
+					if banErr := r.peerManager.Ban(peerID, r.options.RateLimitBanDuration); banErr != nil {
+						r.logger.Error("failed to ban peer", "peer", peerID, "err", banErr)
+					}
+					return err
+				}
+				r.logger.Debug("peer exceeded rate limit, dropping message", "peer", peerID, "channel", chID)
+				continue
+			}
+		}
+
 		msg := proto.Clone(messageType)
 		if err := proto.Unmarshal(bz, msg); err != nil {
 			r.logger.Error("message decoding failed, dropping message", "peer", peerID, "err", err)
+			if onInvalid != nil {
+				onInvalid(peerID, err)
+			}
 			continue
 		}
 
@@ -815,6 +1193,9 @@ func (r *Router) receivePeer(ctx context.Context, peerID types.NodeID, conn Conn
 			msg, err = wrapper.Unwrap()
 			if err != nil {
 				r.logger.Error("failed to unwrap message", "err", err)
+				if onInvalid != nil {
+					onInvalid(peerID, err)
+				}
 				continue
 			}
 		}
@@ -923,6 +1304,9 @@ func (r *Router) OnStart(ctx context.Context) error {
 	go r.dialPeers(ctx)
 	go r.evictPeers(ctx)
 	go r.acceptPeers(ctx, r.transport)
+	if r.options.ResolveInterval > 0 {
+		go r.resolvePeers(ctx)
+	}
 
 	return nil
 }
@@ -934,6 +1318,9 @@ func (r *Router) OnStart(ctx context.Context) error {
 // here, since that would cause any reactor senders to panic, so it is the
 // sender's responsibility.
 func (r *Router) OnStop() {
+	// Tell peers that we're shutting down when disconnecting them.
+	atomic.StoreUint32(&r.stopping, 1)
+
 	// Close transport listeners (unblocks Accept calls).
 	if err := r.transport.Close(); err != nil {
 		r.logger.Error("failed to close transport", "err", err)
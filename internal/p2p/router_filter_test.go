@@ -32,3 +32,29 @@ func TestConnectionFiltering(t *testing.T) {
 	router.openConnection(ctx, &MemoryConnection{logger: logger, closeFn: func() {}})
 	require.Equal(t, 1, filterByIPCount)
 }
+
+func TestConnectionFiltering_IPFilter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := log.NewNopLogger()
+
+	ipFilter, err := NewIPFilter([]string{"10.0.0.0/8"}, nil)
+	require.NoError(t, err)
+
+	filterByIPCount := 0
+	router := &Router{
+		logger:      logger,
+		connTracker: newConnTracker(1, time.Second),
+		options: RouterOptions{
+			IPFilter: ipFilter,
+			FilterPeerByIP: func(ctx context.Context, ip net.IP, port uint16) error {
+				filterByIPCount++
+				return nil
+			},
+		},
+	}
+
+	// The connection is rejected before any other filters are consulted.
+	router.openConnection(ctx, &MemoryConnection{logger: logger, closeFn: func() {}})
+	require.Equal(t, 0, filterByIPCount)
+}
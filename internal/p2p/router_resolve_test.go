@@ -0,0 +1,32 @@
+package p2p
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestRouterHostnameMoved(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	router := &Router{
+		logger:  log.NewNopLogger(),
+		options: RouterOptions{ResolveTimeout: 5 * time.Second},
+	}
+	nodeID := types.NodeID("00112233445566778899aabbccddeeff00112233")
+	address := NodeAddress{Protocol: "tcp", NodeID: nodeID, Hostname: "localhost", Port: 26656}
+
+	require.False(t, router.hostnameMoved(ctx, hostnamePeer{address: address, ip: net.IPv4(127, 0, 0, 1)}))
+	require.True(t, router.hostnameMoved(ctx, hostnamePeer{address: address, ip: net.IPv4(10, 0, 0, 1)}))
+
+	// Hostnames that fail to resolve are assumed not to have moved.
+	address.Hostname = "tendermint.invalid"
+	require.False(t, router.hostnameMoved(ctx, hostnamePeer{address: address, ip: net.IPv4(10, 0, 0, 1)}))
+}
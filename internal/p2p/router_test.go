@@ -111,12 +111,15 @@ func TestRouter_Channel_Basic(t *testing.T) {
 
 	testnet := p2ptest.MakeNetwork(ctx, t, p2ptest.NetworkOptions{NumNodes: 1})
 
+	// Opening and closing channels changes the node info, so we use a copy.
+	nodeInfo := selfInfo
+
 	router, err := p2p.NewRouter(
 		log.NewNopLogger(),
 		p2p.NopMetrics(),
 		selfKey,
 		peerManager,
-		func() *types.NodeInfo { return &selfInfo },
+		func() *types.NodeInfo { return &nodeInfo },
 		testnet.RandomNode().Transport,
 		&p2p.Endpoint{},
 		p2p.RouterOptions{},
@@ -164,6 +167,109 @@ func TestRouter_Channel_Basic(t *testing.T) {
 	p2ptest.RequireEmpty(ctx, t, channel)
 }
 
+func TestRouter_Channel_OpenClose(t *testing.T) {
+	t.Cleanup(leaktest.Check(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+
+	testnet := p2ptest.MakeNetwork(ctx, t, p2ptest.NetworkOptions{NumNodes: 1})
+
+	nodeInfo := types.NodeInfo{
+		NodeID:     selfID,
+		ListenAddr: "0.0.0.0:0",
+		Network:    "test",
+		Moniker:    string(selfID),
+	}
+
+	router, err := p2p.NewRouter(
+		log.NewNopLogger(),
+		p2p.NopMetrics(),
+		selfKey,
+		peerManager,
+		func() *types.NodeInfo { return &nodeInfo },
+		testnet.RandomNode().Transport,
+		&p2p.Endpoint{},
+		p2p.RouterOptions{},
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, router.Start(ctx))
+	t.Cleanup(router.Wait)
+
+	// Opening channels on a running router should advertise them.
+	chctx, chcancel := context.WithCancel(ctx)
+	defer chcancel()
+	_, err = router.OpenChannel(chctx, chDesc)
+	require.NoError(t, err)
+	chDesc2 := &p2p.ChannelDescriptor{ID: 2, MessageType: &p2ptest.Message{}}
+	_, err = router.OpenChannel(ctx, chDesc2)
+	require.NoError(t, err)
+	require.EqualValues(t, []byte{byte(chDesc.ID), byte(chDesc2.ID)}, nodeInfo.Channels)
+
+	// Closing a channel should stop advertising it, such that it's advertised
+	// after channel 2 once reopened.
+	chcancel()
+	require.Eventually(t, func() bool {
+		_, err := router.OpenChannel(ctx, chDesc)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	require.EqualValues(t, []byte{byte(chDesc2.ID), byte(chDesc.ID)}, nodeInfo.Channels)
+}
+
+func TestRouter_Channel_CloseRemovesFromTransport(t *testing.T) {
+	t.Cleanup(leaktest.Check(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockTransport := &mocks.Transport{}
+	mockTransport.On("String").Maybe().Return("mock")
+	mockTransport.On("Close").Return(nil)
+	mockTransport.On("Accept", mock.Anything).Maybe().Return(nil, io.EOF)
+	mockTransport.On("Listen", mock.Anything).Return(nil)
+	mockTransport.On("AddChannelDescriptors", []*p2p.ChannelDescriptor{chDesc}).Once().Return()
+	removed := make(chan struct{})
+	mockTransport.On("RemoveChannelDescriptor", chDesc.ID).Once().
+		Run(func(mock.Arguments) { close(removed) }).Return()
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+
+	// Opening and closing channels changes the node info, so we use a copy.
+	nodeInfo := selfInfo
+
+	router, err := p2p.NewRouter(
+		log.NewNopLogger(),
+		p2p.NopMetrics(),
+		selfKey,
+		peerManager,
+		func() *types.NodeInfo { return &nodeInfo },
+		mockTransport,
+		nil,
+		p2p.RouterOptions{},
+	)
+	require.NoError(t, err)
+	require.NoError(t, router.Start(ctx))
+
+	// Closing the channel should stop new connections from setting it up.
+	chctx, chcancel := context.WithCancel(ctx)
+	_, err = router.OpenChannel(chctx, chDesc)
+	require.NoError(t, err)
+	chcancel()
+	select {
+	case <-removed:
+	case <-time.After(time.Second):
+		t.Fatal("closed channel was not removed from the transport")
+	}
+
+	router.Stop()
+	mockTransport.AssertExpectations(t)
+}
+
 // Channel tests are hairy to mock, so we use an in-memory network instead.
 func TestRouter_Channel_SendReceive(t *testing.T) {
 	if testing.Short() {
@@ -458,6 +564,85 @@ func TestRouter_AcceptPeers(t *testing.T) {
 	}
 }
 
+func TestRouter_AcceptPeers_NodeKeyRotation(t *testing.T) {
+	next := types.GenNodeKey()
+	rotation, err := types.NodeKey{ID: peerID, PrivKey: peerKey}.SignRotation(next)
+	require.NoError(t, err)
+	rotatingInfo := peerInfo
+	rotatingInfo.NextNodeKey = &rotation
+
+	for name, banned := range map[string]bool{"accepted": false, "banned": true} {
+		banned := banned
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			t.Cleanup(leaktest.Check(t))
+
+			connCtx, connCancel := context.WithCancel(context.Background())
+			mockConnection := &mocks.Connection{}
+			mockConnection.On("String").Maybe().Return("mock")
+			mockConnection.On("Handshake", mock.Anything, mock.Anything, selfInfo, selfKey).
+				Return(rotatingInfo, peerKey.PubKey(), nil)
+			mockConnection.On("Close").Run(func(_ mock.Arguments) { connCancel() }).Return(nil).Maybe()
+			mockConnection.On("RemoteEndpoint").Return(p2p.Endpoint{}).Maybe()
+			mockConnection.On("ReceiveMessage", mock.Anything).Return(chID, nil, io.EOF).Maybe()
+
+			mockTransport := &mocks.Transport{}
+			mockTransport.On("String").Maybe().Return("mock")
+			mockTransport.On("Close").Return(nil).Maybe()
+			mockTransport.On("Accept", mock.Anything).Once().Return(mockConnection, nil)
+			mockTransport.On("Accept", mock.Anything).Maybe().Return(nil, io.EOF)
+			mockTransport.On("Listen", mock.Anything).Return(nil)
+			mockTransport.On("Dial", mock.Anything, mock.Anything).Maybe().Return(nil, io.EOF)
+
+			peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+			require.NoError(t, err)
+			added, err := peerManager.Add(p2p.NodeAddress{Protocol: "memory", NodeID: peerID})
+			require.NoError(t, err)
+			require.True(t, added)
+			if banned {
+				require.NoError(t, peerManager.Ban(peerID, time.Hour))
+			}
+			sub := peerManager.Subscribe(ctx)
+
+			router, err := p2p.NewRouter(
+				log.NewNopLogger(),
+				p2p.NopMetrics(),
+				selfKey,
+				peerManager,
+				func() *types.NodeInfo { return &selfInfo },
+				mockTransport,
+				nil,
+				p2p.RouterOptions{},
+			)
+			require.NoError(t, err)
+			require.NoError(t, router.Start(ctx))
+
+			// The rotation is only recorded once the connection has been
+			// accepted, so banned peers can't add addresses for other IDs.
+			if banned {
+				select {
+				case <-connCtx.Done():
+				case <-time.After(time.Second):
+					require.Fail(t, "connection not closed")
+				}
+				require.Empty(t, peerManager.Addresses(next.ID))
+			} else {
+				p2ptest.RequireUpdate(t, sub, p2p.PeerUpdate{
+					NodeID: peerID,
+					Status: p2p.PeerStatusUp,
+				})
+				require.Equal(t, []p2p.NodeAddress{{Protocol: "memory", NodeID: next.ID}},
+					peerManager.Addresses(next.ID))
+			}
+
+			router.Stop()
+			mockTransport.AssertExpectations(t)
+		})
+	}
+}
+
 func TestRouter_AcceptPeers_Errors(t *testing.T) {
 	if testing.Short() {
 		// Each subtest takes more than one second due to the time.Sleep call,
@@ -676,6 +861,69 @@ func TestRouter_DialPeers(t *testing.T) {
 	}
 }
 
+func TestRouter_DialPeers_FilterByID(t *testing.T) {
+	t.Cleanup(leaktest.Check(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	address := p2p.NodeAddress{Protocol: "mock", NodeID: peerID}
+	endpoint := &p2p.Endpoint{Protocol: "mock", Path: string(peerID)}
+
+	closeCh := make(chan struct{})
+	mockConnection := &mocks.Connection{}
+	mockConnection.On("String").Maybe().Return("mock")
+	mockConnection.On("Handshake", mock.Anything, mock.Anything, selfInfo, selfKey).
+		Return(peerInfo, peerKey.PubKey(), nil)
+	mockConnection.On("Close").Run(func(_ mock.Arguments) { close(closeCh) }).Once().Return(nil)
+
+	mockTransport := &mocks.Transport{}
+	mockTransport.On("String").Maybe().Return("mock")
+	mockTransport.On("Close").Return(nil).Maybe()
+	mockTransport.On("Listen", mock.Anything).Return(nil)
+	mockTransport.On("Accept", mock.Anything).Maybe().Return(nil, io.EOF)
+	mockTransport.On("Dial", mock.Anything, endpoint).Once().Return(mockConnection, nil)
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	added, err := peerManager.Add(address)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	filtered := make(chan types.NodeID, 1)
+	router, err := p2p.NewRouter(
+		log.NewNopLogger(),
+		p2p.NopMetrics(),
+		selfKey,
+		peerManager,
+		func() *types.NodeInfo { return &selfInfo },
+		mockTransport,
+		nil,
+		p2p.RouterOptions{
+			FilterPeerByID: func(_ context.Context, id types.NodeID) error {
+				filtered <- id
+				return errors.New("filtered")
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, router.Start(ctx))
+
+	// The dialed peer is filtered after the handshake, and disconnected.
+	select {
+	case id := <-filtered:
+		require.Equal(t, peerID, id)
+	case <-time.After(time.Second):
+		require.Fail(t, "peer not filtered")
+	}
+	select {
+	case <-closeCh:
+	case <-time.After(time.Second):
+		require.Fail(t, "connection not closed")
+	}
+	router.Stop()
+	mockConnection.AssertExpectations(t)
+}
+
 func TestRouter_DialPeers_Parallel(t *testing.T) {
 	t.Cleanup(leaktest.Check(t))
 
@@ -907,6 +1155,7 @@ func TestRouter_DontSendOnInvalidChannel(t *testing.T) {
 
 	mockTransport := &mocks.Transport{}
 	mockTransport.On("AddChannelDescriptors", mock.Anything).Return()
+	mockTransport.On("RemoveChannelDescriptor", mock.Anything).Maybe().Return()
 	mockTransport.On("String").Maybe().Return("mock")
 	mockTransport.On("Close").Return(nil)
 	mockTransport.On("Accept", mock.Anything).Once().Return(mockConnection, nil)
@@ -919,12 +1168,15 @@ func TestRouter_DontSendOnInvalidChannel(t *testing.T) {
 
 	sub := peerManager.Subscribe(ctx)
 
+	// Opening and closing channels changes the node info, so we use a copy.
+	nodeInfo := selfInfo
+
 	router, err := p2p.NewRouter(
 		log.NewNopLogger(),
 		p2p.NopMetrics(),
 		selfKey,
 		peerManager,
-		func() *types.NodeInfo { return &selfInfo },
+		func() *types.NodeInfo { return &nodeInfo },
 		mockTransport,
 		nil,
 		p2p.RouterOptions{},
@@ -948,3 +1200,136 @@ func TestRouter_DontSendOnInvalidChannel(t *testing.T) {
 	router.Stop()
 	mockTransport.AssertExpectations(t)
 }
+
+func TestRouter_RateLimitBansPeer(t *testing.T) {
+	t.Cleanup(leaktest.Check(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	msg, err := proto.Marshal(&p2ptest.Message{Value: "flood"})
+	require.NoError(t, err)
+
+	// The mock peer floods us with messages until it's disconnected.
+	mockConnection := &mocks.Connection{}
+	mockConnection.On("String").Maybe().Return("mock")
+	mockConnection.On("Handshake", mock.Anything, mock.Anything, selfInfo, selfKey).
+		Return(peerInfo, peerKey.PubKey(), nil)
+	mockConnection.On("RemoteEndpoint").Return(p2p.Endpoint{})
+	mockConnection.On("Close").Return(nil)
+	mockConnection.On("ReceiveMessage", mock.Anything).Return(chID, msg, nil)
+
+	mockTransport := &mocks.Transport{}
+	mockTransport.On("AddChannelDescriptors", mock.Anything).Return()
+	mockTransport.On("RemoveChannelDescriptor", mock.Anything).Maybe().Return()
+	mockTransport.On("String").Maybe().Return("mock")
+	mockTransport.On("Close").Return(nil)
+	mockTransport.On("Accept", mock.Anything).Once().Return(mockConnection, nil)
+	mockTransport.On("Accept", mock.Anything).Maybe().Return(nil, io.EOF)
+	mockTransport.On("Listen", mock.Anything).Return(nil)
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+
+	sub := peerManager.Subscribe(ctx)
+
+	// Opening and closing channels changes the node info, so we use a copy.
+	nodeInfo := selfInfo
+
+	router, err := p2p.NewRouter(
+		log.NewNopLogger(),
+		p2p.NopMetrics(),
+		selfKey,
+		peerManager,
+		func() *types.NodeInfo { return &nodeInfo },
+		mockTransport,
+		nil,
+		p2p.RouterOptions{MaxRateLimitViolations: 3},
+	)
+	require.NoError(t, err)
+	require.NoError(t, router.Start(ctx))
+
+	limitedDesc := *chDesc
+	limitedDesc.RecvBufferCapacity = 10
+	limitedDesc.RecvMessageRate = 1
+	_, err = router.OpenChannel(ctx, &limitedDesc)
+	require.NoError(t, err)
+
+	p2ptest.RequireUpdate(t, sub, p2p.PeerUpdate{
+		NodeID: peerID,
+		Status: p2p.PeerStatusUp,
+	})
+	p2ptest.RequireUpdate(t, sub, p2p.PeerUpdate{
+		NodeID: peerID,
+		Status: p2p.PeerStatusDown,
+	})
+	require.True(t, peerManager.IsBanned(peerID))
+
+	router.Stop()
+	mockTransport.AssertExpectations(t)
+}
+
+func TestRouter_InvalidMessageHook(t *testing.T) {
+	t.Cleanup(leaktest.Check(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The mock peer sends us a single undecodable message once the channel is
+	// open, and then hangs up.
+	opened := make(chan time.Time)
+	mockConnection := &mocks.Connection{}
+	mockConnection.On("String").Maybe().Return("mock")
+	mockConnection.On("Handshake", mock.Anything, mock.Anything, selfInfo, selfKey).
+		Return(peerInfo, peerKey.PubKey(), nil)
+	mockConnection.On("RemoteEndpoint").Return(p2p.Endpoint{})
+	mockConnection.On("Close").Return(nil)
+	mockConnection.On("ReceiveMessage", mock.Anything).Once().WaitUntil(opened).Return(chID, []byte{0xff}, nil)
+	mockConnection.On("ReceiveMessage", mock.Anything).Return(chID, nil, io.EOF)
+
+	mockTransport := &mocks.Transport{}
+	mockTransport.On("AddChannelDescriptors", mock.Anything).Return()
+	mockTransport.On("RemoveChannelDescriptor", mock.Anything).Maybe().Return()
+	mockTransport.On("String").Maybe().Return("mock")
+	mockTransport.On("Close").Return(nil)
+	mockTransport.On("Accept", mock.Anything).Once().Return(mockConnection, nil)
+	mockTransport.On("Accept", mock.Anything).Maybe().Return(nil, io.EOF)
+	mockTransport.On("Listen", mock.Anything).Return(nil)
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+
+	// Opening and closing channels changes the node info, so we use a copy.
+	nodeInfo := selfInfo
+
+	router, err := p2p.NewRouter(
+		log.NewNopLogger(),
+		p2p.NopMetrics(),
+		selfKey,
+		peerManager,
+		func() *types.NodeInfo { return &nodeInfo },
+		mockTransport,
+		nil,
+		p2p.RouterOptions{},
+	)
+	require.NoError(t, err)
+	require.NoError(t, router.Start(ctx))
+
+	invalidCh := make(chan types.NodeID, 1)
+	hookDesc := *chDesc
+	hookDesc.OnInvalidMessage = func(peerID types.NodeID, err error) {
+		require.Error(t, err)
+		invalidCh <- peerID
+	}
+	_, err = router.OpenChannel(ctx, &hookDesc)
+	require.NoError(t, err)
+	close(opened)
+
+	select {
+	case id := <-invalidCh:
+		require.Equal(t, peerID, id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("invalid message was not reported")
+	}
+
+	router.Stop()
+	mockTransport.AssertExpectations(t)
+}
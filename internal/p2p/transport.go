@@ -52,6 +52,10 @@ type Transport interface {
 	// temporarily
 	AddChannelDescriptors([]*ChannelDescriptor)
 
+	// RemoveChannelDescriptor removes the descriptor of a closed channel, such
+	// that new connections no longer set it up.
+	RemoveChannelDescriptor(ChannelID)
+
 	// Stringer is used to display the transport, e.g. in logs.
 	//
 	// Without this, the logger may use reflection to access and display
@@ -70,6 +74,11 @@ type Transport interface {
 // requires MConnection protocol changes or a shim. For details, see:
 // https://github.com/tendermint/spec/pull/227
 //
+// There is deliberately no QUIC transport yet. It would mean taking on
+// quic-go, a large dependency with a significant maintenance cost, and the
+// Router only drives a single transport, so QUIC couldn't be offered next to
+// MConnection without first selecting transports by protocol.
+//
 // FIXME: The interface is currently very broad in order to accommodate
 // MConnection behavior that the legacy P2P stack relies on. It should be
 // cleaned up when the legacy stack is removed.
@@ -121,6 +130,10 @@ type Endpoint struct {
 	// endpoint as a networked endpoint.
 	IP net.IP
 
+	// Hostname is an unresolved hostname to connect to, used instead of IP
+	// for Tor onion services, which can only be dialed through a proxy.
+	Hostname string
+
 	// Port is a network port (either TCP or UDP). If 0, a default port may be
 	// used depending on the protocol.
 	Port uint16
@@ -150,9 +163,13 @@ func (e Endpoint) NodeAddress(nodeID types.NodeID) NodeAddress {
 		Protocol: e.Protocol,
 		Path:     e.Path,
 	}
-	if len(e.IP) > 0 {
+	switch {
+	case len(e.IP) > 0:
 		address.Hostname = e.IP.String()
 		address.Port = e.Port
+	case e.Hostname != "":
+		address.Hostname = e.Hostname
+		address.Port = e.Port
 	}
 	return address
 }
@@ -162,7 +179,7 @@ func (e Endpoint) String() string {
 	// If this is a non-networked endpoint with a valid node ID as a path,
 	// assume that path is a node ID (to handle opaque URLs of the form
 	// scheme:id).
-	if e.IP == nil {
+	if e.IP == nil && e.Hostname == "" {
 		if nodeID, err := types.NewNodeID(e.Path); err == nil {
 			return e.NodeAddress(nodeID).String()
 		}
@@ -179,10 +196,16 @@ func (e Endpoint) Validate() error {
 	case len(e.IP) > 0 && e.IP.To16() == nil:
 		return fmt.Errorf("invalid IP address %v", e.IP)
 
-	case e.Port > 0 && len(e.IP) == 0:
+	case len(e.IP) > 0 && e.Hostname != "":
+		return errors.New("endpoint has both IP and hostname")
+
+	case e.Hostname != "" && !types.IsOnionHost(e.Hostname):
+		return fmt.Errorf("endpoint hostname %q is not an onion address", e.Hostname)
+
+	case e.Port > 0 && len(e.IP) == 0 && e.Hostname == "":
 		return fmt.Errorf("endpoint has port %v but no IP", e.Port)
 
-	case len(e.IP) == 0 && e.Path == "":
+	case len(e.IP) == 0 && e.Hostname == "" && e.Path == "":
 		return errors.New("endpoint has neither path nor IP")
 
 	default:
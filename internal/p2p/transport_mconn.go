@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -36,14 +37,45 @@ type MConnTransportOptions struct {
 	// Router, since it will need to do e.g. rate limiting and such as well.
 	// But it might also make sense to have per-transport limits.
 	MaxAcceptedConnections uint32
+
+	// Dialer is used to dial outgoing connections, e.g. through a SOCKS5
+	// proxy. If nil, connections are dialed directly.
+	Dialer ContextDialer
+
+	// WebSocketListenAddress, if set, is a host:port address on which we also
+	// accept connections tunneled through WebSocket, for peers behind
+	// firewalls that only allow web traffic.
+	WebSocketListenAddress string
+
+	// WebSocketTLSConfig, if set, serves WebSocket connections over HTTPS
+	// (wss), otherwise they are served over plain HTTP (ws), e.g. behind a
+	// TLS-terminating reverse proxy.
+	WebSocketTLSConfig *tls.Config
+
+	// HandshakeProtocols, if set, are the handshake protocols we negotiate
+	// with peers. Otherwise, we use the legacy secret connection handshake
+	// without negotiation, which is compatible with older peers. Including
+	// conn.HandshakeSecretConnection falls back to the legacy handshake with
+	// peers that don't negotiate: accepted connections from them are
+	// detected, and they are redialed when dialing them fails to negotiate.
+	HandshakeProtocols []conn.HandshakeProtocol
+}
+
+// ContextDialer dials network connections.
+type ContextDialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
 }
 
 // MConnTransport is a Transport implementation using the current multiplexed
 // Tendermint protocol ("MConn").
 type MConnTransport struct {
-	logger       log.Logger
-	options      MConnTransportOptions
-	mConnConfig  conn.MConnConfig
+	logger      log.Logger
+	options     MConnTransportOptions
+	mConnConfig conn.MConnConfig
+
+	// channelDescs is replaced rather than modified when channels are added
+	// or removed, so each connection can use a snapshot of it.
+	channelMtx   sync.RWMutex
 	channelDescs []*ChannelDescriptor
 
 	closeOnce sync.Once
@@ -74,9 +106,10 @@ func (m *MConnTransport) String() string {
 	return string(MConnProtocol)
 }
 
-// Protocols implements Transport. We support tcp for backwards-compatibility.
+// Protocols implements Transport. We support tcp for backwards-compatibility,
+// and WebSocket for tunneling through firewalls.
 func (m *MConnTransport) Protocols() []Protocol {
-	return []Protocol{MConnProtocol, TCPProtocol}
+	return []Protocol{MConnProtocol, TCPProtocol, WebSocketProtocol, WebSocketSecureProtocol}
 }
 
 // Endpoint implements Transport.
@@ -120,6 +153,14 @@ func (m *MConnTransport) Listen(endpoint *Endpoint) error {
 	if err != nil {
 		return err
 	}
+	if m.options.WebSocketListenAddress != "" {
+		wsListener, err := listenWebSocket(m.options.WebSocketListenAddress, m.options.WebSocketTLSConfig)
+		if err != nil {
+			listener.Close()
+			return fmt.Errorf("failed to listen for WebSocket connections: %w", err)
+		}
+		listener = newMultiListener(listener, wsListener)
+	}
 	if m.options.MaxAcceptedConnections > 0 {
 		// FIXME: This will establish the inbound connection but simply hang it
 		// until another connection is released. It would probably be better to
@@ -165,7 +206,7 @@ func (m *MConnTransport) Accept(ctx context.Context) (Connection, error) {
 	case err := <-errCh:
 		return nil, err
 	case tcpConn := <-conCh:
-		return newMConnConnection(m.logger, tcpConn, m.mConnConfig, m.channelDescs), nil
+		return newMConnConnection(m.logger, tcpConn, m.mConnConfig, m.channels(), m.options.HandshakeProtocols, nil), nil
 	}
 
 }
@@ -175,13 +216,37 @@ func (m *MConnTransport) Dial(ctx context.Context, endpoint *Endpoint) (Connecti
 	if err := m.validateEndpoint(endpoint); err != nil {
 		return nil, err
 	}
-	if endpoint.Port == 0 {
+	if endpoint.Port == 0 && !isWebSocket(endpoint.Protocol) {
 		endpoint.Port = 26657
 	}
+	tcpConn, err := m.dial(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	redial := func(ctx context.Context) (net.Conn, error) { return m.dial(ctx, endpoint) }
+	return newMConnConnection(m.logger, tcpConn, m.mConnConfig, m.channels(), m.options.HandshakeProtocols, redial), nil
+}
 
-	dialer := net.Dialer{}
-	tcpConn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(
-		endpoint.IP.String(), strconv.Itoa(int(endpoint.Port))))
+// dial opens a network connection to a validated endpoint.
+func (m *MConnTransport) dial(ctx context.Context, endpoint *Endpoint) (net.Conn, error) {
+	var dialer ContextDialer = &net.Dialer{}
+	if m.options.Dialer != nil {
+		dialer = m.options.Dialer
+	}
+	host := endpoint.IP.String()
+	if endpoint.Hostname != "" {
+		if m.options.Dialer == nil {
+			return nil, fmt.Errorf("can't dial onion address %v without a proxy", endpoint.Hostname)
+		}
+		host = endpoint.Hostname
+	}
+	var tcpConn net.Conn
+	var err error
+	if isWebSocket(endpoint.Protocol) {
+		tcpConn, err = dialWebSocket(ctx, dialer, endpoint.Protocol, webSocketAddress(host, endpoint))
+	} else {
+		tcpConn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(int(endpoint.Port))))
+	}
 	if err != nil {
 		select {
 		case <-ctx.Done():
@@ -190,8 +255,7 @@ func (m *MConnTransport) Dial(ctx context.Context, endpoint *Endpoint) (Connecti
 			return nil, err
 		}
 	}
-
-	return newMConnConnection(m.logger, tcpConn, m.mConnConfig, m.channelDescs), nil
+	return tcpConn, nil
 }
 
 // Close implements Transport.
@@ -214,7 +278,48 @@ func (m *MConnTransport) Close() error {
 // connections should be agnostic to everything but the channel ID's which are
 // initialized in the handshake.
 func (m *MConnTransport) AddChannelDescriptors(channelDesc []*ChannelDescriptor) {
-	m.channelDescs = append(m.channelDescs, channelDesc...)
+	m.channelMtx.Lock()
+	defer m.channelMtx.Unlock()
+
+	channelDescs := make([]*ChannelDescriptor, len(m.channelDescs), len(m.channelDescs)+len(channelDesc))
+	copy(channelDescs, m.channelDescs)
+	for _, chDesc := range channelDesc {
+		replaced := false
+		for i, existing := range channelDescs {
+			if existing.ID == chDesc.ID {
+				channelDescs[i] = chDesc
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			channelDescs = append(channelDescs, chDesc)
+		}
+	}
+	m.channelDescs = channelDescs
+}
+
+// RemoveChannelDescriptor removes a channel descriptor, such that new
+// connections no longer set up the channel.
+func (m *MConnTransport) RemoveChannelDescriptor(id ChannelID) {
+	m.channelMtx.Lock()
+	defer m.channelMtx.Unlock()
+
+	channelDescs := make([]*ChannelDescriptor, 0, len(m.channelDescs))
+	for _, chDesc := range m.channelDescs {
+		if chDesc.ID != id {
+			channelDescs = append(channelDescs, chDesc)
+		}
+	}
+	m.channelDescs = channelDescs
+}
+
+// channels returns a snapshot of the channel descriptors for a new
+// connection.
+func (m *MConnTransport) channels() []*ChannelDescriptor {
+	m.channelMtx.RLock()
+	defer m.channelMtx.RUnlock()
+	return m.channelDescs
 }
 
 // validateEndpoint validates an endpoint.
@@ -222,10 +327,10 @@ func (m *MConnTransport) validateEndpoint(endpoint *Endpoint) error {
 	if err := endpoint.Validate(); err != nil {
 		return err
 	}
-	if endpoint.Protocol != MConnProtocol && endpoint.Protocol != TCPProtocol {
+	if endpoint.Protocol != MConnProtocol && endpoint.Protocol != TCPProtocol && !isWebSocket(endpoint.Protocol) {
 		return fmt.Errorf("unsupported protocol %q", endpoint.Protocol)
 	}
-	if len(endpoint.IP) == 0 {
+	if len(endpoint.IP) == 0 && endpoint.Hostname == "" {
 		return errors.New("endpoint has no IP address")
 	}
 	if endpoint.Path != "" {
@@ -234,18 +339,31 @@ func (m *MConnTransport) validateEndpoint(endpoint *Endpoint) error {
 	return nil
 }
 
+// isWebSocket returns true if the protocol tunnels through WebSocket.
+func isWebSocket(protocol Protocol) bool {
+	return protocol == WebSocketProtocol || protocol == WebSocketSecureProtocol
+}
+
 // mConnConnection implements Connection for MConnTransport.
 type mConnConnection struct {
 	logger       log.Logger
-	conn         net.Conn
 	mConnConfig  conn.MConnConfig
 	channelDescs []*ChannelDescriptor
+	protocols    []conn.HandshakeProtocol
 	receiveCh    chan mConnMessage
 	errorCh      chan error
 	doneCh       chan struct{}
 	closeOnce    sync.Once
 
 	mconn *conn.MConnection // set during Handshake()
+
+	// conn is the network connection, which is replaced if the peer is
+	// redialed for the legacy handshake, unless connClosed. redial is set
+	// for outbound connections.
+	connMtx    sync.Mutex
+	conn       net.Conn
+	connClosed bool
+	redial     func(context.Context) (net.Conn, error)
 }
 
 // mConnMessage passes MConnection messages through internal channels.
@@ -260,12 +378,16 @@ func newMConnConnection(
 	conn net.Conn,
 	mConnConfig conn.MConnConfig,
 	channelDescs []*ChannelDescriptor,
+	protocols []conn.HandshakeProtocol,
+	redial func(context.Context) (net.Conn, error),
 ) *mConnConnection {
 	return &mConnConnection{
 		logger:       logger,
 		conn:         conn,
+		redial:       redial,
 		mConnConfig:  mConnConfig,
 		channelDescs: channelDescs,
+		protocols:    protocols,
 		receiveCh:    make(chan mConnMessage),
 		errorCh:      make(chan error, 1), // buffered to avoid onError leak
 		doneCh:       make(chan struct{}),
@@ -345,7 +467,7 @@ func (c *mConnConnection) handshake(
 		return nil, types.NodeInfo{}, nil, errors.New("connection is already handshaked")
 	}
 
-	secretConn, err := conn.MakeSecretConnection(c.conn, privKey)
+	secretConn, err := c.authenticate(ctx, privKey)
 	if err != nil {
 		return nil, types.NodeInfo{}, nil, err
 	}
@@ -388,18 +510,41 @@ func (c *mConnConnection) handshake(
 		return nil, types.NodeInfo{}, nil, err
 	}
 
+	mConnConfig := c.mConnConfig
+	mConnConfig.Compression = negotiateCompression(nodeInfo.Compression, peerInfo.Compression)
+	if mConnConfig.Compression != "" {
+		c.logger.Debug("negotiated compression", "compression", mConnConfig.Compression)
+	}
+
 	mconn := conn.NewMConnection(
 		c.logger.With("peer", c.RemoteEndpoint().NodeAddress(peerInfo.NodeID)),
 		secretConn,
 		c.channelDescs,
 		c.onReceive,
 		c.onError,
-		c.mConnConfig,
+		mConnConfig,
 	)
 
 	return mconn, peerInfo, secretConn.RemotePubKey(), nil
 }
 
+// negotiateCompression returns the first compression algorithm in the local
+// node's order of preference that is also supported by the peer and by
+// MConnection, or an empty string if there is none.
+func negotiateCompression(local, remote []string) string {
+	for _, c := range local {
+		if c != conn.CompressionSnappy {
+			continue
+		}
+		for _, r := range remote {
+			if r == c {
+				return c
+			}
+		}
+	}
+	return ""
+}
+
 // onReceive is a callback for MConnection received messages.
 func (c *mConnConnection) onReceive(ctx context.Context, chID ChannelID, payload []byte) {
 	select {
@@ -415,13 +560,15 @@ func (c *mConnConnection) onError(ctx context.Context, e interface{}) {
 	if !ok {
 		err = fmt.Errorf("%v", err)
 	}
-	// We have to close the connection here, since MConnection will have stopped
-	// the service on any errors.
-	_ = c.Close()
+	// We pass on the error before closing the connection, such that
+	// ReceiveMessage returns it rather than io.EOF.
 	select {
 	case c.errorCh <- err:
 	case <-ctx.Done():
 	}
+	// We have to close the connection here, since MConnection will have stopped
+	// the service on any errors.
+	_ = c.Close()
 }
 
 // String displays connection information.
@@ -437,6 +584,8 @@ func (c *mConnConnection) SendMessage(ctx context.Context, chID ChannelID, msg [
 	select {
 	case err := <-c.errorCh:
 		return err
+	case <-c.doneCh:
+		return io.EOF
 	case <-ctx.Done():
 		return io.EOF
 	default:
@@ -454,7 +603,12 @@ func (c *mConnConnection) ReceiveMessage(ctx context.Context) (ChannelID, []byte
 	case err := <-c.errorCh:
 		return 0, nil, err
 	case <-c.doneCh:
-		return 0, nil, io.EOF
+		select {
+		case err := <-c.errorCh:
+			return 0, nil, err
+		default:
+			return 0, nil, io.EOF
+		}
 	case <-ctx.Done():
 		return 0, nil, io.EOF
 	case msg := <-c.receiveCh:
@@ -462,12 +616,83 @@ func (c *mConnConnection) ReceiveMessage(ctx context.Context) (ChannelID, []byte
 	}
 }
 
+// authenticate performs the handshake that authenticates and encrypts the
+// connection, negotiating it if we have handshake protocols. If the peer we
+// dialed doesn't negotiate and we support the legacy handshake, the peer is
+// redialed for it.
+func (c *mConnConnection) authenticate(ctx context.Context, privKey crypto.PrivKey) (conn.AuthenticatedConn, error) {
+	netConn := c.netConn()
+	if len(c.protocols) == 0 {
+		return conn.MakeSecretConnection(netConn, privKey)
+	}
+
+	outbound := c.redial != nil
+	secretConn, protocol, err := conn.MakeNegotiatedConnection(netConn, privKey, c.protocols, outbound)
+	if errors.Is(err, conn.ErrHandshakeNotNegotiated) && outbound && c.supportsLegacyHandshake() {
+		c.logger.Debug("peer does not negotiate the handshake, redialing for the legacy handshake",
+			"peer", netConn.RemoteAddr())
+		_ = netConn.Close()
+		if netConn, err = c.redial(ctx); err != nil {
+			return nil, err
+		}
+		if err = c.setNetConn(netConn); err != nil {
+			return nil, err
+		}
+		secretConn, err = conn.MakeSecretConnection(netConn, privKey)
+		protocol = conn.HandshakeSecretConnection
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("negotiated handshake protocol", "protocol", protocol)
+	return secretConn, nil
+}
+
+// supportsLegacyHandshake returns true if we fall back to the legacy
+// handshake with peers that don't negotiate.
+func (c *mConnConnection) supportsLegacyHandshake() bool {
+	for _, p := range c.protocols {
+		if p == conn.HandshakeSecretConnection {
+			return true
+		}
+	}
+	return false
+}
+
+// netConn returns the network connection.
+func (c *mConnConnection) netConn() net.Conn {
+	c.connMtx.Lock()
+	defer c.connMtx.Unlock()
+	return c.conn
+}
+
+// setNetConn replaces the network connection, unless the connection has been
+// closed meanwhile, in which case it's closed instead.
+func (c *mConnConnection) setNetConn(netConn net.Conn) error {
+	c.connMtx.Lock()
+	defer c.connMtx.Unlock()
+	if c.connClosed {
+		_ = netConn.Close()
+		return io.EOF
+	}
+	c.conn = netConn
+	return nil
+}
+
+// closeNetConn closes the network connection.
+func (c *mConnConnection) closeNetConn() error {
+	c.connMtx.Lock()
+	defer c.connMtx.Unlock()
+	c.connClosed = true
+	return c.conn.Close()
+}
+
 // LocalEndpoint implements Connection.
 func (c *mConnConnection) LocalEndpoint() Endpoint {
 	endpoint := Endpoint{
 		Protocol: MConnProtocol,
 	}
-	if addr, ok := c.conn.LocalAddr().(*net.TCPAddr); ok {
+	if addr, ok := c.netConn().LocalAddr().(*net.TCPAddr); ok {
 		endpoint.IP = addr.IP
 		endpoint.Port = uint16(addr.Port)
 	}
@@ -479,13 +704,38 @@ func (c *mConnConnection) RemoteEndpoint() Endpoint {
 	endpoint := Endpoint{
 		Protocol: MConnProtocol,
 	}
-	if addr, ok := c.conn.RemoteAddr().(*net.TCPAddr); ok {
+	if addr, ok := c.netConn().RemoteAddr().(*net.TCPAddr); ok {
 		endpoint.IP = addr.IP
 		endpoint.Port = uint16(addr.Port)
 	}
 	return endpoint
 }
 
+// RTT returns the smoothed ping round-trip time of the connection, or 0 if it
+// hasn't been measured yet.
+func (c *mConnConnection) RTT() time.Duration {
+	if c.mconn == nil {
+		return 0
+	}
+	return c.mconn.RTT()
+}
+
+// Disconnect tells the peer why the connection is being closed, if the
+// connection is running, and closes it.
+func (c *mConnConnection) Disconnect(reason p2pproto.DisconnectReason) error {
+	var err error
+	c.closeOnce.Do(func() {
+		defer close(c.doneCh)
+
+		if c.mconn != nil && c.mconn.IsRunning() {
+			c.mconn.Disconnect(reason)
+		} else {
+			err = c.closeNetConn()
+		}
+	})
+	return err
+}
+
 // Close implements Connection.
 func (c *mConnConnection) Close() error {
 	var err error
@@ -495,7 +745,7 @@ func (c *mConnConnection) Close() error {
 		if c.mconn != nil && c.mconn.IsRunning() {
 			c.mconn.Stop()
 		} else {
-			err = c.conn.Close()
+			err = c.closeNetConn()
 		}
 	})
 	return err
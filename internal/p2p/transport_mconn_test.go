@@ -2,17 +2,28 @@ package p2p_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
 	"io"
+	"math/big"
 	"net"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/fortytw2/leaktest"
 	"github.com/stretchr/testify/require"
 
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/internal/p2p"
 	"github.com/tendermint/tendermint/internal/p2p/conn"
 	"github.com/tendermint/tendermint/libs/log"
+	tmnet "github.com/tendermint/tendermint/libs/net"
+	"github.com/tendermint/tendermint/types"
 )
 
 // Transports are mainly tested by common tests in transport_test.go, we
@@ -36,6 +47,27 @@ func init() {
 
 		return transport
 	}
+
+	testTransports["mconn-noise"] = func(t *testing.T) p2p.Transport {
+		transport := p2p.NewMConnTransport(
+			log.NewNopLogger(),
+			conn.DefaultMConnConfig(),
+			[]*p2p.ChannelDescriptor{{ID: chID, Priority: 1}},
+			p2p.MConnTransportOptions{
+				HandshakeProtocols: []conn.HandshakeProtocol{conn.HandshakeNoise},
+			},
+		)
+		err := transport.Listen(&p2p.Endpoint{
+			Protocol: p2p.MConnProtocol,
+			IP:       net.IPv4(127, 0, 0, 1),
+			Port:     0, // assign a random port
+		})
+		require.NoError(t, err)
+
+		t.Cleanup(func() { _ = transport.Close() })
+
+		return transport
+	}
 }
 
 func TestMConnTransport_AcceptBeforeListen(t *testing.T) {
@@ -132,6 +164,84 @@ func TestMConnTransport_AcceptMaxAcceptedConnections(t *testing.T) {
 	require.Equal(t, dial3.LocalEndpoint(), accept3.RemoteEndpoint())
 }
 
+// recordingDialer records the addresses it dials, before dialing directly.
+type recordingDialer struct {
+	mtx    sync.Mutex
+	dialed []string
+}
+
+func (d *recordingDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	d.mtx.Lock()
+	d.dialed = append(d.dialed, address)
+	d.mtx.Unlock()
+	return (&net.Dialer{}).DialContext(ctx, network, address)
+}
+
+func TestMConnTransport_Dialer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dialer := &recordingDialer{}
+	transport := p2p.NewMConnTransport(
+		log.NewNopLogger(),
+		conn.DefaultMConnConfig(),
+		[]*p2p.ChannelDescriptor{{ID: chID, Priority: 1}},
+		p2p.MConnTransportOptions{Dialer: dialer},
+	)
+	t.Cleanup(func() {
+		_ = transport.Close()
+	})
+	err := transport.Listen(&p2p.Endpoint{
+		Protocol: p2p.MConnProtocol,
+		IP:       net.IPv4(127, 0, 0, 1),
+	})
+	require.NoError(t, err)
+	endpoint, err := transport.Endpoint()
+	require.NoError(t, err)
+
+	go func() {
+		if conn, err := transport.Accept(ctx); err == nil {
+			_ = conn.Close()
+		}
+	}()
+
+	dialConn, err := transport.Dial(ctx, endpoint)
+	require.NoError(t, err)
+	defer dialConn.Close()
+
+	dialer.mtx.Lock()
+	defer dialer.mtx.Unlock()
+	require.Equal(t, []string{net.JoinHostPort(endpoint.IP.String(), strconv.Itoa(int(endpoint.Port)))}, dialer.dialed)
+}
+
+func TestMConnTransport_DialOnion(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	endpoint := &p2p.Endpoint{
+		Protocol: p2p.MConnProtocol,
+		Hostname: "vww6ybal4bd7szmgncyruucpgfkqahzddi37ktceo3ah7ngmcopnpyyd.onion",
+		Port:     26656,
+	}
+
+	// Onion addresses can't be dialed directly.
+	transport := p2p.NewMConnTransport(log.NewNopLogger(), conn.DefaultMConnConfig(),
+		[]*p2p.ChannelDescriptor{}, p2p.MConnTransportOptions{})
+	_, err := transport.Dial(ctx, endpoint)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "without a proxy")
+
+	// With a proxy, the hostname is passed on to it.
+	dialer := &recordingDialer{}
+	transport = p2p.NewMConnTransport(log.NewNopLogger(), conn.DefaultMConnConfig(),
+		[]*p2p.ChannelDescriptor{}, p2p.MConnTransportOptions{Dialer: dialer})
+	_, _ = transport.Dial(ctx, endpoint)
+
+	dialer.mtx.Lock()
+	defer dialer.mtx.Unlock()
+	require.Equal(t, []string{endpoint.Hostname + ":26656"}, dialer.dialed)
+}
+
 func TestMConnTransport_Listen(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -232,3 +342,263 @@ func TestMConnTransport_Listen(t *testing.T) {
 		})
 	}
 }
+
+func TestMConnTransport_WebSocket(t *testing.T) {
+	testcases := map[string]struct {
+		protocol  p2p.Protocol
+		tlsConfig *tls.Config
+	}{
+		"ws":  {p2p.WebSocketProtocol, nil},
+		"wss": {p2p.WebSocketSecureProtocol, selfSignedTLSConfig(t)},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			port, err := tmnet.GetFreePort()
+			require.NoError(t, err)
+
+			transport := p2p.NewMConnTransport(
+				log.NewNopLogger(),
+				conn.DefaultMConnConfig(),
+				[]*p2p.ChannelDescriptor{{ID: chID, Priority: 1}},
+				p2p.MConnTransportOptions{
+					WebSocketListenAddress: net.JoinHostPort("127.0.0.1", strconv.Itoa(port)),
+					WebSocketTLSConfig:     tc.tlsConfig,
+				},
+			)
+			t.Cleanup(func() { _ = transport.Close() })
+			require.NoError(t, transport.Listen(&p2p.Endpoint{
+				Protocol: p2p.MConnProtocol,
+				IP:       net.IPv4(127, 0, 0, 1),
+			}))
+			require.Contains(t, transport.Protocols(), tc.protocol)
+
+			acceptCh := make(chan p2p.Connection, 1)
+			go func() {
+				if conn, err := transport.Accept(ctx); err == nil {
+					acceptCh <- conn
+				}
+			}()
+
+			dialConn, err := transport.Dial(ctx, &p2p.Endpoint{
+				Protocol: tc.protocol,
+				IP:       net.IPv4(127, 0, 0, 1),
+				Port:     uint16(port),
+			})
+			require.NoError(t, err)
+			defer dialConn.Close()
+			acceptConn := <-acceptCh
+			defer acceptConn.Close()
+
+			// The tunneled connections handshake and exchange messages like
+			// any other MConn connection.
+			errCh := make(chan error, 1)
+			go func() {
+				privKey := ed25519.GenPrivKey()
+				nodeInfo := types.NodeInfo{NodeID: types.NodeIDFromPubKey(privKey.PubKey())}
+				_, _, err := acceptConn.Handshake(ctx, 0, nodeInfo, privKey)
+				errCh <- err
+			}()
+			privKey := ed25519.GenPrivKey()
+			nodeInfo := types.NodeInfo{NodeID: types.NodeIDFromPubKey(privKey.PubKey())}
+			_, _, err = dialConn.Handshake(ctx, 0, nodeInfo, privKey)
+			require.NoError(t, err)
+			require.NoError(t, <-errCh)
+
+			require.NoError(t, dialConn.SendMessage(ctx, chID, []byte("foo")))
+			ch, msg, err := acceptConn.ReceiveMessage(ctx)
+			require.NoError(t, err)
+			require.Equal(t, chID, ch)
+			require.Equal(t, []byte("foo"), msg)
+
+			require.NoError(t, acceptConn.SendMessage(ctx, chID, []byte("bar")))
+			_, msg, err = dialConn.ReceiveMessage(ctx)
+			require.NoError(t, err)
+			require.Equal(t, []byte("bar"), msg)
+		})
+	}
+}
+
+// selfSignedTLSConfig generates a TLS config with a self-signed certificate.
+func selfSignedTLSConfig(t *testing.T) *tls.Config {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		MinVersion:   tls.VersionTLS12,
+	}
+}
+
+func TestMConnTransport_Compression(t *testing.T) {
+	snappy := []string{conn.CompressionSnappy}
+	testcases := map[string]struct {
+		dialCompression   []string
+		acceptCompression []string
+	}{
+		"both":     {snappy, snappy},
+		"dialer":   {snappy, nil},
+		"acceptor": {nil, snappy},
+		"unknown":  {[]string{"zstd"}, []string{"zstd"}},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			transport := p2p.NewMConnTransport(
+				log.NewNopLogger(),
+				conn.DefaultMConnConfig(),
+				[]*p2p.ChannelDescriptor{{ID: chID, Priority: 1, Compress: true}},
+				p2p.MConnTransportOptions{},
+			)
+			t.Cleanup(func() { _ = transport.Close() })
+			require.NoError(t, transport.Listen(&p2p.Endpoint{
+				Protocol: p2p.MConnProtocol,
+				IP:       net.IPv4(127, 0, 0, 1),
+			}))
+
+			acceptCh := make(chan p2p.Connection, 1)
+			go func() {
+				if conn, err := transport.Accept(ctx); err == nil {
+					acceptCh <- conn
+				}
+			}()
+			endpoint, err := transport.Endpoint()
+			require.NoError(t, err)
+			dialConn, err := transport.Dial(ctx, endpoint)
+			require.NoError(t, err)
+			defer dialConn.Close()
+			acceptConn := <-acceptCh
+			defer acceptConn.Close()
+
+			errCh := make(chan error, 1)
+			go func() {
+				privKey := ed25519.GenPrivKey()
+				nodeInfo := types.NodeInfo{
+					NodeID:      types.NodeIDFromPubKey(privKey.PubKey()),
+					Compression: tc.acceptCompression,
+				}
+				_, _, err := acceptConn.Handshake(ctx, 0, nodeInfo, privKey)
+				errCh <- err
+			}()
+			privKey := ed25519.GenPrivKey()
+			nodeInfo := types.NodeInfo{
+				NodeID:      types.NodeIDFromPubKey(privKey.PubKey()),
+				Compression: tc.dialCompression,
+			}
+			_, _, err = dialConn.Handshake(ctx, 0, nodeInfo, privKey)
+			require.NoError(t, err)
+			require.NoError(t, <-errCh)
+
+			// Both sides must agree on whether the channel is compressed.
+			msg := make([]byte, 4096)
+			require.NoError(t, dialConn.SendMessage(ctx, chID, msg))
+			_, received, err := acceptConn.ReceiveMessage(ctx)
+			require.NoError(t, err)
+			require.Equal(t, msg, received)
+
+			require.NoError(t, acceptConn.SendMessage(ctx, chID, []byte("bar")))
+			_, received, err = dialConn.ReceiveMessage(ctx)
+			require.NoError(t, err)
+			require.Equal(t, []byte("bar"), received)
+		})
+	}
+}
+
+func TestMConnTransport_LegacyHandshakeFallback(t *testing.T) {
+	noise := []conn.HandshakeProtocol{conn.HandshakeNoise}
+	fallback := []conn.HandshakeProtocol{conn.HandshakeNoise, conn.HandshakeSecretConnection}
+	testcases := map[string]struct {
+		dialProtocols   []conn.HandshakeProtocol
+		acceptProtocols []conn.HandshakeProtocol
+		ok              bool
+	}{
+		"negotiating node dials legacy node":   {fallback, nil, true},
+		"legacy node dials negotiating node":   {nil, fallback, true},
+		"negotiating nodes":                    {fallback, fallback, true},
+		"dialing legacy node without fallback": {noise, nil, false},
+		"legacy node dials without fallback":   {nil, noise, false},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			newTransport := func(protocols []conn.HandshakeProtocol) *p2p.MConnTransport {
+				transport := p2p.NewMConnTransport(
+					log.NewNopLogger(),
+					conn.DefaultMConnConfig(),
+					[]*p2p.ChannelDescriptor{{ID: chID, Priority: 1}},
+					p2p.MConnTransportOptions{HandshakeProtocols: protocols},
+				)
+				t.Cleanup(func() { _ = transport.Close() })
+				require.NoError(t, transport.Listen(&p2p.Endpoint{
+					Protocol: p2p.MConnProtocol,
+					IP:       net.IPv4(127, 0, 0, 1),
+				}))
+				return transport
+			}
+			dialer := newTransport(tc.dialProtocols)
+			acceptor := newTransport(tc.acceptProtocols)
+			dialKey, acceptKey := ed25519.GenPrivKey(), ed25519.GenPrivKey()
+
+			// The acceptor handshakes every connection, since a dialer that
+			// falls back to the legacy handshake connects twice.
+			acceptCh := make(chan p2p.Connection, 2)
+			go func() {
+				for {
+					c, err := acceptor.Accept(ctx)
+					if err != nil {
+						return
+					}
+					go func() {
+						nodeInfo := types.NodeInfo{NodeID: types.NodeIDFromPubKey(acceptKey.PubKey())}
+						if _, _, err := c.Handshake(ctx, 0, nodeInfo, acceptKey); err != nil {
+							_ = c.Close()
+							return
+						}
+						acceptCh <- c
+					}()
+				}
+			}()
+
+			endpoint, err := acceptor.Endpoint()
+			require.NoError(t, err)
+			dialConn, err := dialer.Dial(ctx, endpoint)
+			require.NoError(t, err)
+			defer dialConn.Close()
+
+			nodeInfo := types.NodeInfo{NodeID: types.NodeIDFromPubKey(dialKey.PubKey())}
+			_, peerKey, err := dialConn.Handshake(ctx, 5*time.Second, nodeInfo, dialKey)
+			if !tc.ok {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, peerKey.Equals(acceptKey.PubKey()))
+
+			acceptConn := <-acceptCh
+			defer acceptConn.Close()
+			require.NoError(t, dialConn.SendMessage(ctx, chID, []byte("foo")))
+			_, received, err := acceptConn.ReceiveMessage(ctx)
+			require.NoError(t, err)
+			require.Equal(t, []byte("foo"), received)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net"
 	"sync"
 	"time"
@@ -23,12 +24,23 @@ const (
 //
 // Network endpoints are allocated via CreateTransport(), which takes a node ID,
 // and the endpoint is then immediately accessible via the URL "memory:<nodeID>".
+//
+// Network conditions can be simulated with SetLatency(), SetPacketLoss() and
+// Partition(), which apply to all messages sent after the call.
 type MemoryNetwork struct {
 	logger log.Logger
 
 	mtx        sync.RWMutex
 	transports map[types.NodeID]*MemoryTransport
 	bufferSize int
+
+	// latency delays the delivery of each message, lossRate is the fraction
+	// of messages dropped as drawn from lossRand, and partitions maps nodes to
+	// partition groups, where nodes can only reach nodes in the same group.
+	latency    time.Duration
+	lossRate   float64
+	lossRand   *rand.Rand
+	partitions map[types.NodeID]int
 }
 
 // NewMemoryNetwork creates a new in-memory network.
@@ -83,6 +95,71 @@ func (n *MemoryNetwork) Size() int {
 	return len(n.transports)
 }
 
+// SetLatency delays the delivery of every message by the given latency. The
+// order of messages on each connection is preserved.
+func (n *MemoryNetwork) SetLatency(latency time.Duration) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.latency = latency
+}
+
+// SetPacketLoss drops the given fraction of messages, between 0 and 1, as
+// if lost in transit. The messages to drop are drawn from a random source
+// with the given seed, so that runs are reproducible. Handshakes are never
+// dropped.
+func (n *MemoryNetwork) SetPacketLoss(rate float64, seed int64) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.lossRate = rate
+	n.lossRand = rand.New(rand.NewSource(seed)) // nolint: gosec
+}
+
+// Partition splits the network into the given groups of nodes. Nodes can only
+// dial and send messages to nodes in the same group, while messages sent to
+// nodes in other groups are dropped. Nodes that aren't in any group form a
+// group of their own.
+func (n *MemoryNetwork) Partition(groups ...[]types.NodeID) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.partitions = map[types.NodeID]int{}
+	for i, group := range groups {
+		for _, nodeID := range group {
+			n.partitions[nodeID] = i + 1
+		}
+	}
+}
+
+// Heal removes any partitions, such that all nodes can reach each other.
+func (n *MemoryNetwork) Heal() {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.partitions = nil
+}
+
+// reachable returns whether the nodes are in the same partition group.
+// The caller must hold the mutex lock.
+func (n *MemoryNetwork) reachable(a, b types.NodeID) bool {
+	return n.partitions[a] == n.partitions[b]
+}
+
+// deliver decides whether a message from one node to another is delivered,
+// and if so, when.
+func (n *MemoryNetwork) deliver(from, to types.NodeID) (time.Time, bool) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if !n.reachable(from, to) {
+		return time.Time{}, false
+	}
+	if n.lossRate > 0 && n.lossRand.Float64() < n.lossRate {
+		return time.Time{}, false
+	}
+	if n.latency > 0 {
+		return time.Now().Add(n.latency), true
+	}
+	return time.Time{}, true
+}
+
 // MemoryTransport is an in-memory transport that uses buffered Go channels to
 // communicate between endpoints. It is primarily meant for testing.
 //
@@ -124,6 +201,8 @@ func (*MemoryTransport) Listen(*Endpoint) error { return nil }
 
 func (t *MemoryTransport) AddChannelDescriptors([]*ChannelDescriptor) {}
 
+func (t *MemoryTransport) RemoveChannelDescriptor(ChannelID) {}
+
 // Protocols implements Transport.
 func (t *MemoryTransport) Protocols() []Protocol {
 	return []Protocol{MemoryProtocol}
@@ -180,6 +259,12 @@ func (t *MemoryTransport) Dial(ctx context.Context, endpoint *Endpoint) (Connect
 	if peer == nil {
 		return nil, fmt.Errorf("unknown peer %q", nodeID)
 	}
+	t.network.mtx.RLock()
+	reachable := t.network.reachable(t.nodeID, nodeID)
+	t.network.mtx.RUnlock()
+	if !reachable {
+		return nil, fmt.Errorf("peer %q is partitioned", nodeID)
+	}
 
 	inCh := make(chan memoryMessage, t.bufferSize)
 	outCh := make(chan memoryMessage, t.bufferSize)
@@ -191,9 +276,11 @@ func (t *MemoryTransport) Dial(ctx context.Context, endpoint *Endpoint) (Connect
 	outConn := newMemoryConnection(t.logger, t.nodeID, peer.nodeID, inCh, outCh)
 	outConn.closeCh = closeCh
 	outConn.closeFn = closeFn
+	outConn.network = t.network
 	inConn := newMemoryConnection(peer.logger, peer.nodeID, t.nodeID, outCh, inCh)
 	inConn.closeCh = closeCh
 	inConn.closeFn = closeFn
+	inConn.network = t.network
 
 	select {
 	case peer.acceptCh <- inConn:
@@ -221,12 +308,16 @@ type MemoryConnection struct {
 
 	closeFn func()
 	closeCh <-chan struct{}
+
+	// network simulates network conditions, if set.
+	network *MemoryNetwork
 }
 
 // memoryMessage is passed internally, containing either a message or handshake.
 type memoryMessage struct {
 	channelID ChannelID
 	message   []byte
+	deliverAt time.Time // when the message arrives, if delayed
 
 	// For handshakes.
 	nodeInfo *types.NodeInfo
@@ -321,6 +412,17 @@ func (c *MemoryConnection) ReceiveMessage(ctx context.Context) (ChannelID, []byt
 
 	select {
 	case msg := <-c.receiveCh:
+		if delay := time.Until(msg.deliverAt); delay > 0 {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return 0, nil, io.EOF
+			case <-c.closeCh:
+				return 0, nil, io.EOF
+			}
+		}
 		c.logger.Debug("received message", "chID", msg.channelID, "msg", msg.message)
 		return msg.channelID, msg.message, nil
 	case <-ctx.Done():
@@ -342,8 +444,17 @@ func (c *MemoryConnection) SendMessage(ctx context.Context, chID ChannelID, msg
 	default:
 	}
 
+	var deliverAt time.Time
+	if c.network != nil {
+		var ok bool
+		if deliverAt, ok = c.network.deliver(c.localID, c.remoteID); !ok {
+			c.logger.Debug("dropped message", "chID", chID, "msg", msg)
+			return nil
+		}
+	}
+
 	select {
-	case c.sendCh <- memoryMessage{channelID: chID, message: msg}:
+	case c.sendCh <- memoryMessage{channelID: chID, message: msg, deliverAt: deliverAt}:
 		c.logger.Debug("sent message", "chID", chID, "msg", msg)
 		return nil
 	case <-ctx.Done():
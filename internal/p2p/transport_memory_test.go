@@ -2,8 +2,11 @@ package p2p_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -34,3 +37,68 @@ func init() {
 		return transport
 	}
 }
+
+func TestMemoryNetwork_Conditions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	aID := types.NodeID(strings.Repeat("a", 40))
+	bID := types.NodeID(strings.Repeat("b", 40))
+	cID := types.NodeID(strings.Repeat("c", 40))
+	network := p2p.NewMemoryNetwork(log.NewNopLogger(), 100)
+	a := network.CreateTransport(aID)
+	b := network.CreateTransport(bID)
+	c := network.CreateTransport(cID)
+	ab, ba := dialAcceptHandshake(ctx, t, a, b)
+
+	// Messages are delayed by the latency.
+	network.SetLatency(50 * time.Millisecond)
+	start := time.Now()
+	require.NoError(t, ab.SendMessage(ctx, chID, []byte("latency")))
+	_, msg, err := ba.ReceiveMessage(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []byte("latency"), msg)
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	network.SetLatency(0)
+
+	// The same messages are dropped for the same seed.
+	received := func() []byte {
+		network.SetPacketLoss(0.5, 1)
+		for i := byte(0); i < 20; i++ {
+			require.NoError(t, ab.SendMessage(ctx, chID, []byte{i}))
+		}
+		network.SetPacketLoss(0, 0)
+		require.NoError(t, ab.SendMessage(ctx, chID, []byte("done")))
+
+		var got []byte
+		for {
+			_, msg, err := ba.ReceiveMessage(ctx)
+			require.NoError(t, err)
+			if string(msg) == "done" {
+				return got
+			}
+			got = append(got, msg...)
+		}
+	}
+	dropped := received()
+	require.NotEmpty(t, dropped)
+	require.Less(t, len(dropped), 20)
+	require.Equal(t, dropped, received())
+
+	// Nodes can't dial across partitions, and messages sent across them are
+	// dropped. Nodes outside of any group are partitioned from both groups.
+	network.Partition([]types.NodeID{aID}, []types.NodeID{bID})
+	for _, pair := range [][2]*p2p.MemoryTransport{{a, b}, {a, c}, {c, b}} {
+		endpoint, err := pair[1].Endpoint()
+		require.NoError(t, err)
+		_, err = pair[0].Dial(ctx, endpoint)
+		require.Error(t, err)
+	}
+	require.NoError(t, ab.SendMessage(ctx, chID, []byte("partitioned")))
+
+	network.Heal()
+	require.NoError(t, ab.SendMessage(ctx, chID, []byte("healed")))
+	_, msg, err = ba.ReceiveMessage(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []byte("healed"), msg)
+}
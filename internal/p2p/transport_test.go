@@ -563,6 +563,7 @@ func TestEndpoint_Validate(t *testing.T) {
 		ip4    = []byte{1, 2, 3, 4}
 		ip4in6 = net.IPv4(1, 2, 3, 4)
 		ip6    = []byte{0xb1, 0x0c, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01}
+		onion  = "vww6ybal4bd7szmgncyruucpgfkqahzddi37ktceo3ah7ngmcopnpyyd.onion"
 	)
 
 	testcases := []struct {
@@ -576,6 +577,7 @@ func TestEndpoint_Validate(t *testing.T) {
 		{p2p.Endpoint{Protocol: "tcp", IP: ip4, Port: 8008}, true},
 		{p2p.Endpoint{Protocol: "tcp", IP: ip4, Port: 8080, Path: "path"}, true},
 		{p2p.Endpoint{Protocol: "memory", Path: "path"}, true},
+		{p2p.Endpoint{Protocol: "tcp", Hostname: onion, Port: 8080}, true},
 
 		// Invalid endpoints.
 		{p2p.Endpoint{}, false},
@@ -583,6 +585,8 @@ func TestEndpoint_Validate(t *testing.T) {
 		{p2p.Endpoint{Protocol: "tcp"}, false},
 		{p2p.Endpoint{Protocol: "tcp", IP: []byte{1, 2, 3}}, false},
 		{p2p.Endpoint{Protocol: "tcp", Port: 8080, Path: "path"}, false},
+		{p2p.Endpoint{Protocol: "tcp", Hostname: "example.com", Port: 8080}, false},
+		{p2p.Endpoint{Protocol: "tcp", IP: ip4, Hostname: onion, Port: 8080}, false},
 	}
 	for _, tc := range testcases {
 		tc := tc
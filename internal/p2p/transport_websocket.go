@@ -0,0 +1,278 @@
+package p2p
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// WebSocketProtocol and WebSocketSecureProtocol tunnel MConn connections
+	// through WebSocket, respectively over plain HTTP and HTTPS, for peers
+	// behind firewalls that only allow web traffic.
+	WebSocketProtocol       Protocol = "ws"
+	WebSocketSecureProtocol Protocol = "wss"
+
+	// webSocketPath is the HTTP path that connections are tunneled through.
+	webSocketPath = "/p2p"
+
+	// webSocketReadLimit is the maximum size of a WebSocket message. Secret
+	// connection frames are well below this.
+	webSocketReadLimit = 64 * 1024
+
+	// webSocketHeaderTimeout is how long we wait for the HTTP request headers
+	// of an inbound WebSocket connection.
+	webSocketHeaderTimeout = 10 * time.Second
+)
+
+var webSocketUpgrader = websocket.Upgrader{
+	// Peers aren't browsers, so there's no origin to check.
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// dialWebSocket dials an MConn connection tunneled through WebSocket.
+func dialWebSocket(ctx context.Context, dialer ContextDialer, protocol Protocol, address string) (net.Conn, error) {
+	u := url.URL{Scheme: string(protocol), Host: address, Path: webSocketPath}
+	wsDialer := &websocket.Dialer{
+		NetDialContext: dialer.DialContext,
+		// Peers are authenticated by their node key during the secret
+		// connection handshake, so TLS is only used to get through firewalls
+		// and we don't verify the peer's certificate.
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // nolint:gosec
+	}
+	wsConn, _, err := wsDialer.DialContext(ctx, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return newWebSocketConn(wsConn), nil
+}
+
+// webSocketConn adapts a WebSocket connection to a net.Conn byte stream,
+// sending each write as a binary message. Like websocket.Conn, it supports
+// one concurrent reader and one concurrent writer.
+type webSocketConn struct {
+	*websocket.Conn
+	reader io.Reader // current message
+}
+
+var _ net.Conn = (*webSocketConn)(nil)
+
+func newWebSocketConn(conn *websocket.Conn) *webSocketConn {
+	conn.SetReadLimit(webSocketReadLimit)
+	return &webSocketConn{Conn: conn}
+}
+
+// Read implements net.Conn.
+func (c *webSocketConn) Read(b []byte) (int, error) {
+	for {
+		if c.reader == nil {
+			msgType, reader, err := c.NextReader()
+			if err != nil {
+				return 0, err
+			}
+			if msgType != websocket.BinaryMessage {
+				return 0, fmt.Errorf("unexpected WebSocket message type %v", msgType)
+			}
+			c.reader = reader
+		}
+		n, err := c.reader.Read(b)
+		if errors.Is(err, io.EOF) {
+			c.reader = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+// Write implements net.Conn.
+func (c *webSocketConn) Write(b []byte) (int, error) {
+	if err := c.WriteMessage(websocket.BinaryMessage, b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// SetDeadline implements net.Conn.
+func (c *webSocketConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+// webSocketListener is a net.Listener for MConn connections tunneled through
+// WebSocket, served over HTTPS if a TLS config is given.
+type webSocketListener struct {
+	listener net.Listener
+	server   *http.Server
+	connCh   chan net.Conn
+
+	closeOnce sync.Once
+	doneCh    chan struct{}
+}
+
+// listenWebSocket listens for WebSocket connections on the given address.
+func listenWebSocket(address string, tlsConfig *tls.Config) (*webSocketListener, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	l := &webSocketListener{
+		listener: listener,
+		connCh:   make(chan net.Conn),
+		doneCh:   make(chan struct{}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(webSocketPath, l.handle)
+	l.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: webSocketHeaderTimeout,
+	}
+	go func() { _ = l.server.Serve(listener) }()
+
+	return l, nil
+}
+
+// handle upgrades an HTTP request to a WebSocket connection, and passes it
+// on to Accept.
+func (l *webSocketListener) handle(w http.ResponseWriter, r *http.Request) {
+	wsConn, err := webSocketUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade has already responded with an HTTP error
+	}
+	conn := newWebSocketConn(wsConn)
+	select {
+	case l.connCh <- conn:
+	case <-l.doneCh:
+		conn.Close()
+	}
+}
+
+// Accept implements net.Listener.
+func (l *webSocketListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.connCh:
+		return conn, nil
+	case <-l.doneCh:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close implements net.Listener.
+func (l *webSocketListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.doneCh)
+		err = l.server.Close()
+	})
+	return err
+}
+
+// Addr implements net.Listener.
+func (l *webSocketListener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// multiListener accepts connections from several listeners.
+type multiListener struct {
+	listeners []net.Listener
+	acceptCh  chan acceptResult
+
+	closeOnce sync.Once
+	doneCh    chan struct{}
+}
+
+type acceptResult struct {
+	conn net.Conn
+	err  error
+}
+
+// newMultiListener accepts connections from the given listeners until it is
+// closed. Its address is that of the first listener.
+func newMultiListener(listeners ...net.Listener) *multiListener {
+	l := &multiListener{
+		listeners: listeners,
+		acceptCh:  make(chan acceptResult),
+		doneCh:    make(chan struct{}),
+	}
+	for _, listener := range listeners {
+		go l.acceptRoutine(listener)
+	}
+	return l
+}
+
+func (l *multiListener) acceptRoutine(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		select {
+		case l.acceptCh <- acceptResult{conn: conn, err: err}:
+		case <-l.doneCh:
+			if conn != nil {
+				conn.Close()
+			}
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Accept implements net.Listener.
+func (l *multiListener) Accept() (net.Conn, error) {
+	select {
+	case res := <-l.acceptCh:
+		return res.conn, res.err
+	case <-l.doneCh:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close implements net.Listener.
+func (l *multiListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.doneCh)
+		for _, listener := range l.listeners {
+			if lerr := listener.Close(); lerr != nil && err == nil {
+				err = lerr
+			}
+		}
+	})
+	return err
+}
+
+// Addr implements net.Listener.
+func (l *multiListener) Addr() net.Addr {
+	return l.listeners[0].Addr()
+}
+
+// webSocketAddress returns the host:port to dial for a WebSocket endpoint,
+// defaulting to the standard HTTP(S) ports.
+func webSocketAddress(host string, endpoint *Endpoint) string {
+	port := endpoint.Port
+	if port == 0 {
+		port = 80
+		if endpoint.Protocol == WebSocketSecureProtocol {
+			port = 443
+		}
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(port)))
+}
@@ -169,6 +169,11 @@ func (app *proxyClient) CheckTx(ctx context.Context, req *types.RequestCheckTx)
 	return app.client.CheckTx(ctx, req)
 }
 
+func (app *proxyClient) CheckTxs(ctx context.Context, reqs []*types.RequestCheckTx) ([]*types.ResponseCheckTx, error) {
+	defer addTimeSample(app.metrics.MethodTiming.With("method", "check_txs", "type", "sync"))()
+	return abciclient.CheckTxs(ctx, app.client, reqs)
+}
+
 func (app *proxyClient) Echo(ctx context.Context, msg string) (*types.ResponseEcho, error) {
 	defer addTimeSample(app.metrics.MethodTiming.With("method", "echo", "type", "sync"))()
 	return app.client.Echo(ctx, msg)
@@ -3,8 +3,12 @@ package core
 import (
 	"context"
 
+	"github.com/tendermint/tendermint/crypto"
+	cstypes "github.com/tendermint/tendermint/internal/consensus/types"
+	"github.com/tendermint/tendermint/libs/bits"
 	tmmath "github.com/tendermint/tendermint/libs/math"
 	"github.com/tendermint/tendermint/rpc/coretypes"
+	"github.com/tendermint/tendermint/types"
 )
 
 // Validators gets the validator set at the given block height.
@@ -51,6 +55,8 @@ func (env *Environment) Validators(ctx context.Context, req *coretypes.RequestVa
 func (env *Environment) DumpConsensusState(ctx context.Context) (*coretypes.ResultDumpConsensusState, error) {
 	// Get Peer consensus states.
 
+	rs := env.ConsensusState.GetRoundState()
+
 	var peerStates []coretypes.PeerStateInfo
 	peers := env.PeerManager.Peers()
 	peerStates = make([]coretypes.PeerStateInfo, 0, len(peers))
@@ -71,7 +77,8 @@ func (env *Environment) DumpConsensusState(ctx context.Context) (*coretypes.Resu
 				// Peer basic info.
 				NodeAddress: addr[0].String(),
 				// Peer consensus state.
-				PeerState: peerStateJSON,
+				PeerState:  peerStateJSON,
+				RoundState: peerRoundStateInfo(peerState.GetRoundState(), rs),
 			})
 		}
 	}
@@ -83,10 +90,48 @@ func (env *Environment) DumpConsensusState(ctx context.Context) (*coretypes.Resu
 	}
 	return &coretypes.ResultDumpConsensusState{
 		RoundState: roundState,
-		Peers:      peerStates,
+		Locks: coretypes.ConsensusLockInfo{
+			Height:          rs.Height,
+			Round:           rs.Round,
+			Step:            rs.Step.String(),
+			LockedRound:     rs.LockedRound,
+			LockedBlockHash: rs.LockedBlock.Hash(),
+			ValidRound:      rs.ValidRound,
+			ValidBlockHash:  rs.ValidBlock.Hash(),
+		},
+		Peers: peerStates,
 	}, nil
 }
 
+// peerRoundStateInfo summarizes a peer's round state. The validators whose
+// votes the peer has are resolved using our validator set, so they're only
+// set if the peer is at our height.
+func peerRoundStateInfo(prs *cstypes.PeerRoundState, rs *cstypes.RoundState) coretypes.PeerRoundStateInfo {
+	info := coretypes.PeerRoundStateInfo{
+		Height:           prs.Height,
+		Round:            prs.Round,
+		Step:             prs.Step.String(),
+		Proposal:         prs.Proposal,
+		ProposalPOLRound: prs.ProposalPOLRound,
+	}
+	if prs.Height == rs.Height && rs.Validators != nil {
+		info.Prevotes = validatorAddresses(prs.Prevotes, rs.Validators)
+		info.Precommits = validatorAddresses(prs.Precommits, rs.Validators)
+	}
+	return info
+}
+
+// validatorAddresses returns the addresses of the validators set in votes.
+func validatorAddresses(votes *bits.BitArray, vals *types.ValidatorSet) []crypto.Address {
+	addrs := []crypto.Address{}
+	for i := 0; i < votes.Size() && i < vals.Size(); i++ {
+		if votes.GetIndex(i) {
+			addrs = append(addrs, vals.Validators[i].Address)
+		}
+	}
+	return addrs
+}
+
 // ConsensusState returns a concise summary of the consensus state.
 // UNSTABLE
 // More: https://docs.tendermint.com/master/rpc/#/Info/consensus_state
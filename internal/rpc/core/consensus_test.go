@@ -0,0 +1,47 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cstypes "github.com/tendermint/tendermint/internal/consensus/types"
+	"github.com/tendermint/tendermint/libs/bits"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestPeerRoundStateInfo(t *testing.T) {
+	vals := types.NewValidatorSet([]*types.Validator{
+		types.NewValidator(ed25519.GenPrivKey().PubKey(), 10),
+		types.NewValidator(ed25519.GenPrivKey().PubKey(), 10),
+		types.NewValidator(ed25519.GenPrivKey().PubKey(), 10),
+	})
+	rs := &cstypes.RoundState{Height: 10, Validators: vals}
+
+	prevotes := bits.NewBitArray(3)
+	prevotes.SetIndex(0, true)
+	prevotes.SetIndex(2, true)
+	prs := &cstypes.PeerRoundState{
+		Height:           10,
+		Round:            1,
+		Step:             cstypes.RoundStepPrevote,
+		ProposalPOLRound: -1,
+		Prevotes:         prevotes,
+	}
+
+	info := peerRoundStateInfo(prs, rs)
+	assert.EqualValues(t, 10, info.Height)
+	assert.EqualValues(t, 1, info.Round)
+	assert.Equal(t, "RoundStepPrevote", info.Step)
+	assert.EqualValues(t, -1, info.ProposalPOLRound)
+	assert.Equal(t, []crypto.Address{vals.Validators[0].Address, vals.Validators[2].Address}, info.Prevotes)
+	assert.Empty(t, info.Precommits)
+
+	// votes aren't resolved for peers at another height
+	prs.Height = 9
+	info = peerRoundStateInfo(prs, rs)
+	assert.Nil(t, info.Prevotes)
+	assert.Nil(t, info.Precommits)
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"errors"
 
 	"github.com/tendermint/tendermint/rpc/coretypes"
 )
@@ -11,3 +12,15 @@ func (env *Environment) UnsafeFlushMempool(ctx context.Context) (*coretypes.Resu
 	env.Mempool.Flush()
 	return &coretypes.ResultUnsafeFlushMempool{}, nil
 }
+
+// UnsafeSwitchToConsensus makes block sync hand over to consensus, even if it
+// isn't caught up with the peers.
+func (env *Environment) UnsafeSwitchToConsensus(ctx context.Context) (*coretypes.ResultUnsafeSwitchToConsensus, error) {
+	if env.BlockSyncReactor == nil {
+		return nil, errors.New("block sync is not available")
+	}
+	if err := env.BlockSyncReactor.ForceSwitchToConsensus(); err != nil {
+		return nil, err
+	}
+	return &coretypes.ResultUnsafeSwitchToConsensus{}, nil
+}
@@ -16,11 +16,13 @@ import (
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/internal/blocksync"
 	"github.com/tendermint/tendermint/internal/consensus"
+	cstypes "github.com/tendermint/tendermint/internal/consensus/types"
 	"github.com/tendermint/tendermint/internal/eventbus"
 	"github.com/tendermint/tendermint/internal/eventlog"
 	"github.com/tendermint/tendermint/internal/libs/strings"
 	"github.com/tendermint/tendermint/internal/mempool"
 	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/internal/p2p/pex"
 	tmpubsub "github.com/tendermint/tendermint/internal/pubsub"
 	"github.com/tendermint/tendermint/internal/pubsub/query"
 	sm "github.com/tendermint/tendermint/internal/state"
@@ -53,13 +55,21 @@ type consensusState interface {
 	GetState() sm.State
 	GetValidators() (int64, []*types.Validator)
 	GetLastHeight() int64
+	GetRoundState() *cstypes.RoundState
 	GetRoundStateJSON() ([]byte, error)
 	GetRoundStateSimpleJSON() ([]byte, error)
 }
 
+type crawler interface {
+	CrawlStatus() map[types.NodeID]pex.CrawlStatus
+}
+
 type peerManager interface {
 	Peers() []types.NodeID
 	Addresses(types.NodeID) []p2p.NodeAddress
+	KnownAddresses() []p2p.KnownAddress
+	Add(p2p.NodeAddress) (bool, error)
+	RemoveAddress(p2p.NodeAddress) (bool, error)
 }
 
 //----------------------------------------------
@@ -83,6 +93,7 @@ type Environment struct {
 
 	// interfaces for new p2p interfaces
 	PeerManager peerManager
+	Crawler     crawler // set on seed nodes
 
 	// objects
 	PubKey            crypto.PubKey
@@ -97,6 +108,10 @@ type Environment struct {
 
 	Config config.RPCConfig
 
+	// MaxTxBytes, if positive, is the maximum size of a transaction accepted
+	// by the mempool. Larger transactions are rejected before reaching it.
+	MaxTxBytes int
+
 	// cache of chunked genesis data.
 	genChunks []string
 }
@@ -224,15 +239,11 @@ func (env *Environment) StartService(ctx context.Context, conf *config.Config) (
 		fmt.Sprintf("Listener(@%v)", conf.P2P.ExternalAddress),
 	}
 
-	listenAddrs := strings.SplitAndTrimEmpty(conf.RPC.ListenAddress, ",", " ")
 	routes := NewRoutesMap(env, &RouteOptions{
 		Unsafe: conf.RPC.Unsafe,
 	})
 
-	cfg := rpcserver.DefaultConfig()
-	cfg.MaxBodyBytes = conf.RPC.MaxBodyBytes
-	cfg.MaxHeaderBytes = conf.RPC.MaxHeaderBytes
-	cfg.MaxOpenConnections = conf.RPC.MaxOpenConnections
+	cfg := rpcserverConfig(conf)
 	// If necessary adjust global WriteTimeout to ensure it's greater than
 	// TimeoutBroadcastTxCommit.
 	// See https://github.com/tendermint/tendermint/issues/3435
@@ -276,16 +287,50 @@ func (env *Environment) StartService(ctx context.Context, conf *config.Config) (
 		env.Logger.Info("Event log subscription enabled")
 	}
 
+	return env.listen(ctx, conf, cfg, routes, true)
+}
+
+// StartSeedService constructs and starts listeners for the RPC service of a
+// seed node, which serves the routes of NewSeedRoutesMap without websockets.
+// The listeners run until the context is canceled.
+func (env *Environment) StartSeedService(ctx context.Context, conf *config.Config) ([]net.Listener, error) {
+	env.Listeners = []string{
+		fmt.Sprintf("Listener(@%v)", conf.P2P.ExternalAddress),
+	}
+	return env.listen(ctx, conf, rpcserverConfig(conf), NewSeedRoutesMap(env), false)
+}
+
+// rpcserverConfig returns the RPC server configuration given by conf.
+func rpcserverConfig(conf *config.Config) *rpcserver.Config {
+	cfg := rpcserver.DefaultConfig()
+	cfg.MaxBodyBytes = conf.RPC.MaxBodyBytes
+	cfg.MaxHeaderBytes = conf.RPC.MaxHeaderBytes
+	cfg.MaxOpenConnections = conf.RPC.MaxOpenConnections
+	return cfg
+}
+
+// listen starts serving routes on the configured listen addresses, along with
+// the websocket endpoint if websocket is set.
+func (env *Environment) listen(
+	ctx context.Context,
+	conf *config.Config,
+	cfg *rpcserver.Config,
+	routes RoutesMap,
+	websocket bool,
+) ([]net.Listener, error) {
 	// We may expose the RPC over both TCP and a Unix-domain socket.
+	listenAddrs := strings.SplitAndTrimEmpty(conf.RPC.ListenAddress, ",", " ")
 	listeners := make([]net.Listener, len(listenAddrs))
 	for i, listenAddr := range listenAddrs {
 		mux := http.NewServeMux()
 		rpcLogger := env.Logger.With("module", "rpc-server")
 		rpcserver.RegisterRPCFuncs(mux, routes, rpcLogger)
 
-		if conf.RPC.ExperimentalDisableWebsocket {
+		switch {
+		case !websocket:
+		case conf.RPC.ExperimentalDisableWebsocket:
 			rpcLogger.Info("Disabling websocket endpoints (experimental-disable-websocket=true)")
-		} else {
+		default:
 			rpcLogger.Info("WARNING: Websocket RPC access is deprecated and will be removed " +
 				"in Tendermint v0.37. See https://tinyurl.com/adr075 for more information.")
 			wmLogger := rpcLogger.With("protocol", "websocket")
@@ -350,5 +395,4 @@ func (env *Environment) StartService(ctx context.Context, conf *config.Config) (
 	}
 
 	return listeners, nil
-
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/tendermint/tendermint/internal/state/indexer"
 	tmmath "github.com/tendermint/tendermint/libs/math"
 	"github.com/tendermint/tendermint/rpc/coretypes"
+	"github.com/tendermint/tendermint/types"
 )
 
 //-----------------------------------------------------------------------------
@@ -23,6 +24,9 @@ import (
 // https://docs.tendermint.com/master/rpc/#/Tx/broadcast_tx_async
 // Deprecated and should be removed in 0.37
 func (env *Environment) BroadcastTxAsync(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTx, error) {
+	if err := env.validateTxSize(req.Tx); err != nil {
+		return nil, err
+	}
 	go func() { _ = env.Mempool.CheckTx(ctx, req.Tx, nil, mempool.TxInfo{}) }()
 
 	return &coretypes.ResultBroadcastTx{Hash: req.Tx.Hash()}, nil
@@ -37,6 +41,9 @@ func (env *Environment) BroadcastTxSync(ctx context.Context, req *coretypes.Requ
 // DeliverTx result.
 // More: https://docs.tendermint.com/master/rpc/#/Tx/broadcast_tx_sync
 func (env *Environment) BroadcastTx(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTx, error) {
+	if err := env.validateTxSize(req.Tx); err != nil {
+		return nil, err
+	}
 	resCh := make(chan *abci.ResponseCheckTx, 1)
 	err := env.Mempool.CheckTx(
 		ctx,
@@ -69,6 +76,9 @@ func (env *Environment) BroadcastTx(ctx context.Context, req *coretypes.RequestB
 // BroadcastTxCommit returns with the responses from CheckTx and DeliverTx.
 // More: https://docs.tendermint.com/master/rpc/#/Tx/broadcast_tx_commit
 func (env *Environment) BroadcastTxCommit(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTxCommit, error) {
+	if err := env.validateTxSize(req.Tx); err != nil {
+		return nil, err
+	}
 	resCh := make(chan *abci.ResponseCheckTx, 1)
 	err := env.Mempool.CheckTx(
 		ctx,
@@ -144,10 +154,16 @@ func (env *Environment) BroadcastTxCommit(ctx context.Context, req *coretypes.Re
 	}
 }
 
-// UnconfirmedTxs gets unconfirmed transactions from the mempool in order of priority
+// UnconfirmedTxs gets unconfirmed transactions from the mempool in order of priority,
+// optionally only those from the sender reported by the application in CheckTx.
 // More: https://docs.tendermint.com/master/rpc/#/Info/unconfirmed_txs
 func (env *Environment) UnconfirmedTxs(ctx context.Context, req *coretypes.RequestUnconfirmedTxs) (*coretypes.ResultUnconfirmedTxs, error) {
+	var senderTxs types.Txs
 	totalCount := env.Mempool.Size()
+	if req.Sender != "" {
+		senderTxs = env.Mempool.ReapTxsBySender(req.Sender)
+		totalCount = len(senderTxs)
+	}
 	perPage := env.validatePerPage(req.PerPage.IntPtr())
 	page, err := validatePage(req.Page.IntPtr(), perPage, totalCount)
 	if err != nil {
@@ -155,9 +171,16 @@ func (env *Environment) UnconfirmedTxs(ctx context.Context, req *coretypes.Reque
 	}
 
 	skipCount := validateSkipCount(page, perPage)
+	maxCount := skipCount + tmmath.MinInt(perPage, totalCount-skipCount)
 
-	txs := env.Mempool.ReapMaxTxs(skipCount + tmmath.MinInt(perPage, totalCount-skipCount))
-	result := txs[skipCount:]
+	var txs types.Txs
+	if req.Sender != "" {
+		txs = senderTxs[:maxCount]
+	} else {
+		txs = env.Mempool.ReapMaxTxs(maxCount)
+	}
+	// The mempool may have shrunk since its size was taken.
+	result := txs[tmmath.MinInt(skipCount, len(txs)):]
 
 	return &coretypes.ResultUnconfirmedTxs{
 		Count:      len(result),
@@ -180,6 +203,9 @@ func (env *Environment) NumUnconfirmedTxs(ctx context.Context) (*coretypes.Resul
 // be added to the mempool either.
 // More: https://docs.tendermint.com/master/rpc/#/Tx/check_tx
 func (env *Environment) CheckTx(ctx context.Context, req *coretypes.RequestCheckTx) (*coretypes.ResultCheckTx, error) {
+	if err := env.validateTxSize(req.Tx); err != nil {
+		return nil, err
+	}
 	res, err := env.ProxyApp.CheckTx(ctx, &abci.RequestCheckTx{Tx: req.Tx})
 	if err != nil {
 		return nil, err
@@ -187,6 +213,47 @@ func (env *Environment) CheckTx(ctx context.Context, req *coretypes.RequestCheck
 	return &coretypes.ResultCheckTx{ResponseCheckTx: *res}, nil
 }
 
+// CheckTxResult reports why the mempool recently rejected the transaction with
+// the given hash, or dropped it after it failed a recheck, including the
+// application's CheckTx code.
+// More: https://docs.tendermint.com/master/rpc/#/Tx/check_tx_result
+func (env *Environment) CheckTxResult(ctx context.Context, req *coretypes.RequestCheckTxResult) (*coretypes.ResultCheckTxResult, error) {
+	var key types.TxKey
+	if len(req.Hash) != len(key) {
+		return nil, fmt.Errorf("invalid transaction hash %X: must be %d bytes", req.Hash, len(key))
+	}
+	copy(key[:], req.Hash)
+
+	rejected, ok := env.Mempool.RejectedTx(key)
+	if !ok {
+		return nil, fmt.Errorf("transaction %X was not rejected recently", req.Hash)
+	}
+	return &coretypes.ResultCheckTxResult{
+		Hash:      req.Hash,
+		Code:      rejected.Code,
+		Codespace: rejected.Codespace,
+		Data:      rejected.Data,
+		Reason:    rejected.Reason,
+		Height:    rejected.Height,
+		Time:      rejected.Time,
+	}, nil
+}
+
+// validateTxSize reports an error if tx exceeds the maximum transaction size of
+// the mempool. It lets the RPC reject such transactions up front, including
+// for broadcast_tx_async, which doesn't report mempool errors otherwise.
+func (env *Environment) validateTxSize(tx types.Tx) error {
+	if env.MaxTxBytes > 0 && len(tx) > env.MaxTxBytes {
+		return types.ErrTxTooLarge{Max: env.MaxTxBytes, Actual: len(tx)}
+	}
+	return nil
+}
+
+// RemoveTx removes the transaction with the given key from the mempool, e.g.
+// to purge a known-bad or replaced transaction. The transaction stays in the
+// cache, so it isn't accepted again until it is evicted from the cache. It is
+// only available with unsafe RPC commands enabled.
+// More: https://docs.tendermint.com/master/rpc/#/Unsafe/remove_tx
 func (env *Environment) RemoveTx(ctx context.Context, req *coretypes.RequestRemoveTx) error {
 	return env.Mempool.RemoveTxByKey(req.TxKey)
 }
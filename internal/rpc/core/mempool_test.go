@@ -0,0 +1,67 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/internal/mempool"
+	"github.com/tendermint/tendermint/internal/mempool/mocks"
+	"github.com/tendermint/tendermint/rpc/coretypes"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestBroadcastTxTooLarge(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The mempool mock fails the test if it's called.
+	env := &Environment{Mempool: &mocks.Mempool{}, MaxTxBytes: 4}
+	req := &coretypes.RequestBroadcastTx{Tx: types.Tx("12345")}
+
+	var errTooLarge types.ErrTxTooLarge
+	_, err := env.BroadcastTxAsync(ctx, req)
+	require.True(t, errors.As(err, &errTooLarge))
+	require.Equal(t, types.ErrTxTooLarge{Max: 4, Actual: 5}, errTooLarge)
+
+	_, err = env.BroadcastTx(ctx, req)
+	require.True(t, errors.As(err, &errTooLarge))
+	_, err = env.BroadcastTxCommit(ctx, req)
+	require.True(t, errors.As(err, &errTooLarge))
+	_, err = env.CheckTx(ctx, &coretypes.RequestCheckTx{Tx: req.Tx})
+	require.True(t, errors.As(err, &errTooLarge))
+}
+
+func TestCheckTxResult(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tx := types.Tx("tx")
+	key := tx.Key()
+	rejected := mempool.RejectedTx{
+		Code:      5,
+		Codespace: "bank",
+		Data:      []byte("data"),
+		Reason:    mempool.RejectedInvalid,
+		Height:    10,
+		Time:      time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	mp := &mocks.Mempool{}
+	mp.On("RejectedTx", key).Return(rejected, true)
+	env := &Environment{Mempool: mp}
+
+	res, err := env.CheckTxResult(ctx, &coretypes.RequestCheckTxResult{Hash: key[:]})
+	require.NoError(t, err)
+	require.Equal(t, &coretypes.ResultCheckTxResult{
+		Hash:      key[:],
+		Code:      rejected.Code,
+		Codespace: rejected.Codespace,
+		Data:      rejected.Data,
+		Reason:    rejected.Reason,
+		Height:    rejected.Height,
+		Time:      rejected.Time,
+	}, res)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 
+	"github.com/tendermint/tendermint/internal/p2p"
 	"github.com/tendermint/tendermint/rpc/coretypes"
 )
 
@@ -34,6 +36,85 @@ func (env *Environment) NetInfo(ctx context.Context) (*coretypes.ResultNetInfo,
 	}, nil
 }
 
+// CrawlStatus returns the outcome of crawling each peer, as recorded by a seed
+// node, for network health monitoring.
+func (env *Environment) CrawlStatus(ctx context.Context) (*coretypes.ResultCrawlStatus, error) {
+	if env.Crawler == nil {
+		return nil, errors.New("crawl status is only available on seed nodes")
+	}
+	status := env.Crawler.CrawlStatus()
+
+	peers := make([]coretypes.CrawlStatusEntry, 0, len(status))
+	for id, s := range status {
+		peers = append(peers, coretypes.CrawlStatusEntry{
+			ID:                  id,
+			LastCrawled:         s.LastCrawled,
+			LastFailed:          s.LastFailed,
+			Crawls:              int64(s.Crawls),
+			ConsecutiveFailures: int64(s.ConsecutiveFailures),
+			Addresses:           int64(s.Addresses),
+			Moniker:             s.Moniker,
+			Version:             s.Version,
+		})
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].ID < peers[j].ID })
+
+	return &coretypes.ResultCrawlStatus{Peers: peers}, nil
+}
+
+// UnsafeAddressBook returns all addresses in the peer store, better peers
+// first, so that operators can inspect what the node has learned.
+func (env *Environment) UnsafeAddressBook(ctx context.Context) (*coretypes.ResultAddressBook, error) {
+	known := env.PeerManager.KnownAddresses()
+
+	addresses := make([]coretypes.AddressBookEntry, 0, len(known))
+	for _, addr := range known {
+		addresses = append(addresses, coretypes.AddressBookEntry{
+			ID:              addr.Address.NodeID,
+			URL:             addr.Address.String(),
+			Tried:           !addr.LastDialSuccess.IsZero(),
+			Score:           int64(addr.Score),
+			Persistent:      addr.Persistent,
+			Connected:       addr.Connected,
+			BannedUntil:     addr.BannedUntil,
+			LastConnected:   addr.LastConnected,
+			LastDialSuccess: addr.LastDialSuccess,
+			LastDialFailure: addr.LastDialFailure,
+			DialFailures:    int64(addr.DialFailures),
+		})
+	}
+
+	return &coretypes.ResultAddressBook{Addresses: addresses}, nil
+}
+
+// UnsafeAddressBookAdd adds an address to the peer store, which the node will
+// then consider when dialing peers.
+func (env *Environment) UnsafeAddressBookAdd(ctx context.Context, req *coretypes.RequestAddressBookAdd) (*coretypes.ResultAddressBookAdd, error) {
+	addr, err := p2p.ParseNodeAddress(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", req.URL, err)
+	}
+	added, err := env.PeerManager.Add(addr)
+	if err != nil {
+		return nil, err
+	}
+	return &coretypes.ResultAddressBookAdd{Added: added}, nil
+}
+
+// UnsafeAddressBookRemove removes an address from the peer store. It does not
+// disconnect the peer if we're currently connected to it.
+func (env *Environment) UnsafeAddressBookRemove(ctx context.Context, req *coretypes.RequestAddressBookRemove) (*coretypes.ResultAddressBookRemove, error) {
+	addr, err := p2p.ParseNodeAddress(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", req.URL, err)
+	}
+	removed, err := env.PeerManager.RemoveAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	return &coretypes.ResultAddressBookRemove{Removed: removed}, nil
+}
+
 // Genesis returns genesis file.
 // More: https://docs.tendermint.com/master/rpc/#/Info/genesis
 func (env *Environment) Genesis(ctx context.Context) (*coretypes.ResultGenesis, error) {
@@ -47,7 +47,7 @@ func NewRoutesMap(svc RPCService, opts *RouteOptions) RoutesMap {
 		"block_results":        rpc.NewRPCFunc(svc.BlockResults),
 		"commit":               rpc.NewRPCFunc(svc.Commit),
 		"check_tx":             rpc.NewRPCFunc(svc.CheckTx),
-		"remove_tx":            rpc.NewRPCFunc(svc.RemoveTx),
+		"check_tx_result":      rpc.NewRPCFunc(svc.CheckTxResult),
 		"tx":                   rpc.NewRPCFunc(svc.Tx),
 		"tx_search":            rpc.NewRPCFunc(svc.TxSearch),
 		"block_search":         rpc.NewRPCFunc(svc.BlockSearch),
@@ -73,12 +73,30 @@ func NewRoutesMap(svc RPCService, opts *RouteOptions) RoutesMap {
 		// evidence API
 		"broadcast_evidence": rpc.NewRPCFunc(svc.BroadcastEvidence),
 	}
+	if opts.Unsafe {
+		// Removing transactions from the mempool is reserved for operators.
+		out["remove_tx"] = rpc.NewRPCFunc(svc.RemoveTx)
+	}
 	if u, ok := svc.(RPCUnsafe); ok && opts.Unsafe {
 		out["unsafe_flush_mempool"] = rpc.NewRPCFunc(u.UnsafeFlushMempool)
+		out["unsafe_address_book"] = rpc.NewRPCFunc(u.UnsafeAddressBook)
+		out["unsafe_address_book_add"] = rpc.NewRPCFunc(u.UnsafeAddressBookAdd)
+		out["unsafe_address_book_remove"] = rpc.NewRPCFunc(u.UnsafeAddressBookRemove)
+		out["unsafe_switch_to_consensus"] = rpc.NewRPCFunc(u.UnsafeSwitchToConsensus)
 	}
 	return out
 }
 
+// NewSeedRoutesMap constructs the RPC routing map of a seed node, which only
+// runs the p2p layer.
+func NewSeedRoutesMap(env *Environment) RoutesMap {
+	return RoutesMap{
+		"health":       rpc.NewRPCFunc(env.Health),
+		"net_info":     rpc.NewRPCFunc(env.NetInfo),
+		"crawl_status": rpc.NewRPCFunc(env.CrawlStatus),
+	}
+}
+
 // RPCService defines the set of methods exported by the RPC service
 // implementation, for use in constructing a routing table.
 type RPCService interface {
@@ -95,6 +113,7 @@ type RPCService interface {
 	BroadcastTxCommit(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTxCommit, error)
 	BroadcastTxSync(ctx context.Context, req *coretypes.RequestBroadcastTx) (*coretypes.ResultBroadcastTx, error)
 	CheckTx(ctx context.Context, req *coretypes.RequestCheckTx) (*coretypes.ResultCheckTx, error)
+	CheckTxResult(ctx context.Context, req *coretypes.RequestCheckTxResult) (*coretypes.ResultCheckTxResult, error)
 	Commit(ctx context.Context, req *coretypes.RequestBlockInfo) (*coretypes.ResultCommit, error)
 	ConsensusParams(ctx context.Context, req *coretypes.RequestConsensusParams) (*coretypes.ResultConsensusParams, error)
 	DumpConsensusState(ctx context.Context) (*coretypes.ResultDumpConsensusState, error)
@@ -122,4 +141,8 @@ type RPCService interface {
 // exported by the RPC service.
 type RPCUnsafe interface {
 	UnsafeFlushMempool(ctx context.Context) (*coretypes.ResultUnsafeFlushMempool, error)
+	UnsafeAddressBook(ctx context.Context) (*coretypes.ResultAddressBook, error)
+	UnsafeAddressBookAdd(ctx context.Context, req *coretypes.RequestAddressBookAdd) (*coretypes.ResultAddressBookAdd, error)
+	UnsafeAddressBookRemove(ctx context.Context, req *coretypes.RequestAddressBookRemove) (*coretypes.ResultAddressBookRemove, error)
+	UnsafeSwitchToConsensus(ctx context.Context) (*coretypes.ResultUnsafeSwitchToConsensus, error)
 }
@@ -94,6 +94,8 @@ func (env *Environment) Status(ctx context.Context) (*coretypes.ResultStatus, er
 		result.SyncInfo.MaxPeerBlockHeight = env.BlockSyncReactor.GetMaxPeerBlockHeight()
 		result.SyncInfo.TotalSyncedTime = env.BlockSyncReactor.GetTotalSyncedTime()
 		result.SyncInfo.RemainingTime = env.BlockSyncReactor.GetRemainingSyncTime()
+		result.SyncInfo.SyncRate = env.BlockSyncReactor.GetSyncRate()
+		result.SyncInfo.NumSyncPeers = int64(env.BlockSyncReactor.GetNumSyncPeers())
 	}
 
 	if env.StateSyncMetricer != nil {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 
+	abci "github.com/tendermint/tendermint/abci/types"
 	tmquery "github.com/tendermint/tendermint/internal/pubsub/query"
 	"github.com/tendermint/tendermint/internal/state/indexer"
 	tmmath "github.com/tendermint/tendermint/libs/math"
@@ -52,6 +53,10 @@ func (env *Environment) Tx(ctx context.Context, req *coretypes.RequestTx) (*core
 
 // TxSearch allows you to query for multiple transactions results. It returns a
 // list of transactions (maximum ?per_page entries) and the total count.
+//
+// Pages are selected either by ?page, or by ?cursor, which is the next_cursor
+// of the previous page. With a cursor, only the transactions after it are
+// loaded from the index and total_count counts them, so deep pages stay cheap.
 // More: https://docs.tendermint.com/master/rpc/#/Info/tx_search
 func (env *Environment) TxSearch(ctx context.Context, req *coretypes.RequestTxSearch) (*coretypes.ResultTxSearch, error) {
 	if !indexer.KVSinkEnabled(env.EventSinks) {
@@ -60,7 +65,35 @@ func (env *Environment) TxSearch(ctx context.Context, req *coretypes.RequestTxSe
 		return nil, errors.New("maximum query length exceeded")
 	}
 
-	q, err := tmquery.New(req.Query)
+	var desc bool
+	switch req.OrderBy {
+	case "desc", "":
+		desc = true
+	case "asc":
+	default:
+		return nil, fmt.Errorf("expected order_by to be either `asc` or `desc` or empty: %w", coretypes.ErrInvalidRequest)
+	}
+
+	query := req.Query
+	var cursor txCursor
+	if req.Cursor != "" {
+		if req.Page != nil {
+			return nil, fmt.Errorf("page and cursor can't be used together: %w", coretypes.ErrInvalidRequest)
+		}
+		var err error
+		cursor, err = parseTxCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		// let the indexer skip the heights before the cursor
+		if desc {
+			query = fmt.Sprintf("%s AND tx.height <= %d", query, cursor.height)
+		} else {
+			query = fmt.Sprintf("%s AND tx.height >= %d", query, cursor.height)
+		}
+	}
+
+	q, err := tmquery.New(query)
 	if err != nil {
 		return nil, err
 	}
@@ -73,35 +106,29 @@ func (env *Environment) TxSearch(ctx context.Context, req *coretypes.RequestTxSe
 			}
 
 			// sort results (must be done before pagination)
-			switch req.OrderBy {
-			case "desc", "":
-				sort.Slice(results, func(i, j int) bool {
-					if results[i].Height == results[j].Height {
-						return results[i].Index > results[j].Index
-					}
-					return results[i].Height > results[j].Height
-				})
-			case "asc":
-				sort.Slice(results, func(i, j int) bool {
-					if results[i].Height == results[j].Height {
-						return results[i].Index < results[j].Index
-					}
-					return results[i].Height < results[j].Height
+			sort.Slice(results, func(i, j int) bool {
+				return txBefore(results[i], results[j], desc)
+			})
+
+			if req.Cursor != "" {
+				start := sort.Search(len(results), func(i int) bool {
+					return cursor.before(results[i], desc)
 				})
-			default:
-				return nil, fmt.Errorf("expected order_by to be either `asc` or `desc` or empty: %w", coretypes.ErrInvalidRequest)
+				results = results[start:]
 			}
 
-			// paginate results
+			// paginate results, capping the page size even in unsafe mode
 			totalCount := len(results)
-			perPage := env.validatePerPage(req.PerPage.IntPtr())
+			perPage := tmmath.MinInt(env.validatePerPage(req.PerPage.IntPtr()), maxPerPage)
 
-			page, err := validatePage(req.Page.IntPtr(), perPage, totalCount)
-			if err != nil {
-				return nil, err
+			skipCount := 0
+			if req.Cursor == "" {
+				page, err := validatePage(req.Page.IntPtr(), perPage, totalCount)
+				if err != nil {
+					return nil, err
+				}
+				skipCount = validateSkipCount(page, perPage)
 			}
-
-			skipCount := validateSkipCount(page, perPage)
 			pageSize := tmmath.MinInt(perPage, totalCount-skipCount)
 
 			apiResults := make([]*coretypes.ResultTx, 0, pageSize)
@@ -124,9 +151,55 @@ func (env *Environment) TxSearch(ctx context.Context, req *coretypes.RequestTxSe
 				})
 			}
 
-			return &coretypes.ResultTxSearch{Txs: apiResults, TotalCount: totalCount}, nil
+			var nextCursor string
+			if pageSize > 0 && skipCount+pageSize < totalCount {
+				last := results[skipCount+pageSize-1]
+				nextCursor = txCursor{height: last.Height, index: last.Index}.String()
+			}
+
+			return &coretypes.ResultTxSearch{Txs: apiResults, TotalCount: totalCount, NextCursor: nextCursor}, nil
 		}
 	}
 
 	return nil, fmt.Errorf("transaction searching is disabled on this node due to the KV event sink being disabled")
 }
+
+// txCursor is a tx_search cursor. It identifies the last transaction of a
+// page, which the next page starts after.
+type txCursor struct {
+	height int64
+	index  uint32
+}
+
+func parseTxCursor(s string) (txCursor, error) {
+	var c txCursor
+	if _, err := fmt.Sscanf(s, "%d:%d", &c.height, &c.index); err != nil || c.height < 1 || c.String() != s {
+		return txCursor{}, fmt.Errorf("invalid cursor %q: %w", s, coretypes.ErrInvalidRequest)
+	}
+	return c, nil
+}
+
+func (c txCursor) String() string {
+	return fmt.Sprintf("%d:%d", c.height, c.index)
+}
+
+// before reports whether the cursor comes before the transaction r in the
+// given order.
+func (c txCursor) before(r *abci.TxResult, desc bool) bool {
+	return txBefore(&abci.TxResult{Height: c.height, Index: c.index}, r, desc)
+}
+
+// txBefore reports whether the transaction a comes before b, ordered by height
+// and index, in descending order if desc is set.
+func txBefore(a, b *abci.TxResult, desc bool) bool {
+	if a.Height == b.Height {
+		if desc {
+			return a.Index > b.Index
+		}
+		return a.Index < b.Index
+	}
+	if desc {
+		return a.Height > b.Height
+	}
+	return a.Height < b.Height
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/internal/state/indexer"
+	"github.com/tendermint/tendermint/internal/state/indexer/sink/kv"
+	"github.com/tendermint/tendermint/rpc/coretypes"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestTxSearchCursor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sink := kv.NewEventSink(dbm.NewMemDB())
+	var txs []*abci.TxResult
+	for _, pos := range [][2]int64{{1, 0}, {1, 1}, {2, 0}, {3, 0}, {3, 1}} {
+		txs = append(txs, &abci.TxResult{
+			Height: pos[0],
+			Index:  uint32(pos[1]),
+			Tx:     types.Tx{byte(pos[0]), byte(pos[1])},
+			Result: abci.ExecTxResult{Events: []abci.Event{{
+				Type:       "app",
+				Attributes: []abci.EventAttribute{{Key: "key", Value: "value", Index: true}},
+			}}},
+		})
+	}
+	require.NoError(t, sink.IndexTxEvents(txs))
+	env := &Environment{EventSinks: []indexer.EventSink{sink}}
+	perPage, page := 2, 1
+
+	for _, orderBy := range []string{"asc", "desc"} {
+		var (
+			positions []int64
+			cursor  string
+		)
+		for {
+			res, err := env.TxSearch(ctx, &coretypes.RequestTxSearch{
+				Query:   "app.key='value'",
+				PerPage: coretypes.Int64Ptr(&perPage),
+				OrderBy: orderBy,
+				Cursor:  cursor,
+			})
+			require.NoError(t, err)
+			for _, tx := range res.Txs {
+				positions = append(positions, tx.Height*10+int64(tx.Index))
+			}
+			if res.NextCursor == "" {
+				break
+			}
+			cursor = res.NextCursor
+		}
+		if orderBy == "asc" {
+			assert.Equal(t, []int64{10, 11, 20, 30, 31}, positions)
+		} else {
+			assert.Equal(t, []int64{31, 30, 20, 11, 10}, positions)
+		}
+	}
+
+	_, err := env.TxSearch(ctx, &coretypes.RequestTxSearch{Query: "app.key='value'", Cursor: "2"})
+	assert.ErrorIs(t, err, coretypes.ErrInvalidRequest)
+
+	_, err = env.TxSearch(ctx, &coretypes.RequestTxSearch{
+		Query:  "app.key='value'",
+		Page:   coretypes.Int64Ptr(&page),
+		Cursor: "2:0",
+	})
+	assert.ErrorIs(t, err, coretypes.ErrInvalidRequest)
+}
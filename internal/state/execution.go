@@ -47,6 +47,35 @@ type BlockExecutor struct {
 
 	// cache the verification results over a single height
 	cache map[string]struct{}
+
+	// minimum number of recent blocks kept when pruning
+	minRetainBlocks int64
+
+	// compact the stores in the background once compactionInterval blocks
+	// were pruned since the last compaction
+	compactionInterval int64
+	compact            func() error
+	prunedSinceCompact int64
+	compacting         chan struct{}
+}
+
+// BlockExecutorOption sets an optional parameter on the BlockExecutor.
+type BlockExecutorOption func(*BlockExecutor)
+
+// BlockExecutorWithMinRetainBlocks sets the minimum number of recent blocks
+// kept when the application requests pruning.
+func BlockExecutorWithMinRetainBlocks(n int64) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) { blockExec.minRetainBlocks = n }
+}
+
+// BlockExecutorWithCompaction runs compact in the background once interval
+// blocks were pruned since the last compaction, so that the disk space of the
+// pruned blocks and states is reclaimed. 0 disables compaction.
+func BlockExecutorWithCompaction(interval int64, compact func() error) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.compactionInterval = interval
+		blockExec.compact = compact
+	}
 }
 
 // NewBlockExecutor returns a new BlockExecutor with the passed-in EventBus.
@@ -59,8 +88,9 @@ func NewBlockExecutor(
 	blockStore BlockStore,
 	eventBus *eventbus.EventBus,
 	metrics *Metrics,
+	options ...BlockExecutorOption,
 ) *BlockExecutor {
-	return &BlockExecutor{
+	blockExec := &BlockExecutor{
 		eventBus:   eventBus,
 		store:      stateStore,
 		appClient:  appClient,
@@ -70,7 +100,12 @@ func NewBlockExecutor(
 		metrics:    metrics,
 		cache:      make(map[string]struct{}),
 		blockStore: blockStore,
+		compacting: make(chan struct{}, 1),
+	}
+	for _, option := range options {
+		option(blockExec)
 	}
+	return blockExec
 }
 
 func (blockExec *BlockExecutor) Store() Store {
@@ -90,6 +125,30 @@ func (blockExec *BlockExecutor) CreateProposalBlock(
 	lastExtCommit *types.ExtendedCommit,
 	proposerAddr []byte,
 ) (*types.Block, error) {
+	return blockExec.createProposalBlock(ctx, height, state, lastExtCommit, proposerAddr, true)
+}
+
+// CreateProposalBlockWithoutMempool is like CreateProposalBlock, but it
+// doesn't reap any txs from the mempool. The application is still asked to
+// prepare the proposal, and may add txs of its own.
+func (blockExec *BlockExecutor) CreateProposalBlockWithoutMempool(
+	ctx context.Context,
+	height int64,
+	state State,
+	lastExtCommit *types.ExtendedCommit,
+	proposerAddr []byte,
+) (*types.Block, error) {
+	return blockExec.createProposalBlock(ctx, height, state, lastExtCommit, proposerAddr, false)
+}
+
+func (blockExec *BlockExecutor) createProposalBlock(
+	ctx context.Context,
+	height int64,
+	state State,
+	lastExtCommit *types.ExtendedCommit,
+	proposerAddr []byte,
+	reapMempool bool,
+) (*types.Block, error) {
 
 	maxBytes := state.ConsensusParams.Block.MaxBytes
 	maxGas := state.ConsensusParams.Block.MaxGas
@@ -99,7 +158,10 @@ func (blockExec *BlockExecutor) CreateProposalBlock(
 	// Fetch a limited amount of valid txs
 	maxDataBytes := types.MaxDataBytes(maxBytes, evSize, state.Validators.Size())
 
-	txs := blockExec.mempool.ReapMaxBytesMaxGas(maxDataBytes, maxGas)
+	var txs types.Txs
+	if reapMempool {
+		txs = blockExec.mempool.ReapMaxBytesMaxGas(maxDataBytes, maxGas)
+	}
 	commit := lastExtCommit.ToCommit()
 	block := state.MakeBlock(height, txs, commit, evidence, proposerAddr)
 	rpp, err := blockExec.appClient.PrepareProposal(
@@ -285,12 +347,14 @@ func (blockExec *BlockExecutor) ApplyBlock(
 	}
 
 	// Prune old heights, if requested by ABCI app.
+	retainHeight = blockExec.pruneRetainHeight(state, retainHeight)
 	if retainHeight > 0 {
 		pruned, err := blockExec.pruneBlocks(retainHeight)
 		if err != nil {
 			blockExec.logger.Error("failed to prune blocks", "retain_height", retainHeight, "err", err)
 		} else {
 			blockExec.logger.Debug("pruned blocks", "pruned", pruned, "retain_height", retainHeight)
+			blockExec.maybeCompact(pruned)
 		}
 	}
 
@@ -299,7 +363,7 @@ func (blockExec *BlockExecutor) ApplyBlock(
 
 	// Events are fired after everything else.
 	// NOTE: if we crash between Commit and Save, events wont be fired during replay
-	fireEvents(blockExec.logger, blockExec.eventBus, block, blockID, fBlockRes, validatorUpdates)
+	fireEvents(blockExec.logger, blockExec.eventBus, block, blockID, fBlockRes, validatorUpdates, state.ConsensusParams)
 
 	return state, nil
 }
@@ -596,6 +660,8 @@ func (state State) Update(
 
 // Fire NewBlock, NewBlockHeader.
 // Fire TxEvent for every tx.
+// Fire ValidatorSetUpdates and ConsensusParamUpdates if the application
+// returned updates. consensusParams are the params after the updates.
 // NOTE: if Tendermint crashes before commit, some or all of these events may be published again.
 func fireEvents(
 	logger log.Logger,
@@ -604,6 +670,7 @@ func fireEvents(
 	blockID types.BlockID,
 	finalizeBlockResponse *abci.ResponseFinalizeBlock,
 	validatorUpdates []*types.Validator,
+	consensusParams types.ConsensusParams,
 ) {
 	if err := eventBus.PublishEventNewBlock(types.EventDataNewBlock{
 		Block:               block,
@@ -652,8 +719,19 @@ func fireEvents(
 	}
 
 	if len(finalizeBlockResponse.ValidatorUpdates) > 0 {
-		if err := eventBus.PublishEventValidatorSetUpdates(
-			types.EventDataValidatorSetUpdates{ValidatorUpdates: validatorUpdates}); err != nil {
+		if err := eventBus.PublishEventValidatorSetUpdates(types.EventDataValidatorSetUpdates{
+			ValidatorUpdates: validatorUpdates,
+			Height:           block.Height,
+		}); err != nil {
+			logger.Error("failed publishing event", "err", err)
+		}
+	}
+
+	if finalizeBlockResponse.ConsensusParamUpdates != nil {
+		if err := eventBus.PublishEventConsensusParamUpdates(types.EventDataConsensusParamUpdates{
+			ConsensusParams: consensusParams,
+			Height:          block.Height,
+		}); err != nil {
 			logger.Error("failed publishing event", "err", err)
 		}
 	}
@@ -711,7 +789,8 @@ func ExecCommitBlock(
 		}
 
 		blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: bps.Header()}
-		fireEvents(be.logger, be.eventBus, block, blockID, finalizeBlockResponse, validatorUpdates)
+		consensusParams := s.ConsensusParams.UpdateConsensusParams(finalizeBlockResponse.ConsensusParamUpdates)
+		fireEvents(be.logger, be.eventBus, block, blockID, finalizeBlockResponse, validatorUpdates, consensusParams)
 	}
 
 	// Commit block
@@ -725,6 +804,84 @@ func ExecCommitBlock(
 	return finalizeBlockResponse.AppHash, nil
 }
 
+// pruneRetainHeight returns the height below which blocks and states are
+// pruned, given the retain height requested by the application after
+// committing the last block of state. It keeps the last minRetainBlocks
+// blocks, and the blocks evidence may still be submitted for, i.e. those not
+// older than both MaxAgeNumBlocks and MaxAgeDuration. Light client trusting
+// periods are expected to be shorter than MaxAgeDuration, so the headers light
+// clients may need are kept as well. It returns 0 if nothing must be pruned.
+func (blockExec *BlockExecutor) pruneRetainHeight(state State, appRetainHeight int64) int64 {
+	if appRetainHeight <= 0 {
+		return 0
+	}
+	retainHeight := appRetainHeight
+
+	if blockExec.minRetainBlocks > 0 {
+		if h := state.LastBlockHeight - blockExec.minRetainBlocks + 1; h < retainHeight {
+			retainHeight = h
+		}
+	}
+
+	evParams := state.ConsensusParams.Evidence
+	if h := state.LastBlockHeight - evParams.MaxAgeNumBlocks; h < retainHeight {
+		retainHeight = h
+	}
+
+	// Block times increase with heights, so binary search for the lowest
+	// height below retainHeight which evidence may still be submitted for.
+	horizon := state.LastBlockTime.Add(-evParams.MaxAgeDuration)
+	lo, hi := blockExec.blockStore.Base(), retainHeight
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		meta := blockExec.blockStore.LoadBlockMeta(mid)
+		if meta == nil || !meta.Header.Time.Before(horizon) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	if lo < retainHeight {
+		retainHeight = lo
+	}
+
+	if retainHeight <= 0 {
+		return 0
+	}
+	return retainHeight
+}
+
+// maybeCompact starts a background compaction of the stores once
+// compactionInterval blocks were pruned since the last one. A compaction isn't
+// started while the previous one is still running.
+func (blockExec *BlockExecutor) maybeCompact(pruned uint64) {
+	if blockExec.compactionInterval <= 0 || blockExec.compact == nil {
+		return
+	}
+	blockExec.prunedSinceCompact += int64(pruned)
+	if blockExec.prunedSinceCompact < blockExec.compactionInterval {
+		return
+	}
+
+	select {
+	case blockExec.compacting <- struct{}{}:
+	default:
+		return
+	}
+	blockExec.prunedSinceCompact = 0
+
+	go func() {
+		defer func() { <-blockExec.compacting }()
+
+		start := time.Now()
+		if err := blockExec.compact(); err != nil {
+			blockExec.logger.Error("failed to compact stores", "err", err)
+			return
+		}
+		blockExec.logger.Info("compacted stores", "duration", time.Since(start))
+	}()
+}
+
 func (blockExec *BlockExecutor) pruneBlocks(retainHeight int64) (uint64, error) {
 	base := blockExec.blockStore.Base()
 	if retainHeight <= base {
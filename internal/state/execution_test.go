@@ -277,6 +277,75 @@ func TestFinalizeBlockMisbehavior(t *testing.T) {
 	assert.Equal(t, abciMb, app.Misbehavior)
 }
 
+func TestPruneRetainHeight(t *testing.T) {
+	genesis := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// blocks 1 to 100, one minute apart
+	blockStore := &mocks.BlockStore{}
+	blockStore.On("Base").Return(int64(1))
+	blockStore.On("LoadBlockMeta", mock.AnythingOfType("int64")).Return(func(height int64) *types.BlockMeta {
+		return &types.BlockMeta{Header: types.Header{
+			Height: height,
+			Time:   genesis.Add(time.Duration(height) * time.Minute),
+		}}
+	})
+
+	testCases := []struct {
+		name            string
+		minRetainBlocks int64
+		maxAgeNumBlocks int64
+		maxAgeDuration  time.Duration
+		appRetainHeight int64
+		expected        int64
+	}{
+		{"no pruning requested", 0, 10, time.Minute, 0, 0},
+		{"app retain height", 0, 10, time.Minute, 50, 50},
+		{"min retain blocks", 20, 10, time.Minute, 90, 81},
+		{"evidence max age blocks", 0, 30, time.Minute, 90, 70},
+		{"evidence max age duration", 0, 10, 40 * time.Minute, 90, 60},
+		{"everything retained", 200, 10, time.Minute, 90, 0},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			state := sm.State{
+				LastBlockHeight: 100,
+				LastBlockTime:   genesis.Add(100 * time.Minute),
+				ConsensusParams: *types.DefaultConsensusParams(),
+			}
+			state.ConsensusParams.Evidence.MaxAgeNumBlocks = tc.maxAgeNumBlocks
+			state.ConsensusParams.Evidence.MaxAgeDuration = tc.maxAgeDuration
+
+			blockExec := sm.NewBlockExecutor(nil, log.NewNopLogger(), nil, nil, nil, blockStore, nil,
+				sm.NopMetrics(), sm.BlockExecutorWithMinRetainBlocks(tc.minRetainBlocks))
+			assert.Equal(t, tc.expected, blockExec.PruneRetainHeight(state, tc.appRetainHeight))
+		})
+	}
+}
+
+func TestCompactionInterval(t *testing.T) {
+	compacted := make(chan struct{})
+	blockExec := sm.NewBlockExecutor(nil, log.NewNopLogger(), nil, nil, nil, nil, nil, sm.NopMetrics(),
+		sm.BlockExecutorWithCompaction(10, func() error {
+			compacted <- struct{}{}
+			return nil
+		}))
+
+	blockExec.MaybeCompact(5)
+	select {
+	case <-compacted:
+		t.Fatal("compacted before the interval was reached")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	blockExec.MaybeCompact(5)
+	select {
+	case <-compacted:
+	case <-time.After(time.Second):
+		t.Fatal("did not compact once the interval was reached")
+	}
+}
+
 func TestProcessProposal(t *testing.T) {
 	const height = 2
 	txs := factory.MakeNTxs(height, 10)
@@ -489,7 +558,8 @@ func TestUpdateValidators(t *testing.T) {
 	}
 }
 
-// TestFinalizeBlockValidatorUpdates ensures we update validator set and send an event.
+// TestFinalizeBlockValidatorUpdates ensures we update validator set and send
+// an event, as well as an event for the consensus param updates.
 func TestFinalizeBlockValidatorUpdates(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -538,6 +608,12 @@ func TestFinalizeBlockValidatorUpdates(t *testing.T) {
 	})
 	require.NoError(t, err)
 
+	paramsSub, err := eventBus.SubscribeWithArgs(ctx, pubsub.SubscribeArgs{
+		ClientID: "TestFinalizeBlockConsensusParamUpdates",
+		Query:    types.EventQueryConsensusParamUpdates,
+	})
+	require.NoError(t, err)
+
 	block := sf.MakeBlock(state, 1, new(types.Commit))
 	bps, err := block.MakePartSet(testPartSize)
 	require.NoError(t, err)
@@ -571,6 +647,15 @@ func TestFinalizeBlockValidatorUpdates(t *testing.T) {
 		assert.Equal(t, pubkey, event.ValidatorUpdates[0].PubKey)
 		assert.EqualValues(t, 10, event.ValidatorUpdates[0].VotingPower)
 	}
+	assert.EqualValues(t, 1, event.Height)
+
+	msg, err = paramsSub.Next(ctx)
+	require.NoError(t, err)
+	paramsEvent, ok := msg.Data().(types.EventDataConsensusParamUpdates)
+	require.True(t, ok, "Expected event of type EventDataConsensusParamUpdates, got %T", msg.Data())
+	assert.EqualValues(t, 1, paramsEvent.Height)
+	assert.Equal(t, state.ConsensusParams, paramsEvent.ConsensusParams)
+	assert.EqualValues(t, 1, paramsEvent.ConsensusParams.Version.AppVersion)
 }
 
 // TestFinalizeBlockValidatorUpdatesResultingInEmptySet checks that processing validator updates that
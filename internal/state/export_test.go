@@ -10,3 +10,16 @@ import (
 func ValidateValidatorUpdates(abciUpdates []abci.ValidatorUpdate, params types.ValidatorParams) error {
 	return validateValidatorUpdates(abciUpdates, params)
 }
+
+// PruneRetainHeight is an alias for the pruneRetainHeight method of
+// BlockExecutor exported from execution.go, exclusively and explicitly for
+// testing.
+func (blockExec *BlockExecutor) PruneRetainHeight(state State, appRetainHeight int64) int64 {
+	return blockExec.pruneRetainHeight(state, appRetainHeight)
+}
+
+// MaybeCompact is an alias for the maybeCompact method of BlockExecutor
+// exported from execution.go, exclusively and explicitly for testing.
+func (blockExec *BlockExecutor) MaybeCompact(pruned uint64) {
+	blockExec.maybeCompact(pruned)
+}
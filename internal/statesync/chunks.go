@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/tendermint/tendermint/internal/libs/tempfile"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -31,6 +32,7 @@ type chunkQueue struct {
 	sync.Mutex
 	snapshot       *snapshot                  // if this is nil, the queue has been closed
 	dir            string                     // temp dir for on-disk chunk storage
+	keep           bool                       // keep dir on Close(), see openChunkQueue()
 	chunkFiles     map[uint32]string          // path to temporary chunk file
 	chunkSenders   map[uint32]types.NodeID    // the peer who sent the given chunk
 	chunkAllocated map[uint32]bool            // chunks that have been allocated via Allocate()
@@ -86,7 +88,13 @@ func (q *chunkQueue) Add(chunk *chunk) (bool, error) {
 	}
 
 	path := filepath.Join(q.dir, strconv.FormatUint(uint64(chunk.Index), 10))
-	err := os.WriteFile(path, chunk.Chunk, 0600)
+	var err error
+	if q.keep {
+		// a chunk torn by a crash must not be resumed
+		err = tempfile.WriteFileAtomic(path, chunk.Chunk, 0600)
+	} else {
+		err = os.WriteFile(path, chunk.Chunk, 0600)
+	}
 	if err != nil {
 		return false, fmt.Errorf("failed to save chunk %v to file %v: %w", chunk.Index, path, err)
 	}
@@ -129,7 +137,8 @@ func (q *chunkQueue) Allocate() (uint32, error) {
 	return 0, errDone
 }
 
-// Close closes the chunk queue, cleaning up all temporary files.
+// Close closes the chunk queue, cleaning up all temporary files unless the
+// queue was opened in a resume directory.
 func (q *chunkQueue) Close() error {
 	q.Lock()
 	defer q.Unlock()
@@ -147,6 +156,9 @@ func (q *chunkQueue) Close() error {
 	q.waiters = nil
 	q.snapshot = nil
 
+	if q.keep {
+		return nil
+	}
 	if err := os.RemoveAll(q.dir); err != nil {
 		return fmt.Errorf("failed to clean up state sync tempdir %v: %w", q.dir, err)
 	}
@@ -2,6 +2,7 @@ package statesync
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,6 +52,63 @@ func TestNewChunkQueue_TempDir(t *testing.T) {
 	assert.Len(t, files, 0)
 }
 
+func TestOpenChunkQueue_Resume(t *testing.T) {
+	snapshot := &snapshot{
+		Height:   3,
+		Format:   1,
+		Chunks:   5,
+		Hash:     []byte{7},
+		Metadata: nil,
+	}
+	dir := filepath.Join(t.TempDir(), "resume")
+
+	saved, err := loadResumeSnapshot(dir)
+	require.NoError(t, err)
+	assert.Nil(t, saved)
+
+	queue, err := openChunkQueue(snapshot, dir)
+	require.NoError(t, err)
+	for _, index := range []uint32{0, 2} {
+		added, err := queue.Add(&chunk{Height: 3, Format: 1, Index: index, Chunk: []byte{3, 1, byte(index)}})
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	require.NoError(t, queue.Close())
+
+	// Reopening the queue for the same snapshot keeps the fetched chunks,
+	// which are only allocated for fetching if they're missing.
+	saved, err = loadResumeSnapshot(dir)
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, snapshot.Key(), saved.Key())
+
+	queue, err = openChunkQueue(snapshot, dir)
+	require.NoError(t, err)
+	assert.True(t, queue.Has(0))
+	assert.False(t, queue.Has(1))
+	assert.True(t, queue.Has(2))
+	for _, expect := range []uint32{1, 3, 4} {
+		index, err := queue.Allocate()
+		require.NoError(t, err)
+		assert.Equal(t, expect, index)
+	}
+	c, err := queue.Next()
+	require.NoError(t, err)
+	assert.Equal(t, &chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{3, 1, 0}}, c)
+	require.NoError(t, queue.Close())
+
+	// Opening it for another snapshot discards them.
+	snapshot.Height = 4
+	queue, err = openChunkQueue(snapshot, dir)
+	require.NoError(t, err)
+	assert.False(t, queue.Has(0))
+	require.NoError(t, queue.Close())
+
+	require.NoError(t, removeResumeDir(dir))
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+}
+
 func TestChunkQueue(t *testing.T) {
 	queue, teardown := setupChunkQueue(t)
 	defer teardown()
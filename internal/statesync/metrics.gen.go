@@ -56,6 +56,18 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "back_fill_blocks_total",
 			Help:      "The total number of blocks that need to be back-filled.",
 		}, labels).With(labelsAndValues...),
+		ChunksServed: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "chunks_served",
+			Help:      "The number of snapshot chunks served to peers.",
+		}, labels).With(labelsAndValues...),
+		ChunkBytesServed: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "chunk_bytes_served",
+			Help:      "The number of bytes of snapshot chunks served to peers.",
+		}, labels).With(labelsAndValues...),
 	}
 }
 
@@ -68,5 +80,7 @@ func NopMetrics() *Metrics {
 		SnapshotChunkTotal:  discard.NewGauge(),
 		BackFilledBlocks:    discard.NewCounter(),
 		BackFillBlocksTotal: discard.NewGauge(),
+		ChunksServed:        discard.NewCounter(),
+		ChunkBytesServed:    discard.NewCounter(),
 	}
 }
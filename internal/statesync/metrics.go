@@ -27,4 +27,8 @@ type Metrics struct {
 	BackFilledBlocks metrics.Counter
 	// The total number of blocks that need to be back-filled.
 	BackFillBlocksTotal metrics.Gauge
+	// The number of snapshot chunks served to peers.
+	ChunksServed metrics.Counter
+	// The number of bytes of snapshot chunks served to peers.
+	ChunkBytesServed metrics.Counter
 }
@@ -15,6 +15,7 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/internal/eventbus"
+	"github.com/tendermint/tendermint/internal/libs/flowrate"
 	"github.com/tendermint/tendermint/internal/p2p"
 	sm "github.com/tendermint/tendermint/internal/state"
 	"github.com/tendermint/tendermint/internal/store"
@@ -91,6 +92,7 @@ func getChannelDescriptors() map[p2p.ChannelID]*p2p.ChannelDescriptor {
 			RecvMessageCapacity: chunkMsgSize,
 			RecvBufferCapacity:  128,
 			Name:                "chunk",
+			Compress:            true,
 		},
 		LightBlockChannel: {
 			ID:                  LightBlockChannel,
@@ -100,6 +102,7 @@ func getChannelDescriptors() map[p2p.ChannelID]*p2p.ChannelDescriptor {
 			RecvMessageCapacity: lightBlockMsgSize,
 			RecvBufferCapacity:  128,
 			Name:                "light-block",
+			Compress:            true,
 		},
 		ParamsChannel: {
 			ID:                  ParamsChannel,
@@ -145,6 +148,9 @@ type Reactor struct {
 	sendBlockError func(context.Context, p2p.PeerError) error
 	postSyncHook   func(context.Context, sm.State) error
 
+	// chunkSendMonitor limits the rate at which chunks are served to peers.
+	chunkSendMonitor *flowrate.Monitor
+
 	// when true, the reactor will, during startup perform a
 	// statesync for this node, and otherwise just provide
 	// snapshots to other nodes.
@@ -209,6 +215,8 @@ func NewReactor(
 		eventBus:       eventBus,
 		postSyncHook:   postSyncHook,
 		needsStateSync: needsStateSync,
+
+		chunkSendMonitor: flowrate.New(time.Now(), 0, 0),
 	}
 
 	r.BaseService = *service.NewBaseService(logger, "StateSync", r)
@@ -245,6 +253,10 @@ func (r *Reactor) OnStart(ctx context.Context) error {
 	// references to these channels for use later. This is not
 	// ideal.
 	r.initSyncer = func() *syncer {
+		var resumeDir string
+		if r.cfg.ResumeEnabled() {
+			resumeDir = r.cfg.ResumeDir()
+		}
 		return &syncer{
 			logger:        r.logger,
 			stateProvider: r.stateProvider,
@@ -253,6 +265,7 @@ func (r *Reactor) OnStart(ctx context.Context) error {
 			snapshotCh:    snapshotCh,
 			chunkCh:       chunkCh,
 			tempDir:       r.tempDir,
+			resumeDir:     resumeDir,
 			fetchers:      r.cfg.Fetchers,
 			retryTimeout:  r.cfg.ChunkRequestTimeout,
 			metrics:       r.metrics,
@@ -294,6 +307,17 @@ func (r *Reactor) OnStart(ctx context.Context) error {
 				return fmt.Errorf("failed to initialize P2P state provider: %w", err)
 			}
 			r.stateProvider = stateProvider
+
+			// fall back to the RPC servers, if any, when the peers fail to
+			// serve light blocks
+			if len(r.cfg.RPCServers) > 0 {
+				rpcStateProvider, err := NewRPCStateProvider(ctx, chainID, initialHeight, r.cfg.RPCServers, to, spLogger)
+				if err != nil {
+					spLogger.Error("failed to initialize RPC fallback state provider", "err", err)
+					return nil
+				}
+				r.stateProvider = NewFallbackStateProvider(stateProvider, rpcStateProvider, spLogger)
+			}
 			return nil
 		}
 
@@ -616,7 +640,7 @@ func (r *Reactor) handleSnapshotMessage(ctx context.Context, envelope *p2p.Envel
 
 	switch msg := envelope.Message.(type) {
 	case *ssproto.SnapshotsRequest:
-		snapshots, err := r.recentSnapshots(ctx, recentSnapshots)
+		snapshots, err := r.recentSnapshots(ctx, r.cfg.ServeRecentSnapshots)
 		if err != nil {
 			logger.Error("failed to fetch snapshots", "err", err)
 			return nil
@@ -691,6 +715,10 @@ func (r *Reactor) handleChunkMessage(ctx context.Context, envelope *p2p.Envelope
 			"format", msg.Format,
 			"chunk", msg.Index,
 			"peer", envelope.From)
+		if r.cfg.ServeRecentSnapshots == 0 {
+			r.logger.Debug("ignoring chunk request; not serving snapshots", "peer", envelope.From)
+			return nil
+		}
 		resp, err := r.conn.LoadSnapshotChunk(ctx, &abci.RequestLoadSnapshotChunk{
 			Height: msg.Height,
 			Format: msg.Format,
@@ -706,6 +734,10 @@ func (r *Reactor) handleChunkMessage(ctx context.Context, envelope *p2p.Envelope
 			return nil
 		}
 
+		if err := r.limitChunkSendRate(ctx, len(resp.Chunk)); err != nil {
+			return err
+		}
+
 		r.logger.Debug("sending chunk",
 			"height", msg.Height,
 			"format", msg.Format,
@@ -723,6 +755,10 @@ func (r *Reactor) handleChunkMessage(ctx context.Context, envelope *p2p.Envelope
 		}); err != nil {
 			return err
 		}
+		if resp.Chunk != nil {
+			r.metrics.ChunksServed.Add(1)
+			r.metrics.ChunkBytesServed.Add(float64(len(resp.Chunk)))
+		}
 
 	case *ssproto.ChunkResponse:
 		r.mtx.RLock()
@@ -1030,7 +1066,7 @@ func (r *Reactor) recentSnapshots(ctx context.Context, n uint32) ([]*snapshot, e
 
 	snapshots := make([]*snapshot, 0, n)
 	for i, s := range resp.Snapshots {
-		if i >= recentSnapshots {
+		if i >= int(n) {
 			break
 		}
 
@@ -1046,6 +1082,20 @@ func (r *Reactor) recentSnapshots(ctx context.Context, n uint32) ([]*snapshot, e
 	return snapshots, nil
 }
 
+// limitChunkSendRate blocks until size bytes of chunks may be sent without
+// exceeding the configured chunk send rate.
+func (r *Reactor) limitChunkSendRate(ctx context.Context, size int) error {
+	for size > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n := r.chunkSendMonitor.Limit(size, r.cfg.ChunkSendRate, true)
+		r.chunkSendMonitor.Update(n)
+		size -= n
+	}
+	return nil
+}
+
 // fetchLightBlock works out whether the node has a light block at a particular
 // height and if so returns it so it can be gossiped to peers
 func (r *Reactor) fetchLightBlock(height uint64) (*types.LightBlock, error) {
@@ -428,6 +428,34 @@ func TestReactor_SnapshotsRequest(t *testing.T) {
 	}
 }
 
+func TestReactor_SnapshotsRequest_ServeRecentSnapshots(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn := &clientmocks.Client{}
+	conn.On("ListSnapshots", mock.Anything, &abci.RequestListSnapshots{}).Return(&abci.ResponseListSnapshots{
+		Snapshots: []*abci.Snapshot{
+			{Height: 1, Format: 1, Chunks: 7, Hash: []byte{1, 1}},
+			{Height: 3, Format: 1, Chunks: 7, Hash: []byte{3, 1}},
+			{Height: 2, Format: 1, Chunks: 7, Hash: []byte{2, 1}},
+		},
+	}, nil)
+
+	rts := setup(ctx, t, conn, nil, 100)
+	rts.reactor.cfg.ServeRecentSnapshots = 2
+
+	rts.snapshotInCh <- p2p.Envelope{
+		From:      types.NodeID("aa"),
+		ChannelID: SnapshotChannel,
+		Message:   &ssproto.SnapshotsRequest{},
+	}
+	retryUntil(ctx, t, func() bool { return len(rts.snapshotOutCh) == 2 }, time.Second)
+
+	require.Equal(t, uint64(3), (<-rts.snapshotOutCh).Message.(*ssproto.SnapshotsResponse).Height)
+	require.Equal(t, uint64(2), (<-rts.snapshotOutCh).Message.(*ssproto.SnapshotsResponse).Height)
+	require.Empty(t, rts.snapshotOutCh)
+}
+
 func TestReactor_LightBlockResponse(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -0,0 +1,108 @@
+package statesync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/tendermint/tendermint/internal/libs/tempfile"
+	"github.com/tendermint/tendermint/types"
+)
+
+// A resume directory holds the snapshot being restored, in resumeSnapshotFile,
+// and the chunks fetched for it, in resumeChunksDir. It outlives the node, so
+// that a restore interrupted by a restart can pick up the chunks already
+// fetched instead of discarding them. ABCI has no way to resume a partial
+// restore in the app, so the snapshot is offered again and the chunks are
+// re-applied, but only the missing ones are fetched from peers.
+const (
+	resumeSnapshotFile = "snapshot.json"
+	resumeChunksDir    = "chunks"
+)
+
+// loadResumeSnapshot loads the snapshot being restored from the resume
+// directory dir. It returns nil if there is none.
+func loadResumeSnapshot(dir string) (*snapshot, error) {
+	bz, err := os.ReadFile(filepath.Join(dir, resumeSnapshotFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	s := &snapshot{}
+	if err := json.Unmarshal(bz, s); err != nil {
+		return nil, fmt.Errorf("decoding %v: %w", resumeSnapshotFile, err)
+	}
+	return s, nil
+}
+
+// openChunkQueue creates a chunk queue for a snapshot, keeping the chunks in
+// the resume directory dir. If dir holds the same snapshot, the chunks already
+// there are added to the queue, otherwise the contents of dir are replaced.
+// Callers must call Close() when done, which keeps the chunks on disk, and
+// removeResumeDir() once they aren't needed anymore.
+func openChunkQueue(snapshot *snapshot, dir string) (*chunkQueue, error) {
+	if snapshot.Chunks == 0 {
+		return nil, errors.New("snapshot has no chunks")
+	}
+
+	saved, err := loadResumeSnapshot(dir)
+	if err != nil {
+		return nil, err
+	}
+	if saved == nil || saved.Key() != snapshot.Key() {
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Join(dir, resumeChunksDir), 0700); err != nil {
+			return nil, err
+		}
+		bz, err := json.Marshal(snapshot)
+		if err != nil {
+			return nil, err
+		}
+		if err := tempfile.WriteFileAtomic(filepath.Join(dir, resumeSnapshotFile), bz, 0600); err != nil {
+			return nil, err
+		}
+	}
+
+	q := &chunkQueue{
+		snapshot:       snapshot,
+		dir:            filepath.Join(dir, resumeChunksDir),
+		keep:           true,
+		chunkFiles:     make(map[uint32]string, snapshot.Chunks),
+		chunkSenders:   make(map[uint32]types.NodeID, snapshot.Chunks),
+		chunkAllocated: make(map[uint32]bool, snapshot.Chunks),
+		chunkReturned:  make(map[uint32]bool, snapshot.Chunks),
+		waiters:        make(map[uint32][]chan<- uint32),
+	}
+
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		index, err := strconv.ParseUint(entry.Name(), 10, 32)
+		if err != nil || uint32(index) >= snapshot.Chunks {
+			continue
+		}
+		q.chunkFiles[uint32(index)] = filepath.Join(q.dir, entry.Name())
+		q.chunkAllocated[uint32(index)] = true
+	}
+
+	return q, nil
+}
+
+// removeResumeDir removes the resume directory dir, if any.
+func removeResumeDir(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clean up state sync resume dir %v: %w", dir, err)
+	}
+	return nil
+}
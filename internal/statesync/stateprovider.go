@@ -461,3 +461,59 @@ func (s *stateProviderP2P) consensusParams(ctx context.Context, height int64) (t
 	}
 
 }
+
+// stateProviderFallback is a StateProvider which falls back to another
+// provider when its primary one fails, e.g. to RPC servers when the peers
+// don't serve the light blocks of the needed heights.
+type stateProviderFallback struct {
+	primary  StateProvider
+	fallback StateProvider
+	logger   log.Logger
+}
+
+// NewFallbackStateProvider creates a StateProvider which uses primary, and
+// fallback whenever primary fails.
+func NewFallbackStateProvider(primary, fallback StateProvider, logger log.Logger) StateProvider {
+	return &stateProviderFallback{
+		primary:  primary,
+		fallback: fallback,
+		logger:   logger,
+	}
+}
+
+// shouldFallBack returns true if the primary provider failed with err, and
+// the fallback provider should be tried.
+func (s *stateProviderFallback) shouldFallBack(ctx context.Context, err error, height uint64) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	s.logger.Info("primary state provider failed, falling back", "height", height, "err", err)
+	return true
+}
+
+// AppHash implements StateProvider.
+func (s *stateProviderFallback) AppHash(ctx context.Context, height uint64) ([]byte, error) {
+	appHash, err := s.primary.AppHash(ctx, height)
+	if s.shouldFallBack(ctx, err, height) {
+		return s.fallback.AppHash(ctx, height)
+	}
+	return appHash, err
+}
+
+// Commit implements StateProvider.
+func (s *stateProviderFallback) Commit(ctx context.Context, height uint64) (*types.Commit, error) {
+	commit, err := s.primary.Commit(ctx, height)
+	if s.shouldFallBack(ctx, err, height) {
+		return s.fallback.Commit(ctx, height)
+	}
+	return commit, err
+}
+
+// State implements StateProvider.
+func (s *stateProviderFallback) State(ctx context.Context, height uint64) (sm.State, error) {
+	state, err := s.primary.State(ctx, height)
+	if s.shouldFallBack(ctx, err, height) {
+		return s.fallback.State(ctx, height)
+	}
+	return state, err
+}
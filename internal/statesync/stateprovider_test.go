@@ -0,0 +1,59 @@
+package statesync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/internal/statesync/mocks"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestFallbackStateProvider(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errNoBlock := errors.New("no light block")
+
+	// the primary provider only serves height 1
+	primary := &mocks.StateProvider{}
+	primary.On("AppHash", mock.Anything, uint64(1)).Return([]byte("primary"), nil)
+	primary.On("AppHash", mock.Anything, uint64(2)).Return(nil, errNoBlock)
+	primary.On("Commit", mock.Anything, uint64(2)).Return(nil, errNoBlock)
+	primary.On("State", mock.Anything, uint64(2)).Return(sm.State{}, errNoBlock)
+
+	fallback := &mocks.StateProvider{}
+	fallback.On("AppHash", mock.Anything, uint64(2)).Return([]byte("fallback"), nil)
+	fallback.On("Commit", mock.Anything, uint64(2)).Return(&types.Commit{Height: 2}, nil)
+	fallback.On("State", mock.Anything, uint64(2)).Return(sm.State{LastBlockHeight: 2}, nil)
+
+	sp := NewFallbackStateProvider(primary, fallback, log.NewNopLogger())
+
+	appHash, err := sp.AppHash(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("primary"), appHash)
+	fallback.AssertNotCalled(t, "AppHash", mock.Anything, uint64(1))
+
+	appHash, err = sp.AppHash(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, []byte("fallback"), appHash)
+
+	commit, err := sp.Commit(ctx, 2)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, commit.Height)
+
+	state, err := sp.State(ctx, 2)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, state.LastBlockHeight)
+
+	// the fallback isn't used once the context is canceled
+	cancel()
+	_, err = sp.AppHash(ctx, 2)
+	require.ErrorIs(t, err, errNoBlock)
+	fallback.AssertNumberOfCalls(t, "AppHash", 1)
+}
@@ -59,6 +59,7 @@ type syncer struct {
 	snapshotCh    p2p.Channel
 	chunkCh       p2p.Channel
 	tempDir       string
+	resumeDir     string // if set, chunks are kept there to resume restores
 	fetchers      int32
 	retryTimeout  time.Duration
 
@@ -164,7 +165,10 @@ func (s *syncer) SyncAny(
 		iters++
 		// If not nil, we're going to retry restoration of the same snapshot.
 		if snapshot == nil {
-			snapshot = s.snapshots.Best()
+			snapshot = s.resumableSnapshot()
+			if snapshot == nil {
+				snapshot = s.snapshots.Best()
+			}
 			chunks = nil
 		}
 		if snapshot == nil {
@@ -183,7 +187,11 @@ func (s *syncer) SyncAny(
 			}
 		}
 		if chunks == nil {
-			chunks, err = newChunkQueue(snapshot, s.tempDir)
+			if s.resumeDir != "" {
+				chunks, err = openChunkQueue(snapshot, s.resumeDir)
+			} else {
+				chunks, err = newChunkQueue(snapshot, s.tempDir)
+			}
 			if err != nil {
 				return sm.State{}, nil, fmt.Errorf("failed to create chunk queue: %w", err)
 			}
@@ -198,6 +206,9 @@ func (s *syncer) SyncAny(
 		case err == nil:
 			s.metrics.SnapshotHeight.Set(float64(snapshot.Height))
 			s.lastSyncedSnapshotHeight = int64(snapshot.Height)
+			if err := removeResumeDir(s.resumeDir); err != nil {
+				s.logger.Error("Failed to clean up chunk queue", "err", err)
+			}
 			return newState, commit, nil
 
 		case errors.Is(err, errAbort):
@@ -237,6 +248,9 @@ func (s *syncer) SyncAny(
 
 		// Discard snapshot and chunks for next iteration
 		err = chunks.Close()
+		if err == nil {
+			err = removeResumeDir(s.resumeDir)
+		}
 		if err != nil {
 			s.logger.Error("Failed to clean up chunk queue", "err", err)
 		}
@@ -246,6 +260,28 @@ func (s *syncer) SyncAny(
 	}
 }
 
+// resumableSnapshot returns the snapshot of an interrupted restore, if any,
+// provided that peers still offer it.
+func (s *syncer) resumableSnapshot() *snapshot {
+	if s.resumeDir == "" {
+		return nil
+	}
+	saved, err := loadResumeSnapshot(s.resumeDir)
+	if err != nil {
+		s.logger.Error("Failed to load snapshot to resume", "err", err)
+		return nil
+	}
+	if saved == nil {
+		return nil
+	}
+	snapshot := s.snapshots.Get(saved.Key())
+	if snapshot != nil {
+		s.logger.Info("Resuming snapshot restore", "height", snapshot.Height, "format", snapshot.Format,
+			"hash", snapshot.Hash)
+	}
+	return snapshot
+}
+
 // Sync executes a sync for a specific snapshot, returning the latest state and block commit which
 // the caller must use to bootstrap the node.
 func (s *syncer) Sync(ctx context.Context, snapshot *snapshot, chunks *chunkQueue) (sm.State, *types.Commit, error) {
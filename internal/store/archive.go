@@ -0,0 +1,104 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gogo/protobuf/proto"
+
+	bcproto "github.com/tendermint/tendermint/proto/tendermint/blocksync"
+	"github.com/tendermint/tendermint/types"
+)
+
+// A block archive is a directory holding one file per block, named after its
+// height by ArchiveFileName. Each file holds a blocksync BlockResponse, i.e.
+// the block and the commit for it, so that every block of an archive can be
+// verified on its own when it's imported.
+
+// ErrArchivedBlockNotFound is returned when a block is missing from an archive.
+var ErrArchivedBlockNotFound = errors.New("block not found in archive")
+
+// ArchiveFileName returns the name of the file of the block at height in a
+// block archive. Heights are zero-padded, so that the files sort by height.
+func ArchiveFileName(height int64) string {
+	return fmt.Sprintf("%020d.block", height)
+}
+
+// ExportBlocks writes the blocks from height from to height to, inclusive, to
+// the block archive in dir, together with their commits. The extended commits
+// are exported where the block store has them.
+func (bs *BlockStore) ExportBlocks(dir string, from, to int64) error {
+	if from < bs.Base() || to > bs.Height() || from > to {
+		return fmt.Errorf("invalid height range %d-%d, the block store has %d-%d",
+			from, to, bs.Base(), bs.Height())
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for height := from; height <= to; height++ {
+		block := bs.LoadBlock(height)
+		if block == nil {
+			return fmt.Errorf("block %d not found", height)
+		}
+		extCommit := bs.LoadBlockExtendedCommit(height)
+		if extCommit == nil {
+			commit := bs.LoadBlockCommit(height)
+			if commit == nil && height == bs.Height() {
+				commit = bs.LoadSeenCommit()
+			}
+			if commit == nil || commit.Height != height {
+				return fmt.Errorf("commit for block %d not found", height)
+			}
+			extCommit = commit.WrappedExtendedCommit()
+		}
+
+		pb, err := block.ToProto()
+		if err != nil {
+			return fmt.Errorf("converting block %d to proto: %w", height, err)
+		}
+		bz, err := proto.Marshal(&bcproto.BlockResponse{
+			Block:     pb,
+			ExtCommit: extCommit.ToProto(),
+		})
+		if err != nil {
+			return fmt.Errorf("encoding block %d: %w", height, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, ArchiveFileName(height)), bz, 0644); err != nil { // nolint: gosec
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadArchivedBlock reads the block at height, and the commit for it, from the
+// block archive in dir. It returns ErrArchivedBlockNotFound if the archive
+// doesn't have the block. The block and the commit aren't verified.
+func ReadArchivedBlock(dir string, height int64) (*types.Block, *types.ExtendedCommit, error) {
+	bz, err := os.ReadFile(filepath.Join(dir, ArchiveFileName(height)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, ErrArchivedBlockNotFound
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	var pb bcproto.BlockResponse
+	if err := proto.Unmarshal(bz, &pb); err != nil {
+		return nil, nil, fmt.Errorf("decoding block %d: %w", height, err)
+	}
+	block, err := types.BlockFromProto(pb.Block)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding block %d: %w", height, err)
+	}
+	extCommit, err := types.ExtendedCommitFromProto(pb.ExtCommit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding commit for block %d: %w", height, err)
+	}
+	if block.Height != height || extCommit.Height != height {
+		return nil, nil, fmt.Errorf("archive file %s holds block %d with the commit for height %d",
+			ArchiveFileName(height), block.Height, extCommit.Height)
+	}
+	return block, extCommit, nil
+}
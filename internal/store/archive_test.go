@@ -0,0 +1,53 @@
+package store
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/config"
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/internal/state/test/factory"
+	tmtime "github.com/tendermint/tendermint/libs/time"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestExportReadArchivedBlocks(t *testing.T) {
+	cfg, err := config.ResetTestRoot(t.TempDir(), "block_archive_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(cfg.RootDir)
+	state, err := sm.MakeGenesisStateFromFile(cfg.GenesisFile())
+	require.NoError(t, err)
+
+	bs := NewBlockStore(dbm.NewMemDB())
+	for h := int64(1); h <= 5; h++ {
+		block := factory.MakeBlock(state, h, new(types.Commit))
+		partSet, err := block.MakePartSet(2)
+		require.NoError(t, err)
+		bs.SaveBlockWithExtendedCommit(block, partSet, makeTestExtCommit(h, tmtime.Now()))
+	}
+
+	dir := t.TempDir()
+	require.Error(t, bs.ExportBlocks(dir, 0, 5))
+	require.Error(t, bs.ExportBlocks(dir, 2, 6))
+	require.NoError(t, bs.ExportBlocks(dir, 2, 5))
+
+	for h := int64(2); h <= 5; h++ {
+		block, extCommit, err := ReadArchivedBlock(dir, h)
+		require.NoError(t, err)
+		assert.Equal(t, bs.LoadBlock(h).Hash(), block.Hash())
+		assert.Equal(t, bs.LoadBlockExtendedCommit(h), extCommit)
+	}
+
+	_, _, err = ReadArchivedBlock(dir, 1)
+	require.ErrorIs(t, err, ErrArchivedBlockNotFound)
+
+	// a block stored under another height is rejected
+	require.NoError(t, os.Rename(
+		dir+"/"+ArchiveFileName(5), dir+"/"+ArchiveFileName(6)))
+	_, _, err = ReadArchivedBlock(dir, 6)
+	require.Error(t, err)
+}
@@ -0,0 +1,29 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	dbm "github.com/tendermint/tm-db"
+)
+
+// ErrCompactionNotSupported is returned when compacting a database whose
+// backend doesn't support it.
+var ErrCompactionNotSupported = errors.New("compaction is only supported with goleveldb")
+
+// CompactDB compacts the whole key range of db, so that the disk space of the
+// keys deleted by pruning is reclaimed. Only goleveldb databases are supported.
+// It's safe to call while db is in use.
+func CompactDB(db dbm.DB) error {
+	ldb, ok := db.(interface{ DB() *leveldb.DB })
+	if !ok {
+		return ErrCompactionNotSupported
+	}
+	return ldb.DB().CompactRange(util.Range{Start: nil, Limit: nil})
+}
+
+// Compact compacts the underlying database of the block store.
+func (bs *BlockStore) Compact() error {
+	return CompactDB(bs.db)
+}
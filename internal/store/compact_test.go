@@ -0,0 +1,28 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestCompactDB(t *testing.T) {
+	db, err := dbm.NewGoLevelDB("compact", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	for i := byte(0); i < 100; i++ {
+		require.NoError(t, db.Set([]byte{i}, []byte{i}))
+	}
+	for i := byte(0); i < 50; i++ {
+		require.NoError(t, db.Delete([]byte{i}))
+	}
+	require.NoError(t, CompactDB(db))
+
+	bz, err := db.Get([]byte{99})
+	require.NoError(t, err)
+	require.Equal(t, []byte{99}, bz)
+
+	require.ErrorIs(t, CompactDB(dbm.NewMemDB()), ErrCompactionNotSupported)
+}
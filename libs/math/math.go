@@ -14,6 +14,13 @@ func MaxInt(a, b int) int {
 	return b
 }
 
+func MaxUint64(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 //-----------------------------------------------------------------------------
 
 func MinInt64(a, b int64) int64 {
@@ -29,3 +36,10 @@ func MinInt(a, b int) int {
 	}
 	return b
 }
+
+func MinUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
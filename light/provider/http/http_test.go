@@ -42,6 +42,13 @@ func TestProvider(t *testing.T) {
 	cfg, err := rpctest.CreateConfig(t, t.Name())
 	require.NoError(t, err)
 
+	// blocks are only pruned once evidence for them has expired
+	genDoc, err := types.GenesisDocFromFile(cfg.GenesisFile())
+	require.NoError(t, err)
+	genDoc.ConsensusParams.Evidence.MaxAgeNumBlocks = 1
+	genDoc.ConsensusParams.Evidence.MaxAgeDuration = time.Millisecond
+	require.NoError(t, genDoc.SaveAs(cfg.GenesisFile()))
+
 	// start a tendermint node in the background to test against
 	app := kvstore.NewApplication()
 	app.RetainBlocks = 9
@@ -49,8 +56,6 @@ func TestProvider(t *testing.T) {
 	require.NoError(t, err)
 
 	rpcAddr := cfg.RPC.ListenAddress
-	genDoc, err := types.GenesisDocFromFile(cfg.GenesisFile())
-	require.NoError(t, err)
 
 	chainID := genDoc.ChainID
 	t.Log("chainID:", chainID)
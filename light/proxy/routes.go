@@ -68,6 +68,10 @@ func (p proxyService) CheckTx(ctx context.Context, req *coretypes.RequestCheckTx
 	return p.Client.CheckTx(ctx, req.Tx)
 }
 
+func (p proxyService) CheckTxResult(ctx context.Context, req *coretypes.RequestCheckTxResult) (*coretypes.ResultCheckTxResult, error) {
+	return p.Client.CheckTxResult(ctx, req.Hash)
+}
+
 func (p proxyService) Commit(ctx context.Context, req *coretypes.RequestBlockInfo) (*coretypes.ResultCommit, error) {
 	return p.Client.Commit(ctx, (*int64)(req.Height))
 }
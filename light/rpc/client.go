@@ -245,6 +245,10 @@ func (c *Client) CheckTx(ctx context.Context, tx types.Tx) (*coretypes.ResultChe
 	return c.next.CheckTx(ctx, tx)
 }
 
+func (c *Client) CheckTxResult(ctx context.Context, hash tmbytes.HexBytes) (*coretypes.ResultCheckTxResult, error) {
+	return c.next.CheckTxResult(ctx, hash)
+}
+
 func (c *Client) RemoveTx(ctx context.Context, txKey types.TxKey) error {
 	return c.next.RemoveTx(ctx, txKey)
 }
@@ -66,6 +66,7 @@ type nodeImpl struct {
 	stateStore     sm.Store
 	blockStore     *store.BlockStore // store the blockchain to disk
 	evPool         *evidence.Pool
+	mempool        *mempool.TxMempool
 	indexerService *indexer.Service
 	services       []service.Service
 	rpcListeners   []net.Listener // rpc servers
@@ -127,7 +128,13 @@ func makeNode(
 	genesisDocProvider genesisDocProvider,
 	dbProvider config.DBProvider,
 	logger log.Logger,
+	opts ...Option,
 ) (service.Service, error) {
+	var options nodeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	var cancel context.CancelFunc
 	ctx, cancel = context.WithCancel(ctx)
 
@@ -245,6 +252,7 @@ func makeNode(
 			EventLog:   eventLog,
 			Logger:     logger.With("module", "rpc"),
 			Config:     *cfg.RPC,
+			MaxTxBytes: cfg.Mempool.MaxTxBytes,
 		},
 	}
 
@@ -266,8 +274,9 @@ func makeNode(
 	node.evPool = evPool
 
 	mpReactor, mp := createMempoolReactor(logger, cfg, proxyApp, stateStore, nodeMetrics.mempool,
-		peerManager.Subscribe, node.router.OpenChannel)
+		peerManager.Subscribe, node.router.OpenChannel, eventBus)
 	node.rpcEnv.Mempool = mp
+	node.mempool = mp
 	node.services = append(node.services, mpReactor)
 
 	// make block executor for consensus and blockchain reactors to execute blocks
@@ -280,6 +289,16 @@ func makeNode(
 		blockStore,
 		eventBus,
 		nodeMetrics.state,
+		sm.BlockExecutorWithMinRetainBlocks(cfg.MinRetainBlocks),
+		sm.BlockExecutorWithCompaction(cfg.CompactionInterval, func() error {
+			if err := blockStore.Compact(); err != nil {
+				return fmt.Errorf("blockstore: %w", err)
+			}
+			if err := store.CompactDB(stateDB); err != nil {
+				return fmt.Errorf("state: %w", err)
+			}
+			return nil
+		}),
 	)
 
 	// Determine whether we should attempt state sync.
@@ -294,6 +313,16 @@ func makeNode(
 	blockSync := !onlyValidatorIsUs(state, pubKey)
 	waitSync := stateSync || blockSync
 
+	csOptions := []consensus.StateOption{
+		consensus.StateMetrics(nodeMetrics.consensus),
+		consensus.SkipStateStoreBootstrap,
+	}
+	if options.proposerSelector != nil {
+		csOptions = append(csOptions, consensus.StateProposerSelector(options.proposerSelector))
+	}
+	if options.proposalBlockHook != nil {
+		csOptions = append(csOptions, consensus.StateProposalBlockHook(options.proposalBlockHook))
+	}
 	csState, err := consensus.NewState(logger.With("module", "consensus"),
 		cfg.Consensus,
 		stateStore,
@@ -302,8 +331,7 @@ func makeNode(
 		mp,
 		evPool,
 		eventBus,
-		consensus.StateMetrics(nodeMetrics.consensus),
-		consensus.SkipStateStoreBootstrap,
+		csOptions...,
 	)
 	if err != nil {
 		return nil, combineCloseError(err, makeCloser(closers))
@@ -326,6 +354,7 @@ func makeNode(
 	// doing a state sync first.
 	bcReactor := blocksync.NewReactor(
 		logger.With("module", "blockchain"),
+		cfg.BlockSync,
 		stateStore,
 		blockExec,
 		blockStore,
@@ -348,7 +377,15 @@ func makeNode(
 	}
 
 	if cfg.P2P.PexReactor {
-		node.services = append(node.services, pex.NewReactor(logger, peerManager, node.router.OpenChannel, peerManager.Subscribe))
+		node.services = append(node.services, pex.NewReactor(logger, peerManager, node.router.OpenChannel, peerManager.Subscribe,
+			pex.WithMetrics(nodeMetrics.pex),
+			pex.WithAddressBudget(cfg.P2P.PexAddressBudget),
+			pex.WithColdStart(cfg.P2P.PexColdStartPeers),
+			pex.WithSeeds(bootstrapPeerIDs(cfg)...),
+			pex.WithRequestRateTarget(cfg.P2P.PexRequestRateTarget),
+			pex.WithCPUTarget(cfg.P2P.PexCPUTarget),
+			pex.WithMinRequestInterval(cfg.P2P.PexMinRequestInterval),
+			pex.WithStopTimeout(cfg.P2P.PexStopTimeout)))
 	}
 
 	// Set up state sync reactor, and schedule a sync if requested.
@@ -389,7 +426,7 @@ func makeNode(
 
 	if cfg.Mode == config.ModeValidator {
 		if privValidator != nil {
-			csState.SetPrivValidator(ctx, privValidator)
+			csState.SetPrivValidator(ctx, gatePrivval(cfg, privValidator))
 		}
 		node.rpcEnv.PubKey = pubKey
 	}
@@ -441,6 +478,28 @@ func (n *nodeImpl) OnStart(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+
+	// If we're behind a NAT gateway, map our listen port on it and advertise
+	// the gateway's external address instead.
+	if n.config.P2P.UPNP && n.config.P2P.ExternalAddress == "" {
+		addr, err := mapNATPort(ctx, n.logger.With("module", "nat"), n.config, n.nodeKey.ID)
+		if err != nil {
+			n.logger.Error("failed to map p2p port on NAT gateway", "err", err)
+		} else {
+			n.logger.Info("advertising NAT gateway address", "addr", addr)
+			n.nodeInfo.ListenAddr = addr
+			if err := setSelfAddress(n.peerManager, n.nodeKey.ID, addr); err != nil {
+				n.logger.Error("failed to advertise NAT gateway address", "err", err)
+			}
+		}
+	}
+
+	if n.config.P2P.MDNS {
+		if err := startMDNS(ctx, n.logger.With("module", "mdns"), n.config, n.nodeKey.ID,
+			n.nodeInfo.Network, n.peerManager); err != nil {
+			n.logger.Error("failed to start mDNS peer discovery", "err", err)
+		}
+	}
 	// Start Internal Services
 
 	if n.config.RPC.PprofListenAddress != "" {
@@ -488,6 +547,14 @@ func (n *nodeImpl) OnStart(ctx context.Context) error {
 		return err
 	}
 
+	// Restore the transactions from the mempool WAL before the mempool can
+	// receive new ones, now that the application has caught up.
+	if n.config.Mempool.WalEnabled() {
+		if err := n.mempool.InitWAL(ctx); err != nil {
+			return err
+		}
+	}
+
 	if n.config.Instrumentation.Prometheus && n.config.Instrumentation.PrometheusListenAddr != "" {
 		n.prometheusSrv = n.startPrometheusServer(ctx, n.config.Instrumentation.PrometheusListenAddr)
 	}
@@ -539,6 +606,10 @@ func (n *nodeImpl) OnStop() {
 		reactor.Wait()
 	}
 
+	if err := n.mempool.CloseWAL(); err != nil {
+		n.logger.Error("problem closing mempool WAL", "err", err)
+	}
+
 	n.router.Wait()
 	n.rpcEnv.IsListening = false
 
@@ -644,6 +715,7 @@ type nodeMetrics struct {
 	indexer   *indexer.Metrics
 	mempool   *mempool.Metrics
 	p2p       *p2p.Metrics
+	pex       *pex.Metrics
 	proxy     *proxy.Metrics
 	state     *sm.Metrics
 	statesync *statesync.Metrics
@@ -664,6 +736,7 @@ func defaultMetricsProvider(cfg *config.InstrumentationConfig) metricsProvider {
 				indexer:   indexer.PrometheusMetrics(cfg.Namespace, "chain_id", chainID),
 				mempool:   mempool.PrometheusMetrics(cfg.Namespace, "chain_id", chainID),
 				p2p:       p2p.PrometheusMetrics(cfg.Namespace, "chain_id", chainID),
+				pex:       pex.PrometheusMetrics(cfg.Namespace, "chain_id", chainID),
 				proxy:     proxy.PrometheusMetrics(cfg.Namespace, "chain_id", chainID),
 				state:     sm.PrometheusMetrics(cfg.Namespace, "chain_id", chainID),
 				statesync: statesync.PrometheusMetrics(cfg.Namespace, "chain_id", chainID),
@@ -675,6 +748,7 @@ func defaultMetricsProvider(cfg *config.InstrumentationConfig) metricsProvider {
 			indexer:   indexer.NopMetrics(),
 			mempool:   mempool.NopMetrics(),
 			p2p:       p2p.NopMetrics(),
+			pex:       pex.NopMetrics(),
 			proxy:     proxy.NopMetrics(),
 			state:     sm.NopMetrics(),
 			statesync: statesync.NopMetrics(),
@@ -718,6 +792,7 @@ func getRouterConfig(conf *config.Config, appClient abciclient.Client) p2p.Route
 		QueueType:        conf.P2P.QueueType,
 		HandshakeTimeout: conf.P2P.HandshakeTimeout,
 		DialTimeout:      conf.P2P.DialTimeout,
+		ResolveInterval:  conf.P2P.ResolveInterval,
 	}
 
 	if conf.FilterPeers && appClient != nil {
@@ -7,6 +7,7 @@ import (
 	"math"
 	"net"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/tendermint/tendermint/internal/eventbus"
 	"github.com/tendermint/tendermint/internal/evidence"
 	"github.com/tendermint/tendermint/internal/mempool"
+	"github.com/tendermint/tendermint/internal/p2p"
 	"github.com/tendermint/tendermint/internal/proxy"
 	"github.com/tendermint/tendermint/internal/pubsub"
 	sm "github.com/tendermint/tendermint/internal/state"
@@ -791,3 +793,19 @@ func loadStatefromGenesis(ctx context.Context, t *testing.T) sm.State {
 
 	return state
 }
+
+func TestSetSelfAddress(t *testing.T) {
+	nodeKey := types.GenNodeKey()
+	peerManager, err := p2p.NewPeerManager(nodeKey.ID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+
+	// The address mapped on a NAT gateway is advertised to peers.
+	require.NoError(t, setSelfAddress(peerManager, nodeKey.ID, "203.0.113.1:26656"))
+	advertised := peerManager.Advertise(types.NodeID(strings.Repeat("a", 40)), 100)
+	require.Len(t, advertised, 1)
+	require.Equal(t, nodeKey.ID, advertised[0].NodeID)
+	require.Equal(t, "203.0.113.1", advertised[0].Hostname)
+	require.EqualValues(t, 26656, advertised[0].Port)
+
+	require.Error(t, setSelfAddress(peerManager, nodeKey.ID, "not an address"))
+}
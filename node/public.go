@@ -30,13 +30,14 @@ func NewDefault(
 // process as the tendermint node.  The final option is a pointer to a
 // Genesis document: if the value is nil, the genesis document is read
 // from the file specified in the config, and otherwise the node uses
-// value of the final argument.
+// value of the final argument. The options customize the node further.
 func New(
 	ctx context.Context,
 	conf *config.Config,
 	logger log.Logger,
 	cf abciclient.Client,
 	gen *types.GenesisDoc,
+	opts ...Option,
 ) (service.Service, error) {
 	nodeKey, err := types.LoadOrGenNodeKey(conf.NodeKeyFile())
 	if err != nil {
@@ -66,10 +67,34 @@ func New(
 			cf,
 			genProvider,
 			config.DefaultDBProvider,
-			logger)
+			logger,
+			opts...)
 	case config.ModeSeed:
 		return makeSeedNode(logger, conf, config.DefaultDBProvider, nodeKey, genProvider)
 	default:
 		return nil, fmt.Errorf("%q is not a valid mode", conf.Mode)
 	}
 }
+
+// Option sets an optional parameter on a node constructed by New.
+type Option func(*nodeOptions)
+
+type nodeOptions struct {
+	proposerSelector  types.ProposerSelector
+	proposalBlockHook types.ProposalBlockHook
+}
+
+// WithProposerSelector sets the selector of the proposer of each round of
+// consensus, in place of the default rotation by proposer priority. All the
+// validators of a network must use the same selector.
+func WithProposerSelector(selector types.ProposerSelector) Option {
+	return func(o *nodeOptions) { o.proposerSelector = selector }
+}
+
+// WithProposalBlockHook sets a hook called with each block the node assembles
+// as proposer, before signing the proposal for it. The hook may veto the
+// block, in which case a block without mempool transactions is proposed instead,
+// and if that is vetoed too, a block without any transactions.
+func WithProposalBlockHook(hook types.ProposalBlockHook) Option {
+	return func(o *nodeOptions) { o.proposalBlockHook = hook }
+}
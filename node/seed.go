@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/internal/p2p"
 	"github.com/tendermint/tendermint/internal/p2p/pex"
+	rpccore "github.com/tendermint/tendermint/internal/rpc/core"
 	sm "github.com/tendermint/tendermint/internal/state"
 	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/libs/service"
@@ -33,8 +35,10 @@ type seedNodeImpl struct {
 	isListening bool
 
 	// services
-	pexReactor  service.Service // for exchanging peer addresses
-	shutdownOps closer
+	pexReactor   service.Service // for exchanging peer addresses
+	rpcEnv       *rpccore.Environment
+	rpcListeners []net.Listener // rpc servers
+	shutdownOps  closer
 }
 
 // makeSeedNode returns a new seed node, containing only p2p, pex reactor
@@ -81,6 +85,25 @@ func makeSeedNode(
 			closer)
 	}
 
+	crawlDB, err := dbProvider(&config.DBContext{ID: "crawlstore", Config: cfg})
+	if err != nil {
+		return nil, combineCloseError(
+			fmt.Errorf("unable to initialize crawl store: %w", err),
+			closer)
+	}
+	peerDBCloser := closer
+	closer = func() error { return combineCloseError(crawlDB.Close(), peerDBCloser) }
+
+	pexReactor := pex.NewReactor(logger, peerManager, router.OpenChannel, peerManager.Subscribe,
+		pex.WithSeedMode(),
+		pex.WithCrawlStore(crawlDB),
+		pex.WithMetrics(pex.PrometheusMetrics(cfg.Instrumentation.Namespace, "chain_id", genDoc.ChainID)),
+		pex.WithAddressBudget(cfg.P2P.PexAddressBudget),
+		pex.WithRequestRateTarget(cfg.P2P.PexRequestRateTarget),
+		pex.WithCPUTarget(cfg.P2P.PexCPUTarget),
+		pex.WithMinRequestInterval(cfg.P2P.PexMinRequestInterval),
+		pex.WithStopTimeout(cfg.P2P.PexStopTimeout))
+
 	node := &seedNodeImpl{
 		config:     cfg,
 		logger:     logger,
@@ -92,7 +115,13 @@ func makeSeedNode(
 
 		shutdownOps: closer,
 
-		pexReactor: pex.NewReactor(logger, peerManager, router.OpenChannel, peerManager.Subscribe),
+		pexReactor: pexReactor,
+		rpcEnv: &rpccore.Environment{
+			NodeInfo:    nodeInfo,
+			PeerManager: peerManager,
+			Crawler:     pexReactor,
+			Logger:      logger.With("module", "rpc"),
+		},
 	}
 	node.BaseService = *service.NewBaseService(logger, "SeedNode", node)
 
@@ -143,16 +172,32 @@ func (n *seedNodeImpl) OnStart(ctx context.Context) error {
 		}
 	}
 
+	n.rpcEnv.IsListening = true
+	if n.config.RPC.ListenAddress != "" {
+		var err error
+		n.rpcListeners, err = n.rpcEnv.StartSeedService(ctx, n.config)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // OnStop stops the Seed Node. It implements service.Service.
 func (n *seedNodeImpl) OnStop() {
 	n.logger.Info("Stopping Node")
+	for _, l := range n.rpcListeners {
+		n.logger.Info("Closing rpc listener", "listener", l)
+		if err := l.Close(); err != nil {
+			n.logger.Error("error closing listener", "listener", l, "err", err)
+		}
+	}
 
 	n.pexReactor.Wait()
 	n.router.Wait()
 	n.isListening = false
+	n.rpcEnv.IsListening = false
 
 	if err := n.shutdownOps(); err != nil {
 		if strings.TrimSpace(err.Error()) != "" {
@@ -3,12 +3,17 @@ package node
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	dbm "github.com/tendermint/tm-db"
+	"golang.org/x/net/proxy"
 
 	abciclient "github.com/tendermint/tendermint/abci/client"
 	"github.com/tendermint/tendermint/config"
@@ -21,6 +26,8 @@ import (
 	"github.com/tendermint/tendermint/internal/mempool"
 	"github.com/tendermint/tendermint/internal/p2p"
 	"github.com/tendermint/tendermint/internal/p2p/conn"
+	"github.com/tendermint/tendermint/internal/p2p/mdns"
+	"github.com/tendermint/tendermint/internal/p2p/nat"
 	"github.com/tendermint/tendermint/internal/p2p/pex"
 	sm "github.com/tendermint/tendermint/internal/state"
 	"github.com/tendermint/tendermint/internal/state/indexer"
@@ -147,7 +154,8 @@ func createMempoolReactor(
 	memplMetrics *mempool.Metrics,
 	peerEvents p2p.PeerEventSubscriber,
 	chCreator p2p.ChannelCreator,
-) (service.Service, mempool.Mempool) {
+	eventBus *eventbus.EventBus,
+) (service.Service, *mempool.TxMempool) {
 	logger = logger.With("module", "mempool")
 
 	mp := mempool.NewTxMempool(
@@ -155,6 +163,7 @@ func createMempoolReactor(
 		cfg.Mempool,
 		appClient,
 		mempool.WithMetrics(memplMetrics),
+		mempool.WithEventBus(eventBus),
 		mempool.WithPreCheck(sm.TxPreCheckFromStore(store)),
 		mempool.WithPostCheck(sm.TxPostCheckFromStore(store)),
 	)
@@ -215,6 +224,16 @@ func createPeerManager(
 		privatePeerIDs[types.NodeID(id)] = struct{}{}
 	}
 
+	unconditionalPeerIDs := make(map[types.NodeID]struct{})
+	for _, id := range tmstrings.SplitAndTrimEmpty(cfg.P2P.UnconditionalPeerIDs, ",", " ") {
+		unconditionalPeerIDs[types.NodeID(id)] = struct{}{}
+	}
+
+	allowedPeerIDs := make(map[types.NodeID]struct{})
+	for _, id := range tmstrings.SplitAndTrimEmpty(cfg.P2P.AllowedPeerIDs, ",", " ") {
+		allowedPeerIDs[types.NodeID(id)] = struct{}{}
+	}
+
 	var maxConns uint16
 
 	switch {
@@ -238,17 +257,32 @@ func createPeerManager(
 		SelfAddress:              selfAddr,
 		MaxConnected:             maxConns,
 		MaxOutgoingConnections:   maxOutgoingConns,
+		MaxIncomingConnections:   cfg.P2P.MaxIncomingConnections,
+		EvictIncoming:            cfg.P2P.EvictIncoming,
+		MaxPeersPerSubnet:        cfg.P2P.MaxPeersPerSubnet,
+		SubnetPrefixIPv4:         cfg.P2P.SubnetPrefixIPv4,
+		SubnetPrefixIPv6:         cfg.P2P.SubnetPrefixIPv6,
 		MaxConnectedUpgrade:      maxUpgradeConns,
 		DisconnectCooldownPeriod: 2 * time.Second,
+		RemoteBanWindow:          10 * time.Second,
+		RemoteBanBackoff:         cfg.P2P.RemoteBanBackoff,
 		MaxPeers:                 maxUpgradeConns + 4*maxConns,
 		MinRetryTime:             250 * time.Millisecond,
 		MaxRetryTime:             30 * time.Minute,
 		MaxRetryTimePersistent:   5 * time.Minute,
+		NodeKeyRotationTimeout:   24 * time.Hour,
 		RetryTimeJitter:          5 * time.Second,
 		PrivatePeers:             privatePeerIDs,
+		UnconditionalPeers:       unconditionalPeerIDs,
+		AllowedPeers:             allowedPeerIDs,
 		Metrics:                  metrics,
 	}
 
+	options.IPFilter, err = createIPFilter(cfg)
+	if err != nil {
+		return nil, func() error { return nil }, err
+	}
+
 	peers := []p2p.NodeAddress{}
 	for _, p := range tmstrings.SplitAndTrimEmpty(cfg.P2P.PersistentPeers, ",", " ") {
 		address, err := p2p.ParseNodeAddress(p)
@@ -287,6 +321,19 @@ func createPeerManager(
 	return peerManager, peerDB.Close, nil
 }
 
+// bootstrapPeerIDs returns the node IDs of the configured bootstrap peers,
+// which are dialed right away by the PEX reactor when the node has no peers.
+// Invalid addresses are skipped, createPeerManager reports them.
+func bootstrapPeerIDs(cfg *config.Config) []types.NodeID {
+	var ids []types.NodeID
+	for _, p := range tmstrings.SplitAndTrimEmpty(cfg.P2P.BootstrapPeers, ",", " ") {
+		if address, err := p2p.ParseNodeAddress(p); err == nil {
+			ids = append(ids, address.NodeID)
+		}
+	}
+	return ids
+}
+
 func createRouter(
 	logger log.Logger,
 	p2pMetrics *p2p.Metrics,
@@ -304,11 +351,41 @@ func createRouter(
 	transportConf.SendRate = cfg.P2P.SendRate
 	transportConf.RecvRate = cfg.P2P.RecvRate
 	transportConf.MaxPacketMsgPayloadSize = cfg.P2P.MaxPacketMsgPayloadSize
+	transportConf.PingInterval = cfg.P2P.PingInterval
+	transportConf.PongTimeout = cfg.P2P.PongTimeout
+	transportConf.MaxMissedPongs = cfg.P2P.MaxMissedPongs
+	transportOpts := p2p.MConnTransportOptions{
+		MaxAcceptedConnections: uint32(cfg.P2P.MaxConnections),
+	}
+	if cfg.P2P.Proxy != "" {
+		dialer, err := newProxyDialer(cfg.P2P.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		transportOpts.Dialer = dialer
+	}
+	if cfg.P2P.WebSocketListenAddress != "" {
+		transportOpts.WebSocketListenAddress = cfg.P2P.WebSocketListenAddress
+		if cfg.P2P.WebSocketTLSCertFile != "" {
+			cert, err := tls.LoadX509KeyPair(cfg.P2P.WebSocketCertFile(), cfg.P2P.WebSocketKeyFile())
+			if err != nil {
+				return nil, fmt.Errorf("failed to load WebSocket TLS certificate: %w", err)
+			}
+			transportOpts.WebSocketTLSConfig = &tls.Config{
+				Certificates: []tls.Certificate{cert},
+				MinVersion:   tls.VersionTLS12,
+			}
+		}
+	}
+	if cfg.P2P.Handshake == "noise" {
+		transportOpts.HandshakeProtocols = []conn.HandshakeProtocol{conn.HandshakeNoise}
+		if cfg.P2P.HandshakeFallback {
+			transportOpts.HandshakeProtocols = append(transportOpts.HandshakeProtocols,
+				conn.HandshakeSecretConnection)
+		}
+	}
 	transport := p2p.NewMConnTransport(
-		p2pLogger, transportConf, []*p2p.ChannelDescriptor{},
-		p2p.MConnTransportOptions{
-			MaxAcceptedConnections: uint32(cfg.P2P.MaxConnections),
-		},
+		p2pLogger, transportConf, []*p2p.ChannelDescriptor{}, transportOpts,
 	)
 
 	ep, err := p2p.NewEndpoint(nodeKey.ID.AddressString(cfg.P2P.ListenAddress))
@@ -316,6 +393,12 @@ func createRouter(
 		return nil, err
 	}
 
+	routerOpts := getRouterConfig(cfg, appClient)
+	routerOpts.IPFilter, err = createIPFilter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return p2p.NewRouter(
 		p2pLogger,
 		p2pMetrics,
@@ -324,10 +407,90 @@ func createRouter(
 		nodeInfoProducer,
 		transport,
 		ep,
-		getRouterConfig(cfg, appClient),
+		routerOpts,
+	)
+}
+
+// createIPFilter creates a peer IP filter from the configured CIDR ranges.
+func createIPFilter(cfg *config.Config) (*p2p.IPFilter, error) {
+	return p2p.NewIPFilter(
+		tmstrings.SplitAndTrimEmpty(cfg.P2P.AllowedCIDRs, ",", " "),
+		tmstrings.SplitAndTrimEmpty(cfg.P2P.DeniedCIDRs, ",", " "),
 	)
 }
 
+// newProxyDialer creates a dialer that connects through the SOCKS5 proxy at
+// the given URL.
+func newProxyDialer(proxyURL string) (p2p.ContextDialer, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	dialer, err := proxy.FromURL(u, proxy.Direct)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create proxy dialer: %w", err)
+	}
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("proxy dialer for %q does not support contexts", u.Scheme)
+	}
+	return contextDialer, nil
+}
+
+// mapNATPort discovers a UPnP or NAT-PMP gateway and maps the p2p listen port
+// on it until the context is done, returning the external address to
+// advertise.
+func mapNATPort(ctx context.Context, logger log.Logger, cfg *config.Config, nodeID types.NodeID) (string, error) {
+	ep, err := p2p.NewEndpoint(nodeID.AddressString(cfg.P2P.ListenAddress))
+	if err != nil {
+		return "", err
+	}
+
+	dctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	gateway, err := nat.Discover(dctx)
+	if err != nil {
+		return "", err
+	}
+	externalIP, err := gateway.ExternalIP(dctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get external IP from %v: %w", gateway, err)
+	}
+	if err := nat.Map(ctx, logger, gateway, "tcp", ep.Port, ep.Port, "tendermint p2p"); err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(externalIP.String(), strconv.Itoa(int(ep.Port))), nil
+}
+
+// setSelfAddress makes the peer manager advertise addr to peers, for them to
+// dial back to us.
+func setSelfAddress(peerManager *p2p.PeerManager, nodeID types.NodeID, addr string) error {
+	selfAddr, err := p2p.ParseNodeAddress(nodeID.AddressString(addr))
+	if err != nil {
+		return fmt.Errorf("couldn't parse address %q: %w", addr, err)
+	}
+	peerManager.SetSelfAddress(selfAddr)
+	return nil
+}
+
+// startMDNS advertises the node on the local network via mDNS, and adds the
+// nodes it discovers to the peer manager until the context is done.
+func startMDNS(
+	ctx context.Context,
+	logger log.Logger,
+	cfg *config.Config,
+	nodeID types.NodeID,
+	network string,
+	peerManager *p2p.PeerManager,
+) error {
+	ep, err := p2p.NewEndpoint(nodeID.AddressString(cfg.P2P.ListenAddress))
+	if err != nil {
+		return err
+	}
+	return mdns.NewService(logger, nodeID, network, *ep, peerManager).Start(ctx)
+}
+
 func makeNodeInfo(
 	cfg *config.Config,
 	nodeKey types.NodeKey,
@@ -375,11 +538,20 @@ func makeNodeInfo(
 		nodeInfo.Channels = append(nodeInfo.Channels, pex.PexChannel)
 	}
 
+	if cfg.P2P.Compression != "none" {
+		nodeInfo.Compression = []string{cfg.P2P.Compression}
+	}
+
 	nodeInfo.ListenAddr = cfg.P2P.ExternalAddress
 	if nodeInfo.ListenAddr == "" {
 		nodeInfo.ListenAddr = cfg.P2P.ListenAddress
 	}
 
+	var err error
+	if nodeInfo.NextNodeKey, err = loadNodeKeyRotation(cfg, nodeKey); err != nil {
+		return nodeInfo, err
+	}
+
 	return nodeInfo, nodeInfo.Validate()
 }
 
@@ -408,14 +580,40 @@ func makeSeedNodeInfo(
 		},
 	}
 
+	if cfg.P2P.Compression != "none" {
+		nodeInfo.Compression = []string{cfg.P2P.Compression}
+	}
+
 	nodeInfo.ListenAddr = cfg.P2P.ExternalAddress
 	if nodeInfo.ListenAddr == "" {
 		nodeInfo.ListenAddr = cfg.P2P.ListenAddress
 	}
 
+	var err error
+	if nodeInfo.NextNodeKey, err = loadNodeKeyRotation(cfg, nodeKey); err != nil {
+		return nodeInfo, err
+	}
+
 	return nodeInfo, nodeInfo.Validate()
 }
 
+// loadNodeKeyRotation signs the announcement of the next node key, if one is
+// configured.
+func loadNodeKeyRotation(cfg *config.Config, nodeKey types.NodeKey) (*types.NodeKeyRotation, error) {
+	if cfg.NextNodeKey == "" {
+		return nil, nil
+	}
+	nextKey, err := types.LoadNodeKey(cfg.NextNodeKeyFile())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load next node key: %w", err)
+	}
+	rotation, err := nodeKey.SignRotation(nextKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign node key rotation: %w", err)
+	}
+	return &rotation, nil
+}
+
 func createAndStartPrivValidatorSocketClient(
 	ctx context.Context,
 	listenAddr, chainID string,
@@ -515,3 +713,13 @@ func createPrivval(ctx context.Context, logger log.Logger, conf *config.Config,
 
 	return defaultPV, nil
 }
+
+// gatePrivval wraps privValidator to consult the configured sign gate, if any,
+// before each signature.
+func gatePrivval(conf *config.Config, privValidator types.PrivValidator) types.PrivValidator {
+	if conf.PrivValidator.SignGateURL == "" {
+		return privValidator
+	}
+	gate := privval.NewHTTPSignGate(conf.PrivValidator.SignGateURL, conf.PrivValidator.SignGateTimeout)
+	return privval.NewSignGatedPrivValidator(privValidator, gate)
+}
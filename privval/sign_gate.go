@@ -0,0 +1,127 @@
+package privval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// SignGate is consulted before a vote or proposal signature is produced. It
+// lets high-availability validator setups, where several nodes share a
+// validator key, enforce a cluster-wide high-watermark of signed
+// height/round/steps beyond the last sign state of a single node.
+type SignGate interface {
+	// Allow returns nil if the signature described by req may be produced.
+	Allow(ctx context.Context, req SignGateRequest) error
+}
+
+// SignGateRequest describes a signature about to be produced. The block ID
+// hash lets the gate allow signing the same block again at the same
+// height/round/step, as the local sign state does.
+type SignGateRequest struct {
+	ChainID     string                `json:"chain_id"`
+	Height      int64                 `json:"height,string"`
+	Round       int32                 `json:"round"`
+	Type        tmproto.SignedMsgType `json:"type"`
+	BlockIDHash tmbytes.HexBytes      `json:"block_id_hash"`
+}
+
+// HTTPSignGate is a SignGate backed by an HTTP service. Each request is POSTed
+// as JSON, and the signature is allowed if the service responds with 200 OK.
+type HTTPSignGate struct {
+	url    string
+	client *http.Client
+}
+
+var _ SignGate = (*HTTPSignGate)(nil)
+
+// NewHTTPSignGate returns a sign gate that POSTs requests to url, failing them
+// after timeout.
+func NewHTTPSignGate(url string, timeout time.Duration) *HTTPSignGate {
+	return &HTTPSignGate{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Allow implements SignGate.
+func (g *HTTPSignGate) Allow(ctx context.Context, req SignGateRequest) error {
+	bz, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, g.url, bytes.NewReader(bz))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("sign gate request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("sign gate refused signature: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// SignGatedPrivValidator wraps a PrivValidator, consulting a SignGate before
+// each signature. Signatures refused by the gate, or for which the gate can't
+// be reached, are not produced.
+type SignGatedPrivValidator struct {
+	next types.PrivValidator
+	gate SignGate
+}
+
+var _ types.PrivValidator = (*SignGatedPrivValidator)(nil)
+
+// NewSignGatedPrivValidator returns pv gated by gate.
+func NewSignGatedPrivValidator(pv types.PrivValidator, gate SignGate) *SignGatedPrivValidator {
+	return &SignGatedPrivValidator{next: pv, gate: gate}
+}
+
+// GetPubKey implements PrivValidator.
+func (pv *SignGatedPrivValidator) GetPubKey(ctx context.Context) (crypto.PubKey, error) {
+	return pv.next.GetPubKey(ctx)
+}
+
+// SignVote implements PrivValidator.
+func (pv *SignGatedPrivValidator) SignVote(ctx context.Context, chainID string, vote *tmproto.Vote) error {
+	if err := pv.gate.Allow(ctx, SignGateRequest{
+		ChainID:     chainID,
+		Height:      vote.Height,
+		Round:       vote.Round,
+		Type:        vote.Type,
+		BlockIDHash: vote.BlockID.Hash,
+	}); err != nil {
+		return err
+	}
+	return pv.next.SignVote(ctx, chainID, vote)
+}
+
+// SignProposal implements PrivValidator.
+func (pv *SignGatedPrivValidator) SignProposal(ctx context.Context, chainID string, proposal *tmproto.Proposal) error {
+	if err := pv.gate.Allow(ctx, SignGateRequest{
+		ChainID:     chainID,
+		Height:      proposal.Height,
+		Round:       proposal.Round,
+		Type:        proposal.Type,
+		BlockIDHash: proposal.BlockID.Hash,
+	}); err != nil {
+		return err
+	}
+	return pv.next.SignProposal(ctx, chainID, proposal)
+}
@@ -0,0 +1,64 @@
+package privval
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestSignGatedPrivValidator(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The gate only allows signing at increasing heights.
+	var (
+		mtx       sync.Mutex
+		watermark int64
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SignGateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mtx.Lock()
+		defer mtx.Unlock()
+		if req.ChainID != "test-chain" || req.Height <= watermark {
+			http.Error(w, "height already signed", http.StatusConflict)
+			return
+		}
+		watermark = req.Height
+	}))
+	defer srv.Close()
+
+	pv := NewSignGatedPrivValidator(types.NewMockPV(), NewHTTPSignGate(srv.URL, time.Second))
+
+	vote := &tmproto.Vote{Type: tmproto.PrevoteType, Height: 1}
+	require.NoError(t, pv.SignVote(ctx, "test-chain", vote))
+	assert.NotEmpty(t, vote.Signature)
+
+	vote = &tmproto.Vote{Type: tmproto.PrecommitType, Height: 1}
+	err := pv.SignVote(ctx, "test-chain", vote)
+	assert.ErrorContains(t, err, "height already signed")
+	assert.Empty(t, vote.Signature)
+
+	proposal := &tmproto.Proposal{Type: tmproto.ProposalType, Height: 2}
+	require.NoError(t, pv.SignProposal(ctx, "test-chain", proposal))
+	assert.NotEmpty(t, proposal.Signature)
+
+	// An unreachable gate refuses signatures.
+	srv.Close()
+	proposal = &tmproto.Proposal{Type: tmproto.ProposalType, Height: 3}
+	assert.Error(t, pv.SignProposal(ctx, "test-chain", proposal))
+	assert.Empty(t, proposal.Signature)
+}
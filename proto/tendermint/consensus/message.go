@@ -36,6 +36,15 @@ func (m *Message) Wrap(pb proto.Message) error {
 	case *VoteSetBits:
 		m.Sum = &Message_VoteSetBits{VoteSetBits: msg}
 
+	case *BlockParityPart:
+		m.Sum = &Message_BlockParityPart{BlockParityPart: msg}
+
+	case *VoteRequest:
+		m.Sum = &Message_VoteRequest{VoteRequest: msg}
+
+	case *MessageBatch:
+		m.Sum = &Message_MessageBatch{MessageBatch: msg}
+
 	default:
 		return fmt.Errorf("unknown message: %T", msg)
 	}
@@ -74,6 +83,15 @@ func (m *Message) Unwrap() (proto.Message, error) {
 	case *Message_VoteSetBits:
 		return m.GetVoteSetBits(), nil
 
+	case *Message_BlockParityPart:
+		return m.GetBlockParityPart(), nil
+
+	case *Message_VoteRequest:
+		return m.GetVoteRequest(), nil
+
+	case *Message_MessageBatch:
+		return m.GetMessageBatch(), nil
+
 	default:
 		return nil, fmt.Errorf("unknown message: %T", msg)
 	}
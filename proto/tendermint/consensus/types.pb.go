@@ -351,6 +351,84 @@ func (m *BlockPart) GetPart() types.Part {
 	return types.Part{}
 }
 
+// BlockParityPart is sent when gossipping a Reed-Solomon parity part of the
+// proposed block to peers that support it.
+type BlockParityPart struct {
+	Height       int64  `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Round        int32  `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	Index        uint32 `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	LastPartSize uint32 `protobuf:"varint,4,opt,name=last_part_size,json=lastPartSize,proto3" json:"last_part_size,omitempty"`
+	Bytes        []byte `protobuf:"bytes,5,opt,name=bytes,proto3" json:"bytes,omitempty"`
+}
+
+func (m *BlockParityPart) Reset()         { *m = BlockParityPart{} }
+func (m *BlockParityPart) String() string { return proto.CompactTextString(m) }
+func (*BlockParityPart) ProtoMessage()    {}
+func (*BlockParityPart) Descriptor() ([]byte, []int) {
+	return fileDescriptor_81a22d2efc008981, []int{5}
+}
+func (m *BlockParityPart) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BlockParityPart) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BlockParityPart.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *BlockParityPart) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockParityPart.Merge(m, src)
+}
+func (m *BlockParityPart) XXX_Size() int {
+	return m.Size()
+}
+func (m *BlockParityPart) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockParityPart.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockParityPart proto.InternalMessageInfo
+
+func (m *BlockParityPart) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *BlockParityPart) GetRound() int32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *BlockParityPart) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *BlockParityPart) GetLastPartSize() uint32 {
+	if m != nil {
+		return m.LastPartSize
+	}
+	return 0
+}
+
+func (m *BlockParityPart) GetBytes() []byte {
+	if m != nil {
+		return m.Bytes
+	}
+	return nil
+}
+
 // Vote is sent when voting for a proposal (or lack thereof).
 type Vote struct {
 	Vote *types.Vote `protobuf:"bytes,1,opt,name=vote,proto3" json:"vote,omitempty"`
@@ -360,7 +438,7 @@ func (m *Vote) Reset()         { *m = Vote{} }
 func (m *Vote) String() string { return proto.CompactTextString(m) }
 func (*Vote) ProtoMessage()    {}
 func (*Vote) Descriptor() ([]byte, []int) {
-	return fileDescriptor_81a22d2efc008981, []int{5}
+	return fileDescriptor_81a22d2efc008981, []int{6}
 }
 func (m *Vote) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -408,7 +486,7 @@ func (m *HasVote) Reset()         { *m = HasVote{} }
 func (m *HasVote) String() string { return proto.CompactTextString(m) }
 func (*HasVote) ProtoMessage()    {}
 func (*HasVote) Descriptor() ([]byte, []int) {
-	return fileDescriptor_81a22d2efc008981, []int{6}
+	return fileDescriptor_81a22d2efc008981, []int{7}
 }
 func (m *HasVote) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -477,7 +555,7 @@ func (m *VoteSetMaj23) Reset()         { *m = VoteSetMaj23{} }
 func (m *VoteSetMaj23) String() string { return proto.CompactTextString(m) }
 func (*VoteSetMaj23) ProtoMessage()    {}
 func (*VoteSetMaj23) Descriptor() ([]byte, []int) {
-	return fileDescriptor_81a22d2efc008981, []int{7}
+	return fileDescriptor_81a22d2efc008981, []int{8}
 }
 func (m *VoteSetMaj23) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -548,7 +626,7 @@ func (m *VoteSetBits) Reset()         { *m = VoteSetBits{} }
 func (m *VoteSetBits) String() string { return proto.CompactTextString(m) }
 func (*VoteSetBits) ProtoMessage()    {}
 func (*VoteSetBits) Descriptor() ([]byte, []int) {
-	return fileDescriptor_81a22d2efc008981, []int{8}
+	return fileDescriptor_81a22d2efc008981, []int{9}
 }
 func (m *VoteSetBits) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -612,6 +690,75 @@ func (m *VoteSetBits) GetVotes() bits.BitArray {
 	return bits.BitArray{}
 }
 
+// VoteRequest is sent to request the votes of a vote set that we are missing.
+type VoteRequest struct {
+	Height int64               `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Round  int32               `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	Type   types.SignedMsgType `protobuf:"varint,3,opt,name=type,proto3,enum=tendermint.types.SignedMsgType" json:"type,omitempty"`
+	Votes  bits.BitArray       `protobuf:"bytes,4,opt,name=votes,proto3" json:"votes"`
+}
+
+func (m *VoteRequest) Reset()         { *m = VoteRequest{} }
+func (m *VoteRequest) String() string { return proto.CompactTextString(m) }
+func (*VoteRequest) ProtoMessage()    {}
+func (*VoteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_81a22d2efc008981, []int{10}
+}
+func (m *VoteRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *VoteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_VoteRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *VoteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VoteRequest.Merge(m, src)
+}
+func (m *VoteRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *VoteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VoteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VoteRequest proto.InternalMessageInfo
+
+func (m *VoteRequest) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *VoteRequest) GetRound() int32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *VoteRequest) GetType() types.SignedMsgType {
+	if m != nil {
+		return m.Type
+	}
+	return types.UnknownType
+}
+
+func (m *VoteRequest) GetVotes() bits.BitArray {
+	if m != nil {
+		return m.Votes
+	}
+	return bits.BitArray{}
+}
+
 type Message struct {
 	// Types that are valid to be assigned to Sum:
 	//	*Message_NewRoundStep
@@ -623,6 +770,9 @@ type Message struct {
 	//	*Message_HasVote
 	//	*Message_VoteSetMaj23
 	//	*Message_VoteSetBits
+	//	*Message_BlockParityPart
+	//	*Message_VoteRequest
+	//	*Message_MessageBatch
 	Sum isMessage_Sum `protobuf_oneof:"sum"`
 }
 
@@ -630,7 +780,7 @@ func (m *Message) Reset()         { *m = Message{} }
 func (m *Message) String() string { return proto.CompactTextString(m) }
 func (*Message) ProtoMessage()    {}
 func (*Message) Descriptor() ([]byte, []int) {
-	return fileDescriptor_81a22d2efc008981, []int{9}
+	return fileDescriptor_81a22d2efc008981, []int{11}
 }
 func (m *Message) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -692,16 +842,28 @@ type Message_VoteSetMaj23 struct {
 type Message_VoteSetBits struct {
 	VoteSetBits *VoteSetBits `protobuf:"bytes,9,opt,name=vote_set_bits,json=voteSetBits,proto3,oneof" json:"vote_set_bits,omitempty"`
 }
+type Message_BlockParityPart struct {
+	BlockParityPart *BlockParityPart `protobuf:"bytes,10,opt,name=block_parity_part,json=blockParityPart,proto3,oneof" json:"block_parity_part,omitempty"`
+}
+type Message_VoteRequest struct {
+	VoteRequest *VoteRequest `protobuf:"bytes,11,opt,name=vote_request,json=voteRequest,proto3,oneof" json:"vote_request,omitempty"`
+}
+type Message_MessageBatch struct {
+	MessageBatch *MessageBatch `protobuf:"bytes,12,opt,name=message_batch,json=messageBatch,proto3,oneof" json:"message_batch,omitempty"`
+}
 
-func (*Message_NewRoundStep) isMessage_Sum()  {}
-func (*Message_NewValidBlock) isMessage_Sum() {}
-func (*Message_Proposal) isMessage_Sum()      {}
-func (*Message_ProposalPol) isMessage_Sum()   {}
-func (*Message_BlockPart) isMessage_Sum()     {}
-func (*Message_Vote) isMessage_Sum()          {}
-func (*Message_HasVote) isMessage_Sum()       {}
-func (*Message_VoteSetMaj23) isMessage_Sum()  {}
-func (*Message_VoteSetBits) isMessage_Sum()   {}
+func (*Message_NewRoundStep) isMessage_Sum()    {}
+func (*Message_NewValidBlock) isMessage_Sum()   {}
+func (*Message_Proposal) isMessage_Sum()        {}
+func (*Message_ProposalPol) isMessage_Sum()     {}
+func (*Message_BlockPart) isMessage_Sum()       {}
+func (*Message_Vote) isMessage_Sum()            {}
+func (*Message_HasVote) isMessage_Sum()         {}
+func (*Message_VoteSetMaj23) isMessage_Sum()    {}
+func (*Message_VoteSetBits) isMessage_Sum()     {}
+func (*Message_BlockParityPart) isMessage_Sum() {}
+func (*Message_VoteRequest) isMessage_Sum()     {}
+func (*Message_MessageBatch) isMessage_Sum()    {}
 
 func (m *Message) GetSum() isMessage_Sum {
 	if m != nil {
@@ -773,6 +935,27 @@ func (m *Message) GetVoteSetBits() *VoteSetBits {
 	return nil
 }
 
+func (m *Message) GetBlockParityPart() *BlockParityPart {
+	if x, ok := m.GetSum().(*Message_BlockParityPart); ok {
+		return x.BlockParityPart
+	}
+	return nil
+}
+
+func (m *Message) GetVoteRequest() *VoteRequest {
+	if x, ok := m.GetSum().(*Message_VoteRequest); ok {
+		return x.VoteRequest
+	}
+	return nil
+}
+
+func (m *Message) GetMessageBatch() *MessageBatch {
+	if x, ok := m.GetSum().(*Message_MessageBatch); ok {
+		return x.MessageBatch
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Message) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -785,7 +968,55 @@ func (*Message) XXX_OneofWrappers() []interface{} {
 		(*Message_HasVote)(nil),
 		(*Message_VoteSetMaj23)(nil),
 		(*Message_VoteSetBits)(nil),
+		(*Message_BlockParityPart)(nil),
+		(*Message_VoteRequest)(nil),
+		(*Message_MessageBatch)(nil),
+	}
+}
+
+// MessageBatch coalesces several messages sent to a peer into a single one.
+type MessageBatch struct {
+	Messages []Message `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages"`
+}
+
+func (m *MessageBatch) Reset()         { *m = MessageBatch{} }
+func (m *MessageBatch) String() string { return proto.CompactTextString(m) }
+func (*MessageBatch) ProtoMessage()    {}
+func (*MessageBatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_81a22d2efc008981, []int{12}
+}
+func (m *MessageBatch) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MessageBatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MessageBatch.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MessageBatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageBatch.Merge(m, src)
+}
+func (m *MessageBatch) XXX_Size() int {
+	return m.Size()
+}
+func (m *MessageBatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageBatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MessageBatch proto.InternalMessageInfo
+
+func (m *MessageBatch) GetMessages() []Message {
+	if m != nil {
+		return m.Messages
 	}
+	return nil
 }
 
 func init() {
@@ -794,71 +1025,83 @@ func init() {
 	proto.RegisterType((*Proposal)(nil), "tendermint.consensus.Proposal")
 	proto.RegisterType((*ProposalPOL)(nil), "tendermint.consensus.ProposalPOL")
 	proto.RegisterType((*BlockPart)(nil), "tendermint.consensus.BlockPart")
+	proto.RegisterType((*BlockParityPart)(nil), "tendermint.consensus.BlockParityPart")
 	proto.RegisterType((*Vote)(nil), "tendermint.consensus.Vote")
 	proto.RegisterType((*HasVote)(nil), "tendermint.consensus.HasVote")
 	proto.RegisterType((*VoteSetMaj23)(nil), "tendermint.consensus.VoteSetMaj23")
 	proto.RegisterType((*VoteSetBits)(nil), "tendermint.consensus.VoteSetBits")
+	proto.RegisterType((*VoteRequest)(nil), "tendermint.consensus.VoteRequest")
 	proto.RegisterType((*Message)(nil), "tendermint.consensus.Message")
+	proto.RegisterType((*MessageBatch)(nil), "tendermint.consensus.MessageBatch")
 }
 
 func init() { proto.RegisterFile("tendermint/consensus/types.proto", fileDescriptor_81a22d2efc008981) }
 
 var fileDescriptor_81a22d2efc008981 = []byte{
-	// 853 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x56, 0x4f, 0x8f, 0xdb, 0x44,
-	0x14, 0xb7, 0x59, 0x67, 0x93, 0x7d, 0xde, 0xec, 0xc2, 0x68, 0x5b, 0x85, 0x00, 0x49, 0x30, 0x97,
-	0x15, 0x42, 0x0e, 0xca, 0x1e, 0x90, 0x0a, 0x12, 0x60, 0xfe, 0xd4, 0xad, 0x9a, 0x36, 0x72, 0x4a,
-	0x85, 0xb8, 0x58, 0x4e, 0x3c, 0x4a, 0x86, 0xc6, 0x1e, 0xcb, 0x33, 0xc9, 0xb2, 0x57, 0x3e, 0x01,
-	0x1f, 0x80, 0xaf, 0x81, 0xc4, 0x47, 0xe8, 0xb1, 0x47, 0x4e, 0x15, 0xca, 0x7e, 0x04, 0x04, 0x67,
-	0x34, 0xe3, 0x49, 0x3c, 0xa1, 0xde, 0x85, 0xbd, 0x20, 0xf5, 0x36, 0xe3, 0xf7, 0xde, 0x6f, 0xde,
-	0xfc, 0xde, 0x7b, 0x3f, 0x0f, 0xf4, 0x38, 0x4e, 0x63, 0x9c, 0x27, 0x24, 0xe5, 0xfd, 0x29, 0x4d,
-	0x19, 0x4e, 0xd9, 0x92, 0xf5, 0xf9, 0x45, 0x86, 0x99, 0x9b, 0xe5, 0x94, 0x53, 0x74, 0x52, 0x7a,
-	0xb8, 0x5b, 0x8f, 0xf6, 0xc9, 0x8c, 0xce, 0xa8, 0x74, 0xe8, 0x8b, 0x55, 0xe1, 0xdb, 0x7e, 0x5b,
-	0x43, 0x93, 0x18, 0x3a, 0x52, 0x5b, 0x3f, 0x6b, 0x41, 0x26, 0xac, 0x3f, 0x21, 0x7c, 0xc7, 0xc3,
-	0xf9, 0xc5, 0x84, 0xc3, 0x87, 0xf8, 0x3c, 0xa0, 0xcb, 0x34, 0x1e, 0x73, 0x9c, 0xa1, 0xdb, 0xb0,
-	0x3f, 0xc7, 0x64, 0x36, 0xe7, 0x2d, 0xb3, 0x67, 0x9e, 0xee, 0x05, 0x6a, 0x87, 0x4e, 0xa0, 0x96,
-	0x0b, 0xa7, 0xd6, 0x6b, 0x3d, 0xf3, 0xb4, 0x16, 0x14, 0x1b, 0x84, 0xc0, 0x62, 0x1c, 0x67, 0xad,
-	0xbd, 0x9e, 0x79, 0xda, 0x0c, 0xe4, 0x1a, 0x7d, 0x04, 0x2d, 0x86, 0xa7, 0x34, 0x8d, 0x59, 0xc8,
-	0x48, 0x3a, 0xc5, 0x21, 0xe3, 0x51, 0xce, 0x43, 0x4e, 0x12, 0xdc, 0xb2, 0x24, 0xe6, 0x2d, 0x65,
-	0x1f, 0x0b, 0xf3, 0x58, 0x58, 0x1f, 0x93, 0x04, 0xa3, 0xf7, 0xe1, 0x8d, 0x45, 0xc4, 0x78, 0x38,
-	0xa5, 0x49, 0x42, 0x78, 0x58, 0x1c, 0x57, 0x93, 0xc7, 0x1d, 0x0b, 0xc3, 0x17, 0xf2, 0xbb, 0x4c,
-	0xd5, 0xf9, 0xd3, 0x84, 0xe6, 0x43, 0x7c, 0xfe, 0x24, 0x5a, 0x90, 0xd8, 0x5b, 0xd0, 0xe9, 0xd3,
-	0x1b, 0x26, 0xfe, 0x2d, 0xdc, 0x9a, 0x88, 0xb0, 0x30, 0x13, 0xb9, 0x31, 0xcc, 0xc3, 0x39, 0x8e,
-	0x62, 0x9c, 0xcb, 0x9b, 0xd8, 0x83, 0xae, 0xab, 0xd5, 0xa0, 0xe0, 0x6b, 0x14, 0xe5, 0x7c, 0x8c,
-	0xb9, 0x2f, 0xdd, 0x3c, 0xeb, 0xd9, 0x8b, 0xae, 0x11, 0x20, 0x89, 0xb1, 0x63, 0x41, 0x9f, 0x82,
-	0x5d, 0x22, 0x33, 0x79, 0x63, 0x7b, 0xd0, 0xd1, 0xf1, 0x44, 0x25, 0x5c, 0x51, 0x09, 0xd7, 0x23,
-	0xfc, 0xf3, 0x3c, 0x8f, 0x2e, 0x02, 0xd8, 0x02, 0x31, 0xf4, 0x16, 0x1c, 0x10, 0xa6, 0x48, 0x90,
-	0xd7, 0x6f, 0x04, 0x0d, 0xc2, 0x8a, 0xcb, 0x3b, 0x3e, 0x34, 0x46, 0x39, 0xcd, 0x28, 0x8b, 0x16,
-	0xe8, 0x13, 0x68, 0x64, 0x6a, 0x2d, 0xef, 0x6c, 0x0f, 0xda, 0x15, 0x69, 0x2b, 0x0f, 0x95, 0xf1,
-	0x36, 0xc2, 0xf9, 0xd9, 0x04, 0x7b, 0x63, 0x1c, 0x3d, 0x7a, 0x70, 0x25, 0x7f, 0x1f, 0x00, 0xda,
-	0xc4, 0x84, 0x19, 0x5d, 0x84, 0x3a, 0x99, 0xaf, 0x6f, 0x2c, 0x23, 0xba, 0x90, 0x75, 0x41, 0x77,
-	0xe1, 0x50, 0xf7, 0x56, 0x74, 0xfe, 0xcb, 0xf5, 0x55, 0x6e, 0xb6, 0x86, 0xe6, 0x3c, 0x85, 0x03,
-	0x6f, 0xc3, 0xc9, 0x0d, 0x6b, 0xfb, 0x21, 0x58, 0x82, 0x7b, 0x75, 0xf6, 0xed, 0xea, 0x52, 0xaa,
-	0x33, 0xa5, 0xa7, 0x33, 0x00, 0xeb, 0x09, 0xe5, 0xa2, 0x03, 0xad, 0x15, 0xe5, 0x58, 0xb1, 0x59,
-	0x11, 0x29, 0xbc, 0x02, 0xe9, 0xe3, 0xfc, 0x68, 0x42, 0xdd, 0x8f, 0x98, 0x8c, 0xbb, 0x59, 0x7e,
-	0x67, 0x60, 0x09, 0x34, 0x99, 0xdf, 0x51, 0x55, 0xab, 0x8d, 0xc9, 0x2c, 0xc5, 0xf1, 0x90, 0xcd,
-	0x1e, 0x5f, 0x64, 0x38, 0x90, 0xce, 0x02, 0x8a, 0xa4, 0x31, 0xfe, 0x41, 0x36, 0x54, 0x2d, 0x28,
-	0x36, 0xce, 0xaf, 0x26, 0x1c, 0x8a, 0x0c, 0xc6, 0x98, 0x0f, 0xa3, 0xef, 0x07, 0x67, 0xff, 0x47,
-	0x26, 0x5f, 0x41, 0xa3, 0x68, 0x70, 0x12, 0xab, 0xee, 0x7e, 0xf3, 0xe5, 0x40, 0x59, 0xbb, 0x7b,
-	0x5f, 0x7a, 0xc7, 0x82, 0xe5, 0xf5, 0x8b, 0x6e, 0x5d, 0x7d, 0x08, 0xea, 0x32, 0xf6, 0x5e, 0xec,
-	0xfc, 0x61, 0x82, 0xad, 0x52, 0xf7, 0x08, 0x67, 0xaf, 0x4e, 0xe6, 0xe8, 0x0e, 0xd4, 0x44, 0x07,
-	0x30, 0x39, 0x9c, 0xff, 0xb5, 0xb9, 0x8b, 0x10, 0xe7, 0x2f, 0x0b, 0xea, 0x43, 0xcc, 0x58, 0x34,
-	0xc3, 0xe8, 0x3e, 0x1c, 0xa5, 0xf8, 0xbc, 0x18, 0xa8, 0x50, 0xca, 0x68, 0xd1, 0x77, 0x8e, 0x5b,
-	0xf5, 0x03, 0x70, 0x75, 0x99, 0xf6, 0x8d, 0xe0, 0x30, 0xd5, 0x65, 0x7b, 0x08, 0xc7, 0x02, 0x6b,
-	0x25, 0xf4, 0x30, 0x94, 0x89, 0x4a, 0xbe, 0xec, 0xc1, 0x7b, 0x57, 0x82, 0x95, 0xda, 0xe9, 0x1b,
-	0x41, 0x33, 0xdd, 0x11, 0x53, 0x5d, 0x5a, 0x2a, 0x46, 0xb8, 0xc4, 0xd9, 0x28, 0x88, 0xaf, 0x49,
-	0x0b, 0xfa, 0xfa, 0x1f, 0x22, 0x50, 0x70, 0xfd, 0xee, 0xf5, 0x08, 0xa3, 0x47, 0x0f, 0xfc, 0x5d,
-	0x0d, 0x40, 0x9f, 0x01, 0x94, 0x52, 0xaa, 0xd8, 0xee, 0x56, 0xa3, 0x6c, 0xb5, 0xc2, 0x37, 0x82,
-	0x83, 0xad, 0x98, 0x0a, 0x29, 0x90, 0x03, 0xbd, 0xff, 0xb2, 0x3c, 0x96, 0xb1, 0xa2, 0x0b, 0x7d,
-	0xa3, 0x18, 0x6b, 0x74, 0x07, 0x1a, 0xf3, 0x88, 0x85, 0x32, 0xaa, 0x2e, 0xa3, 0xde, 0xa9, 0x8e,
-	0x52, 0xb3, 0xef, 0x1b, 0x41, 0x7d, 0xae, 0x64, 0xe0, 0x3e, 0x1c, 0x89, 0x38, 0xf9, 0x3b, 0x49,
-	0xc4, 0x38, 0xb6, 0x1a, 0xd7, 0x15, 0x54, 0x1f, 0x5c, 0x51, 0xd0, 0x95, 0x3e, 0xc8, 0x77, 0xa1,
-	0xb9, 0xc5, 0x12, 0xfd, 0xd4, 0x3a, 0xb8, 0x8e, 0x44, 0x6d, 0x90, 0x04, 0x89, 0xab, 0x72, 0xeb,
-	0xd5, 0x60, 0x8f, 0x2d, 0x13, 0xef, 0x9b, 0x67, 0xeb, 0x8e, 0xf9, 0x7c, 0xdd, 0x31, 0x7f, 0x5f,
-	0x77, 0xcc, 0x9f, 0x2e, 0x3b, 0xc6, 0xf3, 0xcb, 0x8e, 0xf1, 0xdb, 0x65, 0xc7, 0xf8, 0xee, 0xe3,
-	0x19, 0xe1, 0xf3, 0xe5, 0xc4, 0x9d, 0xd2, 0xa4, 0xaf, 0xbf, 0x26, 0xca, 0x65, 0xf1, 0xea, 0xa8,
-	0x7a, 0xb7, 0x4c, 0xf6, 0xa5, 0xed, 0xec, 0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0xc7, 0x5c, 0x91,
-	0x04, 0xd6, 0x08, 0x00, 0x00,
+	// 1001 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x56, 0xcd, 0x8e, 0x1b, 0x45,
+	0x10, 0x9e, 0x61, 0xed, 0xb5, 0xb7, 0x6c, 0xaf, 0x49, 0x6b, 0x13, 0x0d, 0x0b, 0x78, 0xcd, 0x00,
+	0xd2, 0x0a, 0x21, 0x1b, 0x79, 0x0f, 0x48, 0x01, 0x29, 0x60, 0x7e, 0x32, 0x8b, 0xb2, 0x59, 0xab,
+	0x1d, 0x22, 0xc4, 0x65, 0x34, 0xb6, 0x5b, 0x76, 0x13, 0xcf, 0x0f, 0xd3, 0x6d, 0x2f, 0xce, 0x91,
+	0x27, 0x80, 0x3b, 0x8f, 0xc0, 0x15, 0x89, 0x47, 0xc8, 0x31, 0x47, 0x4e, 0x2b, 0xb4, 0xfb, 0x08,
+	0x88, 0x3b, 0xea, 0x9f, 0xf1, 0xb4, 0x89, 0xd7, 0x64, 0x39, 0x44, 0xca, 0xad, 0xab, 0xbb, 0xea,
+	0xeb, 0xaf, 0xaa, 0xba, 0xaa, 0x1a, 0x9a, 0x9c, 0x44, 0x23, 0x92, 0x86, 0x34, 0xe2, 0xed, 0x61,
+	0x1c, 0x31, 0x12, 0xb1, 0x19, 0x6b, 0xf3, 0x45, 0x42, 0x58, 0x2b, 0x49, 0x63, 0x1e, 0xa3, 0xbd,
+	0x5c, 0xa3, 0xb5, 0xd4, 0xd8, 0xdf, 0x1b, 0xc7, 0xe3, 0x58, 0x2a, 0xb4, 0xc5, 0x4a, 0xe9, 0xee,
+	0xbf, 0x61, 0xa0, 0x49, 0x0c, 0x13, 0x69, 0xdf, 0xbc, 0x6b, 0x4a, 0x07, 0xac, 0x3d, 0xa0, 0x7c,
+	0x45, 0xc3, 0xfd, 0xcd, 0x86, 0xea, 0x7d, 0x72, 0x86, 0xe3, 0x59, 0x34, 0xea, 0x73, 0x92, 0xa0,
+	0x5b, 0xb0, 0x3d, 0x21, 0x74, 0x3c, 0xe1, 0x8e, 0xdd, 0xb4, 0x0f, 0xb7, 0xb0, 0x96, 0xd0, 0x1e,
+	0x14, 0x53, 0xa1, 0xe4, 0xbc, 0xd2, 0xb4, 0x0f, 0x8b, 0x58, 0x09, 0x08, 0x41, 0x81, 0x71, 0x92,
+	0x38, 0x5b, 0x4d, 0xfb, 0xb0, 0x86, 0xe5, 0x1a, 0x7d, 0x08, 0x0e, 0x23, 0xc3, 0x38, 0x1a, 0x31,
+	0x9f, 0xd1, 0x68, 0x48, 0x7c, 0xc6, 0x83, 0x94, 0xfb, 0x9c, 0x86, 0xc4, 0x29, 0x48, 0xcc, 0x9b,
+	0xfa, 0xbc, 0x2f, 0x8e, 0xfb, 0xe2, 0xf4, 0x01, 0x0d, 0x09, 0x7a, 0x0f, 0x6e, 0x4c, 0x03, 0xc6,
+	0xfd, 0x61, 0x1c, 0x86, 0x94, 0xfb, 0xea, 0xba, 0xa2, 0xbc, 0xae, 0x2e, 0x0e, 0x3e, 0x93, 0xfb,
+	0x92, 0xaa, 0xfb, 0xb7, 0x0d, 0xb5, 0xfb, 0xe4, 0xec, 0x61, 0x30, 0xa5, 0xa3, 0xee, 0x34, 0x1e,
+	0x3e, 0xba, 0x26, 0xf1, 0x6f, 0xe0, 0xe6, 0x40, 0x98, 0xf9, 0x89, 0xe0, 0xc6, 0x08, 0xf7, 0x27,
+	0x24, 0x18, 0x91, 0x54, 0x7a, 0x52, 0xe9, 0x1c, 0xb4, 0x8c, 0x1c, 0xa8, 0x78, 0xf5, 0x82, 0x94,
+	0xf7, 0x09, 0xf7, 0xa4, 0x5a, 0xb7, 0xf0, 0xe4, 0xfc, 0xc0, 0xc2, 0x48, 0x62, 0xac, 0x9c, 0xa0,
+	0x3b, 0x50, 0xc9, 0x91, 0x99, 0xf4, 0xb8, 0xd2, 0x69, 0x98, 0x78, 0x22, 0x13, 0x2d, 0x91, 0x89,
+	0x56, 0x97, 0xf2, 0x4f, 0xd3, 0x34, 0x58, 0x60, 0x58, 0x02, 0x31, 0xf4, 0x3a, 0xec, 0x50, 0xa6,
+	0x83, 0x20, 0xdd, 0x2f, 0xe3, 0x32, 0x65, 0xca, 0x79, 0xd7, 0x83, 0x72, 0x2f, 0x8d, 0x93, 0x98,
+	0x05, 0x53, 0xf4, 0x31, 0x94, 0x13, 0xbd, 0x96, 0x3e, 0x57, 0x3a, 0xfb, 0x6b, 0x68, 0x6b, 0x0d,
+	0xcd, 0x78, 0x69, 0xe1, 0xfe, 0x62, 0x43, 0x25, 0x3b, 0xec, 0x9d, 0xde, 0xbb, 0x32, 0x7e, 0xef,
+	0x03, 0xca, 0x6c, 0xfc, 0x24, 0x9e, 0xfa, 0x66, 0x30, 0x5f, 0xcd, 0x4e, 0x7a, 0xf1, 0x54, 0xe6,
+	0x05, 0xdd, 0x85, 0xaa, 0xa9, 0xed, 0x6c, 0x3d, 0x8f, 0xfb, 0x9a, 0x5b, 0xc5, 0x40, 0x73, 0x1f,
+	0xc1, 0x4e, 0x37, 0x8b, 0xc9, 0x35, 0x73, 0xfb, 0x01, 0x14, 0x44, 0xec, 0xf5, 0xdd, 0xb7, 0xd6,
+	0xa7, 0x52, 0xdf, 0x29, 0x35, 0xdd, 0x9f, 0x6d, 0xa8, 0x67, 0xb7, 0x51, 0xbe, 0xf8, 0x1f, 0x77,
+	0xee, 0x41, 0x91, 0x46, 0x23, 0xf2, 0x83, 0xae, 0x04, 0x25, 0xa0, 0x77, 0x60, 0x57, 0xbe, 0x68,
+	0xf5, 0xc8, 0xe8, 0x63, 0x55, 0x00, 0x35, 0x5c, 0x15, 0xbb, 0xf2, 0xd9, 0xd0, 0xc7, 0x44, 0xd8,
+	0x0e, 0x16, 0x9c, 0x30, 0x99, 0xec, 0x2a, 0x56, 0x82, 0xdb, 0x81, 0xc2, 0xc3, 0x98, 0x8b, 0xaa,
+	0x28, 0xcc, 0x63, 0x4e, 0x1c, 0xfb, 0x2a, 0x6f, 0x84, 0x16, 0x96, 0x3a, 0xee, 0x8f, 0x36, 0x94,
+	0xbc, 0x80, 0x49, 0xbb, 0xeb, 0xf1, 0x3f, 0x82, 0x82, 0x40, 0x93, 0xf4, 0x77, 0xd7, 0x3d, 0xff,
+	0x3e, 0x1d, 0x47, 0x64, 0x74, 0xc2, 0xc6, 0x0f, 0x16, 0x09, 0xc1, 0x52, 0x39, 0x77, 0xba, 0xa0,
+	0xa0, 0xa4, 0xe0, 0xfe, 0x6e, 0x43, 0x55, 0x30, 0xe8, 0x13, 0x7e, 0x12, 0x7c, 0xd7, 0x39, 0x7a,
+	0x11, 0x4c, 0xbe, 0x80, 0xb2, 0x2a, 0x3a, 0x3a, 0xd2, 0x15, 0xf7, 0xda, 0xb3, 0x86, 0x32, 0xc3,
+	0xc7, 0x9f, 0x77, 0xeb, 0x22, 0xf3, 0x17, 0xe7, 0x07, 0x25, 0xbd, 0x81, 0x4b, 0xd2, 0xf6, 0x78,
+	0xe4, 0xfe, 0x65, 0x43, 0x45, 0x53, 0xef, 0x52, 0xce, 0x5e, 0x1e, 0xe6, 0xe8, 0x36, 0x14, 0xe7,
+	0x71, 0xf6, 0x86, 0x9e, 0xb7, 0xe0, 0x94, 0x89, 0xfb, 0xab, 0xf6, 0x1a, 0x93, 0xef, 0x67, 0x84,
+	0xf1, 0x17, 0xe1, 0xf5, 0x92, 0x6e, 0xe1, 0xfa, 0x74, 0xcf, 0xb7, 0xa1, 0x74, 0x42, 0x18, 0x0b,
+	0xc6, 0x04, 0x7d, 0x05, 0xbb, 0x11, 0x39, 0x53, 0x3d, 0xc9, 0x97, 0x93, 0x48, 0x95, 0x89, 0xdb,
+	0x5a, 0x37, 0x43, 0x5b, 0xe6, 0xa4, 0xf3, 0x2c, 0x5c, 0x8d, 0x0c, 0x19, 0x9d, 0x40, 0x5d, 0x60,
+	0xcd, 0xc5, 0x48, 0xf1, 0x65, 0x5c, 0xa5, 0xa3, 0x95, 0xce, 0xdb, 0x57, 0x82, 0xe5, 0xe3, 0xc7,
+	0xb3, 0x70, 0x2d, 0x32, 0x37, 0x56, 0xba, 0xf3, 0x9a, 0x2e, 0x98, 0xe3, 0x64, 0x4d, 0xd8, 0x33,
+	0xba, 0x33, 0xfa, 0xf2, 0x5f, 0x7d, 0x54, 0xc5, 0xe9, 0xad, 0xcd, 0x08, 0xbd, 0xd3, 0x7b, 0xde,
+	0x6a, 0x1b, 0x45, 0x9f, 0x00, 0xe4, 0xd3, 0x48, 0x3f, 0x8e, 0x83, 0xf5, 0x28, 0xcb, 0x76, 0xeb,
+	0x59, 0x78, 0x67, 0x39, 0x8f, 0x44, 0x37, 0x95, 0xfd, 0x67, 0xfb, 0xd9, 0x09, 0x93, 0xdb, 0x8a,
+	0xe7, 0xe3, 0x59, 0xaa, 0x0b, 0xa1, 0xdb, 0x50, 0x9e, 0x04, 0xcc, 0x97, 0x56, 0x25, 0x69, 0xf5,
+	0xe6, 0x7a, 0x2b, 0xdd, 0xaa, 0x3c, 0x0b, 0x97, 0x26, 0x6a, 0x29, 0x12, 0x2a, 0xec, 0xe4, 0x44,
+	0x0e, 0x45, 0xf7, 0x70, 0xca, 0x9b, 0x12, 0x6a, 0xf6, 0x19, 0x91, 0xd0, 0xb9, 0x21, 0xa3, 0xbb,
+	0x50, 0x5b, 0x62, 0x89, 0xf7, 0xe4, 0xec, 0x6c, 0x0a, 0xa2, 0x51, 0xf7, 0x22, 0x88, 0xf3, 0x5c,
+	0x44, 0x7d, 0xb8, 0xb1, 0x0c, 0x22, 0xe5, 0x0b, 0x15, 0x4b, 0x90, 0x60, 0xef, 0x6e, 0x8e, 0xa5,
+	0x1e, 0x26, 0x9e, 0x85, 0xeb, 0x83, 0xd5, 0x2d, 0x91, 0x61, 0xc9, 0x2e, 0x55, 0x55, 0xe7, 0x54,
+	0xfe, 0x8b, 0x9c, 0x2e, 0xcf, 0x8c, 0x9c, 0x16, 0xd1, 0x31, 0xd4, 0x42, 0x55, 0x0d, 0xfe, 0x20,
+	0xe0, 0xc3, 0x89, 0x53, 0xdd, 0x14, 0x30, 0x5d, 0x38, 0x5d, 0xa1, 0x29, 0x02, 0x16, 0x1a, 0x72,
+	0xb7, 0x08, 0x5b, 0x6c, 0x16, 0xba, 0xa7, 0x50, 0x35, 0xd5, 0xd0, 0x1d, 0x28, 0x6b, 0x35, 0xe6,
+	0xd8, 0xcd, 0xad, 0xab, 0xf3, 0x99, 0x59, 0xe9, 0xaf, 0x46, 0x66, 0xd4, 0xfd, 0xfa, 0xc9, 0x45,
+	0xc3, 0x7e, 0x7a, 0xd1, 0xb0, 0xff, 0xbc, 0x68, 0xd8, 0x3f, 0x5d, 0x36, 0xac, 0xa7, 0x97, 0x0d,
+	0xeb, 0x8f, 0xcb, 0x86, 0xf5, 0xed, 0x47, 0x63, 0xca, 0x27, 0xb3, 0x41, 0x6b, 0x18, 0x87, 0x6d,
+	0xf3, 0x27, 0x9b, 0x2f, 0xd5, 0x8f, 0x77, 0xdd, 0x9f, 0x79, 0xb0, 0x2d, 0xcf, 0x8e, 0xfe, 0x19,
+	0x00, 0xe8, 0xe0, 0x7b, 0xed, 0x52, 0x0b, 0x00, 0x00,
 }
 
 func (m *NewRoundStep) Marshal() (dAtA []byte, err error) {
@@ -1093,6 +1336,56 @@ func (m *BlockPart) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *BlockParityPart) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BlockParityPart) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BlockParityPart) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Bytes) > 0 {
+		i -= len(m.Bytes)
+		copy(dAtA[i:], m.Bytes)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Bytes)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.LastPartSize != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.LastPartSize))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Index != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Index))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Round != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Round))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Height != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *Vote) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1277,6 +1570,54 @@ func (m *VoteSetBits) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *VoteRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VoteRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *VoteRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.Votes.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintTypes(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x22
+	if m.Type != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Round != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Round))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Height != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *Message) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1498,18 +1839,118 @@ func (m *Message_VoteSetBits) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	}
 	return len(dAtA) - i, nil
 }
-func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
-	offset -= sovTypes(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *Message_BlockParityPart) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Message_BlockParityPart) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.BlockParityPart != nil {
+		{
+			size, err := m.BlockParityPart.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return len(dAtA) - i, nil
 }
-func (m *NewRoundStep) Size() (n int) {
+func (m *Message_VoteRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Message_VoteRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.VoteRequest != nil {
+		{
+			size, err := m.VoteRequest.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x5a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Message_MessageBatch) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Message_MessageBatch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.MessageBatch != nil {
+		{
+			size, err := m.MessageBatch.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x62
+	}
+	return len(dAtA) - i, nil
+}
+func (m *MessageBatch) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MessageBatch) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MessageBatch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Messages) > 0 {
+		for iNdEx := len(m.Messages) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Messages[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTypes(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
+	offset -= sovTypes(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *NewRoundStep) Size() (n int) {
 	if m == nil {
 		return 0
 	}
@@ -1602,6 +2043,31 @@ func (m *BlockPart) Size() (n int) {
 	return n
 }
 
+func (m *BlockParityPart) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovTypes(uint64(m.Height))
+	}
+	if m.Round != 0 {
+		n += 1 + sovTypes(uint64(m.Round))
+	}
+	if m.Index != 0 {
+		n += 1 + sovTypes(uint64(m.Index))
+	}
+	if m.LastPartSize != 0 {
+		n += 1 + sovTypes(uint64(m.LastPartSize))
+	}
+	l = len(m.Bytes)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
 func (m *Vote) Size() (n int) {
 	if m == nil {
 		return 0
@@ -1678,6 +2144,26 @@ func (m *VoteSetBits) Size() (n int) {
 	return n
 }
 
+func (m *VoteRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovTypes(uint64(m.Height))
+	}
+	if m.Round != 0 {
+		n += 1 + sovTypes(uint64(m.Round))
+	}
+	if m.Type != 0 {
+		n += 1 + sovTypes(uint64(m.Type))
+	}
+	l = m.Votes.Size()
+	n += 1 + l + sovTypes(uint64(l))
+	return n
+}
+
 func (m *Message) Size() (n int) {
 	if m == nil {
 		return 0
@@ -1798,6 +2284,56 @@ func (m *Message_VoteSetBits) Size() (n int) {
 	}
 	return n
 }
+func (m *Message_BlockParityPart) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.BlockParityPart != nil {
+		l = m.BlockParityPart.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *Message_VoteRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.VoteRequest != nil {
+		l = m.VoteRequest.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *Message_MessageBatch) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.MessageBatch != nil {
+		l = m.MessageBatch.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *MessageBatch) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Messages) > 0 {
+		for _, e := range m.Messages {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	return n
+}
 
 func sovTypes(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
@@ -2452,7 +2988,7 @@ func (m *BlockPart) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Vote) Unmarshal(dAtA []byte) error {
+func (m *BlockParityPart) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2475,17 +3011,93 @@ func (m *Vote) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Vote: wiretype end group for non-group")
+			return fmt.Errorf("proto: BlockParityPart: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Vote: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: BlockParityPart: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Round", wireType)
+			}
+			m.Round = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Round |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastPartSize", wireType)
+			}
+			m.LastPartSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastPartSize |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Vote", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Bytes", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -2495,26 +3107,24 @@ func (m *Vote) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthTypes
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTypes
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Vote == nil {
-				m.Vote = &types.Vote{}
-			}
-			if err := m.Vote.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Bytes = append(m.Bytes[:0], dAtA[iNdEx:postIndex]...)
+			if m.Bytes == nil {
+				m.Bytes = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -2538,7 +3148,7 @@ func (m *Vote) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *HasVote) Unmarshal(dAtA []byte) error {
+func (m *Vote) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2561,17 +3171,17 @@ func (m *HasVote) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: HasVote: wiretype end group for non-group")
+			return fmt.Errorf("proto: Vote: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: HasVote: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Vote: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Vote", wireType)
 			}
-			m.Height = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -2581,24 +3191,110 @@ func (m *HasVote) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Height |= int64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Round", wireType)
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
 			}
-			m.Round = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTypes
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Vote == nil {
+				m.Vote = &types.Vote{}
+			}
+			if err := m.Vote.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HasVote) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HasVote: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HasVote: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Round", wireType)
+			}
+			m.Round = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
 				iNdEx++
 				m.Round |= int32(b&0x7F) << shift
 				if b < 0x80 {
@@ -2977,6 +3673,146 @@ func (m *VoteSetBits) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *VoteRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: VoteRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: VoteRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Round", wireType)
+			}
+			m.Round = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Round |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= types.SignedMsgType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Votes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Votes.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *Message) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -3321,6 +4157,195 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 			}
 			m.Sum = &Message_VoteSetBits{v}
 			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockParityPart", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &BlockParityPart{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Message_BlockParityPart{v}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VoteRequest", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &VoteRequest{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Message_VoteRequest{v}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageBatch", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &MessageBatch{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Message_MessageBatch{v}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MessageBatch) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MessageBatch: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MessageBatch: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Messages", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Messages = append(m.Messages, Message{})
+			if err := m.Messages[len(m.Messages)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -24,6 +24,40 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+type DisconnectReason int32
+
+const (
+	DisconnectUnknown       DisconnectReason = 0
+	DisconnectShuttingDown  DisconnectReason = 1
+	DisconnectPeerLimit     DisconnectReason = 2
+	DisconnectBanned        DisconnectReason = 3
+	DisconnectProtocolError DisconnectReason = 4
+)
+
+var DisconnectReason_name = map[int32]string{
+	0: "DISCONNECT_REASON_UNKNOWN",
+	1: "DISCONNECT_REASON_SHUTTING_DOWN",
+	2: "DISCONNECT_REASON_PEER_LIMIT",
+	3: "DISCONNECT_REASON_BANNED",
+	4: "DISCONNECT_REASON_PROTOCOL_ERROR",
+}
+
+var DisconnectReason_value = map[string]int32{
+	"DISCONNECT_REASON_UNKNOWN":        0,
+	"DISCONNECT_REASON_SHUTTING_DOWN":  1,
+	"DISCONNECT_REASON_PEER_LIMIT":     2,
+	"DISCONNECT_REASON_BANNED":         3,
+	"DISCONNECT_REASON_PROTOCOL_ERROR": 4,
+}
+
+func (x DisconnectReason) String() string {
+	return proto.EnumName(DisconnectReason_name, int32(x))
+}
+
+func (DisconnectReason) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_22474b5527c8fa9f, []int{0}
+}
+
 type PacketPing struct {
 }
 
@@ -156,11 +190,56 @@ func (m *PacketMsg) GetData() []byte {
 	return nil
 }
 
+type PacketDisconnect struct {
+	Reason DisconnectReason `protobuf:"varint,1,opt,name=reason,proto3,enum=tendermint.p2p.DisconnectReason" json:"reason,omitempty"`
+}
+
+func (m *PacketDisconnect) Reset()         { *m = PacketDisconnect{} }
+func (m *PacketDisconnect) String() string { return proto.CompactTextString(m) }
+func (*PacketDisconnect) ProtoMessage()    {}
+func (*PacketDisconnect) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22474b5527c8fa9f, []int{3}
+}
+func (m *PacketDisconnect) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PacketDisconnect) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PacketDisconnect.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PacketDisconnect) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PacketDisconnect.Merge(m, src)
+}
+func (m *PacketDisconnect) XXX_Size() int {
+	return m.Size()
+}
+func (m *PacketDisconnect) XXX_DiscardUnknown() {
+	xxx_messageInfo_PacketDisconnect.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PacketDisconnect proto.InternalMessageInfo
+
+func (m *PacketDisconnect) GetReason() DisconnectReason {
+	if m != nil {
+		return m.Reason
+	}
+	return DisconnectUnknown
+}
+
 type Packet struct {
 	// Types that are valid to be assigned to Sum:
 	//	*Packet_PacketPing
 	//	*Packet_PacketPong
 	//	*Packet_PacketMsg
+	//	*Packet_PacketDisconnect
 	Sum isPacket_Sum `protobuf_oneof:"sum"`
 }
 
@@ -168,7 +247,7 @@ func (m *Packet) Reset()         { *m = Packet{} }
 func (m *Packet) String() string { return proto.CompactTextString(m) }
 func (*Packet) ProtoMessage()    {}
 func (*Packet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22474b5527c8fa9f, []int{3}
+	return fileDescriptor_22474b5527c8fa9f, []int{4}
 }
 func (m *Packet) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -212,10 +291,14 @@ type Packet_PacketPong struct {
 type Packet_PacketMsg struct {
 	PacketMsg *PacketMsg `protobuf:"bytes,3,opt,name=packet_msg,json=packetMsg,proto3,oneof" json:"packet_msg,omitempty"`
 }
+type Packet_PacketDisconnect struct {
+	PacketDisconnect *PacketDisconnect `protobuf:"bytes,4,opt,name=packet_disconnect,json=packetDisconnect,proto3,oneof" json:"packet_disconnect,omitempty"`
+}
 
-func (*Packet_PacketPing) isPacket_Sum() {}
-func (*Packet_PacketPong) isPacket_Sum() {}
-func (*Packet_PacketMsg) isPacket_Sum()  {}
+func (*Packet_PacketPing) isPacket_Sum()       {}
+func (*Packet_PacketPong) isPacket_Sum()       {}
+func (*Packet_PacketMsg) isPacket_Sum()        {}
+func (*Packet_PacketDisconnect) isPacket_Sum() {}
 
 func (m *Packet) GetSum() isPacket_Sum {
 	if m != nil {
@@ -245,12 +328,20 @@ func (m *Packet) GetPacketMsg() *PacketMsg {
 	return nil
 }
 
+func (m *Packet) GetPacketDisconnect() *PacketDisconnect {
+	if x, ok := m.GetSum().(*Packet_PacketDisconnect); ok {
+		return x.PacketDisconnect
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Packet) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
 		(*Packet_PacketPing)(nil),
 		(*Packet_PacketPong)(nil),
 		(*Packet_PacketMsg)(nil),
+		(*Packet_PacketDisconnect)(nil),
 	}
 }
 
@@ -263,7 +354,7 @@ func (m *AuthSigMessage) Reset()         { *m = AuthSigMessage{} }
 func (m *AuthSigMessage) String() string { return proto.CompactTextString(m) }
 func (*AuthSigMessage) ProtoMessage()    {}
 func (*AuthSigMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22474b5527c8fa9f, []int{4}
+	return fileDescriptor_22474b5527c8fa9f, []int{5}
 }
 func (m *AuthSigMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -307,9 +398,11 @@ func (m *AuthSigMessage) GetSig() []byte {
 }
 
 func init() {
+	proto.RegisterEnum("tendermint.p2p.DisconnectReason", DisconnectReason_name, DisconnectReason_value)
 	proto.RegisterType((*PacketPing)(nil), "tendermint.p2p.PacketPing")
 	proto.RegisterType((*PacketPong)(nil), "tendermint.p2p.PacketPong")
 	proto.RegisterType((*PacketMsg)(nil), "tendermint.p2p.PacketMsg")
+	proto.RegisterType((*PacketDisconnect)(nil), "tendermint.p2p.PacketDisconnect")
 	proto.RegisterType((*Packet)(nil), "tendermint.p2p.Packet")
 	proto.RegisterType((*AuthSigMessage)(nil), "tendermint.p2p.AuthSigMessage")
 }
@@ -317,32 +410,47 @@ func init() {
 func init() { proto.RegisterFile("tendermint/p2p/conn.proto", fileDescriptor_22474b5527c8fa9f) }
 
 var fileDescriptor_22474b5527c8fa9f = []byte{
-	// 395 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x52, 0x3d, 0x8f, 0xd3, 0x40,
-	0x10, 0xf5, 0xe2, 0xbb, 0x1c, 0x99, 0x84, 0x13, 0x5a, 0x51, 0x24, 0xd1, 0xc9, 0x89, 0x5c, 0xa5,
-	0x40, 0xb6, 0x64, 0x44, 0x03, 0xa2, 0xc0, 0x7c, 0x88, 0xd3, 0x29, 0xba, 0xc8, 0x74, 0x34, 0x96,
-	0x3f, 0x96, 0xf5, 0x2a, 0xe7, 0xdd, 0x55, 0x76, 0x5d, 0xf8, 0x5f, 0xf0, 0xb3, 0x8e, 0xee, 0x4a,
-	0xaa, 0x08, 0x39, 0x7f, 0x04, 0x79, 0x1d, 0x88, 0x23, 0x71, 0xdd, 0x7b, 0x33, 0xf3, 0xe6, 0x43,
-	0xf3, 0x60, 0xaa, 0x09, 0xcf, 0xc9, 0xb6, 0x64, 0x5c, 0xfb, 0x32, 0x90, 0x7e, 0x26, 0x38, 0xf7,
-	0xe4, 0x56, 0x68, 0x81, 0x2f, 0x8f, 0x29, 0x4f, 0x06, 0x72, 0xf6, 0x82, 0x0a, 0x2a, 0x4c, 0xca,
-	0x6f, 0x51, 0x57, 0x35, 0xbb, 0xea, 0x35, 0xc8, 0xb6, 0xb5, 0xd4, 0xc2, 0xdf, 0x90, 0x5a, 0x75,
-	0x59, 0x77, 0x0c, 0xb0, 0x4e, 0xb2, 0x0d, 0xd1, 0x6b, 0xc6, 0x69, 0x8f, 0x09, 0x4e, 0xdd, 0x02,
-	0x86, 0x1d, 0x5b, 0x29, 0x8a, 0x5f, 0x02, 0x64, 0x45, 0xc2, 0x39, 0xb9, 0x8b, 0x59, 0x3e, 0x41,
-	0x0b, 0xb4, 0x3c, 0x0f, 0x9f, 0x35, 0xbb, 0xf9, 0xf0, 0x43, 0x17, 0xbd, 0xfe, 0x18, 0x0d, 0x0f,
-	0x05, 0xd7, 0x39, 0x9e, 0x82, 0x4d, 0xc4, 0xf7, 0xc9, 0x93, 0x05, 0x5a, 0x3e, 0x0d, 0x2f, 0x9a,
-	0xdd, 0xdc, 0xfe, 0x74, 0xfb, 0x39, 0x6a, 0x63, 0x18, 0xc3, 0x59, 0x9e, 0xe8, 0x64, 0x62, 0x2f,
-	0xd0, 0x72, 0x1c, 0x19, 0xec, 0xfe, 0x44, 0x30, 0xe8, 0x46, 0xe1, 0x77, 0x30, 0x92, 0x06, 0xc5,
-	0x92, 0x71, 0x6a, 0x06, 0x8d, 0x82, 0x99, 0x77, 0x7a, 0xaa, 0x77, 0xdc, 0xf9, 0x8b, 0x15, 0x81,
-	0xfc, 0xc7, 0xfa, 0x72, 0xc1, 0xa9, 0x59, 0xe0, 0x71, 0xb9, 0x38, 0x91, 0x0b, 0x4e, 0xf1, 0x1b,
-	0x38, 0xb0, 0xb8, 0x54, 0xd4, 0xac, 0x38, 0x0a, 0xa6, 0xff, 0x57, 0xaf, 0x54, 0x2b, 0x1e, 0xca,
-	0xbf, 0x24, 0x3c, 0x07, 0x5b, 0x55, 0xa5, 0x1b, 0xc3, 0xe5, 0xfb, 0x4a, 0x17, 0x5f, 0x19, 0x5d,
-	0x11, 0xa5, 0x12, 0x4a, 0xf0, 0x5b, 0xb8, 0x90, 0x55, 0x1a, 0x6f, 0x48, 0x7d, 0x38, 0xe7, 0xaa,
-	0xdf, 0xb1, 0xfb, 0x89, 0xb7, 0xae, 0xd2, 0x3b, 0x96, 0xdd, 0x90, 0x3a, 0x3c, 0xbb, 0xdf, 0xcd,
-	0xad, 0x68, 0x20, 0xab, 0xf4, 0x86, 0xd4, 0xf8, 0x39, 0xd8, 0x8a, 0x75, 0x87, 0x8c, 0xa3, 0x16,
-	0x86, 0xb7, 0xf7, 0x8d, 0x83, 0x1e, 0x1a, 0x07, 0xfd, 0x6e, 0x1c, 0xf4, 0x63, 0xef, 0x58, 0x0f,
-	0x7b, 0xc7, 0xfa, 0xb5, 0x77, 0xac, 0x6f, 0xaf, 0x29, 0xd3, 0x45, 0x95, 0x7a, 0x99, 0x28, 0xfd,
-	0xde, 0xd7, 0xfb, 0x0e, 0x32, 0xee, 0x38, 0xb5, 0x54, 0x3a, 0x30, 0xd1, 0x57, 0x7f, 0x02, 0x00,
-	0x00, 0xff, 0xff, 0x30, 0xfd, 0xb2, 0x8d, 0x6b, 0x02, 0x00, 0x00,
+	// 630 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x94, 0x5d, 0x6b, 0xda, 0x50,
+	0x18, 0xc7, 0x13, 0xb5, 0x76, 0x9e, 0x76, 0x25, 0x3d, 0xeb, 0x56, 0xcd, 0x4a, 0x0c, 0x5e, 0x95,
+	0x31, 0x14, 0xdc, 0x06, 0x7b, 0x61, 0x0c, 0xa3, 0xd9, 0x2a, 0xd5, 0x44, 0x8e, 0x96, 0xc1, 0x6e,
+	0x82, 0xc6, 0xb3, 0x63, 0xb0, 0x9e, 0x73, 0xc8, 0x0b, 0xc3, 0x6f, 0x30, 0xbc, 0xda, 0xf5, 0xc0,
+	0xab, 0x7d, 0x92, 0xdd, 0xf5, 0xb2, 0x97, 0xbb, 0x2a, 0xc3, 0x7e, 0x91, 0x91, 0x44, 0x9a, 0xb4,
+	0x95, 0xdd, 0x3d, 0x6f, 0xbf, 0xff, 0xc3, 0xf3, 0x9c, 0x17, 0x50, 0xf2, 0x31, 0x1d, 0x63, 0x77,
+	0xe6, 0x50, 0xbf, 0xc6, 0xeb, 0xbc, 0x66, 0x33, 0x4a, 0xab, 0xdc, 0x65, 0x3e, 0x83, 0x7b, 0x49,
+	0xaa, 0xca, 0xeb, 0x5c, 0x3e, 0x20, 0x8c, 0xb0, 0x28, 0x55, 0x0b, 0xad, 0xb8, 0x4a, 0x3e, 0x4a,
+	0x09, 0xd8, 0xee, 0x9c, 0xfb, 0xac, 0x36, 0xc5, 0x73, 0x2f, 0xce, 0x56, 0x76, 0x01, 0xe8, 0x0d,
+	0xed, 0x29, 0xf6, 0x7b, 0x0e, 0x25, 0x29, 0x8f, 0x51, 0x52, 0x99, 0x80, 0x42, 0xec, 0x75, 0x3d,
+	0x02, 0x9f, 0x03, 0x60, 0x4f, 0x86, 0x94, 0xe2, 0x73, 0xcb, 0x19, 0x17, 0x45, 0x55, 0x3c, 0xde,
+	0xd2, 0x1e, 0xae, 0xae, 0xca, 0x85, 0x66, 0x1c, 0x6d, 0xb7, 0x50, 0x61, 0x5d, 0xd0, 0x1e, 0xc3,
+	0x12, 0xc8, 0x62, 0xf6, 0xb5, 0x98, 0x51, 0xc5, 0xe3, 0x07, 0xda, 0xf6, 0xea, 0xaa, 0x9c, 0xd5,
+	0xcd, 0x8f, 0x28, 0x8c, 0x41, 0x08, 0x72, 0xe3, 0xa1, 0x3f, 0x2c, 0x66, 0x55, 0xf1, 0x78, 0x17,
+	0x45, 0x76, 0xa5, 0x03, 0xa4, 0xb8, 0x53, 0xcb, 0xf1, 0xc2, 0x01, 0xb1, 0xed, 0xc3, 0xd7, 0x20,
+	0xef, 0xe2, 0xa1, 0xc7, 0x68, 0xd4, 0x6c, 0xaf, 0xae, 0x56, 0x6f, 0x8f, 0x5b, 0x4d, 0x6a, 0x51,
+	0x54, 0x87, 0xd6, 0xf5, 0x95, 0x9f, 0x19, 0x90, 0x8f, 0xe5, 0xe0, 0x7b, 0xb0, 0xc3, 0x23, 0xcb,
+	0xe2, 0x0e, 0x25, 0x91, 0xd2, 0x4e, 0x5d, 0xbe, 0xab, 0x94, 0x6c, 0xe0, 0x44, 0x40, 0x80, 0xdf,
+	0x78, 0x69, 0x9c, 0x51, 0x52, 0xcc, 0xfc, 0x17, 0x67, 0xb7, 0x70, 0x46, 0x09, 0x7c, 0x0b, 0xd6,
+	0x9e, 0x35, 0xf3, 0x48, 0x34, 0xf0, 0x4e, 0xbd, 0xb4, 0x99, 0xee, 0x7a, 0x21, 0x5c, 0xe0, 0x37,
+	0xfb, 0x36, 0xc1, 0xfe, 0x9a, 0x1d, 0xdf, 0xcc, 0x59, 0xcc, 0x45, 0x12, 0xea, 0x66, 0x89, 0x64,
+	0x1f, 0x27, 0x02, 0x92, 0xf8, 0x9d, 0x98, 0xb6, 0x05, 0xb2, 0x5e, 0x30, 0xab, 0x58, 0x60, 0xaf,
+	0x11, 0xf8, 0x93, 0xbe, 0x43, 0xba, 0xd8, 0xf3, 0x86, 0x04, 0xc3, 0x77, 0x60, 0x9b, 0x07, 0x23,
+	0x6b, 0x8a, 0xe7, 0xeb, 0xfd, 0x1c, 0xa5, 0xf5, 0xe3, 0x2b, 0x53, 0xed, 0x05, 0xa3, 0x73, 0xc7,
+	0x3e, 0xc5, 0x73, 0x2d, 0x77, 0x71, 0x55, 0x16, 0x50, 0x9e, 0x07, 0xa3, 0x53, 0x3c, 0x87, 0x12,
+	0xc8, 0x7a, 0x4e, 0xbc, 0x99, 0x5d, 0x14, 0x9a, 0xcf, 0x7e, 0x67, 0x80, 0x74, 0xf7, 0x68, 0xe0,
+	0x4b, 0x50, 0x6a, 0xb5, 0xfb, 0x4d, 0xd3, 0x30, 0xf4, 0xe6, 0xc0, 0x42, 0x7a, 0xa3, 0x6f, 0x1a,
+	0xd6, 0x99, 0x71, 0x6a, 0x98, 0x9f, 0x0d, 0x49, 0x90, 0x1f, 0x2f, 0x96, 0xea, 0x7e, 0x02, 0x9d,
+	0xd1, 0x29, 0x65, 0xdf, 0x28, 0xfc, 0x00, 0xca, 0xf7, 0xa9, 0xfe, 0xc9, 0xd9, 0x60, 0xd0, 0x36,
+	0x3e, 0x59, 0xad, 0x90, 0x15, 0x65, 0x79, 0xb1, 0x54, 0x9f, 0x24, 0x6c, 0x7f, 0x12, 0xf8, 0xbe,
+	0x43, 0x49, 0x2b, 0x14, 0x78, 0x03, 0x8e, 0xee, 0x0b, 0xf4, 0x74, 0x1d, 0x59, 0x9d, 0x76, 0xb7,
+	0x3d, 0x90, 0x32, 0xf2, 0xe1, 0x62, 0xa9, 0x3e, 0x4a, 0xe8, 0x1e, 0xc6, 0x6e, 0xc7, 0x99, 0x39,
+	0x3e, 0xac, 0x83, 0xe2, 0x7d, 0x54, 0x6b, 0x18, 0x86, 0xde, 0x92, 0xb2, 0xf2, 0xc1, 0x62, 0xa9,
+	0xa6, 0xa6, 0xd4, 0xc2, 0x7b, 0x3f, 0x86, 0x0d, 0xa0, 0x6e, 0x68, 0x87, 0xcc, 0x81, 0xd9, 0x34,
+	0x3b, 0x96, 0x8e, 0x90, 0x89, 0xa4, 0x9c, 0xfc, 0x74, 0xb1, 0x54, 0x0f, 0x53, 0x2d, 0xc3, 0x77,
+	0x68, 0xb3, 0x73, 0xdd, 0x75, 0x99, 0x2b, 0xe7, 0xbe, 0xff, 0x52, 0x04, 0xcd, 0xbc, 0x58, 0x29,
+	0xe2, 0xe5, 0x4a, 0x11, 0xff, 0xae, 0x14, 0xf1, 0xc7, 0xb5, 0x22, 0x5c, 0x5e, 0x2b, 0xc2, 0x9f,
+	0x6b, 0x45, 0xf8, 0xf2, 0x8a, 0x38, 0xfe, 0x24, 0x18, 0x55, 0x6d, 0x36, 0xab, 0xa5, 0x1e, 0x76,
+	0xca, 0x8c, 0x3f, 0x80, 0xdb, 0xbf, 0xc6, 0x28, 0x1f, 0x45, 0x5f, 0xfc, 0x1b, 0x00, 0x25, 0x73,
+	0xd7, 0x48, 0x4e, 0x04, 0x00, 0x00,
 }
 
 func (m *PacketPing) Marshal() (dAtA []byte, err error) {
@@ -436,6 +544,34 @@ func (m *PacketMsg) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *PacketDisconnect) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PacketDisconnect) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PacketDisconnect) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Reason != 0 {
+		i = encodeVarintConn(dAtA, i, uint64(m.Reason))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *Packet) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -531,6 +667,27 @@ func (m *Packet_PacketMsg) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	}
 	return len(dAtA) - i, nil
 }
+func (m *Packet_PacketDisconnect) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Packet_PacketDisconnect) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.PacketDisconnect != nil {
+		{
+			size, err := m.PacketDisconnect.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintConn(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	return len(dAtA) - i, nil
+}
 func (m *AuthSigMessage) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -619,6 +776,18 @@ func (m *PacketMsg) Size() (n int) {
 	return n
 }
 
+func (m *PacketDisconnect) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Reason != 0 {
+		n += 1 + sovConn(uint64(m.Reason))
+	}
+	return n
+}
+
 func (m *Packet) Size() (n int) {
 	if m == nil {
 		return 0
@@ -667,6 +836,18 @@ func (m *Packet_PacketMsg) Size() (n int) {
 	}
 	return n
 }
+func (m *Packet_PacketDisconnect) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.PacketDisconnect != nil {
+		l = m.PacketDisconnect.Size()
+		n += 1 + l + sovConn(uint64(l))
+	}
+	return n
+}
 func (m *AuthSigMessage) Size() (n int) {
 	if m == nil {
 		return 0
@@ -911,6 +1092,75 @@ func (m *PacketMsg) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *PacketDisconnect) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConn
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PacketDisconnect: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PacketDisconnect: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
+			}
+			m.Reason = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConn
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Reason |= DisconnectReason(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConn(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthConn
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *Packet) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -1045,6 +1295,41 @@ func (m *Packet) Unmarshal(dAtA []byte) error {
 			}
 			m.Sum = &Packet_PacketMsg{v}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PacketDisconnect", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConn
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConn
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConn
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &PacketDisconnect{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Packet_PacketDisconnect{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConn(dAtA[iNdEx:])
@@ -9,6 +9,7 @@ import (
 	proto "github.com/gogo/protobuf/proto"
 	_ "github.com/gogo/protobuf/types"
 	github_com_gogo_protobuf_types "github.com/gogo/protobuf/types"
+	crypto "github.com/tendermint/tendermint/proto/tendermint/crypto"
 	io "io"
 	math "math"
 	math_bits "math/bits"
@@ -88,14 +89,17 @@ func (m *ProtocolVersion) GetApp() uint64 {
 }
 
 type NodeInfo struct {
-	ProtocolVersion ProtocolVersion `protobuf:"bytes,1,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version"`
-	NodeID          string          `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
-	ListenAddr      string          `protobuf:"bytes,3,opt,name=listen_addr,json=listenAddr,proto3" json:"listen_addr,omitempty"`
-	Network         string          `protobuf:"bytes,4,opt,name=network,proto3" json:"network,omitempty"`
-	Version         string          `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
-	Channels        []byte          `protobuf:"bytes,6,opt,name=channels,proto3" json:"channels,omitempty"`
-	Moniker         string          `protobuf:"bytes,7,opt,name=moniker,proto3" json:"moniker,omitempty"`
-	Other           NodeInfoOther   `protobuf:"bytes,8,opt,name=other,proto3" json:"other"`
+	ProtocolVersion    ProtocolVersion  `protobuf:"bytes,1,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version"`
+	NodeID             string           `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	ListenAddr         string           `protobuf:"bytes,3,opt,name=listen_addr,json=listenAddr,proto3" json:"listen_addr,omitempty"`
+	Network            string           `protobuf:"bytes,4,opt,name=network,proto3" json:"network,omitempty"`
+	Version            string           `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
+	Channels           []byte           `protobuf:"bytes,6,opt,name=channels,proto3" json:"channels,omitempty"`
+	Moniker            string           `protobuf:"bytes,7,opt,name=moniker,proto3" json:"moniker,omitempty"`
+	Other              NodeInfoOther    `protobuf:"bytes,8,opt,name=other,proto3" json:"other"`
+	NextNodeKey        *NodeKeyRotation `protobuf:"bytes,9,opt,name=next_node_key,json=nextNodeKey,proto3" json:"next_node_key,omitempty"`
+	MinProtocolVersion *ProtocolVersion `protobuf:"bytes,10,opt,name=min_protocol_version,json=minProtocolVersion,proto3" json:"min_protocol_version,omitempty"`
+	Compression        []string         `protobuf:"bytes,11,rep,name=compression,proto3" json:"compression,omitempty"`
 }
 
 func (m *NodeInfo) Reset()         { *m = NodeInfo{} }
@@ -187,6 +191,27 @@ func (m *NodeInfo) GetOther() NodeInfoOther {
 	return NodeInfoOther{}
 }
 
+func (m *NodeInfo) GetNextNodeKey() *NodeKeyRotation {
+	if m != nil {
+		return m.NextNodeKey
+	}
+	return nil
+}
+
+func (m *NodeInfo) GetMinProtocolVersion() *ProtocolVersion {
+	if m != nil {
+		return m.MinProtocolVersion
+	}
+	return nil
+}
+
+func (m *NodeInfo) GetCompression() []string {
+	if m != nil {
+		return m.Compression
+	}
+	return nil
+}
+
 type NodeInfoOther struct {
 	TxIndex    string `protobuf:"bytes,1,opt,name=tx_index,json=txIndex,proto3" json:"tx_index,omitempty"`
 	RPCAddress string `protobuf:"bytes,2,opt,name=rpc_address,json=rpcAddress,proto3" json:"rpc_address,omitempty"`
@@ -239,18 +264,89 @@ func (m *NodeInfoOther) GetRPCAddress() string {
 	return ""
 }
 
+type NodeKeyRotation struct {
+	NextNodeID    string            `protobuf:"bytes,1,opt,name=next_node_id,json=nextNodeId,proto3" json:"next_node_id,omitempty"`
+	Signature     []byte            `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	NextPubKey    *crypto.PublicKey `protobuf:"bytes,3,opt,name=next_pub_key,json=nextPubKey,proto3" json:"next_pub_key,omitempty"`
+	NextSignature []byte            `protobuf:"bytes,4,opt,name=next_signature,json=nextSignature,proto3" json:"next_signature,omitempty"`
+}
+
+func (m *NodeKeyRotation) Reset()         { *m = NodeKeyRotation{} }
+func (m *NodeKeyRotation) String() string { return proto.CompactTextString(m) }
+func (*NodeKeyRotation) ProtoMessage()    {}
+func (*NodeKeyRotation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8a29e659aeca578, []int{3}
+}
+func (m *NodeKeyRotation) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NodeKeyRotation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NodeKeyRotation.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *NodeKeyRotation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeKeyRotation.Merge(m, src)
+}
+func (m *NodeKeyRotation) XXX_Size() int {
+	return m.Size()
+}
+func (m *NodeKeyRotation) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeKeyRotation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeKeyRotation proto.InternalMessageInfo
+
+func (m *NodeKeyRotation) GetNextNodeID() string {
+	if m != nil {
+		return m.NextNodeID
+	}
+	return ""
+}
+
+func (m *NodeKeyRotation) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *NodeKeyRotation) GetNextPubKey() *crypto.PublicKey {
+	if m != nil {
+		return m.NextPubKey
+	}
+	return nil
+}
+
+func (m *NodeKeyRotation) GetNextSignature() []byte {
+	if m != nil {
+		return m.NextSignature
+	}
+	return nil
+}
+
 type PeerInfo struct {
-	ID            string             `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	AddressInfo   []*PeerAddressInfo `protobuf:"bytes,2,rep,name=address_info,json=addressInfo,proto3" json:"address_info,omitempty"`
-	LastConnected *time.Time         `protobuf:"bytes,3,opt,name=last_connected,json=lastConnected,proto3,stdtime" json:"last_connected,omitempty"`
-	Inactive      bool               `protobuf:"varint,4,opt,name=inactive,proto3" json:"inactive,omitempty"`
+	ID               string             `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AddressInfo      []*PeerAddressInfo `protobuf:"bytes,2,rep,name=address_info,json=addressInfo,proto3" json:"address_info,omitempty"`
+	LastConnected    *time.Time         `protobuf:"bytes,3,opt,name=last_connected,json=lastConnected,proto3,stdtime" json:"last_connected,omitempty"`
+	Inactive         bool               `protobuf:"varint,4,opt,name=inactive,proto3" json:"inactive,omitempty"`
+	RTT              time.Duration      `protobuf:"bytes,5,opt,name=rtt,proto3,stdduration" json:"rtt"`
+	Connections      uint32             `protobuf:"varint,6,opt,name=connections,proto3" json:"connections,omitempty"`
+	ConnectionErrors uint32             `protobuf:"varint,7,opt,name=connection_errors,json=connectionErrors,proto3" json:"connection_errors,omitempty"`
 }
 
 func (m *PeerInfo) Reset()         { *m = PeerInfo{} }
 func (m *PeerInfo) String() string { return proto.CompactTextString(m) }
 func (*PeerInfo) ProtoMessage()    {}
 func (*PeerInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c8a29e659aeca578, []int{3}
+	return fileDescriptor_c8a29e659aeca578, []int{4}
 }
 func (m *PeerInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -307,6 +403,27 @@ func (m *PeerInfo) GetInactive() bool {
 	return false
 }
 
+func (m *PeerInfo) GetRTT() time.Duration {
+	if m != nil {
+		return m.RTT
+	}
+	return 0
+}
+
+func (m *PeerInfo) GetConnections() uint32 {
+	if m != nil {
+		return m.Connections
+	}
+	return 0
+}
+
+func (m *PeerInfo) GetConnectionErrors() uint32 {
+	if m != nil {
+		return m.ConnectionErrors
+	}
+	return 0
+}
+
 type PeerAddressInfo struct {
 	Address         string     `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
 	LastDialSuccess *time.Time `protobuf:"bytes,2,opt,name=last_dial_success,json=lastDialSuccess,proto3,stdtime" json:"last_dial_success,omitempty"`
@@ -318,7 +435,7 @@ func (m *PeerAddressInfo) Reset()         { *m = PeerAddressInfo{} }
 func (m *PeerAddressInfo) String() string { return proto.CompactTextString(m) }
 func (*PeerAddressInfo) ProtoMessage()    {}
 func (*PeerAddressInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c8a29e659aeca578, []int{4}
+	return fileDescriptor_c8a29e659aeca578, []int{5}
 }
 func (m *PeerAddressInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -375,57 +492,180 @@ func (m *PeerAddressInfo) GetDialFailures() uint32 {
 	return 0
 }
 
+type PeerCrawlRecord struct {
+	ID                  string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	LastCrawled         time.Time `protobuf:"bytes,2,opt,name=last_crawled,json=lastCrawled,proto3,stdtime" json:"last_crawled"`
+	LastFailed          time.Time `protobuf:"bytes,3,opt,name=last_failed,json=lastFailed,proto3,stdtime" json:"last_failed"`
+	Crawls              uint64    `protobuf:"varint,4,opt,name=crawls,proto3" json:"crawls,omitempty"`
+	ConsecutiveFailures uint32    `protobuf:"varint,5,opt,name=consecutive_failures,json=consecutiveFailures,proto3" json:"consecutive_failures,omitempty"`
+	Addresses           uint32    `protobuf:"varint,6,opt,name=addresses,proto3" json:"addresses,omitempty"`
+	Moniker             string    `protobuf:"bytes,7,opt,name=moniker,proto3" json:"moniker,omitempty"`
+	Version             string    `protobuf:"bytes,8,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (m *PeerCrawlRecord) Reset()         { *m = PeerCrawlRecord{} }
+func (m *PeerCrawlRecord) String() string { return proto.CompactTextString(m) }
+func (*PeerCrawlRecord) ProtoMessage()    {}
+func (*PeerCrawlRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8a29e659aeca578, []int{6}
+}
+func (m *PeerCrawlRecord) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PeerCrawlRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PeerCrawlRecord.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PeerCrawlRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerCrawlRecord.Merge(m, src)
+}
+func (m *PeerCrawlRecord) XXX_Size() int {
+	return m.Size()
+}
+func (m *PeerCrawlRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerCrawlRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerCrawlRecord proto.InternalMessageInfo
+
+func (m *PeerCrawlRecord) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *PeerCrawlRecord) GetLastCrawled() time.Time {
+	if m != nil {
+		return m.LastCrawled
+	}
+	return time.Time{}
+}
+
+func (m *PeerCrawlRecord) GetLastFailed() time.Time {
+	if m != nil {
+		return m.LastFailed
+	}
+	return time.Time{}
+}
+
+func (m *PeerCrawlRecord) GetCrawls() uint64 {
+	if m != nil {
+		return m.Crawls
+	}
+	return 0
+}
+
+func (m *PeerCrawlRecord) GetConsecutiveFailures() uint32 {
+	if m != nil {
+		return m.ConsecutiveFailures
+	}
+	return 0
+}
+
+func (m *PeerCrawlRecord) GetAddresses() uint32 {
+	if m != nil {
+		return m.Addresses
+	}
+	return 0
+}
+
+func (m *PeerCrawlRecord) GetMoniker() string {
+	if m != nil {
+		return m.Moniker
+	}
+	return ""
+}
+
+func (m *PeerCrawlRecord) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ProtocolVersion)(nil), "tendermint.p2p.ProtocolVersion")
 	proto.RegisterType((*NodeInfo)(nil), "tendermint.p2p.NodeInfo")
 	proto.RegisterType((*NodeInfoOther)(nil), "tendermint.p2p.NodeInfoOther")
+	proto.RegisterType((*NodeKeyRotation)(nil), "tendermint.p2p.NodeKeyRotation")
 	proto.RegisterType((*PeerInfo)(nil), "tendermint.p2p.PeerInfo")
 	proto.RegisterType((*PeerAddressInfo)(nil), "tendermint.p2p.PeerAddressInfo")
+	proto.RegisterType((*PeerCrawlRecord)(nil), "tendermint.p2p.PeerCrawlRecord")
 }
 
 func init() { proto.RegisterFile("tendermint/p2p/types.proto", fileDescriptor_c8a29e659aeca578) }
 
 var fileDescriptor_c8a29e659aeca578 = []byte{
-	// 621 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0x41, 0x4f, 0xdb, 0x30,
-	0x14, 0x6e, 0xda, 0xd2, 0x96, 0x57, 0x4a, 0x99, 0x85, 0xa6, 0x50, 0x69, 0x0d, 0x2a, 0x17, 0x4e,
-	0x89, 0xd4, 0x69, 0x87, 0x1d, 0x09, 0x68, 0x53, 0xa5, 0x69, 0x54, 0x1e, 0xda, 0x61, 0x3b, 0x44,
-	0x69, 0xec, 0x16, 0x8b, 0xd4, 0xb6, 0x12, 0x97, 0xb1, 0x7f, 0xc1, 0xbf, 0x1a, 0xd2, 0x2e, 0x1c,
-	0x77, 0xea, 0xa6, 0x70, 0xdd, 0x8f, 0x98, 0xec, 0x24, 0xd0, 0x56, 0x3b, 0x70, 0xf3, 0xf7, 0x9e,
-	0xbf, 0xcf, 0xdf, 0x7b, 0xcf, 0x7a, 0xd0, 0x53, 0x94, 0x13, 0x9a, 0xcc, 0x19, 0x57, 0x9e, 0x1c,
-	0x4a, 0x4f, 0x7d, 0x97, 0x34, 0x75, 0x65, 0x22, 0x94, 0x40, 0xbb, 0x4f, 0x39, 0x57, 0x0e, 0x65,
-	0x6f, 0x7f, 0x26, 0x66, 0xc2, 0xa4, 0x3c, 0x7d, 0xca, 0x6f, 0xf5, 0x9c, 0x99, 0x10, 0xb3, 0x98,
-	0x7a, 0x06, 0x4d, 0x16, 0x53, 0x4f, 0xb1, 0x39, 0x4d, 0x55, 0x38, 0x97, 0xf9, 0x85, 0xc1, 0x05,
-	0x74, 0xc7, 0xfa, 0x10, 0x89, 0xf8, 0x33, 0x4d, 0x52, 0x26, 0x38, 0x3a, 0x80, 0x9a, 0x1c, 0x4a,
-	0xdb, 0x3a, 0xb4, 0x8e, 0xeb, 0x7e, 0x33, 0x5b, 0x3a, 0xb5, 0xf1, 0x70, 0x8c, 0x75, 0x0c, 0xed,
-	0xc3, 0xd6, 0x24, 0x16, 0xd1, 0x95, 0x5d, 0xd5, 0x49, 0x9c, 0x03, 0xb4, 0x07, 0xb5, 0x50, 0x4a,
-	0xbb, 0x66, 0x62, 0xfa, 0x38, 0xf8, 0x51, 0x85, 0xd6, 0x47, 0x41, 0xe8, 0x88, 0x4f, 0x05, 0x1a,
-	0xc3, 0x9e, 0x2c, 0x9e, 0x08, 0xae, 0xf3, 0x37, 0x8c, 0x78, 0x7b, 0xe8, 0xb8, 0xeb, 0x45, 0xb8,
-	0x1b, 0x56, 0xfc, 0xfa, 0xdd, 0xd2, 0xa9, 0xe0, 0xae, 0xdc, 0x70, 0x78, 0x04, 0x4d, 0x2e, 0x08,
-	0x0d, 0x18, 0x31, 0x46, 0xb6, 0x7d, 0xc8, 0x96, 0x4e, 0xc3, 0x3c, 0x78, 0x86, 0x1b, 0x3a, 0x35,
-	0x22, 0xc8, 0x81, 0x76, 0xcc, 0x52, 0x45, 0x79, 0x10, 0x12, 0x92, 0x18, 0x77, 0xdb, 0x18, 0xf2,
-	0xd0, 0x09, 0x21, 0x09, 0xb2, 0xa1, 0xc9, 0xa9, 0xfa, 0x26, 0x92, 0x2b, 0xbb, 0x6e, 0x92, 0x25,
-	0xd4, 0x99, 0xd2, 0xe8, 0x56, 0x9e, 0x29, 0x20, 0xea, 0x41, 0x2b, 0xba, 0x0c, 0x39, 0xa7, 0x71,
-	0x6a, 0x37, 0x0e, 0xad, 0xe3, 0x1d, 0xfc, 0x88, 0x35, 0x6b, 0x2e, 0x38, 0xbb, 0xa2, 0x89, 0xdd,
-	0xcc, 0x59, 0x05, 0x44, 0x6f, 0x61, 0x4b, 0xa8, 0x4b, 0x9a, 0xd8, 0x2d, 0x53, 0xf6, 0xab, 0xcd,
-	0xb2, 0xcb, 0x56, 0x9d, 0xeb, 0x4b, 0x45, 0xd1, 0x39, 0x63, 0xf0, 0x15, 0x3a, 0x6b, 0x59, 0x74,
-	0x00, 0x2d, 0x75, 0x13, 0x30, 0x4e, 0xe8, 0x8d, 0xe9, 0xe2, 0x36, 0x6e, 0xaa, 0x9b, 0x91, 0x86,
-	0xc8, 0x83, 0x76, 0x22, 0x23, 0x53, 0x2e, 0x4d, 0xd3, 0xa2, 0x35, 0xbb, 0xd9, 0xd2, 0x01, 0x3c,
-	0x3e, 0x3d, 0xc9, 0xa3, 0x18, 0x12, 0x19, 0x15, 0xe7, 0xc1, 0x4f, 0x0b, 0x5a, 0x63, 0x4a, 0x13,
-	0x33, 0xa6, 0x97, 0x50, 0x65, 0x24, 0x97, 0xf4, 0x1b, 0xd9, 0xd2, 0xa9, 0x8e, 0xce, 0x70, 0x95,
-	0x11, 0xe4, 0xc3, 0x4e, 0xa1, 0x18, 0x30, 0x3e, 0x15, 0x76, 0xf5, 0xb0, 0xf6, 0xdf, 0xd1, 0x51,
-	0x9a, 0x14, 0xba, 0x5a, 0x0e, 0xb7, 0xc3, 0x27, 0x80, 0xde, 0xc3, 0x6e, 0x1c, 0xa6, 0x2a, 0x88,
-	0x04, 0xe7, 0x34, 0x52, 0x94, 0x98, 0x71, 0xb4, 0x87, 0x3d, 0x37, 0xff, 0x9f, 0x6e, 0xf9, 0x3f,
-	0xdd, 0x8b, 0xf2, 0x7f, 0xfa, 0xf5, 0xdb, 0xdf, 0x8e, 0x85, 0x3b, 0x9a, 0x77, 0x5a, 0xd2, 0x74,
-	0xff, 0x19, 0x0f, 0x23, 0xc5, 0xae, 0xa9, 0x19, 0x5a, 0x0b, 0x3f, 0xe2, 0xc1, 0x5f, 0x0b, 0xba,
-	0x1b, 0x2e, 0xf4, 0x4c, 0xca, 0x76, 0x14, 0xcd, 0x2a, 0x20, 0xfa, 0x00, 0x2f, 0x8c, 0x25, 0xc2,
-	0xc2, 0x38, 0x48, 0x17, 0x51, 0x54, 0xb6, 0xec, 0x39, 0xae, 0xba, 0x9a, 0x7a, 0xc6, 0xc2, 0xf8,
-	0x53, 0x4e, 0x5c, 0x57, 0x9b, 0x86, 0x2c, 0x5e, 0x24, 0xf4, 0xd9, 0x35, 0x3e, 0xaa, 0xbd, 0xcb,
-	0x89, 0xe8, 0x08, 0x3a, 0xab, 0x42, 0xa9, 0x29, 0xb5, 0x83, 0x77, 0xc8, 0xd3, 0x9d, 0xd4, 0x3f,
-	0xbf, 0xcb, 0xfa, 0xd6, 0x7d, 0xd6, 0xb7, 0xfe, 0x64, 0x7d, 0xeb, 0xf6, 0xa1, 0x5f, 0xb9, 0x7f,
-	0xe8, 0x57, 0x7e, 0x3d, 0xf4, 0x2b, 0x5f, 0xde, 0xcc, 0x98, 0xba, 0x5c, 0x4c, 0xdc, 0x48, 0xcc,
-	0xbd, 0x95, 0x0d, 0xb2, 0xba, 0x4c, 0xcc, 0x9e, 0x58, 0xdf, 0x2e, 0x93, 0x86, 0x89, 0xbe, 0xfe,
-	0x17, 0x00, 0x00, 0xff, 0xff, 0x42, 0xcb, 0x37, 0x26, 0x76, 0x04, 0x00, 0x00,
+	// 958 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0x4d, 0x6f, 0x1a, 0xc7,
+	0x1b, 0xf7, 0x02, 0xe6, 0xe5, 0x01, 0x8c, 0x33, 0x7f, 0x2b, 0x5a, 0x23, 0xff, 0x01, 0x11, 0x55,
+	0xb2, 0x54, 0x09, 0x5a, 0xaa, 0x1e, 0x2a, 0x55, 0x95, 0x82, 0x49, 0x22, 0xd4, 0x2a, 0xa1, 0x13,
+	0xab, 0x87, 0xf6, 0xb0, 0x5a, 0x76, 0xc7, 0x78, 0xc4, 0x32, 0xb3, 0x9a, 0x1d, 0x12, 0x73, 0xec,
+	0x37, 0xc8, 0xb1, 0x1f, 0xc9, 0xc7, 0x9c, 0xaa, 0x9c, 0x68, 0x85, 0xaf, 0xfd, 0x10, 0xd5, 0xbc,
+	0xac, 0x17, 0x48, 0x5a, 0xb9, 0xb7, 0x7d, 0x9e, 0x67, 0x7e, 0xcf, 0xcb, 0xef, 0x79, 0x01, 0x68,
+	0x4a, 0xc2, 0x42, 0x22, 0x16, 0x94, 0xc9, 0x7e, 0x3c, 0x88, 0xfb, 0x72, 0x15, 0x93, 0xa4, 0x17,
+	0x0b, 0x2e, 0x39, 0x3a, 0xca, 0x6c, 0xbd, 0x78, 0x10, 0x37, 0x4f, 0x66, 0x7c, 0xc6, 0xb5, 0xa9,
+	0xaf, 0xbe, 0xcc, 0xab, 0x66, 0x7b, 0xc6, 0xf9, 0x2c, 0x22, 0x7d, 0x2d, 0x4d, 0x97, 0x57, 0x7d,
+	0x49, 0x17, 0x24, 0x91, 0xfe, 0x22, 0xb6, 0x0f, 0x5a, 0xfb, 0x0f, 0xc2, 0xa5, 0xf0, 0x25, 0xe5,
+	0xcc, 0xda, 0xcf, 0xb6, 0x52, 0x08, 0xc4, 0x2a, 0x96, 0xbc, 0x3f, 0x27, 0x2b, 0x9b, 0x44, 0xf7,
+	0x12, 0x1a, 0x13, 0xf5, 0x11, 0xf0, 0xe8, 0x27, 0x22, 0x12, 0xca, 0x19, 0x3a, 0x85, 0x7c, 0x3c,
+	0x88, 0x5d, 0xa7, 0xe3, 0x9c, 0x17, 0x86, 0xa5, 0xcd, 0xba, 0x9d, 0x9f, 0x0c, 0x26, 0x58, 0xe9,
+	0xd0, 0x09, 0x1c, 0x4e, 0x23, 0x1e, 0xcc, 0xdd, 0x9c, 0x32, 0x62, 0x23, 0xa0, 0x63, 0xc8, 0xfb,
+	0x71, 0xec, 0xe6, 0xb5, 0x4e, 0x7d, 0x76, 0x7f, 0x2d, 0x40, 0xf9, 0x25, 0x0f, 0xc9, 0x98, 0x5d,
+	0x71, 0x34, 0x81, 0xe3, 0xd8, 0x86, 0xf0, 0xde, 0x98, 0x18, 0xda, 0x79, 0x75, 0xd0, 0xee, 0xed,
+	0x52, 0xd0, 0xdb, 0x4b, 0x65, 0x58, 0xb8, 0x5d, 0xb7, 0x0f, 0x70, 0x23, 0xde, 0xcb, 0xf0, 0x09,
+	0x94, 0x18, 0x0f, 0x89, 0x47, 0x43, 0x9d, 0x48, 0x65, 0x08, 0x9b, 0x75, 0xbb, 0xa8, 0x03, 0x8e,
+	0x70, 0x51, 0x99, 0xc6, 0x21, 0x6a, 0x43, 0x35, 0xa2, 0x89, 0x24, 0xcc, 0xf3, 0xc3, 0x50, 0xe8,
+	0xec, 0x2a, 0x18, 0x8c, 0xea, 0x69, 0x18, 0x0a, 0xe4, 0x42, 0x89, 0x11, 0xf9, 0x96, 0x8b, 0xb9,
+	0x5b, 0xd0, 0xc6, 0x54, 0x54, 0x96, 0x34, 0xd1, 0x43, 0x63, 0xb1, 0x22, 0x6a, 0x42, 0x39, 0xb8,
+	0xf6, 0x19, 0x23, 0x51, 0xe2, 0x16, 0x3b, 0xce, 0x79, 0x0d, 0xdf, 0xcb, 0x0a, 0xb5, 0xe0, 0x8c,
+	0xce, 0x89, 0x70, 0x4b, 0x06, 0x65, 0x45, 0xf4, 0x0d, 0x1c, 0x72, 0x79, 0x4d, 0x84, 0x5b, 0xd6,
+	0x65, 0xff, 0x7f, 0xbf, 0xec, 0x94, 0xaa, 0x57, 0xea, 0x91, 0x2d, 0xda, 0x20, 0xd0, 0x05, 0xd4,
+	0x19, 0xb9, 0x91, 0x9e, 0xae, 0x77, 0x4e, 0x56, 0x6e, 0xe5, 0xd3, 0xcc, 0x29, 0x17, 0xdf, 0x93,
+	0x15, 0xe6, 0x52, 0xf7, 0x1e, 0x57, 0x15, 0xca, 0x2a, 0xd1, 0x8f, 0x70, 0xb2, 0xa0, 0xcc, 0xfb,
+	0xa8, 0x0b, 0xf0, 0xa0, 0x2e, 0x60, 0xb4, 0xa0, 0x6c, 0x7f, 0x48, 0x3a, 0x50, 0x0d, 0xf8, 0x22,
+	0x16, 0x24, 0xd1, 0x9e, 0xaa, 0x9d, 0xfc, 0x79, 0x05, 0x6f, 0xab, 0xba, 0xbf, 0x40, 0x7d, 0xa7,
+	0x2e, 0x74, 0x0a, 0x65, 0x79, 0xe3, 0x51, 0x16, 0x92, 0x1b, 0xdd, 0xff, 0x0a, 0x2e, 0xc9, 0x9b,
+	0xb1, 0x12, 0x51, 0x1f, 0xaa, 0x22, 0x0e, 0x74, 0xa3, 0x48, 0x92, 0xd8, 0xa6, 0x1e, 0x6d, 0xd6,
+	0x6d, 0xc0, 0x93, 0x8b, 0xa7, 0x46, 0x8b, 0x41, 0xc4, 0x81, 0xfd, 0xee, 0xde, 0x3a, 0xd0, 0xd8,
+	0x2b, 0x19, 0x7d, 0x01, 0xb5, 0x8c, 0x2a, 0x1a, 0xba, 0x4e, 0xe6, 0xe5, 0xa5, 0x25, 0x63, 0x3c,
+	0xc2, 0x90, 0x12, 0x33, 0x0e, 0xd1, 0x19, 0x54, 0x12, 0x3a, 0x63, 0xbe, 0x5c, 0x0a, 0xa2, 0x83,
+	0xd6, 0x70, 0xa6, 0x40, 0xdf, 0x59, 0x7f, 0xf1, 0x72, 0xaa, 0x99, 0xcf, 0x6b, 0xb6, 0xce, 0xb6,
+	0xd9, 0x32, 0xfb, 0xd4, 0x9b, 0x2c, 0xa7, 0x11, 0x0d, 0x54, 0x2e, 0xda, 0xfb, 0x64, 0x39, 0x55,
+	0xac, 0x7f, 0x06, 0x47, 0x1a, 0x9f, 0x85, 0x28, 0xe8, 0x10, 0xba, 0xa1, 0xaf, 0x53, 0x65, 0xf7,
+	0x43, 0x0e, 0xca, 0x13, 0x42, 0x84, 0xde, 0x95, 0xc7, 0x90, 0xbb, 0xcf, 0xbc, 0xb8, 0x59, 0xb7,
+	0x73, 0xe3, 0x11, 0xce, 0xd1, 0x10, 0x0d, 0xa1, 0x66, 0xc9, 0xf1, 0x28, 0xbb, 0xe2, 0x6e, 0xae,
+	0x93, 0xff, 0x64, 0xe7, 0x08, 0x11, 0x96, 0x22, 0xe5, 0x0e, 0x57, 0xfd, 0x4c, 0x40, 0x2f, 0xe0,
+	0x28, 0xf2, 0x13, 0xe9, 0x05, 0x9c, 0x31, 0x12, 0x48, 0x12, 0xda, 0x8a, 0x9a, 0x3d, 0x73, 0x41,
+	0x7a, 0xe9, 0x05, 0xe9, 0x5d, 0xa6, 0x27, 0x66, 0x58, 0x78, 0xf7, 0x47, 0xdb, 0xc1, 0x75, 0x85,
+	0xbb, 0x48, 0x61, 0x6a, 0x09, 0x28, 0xf3, 0x03, 0x49, 0xdf, 0x98, 0x92, 0xca, 0xf8, 0x5e, 0x46,
+	0xdf, 0x42, 0x5e, 0x48, 0xa9, 0xd7, 0xa6, 0x3a, 0x38, 0xfd, 0xc8, 0xf3, 0xc8, 0xde, 0xa6, 0x61,
+	0x43, 0x0d, 0xb9, 0xba, 0x2d, 0xf8, 0xf2, 0xf2, 0x37, 0x15, 0x43, 0xc1, 0xcc, 0x54, 0xe9, 0x30,
+	0x94, 0x33, 0xb3, 0x61, 0x75, 0xbc, 0xad, 0x42, 0x9f, 0xc3, 0xa3, 0x4c, 0xf4, 0x88, 0x10, 0x5c,
+	0x24, 0x7a, 0xdd, 0xea, 0xf8, 0x38, 0x33, 0x3c, 0xd3, 0xfa, 0xee, 0x5f, 0x0e, 0x34, 0xf6, 0x28,
+	0x51, 0x5b, 0x9a, 0x8e, 0x99, 0x1d, 0x42, 0x2b, 0xa2, 0x1f, 0xe0, 0x91, 0xe6, 0x27, 0xa4, 0x7e,
+	0xe4, 0x25, 0xcb, 0x20, 0x48, 0x47, 0xf1, 0x21, 0x14, 0x35, 0x14, 0x74, 0x44, 0xfd, 0xe8, 0xb5,
+	0x01, 0xee, 0x7a, 0xbb, 0xf2, 0x69, 0xa4, 0x06, 0x20, 0xff, 0x5f, 0xbd, 0x3d, 0x37, 0x40, 0xf4,
+	0x04, 0xea, 0xdb, 0x8e, 0x12, 0xcd, 0x7b, 0x1d, 0xd7, 0xc2, 0xec, 0x4d, 0xd2, 0xfd, 0x3d, 0x67,
+	0xca, 0xbd, 0x10, 0xfe, 0xdb, 0x08, 0x93, 0x80, 0x8b, 0xf0, 0x1f, 0x07, 0xea, 0x05, 0xd4, 0xcc,
+	0x30, 0xa8, 0xb7, 0x24, 0x7c, 0x40, 0x9d, 0x65, 0xd5, 0x31, 0x9d, 0x5d, 0x55, 0x8f, 0x83, 0x01,
+	0xa2, 0x67, 0xa0, 0x45, 0x9d, 0xd9, 0x83, 0x46, 0x2a, 0xf3, 0x03, 0x0a, 0xf8, 0x5c, 0xe3, 0xd0,
+	0x63, 0x28, 0xea, 0x54, 0x4c, 0x65, 0x05, 0x6c, 0x25, 0xf4, 0x25, 0x9c, 0x04, 0x9c, 0x25, 0x24,
+	0x58, 0xaa, 0xf1, 0xca, 0xea, 0x3f, 0xd4, 0xf5, 0xff, 0x6f, 0xcb, 0x96, 0xd2, 0xa0, 0xb6, 0xda,
+	0xb6, 0x94, 0xa4, 0x23, 0x94, 0x29, 0xfe, 0xe5, 0x4a, 0x6f, 0x5d, 0xfd, 0xf2, 0xce, 0xd5, 0x1f,
+	0xbe, 0xba, 0xdd, 0xb4, 0x9c, 0xf7, 0x9b, 0x96, 0xf3, 0xe7, 0xa6, 0xe5, 0xbc, 0xbb, 0x6b, 0x1d,
+	0xbc, 0xbf, 0x6b, 0x1d, 0x7c, 0xb8, 0x6b, 0x1d, 0xfc, 0xfc, 0xf5, 0x8c, 0xca, 0xeb, 0xe5, 0xb4,
+	0x17, 0xf0, 0x45, 0x7f, 0xeb, 0x77, 0x76, 0xeb, 0xd3, 0xfc, 0xa0, 0xef, 0xfe, 0x0d, 0x98, 0x16,
+	0xb5, 0xf6, 0xab, 0xbf, 0x07, 0x00, 0x55, 0x08, 0x99, 0x3d, 0x1f, 0x08, 0x00, 0x00,
 }
 
 func (m *ProtocolVersion) Marshal() (dAtA []byte, err error) {
@@ -486,6 +726,39 @@ func (m *NodeInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Compression) > 0 {
+		for iNdEx := len(m.Compression) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Compression[iNdEx])
+			copy(dAtA[i:], m.Compression[iNdEx])
+			i = encodeVarintTypes(dAtA, i, uint64(len(m.Compression[iNdEx])))
+			i--
+			dAtA[i] = 0x5a
+		}
+	}
+	if m.MinProtocolVersion != nil {
+		{
+			size, err := m.MinProtocolVersion.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	if m.NextNodeKey != nil {
+		{
+			size, err := m.NextNodeKey.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
 	{
 		size, err := m.Other.MarshalToSizedBuffer(dAtA[:i])
 		if err != nil {
@@ -588,6 +861,62 @@ func (m *NodeInfoOther) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *NodeKeyRotation) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NodeKeyRotation) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NodeKeyRotation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.NextSignature) > 0 {
+		i -= len(m.NextSignature)
+		copy(dAtA[i:], m.NextSignature)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.NextSignature)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.NextPubKey != nil {
+		{
+			size, err := m.NextPubKey.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.NextNodeID) > 0 {
+		i -= len(m.NextNodeID)
+		copy(dAtA[i:], m.NextNodeID)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.NextNodeID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *PeerInfo) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -608,6 +937,24 @@ func (m *PeerInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.ConnectionErrors != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.ConnectionErrors))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.Connections != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Connections))
+		i--
+		dAtA[i] = 0x30
+	}
+	n5, err5 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.RTT, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.RTT):])
+	if err5 != nil {
+		return 0, err5
+	}
+	i -= n5
+	i = encodeVarintTypes(dAtA, i, uint64(n5))
+	i--
+	dAtA[i] = 0x2a
 	if m.Inactive {
 		i--
 		if m.Inactive {
@@ -619,12 +966,12 @@ func (m *PeerInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		dAtA[i] = 0x20
 	}
 	if m.LastConnected != nil {
-		n3, err3 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.LastConnected, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.LastConnected):])
-		if err3 != nil {
-			return 0, err3
+		n6, err6 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.LastConnected, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.LastConnected):])
+		if err6 != nil {
+			return 0, err6
 		}
-		i -= n3
-		i = encodeVarintTypes(dAtA, i, uint64(n3))
+		i -= n6
+		i = encodeVarintTypes(dAtA, i, uint64(n6))
 		i--
 		dAtA[i] = 0x1a
 	}
@@ -678,22 +1025,22 @@ func (m *PeerAddressInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		dAtA[i] = 0x20
 	}
 	if m.LastDialFailure != nil {
-		n4, err4 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.LastDialFailure, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.LastDialFailure):])
-		if err4 != nil {
-			return 0, err4
+		n7, err7 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.LastDialFailure, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.LastDialFailure):])
+		if err7 != nil {
+			return 0, err7
 		}
-		i -= n4
-		i = encodeVarintTypes(dAtA, i, uint64(n4))
+		i -= n7
+		i = encodeVarintTypes(dAtA, i, uint64(n7))
 		i--
 		dAtA[i] = 0x1a
 	}
 	if m.LastDialSuccess != nil {
-		n5, err5 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.LastDialSuccess, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.LastDialSuccess):])
-		if err5 != nil {
-			return 0, err5
+		n8, err8 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.LastDialSuccess, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.LastDialSuccess):])
+		if err8 != nil {
+			return 0, err8
 		}
-		i -= n5
-		i = encodeVarintTypes(dAtA, i, uint64(n5))
+		i -= n8
+		i = encodeVarintTypes(dAtA, i, uint64(n8))
 		i--
 		dAtA[i] = 0x12
 	}
@@ -707,6 +1054,81 @@ func (m *PeerAddressInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *PeerCrawlRecord) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PeerCrawlRecord) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PeerCrawlRecord) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Version) > 0 {
+		i -= len(m.Version)
+		copy(dAtA[i:], m.Version)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Version)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.Moniker) > 0 {
+		i -= len(m.Moniker)
+		copy(dAtA[i:], m.Moniker)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Moniker)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.Addresses != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Addresses))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.ConsecutiveFailures != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.ConsecutiveFailures))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Crawls != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Crawls))
+		i--
+		dAtA[i] = 0x20
+	}
+	n9, err9 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.LastFailed, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.LastFailed):])
+	if err9 != nil {
+		return 0, err9
+	}
+	i -= n9
+	i = encodeVarintTypes(dAtA, i, uint64(n9))
+	i--
+	dAtA[i] = 0x1a
+	n10, err10 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.LastCrawled, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.LastCrawled):])
+	if err10 != nil {
+		return 0, err10
+	}
+	i -= n10
+	i = encodeVarintTypes(dAtA, i, uint64(n10))
+	i--
+	dAtA[i] = 0x12
+	if len(m.ID) > 0 {
+		i -= len(m.ID)
+		copy(dAtA[i:], m.ID)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.ID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
 	offset -= sovTypes(v)
 	base := offset
@@ -770,6 +1192,20 @@ func (m *NodeInfo) Size() (n int) {
 	}
 	l = m.Other.Size()
 	n += 1 + l + sovTypes(uint64(l))
+	if m.NextNodeKey != nil {
+		l = m.NextNodeKey.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.MinProtocolVersion != nil {
+		l = m.MinProtocolVersion.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if len(m.Compression) > 0 {
+		for _, s := range m.Compression {
+			l = len(s)
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -790,21 +1226,46 @@ func (m *NodeInfoOther) Size() (n int) {
 	return n
 }
 
-func (m *PeerInfo) Size() (n int) {
+func (m *NodeKeyRotation) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = len(m.ID)
+	l = len(m.NextNodeID)
 	if l > 0 {
 		n += 1 + l + sovTypes(uint64(l))
 	}
-	if len(m.AddressInfo) > 0 {
-		for _, e := range m.AddressInfo {
-			l = e.Size()
-			n += 1 + l + sovTypes(uint64(l))
-		}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.NextPubKey != nil {
+		l = m.NextPubKey.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.NextSignature)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
+func (m *PeerInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if len(m.AddressInfo) > 0 {
+		for _, e := range m.AddressInfo {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
 	}
 	if m.LastConnected != nil {
 		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.LastConnected)
@@ -813,6 +1274,14 @@ func (m *PeerInfo) Size() (n int) {
 	if m.Inactive {
 		n += 2
 	}
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.RTT)
+	n += 1 + l + sovTypes(uint64(l))
+	if m.Connections != 0 {
+		n += 1 + sovTypes(uint64(m.Connections))
+	}
+	if m.ConnectionErrors != 0 {
+		n += 1 + sovTypes(uint64(m.ConnectionErrors))
+	}
 	return n
 }
 
@@ -840,6 +1309,40 @@ func (m *PeerAddressInfo) Size() (n int) {
 	return n
 }
 
+func (m *PeerCrawlRecord) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.LastCrawled)
+	n += 1 + l + sovTypes(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.LastFailed)
+	n += 1 + l + sovTypes(uint64(l))
+	if m.Crawls != 0 {
+		n += 1 + sovTypes(uint64(m.Crawls))
+	}
+	if m.ConsecutiveFailures != 0 {
+		n += 1 + sovTypes(uint64(m.ConsecutiveFailures))
+	}
+	if m.Addresses != 0 {
+		n += 1 + sovTypes(uint64(m.Addresses))
+	}
+	l = len(m.Moniker)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
 func sovTypes(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1242,6 +1745,110 @@ func (m *NodeInfo) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextNodeKey", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.NextNodeKey == nil {
+				m.NextNodeKey = &NodeKeyRotation{}
+			}
+			if err := m.NextNodeKey.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinProtocolVersion", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MinProtocolVersion == nil {
+				m.MinProtocolVersion = &ProtocolVersion{}
+			}
+			if err := m.MinProtocolVersion.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compression", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Compression = append(m.Compression, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -1377,7 +1984,7 @@ func (m *NodeInfoOther) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PeerInfo) Unmarshal(dAtA []byte) error {
+func (m *NodeKeyRotation) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1400,15 +2007,15 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PeerInfo: wiretype end group for non-group")
+			return fmt.Errorf("proto: NodeKeyRotation: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PeerInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: NodeKeyRotation: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field NextNodeID", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1436,13 +2043,13 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ID = string(dAtA[iNdEx:postIndex])
+			m.NextNodeID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AddressInfo", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -1452,29 +2059,29 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthTypes
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTypes
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.AddressInfo = append(m.AddressInfo, &PeerAddressInfo{})
-			if err := m.AddressInfo[len(m.AddressInfo)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
 			}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastConnected", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field NextPubKey", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1501,18 +2108,18 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.LastConnected == nil {
-				m.LastConnected = new(time.Time)
+			if m.NextPubKey == nil {
+				m.NextPubKey = &crypto.PublicKey{}
 			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.LastConnected, dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.NextPubKey.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Inactive", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextSignature", wireType)
 			}
-			var v int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -1522,12 +2129,26 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Inactive = bool(v != 0)
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NextSignature = append(m.NextSignature[:0], dAtA[iNdEx:postIndex]...)
+			if m.NextSignature == nil {
+				m.NextSignature = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -1549,7 +2170,7 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PeerAddressInfo) Unmarshal(dAtA []byte) error {
+func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1572,15 +2193,15 @@ func (m *PeerAddressInfo) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PeerAddressInfo: wiretype end group for non-group")
+			return fmt.Errorf("proto: PeerInfo: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PeerAddressInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PeerInfo: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1608,11 +2229,11 @@ func (m *PeerAddressInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Address = string(dAtA[iNdEx:postIndex])
+			m.ID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastDialSuccess", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AddressInfo", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1639,16 +2260,14 @@ func (m *PeerAddressInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.LastDialSuccess == nil {
-				m.LastDialSuccess = new(time.Time)
-			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.LastDialSuccess, dAtA[iNdEx:postIndex]); err != nil {
+			m.AddressInfo = append(m.AddressInfo, &PeerAddressInfo{})
+			if err := m.AddressInfo[len(m.AddressInfo)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastDialFailure", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LastConnected", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1675,18 +2294,18 @@ func (m *PeerAddressInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.LastDialFailure == nil {
-				m.LastDialFailure = new(time.Time)
+			if m.LastConnected == nil {
+				m.LastConnected = new(time.Time)
 			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.LastDialFailure, dAtA[iNdEx:postIndex]); err != nil {
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.LastConnected, dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DialFailures", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Inactive", wireType)
 			}
-			m.DialFailures = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -1696,11 +2315,525 @@ func (m *PeerAddressInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.DialFailures |= uint32(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Inactive = bool(v != 0)
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RTT", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.RTT, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Connections", wireType)
+			}
+			m.Connections = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Connections |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConnectionErrors", wireType)
+			}
+			m.ConnectionErrors = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ConnectionErrors |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PeerAddressInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PeerAddressInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PeerAddressInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Address = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastDialSuccess", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LastDialSuccess == nil {
+				m.LastDialSuccess = new(time.Time)
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.LastDialSuccess, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastDialFailure", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LastDialFailure == nil {
+				m.LastDialFailure = new(time.Time)
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.LastDialFailure, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DialFailures", wireType)
+			}
+			m.DialFailures = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DialFailures |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PeerCrawlRecord) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PeerCrawlRecord: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PeerCrawlRecord: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastCrawled", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.LastCrawled, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastFailed", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.LastFailed, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Crawls", wireType)
+			}
+			m.Crawls = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Crawls |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConsecutiveFailures", wireType)
+			}
+			m.ConsecutiveFailures = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ConsecutiveFailures |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Addresses", wireType)
+			}
+			m.Addresses = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Addresses |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Moniker", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Moniker = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
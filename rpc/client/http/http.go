@@ -282,6 +282,14 @@ func (c *baseRPCClient) CheckTx(ctx context.Context, tx types.Tx) (*coretypes.Re
 	return result, nil
 }
 
+func (c *baseRPCClient) CheckTxResult(ctx context.Context, hash bytes.HexBytes) (*coretypes.ResultCheckTxResult, error) {
+	result := new(coretypes.ResultCheckTxResult)
+	if err := c.caller.Call(ctx, "check_tx_result", &coretypes.RequestCheckTxResult{Hash: hash}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) RemoveTx(ctx context.Context, txKey types.TxKey) error {
 	if err := c.caller.Call(ctx, "remove_tx", &coretypes.RequestRemoveTx{TxKey: txKey}, nil); err != nil {
 		return err
@@ -166,6 +166,7 @@ type MempoolClient interface {
 	UnconfirmedTxs(ctx context.Context, page, perPage *int) (*coretypes.ResultUnconfirmedTxs, error)
 	NumUnconfirmedTxs(context.Context) (*coretypes.ResultUnconfirmedTxs, error)
 	CheckTx(context.Context, types.Tx) (*coretypes.ResultCheckTx, error)
+	CheckTxResult(ctx context.Context, hash bytes.HexBytes) (*coretypes.ResultCheckTxResult, error)
 	RemoveTx(context.Context, types.TxKey) error
 }
 
@@ -114,6 +114,10 @@ func (c *Local) CheckTx(ctx context.Context, tx types.Tx) (*coretypes.ResultChec
 	return c.env.CheckTx(ctx, &coretypes.RequestCheckTx{Tx: tx})
 }
 
+func (c *Local) CheckTxResult(ctx context.Context, hash bytes.HexBytes) (*coretypes.ResultCheckTxResult, error) {
+	return c.env.CheckTxResult(ctx, &coretypes.RequestCheckTxResult{Hash: hash})
+}
+
 func (c *Local) RemoveTx(ctx context.Context, txKey types.TxKey) error {
 	return c.env.Mempool.RemoveTxByKey(txKey)
 }
@@ -342,6 +342,29 @@ func (_m *Client) CheckTx(_a0 context.Context, _a1 types.Tx) (*coretypes.ResultC
 	return r0, r1
 }
 
+// CheckTxResult provides a mock function with given fields: ctx, hash
+func (_m *Client) CheckTxResult(ctx context.Context, hash bytes.HexBytes) (*coretypes.ResultCheckTxResult, error) {
+	ret := _m.Called(ctx, hash)
+
+	var r0 *coretypes.ResultCheckTxResult
+	if rf, ok := ret.Get(0).(func(context.Context, bytes.HexBytes) *coretypes.ResultCheckTxResult); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coretypes.ResultCheckTxResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, bytes.HexBytes) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Commit provides a mock function with given fields: ctx, height
 func (_m *Client) Commit(ctx context.Context, height *int64) (*coretypes.ResultCommit, error) {
 	ret := _m.Called(ctx, height)
@@ -242,6 +242,7 @@ func TestClientMethodCalls(t *testing.T) {
 				cons, err := nc.DumpConsensusState(ctx)
 				require.NoError(t, err, "%d: %+v", i, err)
 				assert.NotEmpty(t, cons.RoundState)
+				assert.NotZero(t, cons.Locks.Height)
 				assert.Empty(t, cons.Peers)
 			})
 			t.Run("ConsensusState", func(t *testing.T) {
@@ -39,10 +39,22 @@ type RequestCheckTx struct {
 	Tx types.Tx `json:"tx"`
 }
 
+type RequestCheckTxResult struct {
+	Hash bytes.HexBytes `json:"hash"`
+}
+
 type RequestRemoveTx struct {
 	TxKey types.TxKey `json:"txkey"`
 }
 
+type RequestAddressBookAdd struct {
+	URL string `json:"url"`
+}
+
+type RequestAddressBookRemove struct {
+	URL string `json:"url"`
+}
+
 type RequestTx struct {
 	Hash  bytes.HexBytes `json:"hash"`
 	Prove bool           `json:"prove"`
@@ -54,6 +66,7 @@ type RequestTxSearch struct {
 	Page    *Int64 `json:"page"`
 	PerPage *Int64 `json:"per_page"`
 	OrderBy string `json:"order_by"`
+	Cursor  string `json:"cursor"`
 }
 
 type RequestBlockSearch struct {
@@ -76,6 +89,7 @@ type RequestConsensusParams struct {
 type RequestUnconfirmedTxs struct {
 	Page    *Int64 `json:"page"`
 	PerPage *Int64 `json:"per_page"`
+	Sender  string `json:"sender"`
 }
 
 type RequestBroadcastTx struct {
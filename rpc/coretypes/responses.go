@@ -105,6 +105,8 @@ type SyncInfo struct {
 
 	TotalSyncedTime time.Duration `json:"total_synced_time,string"`
 	RemainingTime   time.Duration `json:"remaining_time,string"`
+	SyncRate        float64       `json:"sync_rate"`
+	NumSyncPeers    int64         `json:"num_sync_peers,string"`
 
 	TotalSnapshots      int64         `json:"total_snapshots,string"`
 	ChunkProcessAvgTime time.Duration `json:"chunk_process_avg_time,string"`
@@ -196,6 +198,60 @@ type Peer struct {
 	URL string       `json:"url"`
 }
 
+// Addresses known to the node's peer store
+type ResultAddressBook struct {
+	Addresses []AddressBookEntry `json:"addresses"`
+}
+
+// An address in the node's peer store. Tried addresses have been dialed
+// successfully before, the others have only been learned from the
+// configuration or other peers.
+type AddressBookEntry struct {
+	ID              types.NodeID `json:"node_id"`
+	URL             string       `json:"url"`
+	Tried           bool         `json:"tried"`
+	Score           int64        `json:"score,string"`
+	Persistent      bool         `json:"persistent"`
+	Connected       bool         `json:"connected"`
+	BannedUntil     time.Time    `json:"banned_until"`
+	LastConnected   time.Time    `json:"last_connected"`
+	LastDialSuccess time.Time    `json:"last_dial_success"`
+	LastDialFailure time.Time    `json:"last_dial_failure"`
+	DialFailures    int64        `json:"dial_failures,string"`
+}
+
+// Outcome of crawling each peer, as recorded by a seed node
+type ResultCrawlStatus struct {
+	Peers []CrawlStatusEntry `json:"peers"`
+}
+
+// The outcome of crawling a peer for addresses. Consecutive failures are
+// counted since the last successful crawl. The moniker and version are those
+// the peer last reported.
+type CrawlStatusEntry struct {
+	ID                  types.NodeID `json:"node_id"`
+	LastCrawled         time.Time    `json:"last_crawled"`
+	LastFailed          time.Time    `json:"last_failed"`
+	Crawls              int64        `json:"crawls,string"`
+	ConsecutiveFailures int64        `json:"consecutive_failures,string"`
+	Addresses           int64        `json:"addresses,string"`
+	Moniker             string       `json:"moniker"`
+	Version             string       `json:"version"`
+}
+
+// Result of adding an address to the peer store
+type ResultAddressBookAdd struct {
+	Added bool `json:"added"`
+}
+
+// Result of removing an address from the peer store
+type ResultAddressBookRemove struct {
+	Removed bool `json:"removed"`
+}
+
+// Result of forcing the switch from block sync to consensus
+type ResultUnsafeSwitchToConsensus struct{}
+
 // Validators for a height.
 type ResultValidators struct {
 	BlockHeight int64              `json:"block_height,string"`
@@ -214,14 +270,44 @@ type ResultConsensusParams struct {
 // Info about the consensus state.
 // UNSTABLE
 type ResultDumpConsensusState struct {
-	RoundState json.RawMessage `json:"round_state"`
-	Peers      []PeerStateInfo `json:"peers"`
+	RoundState json.RawMessage   `json:"round_state"`
+	Locks      ConsensusLockInfo `json:"locks"`
+	Peers      []PeerStateInfo   `json:"peers"`
+}
+
+// ConsensusLockInfo summarizes the lock and valid round of the node's round
+// state.
+// UNSTABLE
+type ConsensusLockInfo struct {
+	Height          int64          `json:"height,string"`
+	Round           int32          `json:"round"`
+	Step            string         `json:"step"`
+	LockedRound     int32          `json:"locked_round"`
+	LockedBlockHash bytes.HexBytes `json:"locked_block_hash"`
+	ValidRound      int32          `json:"valid_round"`
+	ValidBlockHash  bytes.HexBytes `json:"valid_block_hash"`
 }
 
 // UNSTABLE
 type PeerStateInfo struct {
-	NodeAddress string          `json:"node_address"`
-	PeerState   json.RawMessage `json:"peer_state"`
+	NodeAddress string             `json:"node_address"`
+	PeerState   json.RawMessage    `json:"peer_state"`
+	RoundState  PeerRoundStateInfo `json:"round_state"`
+}
+
+// PeerRoundStateInfo summarizes the round state a peer has reported.
+// UNSTABLE
+type PeerRoundStateInfo struct {
+	Height           int64  `json:"height,string"`
+	Round            int32  `json:"round"`
+	Step             string `json:"step"`
+	Proposal         bool   `json:"proposal"`
+	ProposalPOLRound int32  `json:"proposal_pol_round"`
+
+	// Addresses of the validators whose prevotes and precommits the peer has
+	// for its round. They're only set if the peer is at the node's height.
+	Prevotes   []crypto.Address `json:"prevotes"`
+	Precommits []crypto.Address `json:"precommits"`
 }
 
 // UNSTABLE
@@ -250,6 +336,18 @@ type ResultCheckTx struct {
 	abci.ResponseCheckTx
 }
 
+// ResultCheckTxResult describes why the mempool recently rejected a
+// transaction.
+type ResultCheckTxResult struct {
+	Hash      bytes.HexBytes `json:"hash"`
+	Code      uint32         `json:"code"`
+	Codespace string         `json:"codespace"`
+	Data      bytes.HexBytes `json:"data"`
+	Reason    string         `json:"reason"`
+	Height    int64          `json:"height,string"`
+	Time      time.Time      `json:"time"`
+}
+
 // Result of querying for a tx
 type ResultTx struct {
 	Hash     bytes.HexBytes    `json:"hash"`
@@ -264,6 +362,7 @@ type ResultTx struct {
 type ResultTxSearch struct {
 	Txs        []*ResultTx `json:"txs"`
 	TotalCount int         `json:"total_count,string"`
+	NextCursor string      `json:"next_cursor,omitempty"`
 }
 
 // ResultBlockSearch defines the RPC response type for a block search by events.
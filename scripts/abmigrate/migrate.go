@@ -0,0 +1,110 @@
+// Package abmigrate implements a migration of the JSON address book used
+// by the PEX reactor in 0.34 into the peer store database.
+//
+// The peer store keeps each peer in its own database entry and writes
+// entries incrementally as peers change, so the address book no longer has
+// to be rewritten in full every few minutes. The Migrate implementation is
+// idempotent: addresses that are already in the peer store are skipped.
+package abmigrate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+// bucketTypeOld is the bucket type of addresses that the 0.34 address book
+// had successfully connected to.
+const bucketTypeOld = 0x02
+
+// addrBookJSON is the on-disk format of the 0.34 address book.
+type addrBookJSON struct {
+	Key   string          `json:"key"`
+	Addrs []*knownAddress `json:"addrs"`
+}
+
+type knownAddress struct {
+	Addr       *netAddress `json:"addr"`
+	BucketType byte        `json:"bucket_type"`
+}
+
+type netAddress struct {
+	ID   types.NodeID `json:"id"`
+	IP   string       `json:"ip"`
+	Port uint16       `json:"port"`
+}
+
+// Load reads the addresses of a 0.34 JSON address book file. Addresses that
+// we had connected to before are returned first.
+func Load(path string) ([]p2p.NodeAddress, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var book addrBookJSON
+	if err := json.Unmarshal(bz, &book); err != nil {
+		return nil, fmt.Errorf("error reading address book %q: %w", path, err)
+	}
+
+	known := make([]*knownAddress, 0, len(book.Addrs))
+	for _, ka := range book.Addrs {
+		if ka != nil && ka.Addr != nil {
+			known = append(known, ka)
+		}
+	}
+	sort.SliceStable(known, func(i, j int) bool {
+		return known[i].BucketType == bucketTypeOld && known[j].BucketType != bucketTypeOld
+	})
+
+	addresses := make([]p2p.NodeAddress, 0, len(known))
+	for _, ka := range known {
+		addresses = append(addresses, p2p.NodeAddress{
+			Protocol: p2p.MConnProtocol,
+			NodeID:   ka.Addr.ID,
+			Hostname: ka.Addr.IP,
+			Port:     ka.Addr.Port,
+		})
+	}
+	return addresses, nil
+}
+
+// Migrate adds the addresses of a 0.34 JSON address book file to the peer
+// store in db, and returns the number of addresses that were added. Invalid
+// addresses are skipped.
+func Migrate(ctx context.Context, selfID types.NodeID, path string, db dbm.DB) (int, error) {
+	addresses, err := Load(path)
+	if err != nil {
+		return 0, err
+	}
+
+	peerManager, err := p2p.NewPeerManager(selfID, db, p2p.PeerManagerOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("loading peer store: %w", err)
+	}
+
+	var added int
+	for _, address := range addresses {
+		if err := ctx.Err(); err != nil {
+			return added, err
+		}
+		if address.Validate() != nil || address.NodeID == selfID {
+			continue
+		}
+		ok, err := peerManager.Add(address)
+		if err != nil {
+			return added, fmt.Errorf("adding address %v: %w", address, err)
+		}
+		if ok {
+			added++
+		}
+	}
+	return added, nil
+}
@@ -0,0 +1,98 @@
+package abmigrate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+const testAddrBook = `{
+	"key": "7bd5b8e8b0c3a5f3d9e2b1a4",
+	"addrs": [
+		{
+			"addr": {"id": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "ip": "10.0.0.1", "port": 26656},
+			"src": {"id": "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", "ip": "10.0.0.2", "port": 26656},
+			"buckets": [12],
+			"attempts": 0,
+			"bucket_type": 1,
+			"last_attempt": "2022-01-01T00:00:00Z",
+			"last_success": "0001-01-01T00:00:00Z",
+			"last_ban_time": "0001-01-01T00:00:00Z"
+		},
+		{
+			"addr": {"id": "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", "ip": "10.0.0.2", "port": 26656},
+			"src": {"id": "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", "ip": "10.0.0.2", "port": 26656},
+			"buckets": [3],
+			"attempts": 0,
+			"bucket_type": 2,
+			"last_attempt": "2022-01-01T00:00:00Z",
+			"last_success": "2022-01-01T00:00:00Z",
+			"last_ban_time": "0001-01-01T00:00:00Z"
+		},
+		{
+			"addr": {"id": "invalid", "ip": "10.0.0.3", "port": 26656},
+			"bucket_type": 1
+		},
+		{
+			"addr": {"id": "cccccccccccccccccccccccccccccccccccccccc", "ip": "10.0.0.4", "port": 26656},
+			"bucket_type": 1
+		}
+	]
+}`
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "addrbook.json")
+	require.NoError(t, os.WriteFile(path, []byte(testAddrBook), 0600))
+
+	addresses, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, addresses, 4)
+
+	// the address we had connected to comes first
+	require.Equal(t, p2p.NodeAddress{
+		Protocol: p2p.MConnProtocol,
+		NodeID:   types.NodeID(strings.Repeat("b", 40)),
+		Hostname: "10.0.0.2",
+		Port:     26656,
+	}, addresses[0])
+	require.Equal(t, types.NodeID(strings.Repeat("a", 40)), addresses[1].NodeID)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
+func TestMigrate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "addrbook.json")
+	require.NoError(t, os.WriteFile(path, []byte(testAddrBook), 0600))
+
+	selfID := types.NodeID(strings.Repeat("c", 40))
+	db := dbm.NewMemDB()
+
+	// the invalid address and our own are skipped
+	added, err := Migrate(ctx, selfID, path, db)
+	require.NoError(t, err)
+	require.Equal(t, 2, added)
+
+	// the migration is idempotent
+	added, err = Migrate(ctx, selfID, path, db)
+	require.NoError(t, err)
+	require.Equal(t, 0, added)
+
+	peerManager, err := p2p.NewPeerManager(selfID, db, p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []types.NodeID{
+		types.NodeID(strings.Repeat("a", 40)),
+		types.NodeID(strings.Repeat("b", 40)),
+	}, peerManager.Peers())
+}
@@ -3,6 +3,7 @@ package types
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/internal/jsontypes"
@@ -16,11 +17,12 @@ const (
 	// after a block has been committed.
 	// These are also used by the tx indexer for async indexing.
 	// All of this data can be fetched through the rpc.
-	EventNewBlockValue            = "NewBlock"
-	EventNewBlockHeaderValue      = "NewBlockHeader"
-	EventNewEvidenceValue         = "NewEvidence"
-	EventTxValue                  = "Tx"
-	EventValidatorSetUpdatesValue = "ValidatorSetUpdates"
+	EventConsensusParamUpdatesValue = "ConsensusParamUpdates"
+	EventNewBlockValue              = "NewBlock"
+	EventNewBlockHeaderValue        = "NewBlockHeader"
+	EventNewEvidenceValue           = "NewEvidence"
+	EventTxValue                    = "Tx"
+	EventValidatorSetUpdatesValue   = "ValidatorSetUpdates"
 
 	// Internal consensus events.
 	// These are used for testing the consensus state machine.
@@ -43,6 +45,28 @@ const (
 	// Events emitted by the evidence reactor when evidence is validated
 	// and before it is committed
 	EventEvidenceValidatedValue = "EvidenceValidated"
+
+	// Events emitted by the mempool when it evicts a valid transaction
+	// before it was committed, and when its fullness crosses one of the
+	// configured thresholds.
+	EventEvictedTxValue       = "EvictedTx"
+	EventMempoolFullnessValue = "MempoolFullness"
+
+	// Events emitted periodically by the blocksync reactor while syncing.
+	EventSyncProgressValue = "SyncProgress"
+)
+
+// Reasons for the mempool to evict a transaction, see EventDataEvictedTx.
+const (
+	// EvictedTxExpired means the transaction exceeded the mempool's
+	// ttl-num-blocks or ttl-duration.
+	EvictedTxExpired = "expired"
+	// EvictedTxFull means the transaction made room for a higher-priority
+	// transaction in a full mempool.
+	EvictedTxFull = "full"
+	// EvictedTxRecheck means the transaction failed CheckTx when it was
+	// rechecked after a block was committed.
+	EvictedTxRecheck = "recheck"
 )
 
 // Pre-populated ABCI Tendermint-reserved events
@@ -104,16 +128,20 @@ type EventData interface {
 func init() {
 	jsontypes.MustRegister(EventDataBlockSyncStatus{})
 	jsontypes.MustRegister(EventDataCompleteProposal{})
+	jsontypes.MustRegister(EventDataConsensusParamUpdates{})
 	jsontypes.MustRegister(EventDataNewBlock{})
 	jsontypes.MustRegister(EventDataNewBlockHeader{})
 	jsontypes.MustRegister(EventDataNewEvidence{})
 	jsontypes.MustRegister(EventDataNewRound{})
 	jsontypes.MustRegister(EventDataRoundState{})
 	jsontypes.MustRegister(EventDataStateSyncStatus{})
+	jsontypes.MustRegister(EventDataSyncProgress{})
 	jsontypes.MustRegister(EventDataTx{})
 	jsontypes.MustRegister(EventDataValidatorSetUpdates{})
 	jsontypes.MustRegister(EventDataVote{})
 	jsontypes.MustRegister(EventDataEvidenceValidated{})
+	jsontypes.MustRegister(EventDataEvictedTx{})
+	jsontypes.MustRegister(EventDataMempoolFullness{})
 	jsontypes.MustRegister(EventDataString(""))
 }
 
@@ -229,11 +257,30 @@ func (EventDataString) TypeTag() string { return "tendermint/event/ProposalStrin
 
 type EventDataValidatorSetUpdates struct {
 	ValidatorUpdates []*Validator `json:"validator_updates"`
+
+	// Height of the block whose execution returned the updates. They apply
+	// to the validator set from Height+2.
+	Height int64 `json:"height,string"`
 }
 
 // TypeTag implements the required method of jsontypes.Tagged.
 func (EventDataValidatorSetUpdates) TypeTag() string { return "tendermint/event/ValidatorSetUpdates" }
 
+// EventDataConsensusParamUpdates is published when the application updates
+// the consensus params while executing a block.
+type EventDataConsensusParamUpdates struct {
+	// The consensus params after the updates, which apply from Height+1.
+	ConsensusParams ConsensusParams `json:"consensus_params"`
+
+	// Height of the block whose execution returned the updates.
+	Height int64 `json:"height,string"`
+}
+
+// TypeTag implements the required method of jsontypes.Tagged.
+func (EventDataConsensusParamUpdates) TypeTag() string {
+	return "tendermint/event/ConsensusParamUpdates"
+}
+
 // EventDataBlockSyncStatus shows the fastsync status and the
 // height when the node state sync mechanism changes.
 type EventDataBlockSyncStatus struct {
@@ -254,6 +301,24 @@ type EventDataStateSyncStatus struct {
 // TypeTag implements the required method of jsontypes.Tagged.
 func (EventDataStateSyncStatus) TypeTag() string { return "tendermint/event/StateSyncStatus" }
 
+// EventDataSyncProgress shows the progress of block sync, so that a node
+// catching up can be told from a stuck one.
+type EventDataSyncProgress struct {
+	// The height of the last synced block, and the height synced up to.
+	Height       int64 `json:"height,string"`
+	TargetHeight int64 `json:"target_height,string"`
+
+	BlocksPerSecond float64       `json:"blocks_per_second"`
+	RemainingTime   time.Duration `json:"remaining_time,string"`
+	// The number of peers which can serve the next block to sync.
+	NumPeers int `json:"num_peers"`
+	// The time the last block was synced, or block sync started.
+	LastAdvance time.Time `json:"last_advance"`
+}
+
+// TypeTag implements the required method of jsontypes.Tagged.
+func (EventDataSyncProgress) TypeTag() string { return "tendermint/event/SyncProgress" }
+
 type EventDataEvidenceValidated struct {
 	Evidence Evidence `json:"evidence"`
 
@@ -263,6 +328,39 @@ type EventDataEvidenceValidated struct {
 // TypeTag implements the required method of jsontypes.Tagged.
 func (EventDataEvidenceValidated) TypeTag() string { return "tendermint/event/EvidenceValidated" }
 
+// EventDataEvictedTx is published when the mempool evicts a transaction
+// that passed CheckTx, for one of the EvictedTx* reasons.
+type EventDataEvictedTx struct {
+	Tx     Tx     `json:"tx"`
+	Reason string `json:"reason"`
+}
+
+// TypeTag implements the required method of jsontypes.Tagged.
+func (EventDataEvictedTx) TypeTag() string { return "tendermint/event/EvictedTx" }
+
+// ABCIEvents implements the eventlog.ABCIEventer interface.
+func (e EventDataEvictedTx) ABCIEvents() []abci.Event {
+	return []abci.Event{eventWithAttr(TxHashKey, fmt.Sprintf("%X", e.Tx.Hash()))}
+}
+
+// EventDataMempoolFullness is published when the fullness of the mempool, the
+// larger of its share of the maximum number of transactions and of the
+// maximum total size, rises to or falls below one of the configured
+// thresholds.
+type EventDataMempoolFullness struct {
+	// The highest threshold reached, in percent, or 0 if none is reached.
+	Threshold int  `json:"threshold"`
+	Rising    bool `json:"rising"`
+
+	NumTxs      int   `json:"num_txs"`
+	MaxTxs      int   `json:"max_txs"`
+	TxsBytes    int64 `json:"txs_bytes,string"`
+	MaxTxsBytes int64 `json:"max_txs_bytes,string"`
+}
+
+// TypeTag implements the required method of jsontypes.Tagged.
+func (EventDataMempoolFullness) TypeTag() string { return "tendermint/event/MempoolFullness" }
+
 // PUBSUB
 
 const (
@@ -282,24 +380,28 @@ const (
 )
 
 var (
-	EventQueryCompleteProposal    = QueryForEvent(EventCompleteProposalValue)
-	EventQueryLock                = QueryForEvent(EventLockValue)
-	EventQueryNewBlock            = QueryForEvent(EventNewBlockValue)
-	EventQueryNewBlockHeader      = QueryForEvent(EventNewBlockHeaderValue)
-	EventQueryNewEvidence         = QueryForEvent(EventNewEvidenceValue)
-	EventQueryNewRound            = QueryForEvent(EventNewRoundValue)
-	EventQueryNewRoundStep        = QueryForEvent(EventNewRoundStepValue)
-	EventQueryPolka               = QueryForEvent(EventPolkaValue)
-	EventQueryRelock              = QueryForEvent(EventRelockValue)
-	EventQueryTimeoutPropose      = QueryForEvent(EventTimeoutProposeValue)
-	EventQueryTimeoutWait         = QueryForEvent(EventTimeoutWaitValue)
-	EventQueryTx                  = QueryForEvent(EventTxValue)
-	EventQueryValidatorSetUpdates = QueryForEvent(EventValidatorSetUpdatesValue)
-	EventQueryValidBlock          = QueryForEvent(EventValidBlockValue)
-	EventQueryVote                = QueryForEvent(EventVoteValue)
-	EventQueryBlockSyncStatus     = QueryForEvent(EventBlockSyncStatusValue)
-	EventQueryStateSyncStatus     = QueryForEvent(EventStateSyncStatusValue)
-	EventQueryEvidenceValidated   = QueryForEvent(EventEvidenceValidatedValue)
+	EventQueryCompleteProposal      = QueryForEvent(EventCompleteProposalValue)
+	EventQueryConsensusParamUpdates = QueryForEvent(EventConsensusParamUpdatesValue)
+	EventQueryLock                  = QueryForEvent(EventLockValue)
+	EventQueryNewBlock              = QueryForEvent(EventNewBlockValue)
+	EventQueryNewBlockHeader        = QueryForEvent(EventNewBlockHeaderValue)
+	EventQueryNewEvidence           = QueryForEvent(EventNewEvidenceValue)
+	EventQueryNewRound              = QueryForEvent(EventNewRoundValue)
+	EventQueryNewRoundStep          = QueryForEvent(EventNewRoundStepValue)
+	EventQueryPolka                 = QueryForEvent(EventPolkaValue)
+	EventQueryRelock                = QueryForEvent(EventRelockValue)
+	EventQueryTimeoutPropose        = QueryForEvent(EventTimeoutProposeValue)
+	EventQueryTimeoutWait           = QueryForEvent(EventTimeoutWaitValue)
+	EventQueryTx                    = QueryForEvent(EventTxValue)
+	EventQueryValidatorSetUpdates   = QueryForEvent(EventValidatorSetUpdatesValue)
+	EventQueryValidBlock            = QueryForEvent(EventValidBlockValue)
+	EventQueryVote                  = QueryForEvent(EventVoteValue)
+	EventQueryBlockSyncStatus       = QueryForEvent(EventBlockSyncStatusValue)
+	EventQueryStateSyncStatus       = QueryForEvent(EventStateSyncStatusValue)
+	EventQuerySyncProgress          = QueryForEvent(EventSyncProgressValue)
+	EventQueryEvidenceValidated     = QueryForEvent(EventEvidenceValidatedValue)
+	EventQueryEvictedTx             = QueryForEvent(EventEvictedTxValue)
+	EventQueryMempoolFullness       = QueryForEvent(EventMempoolFullnessValue)
 )
 
 func EventQueryTxFor(tx Tx) *tmquery.Query {
@@ -317,6 +419,7 @@ type BlockEventPublisher interface {
 	PublishEventNewEvidence(EventDataNewEvidence) error
 	PublishEventTx(EventDataTx) error
 	PublishEventValidatorSetUpdates(EventDataValidatorSetUpdates) error
+	PublishEventConsensusParamUpdates(EventDataConsensusParamUpdates) error
 }
 
 type TxEventPublisher interface {
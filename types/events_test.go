@@ -19,6 +19,7 @@ var (
 	_ EventData = EventDataStateSyncStatus{}
 	_ EventData = EventDataTx{}
 	_ EventData = EventDataValidatorSetUpdates{}
+	_ EventData = EventDataConsensusParamUpdates{}
 	_ EventData = EventDataVote{}
 	_ EventData = EventDataString("")
 )
@@ -1,20 +1,28 @@
 package types
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
+	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/encoding"
+	"github.com/tendermint/tendermint/internal/jsontypes"
 	tmstrings "github.com/tendermint/tendermint/internal/libs/strings"
 	"github.com/tendermint/tendermint/libs/bytes"
+	tmmath "github.com/tendermint/tendermint/libs/math"
 	tmp2p "github.com/tendermint/tendermint/proto/tendermint/p2p"
 )
 
 const (
 	maxNodeInfoSize = 10240 // 10KB
 	maxNumChannels  = 16    // plenty of room for upgrades, for now
+
+	maxNumCompression = 8
 )
 
 // Max size of the NodeInfo struct
@@ -29,6 +37,25 @@ type ProtocolVersion struct {
 	App   uint64 `json:"app,string"`
 }
 
+// protocolVersionRange returns the range of protocol versions supported by a
+// node, given its minimum and maximum versions. Components of min that are 0
+// are taken to be the same as in max, i.e. only that version is supported.
+func protocolVersionRange(min *ProtocolVersion, max ProtocolVersion) (ProtocolVersion, ProtocolVersion) {
+	lo := max
+	if min != nil {
+		if min.P2P != 0 {
+			lo.P2P = min.P2P
+		}
+		if min.Block != 0 {
+			lo.Block = min.Block
+		}
+		if min.App != 0 {
+			lo.App = min.App
+		}
+	}
+	return lo, max
+}
+
 //-------------------------------------------------------------
 
 // NodeInfo is the basic node information exchanged
@@ -50,6 +77,17 @@ type NodeInfo struct {
 	// ASCIIText fields
 	Moniker string        `json:"moniker"` // arbitrary moniker
 	Other   NodeInfoOther `json:"other"`   // other application specific data
+
+	// NextNodeKey, if set, announces the node key this node will rotate to.
+	NextNodeKey *NodeKeyRotation `json:"next_node_key,omitempty"`
+
+	// MinProtocolVersion, if set, is the lowest protocol versions the node
+	// supports, along with those up to ProtocolVersion.
+	MinProtocolVersion *ProtocolVersion `json:"min_protocol_version,omitempty"`
+
+	// Compression lists the connection compression algorithms the node
+	// supports, in order of preference.
+	Compression []string `json:"compression,omitempty"`
 }
 
 // NodeInfoOther is the misc. applcation specific data
@@ -58,6 +96,88 @@ type NodeInfoOther struct {
 	RPCAddress string `json:"rpc_address"`
 }
 
+// NodeKeyRotation announces that a node will rotate its node key, so that
+// peers can learn its next node ID from the node itself. It is signed by both
+// the node's current and next keys, so that it proves the node holds the next
+// key.
+type NodeKeyRotation struct {
+	NextNodeID    NodeID
+	Signature     bytes.HexBytes
+	NextPubKey    crypto.PubKey
+	NextSignature bytes.HexBytes
+}
+
+type nodeKeyRotationJSON struct {
+	NextNodeID    NodeID          `json:"next_node_id"`
+	Signature     bytes.HexBytes  `json:"signature"`
+	NextPubKey    json.RawMessage `json:"next_pub_key,omitempty"`
+	NextSignature bytes.HexBytes  `json:"next_signature"`
+}
+
+func (r NodeKeyRotation) MarshalJSON() ([]byte, error) {
+	rj := nodeKeyRotationJSON{
+		NextNodeID:    r.NextNodeID,
+		Signature:     r.Signature,
+		NextSignature: r.NextSignature,
+	}
+	if r.NextPubKey != nil {
+		pk, err := jsontypes.Marshal(r.NextPubKey)
+		if err != nil {
+			return nil, err
+		}
+		rj.NextPubKey = pk
+	}
+	return json.Marshal(rj)
+}
+
+func (r *NodeKeyRotation) UnmarshalJSON(data []byte) error {
+	var rj nodeKeyRotationJSON
+	if err := json.Unmarshal(data, &rj); err != nil {
+		return err
+	}
+	if len(rj.NextPubKey) > 0 {
+		if err := jsontypes.Unmarshal(rj.NextPubKey, &r.NextPubKey); err != nil {
+			return err
+		}
+	}
+	r.NextNodeID = rj.NextNodeID
+	r.Signature = rj.Signature
+	r.NextSignature = rj.NextSignature
+	return nil
+}
+
+// nodeKeyRotationSignBytes returns the bytes signed to announce the rotation
+// from the current to the next node ID.
+func nodeKeyRotationSignBytes(current, next NodeID) []byte {
+	return []byte("TENDERMINT_NODE_KEY_ROTATION:" + string(current) + ":" + string(next))
+}
+
+// Verify checks that the rotation was signed by the given current node key,
+// and by the next node key over the same bytes.
+func (r NodeKeyRotation) Verify(pubKey crypto.PubKey) error {
+	if err := r.NextNodeID.Validate(); err != nil {
+		return fmt.Errorf("invalid next node ID: %w", err)
+	}
+	current := NodeIDFromPubKey(pubKey)
+	if r.NextNodeID == current {
+		return errors.New("next node ID is the current node ID")
+	}
+	if r.NextPubKey == nil {
+		return errors.New("missing next node public key")
+	}
+	if NodeIDFromPubKey(r.NextPubKey) != r.NextNodeID {
+		return errors.New("next node public key does not match the next node ID")
+	}
+	signBytes := nodeKeyRotationSignBytes(current, r.NextNodeID)
+	if !pubKey.VerifySignature(signBytes, r.Signature) {
+		return errors.New("invalid node key rotation signature")
+	}
+	if !r.NextPubKey.VerifySignature(signBytes, r.NextSignature) {
+		return errors.New("invalid next node key rotation signature")
+	}
+	return nil
+}
+
 // ID returns the node's peer ID.
 func (info NodeInfo) ID() NodeID {
 	return info.NodeID
@@ -77,8 +197,11 @@ func (info NodeInfo) ID() NodeID {
 // url-encoding), and we just need to be careful with how we handle that in our
 // clients. (e.g. off by default).
 func (info NodeInfo) Validate() error {
-	if _, _, err := ParseAddressString(info.ID().AddressString(info.ListenAddr)); err != nil {
-		return err
+	// Onion addresses can't be resolved to an IP, they're dialed via a proxy.
+	if !isOnionAddress(info.ListenAddr) {
+		if _, _, err := ParseAddressString(info.ID().AddressString(info.ListenAddr)); err != nil {
+			return err
+		}
 	}
 
 	// Validate Version
@@ -113,6 +236,25 @@ func (info NodeInfo) Validate() error {
 	default:
 		return fmt.Errorf("info.Other.TxIndex should be either 'on', 'off', or empty string, got '%v'", txIndex)
 	}
+	if min := info.MinProtocolVersion; min != nil {
+		max := info.ProtocolVersion
+		if min.P2P > max.P2P || min.Block > max.Block || min.App > max.App {
+			return fmt.Errorf("info.MinProtocolVersion %+v is above info.ProtocolVersion %+v", *min, max)
+		}
+	}
+	if len(info.Compression) > maxNumCompression {
+		return fmt.Errorf("info.Compression is too long (%v). Max is %v", len(info.Compression), maxNumCompression)
+	}
+	for _, c := range info.Compression {
+		if a, err := tmstrings.ASCIITrim(c); err != nil || a == "" {
+			return fmt.Errorf("info.Compression must contain valid non-empty ASCII text without tabs, but got %q", c)
+		}
+	}
+	if info.NextNodeKey != nil {
+		if err := info.NextNodeKey.NextNodeID.Validate(); err != nil {
+			return fmt.Errorf("info.NextNodeKey has invalid node ID: %w", err)
+		}
+	}
 	// XXX: Should we be more strict about address formats?
 	rpcAddr := other.RPCAddress
 	if len(rpcAddr) > 0 {
@@ -124,13 +266,33 @@ func (info NodeInfo) Validate() error {
 	return nil
 }
 
+// NegotiateProtocolVersion returns the protocol versions to use with a peer:
+// the highest versions supported by both nodes. The nodes must support a
+// common Block version. P2P and App versions weren't checked by nodes which
+// don't advertise a MinProtocolVersion, so for compatibility with them the
+// lower of the two versions is used if there's no common version.
+func (info NodeInfo) NegotiateProtocolVersion(other NodeInfo) (ProtocolVersion, error) {
+	lo, hi := protocolVersionRange(info.MinProtocolVersion, info.ProtocolVersion)
+	otherLo, otherHi := protocolVersionRange(other.MinProtocolVersion, other.ProtocolVersion)
+
+	negotiated := ProtocolVersion{
+		P2P:   tmmath.MinUint64(hi.P2P, otherHi.P2P),
+		Block: tmmath.MinUint64(hi.Block, otherHi.Block),
+		App:   tmmath.MinUint64(hi.App, otherHi.App),
+	}
+	if negotiated.Block < tmmath.MaxUint64(lo.Block, otherLo.Block) {
+		return ProtocolVersion{}, fmt.Errorf("peer is on a different Block version. Got %v-%v, expected %v-%v",
+			otherLo.Block, otherHi.Block, lo.Block, hi.Block)
+	}
+	return negotiated, nil
+}
+
 // CompatibleWith checks if two NodeInfo are compatible with each other.
-// CONTRACT: two nodes are compatible if the Block version and network match
-// and they have at least one channel in common.
+// CONTRACT: two nodes are compatible if they support a common Block version,
+// the network matches and they have at least one channel in common.
 func (info NodeInfo) CompatibleWith(other NodeInfo) error {
-	if info.ProtocolVersion.Block != other.ProtocolVersion.Block {
-		return fmt.Errorf("peer is on a different Block version. Got %v, expected %v",
-			other.ProtocolVersion.Block, info.ProtocolVersion.Block)
+	if _, err := info.NegotiateProtocolVersion(other); err != nil {
+		return err
 	}
 
 	// nodes must be on the same network
@@ -172,6 +334,18 @@ func (info *NodeInfo) AddChannel(channel uint16) {
 	info.Channels = append(info.Channels, byte(channel))
 }
 
+// RemoveChannel is used by the router when a channel is closed to remove it
+// from the node info.
+func (info *NodeInfo) RemoveChannel(channel uint16) {
+	channels := make(bytes.HexBytes, 0, len(info.Channels))
+	for _, ch := range info.Channels {
+		if ch != byte(channel) {
+			channels = append(channels, ch)
+		}
+	}
+	info.Channels = channels
+}
+
 func (info NodeInfo) Copy() NodeInfo {
 	return NodeInfo{
 		ProtocolVersion: info.ProtocolVersion,
@@ -182,6 +356,10 @@ func (info NodeInfo) Copy() NodeInfo {
 		Channels:        info.Channels,
 		Moniker:         info.Moniker,
 		Other:           info.Other,
+		NextNodeKey:     info.NextNodeKey,
+
+		MinProtocolVersion: info.MinProtocolVersion,
+		Compression:        info.Compression,
 	}
 }
 
@@ -204,6 +382,28 @@ func (info NodeInfo) ToProto() *tmp2p.NodeInfo {
 		TxIndex:    info.Other.TxIndex,
 		RPCAddress: info.Other.RPCAddress,
 	}
+	if info.NextNodeKey != nil {
+		dni.NextNodeKey = &tmp2p.NodeKeyRotation{
+			NextNodeID:    string(info.NextNodeKey.NextNodeID),
+			Signature:     info.NextNodeKey.Signature,
+			NextSignature: info.NextNodeKey.NextSignature,
+		}
+		if info.NextNodeKey.NextPubKey != nil {
+			// Node keys are always convertible, so the error can't happen.
+			if pk, err := encoding.PubKeyToProto(info.NextNodeKey.NextPubKey); err == nil {
+				dni.NextNodeKey.NextPubKey = &pk
+			}
+		}
+	}
+
+	if min := info.MinProtocolVersion; min != nil {
+		dni.MinProtocolVersion = &tmp2p.ProtocolVersion{
+			P2P:   min.P2P,
+			Block: min.Block,
+			App:   min.App,
+		}
+	}
+	dni.Compression = info.Compression
 
 	return dni
 }
@@ -229,6 +429,28 @@ func NodeInfoFromProto(pb *tmp2p.NodeInfo) (NodeInfo, error) {
 			RPCAddress: pb.Other.RPCAddress,
 		},
 	}
+	if pb.NextNodeKey != nil {
+		dni.NextNodeKey = &NodeKeyRotation{
+			NextNodeID:    NodeID(pb.NextNodeKey.NextNodeID),
+			Signature:     pb.NextNodeKey.Signature,
+			NextSignature: pb.NextNodeKey.NextSignature,
+		}
+		if pb.NextNodeKey.NextPubKey != nil {
+			pk, err := encoding.PubKeyFromProto(*pb.NextNodeKey.NextPubKey)
+			if err != nil {
+				return NodeInfo{}, fmt.Errorf("invalid next node public key: %w", err)
+			}
+			dni.NextNodeKey.NextPubKey = pk
+		}
+	}
+	if min := pb.MinProtocolVersion; min != nil {
+		dni.MinProtocolVersion = &ProtocolVersion{
+			P2P:   min.P2P,
+			Block: min.Block,
+			App:   min.App,
+		}
+	}
+	dni.Compression = pb.Compression
 
 	return dni, nil
 }
@@ -269,6 +491,9 @@ func ParseAddressString(addr string) (net.IP, uint16, error) {
 		if err != nil {
 			return nil, 0, err
 		}
+		if len(ips) == 0 {
+			return nil, 0, fmt.Errorf("no IP addresses found for %q", host)
+		}
 		ip = ips[0]
 	}
 
@@ -280,6 +505,26 @@ func ParseAddressString(addr string) (net.IP, uint16, error) {
 	return ip, uint16(port), nil
 }
 
+// onionHostRe matches Tor v3 onion service hostnames.
+var onionHostRe = regexp.MustCompile(`^[a-z2-7]{56}\.onion$`)
+
+// IsOnionHost returns true if the host is a Tor (v3) onion service hostname.
+// Onion hostnames can't be resolved, and must be dialed through a Tor proxy.
+func IsOnionHost(host string) bool {
+	return onionHostRe.MatchString(strings.ToLower(host))
+}
+
+// isOnionAddress returns true if addr is a host:port address of an onion
+// service, optionally with a protocol.
+func isOnionAddress(addr string) bool {
+	host, port, err := net.SplitHostPort(removeProtocolIfDefined(addr))
+	if err != nil || !IsOnionHost(host) {
+		return false
+	}
+	_, err = strconv.ParseUint(port, 10, 16)
+	return err == nil
+}
+
 func removeProtocolIfDefined(addr string) string {
 	if strings.Contains(addr, "://") {
 		return strings.Split(addr, "://")[1]
@@ -47,6 +47,10 @@ func TestNodeInfoValidate(t *testing.T) {
 
 		{"Invalid NetAddress", func(ni *NodeInfo) { ni.ListenAddr = "not-an-address" }, true},
 		{"Good NetAddress", func(ni *NodeInfo) { ni.ListenAddr = "0.0.0.0:26656" }, false},
+		{"Onion NetAddress", func(ni *NodeInfo) {
+			ni.ListenAddr = "tcp://vww6ybal4bd7szmgncyruucpgfkqahzddi37ktceo3ah7ngmcopnpyyd.onion:26656"
+		}, false},
+		{"Invalid Onion NetAddress", func(ni *NodeInfo) { ni.ListenAddr = "invalid.onion:26656" }, true},
 
 		{"Non-ASCII Version", func(ni *NodeInfo) { ni.Version = nonASCII }, true},
 		{"Empty tab Version", func(ni *NodeInfo) { ni.Version = emptyTab }, true},
@@ -70,6 +74,20 @@ func TestNodeInfoValidate(t *testing.T) {
 		{"Empty space RPCAddress", func(ni *NodeInfo) { ni.Other.RPCAddress = emptySpace }, true},
 		{"Empty RPCAddress", func(ni *NodeInfo) { ni.Other.RPCAddress = "" }, false},
 		{"Good RPCAddress", func(ni *NodeInfo) { ni.Other.RPCAddress = "0.0.0.0:26657" }, false},
+
+		{"Good MinProtocolVersion", func(ni *NodeInfo) {
+			ni.MinProtocolVersion = &ProtocolVersion{Block: ni.ProtocolVersion.Block - 1}
+		}, false},
+		{"MinProtocolVersion above ProtocolVersion", func(ni *NodeInfo) {
+			ni.MinProtocolVersion = &ProtocolVersion{Block: ni.ProtocolVersion.Block + 1}
+		}, true},
+
+		{"Good Compression", func(ni *NodeInfo) { ni.Compression = []string{"snappy"} }, false},
+		{"Non-ASCII Compression", func(ni *NodeInfo) { ni.Compression = []string{nonASCII} }, true},
+		{"Empty Compression", func(ni *NodeInfo) { ni.Compression = []string{""} }, true},
+		{"Too Many Compression", func(ni *NodeInfo) {
+			ni.Compression = []string{"a", "b", "c", "d", "e", "f", "g", "h", "i"}
+		}, true},
 	}
 
 	nodeKeyID := testNodeID()
@@ -165,6 +183,54 @@ func TestNodeInfoCompatible(t *testing.T) {
 	}
 }
 
+func TestNodeInfoNegotiateProtocolVersion(t *testing.T) {
+	testCases := []struct {
+		testName  string
+		min, max  ProtocolVersion
+		otherMin  *ProtocolVersion
+		otherMax  ProtocolVersion
+		expect    ProtocolVersion
+		expectErr bool
+	}{
+		{"same versions", ProtocolVersion{}, ProtocolVersion{8, 11, 1}, nil, ProtocolVersion{8, 11, 1}, ProtocolVersion{8, 11, 1}, false},
+		{"peer on newer block version in range", ProtocolVersion{8, 10, 1}, ProtocolVersion{8, 11, 1}, nil, ProtocolVersion{8, 10, 1}, ProtocolVersion{8, 10, 1}, false},
+		{"both ranges", ProtocolVersion{7, 10, 0}, ProtocolVersion{9, 12, 1}, &ProtocolVersion{8, 11, 0}, ProtocolVersion{10, 13, 1}, ProtocolVersion{9, 12, 1}, false},
+		{"legacy peer on different block version", ProtocolVersion{}, ProtocolVersion{8, 11, 1}, nil, ProtocolVersion{8, 10, 1}, ProtocolVersion{}, true},
+		{"disjoint block ranges", ProtocolVersion{0, 10, 0}, ProtocolVersion{8, 11, 1}, &ProtocolVersion{0, 12, 0}, ProtocolVersion{8, 13, 1}, ProtocolVersion{}, true},
+		{"different p2p and app versions", ProtocolVersion{}, ProtocolVersion{8, 11, 2}, nil, ProtocolVersion{7, 11, 1}, ProtocolVersion{7, 11, 1}, false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			ni := testNodeInfo(t, testNodeID(), "testing")
+			ni.ProtocolVersion = tc.max
+			if tc.min != (ProtocolVersion{}) {
+				ni.MinProtocolVersion = &tc.min
+			}
+			other := testNodeInfo(t, testNodeID(), "testing")
+			other.ProtocolVersion = tc.otherMax
+			other.MinProtocolVersion = tc.otherMin
+
+			for _, pair := range [][2]NodeInfo{{ni, other}, {other, ni}} {
+				negotiated, err := pair[0].NegotiateProtocolVersion(pair[1])
+				if tc.expectErr {
+					require.Error(t, err)
+					require.Error(t, pair[0].CompatibleWith(pair[1]))
+					continue
+				}
+				require.NoError(t, err)
+				require.Equal(t, tc.expect, negotiated)
+				require.NoError(t, pair[0].CompatibleWith(pair[1]))
+			}
+
+			// The range survives a round trip through protobuf.
+			pb, err := NodeInfoFromProto(ni.ToProto())
+			require.NoError(t, err)
+			require.Equal(t, ni.MinProtocolVersion, pb.MinProtocolVersion)
+		})
+	}
+}
+
 func TestNodeInfoAddChannel(t *testing.T) {
 	nodeInfo := testNodeInfo(t, testNodeID(), "testing")
 	nodeInfo.Channels = []byte{}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 
 	"github.com/tendermint/tendermint/crypto"
@@ -56,6 +57,29 @@ func (nk NodeKey) PubKey() crypto.PubKey {
 	return nk.PrivKey.PubKey()
 }
 
+// SignRotation signs the announcement that the node will rotate to the given
+// next node key, with both the current and the next key.
+func (nk NodeKey) SignRotation(next NodeKey) (NodeKeyRotation, error) {
+	if next.ID == nk.ID {
+		return NodeKeyRotation{}, errors.New("next node ID is the current node ID")
+	}
+	signBytes := nodeKeyRotationSignBytes(nk.ID, next.ID)
+	sig, err := nk.PrivKey.Sign(signBytes)
+	if err != nil {
+		return NodeKeyRotation{}, err
+	}
+	nextSig, err := next.PrivKey.Sign(signBytes)
+	if err != nil {
+		return NodeKeyRotation{}, err
+	}
+	return NodeKeyRotation{
+		NextNodeID:    next.ID,
+		Signature:     sig,
+		NextPubKey:    next.PubKey(),
+		NextSignature: nextSig,
+	}, nil
+}
+
 // SaveAs persists the NodeKey to filePath.
 func (nk NodeKey) SaveAs(filePath string) error {
 	jsonBytes, err := json.Marshal(nk)
@@ -1,6 +1,7 @@
 package types_test
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -43,3 +44,51 @@ func TestNodeKeySaveAs(t *testing.T) {
 	require.NoError(t, nodeKey.SaveAs(filePath))
 	require.FileExists(t, filePath)
 }
+
+func TestNodeKeySignRotation(t *testing.T) {
+	current := types.GenNodeKey()
+	next := types.GenNodeKey()
+
+	_, err := current.SignRotation(current)
+	require.Error(t, err)
+
+	rotation, err := current.SignRotation(next)
+	require.NoError(t, err)
+	require.Equal(t, next.ID, rotation.NextNodeID)
+	require.NoError(t, rotation.Verify(current.PubKey()))
+
+	// The rotation must be signed by the current key.
+	require.Error(t, rotation.Verify(next.PubKey()))
+	forged := rotation
+	forged.NextNodeID = types.GenNodeKey().ID
+	require.Error(t, forged.Verify(current.PubKey()))
+
+	// The rotation must also be signed by the next key, so a rotation signed
+	// by the old key only is rejected.
+	oldOnly := rotation
+	oldOnly.NextPubKey = nil
+	oldOnly.NextSignature = nil
+	require.Error(t, oldOnly.Verify(current.PubKey()))
+	oldOnly.NextPubKey = next.PubKey()
+	oldOnly.NextSignature = rotation.Signature
+	require.Error(t, oldOnly.Verify(current.PubKey()))
+
+	// The next public key must match the next node ID.
+	mismatched := rotation
+	mismatched.NextPubKey = types.GenNodeKey().PubKey()
+	require.Error(t, mismatched.Verify(current.PubKey()))
+
+	// The rotation survives the NodeInfo round trip.
+	nodeInfo := types.NodeInfo{NodeID: current.ID, NextNodeKey: &rotation}
+	decoded, err := types.NodeInfoFromProto(nodeInfo.ToProto())
+	require.NoError(t, err)
+	require.Equal(t, &rotation, decoded.NextNodeKey)
+	require.NoError(t, decoded.NextNodeKey.Verify(current.PubKey()))
+
+	// And the JSON round trip.
+	bz, err := json.Marshal(rotation)
+	require.NoError(t, err)
+	var unmarshaled types.NodeKeyRotation
+	require.NoError(t, json.Unmarshal(bz, &unmarshaled))
+	require.Equal(t, rotation, unmarshaled)
+}
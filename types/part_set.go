@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/internal/libs/reedsolomon"
 	"github.com/tendermint/tendermint/libs/bits"
 	tmbytes "github.com/tendermint/tendermint/libs/bytes"
 	tmmath "github.com/tendermint/tendermint/libs/math"
@@ -18,6 +19,7 @@ import (
 var (
 	ErrPartSetUnexpectedIndex = errors.New("error part set unexpected index")
 	ErrPartSetInvalidProof    = errors.New("error part set invalid proof")
+	ErrPartSetInvalidParity   = errors.New("error part set invalid parity")
 )
 
 type Part struct {
@@ -91,6 +93,55 @@ func PartFromProto(pb *tmproto.Part) (*Part, error) {
 
 //-------------------------------------
 
+// ParityPart is a Reed-Solomon parity shard computed over the parts of a
+// complete PartSet. Data parts are padded to the size of the first part
+// before encoding.
+//
+// Parity parts are not committed to by the PartSetHeader, so they can't be
+// verified on their own. Instead, the parts reconstructed from them are
+// verified against the PartSetHeader hash.
+type ParityPart struct {
+	Index        uint32           `json:"index"`
+	LastPartSize uint32           `json:"last_part_size"`
+	Bytes        tmbytes.HexBytes `json:"bytes"`
+}
+
+// ValidateBasic performs basic validation.
+func (pp *ParityPart) ValidateBasic() error {
+	if len(pp.Bytes) == 0 {
+		return errors.New("empty bytes")
+	}
+	if len(pp.Bytes) > int(BlockPartSizeBytes) {
+		return fmt.Errorf("too big: %d bytes, max: %d", len(pp.Bytes), BlockPartSizeBytes)
+	}
+	if pp.LastPartSize == 0 || pp.LastPartSize > uint32(len(pp.Bytes)) {
+		return fmt.Errorf("invalid last part size %d for parity part of %d bytes", pp.LastPartSize, len(pp.Bytes))
+	}
+	return nil
+}
+
+// String returns a string representation of ParityPart.
+func (pp *ParityPart) String() string {
+	return fmt.Sprintf("ParityPart{#%v %X}", pp.Index, tmbytes.Fingerprint(pp.Bytes))
+}
+
+// NumParityParts returns the number of parity parts computed for a PartSet
+// with total parts: half the number of data parts, rounded up, such that the
+// total number of parts doesn't exceed reedsolomon.MaxShards. PartSets with a
+// single part, or too many parts, have no parity parts.
+func NumParityParts(total uint32) uint32 {
+	if total < 2 || total >= reedsolomon.MaxShards {
+		return 0
+	}
+	numParity := (total + 1) / 2
+	if total+numParity > reedsolomon.MaxShards {
+		numParity = reedsolomon.MaxShards - total
+	}
+	return numParity
+}
+
+//-------------------------------------
+
 type PartSetHeader struct {
 	Total uint32           `json:"total"`
 	Hash  tmbytes.HexBytes `json:"hash"`
@@ -164,6 +215,12 @@ type PartSet struct {
 	// a count of the total size (in bytes). Used to ensure that the
 	// part set doesn't exceed the maximum block bytes
 	byteSize int64
+
+	// parity parts, either computed from the complete part set or received
+	// while it's incomplete
+	parity         []*ParityPart
+	parityCount    uint32
+	parityComputed bool
 }
 
 // Returns an immutable, full PartSet from the data bytes.
@@ -316,6 +373,183 @@ func (ps *PartSet) GetReader() io.Reader {
 	return NewPartSetReader(ps.parts)
 }
 
+// ParityParts returns the parity parts of the complete PartSet. They are
+// computed on the first call.
+func (ps *PartSet) ParityParts() ([]*ParityPart, error) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	if ps.count != ps.total {
+		return nil, errors.New("cannot compute parity parts of an incomplete PartSet")
+	}
+	numParity := NumParityParts(ps.total)
+	if numParity == 0 {
+		return nil, nil
+	}
+	if ps.parityComputed {
+		return ps.parity, nil
+	}
+
+	enc, err := reedsolomon.New(int(ps.total), int(numParity))
+	if err != nil {
+		return nil, err
+	}
+
+	size := len(ps.parts[0].Bytes)
+	shards := make([][]byte, ps.total+numParity)
+	for i, part := range ps.parts {
+		shards[i] = padPartBytes(part.Bytes, size)
+	}
+	if err := enc.Encode(shards); err != nil {
+		return nil, err
+	}
+
+	lastPartSize := uint32(len(ps.parts[ps.total-1].Bytes))
+	ps.parity = make([]*ParityPart, numParity)
+	for i := range ps.parity {
+		ps.parity[i] = &ParityPart{
+			Index:        uint32(i),
+			LastPartSize: lastPartSize,
+			Bytes:        shards[ps.total+uint32(i)],
+		}
+	}
+	ps.parityCount = numParity
+	ps.parityComputed = true
+	return ps.parity, nil
+}
+
+// AddParityPart adds a parity part to an incomplete PartSet. Once the number
+// of parts and parity parts reaches the total number of parts, the missing
+// parts are reconstructed and the PartSet becomes complete. If the
+// reconstructed parts don't match the PartSet hash, all parity parts are
+// discarded and ErrPartSetInvalidParity is returned; the PartSet can still be
+// completed with the missing parts.
+//
+// Parity parts are not authenticated, so callers should only add those
+// received for the proposal whose PartSetHeader the PartSet was created from.
+func (ps *PartSet) AddParityPart(pp *ParityPart) (bool, error) {
+	if ps == nil {
+		return false, nil
+	}
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	numParity := NumParityParts(ps.total)
+	if pp.Index >= numParity {
+		return false, ErrPartSetUnexpectedIndex
+	}
+
+	// Parity is useless once the part set is complete.
+	if ps.count == ps.total {
+		return false, nil
+	}
+
+	if ps.parity == nil {
+		ps.parity = make([]*ParityPart, numParity)
+	}
+	if ps.parity[pp.Index] != nil {
+		return false, nil
+	}
+	ps.parity[pp.Index] = pp
+	ps.parityCount++
+
+	if ps.count+ps.parityCount < ps.total {
+		return true, nil
+	}
+
+	err := ps.reconstruct()
+	// Received parity parts are discarded either way: some of them may not
+	// have been used for the reconstruction and are unverified.
+	ps.parity = nil
+	ps.parityCount = 0
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// reconstruct fills in the missing parts from the parts and parity parts
+// received so far.
+// CONTRACT: caller should hold ps.mtx.
+func (ps *PartSet) reconstruct() error {
+	var (
+		size         int
+		lastPartSize uint32
+	)
+	for _, pp := range ps.parity {
+		if pp == nil {
+			continue
+		}
+		if size == 0 {
+			size, lastPartSize = len(pp.Bytes), pp.LastPartSize
+		} else if len(pp.Bytes) != size || pp.LastPartSize != lastPartSize {
+			return ErrPartSetInvalidParity
+		}
+	}
+
+	numParity := uint32(len(ps.parity))
+	shards := make([][]byte, ps.total+numParity)
+	for i, part := range ps.parts {
+		if part == nil {
+			continue
+		}
+		// All parts but the last one have the size of the parity parts.
+		if uint32(i) == ps.total-1 {
+			if uint32(len(part.Bytes)) != lastPartSize {
+				return ErrPartSetInvalidParity
+			}
+		} else if len(part.Bytes) != size {
+			return ErrPartSetInvalidParity
+		}
+		shards[i] = padPartBytes(part.Bytes, size)
+	}
+	for i, pp := range ps.parity {
+		if pp != nil {
+			shards[ps.total+uint32(i)] = pp.Bytes
+		}
+	}
+
+	enc, err := reedsolomon.New(int(ps.total), int(numParity))
+	if err != nil {
+		return err
+	}
+	if err := enc.Reconstruct(shards); err != nil {
+		return fmt.Errorf("%w: %v", ErrPartSetInvalidParity, err)
+	}
+
+	data := shards[:ps.total]
+	data[ps.total-1] = data[ps.total-1][:lastPartSize]
+	root, proofs := merkle.ProofsFromByteSlices(data)
+	if !bytes.Equal(root, ps.hash) {
+		return ErrPartSetInvalidParity
+	}
+
+	for i, part := range ps.parts {
+		if part != nil {
+			continue
+		}
+		ps.parts[i] = &Part{
+			Index: uint32(i),
+			Bytes: data[i],
+			Proof: *proofs[i],
+		}
+		ps.partsBitArray.SetIndex(i, true)
+		ps.count++
+		ps.byteSize += int64(len(data[i]))
+	}
+	return nil
+}
+
+// padPartBytes returns bz padded with zeros to size.
+func padPartBytes(bz []byte, size int) []byte {
+	if len(bz) == size {
+		return bz
+	}
+	padded := make([]byte, size)
+	copy(padded, bz)
+	return padded
+}
+
 type PartSetReader struct {
 	i      int
 	parts  []*Part
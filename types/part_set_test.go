@@ -96,6 +96,81 @@ func TestWrongProof(t *testing.T) {
 	}
 }
 
+func TestPartSetParityParts(t *testing.T) {
+	// 10 parts, the last one shorter than the others.
+	data := tmrand.Bytes(testPartSize*9 + 100)
+	partSet := NewPartSetFromData(data, testPartSize)
+	require.EqualValues(t, 10, partSet.Total())
+
+	parity, err := partSet.ParityParts()
+	require.NoError(t, err)
+	require.Len(t, parity, int(NumParityParts(partSet.Total())))
+	require.Len(t, parity, 5)
+	for _, pp := range parity {
+		require.NoError(t, pp.ValidateBasic())
+		assert.EqualValues(t, 100, pp.LastPartSize)
+	}
+
+	// Reconstruct from 5 parts and 5 parity parts.
+	partSet2 := NewPartSetFromHeader(partSet.Header())
+	for i := 0; i < 5; i++ {
+		added, err := partSet2.AddPart(partSet.GetPart(i * 2))
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	for i, pp := range parity {
+		added, err := partSet2.AddParityPart(pp)
+		require.NoError(t, err)
+		require.True(t, added)
+		assert.Equal(t, i == len(parity)-1, partSet2.IsComplete())
+	}
+	assert.EqualValues(t, len(data), partSet2.ByteSize())
+	data2, err := io.ReadAll(partSet2.GetReader())
+	require.NoError(t, err)
+	assert.Equal(t, data, data2)
+
+	// Parity parts are ignored once complete.
+	added, err := partSet2.AddParityPart(parity[0])
+	assert.False(t, added)
+	assert.NoError(t, err)
+
+	// Invalid index.
+	partSet3 := NewPartSetFromHeader(partSet.Header())
+	_, err = partSet3.AddParityPart(&ParityPart{Index: 5, LastPartSize: 1, Bytes: []byte{1}})
+	assert.Equal(t, ErrPartSetUnexpectedIndex, err)
+
+	// Corrupted parity is detected once the part set is reconstructed.
+	for i := 0; i < 5; i++ {
+		_, err := partSet3.AddPart(partSet.GetPart(i))
+		require.NoError(t, err)
+	}
+	corrupted := *parity[0]
+	corrupted.Bytes = tmrand.Bytes(len(parity[0].Bytes))
+	_, err = partSet3.AddParityPart(&corrupted)
+	require.NoError(t, err)
+	for _, pp := range parity[1:4] {
+		_, err = partSet3.AddParityPart(pp)
+		require.NoError(t, err)
+	}
+	_, err = partSet3.AddParityPart(parity[4])
+	assert.ErrorIs(t, err, ErrPartSetInvalidParity)
+	assert.False(t, partSet3.IsComplete())
+
+	// The part set still completes from the remaining parts.
+	for i := 5; i < 10; i++ {
+		added, err := partSet3.AddPart(partSet.GetPart(i))
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	assert.True(t, partSet3.IsComplete())
+
+	// A single part has no parity.
+	partSet4 := NewPartSetFromData(data[:100], testPartSize)
+	parity, err = partSet4.ParityParts()
+	assert.NoError(t, err)
+	assert.Empty(t, parity)
+}
+
 func TestPartSetHeaderValidateBasic(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test in short mode")
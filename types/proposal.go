@@ -1,6 +1,7 @@
 package types
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/bits"
@@ -33,6 +34,14 @@ type Proposal struct {
 	Signature []byte    `json:"signature"`
 }
 
+// ProposalBlockHook inspects a block this node assembled as the proposer of a
+// round, before the proposal for it is signed. It returns false to veto the
+// block, in which case a block without mempool transactions is proposed
+// instead, which the application still prepares. That block is passed to the
+// hook too, and if it's vetoed as well, a block without any transactions is
+// proposed. The block must not be modified.
+type ProposalBlockHook func(ctx context.Context, block *Block) bool
+
 // NewProposal returns a new Proposal.
 // If there is no POLRound, polRound should be -1.
 func NewProposal(height int64, round int32, polRound int32, blockID BlockID, ts time.Time) *Proposal {
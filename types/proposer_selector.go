@@ -0,0 +1,31 @@
+package types
+
+// ProposerSelector selects the proposer of each round of consensus among the
+// validator set of its height.
+//
+// The proposer is part of consensus: every validator of a network must use the
+// same ProposerSelector, otherwise they disagree on who may propose and the
+// network halts. Selectors must therefore be deterministic, i.e. only depend
+// on their arguments and on data every validator agrees on (e.g. the blocks
+// committed so far). A selector based on randomness, e.g. weighted random
+// selection with VRF proofs, must derive it from such data.
+type ProposerSelector interface {
+	// SelectProposer returns the proposer of the given round of height. The
+	// proposer priorities of vals have been incremented for the round, so
+	// that vals.GetProposer() is the proposer chosen by the default
+	// rotation. vals must not be modified. The returned validator must belong
+	// to vals.
+	SelectProposer(vals *ValidatorSet, height int64, round int32) *Validator
+}
+
+// PriorityProposerSelector is the default ProposerSelector: the validators
+// take turns proposing, in proportion to their voting power, as tracked by
+// their proposer priorities.
+type PriorityProposerSelector struct{}
+
+var _ ProposerSelector = PriorityProposerSelector{}
+
+// SelectProposer implements ProposerSelector.
+func (PriorityProposerSelector) SelectProposer(vals *ValidatorSet, height int64, round int32) *Validator {
+	return vals.GetProposer()
+}